@@ -0,0 +1,159 @@
+package go61850
+
+import (
+	"encoding/json"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/slonegd/go61850/osi/mms/variant"
+)
+
+// AuditOperation - вид операции, изменяющей состояние сервера
+type AuditOperation string
+
+const (
+	// AuditWrite - запись переменной (в том числе атрибутов RCB)
+	AuditWrite AuditOperation = "write"
+	// AuditSelect - выбор объекта управления (SBO или SBOw)
+	AuditSelect AuditOperation = "select"
+	// AuditOperate - команда управления (Oper)
+	AuditOperate AuditOperation = "operate"
+	// AuditCancel - отмена команды управления (Cancel)
+	AuditCancel AuditOperation = "cancel"
+	// AuditObtainFile - передача файла клиента на сервер
+	AuditObtainFile AuditOperation = "obtain-file"
+)
+
+// AuditRecord - запись журнала аудита: кто, когда и что изменял на сервере
+type AuditRecord struct {
+	// Time - начало операции
+	Time time.Time
+	// Duration - длительность операции
+	Duration time.Duration
+	// Origin - источник команд клиента (см. WithControlOrigin)
+	Origin ControlOrigin
+	// Remote - адрес сервера; пустой, если транспорт не net.Conn
+	Remote    string
+	Operation AuditOperation
+	// Object - MMS имя переменной ("LD0/CSWI1$CO$Pos$Oper"), ссылка объекта
+	// управления (Select) или имя файла на сервере (ObtainFile)
+	Object string
+	// Value - записанное значение; nil для Select и ObtainFile
+	Value *variant.Variant
+	// Err - ошибка операции, в том числе отказ сервера и ErrReadOnlyClient; nil при успехе
+	Err error
+}
+
+// AuditSink принимает записи журнала аудита. Audit вызывается синхронно
+// после завершения каждой операции и не должен блокироваться надолго.
+type AuditSink interface {
+	Audit(record AuditRecord)
+}
+
+// AuditSinkFunc позволяет использовать функцию как AuditSink
+type AuditSinkFunc func(record AuditRecord)
+
+// Audit вызывает f(record)
+func (f AuditSinkFunc) Audit(record AuditRecord) {
+	f(record)
+}
+
+// WithAuditLog передаёт в sinks записи о всех операциях записи, управления
+// и передачи файлов на сервер (см. AuditOperation), включая неуспешные
+func WithAuditLog(sinks ...AuditSink) MmsClientOption {
+	return func(c *MmsClient) {
+		c.auditSinks = append(c.auditSinks, sinks...)
+	}
+}
+
+// audit передаёт запись об операции, начатой в start, в журналы аудита.
+// Вызывается отложенно: defer c.audit(operation, object, value, time.Now(), &err).
+func (c *MmsClient) audit(operation AuditOperation, object string, value *variant.Variant, start time.Time, err *error) {
+	if len(c.auditSinks) == 0 {
+		return
+	}
+	record := AuditRecord{
+		Time:      start,
+		Duration:  time.Since(start),
+		Origin:    c.origin,
+		Operation: operation,
+		Object:    object,
+		Value:     value,
+		Err:       *err,
+	}
+	if conn, ok := c.conn.(net.Conn); ok {
+		record.Remote = conn.RemoteAddr().String()
+	}
+	for _, sink := range c.auditSinks {
+		sink.Audit(record)
+	}
+}
+
+// writeOperation определяет вид операции записи по имени переменной
+func writeOperation(itemID string) AuditOperation {
+	switch {
+	case strings.HasSuffix(itemID, "$Oper"):
+		return AuditOperate
+	case strings.HasSuffix(itemID, "$SBOw"):
+		return AuditSelect
+	case strings.HasSuffix(itemID, "$Cancel"):
+		return AuditCancel
+	}
+	return AuditWrite
+}
+
+// jsonAuditRecord - представление AuditRecord в NewJSONAuditSink
+type jsonAuditRecord struct {
+	Time      time.Time      `json:"time"`
+	Duration  string         `json:"duration"`
+	OrCat     string         `json:"orCat"`
+	OrIdent   string         `json:"orIdent,omitempty"`
+	Remote    string         `json:"remote,omitempty"`
+	Operation AuditOperation `json:"operation"`
+	Object    string         `json:"object"`
+	Value     string         `json:"value,omitempty"`
+	Error     string         `json:"error,omitempty"`
+}
+
+// jsonAuditSink записывает журнал аудита в формате JSON Lines
+type jsonAuditSink struct {
+	mu      sync.Mutex
+	encoder *json.Encoder
+}
+
+// NewJSONAuditSink возвращает AuditSink, записывающий каждую запись в w
+// отдельной строкой JSON, например для передачи в SIEM:
+//
+//	{"time":"...","duration":"12ms","orCat":"station-control","orIdent":"scada",
+//	 "remote":"10.0.0.5:102","operation":"operate","object":"LD0/CSWI1$CO$Pos$Oper",
+//	 "value":"struct{...}"}
+//
+// Ошибки записи в w игнорируются.
+func NewJSONAuditSink(w io.Writer) AuditSink {
+	return &jsonAuditSink{encoder: json.NewEncoder(w)}
+}
+
+// Audit записывает record строкой JSON
+func (s *jsonAuditSink) Audit(record AuditRecord) {
+	out := jsonAuditRecord{
+		Time:      record.Time,
+		Duration:  record.Duration.String(),
+		OrCat:     record.Origin.Category.String(),
+		OrIdent:   string(record.Origin.Identification),
+		Remote:    record.Remote,
+		Operation: record.Operation,
+		Object:    record.Object,
+	}
+	if record.Value != nil {
+		out.Value = record.Value.String()
+	}
+	if record.Err != nil {
+		out.Error = record.Err.Error()
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.encoder.Encode(out)
+}
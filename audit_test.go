@@ -0,0 +1,93 @@
+package go61850
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/slonegd/go61850/mmstest"
+	"github.com/slonegd/go61850/model"
+	"github.com/slonegd/go61850/osi/mms"
+	"github.com/slonegd/go61850/osi/mms/variant"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditLog(t *testing.T) {
+	f, err := os.Open("mmstest/testdata/write.txt")
+	assert.NoError(t, err)
+	defer f.Close()
+	exchanges, err := mmstest.ParseTranscript(f)
+	assert.NoError(t, err)
+
+	var records []AuditRecord
+	var buffer bytes.Buffer
+	server := mmstest.NewTranscriptServer(t, exchanges...)
+	conn := server.Dial()
+	ctx := context.Background()
+	client, err := NewMmsClient(ctx, conn,
+		WithControlOrigin(model.OrCatStationControl, "scada"),
+		WithAuditLog(AuditSinkFunc(func(r AuditRecord) { records = append(records, r) })),
+		WithAuditLog(NewJSONAuditSink(&buffer)),
+	)
+	assert.NoError(t, err)
+	_, err = client.Initiate(ctx)
+	assert.NoError(t, err)
+
+	value := variant.NewUint32Variant(500)
+	assert.NoError(t, client.Write(ctx, "simpleIOGenericIO/GGIO1.AnIn1.db", mms.FCCF, value))
+	assert.Error(t, client.Write(ctx, "simpleIOGenericIO/GGIO1.AnIn1.db", mms.FCCF, value))
+	conn.Close()
+	assert.NoError(t, server.Wait())
+
+	assert.Len(t, records, 2)
+	for _, record := range records {
+		assert.Equal(t, AuditWrite, record.Operation)
+		assert.Equal(t, "simpleIOGenericIO/GGIO1$CF$AnIn1$db", record.Object)
+		assert.Equal(t, value, record.Value)
+		assert.Equal(t, client.Origin(), record.Origin)
+		assert.Equal(t, "pipe", record.Remote)
+		assert.False(t, record.Time.IsZero())
+	}
+	assert.NoError(t, records[0].Err)
+	assert.ErrorIs(t, records[1].Err, &mms.DataAccessError{ErrorCode: mms.ObjectAccessDenied})
+
+	// JSON Lines: по строке на запись
+	decoder := json.NewDecoder(&buffer)
+	var line map[string]any
+	assert.NoError(t, decoder.Decode(&line))
+	assert.Equal(t, "station-control", line["orCat"])
+	assert.Equal(t, "scada", line["orIdent"])
+	assert.Equal(t, "write", line["operation"])
+	assert.Equal(t, "uint32(500)", line["value"])
+	assert.NotContains(t, line, "error")
+	assert.NoError(t, decoder.Decode(&line))
+	assert.Equal(t, "data access error: object-access-denied", line["error"])
+	assert.False(t, decoder.More())
+}
+
+func TestAuditLogReadOnly(t *testing.T) {
+	var records []AuditRecord
+	c := &MmsClient{logger: defaultLogger()}
+	WithReadOnly()(c)
+	WithAuditLog(AuditSinkFunc(func(r AuditRecord) { records = append(records, r) }))(c)
+	ctx := context.Background()
+
+	// Отклонённые попытки тоже попадают в журнал
+	c.Operate(ctx, "LD0/CSWI1.Pos", variant.NewBoolVariant(true))
+	c.Select(ctx, "LD0/CSWI1.Pos")
+	c.ObtainFile(ctx, "local.cid", "IED.cid")
+
+	assert.Len(t, records, 3)
+	assert.Equal(t, AuditOperate, records[0].Operation)
+	assert.Equal(t, "LD0/CSWI1$CO$Pos$Oper", records[0].Object)
+	assert.Equal(t, AuditSelect, records[1].Operation)
+	assert.Equal(t, "LD0/CSWI1.Pos", records[1].Object)
+	assert.Equal(t, AuditObtainFile, records[2].Operation)
+	assert.Equal(t, "IED.cid", records[2].Object)
+	for _, record := range records {
+		assert.ErrorIs(t, record.Err, ErrReadOnlyClient)
+		assert.Empty(t, record.Remote)
+	}
+}
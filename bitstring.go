@@ -0,0 +1,106 @@
+package go61850
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/slonegd/go61850/osi/mms"
+	"github.com/slonegd/go61850/osi/mms/variant"
+)
+
+// ErrBitStringConflict возвращается UpdateBitString с WithVerify, если значение
+// объекта изменилось между чтением и записью
+var ErrBitStringConflict = errors.New("bit-string changed concurrently")
+
+// updateParams - параметры одного UpdateBitString
+type updateParams struct {
+	verify bool
+}
+
+// UpdateOption представляет опцию UpdateBitString
+type UpdateOption func(*updateParams)
+
+// WithVerify перечитывает значение перед записью и после неё: если значение
+// изменил другой клиент, UpdateBitString возвращает ErrBitStringConflict
+func WithVerify() UpdateOption {
+	return func(p *updateParams) {
+		p.verify = true
+	}
+}
+
+// UpdateBitString читает bit-string объекта reference с функциональным ограничением fc,
+// сбрасывает биты clear, устанавливает биты set и записывает результат, например
+// TrgOps RCB: UpdateBitString(ctx, "LD0/LLN0.EventsRCB01.TrgOps", mms.FCRP, []int{4}, nil).
+// Номера битов отсчитываются от старшего бита первого байта, как в IEC 61850-8-1.
+// Если значение не меняется, запись не выполняется.
+func (c *MmsClient) UpdateBitString(ctx context.Context, reference string, fc mms.FunctionalConstraint,
+	set, clear []int, opts ...UpdateOption) error {
+	var params updateParams
+	for _, opt := range opts {
+		opt(&params)
+	}
+
+	current, err := c.Read(ctx, reference, fc)
+	if err != nil {
+		return err
+	}
+	updated, err := updateBits(current, set, clear)
+	if err != nil {
+		return fmt.Errorf("update %s: %w", reference, err)
+	}
+	if updated.Equal(current) {
+		return nil
+	}
+
+	if params.verify {
+		if err := c.verifyBitString(ctx, reference, fc, current); err != nil {
+			return err
+		}
+	}
+	if err := c.Write(ctx, reference, fc, updated); err != nil {
+		return err
+	}
+	if params.verify {
+		return c.verifyBitString(ctx, reference, fc, updated)
+	}
+	return nil
+}
+
+// verifyBitString проверяет, что объект reference имеет значение expected
+func (c *MmsClient) verifyBitString(ctx context.Context, reference string, fc mms.FunctionalConstraint,
+	expected *variant.Variant) error {
+	value, err := c.Read(ctx, reference, fc)
+	if err != nil {
+		return err
+	}
+	if !value.Equal(expected) {
+		return fmt.Errorf("%w: %s is %s, expected %s", ErrBitStringConflict, reference, value, expected)
+	}
+	return nil
+}
+
+// updateBits возвращает копию bit-string v со сброшенными битами clear
+// и установленными битами set
+func updateBits(v *variant.Variant, set, clear []int) (*variant.Variant, error) {
+	if v.Type() != variant.BitString {
+		return nil, fmt.Errorf("expected bit-string, got %s", v.Type())
+	}
+	bs := v.BitString()
+	data := make([]byte, (bs.BitSize+7)/8)
+	copy(data, bs.Data)
+	for _, bits := range [][]int{clear, set} {
+		for _, bit := range bits {
+			if bit < 0 || bit >= bs.BitSize {
+				return nil, fmt.Errorf("bit %d out of range of %d-bit bit-string", bit, bs.BitSize)
+			}
+		}
+	}
+	for _, bit := range clear {
+		data[bit/8] &^= 0x80 >> (bit % 8)
+	}
+	for _, bit := range set {
+		data[bit/8] |= 0x80 >> (bit % 8)
+	}
+	return variant.NewBitStringVariant(data, bs.BitSize), nil
+}
@@ -0,0 +1,53 @@
+package go61850
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/slonegd/go61850/mmstest"
+	"github.com/slonegd/go61850/osi/mms"
+	"github.com/slonegd/go61850/osi/mms/variant"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateBitString(t *testing.T) {
+	f, err := os.Open("mmstest/testdata/bitstring.txt")
+	assert.NoError(t, err)
+	defer f.Close()
+	exchanges, err := mmstest.ParseTranscript(f)
+	assert.NoError(t, err)
+
+	server := mmstest.NewTranscriptServer(t, exchanges...)
+	conn := server.Dial()
+	ctx := context.Background()
+	client, err := NewMmsClient(ctx, conn, WithDefaultDomain("simpleIOGenericIO"))
+	assert.NoError(t, err)
+	_, err = client.Initiate(ctx)
+	assert.NoError(t, err)
+
+	// dchg -> integrity
+	const trgOps = "LLN0.EventsRCB01.TrgOps"
+	assert.NoError(t, client.UpdateBitString(ctx, trgOps, mms.FCRP, []int{4}, []int{1}, WithVerify()))
+	err = client.UpdateBitString(ctx, trgOps, mms.FCRP, []int{4}, []int{1}, WithVerify())
+	assert.ErrorIs(t, err, ErrBitStringConflict)
+	assert.EqualError(t, err, "bit-string changed concurrently: LLN0.EventsRCB01.TrgOps is "+
+		"bit-string(0b00_1010), expected bit-string(0b00_0010)")
+	assert.NoError(t, client.UpdateBitString(ctx, trgOps, mms.FCRP, []int{4}, nil))
+	conn.Close()
+	assert.NoError(t, server.Wait())
+}
+
+func TestUpdateBits(t *testing.T) {
+	v := variant.NewBitStringVariant([]byte{0x40, 0x80}, 13)
+	updated, err := updateBits(v, []int{0, 12}, []int{1, 8})
+	assert.NoError(t, err)
+	assert.Equal(t, variant.NewBitStringVariant([]byte{0x80, 0x08}, 13), updated)
+	// Исходное значение не изменяется
+	assert.Equal(t, []byte{0x40, 0x80}, v.BitString().Data)
+
+	_, err = updateBits(v, []int{13}, nil)
+	assert.EqualError(t, err, "bit 13 out of range of 13-bit bit-string")
+	_, err = updateBits(variant.NewInt32Variant(1), nil, nil)
+	assert.EqualError(t, err, "expected bit-string, got int32")
+}
@@ -0,0 +1,123 @@
+package go61850
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/slonegd/go61850/osi/mms"
+)
+
+// DefaultCancelTimeout - рекомендуемое время ожидания ответа на cancel-RequestPDU
+// для WithCancelTimeout
+const DefaultCancelTimeout = 5 * time.Second
+
+// WithCancelTimeout включает отмену запросов на уровне протокола и задаёт время
+// ожидания ответа сервера на cancel-RequestPDU. При отмене контекста запроса клиент
+// отправляет серверу cancel-RequestPDU и дочитывает ассоциацию до ответа на него,
+// чтобы запоздавший ответ на отменённый запрос не был принят за ответ следующего;
+// отменённый вызов возвращается только после этого, не дольше timeout.
+// Отмена не отправляется серверу, не заявившему сервис Cancel в Initiate.
+// По умолчанию (и при 0) запрос лишь перестаёт ожидаться локально.
+func WithCancelTimeout(timeout time.Duration) MmsClientOption {
+	return func(c *MmsClient) {
+		c.cancelTimeout = timeout
+	}
+}
+
+// receiveResponse получает ответ на confirmed-запрос invokeID.
+// При отмене ctx запрос отменяется на сервере (см. WithCancelTimeout), а без
+// отмены на сервере запоздавший ответ на него отбрасывается следующим запросом.
+// Запросы сервера, пришедшие до ответа, обрабатываются обработчиками WithRequestHandler.
+func (c *MmsClient) receiveResponse(ctx context.Context, invokeID uint32) ([]byte, error) {
+	for {
+		mmsData, err := c.mmsClient.ReceiveAndParseMmsResponse(ctx)
+		if err != nil {
+			c.diag("receive response for invokeID %d failed: %v", invokeID, err)
+			c.connectionLost(ctx, err)
+			switch {
+			case ctx.Err() == nil:
+			case c.cancelTimeout > 0 && c.checkService(mms.Cancel) == nil:
+				c.cancelRequest(invokeID)
+			default:
+				c.abandoned.Store(invokeID, struct{}{})
+			}
+			return nil, err
+		}
+		if id, ok := mms.ResponseInvokeID(mmsData); ok && id != invokeID {
+			if _, abandoned := c.abandoned.LoadAndDelete(id); abandoned {
+				c.logger.Debug("MMS response for abandoned invokeID %d dropped", id)
+				continue
+			}
+		}
+		if !c.handleServerRequest(ctx, mmsData) {
+			return mmsData, nil
+		}
+	}
+}
+
+// receiveConfirmed получает ответ на confirmed-запрос invokeID, как receiveResponse;
+// InformationReport, пришедшие до ответа, передаются в очередь отчётов
+func (c *MmsClient) receiveConfirmed(ctx context.Context, invokeID uint32) ([]byte, error) {
+	for {
+		mmsData, err := c.receiveResponse(ctx, invokeID)
+		if err != nil || !c.dispatchReport(ctx, mmsData, nil) {
+			return mmsData, err
+		}
+	}
+}
+
+// cancelRequest отправляет cancel-RequestPDU для invokeID и читает ассоциацию,
+// пока сервер не ответит на отмену. InformationReport передаются в очередь отчётов,
+// ответ на отменённый запрос отбрасывается. Отказ (RejectPDU) на отмену означает,
+// что запрос не отменён: ожидается ответ на него.
+func (c *MmsClient) cancelRequest(invokeID uint32) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.cancelTimeout)
+	defer cancel()
+
+	request := &mms.CancelRequest{InvokeID: invokeID}
+	c.diag("cancel invokeID %d", invokeID)
+	c.logger.Debug("MMS cancel-Request PDU: %x", request.Bytes())
+	if err := c.mmsClient.SendMmsPdu(request.Bytes()); err != nil {
+		c.logger.Debug("failed to send cancel-Request for invokeID %d: %v", invokeID, err)
+		return
+	}
+
+	// answered - получен ответ на отменяемый запрос; cancelled - получен ответ на отмену
+	var answered, cancelled bool
+	for !cancelled || !answered {
+		mmsData, err := c.mmsClient.ReceiveAndParseMmsResponse(ctx)
+		if err != nil {
+			c.logger.Debug("cancel of invokeID %d not confirmed: %v", invokeID, err)
+			c.diag("cancel of invokeID %d not confirmed: %v", invokeID, err)
+			return
+		}
+		if c.dispatchReport(ctx, mmsData, nil) || c.handleServerRequest(ctx, mmsData) {
+			continue
+		}
+		if mms.IsCancelResult(mmsData) {
+			id, err := mms.ParseCancelResult(mmsData)
+			if id != invokeID {
+				c.logger.Debug("unexpected cancel result for invokeID %d: %x", id, mmsData)
+				continue
+			}
+			cancelled = true
+			var cancelError *mms.CancelError
+			if !errors.As(err, &cancelError) || cancelError.ServiceError.Code != mms.CancelErrorCancelNotPossible {
+				// Отмена выполнена или запрос уже завершён: ответа на него больше не будет
+				answered = true
+			}
+			c.logger.Debug("MMS cancel of invokeID %d: %v", invokeID, err)
+			continue
+		}
+		if id, ok := mms.CancelRejectInvokeID(mmsData); ok && (id == invokeID || id == 0) {
+			cancelled = true
+			c.logger.Debug("MMS cancel of invokeID %d rejected", invokeID)
+			continue
+		}
+		if id, ok := mms.ResponseInvokeID(mmsData); ok && id == invokeID {
+			answered = true
+		}
+		c.logger.Debug("MMS response dropped after cancel of invokeID %d: %x", invokeID, mmsData)
+	}
+}
@@ -0,0 +1,112 @@
+package go61850
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/slonegd/go61850/mmstest"
+	"github.com/slonegd/go61850/osi/mms"
+	"github.com/stretchr/testify/assert"
+)
+
+// cancelRequestTPKT - cancel-RequestPDU для invokeID 1
+const cancelRequestTPKT = "03 00 00 17 02 f0 80 01 00 01 00 61 0a 30 08 02 01 03 a0 03 85 01 01"
+
+func TestCancelRequest(t *testing.T) {
+	for name, cancelAnswer := range map[string][]string{
+		// Запоздавший ответ на чтение отбрасывается, затем cancel-ResponsePDU
+		"cancelled": {"", "03 00 00 17 02 f0 80 01 00 01 00 61 0a 30 08 02 01 03 a0 03 86 01 01"},
+		// RejectPDU cancel-requestPDU: запрос не отменён, клиент дожидается ответа на него
+		"rejected": {"03 00 00 1c 02 f0 80 01 00 01 00 61 0f 30 0d 02 01 03 a0 08 a4 06 80 01 01 86 01 01", ""},
+	} {
+		t.Run(name, func(t *testing.T) { testCancelRequest(t, cancelAnswer) })
+	}
+}
+
+// testCancelRequest проверяет отмену чтения, на которую сервер отвечает answer;
+// пустой элемент answer заменяется ответом на чтение
+func testCancelRequest(t *testing.T, answer []string) {
+	f, err := os.Open("mmstest/testdata/read.txt")
+	assert.NoError(t, err)
+	defer f.Close()
+	exchanges, err := mmstest.ParseTranscript(f)
+	assert.NoError(t, err)
+	read := exchanges[2]
+
+	var responses []string
+	for _, response := range answer {
+		if response == "" {
+			responses = append(responses, read.Responses...)
+		} else {
+			responses = append(responses, response)
+		}
+	}
+	server := mmstest.NewTranscriptServer(t,
+		exchanges[0], exchanges[1],
+		// Сервер не отвечает на чтение до отмены
+		mmstest.Exchange{Request: read.Request},
+		mmstest.Exchange{Request: cancelRequestTPKT, Responses: responses},
+		read,
+	)
+	conn := server.Dial()
+	ctx := context.Background()
+	client, err := NewMmsClient(ctx, conn, WithCancelTimeout(DefaultCancelTimeout))
+	assert.NoError(t, err)
+	_, err = client.Initiate(ctx)
+	assert.NoError(t, err)
+
+	timeout, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	_, err = client.ReadObject(timeout, &mms.ReadRequest{DomainID: "simpleIOGenericIO", ItemID: "GGIO1$MX"})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// Ассоциация остаётся согласованной: следующий запрос получает свой ответ
+	result, err := client.ReadObject(ctx, &mms.ReadRequest{DomainID: "simpleIOGenericIO", ItemID: "GGIO1$MX"})
+	assert.NoError(t, err)
+	assert.True(t, result.Success)
+
+	conn.Close()
+	assert.NoError(t, server.Wait())
+}
+
+func TestAbandonedRequest(t *testing.T) {
+	f, err := os.Open("mmstest/testdata/read.txt")
+	assert.NoError(t, err)
+	defer f.Close()
+	exchanges, err := mmstest.ParseTranscript(f)
+	assert.NoError(t, err)
+	read := exchanges[2]
+	// Ответ на чтение с invokeID 2
+	response := strings.Replace(read.Responses[0], "a1 71 02 01 01", "a1 71 02 01 02", 1)
+	assert.NotEqual(t, read.Responses[0], response)
+
+	server := mmstest.NewTranscriptServer(t,
+		exchanges[0], exchanges[1],
+		// Сервер отвечает на первое чтение после истечения его контекста
+		mmstest.Exchange{Request: read.Request},
+		mmstest.Exchange{Request: read.Request, Responses: append(read.Responses, response)},
+	)
+	conn := server.Dial()
+	ctx := context.Background()
+	// Без WithCancelTimeout отмена не отправляется серверу
+	client, err := NewMmsClient(ctx, conn)
+	assert.NoError(t, err)
+	_, err = client.Initiate(ctx)
+	assert.NoError(t, err)
+
+	timeout, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	_, err = client.ReadObject(timeout, &mms.ReadRequest{DomainID: "simpleIOGenericIO", ItemID: "GGIO1$MX"})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// Запоздавший ответ на первое чтение отбрасывается
+	result, err := client.ReadObject(ctx, &mms.ReadRequest{DomainID: "simpleIOGenericIO", ItemID: "GGIO1$MX"})
+	assert.NoError(t, err)
+	assert.True(t, result.Success)
+
+	conn.Close()
+	assert.NoError(t, server.Wait())
+}
@@ -0,0 +1,219 @@
+package go61850
+
+import (
+	"sync"
+	"time"
+
+	"github.com/slonegd/go61850/model"
+	"github.com/slonegd/go61850/osi/mms/variant"
+)
+
+// DefaultSkewWindow - число последних наблюдений, по которым оценивается расхождение часов
+const DefaultSkewWindow = 64
+
+// ClockSkew оценивает расхождение часов IED с часами шлюза по отметкам времени
+// принятых отчётов и приводит отметки времени к часам шлюза, чтобы события разных
+// IED можно было упорядочить при анализе последовательности событий (SOE).
+//
+// Каждое наблюдение - разность времени приёма и отметки времени IED; она равна
+// задержке доставки минус расхождение часов. Задержка не бывает отрицательной,
+// поэтому оценка берётся по наименьшей разности в окне последних наблюдений:
+// отчёты, задержанные буферизацией (BufTm, буферизированные RCB), на неё не влияют.
+// Безопасен для конкурентного использования.
+type ClockSkew struct {
+	window int
+	now    func() time.Time
+
+	mu      sync.Mutex
+	offsets []time.Duration
+	next    int
+	count   uint64
+}
+
+// ClockSkewOption представляет опцию для настройки ClockSkew
+type ClockSkewOption func(*ClockSkew)
+
+// WithSkewWindow задаёт число наблюдений в окне оценки (по умолчанию DefaultSkewWindow)
+func WithSkewWindow(n int) ClockSkewOption {
+	return func(s *ClockSkew) {
+		if n > 0 {
+			s.window = n
+		}
+	}
+}
+
+// WithSkewClock задаёт часы шлюза (по умолчанию time.Now)
+func WithSkewClock(now func() time.Time) ClockSkewOption {
+	return func(s *ClockSkew) {
+		s.now = now
+	}
+}
+
+// NewClockSkew создаёт оценку расхождения часов одного IED
+func NewClockSkew(opts ...ClockSkewOption) *ClockSkew {
+	s := &ClockSkew{window: DefaultSkewWindow, now: time.Now}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// WithClockSkew включает оценку расхождения часов IED: клиент учитывает отметку
+// времени каждого принятого отчёта (см. ClockSkew.ObserveReport). Оценку
+// возвращает MmsClient.ClockSkew. Для нескольких IED нужны отдельные ClockSkew.
+func WithClockSkew(s *ClockSkew) MmsClientOption {
+	return func(c *MmsClient) {
+		c.clockSkew = s
+	}
+}
+
+// ClockSkew возвращает оценку расхождения часов IED, заданную WithClockSkew, или nil
+func (c *MmsClient) ClockSkew() *ClockSkew {
+	return c.clockSkew
+}
+
+// Observe учитывает отметку времени IED deviceTime, принятую сейчас по часам шлюза
+func (s *ClockSkew) Observe(deviceTime time.Time) {
+	s.ObserveAt(deviceTime, s.now())
+}
+
+// ObserveAt учитывает отметку времени IED deviceTime, принятую в момент received
+func (s *ClockSkew) ObserveAt(deviceTime, received time.Time) {
+	if deviceTime.IsZero() {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	offset := received.Sub(deviceTime)
+	if len(s.offsets) < s.window {
+		s.offsets = append(s.offsets, offset)
+	} else {
+		s.offsets[s.next] = offset
+		s.next = (s.next + 1) % s.window
+	}
+	s.count++
+}
+
+// ObserveReport учитывает отметку времени отчёта r, принятого сейчас:
+// TimeOfEntry, а без неё - наиболее позднюю отметку времени среди значений.
+// Возвращает false, если в отчёте нет отметок времени.
+func (s *ClockSkew) ObserveReport(r *Report) bool {
+	deviceTime, ok := reportTime(r)
+	if ok {
+		s.Observe(deviceTime)
+	}
+	return ok
+}
+
+// Estimate возвращает оценку расхождения часов: на сколько часы IED спешат
+// относительно часов шлюза (отрицательное значение - отстают). ok равно false,
+// пока нет ни одного наблюдения.
+func (s *ClockSkew) Estimate() (skew time.Duration, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.offsets) == 0 {
+		return 0, false
+	}
+	least := s.offsets[0]
+	for _, offset := range s.offsets[1:] {
+		least = min(least, offset)
+	}
+	return -least, true
+}
+
+// Observations возвращает общее число учтённых наблюдений
+func (s *ClockSkew) Observations() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.count
+}
+
+// Normalize приводит отметку времени IED к часам шлюза.
+// Без наблюдений время возвращается без изменений.
+func (s *ClockSkew) Normalize(deviceTime time.Time) time.Time {
+	skew, ok := s.Estimate()
+	if !ok || deviceTime.IsZero() {
+		return deviceTime
+	}
+	return deviceTime.Add(-skew)
+}
+
+// NormalizeReport приводит к часам шлюза TimeOfEntry и отметки времени (utc-time)
+// в значениях отчёта r, включая вложенные структуры
+func (s *ClockSkew) NormalizeReport(r *Report) {
+	skew, ok := s.Estimate()
+	if !ok {
+		return
+	}
+	if r.TimeOfEntry != nil {
+		entry := model.NewTimeOfEntry(r.TimeOfEntry.Time().Add(-skew))
+		r.TimeOfEntry = &entry
+	}
+	for i, value := range r.Values {
+		r.Values[i] = shiftTimes(value, -skew)
+	}
+}
+
+// shiftTimes возвращает value со сдвинутыми на d отметками времени;
+// значения без отметок времени возвращаются без копирования
+func shiftTimes(value *variant.Variant, d time.Duration) *variant.Variant {
+	if value == nil {
+		return nil
+	}
+	switch value.Type() {
+	case variant.UTCTime:
+		if value.Time().IsZero() {
+			return value
+		}
+		return variant.NewUTCTimeVariantWithQuality(value.Time().Add(d), value.TimeQuality())
+	case variant.Structure:
+		elements := value.Structure()
+		var shifted []*variant.Variant
+		for i, element := range elements {
+			next := shiftTimes(element, d)
+			if next != element && shifted == nil {
+				shifted = append(make([]*variant.Variant, 0, len(elements)), elements[:i]...)
+			}
+			if shifted != nil {
+				shifted = append(shifted, next)
+			}
+		}
+		if shifted == nil {
+			return value
+		}
+		return variant.NewStructureVariant(shifted)
+	default:
+		return value
+	}
+}
+
+// reportTime возвращает отметку времени отчёта: TimeOfEntry или наиболее позднюю
+// отметку времени среди значений
+func reportTime(r *Report) (time.Time, bool) {
+	if r.TimeOfEntry != nil {
+		return r.TimeOfEntry.Time(), true
+	}
+	var latest time.Time
+	for _, value := range r.Values {
+		latest = latestTime(value, latest)
+	}
+	return latest, !latest.IsZero()
+}
+
+// latestTime возвращает наиболее позднюю из latest и отметок времени в value
+func latestTime(value *variant.Variant, latest time.Time) time.Time {
+	if value == nil {
+		return latest
+	}
+	switch value.Type() {
+	case variant.UTCTime:
+		if value.Time().After(latest) {
+			return value.Time()
+		}
+	case variant.Structure:
+		for _, element := range value.Structure() {
+			latest = latestTime(element, latest)
+		}
+	}
+	return latest
+}
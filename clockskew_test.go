@@ -0,0 +1,83 @@
+package go61850
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/slonegd/go61850/model"
+	"github.com/slonegd/go61850/osi/mms"
+	"github.com/slonegd/go61850/osi/mms/variant"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClockSkew(t *testing.T) {
+	gateway := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	skew := NewClockSkew(WithSkewWindow(3), WithSkewClock(func() time.Time { return gateway }))
+	_, ok := skew.Estimate()
+	assert.False(t, ok)
+	assert.Equal(t, gateway, skew.Normalize(gateway))
+
+	// Часы IED спешат на 2 с; задержка доставки 10..500 мс
+	skew.Observe(gateway.Add(2*time.Second - 10*time.Millisecond))
+	skew.Observe(gateway.Add(2*time.Second - 500*time.Millisecond))
+	estimate, ok := skew.Estimate()
+	assert.True(t, ok)
+	assert.Equal(t, 2*time.Second-10*time.Millisecond, estimate)
+	assert.Equal(t, gateway, skew.Normalize(gateway.Add(2*time.Second-10*time.Millisecond)))
+
+	// Наблюдения вне окна не учитываются
+	skew.Observe(gateway.Add(time.Second))
+	skew.Observe(gateway.Add(time.Second))
+	skew.Observe(gateway.Add(time.Second))
+	estimate, _ = skew.Estimate()
+	assert.Equal(t, time.Second, estimate)
+	assert.Equal(t, uint64(5), skew.Observations())
+
+	// Отчёт: TimeOfEntry и отметки времени значений сдвигаются, остальные значения не копируются
+	entry := model.NewTimeOfEntry(gateway.Add(time.Second))
+	stVal := variant.NewBoolVariant(true)
+	report := &Report{
+		TimeOfEntry: &entry,
+		Values: []*variant.Variant{
+			variant.NewStructureVariant([]*variant.Variant{stVal, variant.NewUTCTimeVariant(gateway.Add(time.Second))}),
+			nil,
+			stVal,
+		},
+	}
+	skew.NormalizeReport(report)
+	assert.Equal(t, gateway, report.TimeOfEntry.Time())
+	assert.Equal(t, gateway, report.Values[0].Structure()[1].Time())
+	assert.Same(t, stVal, report.Values[0].Structure()[0])
+	assert.Nil(t, report.Values[1])
+	assert.Same(t, stVal, report.Values[2])
+}
+
+func TestWithClockSkew(t *testing.T) {
+	gateway := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	skew := NewClockSkew(WithSkewClock(func() time.Time { return gateway }))
+	c := &MmsClient{logger: defaultLogger()}
+	WithClockSkew(skew)(c)
+	assert.Same(t, skew, c.ClockSkew())
+
+	// Отчёт без TimeOfEntry: учитывается наиболее поздняя отметка времени значений
+	values := []*variant.Variant{
+		variant.NewVisibleStringVariant("Events"),
+		OptDataSet.BitString(),
+		variant.NewVisibleStringVariant("LD0/LLN0$Events"),
+		variant.NewBitStringVariant([]byte{0xc0}, 2),
+		variant.NewStructureVariant([]*variant.Variant{variant.NewUTCTimeVariant(gateway.Add(-3 * time.Second))}),
+		variant.NewStructureVariant([]*variant.Variant{variant.NewUTCTimeVariant(gateway.Add(-time.Second))}),
+	}
+	results := make([]mms.AccessResult, len(values))
+	for i, value := range values {
+		results[i] = mms.AccessResult{Success: true, Value: value}
+	}
+	pdu, err := (&mms.InformationReportPDU{VariableListName: &mms.ObjectName{ItemID: "RPT"}, Results: results}).Bytes()
+	assert.NoError(t, err)
+
+	assert.True(t, c.dispatchReport(context.Background(), pdu, nil))
+	estimate, ok := skew.Estimate()
+	assert.True(t, ok)
+	assert.Equal(t, -time.Second, estimate)
+}
@@ -0,0 +1,103 @@
+// Команда browse подключается к серверу IEC 61850, получает модель данных
+// (DiscoverModel) и выводит её описание в Markdown или CSV: логические устройства,
+// узлы, объекты и атрибуты данных с FC, наборы данных, блоки управления отчётами.
+//
+// Использование:
+//
+//	browse -addr 192.168.0.10[:102] [-format md|csv] [-o model.md] [-timeout 30s]
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/slonegd/go61850"
+	"github.com/slonegd/go61850/model"
+	"github.com/slonegd/go61850/osi/mms"
+)
+
+func main() {
+	address := flag.String("addr", "", "адрес сервера: host или host:port")
+	format := flag.String("format", "md", "формат вывода: md или csv")
+	output := flag.String("o", "", "выходной файл (по умолчанию stdout)")
+	timeout := flag.Duration("timeout", 30*time.Second, "ограничение времени обзора модели")
+	flag.Parse()
+
+	if *address == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := run(*address, *format, *output, *timeout); err != nil {
+		fmt.Fprintln(os.Stderr, "browse:", err)
+		os.Exit(1)
+	}
+}
+
+func run(address, format, output string, timeout time.Duration) error {
+	exportFormat, err := parseFormat(format)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	client, err := go61850.Dial(ctx, address)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	if _, err := client.Initiate(ctx); err != nil {
+		return err
+	}
+	m, err := client.DiscoverModel(ctx)
+	if err != nil {
+		return err
+	}
+	if err := setTypes(ctx, client, m); err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if output != "" {
+		f, err := os.Create(output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	return model.Export(w, m, exportFormat)
+}
+
+// setTypes запрашивает типы логических узлов (GetVariableAccessAttributes)
+// для колонки Type: модель DiscoverModel строится только по именам
+func setTypes(ctx context.Context, client *go61850.MmsClient, m *model.Model) error {
+	for _, ld := range m.LogicalDevices {
+		for _, ln := range ld.LogicalNodes {
+			spec, err := client.GetTypeSpecification(ctx, &mms.ReadRequest{DomainID: ld.Name, ItemID: ln.Name})
+			if err != nil {
+				return fmt.Errorf("type of %s/%s: %w", ld.Name, ln.Name, err)
+			}
+			ln.SetTypes(spec)
+		}
+	}
+	return nil
+}
+
+// parseFormat возвращает формат model.Export по имени флага -format
+func parseFormat(format string) (model.ExportFormat, error) {
+	switch format {
+	case "md", "markdown":
+		return model.ExportMarkdown, nil
+	case "csv":
+		return model.ExportCSV, nil
+	default:
+		return 0, fmt.Errorf("unknown format %q: expected md or csv", format)
+	}
+}
@@ -0,0 +1,77 @@
+// Команда pics выводит матрицу соответствия (PICS) стека: роли клиента или
+// сервера в каждом сервисе MMS и поддержку параметров CBB - в JSON или CSV
+// для сравнения с PIXIT устройства.
+//
+// Использование:
+//
+//	pics [-side client|server] [-format json|csv] [-o pics.json]
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/slonegd/go61850"
+	"github.com/slonegd/go61850/model"
+	"github.com/slonegd/go61850/osi/mms"
+	"github.com/slonegd/go61850/server"
+)
+
+func main() {
+	side := flag.String("side", "client", "сторона: client или server")
+	format := flag.String("format", "json", "формат вывода: json или csv")
+	output := flag.String("o", "", "выходной файл (по умолчанию stdout)")
+	flag.Parse()
+
+	if err := run(*side, *format, *output); err != nil {
+		fmt.Fprintln(os.Stderr, "pics:", err)
+		os.Exit(1)
+	}
+}
+
+func run(side, format, output string) error {
+	conformance, err := sideConformance(side)
+	if err != nil {
+		return err
+	}
+	if format != "json" && format != "csv" {
+		return fmt.Errorf("unknown format %q: expected json or csv", format)
+	}
+
+	var w io.Writer = os.Stdout
+	if output != "" {
+		f, err := os.Create(output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	if format == "csv" {
+		return conformance.WriteCSV(w)
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(conformance)
+}
+
+// sideConformance возвращает матрицу соответствия стороны по имени флага -side
+func sideConformance(side string) (*mms.Conformance, error) {
+	var conformance *mms.Conformance
+	switch side {
+	case "client":
+		conformance = go61850.ClientConformance()
+	case "server":
+		// Набор сервисов сервера не зависит от модели данных
+		conformance = server.New(&model.Model{}).Conformance()
+	default:
+		return nil, fmt.Errorf("unknown side %q: expected client or server", side)
+	}
+	// SV публикует sv.Publisher и принимает sv.Subscriber независимо от стороны MMS;
+	// пакет goose только кодирует данные, издателя и подписчика GOOSE в стеке нет
+	conformance.SetACSI(mms.ACSISV, true, true)
+	return conformance, nil
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"strings"
+	"unicode"
+
+	"github.com/slonegd/go61850/osi/mms"
+	"github.com/slonegd/go61850/scl"
+)
+
+// config - параметры генерации
+type config struct {
+	// Package - имя пакета генерируемого кода
+	Package string
+	// IED - имя IED; пусто - все IED файла
+	IED string
+	// Source - имя исходного файла для заголовка
+	Source string
+}
+
+// knownFC - функциональные ограничения, для которых есть константы в пакете mms
+var knownFC = map[mms.FunctionalConstraint]string{
+	mms.FCMX: "FCMX", mms.FCST: "FCST", mms.FCSP: "FCSP", mms.FCSV: "FCSV",
+	mms.FCCF: "FCCF", mms.FCDC: "FCDC", mms.FCSG: "FCSG", mms.FCSE: "FCSE",
+	mms.FCSR: "FCSR", mms.FCOR: "FCOR", mms.FCBL: "FCBL", mms.FCEX: "FCEX",
+	mms.FCCO: "FCCO",
+}
+
+// generate формирует Go код со ссылками на точки модели.
+// Префикс группы делает символ экспортируемым:
+//   - LD_<LD> - имя логического устройства (домена MMS);
+//   - DO_<LD>_<LN>_<DO> - ссылка на объект данных "LD/LN.DO";
+//   - DA_<LD>_<LN>_<DO>_<DA> - ссылка на атрибут данных с FC (тип Ref);
+//   - DS_<LD>_<LN>_<DataSet> - ссылка на набор данных "LD/LN.DataSet";
+//   - RCB_<LD>_<LN>_<RCB> - ссылка на блок управления отчётами "LD/LN.RP.RCB" или "LD/LN.BR.RCB".
+func generate(s *scl.SCL, cfg config) ([]byte, error) {
+	var lds, dos, das, dss, rcbs bytes.Buffer
+
+	found := false
+	for _, ied := range s.IEDs {
+		if cfg.IED != "" && ied.Name != cfg.IED {
+			continue
+		}
+		found = true
+
+		for _, ld := range ied.LDevices() {
+			ldName := ld.Name(ied.Name)
+			fmt.Fprintf(&lds, "\t%s = %q\n", identifier("LD", ldName), ldName)
+
+			for _, ln := range ld.LogicalNodes() {
+				lnRef := ldName + "/" + ln.Name()
+
+				lnType := s.Templates.LNodeType(ln.LnType)
+				if lnType == nil {
+					return nil, fmt.Errorf("LNodeType %s of %s not found", ln.LnType, lnRef)
+				}
+				for _, do := range lnType.DOs {
+					ref := lnRef + "." + do.Name
+					fmt.Fprintf(&dos, "\t%s = %q\n", identifier("DO", ref), ref)
+				}
+
+				attributes, err := s.Attributes(ldName, ln)
+				if err != nil {
+					return nil, err
+				}
+				for _, a := range attributes {
+					ref := a.Reference()
+					fmt.Fprintf(&das, "\t%s = Ref{Reference: %q, FC: %s} // %s\n",
+						identifier("DA", ref), ref, fcExpr(a.FC), a.BType)
+				}
+
+				for _, ds := range ln.DataSets {
+					ref := lnRef + "." + ds.Name
+					fmt.Fprintf(&dss, "\t%s = %q\n", identifier("DS", ref), ref)
+				}
+
+				for _, rc := range ln.ReportControls {
+					fc := "RP"
+					if rc.Buffered {
+						fc = "BR"
+					}
+					ref := lnRef + "." + fc + "." + rc.Name
+					fmt.Fprintf(&rcbs, "\t%s = %q // datSet=%s\n",
+						identifier("RCB", lnRef+"."+rc.Name), ref, rc.DatSet)
+				}
+			}
+		}
+	}
+	if cfg.IED != "" && !found {
+		return nil, fmt.Errorf("IED %s not found", cfg.IED)
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "// Code generated by sclgen from %s. DO NOT EDIT.\n\n", cfg.Source)
+	fmt.Fprintf(&out, "package %s\n\n", cfg.Package)
+	if das.Len() > 0 {
+		out.WriteString("import \"github.com/slonegd/go61850/osi/mms\"\n\n")
+		out.WriteString("// Ref - ссылка на атрибут данных с функциональным ограничением\n")
+		out.WriteString("type Ref struct {\n\tReference string\n\tFC mms.FunctionalConstraint\n}\n\n")
+	}
+	writeBlock(&out, "const", "Логические устройства", &lds)
+	writeBlock(&out, "const", "Объекты данных", &dos)
+	writeBlock(&out, "var", "Атрибуты данных", &das)
+	writeBlock(&out, "const", "Наборы данных", &dss)
+	writeBlock(&out, "const", "Блоки управления отчётами", &rcbs)
+
+	code, err := format.Source(out.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated code: %w", err)
+	}
+	return code, nil
+}
+
+// writeBlock записывает блок const/var, если он не пуст
+func writeBlock(out *bytes.Buffer, keyword, comment string, body *bytes.Buffer) {
+	if body.Len() == 0 {
+		return
+	}
+	fmt.Fprintf(out, "// %s\n%s (\n", comment, keyword)
+	out.Write(body.Bytes())
+	out.WriteString(")\n\n")
+}
+
+// fcExpr возвращает выражение Go для функционального ограничения
+func fcExpr(fc mms.FunctionalConstraint) string {
+	if name, ok := knownFC[fc]; ok {
+		return "mms." + name
+	}
+	return fmt.Sprintf("mms.FunctionalConstraint(%q)", fc)
+}
+
+// identifier строит идентификатор Go из ссылки:
+// "simpleIOGenericIO/GGIO1.AnIn1" -> "DO_simpleIOGenericIO_GGIO1_AnIn1".
+// Регистр имён сохраняется (идентификатор экспортируется за счёт префикса),
+// разделители и символы, недопустимые в идентификаторе, заменяются на '_'.
+func identifier(prefix, ref string) string {
+	var b strings.Builder
+	b.WriteString(prefix)
+	b.WriteByte('_')
+	for _, r := range ref {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+			b.WriteRune(r)
+		} else {
+			b.WriteByte('_')
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	"github.com/slonegd/go61850/scl"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerate(t *testing.T) {
+	s, err := scl.ParseFile("../../scl/testdata/simpleIO.scd")
+	assert.NoError(t, err)
+
+	// Эталон обновляется командой:
+	// go run ./cmd/sclgen -i scl/testdata/simpleIO.scd -pkg simpleio -o cmd/sclgen/testdata/simpleio.go.golden
+	want, err := os.ReadFile("testdata/simpleio.go.golden")
+	assert.NoError(t, err)
+
+	got, err := generate(s, config{Package: "simpleio", Source: "simpleIO.scd"})
+	assert.NoError(t, err)
+	assert.Equal(t, string(want), string(got))
+
+	_, err = generate(s, config{Package: "simpleio", IED: "unknown"})
+	assert.EqualError(t, err, "IED unknown not found")
+}
+
+func TestIdentifier(t *testing.T) {
+	assert.Equal(t, "DA_simpleIOGenericIO_GGIO1_AnIn1_mag_f", identifier("DA", "simpleIOGenericIO/GGIO1.AnIn1.mag.f"))
+	assert.Equal(t, "LD_IED_1_LD0", identifier("LD", "IED-1_LD0"))
+}
@@ -0,0 +1,58 @@
+// Команда sclgen генерирует Go код с типизированными ссылками на точки модели
+// из файла SCL (SCD/ICD/CID): логические устройства, объекты и атрибуты данных,
+// наборы данных и блоки управления отчётами. Приложение обращается к точкам
+// через символы, проверяемые компилятором, вместо строк.
+//
+// Использование:
+//
+//	sclgen -i station.scd -o points/points.go -pkg points [-ied IED1]
+//
+// или через go:generate:
+//
+//	//go:generate go run github.com/slonegd/go61850/cmd/sclgen -i station.scd -o points.go -pkg points
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/slonegd/go61850/scl"
+)
+
+func main() {
+	input := flag.String("i", "", "входной файл SCL (SCD, ICD, CID)")
+	output := flag.String("o", "", "выходной файл Go (по умолчанию stdout)")
+	pkg := flag.String("pkg", "points", "имя пакета генерируемого кода")
+	ied := flag.String("ied", "", "имя IED (по умолчанию все IED файла)")
+	flag.Parse()
+
+	if *input == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := run(*input, *output, *pkg, *ied); err != nil {
+		fmt.Fprintln(os.Stderr, "sclgen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(input, output, pkg, iedName string) error {
+	s, err := scl.ParseFile(input)
+	if err != nil {
+		return err
+	}
+
+	code, err := generate(s, config{Package: pkg, IED: iedName, Source: filepath.Base(input)})
+	if err != nil {
+		return err
+	}
+
+	if output == "" {
+		_, err = os.Stdout.Write(code)
+		return err
+	}
+	return os.WriteFile(output, code, 0o644)
+}
@@ -0,0 +1,45 @@
+// Пакет compat сохраняет прежние функции сборки запроса ассоциации по уровням
+// стека для кода, написанного до появления mms.NewAssociateRequest.
+// Функции выдают те же байты, что и раньше, и будут удалены в следующей
+// мажорной версии.
+//
+// Замена:
+//
+//	compat.BuildConnectSPDU(compat.BuildCPType(compat.BuildAARQ(compat.BuildInitiateRequestPDU(opts...))))
+//	mms.NewAssociateRequest(mms.NewInitiateRequest(opts...).Bytes()).Session
+package compat
+
+import (
+	"github.com/slonegd/go61850/osi/mms"
+	"github.com/slonegd/go61850/osi/presentation"
+	"github.com/slonegd/go61850/osi/session"
+)
+
+// BuildInitiateRequestPDU создаёт MMS initiate-RequestPDU с параметрами по умолчанию,
+// переопределёнными опциями.
+//
+// Deprecated: используйте mms.NewInitiateRequest(opts...).Bytes().
+func BuildInitiateRequestPDU(opts ...mms.InitiateRequestOption) []byte {
+	return mms.NewInitiateRequest(opts...).Bytes()
+}
+
+// BuildAARQ оборачивает MMS initiate-RequestPDU в ACSE AARQ.
+//
+// Deprecated: используйте поле ACSE результата mms.NewAssociateRequest.
+func BuildAARQ(userData []byte) []byte {
+	return mms.NewAssociateRequest(userData).ACSE
+}
+
+// BuildCPType оборачивает AARQ в Presentation CP-type.
+//
+// Deprecated: используйте поле Presentation результата mms.NewAssociateRequest.
+func BuildCPType(userData []byte) []byte {
+	return presentation.BuildCPType(userData)
+}
+
+// BuildConnectSPDU оборачивает CP-type в Session CONNECT SPDU.
+//
+// Deprecated: используйте поле Session результата mms.NewAssociateRequest.
+func BuildConnectSPDU(userData []byte) []byte {
+	return session.BuildConnectSPDU(userData)
+}
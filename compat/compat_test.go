@@ -0,0 +1,46 @@
+package compat
+
+import (
+	"encoding/hex"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/slonegd/go61850/mmstest"
+	"github.com/slonegd/go61850/osi/mms"
+	"github.com/stretchr/testify/assert"
+)
+
+// recordedConnectSPDU возвращает CONNECT SPDU из записанного обмена с libiec61850
+// (запрос Initiate без TPKT и COTP DT)
+func recordedConnectSPDU(t *testing.T) []byte {
+	f, err := os.Open("../mmstest/testdata/read.txt")
+	assert.NoError(t, err)
+	defer f.Close()
+	exchanges, err := mmstest.ParseTranscript(f)
+	assert.NoError(t, err)
+	packet, err := hex.DecodeString(strings.ReplaceAll(exchanges[1].Request, " ", ""))
+	assert.NoError(t, err)
+	return packet[7:]
+}
+
+func TestBuildAssociateRequest(t *testing.T) {
+	want := recordedConnectSPDU(t)
+
+	mmsPdu := BuildInitiateRequestPDU()
+	assert.Equal(t, mms.NewInitiateRequest().Bytes(), mmsPdu)
+
+	old := BuildConnectSPDU(BuildCPType(BuildAARQ(mmsPdu)))
+	assert.Equal(t, want, old)
+
+	associate := mms.NewAssociateRequest(mmsPdu)
+	assert.Equal(t, BuildAARQ(mmsPdu), associate.ACSE)
+	assert.Equal(t, BuildCPType(associate.ACSE), associate.Presentation)
+	assert.Equal(t, old, associate.Session)
+}
+
+func TestBuildInitiateRequestPDUOptions(t *testing.T) {
+	opts := []mms.InitiateRequestOption{mms.WithProposedMaxServOutstandingCalling(1)}
+	assert.Equal(t, mms.NewInitiateRequest(opts...).Bytes(), BuildInitiateRequestPDU(opts...))
+	assert.NotEqual(t, BuildInitiateRequestPDU(), BuildInitiateRequestPDU(opts...))
+}
@@ -0,0 +1,40 @@
+package go61850
+
+import "github.com/slonegd/go61850/osi/mms"
+
+// requestedServices - сервисы, запросы которых отправляет MmsClient, и отмена
+// запросов (cancel.go). checkService отклоняет сервисы вне таблицы, поэтому
+// запрос, не внесённый сюда, не пройдёт тесты и матрица не отстанет от клиента.
+var requestedServices = []mms.ServiceSupportedBit{
+	mms.GetNameList,
+	mms.Identify,
+	mms.Read,
+	mms.Write,
+	mms.GetVariableAccessAttributes,
+	mms.GetNamedVariableListAttributes,
+	mms.GetDomainAttributes,
+	mms.ObtainFile,
+	mms.FileOpen,
+	mms.FileRead,
+	mms.FileClose,
+	mms.FileDirectory,
+	mms.Cancel,
+}
+
+// Conformance возвращает матрицу соответствия клиента (PICS): сервисы, которые
+// он запрашивает, сервисы с обработчиками запросов сервера (WithRequestHandler)
+// и приём InformationReport в роли responder, параметры CBB предложения Initiate
+// по умолчанию. Матрица не зависит от ассоциации и доступна до Initiate.
+func (c *MmsClient) Conformance() *mms.Conformance {
+	responder := []mms.ServiceSupportedBit{mms.InformationReport}
+	for service := range c.requestHandlers {
+		responder = append(responder, mms.ServiceSupportedBit(service))
+	}
+	return mms.NewConformance(requestedServices, responder, mms.DefaultInitiateRequestParams().ProposedParameterCBB)
+}
+
+// ClientConformance возвращает матрицу соответствия клиента с настройками
+// по умолчанию (см. MmsClient.Conformance)
+func ClientConformance() *mms.Conformance {
+	return (&MmsClient{requestHandlers: defaultRequestHandlers()}).Conformance()
+}
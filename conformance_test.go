@@ -0,0 +1,31 @@
+package go61850
+
+import (
+	"context"
+	"testing"
+
+	"github.com/slonegd/go61850/osi/mms"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientConformance(t *testing.T) {
+	c := ClientConformance()
+	assert.True(t, c.Supports(mms.Read, mms.RoleRequester))
+	assert.True(t, c.Supports(mms.InformationReport, mms.RoleResponder))
+	assert.Equal(t, []mms.ServiceSupportedBit{mms.Status, mms.Identify, mms.InformationReport}, c.ServicesIn(mms.RoleResponder))
+	for _, block := range []mms.ACSIBlock{mms.ACSIDataModel, mms.ACSIDataSet, mms.ACSIBRCB, mms.ACSIURCB,
+		mms.ACSIControl, mms.ACSISBO, mms.ACSIFiles} {
+		assert.True(t, c.SupportsACSI(block, mms.RoleRequester), block)
+	}
+	assert.False(t, c.SupportsACSI(mms.ACSIGOOSE, mms.RoleRequester))
+
+	// Сервис вне таблицы requestedServices отклоняется до отправки запроса
+	assert.ErrorContains(t, (&MmsClient{}).checkService(mms.DefineNamedVariableList), "conformance table")
+
+	// Обработчики запросов сервера отражаются в роли responder
+	client := &MmsClient{requestHandlers: defaultRequestHandlers()}
+	WithRequestHandler(mms.ServiceRead, func(context.Context, *mms.ConfirmedRequest) ([]byte, error) {
+		return nil, nil
+	})(client)
+	assert.True(t, client.Conformance().Supports(mms.Read, mms.RoleResponder))
+}
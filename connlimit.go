@@ -0,0 +1,87 @@
+package go61850
+
+import (
+	"context"
+	"net"
+	"sync"
+	"sync/atomic"
+)
+
+// ConnectionLimiter ограничивает число одновременных TCP соединений,
+// установленных Dial, например у шлюза, опрашивающего сотни IED при ограниченном
+// числе сокетов. Попытки соединения сверх лимита ждут в очереди, пока одно из
+// соединений не будет закрыто, или отмены контекста.
+// Безопасен для конкурентного использования: один ConnectionLimiter разделяется
+// между всеми клиентами через WithConnectionLimiter.
+type ConnectionLimiter struct {
+	slots   chan struct{}
+	waiting atomic.Int64
+}
+
+// NewConnectionLimiter создаёт ограничитель на maxConnections соединений (не меньше 1)
+func NewConnectionLimiter(maxConnections int) *ConnectionLimiter {
+	if maxConnections < 1 {
+		maxConnections = 1
+	}
+	return &ConnectionLimiter{slots: make(chan struct{}, maxConnections)}
+}
+
+// WithConnectionLimiter задаёт общий ограничитель числа соединений для Dial.
+// Место в лимите занимается до установки TCP соединения и освобождается
+// при закрытии соединения или ошибке Dial.
+func WithConnectionLimiter(l *ConnectionLimiter) MmsClientOption {
+	return func(c *MmsClient) {
+		c.connections = l
+	}
+}
+
+// Acquire занимает место для соединения, ожидая освобождения при исчерпании лимита.
+// Возвращает ошибку контекста, если он отменён раньше.
+func (l *ConnectionLimiter) Acquire(ctx context.Context) error {
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	default:
+	}
+	l.waiting.Add(1)
+	defer l.waiting.Add(-1)
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Release освобождает место, занятое Acquire
+func (l *ConnectionLimiter) Release() {
+	<-l.slots
+}
+
+// Active возвращает число занятых мест
+func (l *ConnectionLimiter) Active() int {
+	return len(l.slots)
+}
+
+// Waiting возвращает число попыток соединения, ожидающих места
+func (l *ConnectionLimiter) Waiting() int {
+	return int(l.waiting.Load())
+}
+
+// Limit возвращает максимальное число одновременных соединений
+func (l *ConnectionLimiter) Limit() int {
+	return cap(l.slots)
+}
+
+// limitedConn освобождает место в ConnectionLimiter при первом закрытии соединения
+type limitedConn struct {
+	net.Conn
+	release sync.Once
+	limiter *ConnectionLimiter
+}
+
+func (c *limitedConn) Close() error {
+	err := c.Conn.Close()
+	c.release.Do(c.limiter.Release)
+	return err
+}
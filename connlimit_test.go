@@ -0,0 +1,57 @@
+package go61850
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/slonegd/go61850/mmstest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConnectionLimiter(t *testing.T) {
+	limiter := NewConnectionLimiter(1)
+	dial := func(ctx context.Context, network, address string) (net.Conn, error) {
+		// Сервер только подтверждает COTP соединение
+		return mmstest.NewTranscriptServer(t, fileTranscript(t)[0]).Dial(), nil
+	}
+	opts := []MmsClientOption{WithDialContext(dial), WithConnectionLimiter(limiter)}
+	ctx := context.Background()
+
+	first, err := Dial(ctx, "ied1", opts...)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, limiter.Active())
+
+	// Вторая попытка ждёт закрытия первого соединения
+	dialed := make(chan error, 1)
+	go func() {
+		second, err := Dial(ctx, "ied2", opts...)
+		if err == nil {
+			second.conn.Close()
+		}
+		dialed <- err
+	}()
+	assert.Eventually(t, func() bool { return limiter.Waiting() == 1 }, time.Second, time.Millisecond)
+
+	timeout, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	_, err = Dial(timeout, "ied3", opts...)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	first.conn.Close()
+	first.conn.Close()
+	assert.NoError(t, <-dialed)
+	assert.Equal(t, 0, limiter.Active())
+	assert.Equal(t, 0, limiter.Waiting())
+
+	// Ошибка соединения освобождает место
+	refused := errors.New("connection refused")
+	_, err = Dial(ctx, "ied4", WithConnectionLimiter(limiter), WithDialContext(func(context.Context, string, string) (net.Conn, error) {
+		return nil, refused
+	}))
+	assert.ErrorIs(t, err, refused)
+	assert.Equal(t, 0, limiter.Active())
+	assert.Equal(t, 1, limiter.Limit())
+}
@@ -0,0 +1,470 @@
+package go61850
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/slonegd/go61850/model"
+	"github.com/slonegd/go61850/osi/mms"
+	"github.com/slonegd/go61850/osi/mms/variant"
+)
+
+// ControlOrigin описывает источник команды - атрибут origin структур
+// Oper, SBOw и Cancel (IEC 61850-7-3, тип Originator)
+type ControlOrigin struct {
+	// Category - категория источника (orCat)
+	Category model.OrCat
+	// Identification - идентификатор источника (orIdent, до 64 байт)
+	Identification []byte
+}
+
+// maxOrIdentSize - размер orIdent, OCTET STRING (SIZE(0..64)) по IEC 61850-8-1
+const maxOrIdentSize = 64
+
+// WithControlOrigin задаёт orCat и orIdent, которые клиент подставляет во все команды.
+// По умолчанию используется OrCatNotSupported с пустым orIdent;
+// многие устройства отклоняют такие команды при проверке источника.
+// NewMmsClient отклоняет identification длиннее 64 байт.
+func WithControlOrigin(category model.OrCat, identification string) MmsClientOption {
+	return func(c *MmsClient) {
+		c.origin = ControlOrigin{Category: category, Identification: []byte(identification)}
+	}
+}
+
+// WithTestMode включает режим испытаний: во всех командах клиента Test=true,
+// и сервер выполняет их только для логических узлов в режиме test (Beh=test).
+// В записываемых значениях (Write, WriteMany) у атрибутов Quality
+// устанавливается бит test.
+func WithTestMode(test bool) MmsClientOption {
+	return func(c *MmsClient) {
+		c.testMode = test
+	}
+}
+
+// qualityTestBit - бит test атрибута Quality, bit-string(13) по IEC 61850-8-1
+const qualityTestBit = 11
+
+// markTest возвращает value, в котором у всех атрибутов Quality установлен бит test.
+// Исходное значение не изменяется.
+func markTest(value *variant.Variant) *variant.Variant {
+	switch {
+	case value == nil:
+		return nil
+	case value.Type() == variant.BitString && value.BitString().BitSize == 13:
+		marked, _ := updateBits(value, []int{qualityTestBit}, nil)
+		return marked
+	case value.Type() == variant.Structure:
+		elements := make([]*variant.Variant, len(value.Structure()))
+		for i, element := range value.Structure() {
+			elements[i] = markTest(element)
+		}
+		return variant.NewStructureVariant(elements)
+	default:
+		return value
+	}
+}
+
+// Origin возвращает источник команд клиента
+func (c *MmsClient) Origin() ControlOrigin {
+	return c.origin
+}
+
+// TestMode возвращает true, если клиент отправляет команды в режиме испытаний
+func (c *MmsClient) TestMode() bool {
+	return c.testMode
+}
+
+// controlParams - параметры одной команды
+type controlParams struct {
+	synchroCheck    bool
+	interlockCheck  bool
+	operTime        time.Time
+	waitTermination bool
+}
+
+// ControlOption представляет опцию отдельной команды
+type ControlOption func(*controlParams)
+
+// WithSynchroCheck запрашивает у сервера проверку синхронизма (Check, бит 0)
+func WithSynchroCheck() ControlOption {
+	return func(p *controlParams) {
+		p.synchroCheck = true
+	}
+}
+
+// WithInterlockCheck запрашивает у сервера проверку блокировок (Check, бит 1)
+func WithInterlockCheck() ControlOption {
+	return func(p *controlParams) {
+		p.interlockCheck = true
+	}
+}
+
+// WithOperTime задаёт время T команды; по умолчанию - текущее время
+func WithOperTime(t time.Time) ControlOption {
+	return func(p *controlParams) {
+		p.operTime = t
+	}
+}
+
+// WithCommandTermination указывает, что объект работает с усиленной безопасностью
+// (direct/sbo-with-enhanced-security): Operate после положительного ответа на Write
+// ждёт CommandTermination и возвращает *LastApplError при отрицательном завершении.
+func WithCommandTermination() ControlOption {
+	return func(p *controlParams) {
+		p.waitTermination = true
+	}
+}
+
+// OperValue собирает значение структуры Oper (и SBOw) для ctlVal:
+//
+//	ctlVal, origin{orCat, orIdent}, ctlNum, T, Test, Check
+//
+// origin и Test берутся из настроек клиента (WithControlOrigin, WithTestMode),
+// ctlNum увеличивается с каждой командой.
+func (c *MmsClient) OperValue(ctlVal *variant.Variant, opts ...ControlOption) *variant.Variant {
+	return c.operValue(ctlVal, c.nextCtlNum(), c.origin, opts...)
+}
+
+// nextCtlNum возвращает номер следующей команды
+func (c *MmsClient) nextCtlNum() uint8 {
+	return uint8(c.ctlNum.Add(1) - 1)
+}
+
+// operValue собирает значение структуры Oper с заданными ctlNum и origin:
+// Oper после SBOw повторяет значения, записанные при выборе
+func (c *MmsClient) operValue(ctlVal *variant.Variant, ctlNum uint8, origin ControlOrigin, opts ...ControlOption) *variant.Variant {
+	params := controlParams{operTime: time.Now()}
+	for _, opt := range opts {
+		opt(&params)
+	}
+
+	var check byte
+	if params.synchroCheck {
+		check |= 0x80
+	}
+	if params.interlockCheck {
+		check |= 0x40
+	}
+
+	return variant.NewStructureVariant([]*variant.Variant{
+		ctlVal,
+		variant.NewStructureVariant([]*variant.Variant{
+			variant.NewInt32Variant(int32(origin.Category)),
+			variant.NewOctetStringVariant(origin.Identification),
+		}),
+		variant.NewUint32Variant(uint32(ctlNum)),
+		variant.NewUTCTimeVariant(params.operTime),
+		variant.NewBoolVariant(c.testMode),
+		variant.NewBitStringVariant([]byte{check}, 2),
+	})
+}
+
+// LastApplError описывает отказ команды управления, переданный сервером
+// в переменной LastApplError (IEC 61850-8-1): перед отрицательным ответом
+// на Write или в отрицательном CommandTermination.
+type LastApplError struct {
+	// CntrlObj - ссылка на объект управления, например "LD0/CSWI1$CO$Pos$Oper"
+	CntrlObj  string
+	ErrorCode model.ControlError
+	Origin    ControlOrigin
+	CtlNum    uint8
+	AddCause  model.AddCause
+}
+
+// Error реализует интерфейс error
+func (e *LastApplError) Error() string {
+	return fmt.Sprintf("control %s (ctlNum %d) failed: %s (%s)", e.CntrlObj, e.CtlNum, e.AddCause, e.ErrorCode)
+}
+
+// ParseLastApplError разбирает значение переменной LastApplError:
+// struct{CntrlObj, Error, Origin{orCat, orIdent}, ctlNum, AddCause}
+func ParseLastApplError(v *variant.Variant) (*LastApplError, error) {
+	fields := v.Structure()
+	if v.Type() != variant.Structure || len(fields) != 5 {
+		return nil, fmt.Errorf("LastApplError: expected structure of 5 elements, got %s", v)
+	}
+	origin := fields[2].Structure()
+	if fields[2].Type() != variant.Structure || len(origin) != 2 {
+		return nil, fmt.Errorf("LastApplError: invalid Origin %s", fields[2])
+	}
+
+	// Значения вне диапазона перечислений сохраняются как есть: String покажет код
+	controlError, _ := model.ControlErrorFromVariant(fields[1])
+	orCat, _ := model.OrCatFromVariant(origin[0])
+	addCause, _ := model.AddCauseFromVariant(fields[4])
+
+	return &LastApplError{
+		CntrlObj:  fields[0].StringValue(),
+		ErrorCode: controlError,
+		Origin:    ControlOrigin{Category: orCat, Identification: origin[1].OctetString()},
+		CtlNum:    uint8(fields[3].Uint32()),
+		AddCause:  addCause,
+	}, nil
+}
+
+// pendingControl - команда, ожидающая LastApplError или CommandTermination
+type pendingControl struct {
+	// variable - записываемая переменная объекта управления (Oper или SBOw)
+	variable mms.ObjectName
+	// object - ссылка на объект управления "LD/LN$CO$DO", с которой начинается CntrlObj
+	object        string
+	ctlNum        uint8
+	lastApplError *LastApplError
+	terminated    bool
+}
+
+// handleReport сопоставляет InformationReport с командой.
+// Возвращает false, если отчёт к команде не относится.
+func (p *pendingControl) handleReport(report *mms.InformationReportPDU) bool {
+	if report.VariableListName != nil {
+		return false
+	}
+
+	handled := false
+	for i, name := range report.Variables {
+		if i >= len(report.Results) || !report.Results[i].Success {
+			continue
+		}
+		switch {
+		case name == mms.ObjectName{ItemID: "LastApplError"}:
+			lastApplError, err := ParseLastApplError(report.Results[i].Value)
+			if err != nil || lastApplError.CtlNum != p.ctlNum || !p.controls(lastApplError.CntrlObj) {
+				continue
+			}
+			p.lastApplError = lastApplError
+			handled = true
+		case name == p.variable:
+			// CommandTermination: "+" - только Oper, "-" - LastApplError и Oper
+			p.terminated = true
+			handled = true
+		}
+	}
+	return handled
+}
+
+// controls сообщает, относится ли CntrlObj к объекту команды: совпадает с ним
+// или продолжает его после "$" (LN$CO$DO$Oper). Объект SPCSO10 не относится к SPCSO1.
+func (p *pendingControl) controls(cntrlObj string) bool {
+	rest, ok := strings.CutPrefix(cntrlObj, p.object)
+	return ok && (rest == "" || rest[0] == '$')
+}
+
+// Operate выполняет команду управления объектом reference
+// ("LD/LN.DO", например "simpleIOGenericIO/GGIO1.SPCSO1"):
+// записывает структуру OperValue в LN$CO$DO$Oper.
+//
+// Отказ сервера возвращается как *LastApplError, если сервер его прислал,
+// иначе как *mms.DataAccessError. С WithCommandTermination Operate дополнительно
+// ждёт завершения команды.
+//
+// Если объект выбран через Select или SelectWithValue и sboTimeout истёк,
+// команда не отправляется (ErrSelectionExpired) или объект выбирается повторно
+// (WithAutoReselect).
+//
+// Команда выполняется с приоритетом PriorityControl, если в ctx не задан другой.
+func (c *MmsClient) Operate(ctx context.Context, reference string, ctlVal *variant.Variant, opts ...ControlOption) error {
+	ctx = withDefaultPriority(ctx, PriorityControl)
+	selected, err := c.checkSelection(ctx, reference, ctlVal, opts...)
+	if err != nil {
+		return err
+	}
+	// После Operate сервер снимает выбор независимо от результата
+	defer c.selections.clear(reference)
+
+	var params controlParams
+	for _, opt := range opts {
+		opt(&params)
+	}
+	if params.waitTermination {
+		// CommandTermination читается до освобождения ассоциации, чтобы его не принял Listen
+		held, release, err := c.holdAssociation(ctx)
+		if err != nil {
+			return err
+		}
+		defer release()
+		ctx = held
+	}
+
+	// При sbo-with-enhanced-security сервер сверяет ctlNum и origin Oper с записанными в SBOw
+	ctlNum, origin := selected.ctlNum, selected.origin
+	if !selected.withValue {
+		ctlNum, origin = c.nextCtlNum(), c.origin
+	}
+	control, err := c.writeControl(ctx, reference, "Oper", c.operValue(ctlVal, ctlNum, origin, opts...))
+	if err != nil || !params.waitTermination {
+		return err
+	}
+
+	// CommandTermination может прийти не сразу: читаем отчёты до завершения команды
+	for !control.terminated {
+		mmsData, err := c.mmsClient.ReceiveAndParseMmsResponse(ctx)
+		if err != nil {
+			return fmt.Errorf("waiting for CommandTermination of %s: %w", control.variable, err)
+		}
+		if !c.handleServerRequest(ctx, mmsData) {
+			c.dispatchReport(ctx, mmsData, control.handleReport)
+		}
+	}
+	if control.lastApplError != nil {
+		return control.lastApplError
+	}
+	return nil
+}
+
+// writeControl записывает значение структуры управления value в LN$CO$DO$attribute
+// объекта reference и сопоставляет с командой пришедший до ответа LastApplError
+func (c *MmsClient) writeControl(ctx context.Context, reference, attribute string, value *variant.Variant) (*pendingControl, error) {
+	request, err := c.NewReadRequest(reference, mms.FCCO)
+	if err != nil {
+		return nil, err
+	}
+	control := &pendingControl{
+		variable: mms.ObjectName{DomainID: request.DomainID, ItemID: request.ItemID + "$" + attribute},
+		object:   request.DomainID + "/" + request.ItemID,
+		ctlNum:   uint8(value.Structure()[2].Uint32()),
+	}
+
+	err = c.writeVariable(ctx, control.variable.DomainID, control.variable.ItemID, value, control.handleReport)
+	if err != nil {
+		if control.lastApplError != nil {
+			return nil, control.lastApplError
+		}
+		return nil, err
+	}
+	return control, nil
+}
+
+// dispatchReport разбирает unconfirmed-PDU и передаёт InformationReport в handle.
+// Не принятые handle отчёты передаются обработчику WithReportHandler или ставятся
+// в очередь отчётов, если она задана.
+// Возвращает false, если mmsData - не InformationReport.
+func (c *MmsClient) dispatchReport(ctx context.Context, mmsData []byte, handle func(*mms.InformationReportPDU) bool) bool {
+	if !mms.IsInformationReport(mmsData) {
+		return false
+	}
+	report, err := mms.ParseInformationReport(mmsData)
+	if err != nil {
+		c.logger.Debug("failed to parse InformationReport: %v", err)
+		return true
+	}
+	if c.clockSkew != nil || c.valueCache != nil {
+		r, _ := ParseReport(report, c.profile.Quirks)
+		if r != nil && c.clockSkew != nil {
+			c.clockSkew.ObserveReport(r)
+		}
+		c.cacheReport(report, r)
+	}
+	if handle != nil && handle(report) {
+		return true
+	}
+	if c.reportHandler != nil {
+		c.reportHandler(report)
+		return true
+	}
+	if c.reports == nil {
+		c.logger.Debug("InformationReport dropped: %x", mmsData)
+		return true
+	}
+	if err := c.reports.Push(ctx, report); err != nil {
+		c.logger.Debug("InformationReport dropped: %v", err)
+	}
+	return true
+}
+
+// writeVariable записывает значение одной переменной сервиса MMS Write.
+// InformationReport, пришедшие до ответа (например, LastApplError), передаются в onReport.
+func (c *MmsClient) writeVariable(ctx context.Context, domainID, itemID string, value *variant.Variant,
+	onReport func(*mms.InformationReportPDU) bool) (err error) {
+	defer c.audit(writeOperation(itemID), domainID+"/"+itemID, value, time.Now(), &err)
+	response, err := c.write(ctx, &mms.WriteRequest{DomainID: domainID, ItemID: itemID, Value: value}, onReport)
+	if err != nil {
+		return err
+	}
+	return response.Err()
+}
+
+// write выполняет запрос MMS Write и возвращает ответ, сопоставленный
+// с переменными запроса (mms.WriteResponse.Bind). Статистика учитывается
+// по каждой переменной; InformationReport до ответа передаются в onReport.
+func (c *MmsClient) write(ctx context.Context, request *mms.WriteRequest,
+	onReport func(*mms.InformationReportPDU) bool) (response *mms.WriteResponse, err error) {
+	items := request.Variables()
+	references := make([]string, len(items))
+	for i, item := range items {
+		references[i] = item.Variable.DomainID + "/" + item.Variable.ItemID
+	}
+	if err := c.checkWritable("write " + strings.Join(references, ", ")); err != nil {
+		return nil, err
+	}
+	finish, err := c.beginRequest(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer finish()
+	start := time.Now()
+	defer func() {
+		for i, reference := range references {
+			c.recordStats(reference, start, err != nil || !response.Results[i].Success())
+		}
+	}()
+
+	if c.mmsClient == nil {
+		return nil, fmt.Errorf("connection not established, call Initiate first")
+	}
+	if err := c.checkService(mms.Write); err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		if err := c.checkNames(item.Variable.DomainID, item.Variable.ItemID); err != nil {
+			return nil, err
+		}
+	}
+
+	invokeID, err := c.mmsClient.AllocateInvokeID()
+	if err != nil {
+		return nil, err
+	}
+	defer c.mmsClient.ReleaseInvokeID(invokeID)
+
+	// invokeID проставляет клиент; запрос вызывающего не изменяем
+	numbered := *request
+	numbered.InvokeID = invokeID
+	if c.testMode {
+		numbered.Value, numbered.Items = nil, make([]mms.WriteItem, len(items))
+		for i, item := range items {
+			numbered.Items[i] = mms.WriteItem{Variable: item.Variable, Value: markTest(item.Value)}
+		}
+	}
+	mmsPdu, err := numbered.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	c.logger.Debug("MMS Write Request PDU: %x", mmsPdu)
+
+	if err := c.mmsClient.SendMmsPduContext(ctx, mmsPdu); err != nil {
+		return nil, fmt.Errorf("failed to send Write Request: %w", err)
+	}
+
+	var mmsData []byte
+	for {
+		mmsData, err = c.receiveResponse(ctx, invokeID)
+		if err != nil {
+			return nil, err
+		}
+		if !c.dispatchReport(ctx, mmsData, onReport) {
+			break
+		}
+	}
+	c.logger.Debug("MMS Write Response PDU (raw bytes): %x", mmsData)
+
+	response, err = mms.ParseWriteResponse(mmsData)
+	if err != nil {
+		return nil, responseError("Write", err)
+	}
+	if err := response.Bind(request); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
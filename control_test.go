@@ -0,0 +1,133 @@
+package go61850
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/slonegd/go61850/mmstest"
+	"github.com/slonegd/go61850/model"
+	"github.com/slonegd/go61850/osi/mms"
+	"github.com/slonegd/go61850/osi/mms/variant"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOperValue(t *testing.T) {
+	c := &MmsClient{}
+	WithControlOrigin(model.OrCatStationControl, "scada")(c)
+	WithTestMode(true)(c)
+	assert.Equal(t, ControlOrigin{Category: model.OrCatStationControl, Identification: []byte("scada")}, c.Origin())
+	assert.True(t, c.TestMode())
+
+	operTime := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	oper := c.OperValue(variant.NewBoolVariant(true), WithOperTime(operTime), WithInterlockCheck())
+	assert.Equal(t, "struct{bool(true), struct{int32(2), octet-string(7363616461)}, uint32(0), "+
+		"utc-time(2024-01-02T03:04:05Z), bool(true), bit-string(0b10)}", oper.String())
+
+	// ctlNum увеличивается с каждой командой
+	oper = c.OperValue(variant.NewBoolVariant(false), WithSynchroCheck())
+	assert.Equal(t, uint32(1), oper.Structure()[2].Uint32())
+	assert.Equal(t, []byte{0x80}, oper.Structure()[5].BitString().Data)
+
+	_, err := mms.EncodeData(oper)
+	assert.NoError(t, err)
+}
+
+func TestTestModeWrite(t *testing.T) {
+	// validity good, source substituted; в режиме испытаний добавляется бит test
+	quality := variant.NewBitStringVariant([]byte{0x00, 0x20}, 13)
+	marked := variant.NewBitStringVariant([]byte{0x00, 0x30}, 13)
+	assert.Equal(t, marked, markTest(quality))
+	value := variant.NewStructureVariant([]*variant.Variant{variant.NewFloat32Variant(1), quality})
+	assert.Equal(t, variant.NewStructureVariant([]*variant.Variant{variant.NewFloat32Variant(1), marked}), markTest(value))
+	assert.Equal(t, []byte{0x00, 0x20}, quality.BitString().Data)
+	check := variant.NewBitStringVariant([]byte{0x00}, 2)
+	assert.Same(t, check, markTest(check))
+
+	server := mmstest.NewTranscriptServer(t, append(fileTranscript(t),
+		writeExchange(t, "GGIO1$SV$AnIn1$subQ", marked))...)
+	conn := server.Dial()
+	ctx := context.Background()
+	client, err := NewMmsClient(ctx, conn, WithTestMode(true))
+	assert.NoError(t, err)
+	_, err = client.Initiate(ctx)
+	assert.NoError(t, err)
+	assert.NoError(t, client.Write(ctx, "LD/GGIO1.AnIn1.subQ", mms.FCSV, quality))
+	conn.Close()
+	assert.NoError(t, server.Wait())
+
+	_, err = NewMmsClient(ctx, nil, WithControlOrigin(model.OrCatStationControl, strings.Repeat("x", 65)))
+	assert.EqualError(t, err, "orIdent of 65 bytes exceeds 64")
+}
+
+func lastApplErrorValue(cntrlObj string, ctlNum uint32, addCause model.AddCause) *variant.Variant {
+	return variant.NewStructureVariant([]*variant.Variant{
+		variant.NewVisibleStringVariant(cntrlObj),
+		variant.NewInt32Variant(int32(model.ControlErrorUnknown)),
+		variant.NewStructureVariant([]*variant.Variant{
+			variant.NewInt32Variant(int32(model.OrCatStationControl)),
+			variant.NewOctetStringVariant([]byte("scada")),
+		}),
+		variant.NewUint32Variant(ctlNum),
+		variant.NewInt32Variant(int32(addCause)),
+	})
+}
+
+func TestPendingControlHandleReport(t *testing.T) {
+	oper := mms.ObjectName{DomainID: "LD0", ItemID: "CSWI1$CO$Pos$Oper"}
+	control := &pendingControl{variable: oper, object: "LD0/CSWI1$CO$Pos", ctlNum: 3}
+
+	// Отчёты RCB к команде не относятся
+	assert.False(t, control.handleReport(&mms.InformationReportPDU{VariableListName: &mms.ObjectName{ItemID: "RPT"}}))
+
+	// LastApplError другой команды пропускается
+	other := &mms.InformationReportPDU{
+		Variables: []mms.ObjectName{{ItemID: "LastApplError"}},
+		Results:   []mms.AccessResult{{Success: true, Value: lastApplErrorValue("LD0/CSWI1$CO$Pos$Oper", 2, model.AddCauseBlockedByInterlocking)}},
+	}
+	assert.False(t, control.handleReport(other))
+	assert.Nil(t, control.lastApplError)
+
+	// CommandTermination-: LastApplError и Oper
+	termination := &mms.InformationReportPDU{
+		Variables: []mms.ObjectName{{ItemID: "LastApplError"}, oper},
+		Results: []mms.AccessResult{
+			{Success: true, Value: lastApplErrorValue("LD0/CSWI1$CO$Pos$Oper", 3, model.AddCauseBlockedByInterlocking)},
+			{Success: true, Value: variant.NewBoolVariant(true)},
+		},
+	}
+	assert.True(t, control.handleReport(termination))
+	assert.True(t, control.terminated)
+	assert.Equal(t, &LastApplError{
+		CntrlObj:  "LD0/CSWI1$CO$Pos$Oper",
+		ErrorCode: model.ControlErrorUnknown,
+		Origin:    ControlOrigin{Category: model.OrCatStationControl, Identification: []byte("scada")},
+		CtlNum:    3,
+		AddCause:  model.AddCauseBlockedByInterlocking,
+	}, control.lastApplError)
+	assert.EqualError(t, control.lastApplError, "control LD0/CSWI1$CO$Pos$Oper (ctlNum 3) failed: blocked-by-interlocking (unknown)")
+}
+
+func TestPendingControlObject(t *testing.T) {
+	oper := mms.ObjectName{DomainID: "LD0", ItemID: "GGIO1$CO$SPCSO1$Oper"}
+	control := &pendingControl{variable: oper, object: "LD0/GGIO1$CO$SPCSO1", ctlNum: 3}
+	report := func(cntrlObj string) *mms.InformationReportPDU {
+		return &mms.InformationReportPDU{
+			Variables: []mms.ObjectName{{ItemID: "LastApplError"}},
+			Results:   []mms.AccessResult{{Success: true, Value: lastApplErrorValue(cntrlObj, 3, model.AddCauseBlockedByInterlocking)}},
+		}
+	}
+
+	// Отказ SPCSO10 с тем же ctlNum не относится к команде SPCSO1
+	assert.False(t, control.handleReport(report("LD0/GGIO1$CO$SPCSO10$Oper")))
+	assert.Nil(t, control.lastApplError)
+	assert.True(t, control.handleReport(report("LD0/GGIO1$CO$SPCSO1$Oper")))
+	assert.Equal(t, "LD0/GGIO1$CO$SPCSO1$Oper", control.lastApplError.CntrlObj)
+	assert.True(t, control.controls("LD0/GGIO1$CO$SPCSO1"))
+}
+
+func TestParseLastApplError(t *testing.T) {
+	_, err := ParseLastApplError(variant.NewInt32Variant(1))
+	assert.EqualError(t, err, "LastApplError: expected structure of 5 elements, got int32(1)")
+}
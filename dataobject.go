@@ -0,0 +1,103 @@
+package go61850
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/slonegd/go61850/osi/mms"
+	"github.com/slonegd/go61850/osi/mms/variant"
+)
+
+// ReadDataObject читает объект данных doRef (например, "LD0/GGIO1.AnIn1") с функциональной
+// связью fc и возвращает его атрибуты по путям: "mag.f", "q", "t" (см. mms.FlattenValue).
+// Тип объекта запрашивается GetVariableAccessAttributes, значение - одним запросом Read.
+// Если профиль устройства содержит QuirkNoFCDRead, каждый атрибут читается отдельным
+// запросом Read. Отказ сервера в доступе возвращается как *mms.ReadError (см. ReadDataSet).
+func (c *MmsClient) ReadDataObject(ctx context.Context, doRef string, fc mms.FunctionalConstraint) (map[string]*variant.Variant, error) {
+	request, err := c.NewReadRequest(doRef, fc)
+	if err != nil {
+		return nil, err
+	}
+	spec, err := c.GetTypeSpecification(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	if c.profile.Has(QuirkNoFCDRead) {
+		return c.readAttributes(ctx, request, spec)
+	}
+	result, err := c.ReadObject(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	name := mms.ObjectName{DomainID: request.DomainID, ItemID: request.ItemID}.String()
+	if readErr := mms.NewReadError([]mms.AccessResult{result}, []string{name}); readErr != nil {
+		return nil, readErr
+	}
+	values, err := mms.FlattenValue(spec, result.Value)
+	if err != nil {
+		return nil, fmt.Errorf("read %s/%s: %w", request.DomainID, request.ItemID, err)
+	}
+	return values, nil
+}
+
+// dataAttribute - атрибут объекта данных, читаемый отдельным запросом
+type dataAttribute struct {
+	path   string
+	itemID string
+	spec   *mms.TypeSpecification
+}
+
+// dataAttributes раскрывает структуру spec до атрибутов, не являющихся структурами.
+// Массивы читаются целиком.
+func dataAttributes(attributes []dataAttribute, path, itemID string, spec *mms.TypeSpecification) []dataAttribute {
+	if spec == nil || spec.Type != mms.TypeSpecStructure || spec.Structure == nil || len(spec.Structure.Components) == 0 {
+		return append(attributes, dataAttribute{path: path, itemID: itemID, spec: spec})
+	}
+	for _, component := range spec.Structure.Components {
+		name := component.Name
+		if path != "" {
+			name = path + "." + name
+		}
+		attributes = dataAttributes(attributes, name, itemID+"$"+component.Name, component.Type)
+	}
+	return attributes
+}
+
+// readAttributes читает атрибуты объекта данных request по одному (QuirkNoFCDRead)
+func (c *MmsClient) readAttributes(ctx context.Context, request *mms.ReadRequest, spec *mms.TypeSpecification) (map[string]*variant.Variant, error) {
+	attributes := dataAttributes(nil, "", request.ItemID, spec)
+	results := make([]mms.AccessResult, 0, len(attributes))
+	names := make([]string, 0, len(attributes))
+	for _, attribute := range attributes {
+		result, err := c.ReadObject(ctx, &mms.ReadRequest{DomainID: request.DomainID, ItemID: attribute.itemID})
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+		names = append(names, mms.ObjectName{DomainID: request.DomainID, ItemID: attribute.itemID}.String())
+	}
+	if readErr := mms.NewReadError(results, names); readErr != nil {
+		return nil, readErr
+	}
+
+	values := map[string]*variant.Variant{}
+	for i, attribute := range attributes {
+		flat, err := mms.FlattenValue(attribute.spec, results[i].Value)
+		if err != nil {
+			return nil, fmt.Errorf("read %s/%s: %w", request.DomainID, attribute.itemID, err)
+		}
+		for path, value := range flat {
+			switch {
+			case path == "":
+				path = attribute.path
+			case attribute.path != "" && !strings.HasPrefix(path, "("):
+				path = attribute.path + "." + path
+			default:
+				path = attribute.path + path
+			}
+			values[path] = value
+		}
+	}
+	return values, nil
+}
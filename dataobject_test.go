@@ -0,0 +1,62 @@
+package go61850
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/slonegd/go61850/mmstest"
+	"github.com/slonegd/go61850/osi/mms"
+	"github.com/slonegd/go61850/osi/mms/variant"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadDataObject(t *testing.T) {
+	// Тип GGIO1$MX$AnIn1 из ответа server_example_basic_io: mag.f, q, t
+	attributes, err := hex.DecodeString("a13d020101a638800100a233" +
+		"a231a12f301a80036d6167a113a211a10f300d800166a108a7060201200201083008800171a1038401f33007800174a1029100")
+	assert.NoError(t, err)
+
+	f := variant.NewFloat32Variant(1.5)
+	q := variant.NewBitStringVariant([]byte{0, 0}, 13)
+	ts := variant.NewUTCTimeVariant(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+	read, err := (&mms.ReadResponse{InvokeID: 2, ListOfAccessResult: []mms.AccessResult{{
+		Success: true,
+		Value:   variant.NewStructureVariant([]*variant.Variant{variant.NewStructureVariant([]*variant.Variant{f}), q, ts}),
+	}}}).Bytes()
+	assert.NoError(t, err)
+
+	typeRequest := mms.NewGetVariableAccessAttributesRequest("simpleIOGenericIO", "GGIO1$MX$AnIn1")
+	readRequest := &mms.ReadRequest{DomainID: "simpleIOGenericIO", ItemID: "GGIO1$MX$AnIn1"}
+	denied, err := (&mms.ReadResponse{InvokeID: 1, ListOfAccessResult: []mms.AccessResult{{
+		Error: &mms.DataAccessError{ErrorCode: mms.ObjectAccessDenied},
+	}}}).Bytes()
+	assert.NoError(t, err)
+	exchanges := append(fileTranscript(t),
+		mmstest.Exchange{Request: mmsRequest(typeRequest.Bytes()), Responses: []string{mmsFrame(attributes)}},
+		mmstest.Exchange{Request: mmsRequest(readRequest.Bytes()), Responses: []string{mmsFrame(read)}},
+		mmstest.Exchange{Request: mmsRequest(typeRequest.Bytes()), Responses: []string{mmsFrame(attributes)}},
+		mmstest.Exchange{Request: mmsRequest(readRequest.Bytes()), Responses: []string{mmsFrame(denied)}},
+	)
+	server := mmstest.NewTranscriptServer(t, exchanges...)
+	conn := server.Dial()
+	ctx := context.Background()
+	client, err := NewMmsClient(ctx, conn, WithDefaultDomain("simpleIOGenericIO"))
+	assert.NoError(t, err)
+	_, err = client.Initiate(ctx)
+	assert.NoError(t, err)
+
+	values, err := client.ReadDataObject(ctx, "GGIO1.AnIn1", mms.FCMX)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]*variant.Variant{"mag.f": f, "q": q, "t": ts}, values)
+
+	_, err = client.ReadDataObject(ctx, "GGIO1.AnIn1", mms.FCMX)
+	var readErr *mms.ReadError
+	assert.ErrorAs(t, err, &readErr)
+	assert.Equal(t, "simpleIOGenericIO/GGIO1$MX$AnIn1", readErr.Failures[0].Variable)
+	assert.ErrorIs(t, err, &mms.DataAccessError{ErrorCode: mms.ObjectAccessDenied})
+
+	conn.Close()
+	assert.NoError(t, server.Wait())
+}
@@ -0,0 +1,138 @@
+package go61850
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/slonegd/go61850/osi/mms"
+)
+
+// GetDataSetDirectory запрашивает элементы набора данных dataSet
+// (GetNamedVariableListAttributes) в порядке их определения на сервере.
+func (c *MmsClient) GetDataSetDirectory(ctx context.Context, dataSet mms.ObjectName) (_ *mms.GetNamedVariableListAttributesResponse, err error) {
+	finish, err := c.beginRequest(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer finish()
+	start := time.Now()
+	defer func() {
+		c.recordStats(dataSet.String(), start, err != nil)
+	}()
+
+	if c.mmsClient == nil {
+		return nil, fmt.Errorf("connection not established, call Initiate first")
+	}
+	if err := c.checkService(mms.GetNamedVariableListAttributes); err != nil {
+		return nil, err
+	}
+	if err := c.checkNames(dataSet.DomainID, dataSet.ItemID); err != nil {
+		return nil, err
+	}
+
+	invokeID, err := c.mmsClient.AllocateInvokeID()
+	if err != nil {
+		return nil, err
+	}
+	defer c.mmsClient.ReleaseInvokeID(invokeID)
+
+	request := &mms.GetNamedVariableListAttributesRequest{InvokeID: invokeID, DataSet: dataSet}
+	mmsPdu, err := request.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	c.logger.Debug("MMS GetNamedVariableListAttributes Request PDU: %x", mmsPdu)
+
+	if err := c.mmsClient.SendMmsPduContext(ctx, mmsPdu); err != nil {
+		return nil, fmt.Errorf("failed to send GetNamedVariableListAttributes Request: %w", err)
+	}
+	mmsData, err := c.receiveConfirmed(ctx, invokeID)
+	if err != nil {
+		return nil, err
+	}
+	c.logger.Debug("MMS GetNamedVariableListAttributes Response PDU (raw bytes): %x", mmsData)
+
+	response, err := mms.ParseGetNamedVariableListAttributesResponse(mmsData)
+	if err != nil {
+		return nil, responseError("GetNamedVariableListAttributes", err)
+	}
+	return response, nil
+}
+
+// ReadDataSet читает набор данных dataSet одним запросом Read с variableListName
+// и сопоставляет результаты элементам members (см. mms.MapDataSetResults).
+// members задаются в порядке определения набора на сервере; при nil members
+// они сначала запрашиваются через GetDataSetDirectory. Если число элементов
+// не совпадает с числом результатов, возвращается ошибка.
+//
+// Если сервер отказал в доступе к части элементов, возвращаются все значения
+// и *mms.ReadError со списком отказов.
+func (c *MmsClient) ReadDataSet(ctx context.Context, dataSet mms.ObjectName, members []mms.DataSetMember) (_ []mms.DataSetValue, err error) {
+	if members == nil {
+		directory, err := c.GetDataSetDirectory(ctx, dataSet)
+		if err != nil {
+			return nil, err
+		}
+		members = directory.Members
+	}
+
+	finish, err := c.beginRequest(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer finish()
+	start := time.Now()
+	defer func() {
+		c.recordStats(dataSet.String(), start, err != nil)
+	}()
+
+	if c.mmsClient == nil {
+		return nil, fmt.Errorf("connection not established, call Initiate first")
+	}
+	if err := c.checkService(mms.Read); err != nil {
+		return nil, err
+	}
+	if err := c.checkNames(dataSet.DomainID, dataSet.ItemID); err != nil {
+		return nil, err
+	}
+
+	invokeID, err := c.mmsClient.AllocateInvokeID()
+	if err != nil {
+		return nil, err
+	}
+	defer c.mmsClient.ReleaseInvokeID(invokeID)
+
+	request := &mms.ReadDataSetRequest{InvokeID: invokeID, DataSet: dataSet}
+	mmsPdu, err := request.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	c.logger.Debug("MMS Read Request PDU (data set %s): %x", dataSet, mmsPdu)
+
+	if err := c.mmsClient.SendMmsPduContext(ctx, mmsPdu); err != nil {
+		return nil, fmt.Errorf("failed to send Read Request: %w", err)
+	}
+	mmsData, err := c.receiveConfirmed(ctx, invokeID)
+	if err != nil {
+		return nil, err
+	}
+	c.logger.Debug("MMS Read Response PDU (raw bytes): %x", mmsData)
+
+	readResponse, err := mms.ParseReadResponse(mmsData)
+	if err != nil {
+		return nil, responseError("Read", err)
+	}
+	values, err := mms.MapDataSetResults(members, readResponse.ListOfAccessResult)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(members))
+	for i, member := range members {
+		names[i] = member.String()
+	}
+	if readErr := mms.NewReadError(readResponse.ListOfAccessResult, names); readErr != nil {
+		return values, readErr
+	}
+	return values, nil
+}
@@ -0,0 +1,135 @@
+package go61850
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/slonegd/go61850/osi/mms"
+)
+
+// DefaultDiagnosticsSize - число последних событий в журнале диагностики по умолчанию
+const DefaultDiagnosticsSize = 128
+
+// DiagnosticEvent - запись журнала диагностики соединения
+type DiagnosticEvent struct {
+	Time time.Time
+	// Event - событие протокола или краткое содержание MMS PDU
+	Event string
+}
+
+// String возвращает запись в виде "15:04:05.000000 событие"
+func (e DiagnosticEvent) String() string {
+	return e.Time.Format("15:04:05.000000") + " " + e.Event
+}
+
+// diagnosticsJournal - кольцевой буфер последних событий соединения
+type diagnosticsJournal struct {
+	mu     sync.Mutex
+	events []DiagnosticEvent
+	// next - позиция следующей записи; full - буфер заполнен и перезаписывается
+	next    int
+	full    bool
+	dropped uint64
+}
+
+func newDiagnosticsJournal(size int) *diagnosticsJournal {
+	return &diagnosticsJournal{events: make([]DiagnosticEvent, size)}
+}
+
+// add добавляет событие, вытесняя самое старое при заполнении
+func (j *diagnosticsJournal) add(event string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.full {
+		j.dropped++
+	}
+	j.events[j.next] = DiagnosticEvent{Time: time.Now(), Event: event}
+	j.next++
+	if j.next == len(j.events) {
+		j.next, j.full = 0, true
+	}
+}
+
+// snapshot возвращает события от старых к новым и число вытесненных
+func (j *diagnosticsJournal) snapshot() ([]DiagnosticEvent, uint64) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if !j.full {
+		return append([]DiagnosticEvent(nil), j.events[:j.next]...), 0
+	}
+	events := append([]DiagnosticEvent(nil), j.events[j.next:]...)
+	return append(events, j.events[:j.next]...), j.dropped
+}
+
+// WithDiagnostics задаёт размер журнала диагностики - кольцевого буфера последних
+// событий ассоциации и кратких описаний MMS PDU (по умолчанию DefaultDiagnosticsSize).
+// Журнал позволяет разобрать ошибку в эксплуатации без постоянного отладочного
+// логирования (см. DumpDiagnostics). 0 отключает журнал.
+func WithDiagnostics(size int) MmsClientOption {
+	return func(c *MmsClient) {
+		c.diagnostics = nil
+		if size > 0 {
+			c.diagnostics = newDiagnosticsJournal(size)
+		}
+	}
+}
+
+// Diagnostics возвращает события журнала диагностики от старых к новым;
+// nil, если журнал отключён
+func (c *MmsClient) Diagnostics() []DiagnosticEvent {
+	if c.diagnostics == nil {
+		return nil
+	}
+	events, _ := c.diagnostics.snapshot()
+	return events
+}
+
+// DumpDiagnostics записывает журнал диагностики в w по событию на строку,
+// например при ошибке запроса:
+//
+//	if _, err := client.ReadObject(ctx, request); err != nil {
+//		client.DumpDiagnostics(os.Stderr)
+//	}
+func (c *MmsClient) DumpDiagnostics(w io.Writer) error {
+	if c.diagnostics == nil {
+		_, err := fmt.Fprintln(w, "diagnostics disabled")
+		return err
+	}
+	events, dropped := c.diagnostics.snapshot()
+	if dropped > 0 {
+		if _, err := fmt.Fprintf(w, "... %d earlier events dropped\n", dropped); err != nil {
+			return err
+		}
+	}
+	for _, e := range events {
+		if _, err := fmt.Fprintln(w, e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// diag добавляет событие в журнал диагностики, если он включён
+func (c *MmsClient) diag(format string, args ...any) {
+	if c.diagnostics != nil {
+		c.diagnostics.add(fmt.Sprintf(format, args...))
+	}
+}
+
+// pduTap возвращает получателя PDU стека: пользовательский tap (WithRawPDUTap)
+// и запись MMS PDU в журнал диагностики
+func (c *MmsClient) pduTap() mms.RawPDUTap {
+	if c.diagnostics == nil {
+		return c.tap
+	}
+	return func(direction mms.Direction, layer mms.Layer, data []byte) {
+		if layer == mms.LayerMMS {
+			c.diag("%s %s (%d bytes)", direction, mms.Summary(data), len(data))
+		}
+		if c.tap != nil {
+			c.tap(direction, layer, data)
+		}
+	}
+}
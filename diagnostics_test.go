@@ -0,0 +1,81 @@
+package go61850
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/slonegd/go61850/mmstest"
+	"github.com/slonegd/go61850/osi/mms"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiagnostics(t *testing.T) {
+	f, err := os.Open("mmstest/testdata/read.txt")
+	assert.NoError(t, err)
+	defer f.Close()
+	exchanges, err := mmstest.ParseTranscript(f)
+	assert.NoError(t, err)
+
+	server := mmstest.NewTranscriptServer(t, exchanges...)
+	conn := server.Dial()
+	ctx := context.Background()
+	client, err := NewMmsClient(ctx, conn)
+	assert.NoError(t, err)
+	_, err = client.Initiate(ctx)
+	assert.NoError(t, err)
+	_, err = client.ReadObject(ctx, &mms.ReadRequest{DomainID: "simpleIOGenericIO", ItemID: "GGIO1$MX"})
+	assert.NoError(t, err)
+	conn.Close()
+	assert.NoError(t, server.Wait())
+
+	var events []string
+	for _, e := range client.Diagnostics() {
+		events = append(events, e.Event)
+	}
+	assert.Len(t, events, 6)
+	assert.Equal(t, "COTP connection established", events[0])
+	assert.True(t, strings.HasPrefix(events[1], "send initiate-RequestPDU ("), events[1])
+	assert.True(t, strings.HasPrefix(events[2], "receive initiate-ResponsePDU ("), events[2])
+	assert.True(t, strings.HasPrefix(events[3], "association established: Capabilities{"), events[3])
+	assert.True(t, strings.HasPrefix(events[4], "send confirmed-RequestPDU invokeID "), events[4])
+	assert.Contains(t, events[4], " read (")
+	assert.True(t, strings.HasPrefix(events[5], "receive confirmed-ResponsePDU invokeID "), events[5])
+
+	var dump bytes.Buffer
+	assert.NoError(t, client.DumpDiagnostics(&dump))
+	lines := strings.Split(strings.TrimSuffix(dump.String(), "\n"), "\n")
+	assert.Len(t, lines, 6)
+	assert.True(t, strings.HasSuffix(lines[0], " COTP connection established"), lines[0])
+}
+
+func TestDiagnosticsJournal(t *testing.T) {
+	j := newDiagnosticsJournal(3)
+	events, dropped := j.snapshot()
+	assert.Empty(t, events)
+	assert.Zero(t, dropped)
+
+	for _, e := range []string{"a", "b", "c", "d", "e"} {
+		j.add(e)
+	}
+	events, dropped = j.snapshot()
+	assert.Equal(t, uint64(2), dropped)
+	var names []string
+	for _, e := range events {
+		names = append(names, e.Event)
+	}
+	assert.Equal(t, []string{"c", "d", "e"}, names)
+}
+
+func TestDiagnosticsDisabled(t *testing.T) {
+	client := &MmsClient{}
+	WithDiagnostics(0)(client)
+	client.diag("ignored")
+	assert.Nil(t, client.Diagnostics())
+
+	var dump bytes.Buffer
+	assert.NoError(t, client.DumpDiagnostics(&dump))
+	assert.Equal(t, "diagnostics disabled\n", dump.String())
+}
@@ -0,0 +1,80 @@
+package go61850
+
+import (
+	"context"
+	"fmt"
+	"net"
+)
+
+// DefaultPort - TCP порт MMS (ISO-TSAP, RFC 1006)
+const DefaultPort = "102"
+
+// DialContextFunc устанавливает транспортное соединение с сервером.
+// Сигнатура совпадает с net.Dialer.DialContext, поэтому подходят
+// SOCKS прокси (golang.org/x/net/proxy), мосты serial-over-TCP и тестовые каналы.
+type DialContextFunc func(ctx context.Context, network, address string) (net.Conn, error)
+
+// WithDialContext задаёт функцию установки соединения для Dial вместо net.Dialer
+func WithDialContext(dial DialContextFunc) MmsClientOption {
+	return func(c *MmsClient) {
+		c.dialContext = dial
+	}
+}
+
+// Dial устанавливает TCP соединение с сервером по адресу address ("host" или "host:port",
+// по умолчанию порт 102) и создаёт MMS клиент, как NewMmsClient.
+// Соединение устанавливается функцией из WithDialContext или net.Dialer;
+// с WithConnectionLimiter Dial сначала ожидает места в общем лимите соединений.
+// С WithTLS поверх соединения устанавливается TLS (по умолчанию порт 3782).
+func Dial(ctx context.Context, address string, opts ...MmsClientOption) (*MmsClient, error) {
+	options := &MmsClient{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	dial := options.dialContext
+	if dial == nil {
+		dial = (&net.Dialer{}).DialContext
+	}
+
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		port := DefaultPort
+		if options.tls != nil {
+			port = DefaultTLSPort
+		}
+		host, address = address, net.JoinHostPort(address, port)
+	}
+	if limiter := options.connections; limiter != nil {
+		if err := limiter.Acquire(ctx); err != nil {
+			return nil, fmt.Errorf("failed to dial %s: waiting for connection limit: %w", address, err)
+		}
+		limited := dial
+		dial = func(ctx context.Context, network, address string) (net.Conn, error) {
+			conn, err := limited(ctx, network, address)
+			if err != nil {
+				limiter.Release()
+				return nil, err
+			}
+			return &limitedConn{Conn: conn, limiter: limiter}, nil
+		}
+	}
+	conn, err := dial(ctx, "tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial %s: %w", address, err)
+	}
+	if options.tls != nil {
+		tlsConn, err := options.tls.handshake(ctx, conn, host)
+		if err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("failed to dial %s: %w", address, err)
+		}
+		conn = tlsConn
+	}
+
+	client, err := NewMmsClient(ctx, conn, opts...)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return client, nil
+}
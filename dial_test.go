@@ -0,0 +1,68 @@
+package go61850
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"testing"
+
+	"github.com/slonegd/go61850/mmstest"
+	"github.com/slonegd/go61850/osi/mms"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDial(t *testing.T) {
+	f, err := os.Open("mmstest/testdata/read.txt")
+	assert.NoError(t, err)
+	defer f.Close()
+	exchanges, err := mmstest.ParseTranscript(f)
+	assert.NoError(t, err)
+
+	server := mmstest.NewTranscriptServer(t, exchanges[:2]...)
+	var dialed []string
+	dial := func(ctx context.Context, network, address string) (net.Conn, error) {
+		dialed = append(dialed, network+" "+address)
+		return server.Dial(), nil
+	}
+
+	ctx := context.Background()
+	client, err := Dial(ctx, "ied1", WithDialContext(dial))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"tcp ied1:102"}, dialed)
+	_, err = client.Initiate(ctx)
+	assert.NoError(t, err)
+	client.conn.Close()
+	assert.NoError(t, server.Wait())
+
+	refused := errors.New("connection refused")
+	_, err = Dial(ctx, "[::1]:10102", WithDialContext(func(context.Context, string, string) (net.Conn, error) {
+		return nil, refused
+	}))
+	assert.ErrorIs(t, err, refused)
+	assert.EqualError(t, err, "failed to dial [::1]:10102: connection refused")
+}
+
+func TestNewMmsClientTransport(t *testing.T) {
+	f, err := os.Open("mmstest/testdata/read.txt")
+	assert.NoError(t, err)
+	defer f.Close()
+	exchanges, err := mmstest.ParseTranscript(f)
+	assert.NoError(t, err)
+
+	// Транспорт без методов net.Conn: клиент использует только io.ReadWriteCloser
+	server := mmstest.NewTranscriptServer(t, exchanges...)
+	conn := struct{ io.ReadWriteCloser }{server.Dial()}
+
+	ctx := context.Background()
+	client, err := NewMmsClient(ctx, conn)
+	assert.NoError(t, err)
+	_, err = client.Initiate(ctx)
+	assert.NoError(t, err)
+	result, err := client.ReadObject(ctx, &mms.ReadRequest{DomainID: "simpleIOGenericIO", ItemID: "GGIO1$MX"})
+	assert.NoError(t, err)
+	assert.True(t, result.Success)
+	conn.Close()
+	assert.NoError(t, server.Wait())
+}
@@ -0,0 +1,309 @@
+package go61850
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"iter"
+	"os"
+	"path/filepath"
+
+	"github.com/slonegd/go61850/model"
+	"github.com/slonegd/go61850/osi/mms"
+)
+
+// ModelCacheVersion - версия формата кэша модели; кэш другой версии игнорируется
+const ModelCacheVersion = 1
+
+// ModelCache - кэш обнаруженной модели, сохраняемый на диск в JSON.
+// Для каждого логического устройства хранятся MMS имена переменных и наборов
+// данных и ключ актуальности (например, ConfRev или NamPlt), по которому
+// решается, нужно ли обнаруживать устройство заново.
+type ModelCache struct {
+	Version        int                   `json:"version"`
+	LogicalDevices []CachedLogicalDevice `json:"logicalDevices"`
+}
+
+// CachedLogicalDevice - запись кэша для одного логического устройства
+type CachedLogicalDevice struct {
+	Name      string   `json:"name"`
+	Key       string   `json:"key"`
+	Variables []string `json:"variables"`
+	DataSets  []string `json:"dataSets,omitempty"`
+}
+
+// LoadModelCache читает кэш модели из файла.
+// Отсутствующий файл или кэш другой версии дают пустой кэш без ошибки.
+func LoadModelCache(path string) (*ModelCache, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &ModelCache{Version: ModelCacheVersion}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var cache ModelCache
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return nil, fmt.Errorf("failed to parse model cache %s: %w", path, err)
+	}
+	if cache.Version != ModelCacheVersion {
+		return &ModelCache{Version: ModelCacheVersion}, nil
+	}
+	return &cache, nil
+}
+
+// Save атомарно записывает кэш модели в файл
+func (mc *ModelCache) Save(path string) error {
+	data, err := json.MarshalIndent(mc, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, data)
+}
+
+// writeFileAtomic записывает data во временный файл рядом с path и переименовывает его в path,
+// чтобы при сбое во время записи на диске осталась прежняя версия файла
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// LogicalDevice возвращает запись кэша по имени или nil
+func (mc *ModelCache) LogicalDevice(name string) *CachedLogicalDevice {
+	for i := range mc.LogicalDevices {
+		if mc.LogicalDevices[i].Name == name {
+			return &mc.LogicalDevices[i]
+		}
+	}
+	return nil
+}
+
+// Model строит модель данных из кэша
+func (mc *ModelCache) Model() *model.Model {
+	m := &model.Model{}
+	for _, ld := range mc.LogicalDevices {
+		m.LogicalDevices = append(m.LogicalDevices, model.NewLogicalDeviceFromNames(ld.Name, ld.Variables, ld.DataSets))
+	}
+	return m
+}
+
+// CacheKeyFunc вычисляет ключ актуальности кэша для логического устройства.
+// Пустой ключ означает, что кэшу устройства доверять нельзя.
+type CacheKeyFunc func(ctx context.Context, c *MmsClient, ldName string) (string, error)
+
+// NamePlateKey - ключ кэша по умолчанию: значение LLN0.NamPlt.paramRev устройства.
+// paramRev меняется сервером при изменении конфигурации и уставок,
+// поэтому кэш может обновляться чаще необходимого, но не устаревает.
+// У устройств без paramRev (редакция 1) ключом служат valRev или configRev.
+func NamePlateKey(ctx context.Context, c *MmsClient, ldName string) (string, error) {
+	var errs []error
+	for i, itemID := range namePlateRevisions {
+		result, err := c.ReadObject(ctx, &mms.ReadRequest{DomainID: ldName, ItemID: itemID})
+		if err != nil {
+			return "", err
+		}
+		if !result.Success {
+			errs = append(errs, fmt.Errorf("read %s/%s: %w", ldName, itemID, result.Error))
+			continue
+		}
+		if i == 0 {
+			// Значение paramRev - ключ без имени атрибута
+			return result.Value.String(), nil
+		}
+		return itemID + "=" + result.Value.String(), nil
+	}
+	return "", errors.Join(errs...)
+}
+
+// namePlateRevisions - атрибуты LLN0.NamPlt для NamePlateKey в порядке предпочтения
+var namePlateRevisions = []string{"LLN0$ST$NamPlt$paramRev", "LLN0$ST$NamPlt$valRev", "LLN0$DC$NamPlt$configRev"}
+
+// DiscoverOption представляет опцию для DiscoverModel
+type DiscoverOption func(*discoverConfig)
+
+type discoverConfig struct {
+	cachePath string
+	keyFunc   CacheKeyFunc
+}
+
+// WithModelCache включает кэширование обнаруженной модели в файле path
+func WithModelCache(path string) DiscoverOption {
+	return func(cfg *discoverConfig) {
+		cfg.cachePath = path
+	}
+}
+
+// WithCacheKey задаёт функцию ключа актуальности кэша (по умолчанию NamePlateKey)
+func WithCacheKey(f CacheKeyFunc) DiscoverOption {
+	return func(cfg *discoverConfig) {
+		cfg.keyFunc = f
+	}
+}
+
+// DiscoverModel обнаруживает модель данных сервера через GetNameList:
+// список доменов (логических устройств), затем для каждого домена имена
+// переменных и наборов данных.
+//
+// Обнаружение выполняется по логическим устройствам. С WithModelCache устройства,
+// ключ актуальности которых совпал с кэшем, повторно не запрашиваются,
+// а кэш после обнаружения сохраняется; устройства, исчезнувшие с сервера,
+// удаляются из кэша.
+//
+// Запросы обзора выполняются с приоритетом PriorityBackground, если в ctx
+// не задан другой (WithPriority).
+func (c *MmsClient) DiscoverModel(ctx context.Context, opts ...DiscoverOption) (*model.Model, error) {
+	ctx = withDefaultPriority(ctx, PriorityBackground)
+	cfg := discoverConfig{keyFunc: NamePlateKey}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	cache := &ModelCache{Version: ModelCacheVersion}
+	if cfg.cachePath != "" {
+		var err error
+		cache, err = LoadModelCache(cfg.cachePath)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	domains, err := c.getAllNames(ctx, mms.ObjectClassDomain, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get domain list: %w", err)
+	}
+
+	discovered := &ModelCache{Version: ModelCacheVersion}
+	for _, domain := range domains {
+		entry, err := c.discoverLogicalDevice(ctx, cfg, cache, domain)
+		if err != nil {
+			return nil, err
+		}
+		discovered.LogicalDevices = append(discovered.LogicalDevices, *entry)
+	}
+
+	if cfg.cachePath != "" {
+		if err := discovered.Save(cfg.cachePath); err != nil {
+			return nil, fmt.Errorf("failed to save model cache: %w", err)
+		}
+	}
+	return discovered.Model(), nil
+}
+
+// DiscoverLogicalDevice обнаруживает одно логическое устройство без использования кэша
+func (c *MmsClient) DiscoverLogicalDevice(ctx context.Context, name string) (*model.LogicalDevice, error) {
+	ctx = withDefaultPriority(ctx, PriorityBackground)
+	entry, err := c.discoverLogicalDevice(ctx, discoverConfig{}, &ModelCache{}, name)
+	if err != nil {
+		return nil, err
+	}
+	return model.NewLogicalDeviceFromNames(entry.Name, entry.Variables, entry.DataSets), nil
+}
+
+// discoverLogicalDevice возвращает запись кэша для домена: из cache, если ключ
+// совпал, иначе запрашивая имена у сервера
+func (c *MmsClient) discoverLogicalDevice(ctx context.Context, cfg discoverConfig, cache *ModelCache, domain string) (*CachedLogicalDevice, error) {
+	var key string
+	if cfg.keyFunc != nil {
+		var err error
+		key, err = cfg.keyFunc(ctx, c, domain)
+		if err != nil {
+			c.logger.Debug("model cache key for %s: %v", domain, err)
+			key = ""
+		}
+	}
+	if cached := cache.LogicalDevice(domain); cached != nil && key != "" && cached.Key == key {
+		c.logger.Debug("model cache hit for %s (key %s)", domain, key)
+		return cached, nil
+	}
+
+	variables, err := c.getAllNames(ctx, mms.ObjectClassNamedVariable, domain)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get variables of %s: %w", domain, err)
+	}
+	dataSets, err := c.getAllNames(ctx, mms.ObjectClassNamedVariableList, domain)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get data sets of %s: %w", domain, err)
+	}
+	return &CachedLogicalDevice{Name: domain, Key: key, Variables: variables, DataSets: dataSets}, nil
+}
+
+// GetJournalList возвращает имена журналов (GetNameList с классом journal):
+// журналы домена domain либо, при пустом domain, журналы уровня VMD.
+// Имена журналов домена имеют вид "LLN0$EventLog" и используются
+// в запросах ReadJournal вместе с именем домена.
+func (c *MmsClient) GetJournalList(ctx context.Context, domain string) ([]string, error) {
+	journals, err := c.getAllNames(ctx, mms.ObjectClassJournal, domain)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get journals of %q: %w", domain, err)
+	}
+	return journals, nil
+}
+
+// Names перебирает имена объектов класса class домена domainID (при пустом
+// domainID - уровня VMD), повторяя GetNameList с continueAfter, пока сервер
+// отвечает moreFollows. Ошибка запроса, а также пустая страница с moreFollows
+// или страница, не продвинувшаяся дальше continueAfter (имена упорядочены
+// по возрастанию), передаётся последней парой итерации:
+//
+//	for name, err := range client.Names(ctx, mms.ObjectClassNamedVariable, "LD0") {
+//		if err != nil {
+//			return err
+//		}
+//		...
+//	}
+func (c *MmsClient) Names(ctx context.Context, class mms.ObjectClass, domainID string) iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		request := mms.NewGetNameListRequest(class, domainID)
+		for {
+			response, err := c.GetNameList(ctx, request)
+			if err != nil {
+				yield("", err)
+				return
+			}
+			for _, name := range response.Identifiers {
+				if !yield(name, nil) {
+					return
+				}
+			}
+			if !response.MoreFollows {
+				return
+			}
+			// Сервер, не продвигающийся по continueAfter, зациклил бы перебор
+			if len(response.Identifiers) == 0 {
+				yield("", errors.New("GetNameList: empty response with moreFollows"))
+				return
+			}
+			last := response.Identifiers[len(response.Identifiers)-1]
+			if request.ContinueAfter != "" && last <= request.ContinueAfter {
+				yield("", fmt.Errorf("GetNameList: response does not advance past continueAfter %q", request.ContinueAfter))
+				return
+			}
+			request.ContinueAfter = last
+		}
+	}
+}
+
+// getAllNames запрашивает полный список имён (см. Names)
+func (c *MmsClient) getAllNames(ctx context.Context, class mms.ObjectClass, domainID string) ([]string, error) {
+	names := []string{}
+	for name, err := range c.Names(ctx, class, domainID) {
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
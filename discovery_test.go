@@ -0,0 +1,221 @@
+package go61850
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/slonegd/go61850/mmstest"
+	"github.com/slonegd/go61850/osi/mms"
+	"github.com/slonegd/go61850/osi/mms/variant"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestModelCacheSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "model.json")
+
+	// Отсутствующий файл - пустой кэш
+	cache, err := LoadModelCache(path)
+	assert.NoError(t, err)
+	assert.Equal(t, &ModelCache{Version: ModelCacheVersion}, cache)
+
+	cache.LogicalDevices = []CachedLogicalDevice{{
+		Name:      "simpleIOGenericIO",
+		Key:       "int32(3)",
+		Variables: []string{"GGIO1", "GGIO1$MX", "GGIO1$MX$AnIn1", "GGIO1$MX$AnIn1$mag", "GGIO1$MX$AnIn1$mag$f"},
+		DataSets:  []string{"LLN0$Events"},
+	}}
+	assert.NoError(t, cache.Save(path))
+
+	loaded, err := LoadModelCache(path)
+	assert.NoError(t, err)
+	assert.Equal(t, cache, loaded)
+
+	m := loaded.Model()
+	ln := m.LogicalDevice("simpleIOGenericIO").LogicalNode("GGIO1")
+	assert.NotNil(t, ln)
+	assert.NotNil(t, ln.DataObject("AnIn1"))
+
+	// Кэш другой версии игнорируется
+	assert.NoError(t, os.WriteFile(path, []byte(`{"version": 0, "logicalDevices": [{"name": "LD0"}]}`), 0o644))
+	loaded, err = LoadModelCache(path)
+	assert.NoError(t, err)
+	assert.Empty(t, loaded.LogicalDevices)
+}
+
+func TestDiscoverLogicalDeviceCacheHit(t *testing.T) {
+	cache := &ModelCache{Version: ModelCacheVersion, LogicalDevices: []CachedLogicalDevice{
+		{Name: "LD0", Key: "rev1", Variables: []string{"LLN0"}},
+	}}
+	cfg := discoverConfig{keyFunc: func(context.Context, *MmsClient, string) (string, error) {
+		return "rev1", nil
+	}}
+
+	// Клиент без соединения: при совпадении ключа запросы к серверу не выполняются
+	c := &MmsClient{logger: defaultLogger()}
+	entry, err := c.discoverLogicalDevice(context.Background(), cfg, cache, "LD0")
+	assert.NoError(t, err)
+	assert.Equal(t, &cache.LogicalDevices[0], entry)
+
+	// При несовпадении ключа устройство обнаруживается заново
+	cache.LogicalDevices[0].Key = "rev0"
+	_, err = c.discoverLogicalDevice(context.Background(), cfg, cache, "LD0")
+	assert.EqualError(t, err, "failed to get variables of LD0: connection not established, call Initiate first")
+}
+
+func TestGetJournalList(t *testing.T) {
+	f, err := os.Open("mmstest/testdata/read.txt")
+	assert.NoError(t, err)
+	defer f.Close()
+	exchanges, err := mmstest.ParseTranscript(f)
+	assert.NoError(t, err)
+
+	server := mmstest.NewTranscriptServer(t,
+		exchanges[0], exchanges[1],
+		// getNameList: objectClass journal, domainSpecific "LD"
+		mmstest.Exchange{
+			Request: "03 00 00 26 02 f0 80 01 00 01 00 61 19 30 17 02 01 03 a0 12 a0 10 02 01 xx a1 0b a0 03 80 01 08 a1 04 81 02 4c 44",
+			Responses: []string{"03 00 00 2f 02 f0 80 01 00 01 00 61 22 30 20 02 01 03 a0 1b a1 19 02 01 01 a1 14 " +
+				"a0 0f 1a 0d 4c 4c 4e 30 24 45 76 65 6e 74 4c 6f 67 81 01 00"},
+		},
+	)
+	conn := server.Dial()
+	ctx := context.Background()
+	client, err := NewMmsClient(ctx, conn)
+	assert.NoError(t, err)
+	_, err = client.Initiate(ctx)
+	assert.NoError(t, err)
+
+	journals, err := client.GetJournalList(ctx, "LD")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"LLN0$EventLog"}, journals)
+
+	conn.Close()
+	assert.NoError(t, server.Wait())
+}
+
+func TestNames(t *testing.T) {
+	page := func(continueAfter string, response *mms.GetNameListResponse) mmstest.Exchange {
+		request := mms.NewGetNameListRequest(mms.ObjectClassNamedVariable, "LD0")
+		request.ContinueAfter = continueAfter
+		response.InvokeID = 1
+		return mmstest.Exchange{Request: mmsRequest(request.Bytes()), Responses: []string{mmsFrame(response.Bytes())}}
+	}
+	exchanges := append(fileTranscript(t),
+		page("", &mms.GetNameListResponse{Identifiers: []string{"A", "B"}, MoreFollows: true}),
+		page("B", &mms.GetNameListResponse{Identifiers: []string{"C"}}),
+		// Сервер игнорирует continueAfter и повторяет страницу
+		page("", &mms.GetNameListResponse{Identifiers: []string{"A", "B"}, MoreFollows: true}),
+		page("B", &mms.GetNameListResponse{Identifiers: []string{"A", "B"}, MoreFollows: true}),
+		// Пустая страница с moreFollows
+		page("", &mms.GetNameListResponse{MoreFollows: true}),
+	)
+	server := mmstest.NewTranscriptServer(t, exchanges...)
+	conn := server.Dial()
+	ctx := context.Background()
+	client, err := NewMmsClient(ctx, conn)
+	assert.NoError(t, err)
+	_, err = client.Initiate(ctx)
+	assert.NoError(t, err)
+
+	names, err := client.getAllNames(ctx, mms.ObjectClassNamedVariable, "LD0")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"A", "B", "C"}, names)
+	_, err = client.getAllNames(ctx, mms.ObjectClassNamedVariable, "LD0")
+	assert.EqualError(t, err, `GetNameList: response does not advance past continueAfter "B"`)
+	_, err = client.getAllNames(ctx, mms.ObjectClassNamedVariable, "LD0")
+	assert.EqualError(t, err, "GetNameList: empty response with moreFollows")
+
+	conn.Close()
+	assert.NoError(t, server.Wait())
+}
+
+func TestNamePlateKey(t *testing.T) {
+	read := func(itemID string, result mms.AccessResult) mmstest.Exchange {
+		request := &mms.ReadRequest{DomainID: "LD0", ItemID: itemID}
+		response, err := (&mms.ReadResponse{InvokeID: 1, ListOfAccessResult: []mms.AccessResult{result}}).Bytes()
+		assert.NoError(t, err)
+		return mmstest.Exchange{Request: mmsRequest(request.Bytes()), Responses: []string{mmsFrame(response)}}
+	}
+	missing := mms.AccessResult{Error: &mms.DataAccessError{ErrorCode: mms.ObjectNonExistent}}
+	exchanges := append(fileTranscript(t),
+		read("LLN0$ST$NamPlt$paramRev", mms.AccessResult{Success: true, Value: variant.NewInt32Variant(3)}),
+		// Устройство редакции 1: paramRev и valRev отсутствуют
+		read("LLN0$ST$NamPlt$paramRev", missing),
+		read("LLN0$ST$NamPlt$valRev", missing),
+		read("LLN0$DC$NamPlt$configRev", mms.AccessResult{Success: true, Value: variant.NewVisibleStringVariant("rev7")}),
+		read("LLN0$ST$NamPlt$paramRev", missing),
+		read("LLN0$ST$NamPlt$valRev", missing),
+		read("LLN0$DC$NamPlt$configRev", missing),
+	)
+	server := mmstest.NewTranscriptServer(t, exchanges...)
+	conn := server.Dial()
+	ctx := context.Background()
+	client, err := NewMmsClient(ctx, conn)
+	assert.NoError(t, err)
+	_, err = client.Initiate(ctx)
+	assert.NoError(t, err)
+
+	key, err := NamePlateKey(ctx, client, "LD0")
+	assert.NoError(t, err)
+	assert.Equal(t, "int32(3)", key)
+	key, err = NamePlateKey(ctx, client, "LD0")
+	assert.NoError(t, err)
+	assert.Equal(t, `LLN0$DC$NamPlt$configRev=visible-string("rev7")`, key)
+	_, err = NamePlateKey(ctx, client, "LD0")
+	assert.ErrorIs(t, err, &mms.DataAccessError{ErrorCode: mms.ObjectNonExistent})
+
+	conn.Close()
+	assert.NoError(t, server.Wait())
+}
+
+func TestGetDomainAttributes(t *testing.T) {
+	f, err := os.Open("mmstest/testdata/read.txt")
+	assert.NoError(t, err)
+	defer f.Close()
+	exchanges, err := mmstest.ParseTranscript(f)
+	assert.NoError(t, err)
+
+	// Сервер из транскрипта не заявляет getDomainAttributes: запрос не отправляется
+	server := mmstest.NewTranscriptServer(t, exchanges[0], exchanges[1])
+	conn := server.Dial()
+	ctx := context.Background()
+	client, err := NewMmsClient(ctx, conn)
+	assert.NoError(t, err)
+	_, err = client.Initiate(ctx)
+	assert.NoError(t, err)
+	assert.False(t, client.Capabilities().SupportsService(mms.GetDomainAttributes))
+	_, err = client.GetDomainAttributes(ctx, "LD")
+	assert.ErrorIs(t, err, mms.ErrServiceNotSupported)
+	conn.Close()
+	assert.NoError(t, server.Wait())
+
+	// Initiate Response с битом getDomainAttributes в servicesSupportedCalled
+	initiate := exchanges[1]
+	initiate.Responses = []string{strings.Replace(initiate.Responses[0], "03 ee 1c 00 00 00 02", "03 ee 1c 00 00 04 02", 1)}
+	server = mmstest.NewTranscriptServer(t,
+		exchanges[0], initiate,
+		// getDomainAttributes [37] "LD"
+		mmstest.Exchange{
+			Request: "03 00 00 1e 02 f0 80 01 00 01 00 61 11 30 0f 02 01 03 a0 0a a0 08 02 01 xx 9f 25 02 4c 44",
+			Responses: []string{"03 00 00 2c 02 f0 80 01 00 01 00 61 1f 30 1d 02 01 03 a0 18 a1 16 02 01 01 bf 25 10 " +
+				"a0 00 81 01 02 82 01 00 83 01 ff a4 00 85 01 00"},
+		},
+	)
+	conn = server.Dial()
+	client, err = NewMmsClient(ctx, conn)
+	assert.NoError(t, err)
+	_, err = client.Initiate(ctx)
+	assert.NoError(t, err)
+
+	attributes, err := client.GetDomainAttributes(ctx, "LD")
+	assert.NoError(t, err)
+	assert.Equal(t, mms.DomainStateReady, attributes.State)
+	assert.True(t, attributes.Sharable)
+	assert.Empty(t, attributes.Capabilities)
+
+	conn.Close()
+	assert.NoError(t, server.Wait())
+}
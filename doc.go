@@ -0,0 +1,34 @@
+// Package go61850 - клиент IEC 61850 (MMS) на чистом Go.
+//
+// # Публичный API
+//
+// Поддерживаемая поверхность API, на изменения которой распространяется
+// проверка совместимости (make apidiff):
+//
+//   - go61850 - MmsClient: установка ассоциации, чтение, получение типов;
+//   - server - обработка MMS запросов по модели данных и приём соединений (Serve);
+//   - model - модель данных IEC 61850 и типы стандарта (перечисления, EntryID);
+//   - osi/mms и osi/mms/variant - MMS PDU клиента и сервера, значения и транспорт сервера (mms.Server);
+//   - scl - разбор файлов SCL (SCD, ICD, CID);
+//   - goose - проверка и кодирование данных наборов для публикации GOOSE;
+//   - sv - кодирование и публикация потоков Sampled Values (9-2LE).
+//
+// Точка отсчёта проверки хранится в api/ и обновляется make apidiff-update
+// при намеренном изменении API.
+//
+// Команда cmd/sclgen генерирует из SCD файла Go константы ссылок на точки модели,
+// cmd/pics выводит матрицу соответствия (PICS) клиента или сервера в JSON или CSV.
+// Пакет mmstest воспроизводит транскрипты обмена с MMS сервером и внедряет
+// ошибки транспорта в тестах клиента, пакет mmstest/vnet соединяет сервер
+// и клиента в одном процессе для поведенческих тестов и примеров.
+// Пакет compat сохраняет устаревшие функции сборки запроса ассоциации
+// (BuildInitiateRequestPDU, BuildAARQ, BuildCPType, BuildConnectSPDU).
+//
+// Пакеты osi/cotp, osi/session, osi/presentation и osi/acse - низкоуровневые
+// реализации уровней OSI. Они открыты для исследования протокола и примеров,
+// но не входят в стабильный API: их API может меняться без сохранения
+// совместимости и не проверяется make apidiff.
+//
+// Кодирование BER находится в internal/ber и недоступно вне модуля;
+// internal/cmd/asn1gen генерирует по модулю ASN.1 кодеки PDU MMS.
+package go61850
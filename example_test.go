@@ -0,0 +1,176 @@
+package go61850_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/slonegd/go61850"
+	"github.com/slonegd/go61850/mmstest/vnet"
+	"github.com/slonegd/go61850/model"
+	"github.com/slonegd/go61850/osi/mms"
+	"github.com/slonegd/go61850/osi/mms/variant"
+	"github.com/slonegd/go61850/server"
+)
+
+// nopLogger отключает отладочный вывод в примерах
+type nopLogger struct{}
+
+func (nopLogger) Debug(string, ...any) {}
+
+// exampleModel создаёт модель simpleIOGenericIO из libIEC61850 с одним аналоговым входом
+// и его зоной нечувствительности db (FC CF)
+func exampleModel() *model.Model {
+	return &model.Model{
+		Name: "simpleIO",
+		LogicalDevices: []*model.LogicalDevice{{
+			Name: "simpleIOGenericIO",
+			LogicalNodes: []*model.LogicalNode{{
+				Name: "GGIO1",
+				DataObjects: []*model.DataObject{{
+					Name: "AnIn1",
+					Children: []model.DataNode{
+						&model.DataAttribute{Name: "mag", FC: mms.FCMX, Attributes: []*model.DataAttribute{
+							{Name: "f", FC: mms.FCMX, Value: variant.NewFloat32Variant(1.5)},
+						}},
+						&model.DataAttribute{Name: "db", FC: mms.FCCF, Value: variant.NewUint32Variant(0)},
+					},
+				}},
+			}},
+		}},
+	}
+}
+
+// dialExample подключает клиента к серверу модели exampleModel в том же процессе
+// (mmstest/vnet). С реальным IED клиент создаётся go61850.Dial.
+func dialExample(ctx context.Context) (*vnet.Network, error) {
+	return vnet.Dial(ctx, exampleModel(),
+		vnet.WithServerOptions(server.WithLogger(nopLogger{})),
+		vnet.WithClientOptions(go61850.WithLogger(nopLogger{})),
+	)
+}
+
+// Чтение атрибута по ссылке IEC 61850 с функциональным ограничением
+func ExampleMmsClient_ReadObject() {
+	ctx := context.Background()
+	network, err := dialExample(ctx)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer network.Close()
+	client := network.Client()
+
+	request, err := client.NewReadRequest("simpleIOGenericIO/GGIO1.AnIn1.mag.f", mms.FCMX)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	result, err := client.ReadObject(ctx, request)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	if !result.Success {
+		fmt.Println(result.Error)
+		return
+	}
+	fmt.Println(request.ItemID, result.Value.Float32())
+	// Output: GGIO1$MX$AnIn1$mag$f 1.5
+}
+
+// Чтение значения атрибута; отказ сервера возвращается как *mms.DataAccessError
+func ExampleMmsClient_Read() {
+	ctx := context.Background()
+	network, err := dialExample(ctx)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer network.Close()
+	client := network.Client()
+
+	value, err := client.Read(ctx, "simpleIOGenericIO/GGIO1.AnIn1.mag.f", mms.FCMX)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(value)
+
+	_, err = client.Read(ctx, "simpleIOGenericIO/GGIO1.AnIn2.mag.f", mms.FCMX)
+	fmt.Println(errors.Is(err, &mms.DataAccessError{ErrorCode: mms.ObjectNonExistent}))
+	// Output:
+	// float32(1.5)
+	// true
+}
+
+// Запись значения атрибута; значения процесса (ST, MX) сервер записывать не даёт
+func ExampleMmsClient_Write() {
+	ctx := context.Background()
+	network, err := dialExample(ctx)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer network.Close()
+	client := network.Client()
+
+	if err := client.Write(ctx, "simpleIOGenericIO/GGIO1.AnIn1.db", mms.FCCF, variant.NewUint32Variant(500)); err != nil {
+		fmt.Println(err)
+		return
+	}
+	value, err := client.Read(ctx, "simpleIOGenericIO/GGIO1.AnIn1.db", mms.FCCF)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(value)
+
+	err = client.Write(ctx, "simpleIOGenericIO/GGIO1.AnIn1.mag.f", mms.FCMX, variant.NewFloat32Variant(2))
+	fmt.Println(err)
+	// Output:
+	// uint32(500)
+	// write simpleIOGenericIO/GGIO1.AnIn1.mag.f: data access error: object-access-denied
+}
+
+// Ссылка без домена относится к домену WithDefaultDomain
+func ExampleWithDefaultDomain() {
+	ctx := context.Background()
+	network, err := vnet.Dial(ctx, exampleModel(),
+		vnet.WithServerOptions(server.WithLogger(nopLogger{})),
+		vnet.WithClientOptions(go61850.WithLogger(nopLogger{}), go61850.WithDefaultDomain("simpleIOGenericIO")),
+	)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer network.Close()
+	client := network.Client()
+
+	request, err := client.NewReadRequest("GGIO1.AnIn1.mag.f", mms.FCMX)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(request.DomainID, request.ItemID)
+	// Output: simpleIOGenericIO GGIO1$MX$AnIn1$mag$f
+}
+
+// Получение списка логических устройств (доменов MMS) сервера
+func ExampleMmsClient_GetNameList() {
+	ctx := context.Background()
+	network, err := dialExample(ctx)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer network.Close()
+
+	response, err := network.Client().GetNameList(ctx, mms.NewGetNameListRequest(mms.ObjectClassDomain, ""))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(response.Identifiers)
+	// Output: [simpleIOGenericIO]
+}
@@ -0,0 +1,63 @@
+package gateway_test
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/slonegd/go61850"
+	"github.com/slonegd/go61850/examples/gateway"
+	"github.com/slonegd/go61850/mmstest/vnet"
+	"github.com/slonegd/go61850/model"
+	"github.com/slonegd/go61850/osi/mms"
+	"github.com/slonegd/go61850/osi/mms/variant"
+	"github.com/slonegd/go61850/server"
+)
+
+// nopLogger отключает отладочный вывод в примерах
+type nopLogger struct{}
+
+func (nopLogger) Debug(string, ...any) {}
+
+// Опрос IED и публикация значения в регистры Modbus. Вместо IED в примере
+// сервер модели simpleIOGenericIO в том же процессе (mmstest/vnet);
+// с реальным устройством клиент создаётся go61850.Dial.
+func ExampleGateway_Poll() {
+	m := &model.Model{
+		Name: "simpleIO",
+		LogicalDevices: []*model.LogicalDevice{{
+			Name: "simpleIOGenericIO",
+			LogicalNodes: []*model.LogicalNode{{
+				Name: "GGIO1",
+				DataObjects: []*model.DataObject{{
+					Name: "AnIn1",
+					Children: []model.DataNode{
+						&model.DataAttribute{Name: "mag", FC: mms.FCMX, Attributes: []*model.DataAttribute{
+							{Name: "f", FC: mms.FCMX, Value: variant.NewFloat32Variant(1.5)},
+						}},
+					},
+				}},
+			}},
+		}},
+	}
+	ctx := context.Background()
+	network, err := vnet.Dial(ctx, m,
+		vnet.WithServerOptions(server.WithLogger(nopLogger{})),
+		vnet.WithClientOptions(go61850.WithLogger(nopLogger{})),
+	)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer network.Close()
+
+	registers := gateway.NewRegisterMap()
+	g := gateway.New(network.Client(), registers, []gateway.Point{
+		{Reference: "simpleIOGenericIO/GGIO1.AnIn1.mag.f", FC: mms.FCMX, Address: 100},
+	}, gateway.WithLogger(nopLogger{}))
+	if err := g.Poll(ctx); err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Printf("%04x\n", registers.Registers(100, 2))
+	// Output: [3fc0 0000]
+}
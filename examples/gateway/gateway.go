@@ -0,0 +1,155 @@
+// Package gateway - пример шлюза IEC 61850 -> Modbus/IEC 60870-5-104.
+//
+// Шлюз опрашивает точки IED через MmsClient (или принимает значения из отчётов)
+// и публикует их в Sink, предоставленный пользователем. Sink отвечает
+// за протокол назначения: запись регистров Modbus, формирование ASDU МЭК 104 и т.п.
+// Пакет служит каркасом: таблицу точек, Sink и периодичность опроса
+// приложение задаёт под свою задачу.
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/slonegd/go61850/logger"
+	"github.com/slonegd/go61850/osi/mms"
+	"github.com/slonegd/go61850/osi/mms/variant"
+)
+
+// Point описывает отображение атрибута IEC 61850 на адрес протокола назначения
+type Point struct {
+	// Reference - ссылка на атрибут, например "simpleIOGenericIO/GGIO1.AnIn1.mag.f"
+	Reference string
+	// FC - функциональное ограничение атрибута
+	FC mms.FunctionalConstraint
+	// Address - адрес в протоколе назначения: номер регистра Modbus или IOA МЭК 104
+	Address uint32
+}
+
+// Sink принимает значения точек и передаёт их в протокол назначения
+type Sink interface {
+	Publish(ctx context.Context, point Point, value *variant.Variant, timestamp time.Time) error
+}
+
+// Reader читает значения с IED; реализуется go61850.MmsClient
+type Reader interface {
+	ReadObject(ctx context.Context, readRequest *mms.ReadRequest) (mms.AccessResult, error)
+}
+
+// Gateway опрашивает точки и публикует изменившиеся значения в Sink.
+// Run (Poll) и OnValue можно вызывать из разных горутин: публикации
+// выполняются по одной, Sink не обязан быть безопасным для конкурентного вызова.
+type Gateway struct {
+	reader   Reader
+	sink     Sink
+	points   []Point
+	interval time.Duration
+	logger   logger.Logger
+	// mu упорядочивает публикации и защищает last
+	mu sync.Mutex
+	// last - последние опубликованные значения по индексу точки (для отсечки неизменившихся)
+	last []*variant.Variant
+	now  func() time.Time
+}
+
+// Option представляет опцию для настройки Gateway
+type Option func(*Gateway)
+
+// WithInterval задаёт период опроса для Run (по умолчанию 1 с)
+func WithInterval(d time.Duration) Option {
+	return func(g *Gateway) {
+		g.interval = d
+	}
+}
+
+// WithLogger устанавливает логгер для Gateway
+func WithLogger(l logger.Logger) Option {
+	return func(g *Gateway) {
+		g.logger = l
+	}
+}
+
+// New создаёт шлюз для таблицы точек points
+func New(reader Reader, sink Sink, points []Point, opts ...Option) *Gateway {
+	g := &Gateway{
+		reader:   reader,
+		sink:     sink,
+		points:   points,
+		interval: time.Second,
+		logger:   logger.NewLogger("gateway"),
+		last:     make([]*variant.Variant, len(points)),
+		now:      time.Now,
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+	return g
+}
+
+// Run опрашивает точки с периодом WithInterval до отмены ctx.
+// Ошибки отдельных точек логируются и не прерывают опрос.
+func (g *Gateway) Run(ctx context.Context) error {
+	ticker := time.NewTicker(g.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := g.Poll(ctx); err != nil {
+			g.logger.Debug("poll: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll выполняет один цикл опроса всех точек.
+// Возвращает первую ошибку, при этом остальные точки всё равно опрашиваются.
+func (g *Gateway) Poll(ctx context.Context) error {
+	var firstErr error
+	for i, point := range g.points {
+		var result mms.AccessResult
+		request, err := mms.NewReadRequest(point.Reference, point.FC)
+		if err == nil {
+			result, err = g.reader.ReadObject(ctx, request)
+		}
+		if err == nil && !result.Success {
+			err = fmt.Errorf("read %s: %v", point.Reference, result.Error)
+		}
+		if err == nil {
+			err = g.publish(ctx, i, result.Value)
+		}
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// OnValue передаёт в Sink значение, полученное не опросом, а из отчёта
+// (InformationReport). Значения для неотображённых ссылок игнорируются.
+func (g *Gateway) OnValue(ctx context.Context, reference string, fc mms.FunctionalConstraint, value *variant.Variant) error {
+	for i, point := range g.points {
+		if point.Reference == reference && point.FC == fc {
+			return g.publish(ctx, i, value)
+		}
+	}
+	return nil
+}
+
+// publish отправляет значение точки i в Sink, если оно изменилось (variant.Diff)
+func (g *Gateway) publish(ctx context.Context, i int, value *variant.Variant) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.last[i] != nil && len(variant.Diff(g.last[i], value)) == 0 {
+		return nil
+	}
+	if err := g.sink.Publish(ctx, g.points[i], value, g.now()); err != nil {
+		return fmt.Errorf("publish %s: %w", g.points[i].Reference, err)
+	}
+	g.last[i] = value
+	return nil
+}
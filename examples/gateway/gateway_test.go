@@ -0,0 +1,94 @@
+package gateway
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/slonegd/go61850/osi/mms"
+	"github.com/slonegd/go61850/osi/mms/variant"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeReader возвращает значения из таблицы по itemID
+type fakeReader struct {
+	mu     sync.Mutex
+	values map[string]*variant.Variant
+	reads  int
+}
+
+func (r *fakeReader) ReadObject(_ context.Context, request *mms.ReadRequest) (mms.AccessResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reads++
+	value, ok := r.values[request.ItemID]
+	if !ok {
+		return mms.AccessResult{Error: &mms.DataAccessError{ErrorCode: mms.ObjectNonExistent}}, nil
+	}
+	return mms.AccessResult{Success: true, Value: value}, nil
+}
+
+func TestGatewayPoll(t *testing.T) {
+	reader := &fakeReader{values: map[string]*variant.Variant{
+		"GGIO1$MX$AnIn1$mag$f": variant.NewFloat32Variant(1.5),
+		"GGIO1$ST$Ind1$stVal":  variant.NewInt32Variant(-2),
+	}}
+	sink := NewRegisterMap()
+	g := New(reader, sink, []Point{
+		{Reference: "simpleIOGenericIO/GGIO1.AnIn1.mag.f", FC: mms.FCMX, Address: 100},
+		{Reference: "simpleIOGenericIO/GGIO1.Ind1.stVal", FC: mms.FCST, Address: 102},
+	})
+
+	assert.NoError(t, g.Poll(context.Background()))
+	assert.Equal(t, []uint16{0x3fc0, 0x0000, 0xffff, 0xfffe}, sink.Registers(100, 4))
+
+	// Значение из отчёта
+	assert.NoError(t, g.OnValue(context.Background(), "simpleIOGenericIO/GGIO1.AnIn1.mag.f", mms.FCMX, variant.NewFloat32Variant(2)))
+	assert.Equal(t, []uint16{0x4000, 0x0000}, sink.Registers(100, 2))
+
+	// Неотображённая ссылка игнорируется
+	assert.NoError(t, g.OnValue(context.Background(), "simpleIOGenericIO/GGIO1.AnIn2.mag.f", mms.FCMX, variant.NewFloat32Variant(3)))
+}
+
+func TestGatewayPollError(t *testing.T) {
+	reader := &fakeReader{values: map[string]*variant.Variant{
+		"GGIO1$MX$AnIn2$mag$f": variant.NewFloat32Variant(2.5),
+	}}
+	sink := NewRegisterMap()
+	g := New(reader, sink, []Point{
+		{Reference: "simpleIOGenericIO/GGIO1.AnIn1.mag.f", FC: mms.FCMX, Address: 0},
+		{Reference: "simpleIOGenericIO/GGIO1.AnIn2.mag.f", FC: mms.FCMX, Address: 2},
+	})
+
+	// Ошибка первой точки не мешает опросу второй
+	assert.Error(t, g.Poll(context.Background()))
+	assert.Equal(t, 2, reader.reads)
+	assert.Equal(t, []uint16{0, 0, 0x4020, 0}, sink.Registers(0, 4))
+}
+
+// TestGatewayConcurrent проверяется с -race: опрос и значения отчётов
+// публикуются из разных горутин
+func TestGatewayConcurrent(t *testing.T) {
+	reader := &fakeReader{values: map[string]*variant.Variant{
+		"GGIO1$MX$AnIn1$mag$f": variant.NewFloat32Variant(1.5),
+	}}
+	sink := NewRegisterMap()
+	g := New(reader, sink, []Point{{Reference: "simpleIOGenericIO/GGIO1.AnIn1.mag.f", FC: mms.FCMX, Address: 0}})
+
+	ctx := context.Background()
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for range 100 {
+			assert.NoError(t, g.Poll(ctx))
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := range 100 {
+			assert.NoError(t, g.OnValue(ctx, "simpleIOGenericIO/GGIO1.AnIn1.mag.f", mms.FCMX, variant.NewFloat32Variant(float32(i))))
+		}
+	}()
+	wg.Wait()
+}
@@ -0,0 +1,55 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/slonegd/go61850/osi/mms/variant"
+)
+
+// RegisterMap - пример Sink: таблица holding-регистров Modbus в памяти.
+// float32 и int32 занимают два регистра (старшее слово первым),
+// Point.Address - номер первого регистра. Modbus сервер приложения
+// отдаёт значения из таблицы через Registers.
+type RegisterMap struct {
+	mu        sync.Mutex
+	registers map[uint32]uint16
+}
+
+// NewRegisterMap создаёт пустую таблицу регистров
+func NewRegisterMap() *RegisterMap {
+	return &RegisterMap{registers: make(map[uint32]uint16)}
+}
+
+// Publish записывает значение точки в регистры
+func (m *RegisterMap) Publish(_ context.Context, point Point, value *variant.Variant, _ time.Time) error {
+	var raw uint32
+	switch value.Type() {
+	case variant.Float32:
+		raw = math.Float32bits(value.Float32())
+	case variant.Int32:
+		raw = uint32(value.Int32())
+	default:
+		return fmt.Errorf("unsupported value type %s for Modbus register %d", value.Type(), point.Address)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.registers[point.Address] = uint16(raw >> 16)
+	m.registers[point.Address+1] = uint16(raw)
+	return nil
+}
+
+// Registers возвращает count регистров начиная с address (отсутствующие равны 0)
+func (m *RegisterMap) Registers(address uint32, count int) []uint16 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	result := make([]uint16, count)
+	for i := range result {
+		result[i] = m.registers[address+uint32(i)]
+	}
+	return result
+}
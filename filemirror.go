@@ -0,0 +1,134 @@
+package go61850
+
+import (
+	"bytes"
+	"context"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/slonegd/go61850/osi/mms"
+)
+
+// FileStore - локальное хранилище копий файлов сервера (например, server.MemFS)
+type FileStore interface {
+	WriteFile(name string, data []byte) error
+	Remove(name string) error
+}
+
+// FileChange описывает изменение файла сервера, найденное FileMirror
+type FileChange struct {
+	// Name - имя файла в хранилище сервера
+	Name string
+	// Removed - файл удалён на сервере и из локального хранилища
+	Removed bool
+	// Attributes - атрибуты файла на сервере; нулевые для удалённого файла
+	Attributes mms.FileAttributes
+}
+
+// FileMirror поддерживает локальную копию каталога файлового хранилища сервера:
+// новые и изменённые (по размеру или времени изменения) файлы загружаются
+// через GetFile, удалённые - удаляются из store.
+type FileMirror struct {
+	client *MmsClient
+	dir    string
+	store  FileStore
+
+	mu    sync.Mutex
+	known map[string]mms.FileAttributes
+}
+
+// NewFileMirror создаёт зеркало каталога dir сервера (пустая строка - корень).
+// Имена в store совпадают с именами сервера без ведущего "/".
+func NewFileMirror(client *MmsClient, dir string, store FileStore) *FileMirror {
+	return &FileMirror{client: client, dir: dir, store: store, known: map[string]mms.FileAttributes{}}
+}
+
+// Sync сравнивает каталог сервера с известным состоянием и переносит изменения
+// в store. Возвращает изменения в порядке каталога сервера, затем удаления по имени.
+func (m *FileMirror) Sync(ctx context.Context) ([]FileChange, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entries, err := m.client.FileDirectory(ctx, m.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []FileChange
+	seen := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		name := m.name(entry.Name)
+		seen[name] = true
+		known, ok := m.known[name]
+		if ok && known.Size == entry.Size && known.LastModified.Equal(entry.LastModified) {
+			continue
+		}
+		var data bytes.Buffer
+		if _, err := m.client.GetFile(ctx, name, &data); err != nil {
+			return changes, err
+		}
+		if err := m.store.WriteFile(strings.TrimPrefix(name, "/"), data.Bytes()); err != nil {
+			return changes, err
+		}
+		m.known[name] = entry.FileAttributes
+		changes = append(changes, FileChange{Name: name, Attributes: entry.FileAttributes})
+	}
+	var removed []string
+	for name := range m.known {
+		if !seen[name] {
+			removed = append(removed, name)
+		}
+	}
+	sort.Strings(removed)
+	for _, name := range removed {
+		if err := m.store.Remove(strings.TrimPrefix(name, "/")); err != nil {
+			return changes, err
+		}
+		delete(m.known, name)
+		changes = append(changes, FileChange{Name: name, Removed: true})
+	}
+	return changes, nil
+}
+
+// name возвращает полное имя элемента каталога: серверы возвращают как полные
+// имена ("COMTRADE/rec1.cfg"), так и имена относительно каталога ("rec1.cfg")
+func (m *FileMirror) name(entry string) string {
+	dir := strings.TrimSuffix(m.dir, "/")
+	if dir == "" || strings.HasPrefix(entry, dir+"/") {
+		return entry
+	}
+	return path.Join(dir, entry)
+}
+
+// Watch вызывает Sync каждые interval и при каждом сигнале notify, пока ctx не
+// отменён; onChange получает непустой список изменений. По соглашению IEC 61850
+// о появлении файла сообщает сам сервер (например, отчёт с RDRE.RcdMade или
+// изменением FltNum для осциллограмм), поэтому notify удобно связать с отчётами,
+// а interval использовать как редкую страховочную проверку (0 - без опроса).
+// Возвращает ошибку Sync или ctx.Err().
+func (m *FileMirror) Watch(ctx context.Context, interval time.Duration, notify <-chan struct{}, onChange func([]FileChange)) error {
+	var tick <-chan time.Time
+	if interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	for {
+		changes, err := m.Sync(ctx)
+		if err != nil {
+			return err
+		}
+		if len(changes) > 0 && onChange != nil {
+			onChange(changes)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-tick:
+		case <-notify:
+		}
+	}
+}
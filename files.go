@@ -0,0 +1,298 @@
+package go61850
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/slonegd/go61850/osi/mms"
+)
+
+// defaultFileChunk - размер блока FileRead, если размер PDU не согласован
+const defaultFileChunk = 512
+
+// fileReadOverhead - запас на заголовки confirmed-ResponsePDU fileRead
+const fileReadOverhead = 32
+
+// WithFileSource разрешает серверу читать файлы клиента из fsys запросами
+// FileOpen, FileRead и FileClose. Нужен для ObtainFile: сервер забирает файл
+// у клиента, пока клиент ожидает ответ. Имена файлов сервера отображаются
+// в пути fsys без ведущего "/".
+func WithFileSource(fsys fs.FS) MmsClientOption {
+	return func(c *MmsClient) {
+		source := &fileSource{fsys: fsys, client: c, files: map[int32]fs.File{}}
+		WithRequestHandler(mms.ServiceFileOpen, source.open)(c)
+		WithRequestHandler(mms.ServiceFileRead, source.read)(c)
+		WithRequestHandler(mms.ServiceFileClose, source.close)(c)
+	}
+}
+
+// fileSource обслуживает запросы файловых сервисов сервера к файлам клиента
+type fileSource struct {
+	fsys   fs.FS
+	client *MmsClient
+
+	mu    sync.Mutex
+	files map[int32]fs.File
+	next  int32
+}
+
+func (s *fileSource) open(_ context.Context, request *mms.ConfirmedRequest) ([]byte, error) {
+	open, err := mms.ParseFileOpenRequest(request.Argument)
+	if err != nil {
+		return nil, err
+	}
+	file, err := s.fsys.Open(strings.TrimPrefix(open.FileName, "/"))
+	if err != nil {
+		return nil, fileServiceError(err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, fileServiceError(err)
+	}
+	if info.IsDir() {
+		file.Close()
+		return nil, &mms.ServiceError{Class: mms.ErrorClassFile, Code: mms.FileErrorFileAccessDenied}
+	}
+	if open.InitialPosition > 0 {
+		if _, err := io.CopyN(io.Discard, file, int64(open.InitialPosition)); err != nil {
+			file.Close()
+			return nil, &mms.ServiceError{Class: mms.ErrorClassFile, Code: mms.FileErrorPositionInvalid}
+		}
+	}
+
+	s.mu.Lock()
+	s.next++
+	frsmID := s.next
+	s.files[frsmID] = file
+	s.mu.Unlock()
+
+	response := &mms.FileOpenResponse{
+		FrsmID:     frsmID,
+		Attributes: mms.FileAttributes{Size: fileSize(info), LastModified: info.ModTime()},
+	}
+	return response.Bytes(), nil
+}
+
+// fileSize возвращает размер файла для sizeOfFile (Unsigned32): размер
+// файлов от 4 ГиБ ограничивается math.MaxUint32, как у сервера
+func fileSize(info fs.FileInfo) uint32 {
+	if info.Size() >= math.MaxUint32 {
+		return math.MaxUint32
+	}
+	return uint32(info.Size())
+}
+
+func (s *fileSource) read(_ context.Context, request *mms.ConfirmedRequest) ([]byte, error) {
+	file, err := s.file(request.Argument)
+	if err != nil {
+		return nil, err
+	}
+	data := make([]byte, s.chunkSize())
+	n, err := io.ReadFull(file, data)
+	switch {
+	case err == nil:
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return (&mms.FileReadResponse{Data: data[:n]}).Bytes(), nil
+	default:
+		return nil, fileServiceError(err)
+	}
+	return (&mms.FileReadResponse{Data: data, MoreFollows: true}).Bytes(), nil
+}
+
+func (s *fileSource) close(_ context.Context, request *mms.ConfirmedRequest) ([]byte, error) {
+	frsmID, err := mms.ParseFrsmID(request.Argument)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	file := s.files[frsmID]
+	delete(s.files, frsmID)
+	s.mu.Unlock()
+	if file == nil {
+		return nil, &mms.ServiceError{Class: mms.ErrorClassFile, Code: mms.FileErrorOther}
+	}
+	file.Close()
+	return mms.NullResponse(mms.ServiceFileClose), nil
+}
+
+// file возвращает открытый файл по frsmID из аргумента FileRead
+func (s *fileSource) file(argument []byte) (fs.File, error) {
+	frsmID, err := mms.ParseFrsmID(argument)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	file := s.files[frsmID]
+	if file == nil {
+		return nil, &mms.ServiceError{Class: mms.ErrorClassFile, Code: mms.FileErrorOther}
+	}
+	return file, nil
+}
+
+// chunkSize возвращает размер блока FileRead по согласованному размеру PDU
+func (s *fileSource) chunkSize() int {
+	capabilities := s.client.Capabilities()
+	if capabilities == nil || capabilities.MaxPduSize <= 2*fileReadOverhead {
+		return defaultFileChunk
+	}
+	return int(capabilities.MaxPduSize) - fileReadOverhead
+}
+
+// fileServiceError преобразует ошибку fs в ServiceError класса file
+func fileServiceError(err error) error {
+	code := mms.FileErrorOther
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		code = mms.FileErrorFileNonExistent
+	case errors.Is(err, fs.ErrPermission):
+		code = mms.FileErrorFileAccessDenied
+	case errors.Is(err, fs.ErrInvalid):
+		code = mms.FileErrorFilenameSyntaxError
+	}
+	return &mms.ServiceError{Class: mms.ErrorClassFile, Code: code}
+}
+
+// FileDirectory возвращает содержимое каталога name файлового хранилища сервера
+// (пустая строка - корень). Продолжения списка (moreFollows) запрашиваются автоматически.
+func (c *MmsClient) FileDirectory(ctx context.Context, name string) ([]mms.DirectoryEntry, error) {
+	entries := []mms.DirectoryEntry{}
+	continueAfter := ""
+	for {
+		data, err := c.fileRequest(ctx, mms.FileDirectory, name, func(invokeID uint32) []byte {
+			request := &mms.FileDirectoryRequest{InvokeID: invokeID, FileSpecification: name, ContinueAfter: continueAfter}
+			return request.Bytes()
+		})
+		if err != nil {
+			return nil, err
+		}
+		response, err := mms.ParseFileDirectoryResponse(data)
+		if err != nil {
+			return nil, responseError("FileDirectory", err)
+		}
+		entries = append(entries, response.Entries...)
+		if !response.MoreFollows || len(response.Entries) == 0 {
+			return entries, nil
+		}
+		continueAfter = response.Entries[len(response.Entries)-1].Name
+	}
+}
+
+// GetFile читает файл name сервера в w запросами FileOpen, FileRead и FileClose.
+// Файл закрывается и при ошибке чтения.
+func (c *MmsClient) GetFile(ctx context.Context, name string, w io.Writer) (_ mms.FileAttributes, err error) {
+	data, err := c.fileRequest(ctx, mms.FileOpen, name, func(invokeID uint32) []byte {
+		return (&mms.FileOpenRequest{InvokeID: invokeID, FileName: name}).Bytes()
+	})
+	if err != nil {
+		return mms.FileAttributes{}, err
+	}
+	open, err := mms.ParseFileOpenResponse(data)
+	if err != nil {
+		return mms.FileAttributes{}, responseError("FileOpen", err)
+	}
+	defer func() {
+		data, closeErr := c.fileRequest(context.WithoutCancel(ctx), mms.FileClose, name, func(invokeID uint32) []byte {
+			return (&mms.FileCloseRequest{InvokeID: invokeID, FrsmID: open.FrsmID}).Bytes()
+		})
+		if closeErr == nil {
+			closeErr = mms.ParseNullResponse(data, mms.ServiceFileClose)
+		}
+		if err == nil && closeErr != nil {
+			err = fmt.Errorf("failed to close file %q: %w", name, closeErr)
+		}
+	}()
+
+	for {
+		data, err := c.fileRequest(ctx, mms.FileRead, name, func(invokeID uint32) []byte {
+			return (&mms.FileReadRequest{InvokeID: invokeID, FrsmID: open.FrsmID}).Bytes()
+		})
+		if err != nil {
+			return open.Attributes, err
+		}
+		read, err := mms.ParseFileReadResponse(data)
+		if err != nil {
+			return open.Attributes, responseError("FileRead", err)
+		}
+		if _, err := w.Write(read.Data); err != nil {
+			return open.Attributes, err
+		}
+		if !read.MoreFollows {
+			return open.Attributes, nil
+		}
+	}
+}
+
+// ObtainFile просит сервер получить файл клиента sourceFile и сохранить его
+// как destinationFile (например, загрузка конфигурации или прошивки).
+// Сервер читает файл запросами FileOpen, FileRead и FileClose во время
+// ожидания ответа, поэтому клиент должен быть создан с WithFileSource.
+func (c *MmsClient) ObtainFile(ctx context.Context, sourceFile, destinationFile string) (err error) {
+	defer c.audit(AuditObtainFile, destinationFile, nil, time.Now(), &err)
+	if err := c.checkWritable("obtain file " + destinationFile); err != nil {
+		return err
+	}
+	if c.requestHandlers[mms.ServiceFileOpen] == nil {
+		return fmt.Errorf("ObtainFile requires WithFileSource")
+	}
+	data, err := c.fileRequest(ctx, mms.ObtainFile, destinationFile, func(invokeID uint32) []byte {
+		request := &mms.ObtainFileRequest{InvokeID: invokeID, SourceFile: sourceFile, DestinationFile: destinationFile}
+		return request.Bytes()
+	})
+	if err != nil {
+		return err
+	}
+	return mms.ParseNullResponse(data, mms.ServiceObtainFile)
+}
+
+// fileRequest отправляет запрос файлового сервиса, построенный build, и возвращает
+// MMS PDU ответа. Запросы сервера во время ожидания обслуживаются receiveResponse.
+func (c *MmsClient) fileRequest(ctx context.Context, service mms.ServiceSupportedBit, name string, build func(invokeID uint32) []byte) (_ []byte, err error) {
+	finish, err := c.beginRequest(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer finish()
+	start := time.Now()
+	defer func() {
+		c.recordStats(name, start, err != nil)
+	}()
+
+	if c.mmsClient == nil {
+		return nil, fmt.Errorf("connection not established, call Initiate first")
+	}
+	if err := c.checkService(service); err != nil {
+		return nil, err
+	}
+	if name != "" {
+		if err := mms.ValidateFileName(name); err != nil {
+			return nil, err
+		}
+	}
+
+	invokeID, err := c.mmsClient.AllocateInvokeID()
+	if err != nil {
+		return nil, err
+	}
+	defer c.mmsClient.ReleaseInvokeID(invokeID)
+
+	mmsPdu := build(invokeID)
+	c.logger.Debug("MMS %s Request PDU: %x", service, mmsPdu)
+	if err := c.mmsClient.SendMmsPduContext(ctx, mmsPdu); err != nil {
+		return nil, fmt.Errorf("failed to send %s Request: %w", service, err)
+	}
+	mmsData, err := c.receiveConfirmed(ctx, invokeID)
+	if err != nil {
+		return nil, err
+	}
+	c.logger.Debug("MMS %s Response PDU (raw bytes): %x", service, mmsData)
+	return mmsData, nil
+}
@@ -0,0 +1,302 @@
+package go61850
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/slonegd/go61850/mmstest"
+	"github.com/slonegd/go61850/osi/mms"
+	"github.com/stretchr/testify/assert"
+)
+
+// mmsFrame оборачивает MMS PDU в TPKT, COTP DT, Session и Presentation (короткие длины)
+func mmsFrame(pdu []byte) string {
+	presentation := append([]byte{0x02, 0x01, 0x03, 0xa0, byte(len(pdu))}, pdu...)
+	presentation = append([]byte{0x61, byte(len(presentation) + 2), 0x30, byte(len(presentation))}, presentation...)
+	packet := append([]byte{0x02, 0xf0, 0x80, 0x01, 0x00, 0x01, 0x00}, presentation...)
+	return fmt.Sprintf("%x", append([]byte{0x03, 0x00, 0x00, byte(len(packet) + 4)}, packet...))
+}
+
+// mmsRequest возвращает шаблон кадра запроса клиента с любым invokeID
+func mmsRequest(pdu []byte) string {
+	frame := mmsFrame(pdu)
+	return frame[:48] + "xx" + frame[50:]
+}
+
+// confirmedResponse кодирует confirmed-ResponsePDU с ответом сервиса service
+func confirmedResponse(invokeID byte, service []byte) []byte {
+	content := append([]byte{0x02, 0x01, invokeID}, service...)
+	if len(content) > 0x7f {
+		return append([]byte{0xa1, 0x82, byte(len(content) >> 8), byte(len(content))}, content...)
+	}
+	return append([]byte{0xa1, byte(len(content))}, content...)
+}
+
+// fileTranscript возвращает обмены установления ассоциации из mmstest/testdata/read.txt
+func fileTranscript(t *testing.T) []mmstest.Exchange {
+	f, err := os.Open("mmstest/testdata/read.txt")
+	assert.NoError(t, err)
+	defer f.Close()
+	exchanges, err := mmstest.ParseTranscript(f)
+	assert.NoError(t, err)
+	return exchanges[:2]
+}
+
+func TestObtainFile(t *testing.T) {
+	modified := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	fsys := fstest.MapFS{"cfg/new.cid": {Data: []byte("<SCL/>"), ModTime: modified}}
+
+	exchanges := append(fileTranscript(t),
+		// ObtainFile; до ответа сервер читает файл клиента
+		mmstest.Exchange{
+			Request:   mmsRequest((&mms.ObtainFileRequest{InvokeID: 1, SourceFile: "cfg/new.cid", DestinationFile: "new.cid"}).Bytes()),
+			Responses: []string{mmsFrame((&mms.FileOpenRequest{InvokeID: 9, FileName: "cfg/new.cid"}).Bytes())},
+		},
+		mmstest.Exchange{
+			Request: mmsFrame(confirmedResponse(9, (&mms.FileOpenResponse{
+				FrsmID:     1,
+				Attributes: mms.FileAttributes{Size: 6, LastModified: modified},
+			}).Bytes())),
+			Responses: []string{mmsFrame((&mms.FileReadRequest{InvokeID: 10, FrsmID: 1}).Bytes())},
+		},
+		mmstest.Exchange{
+			Request:   mmsFrame(confirmedResponse(10, (&mms.FileReadResponse{Data: []byte("<SCL/>")}).Bytes())),
+			Responses: []string{mmsFrame((&mms.FileCloseRequest{InvokeID: 11, FrsmID: 1}).Bytes())},
+		},
+		mmstest.Exchange{
+			Request:   mmsFrame(confirmedResponse(11, mms.NullResponse(mms.ServiceFileClose))),
+			Responses: []string{mmsFrame(confirmedResponse(1, mms.NullResponse(mms.ServiceObtainFile)))},
+		},
+		// Второй ObtainFile: сервер запрашивает отсутствующий файл и сообщает об ошибке
+		mmstest.Exchange{
+			Request:   mmsRequest((&mms.ObtainFileRequest{InvokeID: 1, SourceFile: "missing", DestinationFile: "new.cid"}).Bytes()),
+			Responses: []string{mmsFrame((&mms.FileOpenRequest{InvokeID: 12, FileName: "missing"}).Bytes())},
+		},
+		mmstest.Exchange{
+			Request: mmsFrame((&mms.ConfirmedError{
+				InvokeID:     12,
+				ServiceError: mms.ServiceError{Class: mms.ErrorClassFile, Code: mms.FileErrorFileNonExistent},
+			}).Bytes()),
+			Responses: []string{mmsFrame((&mms.ConfirmedError{
+				InvokeID:     1,
+				ServiceError: mms.ServiceError{Class: mms.ErrorClassFile, Code: mms.FileErrorFileNonExistent},
+			}).Bytes())},
+		},
+	)
+	server := mmstest.NewTranscriptServer(t, exchanges...)
+	conn := server.Dial()
+	ctx := context.Background()
+
+	bare := &MmsClient{logger: defaultLogger(), requestHandlers: defaultRequestHandlers()}
+	assert.ErrorContains(t, bare.ObtainFile(ctx, "cfg/new.cid", "new.cid"), "WithFileSource")
+
+	client, err := NewMmsClient(ctx, conn, WithFileSource(fsys))
+	assert.NoError(t, err)
+	_, err = client.Initiate(ctx)
+	assert.NoError(t, err)
+	assert.NoError(t, client.ObtainFile(ctx, "cfg/new.cid", "new.cid"))
+
+	err = client.ObtainFile(ctx, "missing", "new.cid")
+	var serviceError *mms.ServiceError
+	assert.True(t, errors.As(err, &serviceError))
+	assert.Equal(t, mms.FileErrorFileNonExistent, serviceError.Code)
+
+	conn.Close()
+	assert.NoError(t, server.Wait())
+}
+
+func TestFileMirror(t *testing.T) {
+	first := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	second := first.Add(time.Minute)
+	directory := func(entries ...mms.DirectoryEntry) mmstest.Exchange {
+		return mmstest.Exchange{
+			Request:   mmsRequest((&mms.FileDirectoryRequest{InvokeID: 1, FileSpecification: "COMTRADE"}).Bytes()),
+			Responses: []string{mmsFrame(confirmedResponse(1, (&mms.FileDirectoryResponse{Entries: entries}).Bytes()))},
+		}
+	}
+	download := func(name, data string) []mmstest.Exchange {
+		return []mmstest.Exchange{
+			{
+				Request: mmsRequest((&mms.FileOpenRequest{InvokeID: 1, FileName: name}).Bytes()),
+				Responses: []string{mmsFrame(confirmedResponse(1, (&mms.FileOpenResponse{
+					FrsmID:     5,
+					Attributes: mms.FileAttributes{Size: uint32(len(data))},
+				}).Bytes()))},
+			},
+			{
+				Request:   mmsRequest((&mms.FileReadRequest{InvokeID: 1, FrsmID: 5}).Bytes()),
+				Responses: []string{mmsFrame(confirmedResponse(1, (&mms.FileReadResponse{Data: []byte(data)}).Bytes()))},
+			},
+			{
+				Request:   mmsRequest((&mms.FileCloseRequest{InvokeID: 1, FrsmID: 5}).Bytes()),
+				Responses: []string{mmsFrame(confirmedResponse(1, mms.NullResponse(mms.ServiceFileClose)))},
+			},
+		}
+	}
+
+	exchanges := fileTranscript(t)
+	// Первая синхронизация: два новых файла
+	exchanges = append(exchanges, directory(
+		mms.DirectoryEntry{Name: "COMTRADE/rec1.cfg", FileAttributes: mms.FileAttributes{Size: 3, LastModified: first}},
+		mms.DirectoryEntry{Name: "rec1.dat", FileAttributes: mms.FileAttributes{Size: 2, LastModified: first}},
+	))
+	exchanges = append(exchanges, download("COMTRADE/rec1.cfg", "cfg")...)
+	exchanges = append(exchanges, download("COMTRADE/rec1.dat", "da")...)
+	// Вторая: rec1.cfg изменён, rec1.dat удалён
+	exchanges = append(exchanges, directory(
+		mms.DirectoryEntry{Name: "COMTRADE/rec1.cfg", FileAttributes: mms.FileAttributes{Size: 4, LastModified: second}},
+	))
+	exchanges = append(exchanges, download("COMTRADE/rec1.cfg", "cfg2")...)
+	// Третья: изменений нет
+	exchanges = append(exchanges, directory(
+		mms.DirectoryEntry{Name: "COMTRADE/rec1.cfg", FileAttributes: mms.FileAttributes{Size: 4, LastModified: second}},
+	))
+
+	server := mmstest.NewTranscriptServer(t, exchanges...)
+	conn := server.Dial()
+	ctx := context.Background()
+	client, err := NewMmsClient(ctx, conn)
+	assert.NoError(t, err)
+	_, err = client.Initiate(ctx)
+	assert.NoError(t, err)
+
+	store := &memStore{files: map[string][]byte{}}
+	mirror := NewFileMirror(client, "COMTRADE", store)
+
+	changes, err := mirror.Sync(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []FileChange{
+		{Name: "COMTRADE/rec1.cfg", Attributes: mms.FileAttributes{Size: 3, LastModified: first}},
+		{Name: "COMTRADE/rec1.dat", Attributes: mms.FileAttributes{Size: 2, LastModified: first}},
+	}, changes)
+	assert.Equal(t, map[string][]byte{"COMTRADE/rec1.cfg": []byte("cfg"), "COMTRADE/rec1.dat": []byte("da")}, store.files)
+
+	changes, err = mirror.Sync(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []FileChange{
+		{Name: "COMTRADE/rec1.cfg", Attributes: mms.FileAttributes{Size: 4, LastModified: second}},
+		{Name: "COMTRADE/rec1.dat", Removed: true},
+	}, changes)
+	assert.Equal(t, map[string][]byte{"COMTRADE/rec1.cfg": []byte("cfg2")}, store.files)
+
+	changes, err = mirror.Sync(ctx)
+	assert.NoError(t, err)
+	assert.Empty(t, changes)
+
+	conn.Close()
+	assert.NoError(t, server.Wait())
+}
+
+func TestGetFileCloseOnError(t *testing.T) {
+	exchanges := append(fileTranscript(t),
+		mmstest.Exchange{
+			Request:   mmsRequest((&mms.FileOpenRequest{InvokeID: 1, FileName: "log.txt"}).Bytes()),
+			Responses: []string{mmsFrame(confirmedResponse(1, (&mms.FileOpenResponse{FrsmID: 2}).Bytes()))},
+		},
+		mmstest.Exchange{
+			Request:   mmsRequest((&mms.FileReadRequest{InvokeID: 1, FrsmID: 2}).Bytes()),
+			Responses: []string{mmsFrame(confirmedResponse(1, (&mms.FileReadResponse{Data: []byte("abc"), MoreFollows: true}).Bytes()))},
+		},
+		mmstest.Exchange{
+			Request: mmsRequest((&mms.FileReadRequest{InvokeID: 1, FrsmID: 2}).Bytes()),
+			Responses: []string{mmsFrame((&mms.ConfirmedError{
+				InvokeID:     1,
+				ServiceError: mms.ServiceError{Class: mms.ErrorClassFile, Code: mms.FileErrorFileBusy},
+			}).Bytes())},
+		},
+		mmstest.Exchange{
+			Request:   mmsRequest((&mms.FileCloseRequest{InvokeID: 1, FrsmID: 2}).Bytes()),
+			Responses: []string{mmsFrame(confirmedResponse(1, mms.NullResponse(mms.ServiceFileClose)))},
+		},
+	)
+	server := mmstest.NewTranscriptServer(t, exchanges...)
+	conn := server.Dial()
+	ctx := context.Background()
+	client, err := NewMmsClient(ctx, conn)
+	assert.NoError(t, err)
+	_, err = client.Initiate(ctx)
+	assert.NoError(t, err)
+
+	var data bytes.Buffer
+	_, err = client.GetFile(ctx, "log.txt", &data)
+	var serviceError *mms.ServiceError
+	assert.True(t, errors.As(err, &serviceError))
+	assert.Equal(t, mms.FileErrorFileBusy, serviceError.Code)
+	assert.Equal(t, "abc", data.String())
+
+	conn.Close()
+	assert.NoError(t, server.Wait())
+}
+
+func TestFileSource(t *testing.T) {
+	fsys := fstest.MapFS{"a.bin": {Data: bytes.Repeat([]byte{1}, defaultFileChunk+1)}}
+	client := &MmsClient{logger: defaultLogger()}
+	WithFileSource(fsys)(client)
+	ctx := context.Background()
+	handle := func(service mms.ConfirmedService, pdu []byte) ([]byte, error) {
+		request, err := mms.ParseConfirmedRequest(pdu)
+		assert.NoError(t, err)
+		return client.requestHandlers[service](ctx, request)
+	}
+
+	answer, err := handle(mms.ServiceFileOpen, (&mms.FileOpenRequest{InvokeID: 1, FileName: "/a.bin"}).Bytes())
+	assert.NoError(t, err)
+	open, err := mms.ParseFileOpenResponse(confirmedResponse(1, answer))
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(defaultFileChunk+1), open.Attributes.Size)
+
+	// Без согласованных параметров файл читается блоками defaultFileChunk
+	answer, err = handle(mms.ServiceFileRead, (&mms.FileReadRequest{InvokeID: 2, FrsmID: open.FrsmID}).Bytes())
+	assert.NoError(t, err)
+	read, err := mms.ParseFileReadResponse(confirmedResponse(2, answer))
+	assert.NoError(t, err)
+	assert.Len(t, read.Data, defaultFileChunk)
+	assert.True(t, read.MoreFollows)
+
+	answer, err = handle(mms.ServiceFileRead, (&mms.FileReadRequest{InvokeID: 3, FrsmID: open.FrsmID}).Bytes())
+	assert.NoError(t, err)
+	read, err = mms.ParseFileReadResponse(confirmedResponse(3, answer))
+	assert.NoError(t, err)
+	assert.Len(t, read.Data, 1)
+	assert.False(t, read.MoreFollows)
+
+	_, err = handle(mms.ServiceFileClose, (&mms.FileCloseRequest{InvokeID: 4, FrsmID: open.FrsmID}).Bytes())
+	assert.NoError(t, err)
+	_, err = handle(mms.ServiceFileRead, (&mms.FileReadRequest{InvokeID: 5, FrsmID: open.FrsmID}).Bytes())
+	assert.Equal(t, &mms.ServiceError{Class: mms.ErrorClassFile, Code: mms.FileErrorOther}, err)
+
+	_, err = handle(mms.ServiceFileOpen, (&mms.FileOpenRequest{InvokeID: 6, FileName: "b.bin"}).Bytes())
+	assert.Equal(t, &mms.ServiceError{Class: mms.ErrorClassFile, Code: mms.FileErrorFileNonExistent}, err)
+}
+
+// bigFileInfo - сведения о файле размером больше 4 ГиБ
+type bigFileInfo struct{ fs.FileInfo }
+
+func (bigFileInfo) Size() int64 { return 5 << 30 }
+
+func TestFileSize(t *testing.T) {
+	assert.Equal(t, uint32(math.MaxUint32), fileSize(bigFileInfo{}))
+}
+
+// memStore - FileStore в памяти для тестов FileMirror
+type memStore struct {
+	files map[string][]byte
+}
+
+func (s *memStore) WriteFile(name string, data []byte) error {
+	s.files[name] = data
+	return nil
+}
+
+func (s *memStore) Remove(name string) error {
+	delete(s.files, name)
+	return nil
+}
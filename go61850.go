@@ -2,22 +2,80 @@ package go61850
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"math"
 	"net"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/slonegd/go61850/logger"
 	"github.com/slonegd/go61850/osi/acse"
 	"github.com/slonegd/go61850/osi/cotp"
 	"github.com/slonegd/go61850/osi/mms"
-	"github.com/slonegd/go61850/osi/presentation"
-	"github.com/slonegd/go61850/osi/session"
+	"github.com/slonegd/go61850/osi/mms/variant"
 )
 
 type MmsClient struct {
-	conn      net.Conn
+	conn      io.ReadWriteCloser
 	cotpConn  *cotp.Connection
 	logger    logger.Logger
 	mmsClient *mms.Client
+	profile   ServerProfile
+	invokeIDs *mms.InvokeIDAllocator
+	stats     *Stats
+	// reports - очередь незапрошенных InformationReport (см. reportqueue.go)
+	reports *ReportQueue
+	// reportHandler - обработчик незапрошенных InformationReport (см. listen.go)
+	reportHandler func(*mms.InformationReportPDU)
+	// origin и testMode подставляются в структуры управления (см. control.go)
+	origin   ControlOrigin
+	testMode bool
+	ctlNum   atomic.Uint32
+	// selections - выбранные объекты управления и sboTimeout (см. sbo.go)
+	selections selectionTracker
+	// cancelTimeout - ожидание ответа на cancel-RequestPDU (см. cancel.go)
+	cancelTimeout time.Duration
+	// abandoned - invokeID запросов, отменённых только локально; запоздавшие
+	// ответы на них отбрасываются (см. receiveResponse)
+	abandoned sync.Map
+	// dialContext - установка соединения в Dial (см. dial.go)
+	dialContext DialContextFunc
+	// connections - общий лимит соединений Dial (см. connlimit.go)
+	connections *ConnectionLimiter
+	// definiteLengthOnly - строгий разбор длин BER в MMS PDU
+	definiteLengthOnly bool
+	// der - проверка отправляемых MMS PDU на кодирование DER
+	der bool
+	// requestHandlers - обработчики запросов сервера (см. server_request.go)
+	requestHandlers map[mms.ConfirmedService]RequestHandler
+	// limiter - ограничение частоты запросов (см. ratelimit.go)
+	limiter *RateLimiter
+	// queue - очередь confirmed-запросов по приоритету (см. priority.go)
+	queue requestQueue
+	// cotpLocalRef - source reference COTP соединения; 0 - по умолчанию
+	cotpLocalRef uint16
+	// tap - получатель PDU всех уровней стека (см. tap.go)
+	tap mms.RawPDUTap
+	// capabilities - действующие параметры ассоциации, nil до Initiate
+	capabilities *mms.Capabilities
+	// diagnostics - журнал последних событий соединения (см. diagnostics.go)
+	diagnostics *diagnosticsJournal
+	// defaultDomain - домен ссылок без "/" (см. NewReadRequest)
+	defaultDomain string
+	// clockSkew - оценка расхождения часов IED по отчётам (см. clockskew.go)
+	clockSkew *ClockSkew
+	// readOnly - запрет записи и управления (см. readonly.go)
+	readOnly bool
+	// tls - параметры TLS соединения Dial (см. tls.go)
+	tls *tlsSettings
+	// auditSinks - журналы аудита операций записи (см. audit.go)
+	auditSinks []AuditSink
+	// valueCache - кэш прочитанных и полученных в отчётах значений (см. valuecache.go)
+	valueCache *ValueCache
 }
 
 // defaultLogger создает логгер по умолчанию без категории
@@ -35,36 +93,305 @@ func WithLogger(l logger.Logger) MmsClientOption {
 	}
 }
 
+// WithInvokeIDAllocator устанавливает аллокатор invokeID для MmsClient.
+// Позволяет задать фиксированный начальный invokeID для воспроизводимых тестов.
+func WithInvokeIDAllocator(a *mms.InvokeIDAllocator) MmsClientOption {
+	return func(c *MmsClient) {
+		c.invokeIDs = a
+	}
+}
+
+// WithDefaultDomain задаёт домен (логическое устройство) для ссылок на объекты
+// без домена, например "GGIO1.AnIn1.mag.f". Без опции домен в ссылке обязателен.
+func WithDefaultDomain(domain string) MmsClientOption {
+	return func(c *MmsClient) {
+		c.defaultDomain = domain
+	}
+}
+
+// NewReadRequest создаёт ReadRequest для ссылки reference (см. mms.NewReadRequest);
+// ссылка без домена относится к домену WithDefaultDomain.
+func (c *MmsClient) NewReadRequest(reference string, fc mms.FunctionalConstraint) (*mms.ReadRequest, error) {
+	return mms.NewReadRequestInDomain(c.defaultDomain, reference, fc)
+}
+
+// Read читает значение объекта reference с функциональным ограничением fc,
+// например "simpleIOGenericIO/GGIO1.AnIn1.mag.f" и mms.FCMX. Запрос строится
+// NewReadRequest и выполняется ReadObject; отказ сервера в доступе возвращается
+// как *mms.DataAccessError.
+func (c *MmsClient) Read(ctx context.Context, reference string, fc mms.FunctionalConstraint) (*variant.Variant, error) {
+	request, err := c.NewReadRequest(reference, fc)
+	if err != nil {
+		return nil, err
+	}
+	result, err := c.ReadObject(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	if !result.Success {
+		if result.Error != nil {
+			return nil, fmt.Errorf("read %s: %w", reference, result.Error)
+		}
+		return nil, fmt.Errorf("read %s failed", reference)
+	}
+	return result.Value, nil
+}
+
+// Write записывает значение value в объект reference с функциональным ограничением fc,
+// например "simpleIOGenericIO/GGIO1.AnIn1.db" и mms.FCCF. Ссылка разбирается как в
+// NewReadRequest; отказ сервера в доступе возвращается как *mms.DataAccessError.
+func (c *MmsClient) Write(ctx context.Context, reference string, fc mms.FunctionalConstraint, value *variant.Variant) error {
+	request, err := c.NewReadRequest(reference, fc)
+	if err != nil {
+		return err
+	}
+	if err := c.writeVariable(ctx, request.DomainID, request.ItemID, value, nil); err != nil {
+		return fmt.Errorf("write %s: %w", reference, err)
+	}
+	return nil
+}
+
+// WriteMany записывает значения нескольких переменных одним запросом MMS Write,
+// например элементов набора данных. Сервер записывает переменные по отдельности:
+// результаты в порядке items сообщают, какие из них не записаны и почему
+// (mms.WriteResult.Error). Запрос, не помещающийся в размер PDU, согласованный
+// в Initiate, разбивается на несколько запросов Write, выполняемых по очереди.
+// Ошибка возвращается, если запрос не выполнен целиком: разрыв соединения,
+// отказ сервиса или некорректный ответ. Вместе с ошибкой возвращаются результаты
+// предыдущих запросов - эти переменные уже обработаны сервером; результатов
+// для остальных переменных нет.
+func (c *MmsClient) WriteMany(ctx context.Context, items []mms.WriteItem) (_ []mms.WriteResult, err error) {
+	if len(items) == 0 {
+		return nil, fmt.Errorf("no variables to write")
+	}
+	start := time.Now()
+	var results []mms.WriteResult
+	defer func() {
+		// Переменные с результатом сервера записываются в журнал с этим результатом,
+		// неотправленные - с ошибкой запроса
+		for i, item := range items {
+			itemErr := err
+			if i < len(results) {
+				itemErr = nil
+				if !results[i].Success() {
+					itemErr = results[i].Error
+				}
+			}
+			c.audit(writeOperation(item.Variable.ItemID), item.Variable.DomainID+"/"+item.Variable.ItemID,
+				item.Value, start, &itemErr)
+		}
+	}()
+	batches, err := c.writeBatches(items)
+	if err != nil {
+		return nil, err
+	}
+	for _, batch := range batches {
+		response, err := c.write(ctx, &mms.WriteRequest{Items: batch}, nil)
+		if err != nil {
+			return results, err
+		}
+		results = append(results, response.Results...)
+	}
+	return results, nil
+}
+
+// writeBatches делит items на запросы Write, помещающиеся в согласованный
+// размер PDU. Переменная, не помещающаяся в PDU одна, отправляется отдельным
+// запросом и отклоняется при отправке (mms.ErrPduTooLarge).
+func (c *MmsClient) writeBatches(items []mms.WriteItem) ([][]mms.WriteItem, error) {
+	var maxPduSize uint32
+	if c.capabilities != nil {
+		maxPduSize = c.capabilities.MaxPduSize
+	}
+	if maxPduSize == 0 {
+		return [][]mms.WriteItem{items}, nil
+	}
+	var batches [][]mms.WriteItem
+	first := 0
+	for i := range items {
+		// Размер оценивается с invokeID наибольшей длины
+		pdu, err := (&mms.WriteRequest{InvokeID: math.MaxUint32, Items: items[first : i+1]}).Bytes()
+		if err != nil {
+			return nil, err
+		}
+		if uint64(len(pdu)) > uint64(maxPduSize) && i > first {
+			batches = append(batches, items[first:i])
+			first = i
+		}
+	}
+	return append(batches, items[first:]), nil
+}
+
+// WithDefiniteLengthOnly отклоняет ответы сервера с неопределённой формой длины BER
+// в MMS PDU (ошибка mms.ErrIndefiniteLength), как требует DER
+func WithDefiniteLengthOnly() MmsClientOption {
+	return func(c *MmsClient) {
+		c.definiteLengthOnly = true
+	}
+}
+
+// WithDEREncoding проверяет каждый отправляемый MMS PDU на кодирование DER
+// (см. mms.WithDEREncoding); PDU с иным кодированием не отправляются
+func WithDEREncoding() MmsClientOption {
+	return func(c *MmsClient) {
+		c.der = true
+	}
+}
+
+// WithCOTPLocalRef задаёт source reference COTP соединения (по умолчанию 1).
+// CC и DR с другим dst-ref отклоняются (cotp.ErrReferenceMismatch).
+func WithCOTPLocalRef(ref uint16) MmsClientOption {
+	return func(c *MmsClient) {
+		c.cotpLocalRef = ref
+	}
+}
+
 // NewMmsClient создает новый MMS клиент и устанавливает COTP соединение.
 // Контекст используется для установки COTP соединения, которое происходит
 // при создании клиента. Параметры COTP соединения задаются значениями по умолчанию.
-func NewMmsClient(ctx context.Context, conn net.Conn, opts ...MmsClientOption) (*MmsClient, error) {
+//
+// Транспортом может быть любой io.ReadWriteCloser: TCP, TLS, UNIX сокет, туннель.
+// Если транспорт поддерживает SetReadDeadline (как net.Conn), отмена контекста
+// прерывает ожидание ответа; иначе контекст проверяется только между чтениями.
+func NewMmsClient(ctx context.Context, conn io.ReadWriteCloser, opts ...MmsClientOption) (*MmsClient, error) {
 	client := &MmsClient{
-		conn:   conn,
-		logger: defaultLogger(),
+		conn:        conn,
+		logger:      defaultLogger(),
+		profile:     ProfileDefault,
+		diagnostics: newDiagnosticsJournal(DefaultDiagnosticsSize),
 	}
 	for _, opt := range opts {
 		opt(client)
 	}
+	if len(client.origin.Identification) > maxOrIdentSize {
+		return nil, fmt.Errorf("orIdent of %d bytes exceeds %d", len(client.origin.Identification), maxOrIdentSize)
+	}
+	if client.requestHandlers == nil {
+		client.requestHandlers = defaultRequestHandlers()
+	}
 
 	// Создаём COTP соединение и устанавливаем его
+	// T-selectors берутся из профиля устройства
 	params := &cotp.IsoConnectionParameters{
-		RemoteTSelector: cotp.TSelector{Value: []byte{0, 1}},
-		LocalTSelector:  cotp.TSelector{Value: []byte{0, 1}},
+		RemoteTSelector: cotp.TSelector{Value: client.profile.RemoteTSelector},
+		LocalTSelector:  cotp.TSelector{Value: client.profile.LocalTSelector},
 	}
 
-	cotpConn, err := cotp.NewConnectedConnection(ctx, client.conn, params, cotp.WithLogger(client.logger))
+	cotpOpts := []cotp.ConnectionOption{cotp.WithLogger(client.logger)}
+	if client.cotpLocalRef != 0 {
+		cotpOpts = append(cotpOpts, cotp.WithLocalRef(client.cotpLocalRef))
+	}
+	if client.tap != nil {
+		cotpOpts = append(cotpOpts, cotp.WithPacketTap(cotpPacketTap(client.tap)))
+	}
+	cotpConn, err := cotp.NewConnectedConnection(ctx, client.conn, params, cotpOpts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to establish COTP connection: %w", err)
 	}
 	client.cotpConn = cotpConn
+	client.diag("COTP connection established")
 
 	// Создаём MMS клиент для работы с протокольным стеком
-	client.mmsClient = mms.NewClient(client.cotpConn, client.logger)
+	var mmsOpts []mms.ClientOption
+	if client.invokeIDs != nil {
+		mmsOpts = append(mmsOpts, mms.WithInvokeIDAllocator(client.invokeIDs))
+	}
+	if client.definiteLengthOnly {
+		mmsOpts = append(mmsOpts, mms.WithDefiniteLengthOnly())
+	}
+	if client.der {
+		mmsOpts = append(mmsOpts, mms.WithDEREncoding())
+	}
+	if tap := client.pduTap(); tap != nil {
+		mmsOpts = append(mmsOpts, mms.WithRawPDUTap(tap))
+	}
+	client.mmsClient = mms.NewClient(client.cotpConn, client.logger, mmsOpts...)
 
 	return client, nil
 }
 
+// AssociationInfo - сведения об установленной ассоциации
+type AssociationInfo struct {
+	// Responder - идентификация сервера из AARE (responding AP-title и AE-qualifier);
+	// nil, если сервер не передал эти поля
+	Responder *acse.AssociationInfo
+	// Capabilities - действующие параметры, согласованные в Initiate. Клиент
+	// подстраивается под них: запросы больше MaxPduSize и запросы незаявленных
+	// услуг не отправляются, WriteMany делится на запросы по MaxPduSize, а
+	// confirmed-запросы выполняются по одному, не превышая MaxServOutstandingCalling.
+	Capabilities *mms.Capabilities
+}
+
+// AssociationInfo возвращает сведения об ассоциации: идентификацию сервера,
+// чтобы убедиться, что ассоциация установлена с ожидаемым IED, и согласованные
+// параметры (см. Capabilities). nil до Initiate.
+func (c *MmsClient) AssociationInfo() *AssociationInfo {
+	if c.mmsClient == nil || c.capabilities == nil {
+		return nil
+	}
+	return &AssociationInfo{Responder: c.mmsClient.Association(), Capabilities: c.capabilities}
+}
+
+// Close закрывает транспортное соединение клиента без Conclude;
+// выполняемые запросы и Listen завершаются ошибкой соединения
+func (c *MmsClient) Close() error {
+	return c.conn.Close()
+}
+
+// Capabilities возвращает действующие параметры ассоциации, согласованные
+// в Initiate: размер PDU, число одновременных запросов, вложенность структур,
+// параметры CBB и услуги сервера, а также список уменьшений относительно
+// запроса (Downgrades). nil до Initiate.
+func (c *MmsClient) Capabilities() *mms.Capabilities {
+	return c.capabilities
+}
+
+// checkService возвращает mms.ErrServiceNotSupported, если сервер
+// не заявил услугу service в Initiate, и ошибку, если service нет
+// в таблице запрашиваемых клиентом сервисов (requestedServices)
+func (c *MmsClient) checkService(service mms.ServiceSupportedBit) error {
+	if !slices.Contains(requestedServices, service) {
+		return fmt.Errorf("service %s is not in client conformance table", service)
+	}
+	if c.capabilities == nil {
+		return nil
+	}
+	return c.capabilities.CheckService(service)
+}
+
+// Profile возвращает профиль устройства, с которым работает клиент
+func (c *MmsClient) Profile() ServerProfile {
+	return c.profile
+}
+
+// checkNames проверяет MMS имена на допустимые символы Identifier и ограничения
+// профиля устройства до отправки запроса. Пустые имена (необязательные поля) пропускаются.
+func (c *MmsClient) checkNames(names ...string) error {
+	for _, name := range names {
+		if name == "" {
+			continue
+		}
+		if err := mms.ValidateIdentifier(name); err != nil {
+			return err
+		}
+		if err := c.profile.CheckName(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// responseError возвращает ошибку разбора ответа сервиса service. Отказ сервера
+// (*mms.ConfirmedError) возвращается без изменений: это не ошибка разбора,
+// а *mms.ServiceError доступен через errors.As.
+func responseError(service string, err error) error {
+	var confirmedError *mms.ConfirmedError
+	if errors.As(err, &confirmedError) {
+		return err
+	}
+	return fmt.Errorf("failed to parse MMS %s Response: %w", service, err)
+}
+
 func (c *MmsClient) Initiate(ctx context.Context, opts ...mms.InitiateRequestOption) (*mms.InitiateResponse, error) {
 	// --- Создание полного пакета MMS Initiate Request ---
 	// Порядок вложенности: MMS -> ACSE -> Presentation -> Session -> COTP
@@ -72,23 +399,32 @@ func (c *MmsClient) Initiate(ctx context.Context, opts ...mms.InitiateRequestOpt
 	// 1. Создаём MMS InitiateRequest структуру с опциями
 	mmsRequest := mms.NewInitiateRequest(opts...)
 
+	// Приводим параметры к допустимым диапазонам и проверяем до отправки,
+	// чтобы не получать малопонятный отказ в ассоциации от сервера
+	mmsRequest.Normalize()
+	if err := mmsRequest.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid MMS InitiateRequest: %w", err)
+	}
+
 	// Логируем структуру
 	c.logger.Debug("MMS InitiateRequest: %s", mmsRequest)
 
 	// Получаем BER-кодированный пакет
 	mmsPdu := mmsRequest.Bytes()
+	if err := c.mmsClient.CheckEncoding(mmsPdu); err != nil {
+		return nil, err
+	}
 
-	// 2. Обёртываем в ACSE AARQ
-	acsePdu := acse.BuildAARQ(mmsPdu)
-
-	// 3. Обёртываем в Presentation CP-type
-	presentationPdu := presentation.BuildCPType(acsePdu)
+	// 2. Обёртываем в ACSE AARQ, Presentation CP-type и Session CONNECT SPDU
+	associate := mms.NewAssociateRequest(mmsPdu)
 
-	// 4. Обёртываем в Session CONNECT SPDU
-	sessionPdu := session.BuildConnectSPDU(presentationPdu)
+	c.mmsClient.Tap(mms.DirectionSend, mms.LayerMMS, associate.MMS)
+	c.mmsClient.Tap(mms.DirectionSend, mms.LayerACSE, associate.ACSE)
+	c.mmsClient.Tap(mms.DirectionSend, mms.LayerPresentation, associate.Presentation)
+	c.mmsClient.Tap(mms.DirectionSend, mms.LayerSession, associate.Session)
 
-	// 5. Отправляем через COTP
-	err := c.cotpConn.SendDataMessage(sessionPdu)
+	// 3. Отправляем через COTP
+	err := c.cotpConn.SendDataMessageContext(ctx, associate.Session)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send data: %w", err)
 	}
@@ -97,6 +433,7 @@ func (c *MmsClient) Initiate(ctx context.Context, opts ...mms.InitiateRequestOpt
 	// Используем MMS клиент для получения и парсинга ответа
 	mmsData, err := c.mmsClient.ReceiveAndParseMmsResponse(ctx)
 	if err != nil {
+		c.diag("association failed: %v", err)
 		return nil, err
 	}
 
@@ -113,9 +450,40 @@ func (c *MmsClient) Initiate(ctx context.Context, opts ...mms.InitiateRequestOpt
 		return nil, fmt.Errorf("MMS Initiate Response is nil after parsing")
 	}
 
+	// Подстраиваемся под ответ сервера: запросы больше согласованного размера PDU
+	// и запросы незаявленных сервером услуг не отправляются
+	c.capabilities = mms.NegotiateCapabilities(mmsRequest, mmsResponse)
+	c.mmsClient.SetMaxPduSize(c.capabilities.MaxPduSize)
+	c.logger.Debug("MMS negotiated max PDU size: %d", c.capabilities.MaxPduSize)
+	c.logger.Debug("MMS %s", c.capabilities)
+	c.diag("association established: %s", c.capabilities)
+	if c.cotpConn != nil {
+		payload, read, write := c.cotpConn.BufferSizes()
+		c.logger.Debug("COTP buffers: payload %d, read %d, write %d", payload, read, write)
+	}
+	if responder := c.mmsClient.Association(); responder != nil {
+		c.logger.Debug("ACSE responder: %s", responder)
+	}
+	logger.Info(c.logger, "%s", c.associationSummary(mmsRequest, mmsResponse))
+
 	return mmsResponse, nil
 }
 
+// associationSummary возвращает одну строку key=value об установленной ассоциации:
+// адрес и идентификатор сервера, отправленный запрос, ответ и согласованные параметры
+func (c *MmsClient) associationSummary(request *mms.InitiateRequest, response *mms.InitiateResponse) string {
+	peer := "unknown"
+	if conn, ok := c.conn.(interface{ RemoteAddr() net.Addr }); ok {
+		peer = conn.RemoteAddr().String()
+	}
+	responder := "none"
+	if info := c.mmsClient.Association(); info != nil {
+		responder = info.String()
+	}
+	return fmt.Sprintf("MMS association established: peer=%s responder=%q request=%s response=%s negotiated=%s",
+		peer, responder, request, response, c.capabilities)
+}
+
 // ReadObject читает объект из сервера IEC 61850 по имени объекта.
 // Это плейсхолдер метод, который будет реализован позже.
 //
@@ -141,26 +509,50 @@ func (c *MmsClient) Initiate(ctx context.Context, opts ...mms.InitiateRequestOpt
 //   - Для объекта типа AnIn1.mag.f значение должно быть типом Float (REAL в MMS)
 //
 // 5. Вернуть AccessResult с результатом чтения
-func (c *MmsClient) ReadObject(ctx context.Context, readRequest *mms.ReadRequest) (mms.AccessResult, error) {
-	var result mms.AccessResult
+func (c *MmsClient) ReadObject(ctx context.Context, readRequest *mms.ReadRequest) (result mms.AccessResult, err error) {
+	finish, err := c.beginRequest(ctx)
+	if err != nil {
+		return result, err
+	}
+	defer finish()
+	start := time.Now()
+	defer func() {
+		c.recordStats(readRequest.DomainID+"/"+readRequest.ItemID, start, err != nil || !result.Success)
+	}()
+
 	// Проверяем, что соединение установлено
 	if c.mmsClient == nil {
 		return result, fmt.Errorf("connection not established, call Initiate first")
 	}
+	if err := c.checkService(mms.Read); err != nil {
+		return result, err
+	}
+	if err := c.checkNames(readRequest.DomainID, readRequest.ItemID); err != nil {
+		return result, err
+	}
+
+	// invokeID проставляет клиент; запрос пользователя не изменяем
+	invokeID, err := c.mmsClient.AllocateInvokeID()
+	if err != nil {
+		return result, err
+	}
+	defer c.mmsClient.ReleaseInvokeID(invokeID)
+	request := *readRequest
+	request.InvokeID = invokeID
 
-	mmsPdu := readRequest.Bytes()
+	mmsPdu := request.Bytes()
 
 	// Логируем MMS PDU
 	c.logger.Debug("MMS Read Request PDU: %x", mmsPdu)
 
 	// Отправляем MMS PDU через стеки протоколов
-	err := c.mmsClient.SendMmsPdu(mmsPdu)
+	err = c.mmsClient.SendMmsPduContext(ctx, mmsPdu)
 	if err != nil {
 		return result, fmt.Errorf("failed to send Read Request: %w", err)
 	}
 
 	// Получаем и парсим ответ
-	mmsData, err := c.mmsClient.ReceiveAndParseMmsResponse(ctx)
+	mmsData, err := c.receiveConfirmed(ctx, invokeID)
 	if err != nil {
 		return result, err
 	}
@@ -174,7 +566,7 @@ func (c *MmsClient) ReadObject(ctx context.Context, readRequest *mms.ReadRequest
 
 	readResponse, err := mms.ParseReadResponse(mmsData)
 	if err != nil {
-		return result, fmt.Errorf("failed to parse MMS Read Response: %w", err)
+		return result, responseError("Read", err)
 	}
 
 	// Извлекаем значение из результатов
@@ -183,7 +575,68 @@ func (c *MmsClient) ReadObject(ctx context.Context, readRequest *mms.ReadRequest
 	}
 
 	// Берем первый результат (обычно запрашивается один объект)
-	return readResponse.ListOfAccessResult[0], nil
+	result = readResponse.ListOfAccessResult[0]
+	if c.valueCache != nil && result.Success {
+		name := mms.ObjectName{DomainID: readRequest.DomainID, ItemID: readRequest.ItemID}
+		c.valueCache.Update(name.String(), result.Value)
+	}
+	return result, nil
+}
+
+// ReadMany читает несколько переменных одним запросом MMS Read. Результаты
+// возвращаются в порядке variables; отказ в доступе к переменной сообщается
+// в её AccessResult. Ошибка возвращается, если запрос не выполнен целиком
+// или число результатов не совпадает с числом переменных.
+func (c *MmsClient) ReadMany(ctx context.Context, variables []mms.ObjectName) (results []mms.AccessResult, err error) {
+	if len(variables) == 0 {
+		return nil, fmt.Errorf("no variables to read")
+	}
+	finish, err := c.beginRequest(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer finish()
+	start := time.Now()
+	defer func() {
+		c.recordStats(variables[0].String(), start, err != nil)
+	}()
+
+	if c.mmsClient == nil {
+		return nil, fmt.Errorf("connection not established, call Initiate first")
+	}
+	if err := c.checkService(mms.Read); err != nil {
+		return nil, err
+	}
+	for _, variable := range variables {
+		if err := c.checkNames(variable.DomainID, variable.ItemID); err != nil {
+			return nil, err
+		}
+	}
+
+	invokeID, err := c.mmsClient.AllocateInvokeID()
+	if err != nil {
+		return nil, err
+	}
+	defer c.mmsClient.ReleaseInvokeID(invokeID)
+
+	mmsPdu := (&mms.ReadRequest{InvokeID: invokeID, Items: variables}).Bytes()
+	c.logger.Debug("MMS Read Request PDU (%d variables): %x", len(variables), mmsPdu)
+	if err := c.mmsClient.SendMmsPduContext(ctx, mmsPdu); err != nil {
+		return nil, fmt.Errorf("failed to send Read Request: %w", err)
+	}
+	mmsData, err := c.receiveConfirmed(ctx, invokeID)
+	if err != nil {
+		return nil, err
+	}
+
+	readResponse, err := mms.ParseReadResponse(mmsData)
+	if err != nil {
+		return nil, responseError("Read", err)
+	}
+	if len(readResponse.ListOfAccessResult) != len(variables) {
+		return nil, fmt.Errorf("read of %d variables returned %d results", len(variables), len(readResponse.ListOfAccessResult))
+	}
+	return readResponse.ListOfAccessResult, nil
 }
 
 // пример ответа через wireshark (не удалять)
@@ -271,30 +724,53 @@ func (c *MmsClient) ReadObject(ctx context.Context, readRequest *mms.ReadRequest
 //	                      bit-string: -13
 //	                  components item
 //	                    componentName: t
-func (c *MmsClient) GetTypeSpecification(ctx context.Context, readRequest *mms.ReadRequest) (*mms.TypeSpecification, error) {
+func (c *MmsClient) GetTypeSpecification(ctx context.Context, readRequest *mms.ReadRequest) (_ *mms.TypeSpecification, err error) {
+	finish, err := c.beginRequest(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer finish()
+	start := time.Now()
+	defer func() {
+		c.recordStats(readRequest.DomainID+"/"+readRequest.ItemID, start, err != nil)
+	}()
+
 	// Проверяем, что соединение установлено
 	if c.mmsClient == nil {
 		return nil, fmt.Errorf("connection not established, call Initiate first")
 	}
+	if err := c.checkService(mms.GetVariableAccessAttributes); err != nil {
+		return nil, err
+	}
 
 	domainID := readRequest.DomainID
 	itemID := readRequest.ItemID
+	if err := c.checkNames(domainID, itemID); err != nil {
+		return nil, err
+	}
+
+	invokeID, err := c.mmsClient.AllocateInvokeID()
+	if err != nil {
+		return nil, err
+	}
+	defer c.mmsClient.ReleaseInvokeID(invokeID)
 
 	// Создаём запрос getVariableAccessAttributes
 	getVarAccessAttrRequest := mms.NewGetVariableAccessAttributesRequest(domainID, itemID)
+	getVarAccessAttrRequest.InvokeID = invokeID
 	mmsPdu := getVarAccessAttrRequest.Bytes()
 
 	// Логируем MMS PDU
 	c.logger.Debug("MMS GetVariableAccessAttributes Request PDU: %x", mmsPdu)
 
 	// Отправляем MMS PDU через стеки протоколов
-	err := c.mmsClient.SendMmsPdu(mmsPdu)
+	err = c.mmsClient.SendMmsPduContext(ctx, mmsPdu)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send GetVariableAccessAttributes Request: %w", err)
 	}
 
 	// Получаем и парсим ответ
-	mmsData, err := c.mmsClient.ReceiveAndParseMmsResponse(ctx)
+	mmsData, err := c.receiveConfirmed(ctx, invokeID)
 	if err != nil {
 		return nil, err
 	}
@@ -309,7 +785,7 @@ func (c *MmsClient) GetTypeSpecification(ctx context.Context, readRequest *mms.R
 	// Парсим полный ответ GetVariableAccessAttributes, включая invokeID, mmsDeletable и typeSpecification
 	response, err := mms.ParseGetVariableAccessAttributesResponse(mmsData)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse MMS GetVariableAccessAttributes Response: %w", err)
+		return nil, responseError("GetVariableAccessAttributes", err)
 	}
 
 	c.logger.Debug("  InvokeID: %d", response.InvokeID)
@@ -320,3 +796,164 @@ func (c *MmsClient) GetTypeSpecification(ctx context.Context, readRequest *mms.R
 	}
 	return response.TypeSpecification, nil
 }
+
+// GetNameList запрашивает у сервера имена объектов MMS.
+// Класс объектов и область поиска задаются в запросе (см. mms.NewGetNameListRequest):
+// домены - ObjectClassDomain на уровне VMD, переменные, наборы данных
+// и журналы - ObjectClassNamedVariable, ObjectClassNamedVariableList
+// и ObjectClassJournal в области домена.
+//
+// Выполняется один запрос: если в ответе MoreFollows, продолжение запрашивается
+// повторным вызовом с ContinueAfter, равным последнему полученному имени.
+func (c *MmsClient) GetNameList(ctx context.Context, request *mms.GetNameListRequest) (_ *mms.GetNameListResponse, err error) {
+	finish, err := c.beginRequest(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer finish()
+	start := time.Now()
+	defer func() {
+		c.recordStats(request.DomainID, start, err != nil)
+	}()
+
+	if c.mmsClient == nil {
+		return nil, fmt.Errorf("connection not established, call Initiate first")
+	}
+	if err := c.checkService(mms.GetNameList); err != nil {
+		return nil, err
+	}
+	if err := c.checkNames(request.DomainID, request.ContinueAfter); err != nil {
+		return nil, err
+	}
+
+	invokeID, err := c.mmsClient.AllocateInvokeID()
+	if err != nil {
+		return nil, err
+	}
+	defer c.mmsClient.ReleaseInvokeID(invokeID)
+	nameListRequest := *request
+	nameListRequest.InvokeID = invokeID
+
+	mmsPdu := nameListRequest.Bytes()
+	c.logger.Debug("MMS GetNameList Request PDU (%s, %s %s): %x",
+		request.ObjectClass, request.ObjectScope, request.DomainID, mmsPdu)
+
+	err = c.mmsClient.SendMmsPduContext(ctx, mmsPdu)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send GetNameList Request: %w", err)
+	}
+
+	mmsData, err := c.receiveConfirmed(ctx, invokeID)
+	if err != nil {
+		return nil, err
+	}
+
+	c.logger.Debug("MMS GetNameList Response PDU (raw bytes): %x", mmsData)
+
+	if len(mmsData) == 0 {
+		return nil, fmt.Errorf("MMS data is empty")
+	}
+
+	response, err := mms.ParseGetNameListResponse(mmsData)
+	if err != nil {
+		return nil, responseError("GetNameList", err)
+	}
+	return response, nil
+}
+
+// GetDomainAttributes запрашивает атрибуты домена (логического устройства):
+// список возможностей, состояние, признаки mmsDeletable и sharable,
+// программы, использующие домен, и число выполняющихся выгрузок.
+func (c *MmsClient) GetDomainAttributes(ctx context.Context, domain string) (_ *mms.GetDomainAttributesResponse, err error) {
+	finish, err := c.beginRequest(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer finish()
+	start := time.Now()
+	defer func() {
+		c.recordStats(domain, start, err != nil)
+	}()
+
+	if c.mmsClient == nil {
+		return nil, fmt.Errorf("connection not established, call Initiate first")
+	}
+	if err := c.checkService(mms.GetDomainAttributes); err != nil {
+		return nil, err
+	}
+	if err := c.checkNames(domain); err != nil {
+		return nil, err
+	}
+
+	invokeID, err := c.mmsClient.AllocateInvokeID()
+	if err != nil {
+		return nil, err
+	}
+	defer c.mmsClient.ReleaseInvokeID(invokeID)
+
+	request := &mms.GetDomainAttributesRequest{InvokeID: invokeID, DomainID: domain}
+	mmsPdu, err := request.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	c.logger.Debug("MMS GetDomainAttributes Request PDU: %x", mmsPdu)
+
+	if err := c.mmsClient.SendMmsPduContext(ctx, mmsPdu); err != nil {
+		return nil, fmt.Errorf("failed to send GetDomainAttributes Request: %w", err)
+	}
+	mmsData, err := c.receiveConfirmed(ctx, invokeID)
+	if err != nil {
+		return nil, err
+	}
+	c.logger.Debug("MMS GetDomainAttributes Response PDU (raw bytes): %x", mmsData)
+
+	response, err := mms.ParseGetDomainAttributesResponse(mmsData)
+	if err != nil {
+		return nil, responseError("GetDomainAttributes", err)
+	}
+	return response, nil
+}
+
+// Identify запрашивает у сервера производителя, модель и версию (сервис Identify).
+// Обычно вызывается сразу после Initiate, чтобы определить тип IED.
+func (c *MmsClient) Identify(ctx context.Context) (_ *mms.IdentifyResponse, err error) {
+	finish, err := c.beginRequest(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer finish()
+	start := time.Now()
+	defer func() {
+		c.recordStats("", start, err != nil)
+	}()
+
+	if c.mmsClient == nil {
+		return nil, fmt.Errorf("connection not established, call Initiate first")
+	}
+	if err := c.checkService(mms.Identify); err != nil {
+		return nil, err
+	}
+
+	invokeID, err := c.mmsClient.AllocateInvokeID()
+	if err != nil {
+		return nil, err
+	}
+	defer c.mmsClient.ReleaseInvokeID(invokeID)
+
+	mmsPdu := (&mms.IdentifyRequest{InvokeID: invokeID}).Bytes()
+	c.logger.Debug("MMS Identify Request PDU: %x", mmsPdu)
+	if err := c.mmsClient.SendMmsPduContext(ctx, mmsPdu); err != nil {
+		return nil, fmt.Errorf("failed to send Identify Request: %w", err)
+	}
+	mmsData, err := c.receiveConfirmed(ctx, invokeID)
+	if err != nil {
+		return nil, err
+	}
+	c.logger.Debug("MMS Identify Response PDU (raw bytes): %x", mmsData)
+
+	response, err := mms.ParseIdentifyResponse(mmsData)
+	if err != nil {
+		return nil, responseError("Identify", err)
+	}
+	return response, nil
+}
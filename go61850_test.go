@@ -0,0 +1,149 @@
+package go61850
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/slonegd/go61850/mmstest"
+	"github.com/slonegd/go61850/osi/mms"
+	"github.com/slonegd/go61850/osi/mms/variant"
+	"github.com/stretchr/testify/assert"
+)
+
+// infoLogger запоминает сообщения уровня INFO
+type infoLogger struct {
+	debug int
+	info  []string
+}
+
+func (l *infoLogger) Debug(format string, v ...any) { l.debug++ }
+
+func (l *infoLogger) Info(format string, v ...any) {
+	l.info = append(l.info, fmt.Sprintf(format, v...))
+}
+
+func TestInitiateSummary(t *testing.T) {
+	server := mmstest.NewTranscriptServer(t, fileTranscript(t)...)
+	conn := server.Dial()
+	ctx := context.Background()
+	l := &infoLogger{}
+	client, err := NewMmsClient(ctx, conn, WithLogger(l))
+	assert.NoError(t, err)
+	_, err = client.Initiate(ctx)
+	assert.NoError(t, err)
+	conn.Close()
+	assert.NoError(t, server.Wait())
+
+	// Одна строка с параметрами ассоциации, отладочные сообщения - через Debug
+	assert.Len(t, l.info, 1)
+	assert.Greater(t, l.debug, 0)
+	summary := l.info[0]
+	assert.Contains(t, summary, "MMS association established: peer=pipe responder=")
+	assert.Contains(t, summary, "request=InitiateRequest{")
+	assert.Contains(t, summary, "response=InitiateResponse{")
+	assert.Contains(t, summary, "negotiated=Capabilities{MaxPduSize=65000 ")
+}
+
+func TestAssociationInfo(t *testing.T) {
+	server := mmstest.NewTranscriptServer(t, fileTranscript(t)...)
+	conn := server.Dial()
+	ctx := context.Background()
+	client, err := NewMmsClient(ctx, conn)
+	assert.NoError(t, err)
+	assert.Nil(t, client.AssociationInfo())
+	_, err = client.Initiate(ctx)
+	assert.NoError(t, err)
+	conn.Close()
+	assert.NoError(t, server.Wait())
+
+	info := client.AssociationInfo()
+	assert.Same(t, client.Capabilities(), info.Capabilities)
+	assert.Equal(t, uint32(65000), info.Capabilities.MaxPduSize)
+}
+
+func TestRead(t *testing.T) {
+	f, err := os.Open("mmstest/testdata/read.txt")
+	assert.NoError(t, err)
+	defer f.Close()
+	exchanges, err := mmstest.ParseTranscript(f)
+	assert.NoError(t, err)
+
+	server := mmstest.NewTranscriptServer(t, exchanges...)
+	conn := server.Dial()
+	ctx := context.Background()
+	client, err := NewMmsClient(ctx, conn, WithDefaultDomain("simpleIOGenericIO"))
+	assert.NoError(t, err)
+	_, err = client.Initiate(ctx)
+	assert.NoError(t, err)
+
+	value, err := client.Read(ctx, "GGIO1$MX", mms.FCNone)
+	assert.NoError(t, err)
+	assert.Len(t, value.Structure(), 4)
+	conn.Close()
+	assert.NoError(t, server.Wait())
+
+	_, err = client.Read(ctx, "GGIO1", mms.FCMX)
+	assert.Error(t, err)
+	_, err = (&MmsClient{}).Read(ctx, "GGIO1.AnIn1", mms.FCMX)
+	assert.ErrorIs(t, err, mms.ErrMissingDomain)
+}
+
+func TestWrite(t *testing.T) {
+	f, err := os.Open("mmstest/testdata/write.txt")
+	assert.NoError(t, err)
+	defer f.Close()
+	exchanges, err := mmstest.ParseTranscript(f)
+	assert.NoError(t, err)
+
+	server := mmstest.NewTranscriptServer(t, exchanges...)
+	conn := server.Dial()
+	ctx := context.Background()
+	client, err := NewMmsClient(ctx, conn, WithDefaultDomain("simpleIOGenericIO"))
+	assert.NoError(t, err)
+	_, err = client.Initiate(ctx)
+	assert.NoError(t, err)
+
+	assert.NoError(t, client.Write(ctx, "GGIO1.AnIn1.db", mms.FCCF, variant.NewUint32Variant(500)))
+	err = client.Write(ctx, "GGIO1.AnIn1.db", mms.FCCF, variant.NewUint32Variant(500))
+	assert.ErrorIs(t, err, &mms.DataAccessError{ErrorCode: mms.ObjectAccessDenied})
+	assert.EqualError(t, err, "write GGIO1.AnIn1.db: data access error: object-access-denied")
+	conn.Close()
+	assert.NoError(t, server.Wait())
+
+	err = (&MmsClient{}).Write(ctx, "GGIO1.AnIn1.db", mms.FCCF, variant.NewUint32Variant(500))
+	assert.ErrorIs(t, err, mms.ErrMissingDomain)
+}
+
+func TestIdentify(t *testing.T) {
+	f, err := os.Open("mmstest/testdata/read.txt")
+	assert.NoError(t, err)
+	defer f.Close()
+	exchanges, err := mmstest.ParseTranscript(f)
+	assert.NoError(t, err)
+
+	server := mmstest.NewTranscriptServer(t,
+		exchanges[0], exchanges[1],
+		mmstest.Exchange{
+			Request: "03 00 00 1b 02 f0 80 01 00 01 00 61 0e 30 0c 02 01 03 a0 07 a0 05 02 01 xx 82 00",
+			Responses: []string{"03 00 00 3e 02 f0 80 01 00 01 00 61 31 30 2f 02 01 03 a0 2a a1 28 02 01 01 a2 23 " +
+				"80 0d 4d 5a 20 41 75 74 6f 6d 61 74 69 6f 6e 81 0b 4c 49 42 49 45 43 36 31 38 35 30 82 05 31 2e 36 2e 30"},
+		},
+	)
+	conn := server.Dial()
+	ctx := context.Background()
+	client, err := NewMmsClient(ctx, conn)
+	assert.NoError(t, err)
+	_, err = client.Initiate(ctx)
+	assert.NoError(t, err)
+
+	identity, err := client.Identify(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, &mms.IdentifyResponse{VendorName: "MZ Automation", ModelName: "LIBIEC61850", Revision: "1.6.0"}, identity)
+	conn.Close()
+	assert.NoError(t, server.Wait())
+
+	_, err = (&MmsClient{}).Identify(ctx)
+	assert.EqualError(t, err, "connection not established, call Initiate first")
+}
@@ -0,0 +1,195 @@
+// Package goose содержит кодирование данных GOOSE (IEC 61850-8-1).
+//
+// DataSet описывает состав набора данных, опубликованного блоком GoCB,
+// и проверяет значения перед кодированием allData, чтобы издатель
+// не отправил кадр, который подписчики не смогут сопоставить с набором.
+package goose
+
+import (
+	"fmt"
+	"math"
+	"unicode/utf8"
+
+	"github.com/slonegd/go61850/internal/ber"
+	"github.com/slonegd/go61850/osi/mms"
+	"github.com/slonegd/go61850/osi/mms/variant"
+	"github.com/slonegd/go61850/scl"
+)
+
+// tagAllData - allData [11] IMPLICIT SEQUENCE OF Data в IECGoosePdu
+const tagAllData ber.Tag = 0xAB
+
+// Member представляет элемент набора данных
+type Member struct {
+	// Reference - ссылка на элемент "LD/LN.DO.DA"
+	Reference string
+	FC        mms.FunctionalConstraint
+	// Type - тип элемента по шаблонам SCL
+	Type scl.Type
+}
+
+// DataSet представляет набор данных GOOSE
+type DataSet struct {
+	Name    string
+	Members []Member
+}
+
+// NewDataSet создаёт описание набора данных name логического устройства ldInst
+// устройства iedName по SCL
+func NewDataSet(s *scl.SCL, iedName, ldInst, name string) (*DataSet, error) {
+	ied := s.IED(iedName)
+	if ied == nil {
+		return nil, fmt.Errorf("IED %s not found", iedName)
+	}
+	ld := ied.LDevice(ldInst)
+	if ld == nil {
+		return nil, fmt.Errorf("LDevice %s not found in IED %s", ldInst, iedName)
+	}
+	ds := ld.DataSet(name)
+	if ds == nil {
+		return nil, fmt.Errorf("DataSet %s not found in %s", name, ld.Name(iedName))
+	}
+
+	dataSet := &DataSet{Name: ld.Name(iedName) + "/LLN0$" + name}
+	for _, fcda := range ds.FCDAs {
+		typ, err := s.FCDAType(ied, fcda)
+		if err != nil {
+			return nil, err
+		}
+		member := Member{
+			Reference: ied.LDevice(fcda.LdInst).Name(iedName) + "/" + fcda.Reference(),
+			FC:        mms.FunctionalConstraint(fcda.FC),
+			Type:      typ,
+		}
+		dataSet.Members = append(dataSet.Members, member)
+	}
+	return dataSet, nil
+}
+
+// Validate проверяет, что values соответствуют составу набора данных:
+// количество, структура и базовые типы элементов, диапазоны целых и длины строк
+func (d *DataSet) Validate(values []*variant.Variant) error {
+	if len(values) != len(d.Members) {
+		return fmt.Errorf("dataset %s has %d members, got %d values", d.Name, len(d.Members), len(values))
+	}
+	for i, member := range d.Members {
+		if err := checkValue(member.Type, values[i]); err != nil {
+			return fmt.Errorf("%s [%s]: %w", member.Reference, member.FC, err)
+		}
+	}
+	return nil
+}
+
+// EncodeAllData проверяет values и кодирует их в элемент allData IECGoosePdu
+func (d *DataSet) EncodeAllData(values []*variant.Variant) ([]byte, error) {
+	if err := d.Validate(values); err != nil {
+		return nil, err
+	}
+
+	var content []byte
+	for i, v := range values {
+		encoded, err := mms.EncodeData(v)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", d.Members[i].Reference, err)
+		}
+		content = append(content, encoded...)
+	}
+
+	buffer := make([]byte, 1+ber.DetermineLengthSize(uint32(len(content)))+len(content))
+	bufPos := ber.EncodeTL(tagAllData, uint32(len(content)), buffer, 0)
+	copy(buffer[bufPos:], content)
+	return buffer, nil
+}
+
+// checkValue проверяет значение v на соответствие типу t
+func checkValue(t scl.Type, v *variant.Variant) error {
+	if v == nil {
+		return fmt.Errorf("%s: value is nil", t.Name)
+	}
+
+	if t.IsStruct() {
+		if v.Type() != variant.Structure {
+			return fmt.Errorf("%s: expected structure, got %s", t.Name, v.Type())
+		}
+		elements := v.Structure()
+		if len(elements) != len(t.Children) {
+			return fmt.Errorf("%s: expected %d elements, got %d", t.Name, len(t.Children), len(elements))
+		}
+		for i, child := range t.Children {
+			if err := checkValue(child, elements[i]); err != nil {
+				return fmt.Errorf("%s.%w", t.Name, err)
+			}
+		}
+		return nil
+	}
+
+	basic, ok := basicTypes[t.BType]
+	if !ok {
+		return fmt.Errorf("%s: unsupported bType %s", t.Name, t.BType)
+	}
+	expected, limit := basic.typ, basic.limit
+	if v.Type() != expected {
+		return fmt.Errorf("%s: bType %s expects %s, got %s", t.Name, t.BType, expected, v.Type())
+	}
+
+	switch expected {
+	case variant.Int32:
+		if val := int64(v.Int32()); val < -limit-1 || val > limit {
+			return fmt.Errorf("%s: value %d out of range of %s", t.Name, val, t.BType)
+		}
+	case variant.Uint32:
+		if val := int64(v.Uint32()); val > limit {
+			return fmt.Errorf("%s: value %d out of range of %s", t.Name, val, t.BType)
+		}
+	case variant.BitString:
+		if size := v.BitString().BitSize; int64(size) != limit {
+			return fmt.Errorf("%s: bType %s expects %d bits, got %d", t.Name, t.BType, limit, size)
+		}
+	case variant.VisibleString:
+		if size := len(v.StringValue()); int64(size) > limit {
+			return fmt.Errorf("%s: length %d exceeds %s", t.Name, size, t.BType)
+		}
+	case variant.MMSString:
+		if size := utf8.RuneCountInString(v.StringValue()); int64(size) > limit {
+			return fmt.Errorf("%s: length %d exceeds %s", t.Name, size, t.BType)
+		}
+	case variant.OctetString:
+		if size := len(v.OctetString()); int64(size) > limit {
+			return fmt.Errorf("%s: length %d exceeds %s", t.Name, size, t.BType)
+		}
+	}
+	return nil
+}
+
+// basicTypes сопоставляет базовый тип SCL с типом Variant и ограничением:
+// максимальным значением для целых (INT64 и INT64U не проверяются - их диапазон
+// совпадает с типом Variant), числом бит для bit-string, длиной для строк
+var basicTypes = map[string]struct {
+	typ   variant.Type
+	limit int64
+}{
+	"BOOLEAN":      {variant.Bool, 0},
+	"INT8":         {variant.Int32, math.MaxInt8},
+	"INT16":        {variant.Int32, math.MaxInt16},
+	"INT32":        {variant.Int32, math.MaxInt32},
+	"INT64":        {variant.Int64, math.MaxInt64},
+	"Enum":         {variant.Int32, math.MaxInt32},
+	"INT8U":        {variant.Uint32, math.MaxUint8},
+	"INT16U":       {variant.Uint32, math.MaxUint16},
+	"INT24U":       {variant.Uint32, 1<<24 - 1},
+	"INT32U":       {variant.Uint32, math.MaxUint32},
+	"INT64U":       {variant.Uint64, math.MaxInt64},
+	"FLOAT32":      {variant.Float32, 0},
+	"Timestamp":    {variant.UTCTime, 0},
+	"Quality":      {variant.BitString, 13},
+	"Dbpos":        {variant.BitString, 2},
+	"Tcmd":         {variant.BitString, 2},
+	"Check":        {variant.BitString, 2},
+	"VisString32":  {variant.VisibleString, 32},
+	"VisString64":  {variant.VisibleString, 64},
+	"VisString65":  {variant.VisibleString, 65},
+	"VisString129": {variant.VisibleString, 129},
+	"VisString255": {variant.VisibleString, 255},
+	"Unicode255":   {variant.MMSString, 255},
+	"Octet64":      {variant.OctetString, 64},
+}
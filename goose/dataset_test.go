@@ -0,0 +1,68 @@
+package goose
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/slonegd/go61850/osi/mms/variant"
+	"github.com/slonegd/go61850/scl"
+	"github.com/stretchr/testify/assert"
+)
+
+func analogueValue(f float32) *variant.Variant {
+	return variant.NewStructureVariant([]*variant.Variant{
+		variant.NewStructureVariant([]*variant.Variant{variant.NewFloat32Variant(f)}),
+		variant.NewBitStringVariant([]byte{0x00, 0x00}, 13),
+		variant.NewUTCTimeVariant(time.Unix(0, 0)),
+	})
+}
+
+func TestDataSet(t *testing.T) {
+	s, err := scl.ParseFile("../scl/testdata/simpleIO.scd")
+	assert.NoError(t, err)
+
+	ds, err := NewDataSet(s, "simpleIO", "GenericIO", "Events")
+	assert.NoError(t, err)
+	assert.Equal(t, "simpleIOGenericIO/LLN0$Events", ds.Name)
+	assert.Len(t, ds.Members, 2)
+	assert.Equal(t, "simpleIOGenericIO/GGIO1.AnIn2", ds.Members[1].Reference)
+
+	allData, err := ds.EncodeAllData([]*variant.Variant{analogueValue(1), analogueValue(2)})
+	assert.NoError(t, err)
+	// allData (ab) содержит две структуры (a2)
+	assert.Equal(t, byte(0xAB), allData[0])
+	assert.Equal(t, len(allData)-2, int(allData[1]))
+	assert.Equal(t, byte(0xA2), allData[2])
+
+	err = ds.Validate([]*variant.Variant{analogueValue(1)})
+	assert.EqualError(t, err, "dataset simpleIOGenericIO/LLN0$Events has 2 members, got 1 values")
+
+	bad := analogueValue(1)
+	bad.Structure()[0].Structure()[0] = variant.NewInt32Variant(1)
+	_, err = ds.EncodeAllData([]*variant.Variant{analogueValue(1), bad})
+	assert.EqualError(t, err, "simpleIOGenericIO/GGIO1.AnIn2 [MX]: AnIn2.mag.f: bType FLOAT32 expects float32, got int32")
+
+	bad = analogueValue(1)
+	bad.Structure()[1] = variant.NewBitStringVariant([]byte{0x00}, 2)
+	err = ds.Validate([]*variant.Variant{bad, analogueValue(1)})
+	assert.EqualError(t, err, "simpleIOGenericIO/GGIO1.AnIn1 [MX]: AnIn1.q: bType Quality expects 13 bits, got 2")
+
+	_, err = NewDataSet(s, "simpleIO", "GenericIO", "Missing")
+	assert.EqualError(t, err, "DataSet Missing not found in simpleIOGenericIO")
+}
+
+func TestCheckValueRange(t *testing.T) {
+	err := checkValue(scl.Type{Name: "stVal", BType: "INT8"}, variant.NewInt32Variant(200))
+	assert.EqualError(t, err, "stVal: value 200 out of range of INT8")
+	err = checkValue(scl.Type{Name: "cnt", BType: "INT16U"}, variant.NewUint32Variant(70000))
+	assert.EqualError(t, err, "cnt: value 70000 out of range of INT16U")
+	err = checkValue(scl.Type{Name: "d", BType: "VisString32"}, variant.NewVisibleStringVariant("0123456789012345678901234567890123"))
+	assert.EqualError(t, err, "d: length 34 exceeds VisString32")
+	assert.NoError(t, checkValue(scl.Type{Name: "stVal", BType: "INT8"}, variant.NewInt32Variant(-128)))
+
+	assert.NoError(t, checkValue(scl.Type{Name: "actVal", BType: "INT64"}, variant.NewInt64Variant(math.MinInt64)))
+	assert.NoError(t, checkValue(scl.Type{Name: "cnt", BType: "INT64U"}, variant.NewUint64Variant(math.MaxUint64)))
+	err = checkValue(scl.Type{Name: "actVal", BType: "INT64"}, variant.NewInt32Variant(1))
+	assert.EqualError(t, err, "actVal: bType INT64 expects int64, got int32")
+}
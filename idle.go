@@ -0,0 +1,42 @@
+package go61850
+
+import (
+	"context"
+	"time"
+)
+
+// LastActivity возвращает время приёма последнего TPKT от сервера.
+// Безопасен для вызова из другой горутины, в том числе во время запроса.
+func (c *MmsClient) LastActivity() time.Time {
+	return c.mmsClient.LastActivity()
+}
+
+// WatchIdle следит за приёмом TPKT и вызывает onIdle, если сервер молчит дольше threshold.
+// onIdle вызывается один раз за период молчания и получает его длительность; после
+// приёма следующего пакета сторож взводится снова. В onIdle обычно отправляют запрос
+// для проверки связи или закрывают транспорт, чтобы Reconnector установил ассоциацию
+// заново. Блокируется до отмены ctx и возвращает ctx.Err().
+func (c *MmsClient) WatchIdle(ctx context.Context, threshold time.Duration, onIdle func(idle time.Duration)) error {
+	interval := max(threshold/4, time.Millisecond)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	// Молчание отсчитывается не раньше запуска сторожа
+	started := time.Now()
+	var fired time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case now := <-ticker.C:
+			last := c.LastActivity()
+			if last.Before(started) {
+				last = started
+			}
+			if idle := now.Sub(last); idle > threshold && !last.Equal(fired) {
+				fired = last
+				onIdle(idle)
+			}
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package go61850
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/slonegd/go61850/mmstest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatchIdle(t *testing.T) {
+	f, err := os.Open("mmstest/testdata/read.txt")
+	assert.NoError(t, err)
+	defer f.Close()
+	exchanges, err := mmstest.ParseTranscript(f)
+	assert.NoError(t, err)
+
+	server := mmstest.NewTranscriptServer(t, exchanges[0], exchanges[1])
+	conn := server.Dial()
+	ctx := context.Background()
+	client, err := NewMmsClient(ctx, conn)
+	assert.NoError(t, err)
+	_, err = client.Initiate(ctx)
+	assert.NoError(t, err)
+
+	last := client.LastActivity()
+	assert.False(t, last.IsZero())
+	assert.WithinDuration(t, time.Now(), last, time.Second)
+
+	watchCtx, cancel := context.WithCancel(ctx)
+	idle := make(chan time.Duration, 10)
+	done := make(chan error, 1)
+	go func() {
+		done <- client.WatchIdle(watchCtx, 20*time.Millisecond, func(d time.Duration) { idle <- d })
+	}()
+
+	select {
+	case d := <-idle:
+		assert.Greater(t, d, 20*time.Millisecond)
+	case <-time.After(time.Second):
+		t.Fatal("onIdle not called")
+	}
+	// Без новых пакетов сторож не срабатывает повторно
+	time.Sleep(60 * time.Millisecond)
+	assert.Empty(t, idle)
+
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+	conn.Close()
+	assert.NoError(t, server.Wait())
+}
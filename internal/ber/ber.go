@@ -17,12 +17,82 @@ var (
 	ErrInvalidLength     = errors.New("invalid length")
 	ErrInvalidIndefinite = errors.New("invalid indefinite length")
 	ErrMaxDepthExceeded  = errors.New("maximum depth exceeded")
+	ErrLengthTooLarge    = errors.New("length too large for BER encoding")
+	ErrIndefiniteLength  = errors.New("indefinite length not allowed")
+	ErrNotDER            = errors.New("not DER encoded")
+	ErrInvalidOID        = errors.New("invalid object identifier")
 )
 
-// ItuObjectIdentifier represents an ITU-T Object Identifier
+// MaxLength is the largest length supported by the encoder (4-byte long form)
+const MaxLength = 0xffffffff
+
+// ItuObjectIdentifier represents an ITU-T Object Identifier.
+// Arc holds all arcs of the identifier; the number of arcs is not limited.
 type ItuObjectIdentifier struct {
-	Arc      [10]uint32
-	ArcCount int
+	Arc []uint64
+}
+
+// NewOID returns an object identifier with the given arcs
+func NewOID(arcs ...uint64) ItuObjectIdentifier {
+	return ItuObjectIdentifier{Arc: arcs}
+}
+
+// String returns the dotted form of the identifier, e.g. "1.0.9506.2.1"
+func (oid ItuObjectIdentifier) String() string {
+	var builder strings.Builder
+	for i, arc := range oid.Arc {
+		if i > 0 {
+			builder.WriteByte('.')
+		}
+		builder.WriteString(strconv.FormatUint(arc, 10))
+	}
+	return builder.String()
+}
+
+// Equal reports whether both identifiers have the same arcs
+func (oid ItuObjectIdentifier) Equal(other ItuObjectIdentifier) bool {
+	if len(oid.Arc) != len(other.Arc) {
+		return false
+	}
+	for i := range oid.Arc {
+		if oid.Arc[i] != other.Arc[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Bytes encodes the identifier contents (without tag and length).
+// The first two arcs are combined into one subidentifier (X.690 8.19.4).
+func (oid ItuObjectIdentifier) Bytes() []byte {
+	if len(oid.Arc) == 0 {
+		return nil
+	}
+	first := oid.Arc[0] * 40
+	if len(oid.Arc) > 1 {
+		first += oid.Arc[1]
+	}
+	buffer := appendSubidentifier(nil, first)
+	for i := 2; i < len(oid.Arc); i++ {
+		buffer = appendSubidentifier(buffer, oid.Arc[i])
+	}
+	return buffer
+}
+
+// appendSubidentifier appends value in base 128 with continuation bits
+func appendSubidentifier(dst []byte, value uint64) []byte {
+	size := 1
+	for v := value >> 7; v > 0; v >>= 7 {
+		size++
+	}
+	for i := size - 1; i >= 0; i-- {
+		b := byte(value>>(7*uint(i))) & 0x7f
+		if i > 0 {
+			b |= 0x80
+		}
+		dst = append(dst, b)
+	}
+	return dst
 }
 
 // Asn1PrimitiveValue represents an ASN.1 primitive value
@@ -95,31 +165,28 @@ func decodeLengthRecursive(buffer []byte, bufPos, maxBufPos, depth, maxDepth int
 	len1 := buffer[bufPos]
 	bufPos++
 
-	if len1&0x80 != 0 {
+	switch {
+	case len1 < 0x80:
+		length = int(len1)
+	case len1 == 0x80:
+		// indefinite length form: length covers the contents and the end-of-contents octets
+		length, err = getIndefiniteLength(buffer, bufPos, maxBufPos, depth, maxDepth)
+		if err != nil {
+			return -1, 0, err
+		}
+	default:
+		// 0xff is reserved (X.690 8.1.3.5), lengths above 4 octets are not supported
 		lenLength := int(len1 & 0x7f)
-
-		if lenLength == 0 {
-			// indefinite length form
-			indefLength, err := getIndefiniteLength(buffer, bufPos, maxBufPos, depth, maxDepth)
-			if err != nil {
-				return -1, 0, err
-			}
-			length = indefLength
-		} else {
-			length = 0
-			for i := 0; i < lenLength; i++ {
-				if bufPos >= maxBufPos {
-					return -1, 0, ErrBufferOverflow
-				}
-				if bufPos+length > maxBufPos {
-					return -1, 0, ErrBufferOverflow
-				}
-				length = (length << 8) | int(buffer[bufPos])
-				bufPos++
-			}
+		if bufPos+lenLength > maxBufPos {
+			return -1, 0, ErrBufferOverflow
+		}
+		if lenLength > 4 {
+			return -1, 0, ErrInvalidLength
+		}
+		for i := 0; i < lenLength; i++ {
+			length = (length << 8) | int(buffer[bufPos])
+			bufPos++
 		}
-	} else {
-		length = int(len1)
 	}
 
 	if length < 0 {
@@ -133,39 +200,152 @@ func decodeLengthRecursive(buffer []byte, bufPos, maxBufPos, depth, maxDepth int
 	return bufPos, length, nil
 }
 
+// getIndefiniteLength scans the TLV elements of indefinite length contents
+// starting at bufPos up to the end-of-contents octets (00 00).
+// Returns the length of the contents including the end-of-contents octets.
 func getIndefiniteLength(buffer []byte, bufPos, maxBufPos, depth, maxDepth int) (int, error) {
 	depth++
 	if depth > maxDepth {
 		return -1, ErrMaxDepthExceeded
 	}
 
-	length := 0
+	start := bufPos
 	for bufPos < maxBufPos {
-		if bufPos+1 < maxBufPos && buffer[bufPos] == 0 && buffer[bufPos+1] == 0 {
-			return length + 2, nil
+		if buffer[bufPos] == 0 {
+			if bufPos+1 < maxBufPos && buffer[bufPos+1] == 0 {
+				return bufPos + 2 - start, nil
+			}
+			return -1, ErrInvalidIndefinite
 		}
 
-		length++
-
-		if (buffer[bufPos] & 0x1f) == 0x1f {
-			// handle extended tags
-			bufPos++
-			length++
+		tagPos := bufPos
+		var err error
+		bufPos, err = skipTag(buffer, bufPos, maxBufPos)
+		if err != nil {
+			return -1, err
+		}
+		if bufPos >= maxBufPos {
+			return -1, ErrBufferOverflow
+		}
+		// indefinite length is only allowed for constructed encodings (X.690 8.1.3.2)
+		if buffer[bufPos] == 0x80 && buffer[tagPos]&0x20 == 0 {
+			return -1, ErrInvalidIndefinite
 		}
 
-		subLength := -1
-		newBufPos, subLength, err := decodeLengthRecursive(buffer, bufPos, maxBufPos, depth, maxDepth)
+		newPos, subLength, err := decodeLengthRecursive(buffer, bufPos, maxBufPos, depth, maxDepth)
 		if err != nil {
 			return -1, err
 		}
-
-		length += subLength + (newBufPos - bufPos)
-		bufPos = newBufPos + subLength
+		bufPos = newPos + subLength
 	}
 
 	return -1, ErrInvalidIndefinite
 }
 
+// skipTag returns the position after the identifier octets starting at bufPos,
+// including the subsequent octets of high tag numbers (X.690 8.1.2.4)
+func skipTag(buffer []byte, bufPos, maxBufPos int) (int, error) {
+	if bufPos >= maxBufPos {
+		return -1, ErrBufferOverflow
+	}
+	tag := buffer[bufPos]
+	bufPos++
+	if tag&0x1f != 0x1f {
+		return bufPos, nil
+	}
+	for bufPos < maxBufPos {
+		b := buffer[bufPos]
+		bufPos++
+		if b&0x80 == 0 {
+			return bufPos, nil
+		}
+	}
+	return -1, ErrBufferOverflow
+}
+
+// CheckDefiniteLength verifies that the BER element at the start of buffer and all
+// nested constructed elements use definite lengths only (DER-like strictness).
+// Returns ErrIndefiniteLength for indefinite length forms.
+func CheckDefiniteLength(buffer []byte) error {
+	return checkEncoding(buffer, 0, len(buffer), 0, false)
+}
+
+// CheckDER verifies that the BER element at the start of buffer and all nested
+// constructed elements follow the DER rules checkable without the ASN.1 schema:
+// definite minimal lengths (X.690 10.1), minimal universal INTEGER and ENUMERATED
+// (8.3.2) and BOOLEAN TRUE encoded as 0xFF (11.1).
+// Returns an error wrapping ErrNotDER or ErrIndefiniteLength.
+func CheckDER(buffer []byte) error {
+	return checkEncoding(buffer, 0, len(buffer), 0, true)
+}
+
+func checkEncoding(buffer []byte, bufPos, maxBufPos, depth int, der bool) error {
+	if depth > maxDepth {
+		return ErrMaxDepthExceeded
+	}
+	for bufPos < maxBufPos {
+		tagPos := bufPos
+		var err error
+		bufPos, err = skipTag(buffer, bufPos, maxBufPos)
+		if err != nil {
+			return err
+		}
+		if bufPos >= maxBufPos {
+			return ErrBufferOverflow
+		}
+		if buffer[bufPos] == 0x80 {
+			return ErrIndefiniteLength
+		}
+		if der {
+			if err := checkMinimalLength(buffer, bufPos, maxBufPos); err != nil {
+				return err
+			}
+		}
+		newPos, length, err := DecodeLength(buffer, bufPos, maxBufPos)
+		if err != nil {
+			return err
+		}
+		content := buffer[newPos : newPos+length]
+		switch {
+		case buffer[tagPos]&0x20 != 0:
+			if err := checkEncoding(buffer, newPos, newPos+length, depth+1, der); err != nil {
+				return err
+			}
+		case der && (Tag(buffer[tagPos]) == Integer || Tag(buffer[tagPos]) == Enumerated):
+			if len(content) == 0 ||
+				len(content) > 1 && (content[0] == 0x00 && content[1]&0x80 == 0 || content[0] == 0xff && content[1]&0x80 != 0) {
+				return fmt.Errorf("%w: non-minimal integer at %d", ErrNotDER, tagPos)
+			}
+		case der && Tag(buffer[tagPos]) == Boolean:
+			if len(content) != 1 || content[0] != 0x00 && content[0] != 0xff {
+				return fmt.Errorf("%w: invalid boolean at %d", ErrNotDER, tagPos)
+			}
+		}
+		bufPos = newPos + length
+		if depth == 0 {
+			return nil
+		}
+	}
+	return nil
+}
+
+// checkMinimalLength verifies that the length octets at bufPos use the shortest form
+func checkMinimalLength(buffer []byte, bufPos, maxBufPos int) error {
+	len1 := buffer[bufPos]
+	if len1 < 0x80 {
+		return nil
+	}
+	lenLength := int(len1 & 0x7f)
+	if bufPos+1+lenLength > maxBufPos {
+		return ErrBufferOverflow
+	}
+	first := buffer[bufPos+1]
+	if lenLength == 1 && first < 0x80 || lenLength > 1 && first == 0 {
+		return fmt.Errorf("%w: non-minimal length at %d", ErrNotDER, bufPos)
+	}
+	return nil
+}
+
 // DecodeString decodes a BER string from the buffer
 func DecodeString(buffer []byte, strlen, bufPos, maxBufPos int) (string, error) {
 	if maxBufPos-bufPos < 0 {
@@ -255,39 +435,53 @@ func DecodeBoolean(buffer []byte, bufPos int) bool {
 	return buffer[bufPos] != 0
 }
 
-// DecodeOID decodes a BER Object Identifier from the buffer
-func DecodeOID(buffer []byte, bufPos, length int, oid *ItuObjectIdentifier) {
-	startPos := bufPos
-	currentArc := 0
-
-	// clear all arcs
-	for i := 0; i < 10; i++ {
-		oid.Arc[i] = 0
+// DecodeOID decodes a BER Object Identifier from the buffer.
+// Arcs of any count and values up to 2^64-1 are supported; a truncated
+// subidentifier or an arc that does not fit into uint64 yields ErrInvalidOID.
+func DecodeOID(buffer []byte, bufPos, length int, oid *ItuObjectIdentifier) error {
+	oid.Arc = nil
+	if length == 0 {
+		return nil
 	}
-
-	// parse first two arcs
-	if length > 0 {
-		oid.Arc[0] = uint32(buffer[bufPos] / 40)
-		oid.Arc[1] = uint32(buffer[bufPos] % 40)
-		currentArc = 2
-		bufPos++
+	if bufPos < 0 || length < 0 || bufPos+length > len(buffer) {
+		return ErrBufferOverflow
 	}
 
-	// parse remaining arcs
-	for (bufPos-startPos < length) && (currentArc < 10) {
-		oid.Arc[currentArc] = oid.Arc[currentArc] << 7
-
-		if buffer[bufPos] < 0x80 {
-			oid.Arc[currentArc] += uint32(buffer[bufPos])
-			currentArc++
-		} else {
-			oid.Arc[currentArc] += uint32(buffer[bufPos] & 0x7f)
+	end := bufPos + length
+	for bufPos < end {
+		var value uint64
+		for {
+			if bufPos >= end {
+				oid.Arc = nil
+				return fmt.Errorf("%w: truncated subidentifier", ErrInvalidOID)
+			}
+			if value>>57 != 0 {
+				oid.Arc = nil
+				return fmt.Errorf("%w: arc exceeds 64 bits", ErrInvalidOID)
+			}
+			b := buffer[bufPos]
+			bufPos++
+			value = value<<7 | uint64(b&0x7f)
+			if b < 0x80 {
+				break
+			}
 		}
 
-		bufPos++
+		if oid.Arc == nil {
+			// the first subidentifier encodes the first two arcs
+			switch {
+			case value < 40:
+				oid.Arc = append(oid.Arc, 0, value)
+			case value < 80:
+				oid.Arc = append(oid.Arc, 1, value-40)
+			default:
+				oid.Arc = append(oid.Arc, 2, value-80)
+			}
+			continue
+		}
+		oid.Arc = append(oid.Arc, value)
 	}
-
-	oid.ArcCount = currentArc
+	return nil
 }
 
 // DecodeBitmaskFromBytes decodes a BER bit string from bytes and returns a list of set bit offsets.
@@ -303,7 +497,11 @@ func DecodeBitmaskFromBytes(bitmask []byte, paddingBits byte, bitmaskSize int) [
 	
 	var offsets []uint
 	totalBits := bitmaskSize*8 - int(paddingBits)
-	
+	if totalBits <= 0 {
+		// padding больше длины маски: некорректный bit-string
+		return nil
+	}
+
 	for bitIndex := uint(0); bitIndex < uint(totalBits); bitIndex++ {
 		byteIndex := int(bitIndex / 8)
 		bitOffsetInByte := 7 - int(bitIndex%8) // BER: MSB first (bit 0 is leftmost)
@@ -321,36 +519,34 @@ func DecodeBitmaskFromBytes(bitmask []byte, paddingBits byte, bitmaskSize int) [
 // Encoder functions
 
 // EncodeLength encodes a length value in BER format
+// The buffer must have room for DetermineLengthSize(length) bytes.
 // Returns the new buffer position
 func EncodeLength(length uint32, buffer []byte, bufPos int) int {
-	if length < 128 {
-		buffer[bufPos] = byte(length)
-		bufPos++
-	} else if length < 256 {
-		buffer[bufPos] = 0x81
-		bufPos++
+	size := DetermineLengthSize(length)
+	if size == 1 {
 		buffer[bufPos] = byte(length)
-		bufPos++
-	} else if length < 65536 {
-		buffer[bufPos] = 0x82
-		bufPos++
-		buffer[bufPos] = byte(length / 256)
-		bufPos++
-		buffer[bufPos] = byte(length % 256)
-		bufPos++
-	} else {
-		buffer[bufPos] = 0x83
-		bufPos++
-		buffer[bufPos] = byte(length / 0x10000)
-		bufPos++
-		buffer[bufPos] = byte((length & 0xffff) / 0x100)
-		bufPos++
-		buffer[bufPos] = byte(length % 256)
+		return bufPos + 1
+	}
+	buffer[bufPos] = 0x80 | byte(size-1)
+	bufPos++
+	for i := size - 2; i >= 0; i-- {
+		buffer[bufPos] = byte(length >> (8 * i))
 		bufPos++
 	}
 	return bufPos
 }
 
+// AppendLength appends a BER length to dst.
+// Unlike EncodeLength it never panics: lengths above MaxLength return ErrLengthTooLarge.
+func AppendLength(dst []byte, length uint64) ([]byte, error) {
+	if length > MaxLength {
+		return dst, fmt.Errorf("%w: %d", ErrLengthTooLarge, length)
+	}
+	var buffer [5]byte
+	n := EncodeLength(uint32(length), buffer[:], 0)
+	return append(dst, buffer[:n]...), nil
+}
+
 // EncodeTL encodes a Tag and Length in BER format
 func EncodeTL(tag Tag, length uint32, buffer []byte, bufPos int) int {
 	buffer[bufPos] = byte(tag)
@@ -365,7 +561,8 @@ func EncodeBoolean(tag Tag, value bool, buffer []byte, bufPos int) int {
 	buffer[bufPos] = 1
 	bufPos++
 	if value {
-		buffer[bufPos] = 0x01
+		// DER requires all bits set for TRUE (X.690 11.1)
+		buffer[bufPos] = 0xff
 	} else {
 		buffer[bufPos] = 0x00
 	}
@@ -559,16 +756,10 @@ func EncodeUInt32(value uint32, buffer []byte, bufPos int) int {
 }
 
 // EncodeInt32 encodes a signed 32-bit integer in BER format
+// (minimal two's complement representation, X.690 8.3.2)
 func EncodeInt32(value int32, buffer []byte, bufPos int) int {
-	valueArray := make([]byte, 4)
-	binary.BigEndian.PutUint32(valueArray, uint32(value))
-
 	valueBuffer := make([]byte, 4)
-	copy(valueBuffer, valueArray)
-
-	if isLittleEndian() {
-		RevertByteOrder(valueBuffer)
-	}
+	binary.BigEndian.PutUint32(valueBuffer, uint32(value))
 
 	size := CompressInteger(valueBuffer)
 
@@ -582,16 +773,8 @@ func EncodeInt32(value int32, buffer []byte, bufPos int) int {
 
 // EncodeUInt32WithTL encodes an unsigned 32-bit integer with tag and length in BER format
 func EncodeUInt32WithTL(tag Tag, value uint32, buffer []byte, bufPos int) int {
-	valueArray := make([]byte, 4)
-	binary.BigEndian.PutUint32(valueArray, value)
-
 	valueBuffer := make([]byte, 5)
-	valueBuffer[0] = 0
-	copy(valueBuffer[1:], valueArray)
-
-	if isLittleEndian() {
-		RevertByteOrder(valueBuffer[1:])
-	}
+	binary.BigEndian.PutUint32(valueBuffer[1:], value)
 
 	size := CompressInteger(valueBuffer)
 
@@ -630,33 +813,15 @@ func EncodeFloat(floatValue []byte, formatWidth, exponentWidth byte, buffer []by
 
 // UInt32DetermineEncodedSize determines the encoded size of an unsigned 32-bit integer
 func UInt32DetermineEncodedSize(value uint32) int {
-	valueArray := make([]byte, 4)
-	binary.BigEndian.PutUint32(valueArray, value)
-
 	valueBuffer := make([]byte, 5)
-	valueBuffer[0] = 0
-	copy(valueBuffer[1:], valueArray)
-
-	if isLittleEndian() {
-		RevertByteOrder(valueBuffer[1:])
-	}
-
+	binary.BigEndian.PutUint32(valueBuffer[1:], value)
 	return CompressInteger(valueBuffer)
 }
 
 // Int32DetermineEncodedSize determines the encoded size of a signed 32-bit integer
 func Int32DetermineEncodedSize(value int32) int {
-	valueArray := make([]byte, 4)
-	binary.BigEndian.PutUint32(valueArray, uint32(value))
-
-	valueBuffer := make([]byte, 5)
-	valueBuffer[0] = 0
-	copy(valueBuffer[1:], valueArray)
-
-	if isLittleEndian() {
-		RevertByteOrder(valueBuffer[1:])
-	}
-
+	valueBuffer := make([]byte, 4)
+	binary.BigEndian.PutUint32(valueBuffer, uint32(value))
 	return CompressInteger(valueBuffer)
 }
 
@@ -671,7 +836,10 @@ func DetermineLengthSize(length uint32) int {
 	if length < 65536 {
 		return 3
 	}
-	return 4
+	if length < 1<<24 {
+		return 4
+	}
+	return 5
 }
 
 // DetermineEncodedStringSize determines the encoded size of a string
@@ -2,6 +2,9 @@ package ber
 
 import (
 	"bytes"
+	"encoding/hex"
+	"errors"
+	"strings"
 	"testing"
 )
 
@@ -88,6 +91,176 @@ func TestDecodeLength(t *testing.T) {
 	}
 }
 
+// TestDecodeIndefiniteLength проверяет неопределённую форму длины (X.690 8.1.3.6):
+// длина включает содержимое и октеты конца содержимого 00 00
+func TestDecodeIndefiniteLength(t *testing.T) {
+	tests := []struct {
+		name    string
+		hex     string
+		wantPos int
+		wantLen int
+		wantErr error
+	}{
+		{name: "definite long form not minimal", hex: "30 82 00 03 02 01 05", wantPos: 4, wantLen: 3},
+		{name: "simple", hex: "30 80 02 01 05 00 00", wantPos: 2, wantLen: 5},
+		{name: "empty", hex: "30 80 00 00", wantPos: 2, wantLen: 2},
+		{name: "nested indefinite", hex: "30 80 30 80 02 01 01 00 00 04 00 00 00", wantPos: 2, wantLen: 11},
+		{name: "high tag number", hex: "30 80 1f 81 00 01 ff 00 00", wantPos: 2, wantLen: 7},
+		{name: "nested high tag number", hex: "30 80 3f 81 00 80 1f 22 01 ff 00 00 00 00", wantPos: 2, wantLen: 12},
+		{name: "definite long form inside", hex: "30 80 04 81 02 aa bb 00 00", wantPos: 2, wantLen: 7},
+		{name: "missing end-of-contents", hex: "30 80 02 01 05", wantErr: ErrInvalidIndefinite},
+		{name: "single zero octet", hex: "30 80 02 01 05 00", wantErr: ErrInvalidIndefinite},
+		{name: "primitive with indefinite length", hex: "30 80 04 80 00 00 00 00", wantErr: ErrInvalidIndefinite},
+		{name: "truncated high tag number", hex: "30 80 1f 81", wantErr: ErrBufferOverflow},
+		{name: "length of 5 octets", hex: "30 85 00 00 00 00 01 00", wantErr: ErrInvalidLength},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buffer, err := hex.DecodeString(strings.ReplaceAll(tt.hex, " ", ""))
+			if err != nil {
+				t.Fatal(err)
+			}
+			gotPos, gotLen, err := DecodeLength(buffer, 1, len(buffer))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("DecodeLength() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if gotPos != tt.wantPos || gotLen != tt.wantLen {
+				t.Errorf("DecodeLength() = %d, %d, want %d, %d", gotPos, gotLen, tt.wantPos, tt.wantLen)
+			}
+		})
+	}
+
+	// Вложенность ограничена maxDepth
+	deep := bytes.Repeat([]byte{0x30, 0x80}, maxDepth+2)
+	deep = append(deep, make([]byte, 2*(maxDepth+2))...)
+	if _, _, err := DecodeLength(deep, 1, len(deep)); !errors.Is(err, ErrMaxDepthExceeded) {
+		t.Errorf("DecodeLength() error = %v, want ErrMaxDepthExceeded", err)
+	}
+}
+
+func TestCheckDefiniteLength(t *testing.T) {
+	tests := []struct {
+		name    string
+		hex     string
+		wantErr error
+	}{
+		{name: "definite", hex: "a1 08 02 01 01 a4 03 80 01 0a"},
+		{name: "primitive content like indefinite", hex: "04 02 80 00"},
+		{name: "indefinite outer", hex: "30 80 02 01 05 00 00", wantErr: ErrIndefiniteLength},
+		{name: "indefinite nested", hex: "a1 0b 02 01 01 a4 80 80 01 0a 00 00 00", wantErr: ErrIndefiniteLength},
+		{name: "truncated", hex: "a1 08 02 01", wantErr: ErrBufferOverflow},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buffer, err := hex.DecodeString(strings.ReplaceAll(tt.hex, " ", ""))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := CheckDefiniteLength(buffer); !errors.Is(err, tt.wantErr) {
+				t.Errorf("CheckDefiniteLength() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckDER(t *testing.T) {
+	tests := []struct {
+		name    string
+		hex     string
+		wantErr error
+	}{
+		{name: "minimal", hex: "30 0d 02 01 7f 02 02 00 80 01 01 ff 0a 01 00"},
+		{name: "negative", hex: "30 06 02 01 80 02 01 ff"},
+		{name: "long form length", hex: "04 81 80" + strings.Repeat(" 00", 128)},
+		{name: "context tags not checked", hex: "a0 04 80 02 00 01"},
+		{name: "non-minimal length 1 octet", hex: "04 81 01 00", wantErr: ErrNotDER},
+		{name: "non-minimal length leading zero", hex: "30 82 00 03 02 01 05", wantErr: ErrNotDER},
+		{name: "non-minimal positive integer", hex: "30 04 02 02 00 7f", wantErr: ErrNotDER},
+		{name: "non-minimal negative integer", hex: "30 04 02 02 ff 80", wantErr: ErrNotDER},
+		{name: "empty integer", hex: "02 00", wantErr: ErrNotDER},
+		{name: "boolean true 0x01", hex: "30 03 01 01 01", wantErr: ErrNotDER},
+		{name: "indefinite", hex: "30 80 02 01 05 00 00", wantErr: ErrIndefiniteLength},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buffer, err := hex.DecodeString(strings.ReplaceAll(tt.hex, " ", ""))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := CheckDER(buffer); !errors.Is(err, tt.wantErr) {
+				t.Errorf("CheckDER() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestIntegerRoundTrip проверяет, что целые кодируются минимально (DER),
+// размер совпадает с функциями определения размера и значение декодируется обратно
+func TestIntegerRoundTrip(t *testing.T) {
+	signed := []int32{0, 1, 127, 128, 255, 256, 32767, 32768, 1 << 23, 1<<31 - 1,
+		-1, -128, -129, -32768, -32769, -1 << 31}
+	for _, value := range signed {
+		buffer := make([]byte, 8)
+		buffer[0] = byte(Integer)
+		size := EncodeInt32(value, buffer, 2) - 2
+		buffer[1] = byte(size)
+		if size != Int32DetermineEncodedSize(value) {
+			t.Errorf("Int32DetermineEncodedSize(%d) = %d, encoded %d", value, Int32DetermineEncodedSize(value), size)
+		}
+		if got := DecodeInt32(buffer, size, 2); got != value {
+			t.Errorf("DecodeInt32(EncodeInt32(%d)) = %d", value, got)
+		}
+		if err := CheckDER(buffer[:2+size]); err != nil {
+			t.Errorf("EncodeInt32(%d) = % x: %v", value, buffer[2:2+size], err)
+		}
+	}
+
+	unsigned := []uint32{0, 1, 127, 128, 255, 256, 65535, 65536, 1<<31 - 1, 1 << 31, 1<<32 - 1}
+	for _, value := range unsigned {
+		buffer := make([]byte, 8)
+		buffer[0] = byte(Integer)
+		size := EncodeUInt32(value, buffer, 2) - 2
+		buffer[1] = byte(size)
+		if size != UInt32DetermineEncodedSize(value) {
+			t.Errorf("UInt32DetermineEncodedSize(%d) = %d, encoded %d", value, UInt32DetermineEncodedSize(value), size)
+		}
+		if got := DecodeUint32(buffer, size, 2); got != value {
+			t.Errorf("DecodeUint32(EncodeUInt32(%d)) = %d", value, got)
+		}
+		if err := CheckDER(buffer[:2+size]); err != nil {
+			t.Errorf("EncodeUInt32(%d) = % x: %v", value, buffer[2:2+size], err)
+		}
+		withTL := make([]byte, 8)
+		if pos := EncodeUInt32WithTL(Integer, value, withTL, 0); !bytes.Equal(withTL[:pos], buffer[:2+size]) {
+			t.Errorf("EncodeUInt32WithTL(%d) = % x, want % x", value, withTL[:pos], buffer[:2+size])
+		}
+	}
+}
+
+// TestLengthRoundTrip проверяет минимальность и обратимость кодирования длин
+func TestLengthRoundTrip(t *testing.T) {
+	for _, length := range []uint32{0, 127, 128, 255, 256, 65535, 65536} {
+		buffer := make([]byte, 1+DetermineLengthSize(length)+int(length))
+		pos := EncodeTL(OctetString, length, buffer, 0)
+		if pos != 1+DetermineLengthSize(length) {
+			t.Errorf("EncodeTL(%d) length octets = %d, want %d", length, pos-1, DetermineLengthSize(length))
+		}
+		if err := CheckDER(buffer); err != nil {
+			t.Errorf("EncodeTL(%d): %v", length, err)
+		}
+		newPos, got, err := DecodeLength(buffer, 1, len(buffer))
+		if err != nil || newPos != pos || got != int(length) {
+			t.Errorf("DecodeLength(EncodeLength(%d)) = %d, %d, %v", length, newPos, got, err)
+		}
+	}
+}
+
 func TestDecodeString(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -288,45 +461,146 @@ func TestDecodeOID(t *testing.T) {
 		bufPos  int
 		length  int
 		wantOID ItuObjectIdentifier
+		wantErr error
 	}{
 		{
-			name:   "simple OID",
-			buffer: []byte{0x28, 0xca, 0x22, 0x02, 0x01},
-			bufPos: 0,
-			length: 5,
-			wantOID: ItuObjectIdentifier{
-				Arc:      [10]uint32{1, 0, 9506, 2, 1, 0, 0, 0, 0, 0},
-				ArcCount: 5,
-			},
+			name:    "simple OID",
+			buffer:  []byte{0x28, 0xca, 0x22, 0x02, 0x01},
+			bufPos:  0,
+			length:  5,
+			wantOID: NewOID(1, 0, 9506, 2, 1),
 		},
 		{
-			name:   "two arc OID",
-			buffer: []byte{0x52, 0x01},
-			bufPos: 0,
-			length: 2,
-			wantOID: ItuObjectIdentifier{
-				Arc:      [10]uint32{2, 2, 1, 0, 0, 0, 0, 0, 0, 0},
-				ArcCount: 3,
-			},
+			name:    "two arc OID",
+			buffer:  []byte{0x52, 0x01},
+			bufPos:  0,
+			length:  2,
+			wantOID: NewOID(2, 2, 1),
+		},
+		{
+			name:    "more than 10 arcs",
+			buffer:  []byte{0x2b, 0x06, 0x01, 0x04, 0x01, 0x82, 0x37, 0x3c, 0x03, 0x01, 0x02, 0x03, 0x04},
+			length:  13,
+			wantOID: NewOID(1, 3, 6, 1, 4, 1, 311, 60, 3, 1, 2, 3, 4),
+		},
+		{
+			name:    "arc above 2^28",
+			buffer:  []byte{0x2a, 0x8f, 0xff, 0xff, 0xff, 0x7f},
+			length:  6,
+			wantOID: NewOID(1, 2, 0xffffffff),
+		},
+		{
+			name:    "64-bit arc",
+			buffer:  []byte{0x2a, 0x81, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x7f},
+			length:  11,
+			wantOID: NewOID(1, 2, 0xffffffffffffffff),
+		},
+		{
+			name:    "multi-byte first subidentifier",
+			buffer:  []byte{0x81, 0x34, 0x03},
+			length:  3,
+			wantOID: NewOID(2, 100, 3),
+		},
+		{
+			name:    "with offset",
+			buffer:  []byte{0x06, 0x03, 0x51, 0x01, 0x00},
+			bufPos:  2,
+			length:  2,
+			wantOID: NewOID(2, 1, 1),
+		},
+		{
+			name:    "empty",
+			buffer:  []byte{},
+			wantOID: ItuObjectIdentifier{},
+		},
+		{
+			name:    "truncated subidentifier",
+			buffer:  []byte{0x2a, 0x86},
+			length:  2,
+			wantErr: ErrInvalidOID,
+		},
+		{
+			name:    "arc exceeds 64 bits",
+			buffer:  []byte{0x2a, 0x82, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x00},
+			length:  11,
+			wantErr: ErrInvalidOID,
+		},
+		{
+			name:    "length exceeds buffer",
+			buffer:  []byte{0x2a},
+			length:  2,
+			wantErr: ErrBufferOverflow,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			var oid ItuObjectIdentifier
-			DecodeOID(tt.buffer, tt.bufPos, tt.length, &oid)
-			if oid.ArcCount != tt.wantOID.ArcCount {
-				t.Errorf("DecodeOID() ArcCount = %v, want %v", oid.ArcCount, tt.wantOID.ArcCount)
+			err := DecodeOID(tt.buffer, tt.bufPos, tt.length, &oid)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("DecodeOID() error = %v, want %v", err, tt.wantErr)
 			}
-			for i := 0; i < oid.ArcCount; i++ {
-				if oid.Arc[i] != tt.wantOID.Arc[i] {
-					t.Errorf("DecodeOID() Arc[%d] = %v, want %v", i, oid.Arc[i], tt.wantOID.Arc[i])
-				}
+			if !oid.Equal(tt.wantOID) {
+				t.Errorf("DecodeOID() = %v, want %v", oid, tt.wantOID)
 			}
 		})
 	}
 }
 
+func TestOID(t *testing.T) {
+	tests := []struct {
+		name       string
+		oid        ItuObjectIdentifier
+		wantString string
+		wantBytes  []byte
+	}{
+		{
+			name:       "MMS abstract syntax",
+			oid:        NewOID(1, 0, 9506, 2, 1),
+			wantString: "1.0.9506.2.1",
+			wantBytes:  []byte{0x28, 0xca, 0x22, 0x02, 0x01},
+		},
+		{
+			name:       "large arcs",
+			oid:        NewOID(2, 100, 3, 0x10000000, 0xffffffffffffffff),
+			wantString: "2.100.3.268435456.18446744073709551615",
+			wantBytes: []byte{0x81, 0x34, 0x03, 0x81, 0x80, 0x80, 0x80, 0x00,
+				0x81, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x7f},
+		},
+		{
+			name:       "empty",
+			oid:        ItuObjectIdentifier{},
+			wantString: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.oid.String(); got != tt.wantString {
+				t.Errorf("String() = %q, want %q", got, tt.wantString)
+			}
+			got := tt.oid.Bytes()
+			if !bytes.Equal(got, tt.wantBytes) {
+				t.Errorf("Bytes() = % x, want % x", got, tt.wantBytes)
+			}
+			var decoded ItuObjectIdentifier
+			if err := DecodeOID(got, 0, len(got), &decoded); err != nil {
+				t.Fatalf("DecodeOID() error = %v", err)
+			}
+			if !decoded.Equal(tt.oid) {
+				t.Errorf("round trip = %v, want %v", decoded, tt.oid)
+			}
+		})
+	}
+
+	if NewOID(1, 0, 9506).Equal(NewOID(1, 0, 9506, 2)) {
+		t.Error("Equal() = true for OIDs of different length")
+	}
+	if NewOID(1, 0, 9506, 2, 1).Equal(NewOID(1, 0, 9506, 2, 3)) {
+		t.Error("Equal() = true for different OIDs")
+	}
+}
+
 func TestEncodeLength(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -368,6 +642,30 @@ func TestEncodeLength(t *testing.T) {
 			wantPos: 4,
 			wantBuf: []byte{0x83, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
 		},
+		{
+			name:    "long form 3 bytes max",
+			length:  0xFFFFFF,
+			buffer:  make([]byte, 10),
+			bufPos:  0,
+			wantPos: 4,
+			wantBuf: []byte{0x83, 0xFF, 0xFF, 0xFF, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+		},
+		{
+			name:    "long form 4 bytes",
+			length:  0x01000000,
+			buffer:  make([]byte, 10),
+			bufPos:  0,
+			wantPos: 5,
+			wantBuf: []byte{0x84, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+		},
+		{
+			name:    "long form 4 bytes max",
+			length:  0xFFFFFFFF,
+			buffer:  make([]byte, 10),
+			bufPos:  1,
+			wantPos: 6,
+			wantBuf: []byte{0x00, 0x84, 0xFF, 0xFF, 0xFF, 0xFF, 0x00, 0x00, 0x00, 0x00},
+		},
 	}
 
 	for _, tt := range tests {
@@ -383,6 +681,31 @@ func TestEncodeLength(t *testing.T) {
 	}
 }
 
+func TestAppendLength(t *testing.T) {
+	got, err := AppendLength([]byte{0x30}, 1<<24)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, []byte{0x30, 0x84, 0x01, 0x00, 0x00, 0x00}) {
+		t.Errorf("AppendLength() = % x", got)
+	}
+
+	// Длина, полученная декодированием, совпадает с исходной
+	got = append(got, make([]byte, 1<<24)...)
+	pos, length, err := DecodeLength(got, 1, len(got))
+	if err != nil || pos != 6 || length != 1<<24 {
+		t.Errorf("DecodeLength() = %d, %d, %v", pos, length, err)
+	}
+
+	got, err = AppendLength([]byte{0x30}, MaxLength+1)
+	if !errors.Is(err, ErrLengthTooLarge) {
+		t.Errorf("AppendLength() error = %v, want ErrLengthTooLarge", err)
+	}
+	if !bytes.Equal(got, []byte{0x30}) {
+		t.Errorf("AppendLength() modified dst on error: % x", got)
+	}
+}
+
 func TestEncodeTL(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -443,7 +766,7 @@ func TestEncodeBoolean(t *testing.T) {
 			buffer:  make([]byte, 10),
 			bufPos:  0,
 			wantPos: 3,
-			wantBuf: []byte{0x01, 0x01, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+			wantBuf: []byte{0x01, 0x01, 0xFF, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
 		},
 		{
 			name:    "false",
@@ -703,6 +1026,16 @@ func TestDetermineLengthSize(t *testing.T) {
 			length: 65536,
 			want:   4,
 		},
+		{
+			name:   "long form 3 bytes max",
+			length: 1<<24 - 1,
+			want:   4,
+		},
+		{
+			name:   "long form 4 bytes",
+			length: 1 << 24,
+			want:   5,
+		},
 	}
 
 	for _, tt := range tests {
@@ -844,6 +1177,42 @@ func TestRevertByteOrder(t *testing.T) {
 	}
 }
 
+func TestDecodeBitmaskFromBytes(t *testing.T) {
+	tests := []struct {
+		name        string
+		bitmask     []byte
+		paddingBits byte
+		want        []uint
+	}{
+		{
+			name:        "msb first",
+			bitmask:     []byte{0x81, 0x40},
+			paddingBits: 6,
+			want:        []uint{0, 7, 9},
+		},
+		{
+			name:        "padding exceeds bitmask",
+			bitmask:     []byte{0xf1},
+			paddingBits: 0x84,
+			want:        nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DecodeBitmaskFromBytes(tt.bitmask, tt.paddingBits, len(tt.bitmask))
+			if len(got) != len(tt.want) {
+				t.Fatalf("DecodeBitmaskFromBytes() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("DecodeBitmaskFromBytes() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
 // Round-trip tests
 
 func TestEncodeDecodeRoundTrip(t *testing.T) {
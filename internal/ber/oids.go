@@ -0,0 +1,61 @@
+package ber
+
+import "sync"
+
+// Well-known object identifiers of the OSI upper layers and MMS
+var (
+	// OIDBER is the basic-encoding transfer syntax (2.1.1)
+	OIDBER = NewOID(2, 1, 1)
+	// OIDACSE is the ACSE abstract syntax id-as-acse (2.2.1.0.1)
+	OIDACSE = NewOID(2, 2, 1, 0, 1)
+	// OIDPasswordAuthentication is the id-password authentication mechanism (2.2.3.1)
+	OIDPasswordAuthentication = NewOID(2, 2, 3, 1)
+	// OIDMMSAbstractSyntax is mms-abstract-syntax-version1 (1.0.9506.2.1)
+	OIDMMSAbstractSyntax = NewOID(1, 0, 9506, 2, 1)
+	// OIDMMSApplicationContext is the MMS application context name (1.0.9506.2.3)
+	OIDMMSApplicationContext = NewOID(1, 0, 9506, 2, 3)
+)
+
+// KnownOID is an entry of the known-OID registry
+type KnownOID struct {
+	OID  ItuObjectIdentifier
+	Name string
+}
+
+var (
+	knownOIDsMutex sync.RWMutex
+	knownOIDs      = []KnownOID{
+		{OID: OIDBER, Name: "basic-encoding"},
+		{OID: OIDACSE, Name: "id-as-acse"},
+		{OID: OIDPasswordAuthentication, Name: "id-password"},
+		{OID: OIDMMSAbstractSyntax, Name: "mms-abstract-syntax-version1"},
+		{OID: OIDMMSApplicationContext, Name: "MMS"},
+	}
+)
+
+// RegisterOID adds an identifier to the known-OID registry or renames an existing one
+func RegisterOID(oid ItuObjectIdentifier, name string) {
+	knownOIDsMutex.Lock()
+	defer knownOIDsMutex.Unlock()
+
+	for i := range knownOIDs {
+		if knownOIDs[i].OID.Equal(oid) {
+			knownOIDs[i].Name = name
+			return
+		}
+	}
+	knownOIDs = append(knownOIDs, KnownOID{OID: oid, Name: name})
+}
+
+// LookupOID returns the registered name of the identifier
+func LookupOID(oid ItuObjectIdentifier) (string, bool) {
+	knownOIDsMutex.RLock()
+	defer knownOIDsMutex.RUnlock()
+
+	for _, known := range knownOIDs {
+		if known.OID.Equal(oid) {
+			return known.Name, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,45 @@
+package ber
+
+import "testing"
+
+func TestLookupOID(t *testing.T) {
+	tests := []struct {
+		name     string
+		oid      ItuObjectIdentifier
+		wantName string
+		wantOK   bool
+	}{
+		{name: "BER", oid: NewOID(2, 1, 1), wantName: "basic-encoding", wantOK: true},
+		{name: "ACSE", oid: NewOID(2, 2, 1, 0, 1), wantName: "id-as-acse", wantOK: true},
+		{name: "MMS abstract syntax", oid: NewOID(1, 0, 9506, 2, 1), wantName: "mms-abstract-syntax-version1", wantOK: true},
+		{name: "MMS application context", oid: NewOID(1, 0, 9506, 2, 3), wantName: "MMS", wantOK: true},
+		{name: "unknown", oid: NewOID(1, 0, 9506, 2, 2)},
+		{name: "prefix", oid: NewOID(1, 0, 9506)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, ok := LookupOID(tt.oid)
+			if name != tt.wantName || ok != tt.wantOK {
+				t.Errorf("LookupOID(%v) = %q, %v, want %q, %v", tt.oid, name, ok, tt.wantName, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestRegisterOID(t *testing.T) {
+	oid := NewOID(1, 0, 61850, 8, 2)
+	if _, ok := LookupOID(oid); ok {
+		t.Fatalf("LookupOID(%v) found before registration", oid)
+	}
+
+	RegisterOID(oid, "iec61850-8-2")
+	if name, ok := LookupOID(oid); !ok || name != "iec61850-8-2" {
+		t.Errorf("LookupOID(%v) = %q, %v", oid, name, ok)
+	}
+
+	RegisterOID(NewOID(1, 0, 61850, 8, 2), "xmpp")
+	if name, _ := LookupOID(oid); name != "xmpp" {
+		t.Errorf("LookupOID(%v) after rename = %q", oid, name)
+	}
+}
@@ -115,6 +115,7 @@ const (
 	ContextSpecific2Constructed  Tag = 0xA2
 	ContextSpecific3Constructed  Tag = 0xA3
 	ContextSpecific4Constructed  Tag = 0xA4
+	ContextSpecific5Constructed  Tag = 0xA5
 	ContextSpecific6Constructed  Tag = 0xA6
 	ContextSpecific7Constructed  Tag = 0xA7
 	ContextSpecific12Constructed Tag = 0xAC
@@ -126,6 +127,9 @@ const (
 	ContextSpecific0Primitive  Tag = 0x80
 	ContextSpecific1Primitive  Tag = 0x81
 	ContextSpecific2Primitive  Tag = 0x82
+	ContextSpecific3Primitive  Tag = 0x83
+	ContextSpecific5Primitive  Tag = 0x85
+	ContextSpecific6Primitive  Tag = 0x86
 	ContextSpecific10Primitive Tag = 0x8A
 	ContextSpecific11Primitive Tag = 0x8B
 )
@@ -0,0 +1,440 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"strings"
+	"unicode"
+)
+
+// config - параметры генерации
+type config struct {
+	// Package - имя пакета генерируемого кода
+	Package string
+	// Source - имя исходного файла для заголовка
+	Source string
+	// Prefix - префикс имён генерируемых типов, чтобы они не совпадали
+	// с написанными вручную типами пакета (например, "asn" -> asnGetNameListResponse)
+	Prefix string
+}
+
+// universalTags - теги UNIVERSAL встроенных типов
+var universalTags = map[string]byte{
+	"INTEGER": 0x02, "Integer8": 0x02, "Integer16": 0x02, "Integer32": 0x02,
+	"Unsigned8": 0x02, "Unsigned16": 0x02, "Unsigned32": 0x02,
+	"BOOLEAN":      0x01,
+	"OCTET STRING": 0x04,
+	"Identifier":   0x1a, "VisibleString": 0x1a, "GraphicString": 0x19,
+	"MMSString": 0x0c, "UTF8String": 0x0c,
+	"SEQUENCE": 0x30, "SEQUENCE OF": 0x30,
+}
+
+// field - поле структуры после разрешения ссылок на типы
+type field struct {
+	// ASNName и GoName - имя поля в модуле и в Go
+	ASNName string
+	GoName  string
+	// Kind - вид типа поля; для SEQUENCE OF - kindSequenceOf, вид элемента в Elem
+	Kind kind
+	Elem kind
+	// Struct - имя структуры Go для SEQUENCE и элементов SEQUENCE OF SEQUENCE
+	Struct string
+	// Universal - тег UNIVERSAL типа (и элемента для SEQUENCE OF)
+	Universal     byte
+	ElemUniversal byte
+	// Tag - тег поля в кодировке; Explicit - Tag оборачивает тег Universal
+	Tag      byte
+	Explicit bool
+	Optional bool
+}
+
+// generator формирует код по присваиваниям модуля
+type generator struct {
+	types map[string]*asnType
+	// structs - имена SEQUENCE, для которых создаются структуры
+	structs map[string]string
+}
+
+// generate формирует Go код со структурами и методами encode/decode для
+// присваиваний SEQUENCE модуля. encode возвращает содержимое SEQUENCE без
+// тега и длины, decode разбирает его; внешний тег PDU (например, номер
+// сервиса) добавляет написанный вручную код. Сгенерированный код использует
+// вспомогательные функции пакета mms (wrapTL, decodeTLV, encodeInt ...).
+//
+// Не поддерживаются CHOICE, BIT STRING, NULL, REAL, вложенные безымянные
+// SEQUENCE и теги >= 31: такие типы и типы, ссылающиеся на них, пропускаются
+// и перечисляются в заголовке файла.
+func generate(assignments []assignment, cfg config) ([]byte, error) {
+	g := &generator{types: map[string]*asnType{}, structs: map[string]string{}}
+	for _, a := range assignments {
+		if _, ok := g.types[a.Name]; ok {
+			return nil, fmt.Errorf("type %s defined twice", a.Name)
+		}
+		g.types[a.Name] = a.Type
+		if a.Type.Kind == kindSequence {
+			g.structs[a.Name] = cfg.Prefix + goName(a.Name)
+		}
+	}
+
+	// Типы с неподдерживаемыми полями исключаются до тех пор,
+	// пока не останутся только типы, ссылающиеся на генерируемые
+	skipped := map[string]string{}
+	for changed := true; changed; {
+		changed = false
+		for _, a := range assignments {
+			if _, ok := g.structs[a.Name]; !ok {
+				continue
+			}
+			if _, err := g.fields(a.Type); err != nil {
+				skipped[a.Name] = err.Error()
+				delete(g.structs, a.Name)
+				changed = true
+			}
+		}
+	}
+
+	var body bytes.Buffer
+	usesBer := false
+	for _, a := range assignments {
+		name, ok := g.structs[a.Name]
+		if !ok {
+			if _, isSkipped := skipped[a.Name]; !isSkipped && a.Type.Kind == kindChoice {
+				skipped[a.Name] = "CHOICE"
+			}
+			continue
+		}
+		fields, _ := g.fields(a.Type)
+		writeStruct(&body, a.Name, name, fields)
+		for _, f := range fields {
+			if f.Kind == kindUnsigned {
+				usesBer = true
+			}
+		}
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "// Code generated by asn1gen from %s. DO NOT EDIT.\n\n", cfg.Source)
+	var notes []string
+	for _, a := range assignments {
+		if reason, ok := skipped[a.Name]; ok {
+			notes = append(notes, fmt.Sprintf("//   - %s: %s\n", a.Name, reason))
+		}
+	}
+	if len(notes) > 0 {
+		out.WriteString("// Не сгенерированы (не поддерживаются):\n")
+		for _, n := range notes {
+			out.WriteString(n)
+		}
+		out.WriteString("\n")
+	}
+	fmt.Fprintf(&out, "package %s\n\n", cfg.Package)
+	if usesBer {
+		out.WriteString("import (\n\t\"fmt\"\n\n\t\"github.com/slonegd/go61850/internal/ber\"\n)\n\n")
+	} else {
+		out.WriteString("import \"fmt\"\n\n")
+	}
+	out.Write(body.Bytes())
+
+	code, err := format.Source(out.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated code: %w", err)
+	}
+	return code, nil
+}
+
+// resolve раскрывает ссылки на типы-синонимы ("Identifier ::= VisibleString")
+func (g *generator) resolve(t *asnType) (*asnType, error) {
+	for seen := 0; t.Kind == kindRef; seen++ {
+		target, ok := g.types[t.Name]
+		if !ok {
+			return nil, fmt.Errorf("type %s not defined", t.Name)
+		}
+		if seen > len(g.types) {
+			return nil, fmt.Errorf("type %s refers to itself", t.Name)
+		}
+		if target.Kind == kindSequence {
+			return t, nil
+		}
+		if target.Tag >= 0 {
+			return nil, fmt.Errorf("tagged type %s", t.Name)
+		}
+		resolved := *target
+		resolved.Tag, resolved.Implicit = t.Tag, t.Implicit
+		t = &resolved
+	}
+	return t, nil
+}
+
+// fields разрешает поля SEQUENCE; ошибка описывает первое неподдерживаемое поле
+func (g *generator) fields(seq *asnType) ([]field, error) {
+	var fields []field
+	tags := map[byte]string{}
+	for _, c := range seq.Components {
+		f, err := g.field(c)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", c.Name, err)
+		}
+		if other, ok := tags[f.Tag]; ok {
+			return nil, fmt.Errorf("%s: tag 0x%02x also used by %s", c.Name, f.Tag, other)
+		}
+		tags[f.Tag] = c.Name
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
+
+func (g *generator) field(c component) (field, error) {
+	t, err := g.resolve(c.Type)
+	if err != nil {
+		return field{}, err
+	}
+	f := field{ASNName: c.Name, GoName: goName(c.Name), Kind: t.Kind, Optional: c.Optional}
+
+	switch t.Kind {
+	case kindInteger, kindUnsigned, kindBoolean, kindString, kindOctetString:
+		f.Universal = universalTags[t.Name]
+	case kindRef:
+		name, ok := g.structs[t.Name]
+		if !ok {
+			return field{}, fmt.Errorf("type %s is not generated", t.Name)
+		}
+		f.Kind, f.Struct, f.Universal = kindSequence, name, 0x30
+	case kindSequenceOf:
+		f.Universal = 0x30
+		elem, err := g.resolve(t.Elem)
+		if err != nil {
+			return field{}, err
+		}
+		if elem.Tag >= 0 {
+			return field{}, fmt.Errorf("tagged SEQUENCE OF element")
+		}
+		switch elem.Kind {
+		case kindString:
+			if elem.Name == "GraphicString" {
+				return field{}, fmt.Errorf("SEQUENCE OF GraphicString")
+			}
+			f.Elem, f.ElemUniversal = kindString, universalTags[elem.Name]
+		case kindRef:
+			name, ok := g.structs[elem.Name]
+			if !ok {
+				return field{}, fmt.Errorf("type %s is not generated", elem.Name)
+			}
+			f.Elem, f.Struct, f.ElemUniversal = kindSequence, name, 0x30
+		default:
+			return field{}, fmt.Errorf("SEQUENCE OF %s", elem.Name)
+		}
+	case kindSequence:
+		return field{}, fmt.Errorf("inline SEQUENCE")
+	default:
+		return field{}, fmt.Errorf("%s", t.Name)
+	}
+
+	f.Tag = f.Universal
+	if t.Tag >= 0 {
+		if t.Tag >= 31 {
+			return field{}, fmt.Errorf("tag [%d]", t.Tag)
+		}
+		f.Tag = 0x80 | byte(t.Tag)
+		f.Explicit = !t.Implicit
+		if f.Explicit || f.Universal&0x20 != 0 {
+			f.Tag |= 0x20
+		}
+	}
+	return f, nil
+}
+
+// goType возвращает тип поля в Go
+func (f field) goType() string {
+	var t string
+	switch f.Kind {
+	case kindInteger:
+		t = "int32"
+	case kindUnsigned:
+		t = "uint32"
+	case kindBoolean:
+		t = "bool"
+	case kindString:
+		return "string"
+	case kindOctetString:
+		return "[]byte"
+	case kindSequence:
+		t = f.Struct
+	case kindSequenceOf:
+		if f.Elem == kindString {
+			return "[]string"
+		}
+		return "[]" + f.Struct
+	}
+	if f.Optional {
+		return "*" + t
+	}
+	return t
+}
+
+// pointer - необязательное поле хранится указателем (иначе - пустым значением)
+func (f field) pointer() bool {
+	return strings.HasPrefix(f.goType(), "*")
+}
+
+// contentTLV возвращает выражение Go, кодирующее value с тегом tag
+func (f field) contentTLV(tag byte, value string) string {
+	switch f.Kind {
+	case kindInteger:
+		return fmt.Sprintf("wrapTL(0x%02x, encodeInt(%s))", tag, value)
+	case kindUnsigned:
+		return fmt.Sprintf("encodeUnsigned(0x%02x, %s)", tag, value)
+	case kindBoolean:
+		return fmt.Sprintf("wrapTL(0x%02x, encodeBool(%s))", tag, value)
+	case kindString:
+		return fmt.Sprintf("wrapTL(0x%02x, []byte(%s))", tag, value)
+	case kindOctetString:
+		return fmt.Sprintf("wrapTL(0x%02x, %s)", tag, value)
+	case kindSequence:
+		return fmt.Sprintf("wrapTL(0x%02x, %s.encode())", tag, value)
+	default: // kindSequenceOf: содержимое подготовлено в переменной value
+		return fmt.Sprintf("wrapTL(0x%02x, %s)", tag, value)
+	}
+}
+
+// writeStruct записывает структуру и методы encode/decode
+func writeStruct(out *bytes.Buffer, asnName, name string, fields []field) {
+	fmt.Fprintf(out, "// %s - %s\n", name, asnName)
+	fmt.Fprintf(out, "type %s struct {\n", name)
+	for _, f := range fields {
+		fmt.Fprintf(out, "\t%s %s\n", f.GoName, f.goType())
+	}
+	out.WriteString("}\n\n")
+
+	fmt.Fprintf(out, "// encode кодирует содержимое %s (без тега и длины)\n", asnName)
+	fmt.Fprintf(out, "func (v *%s) encode() []byte {\n\tvar content []byte\n", name)
+	for _, f := range fields {
+		writeEncodeField(out, f)
+	}
+	out.WriteString("\treturn content\n}\n\n")
+
+	fmt.Fprintf(out, "// decode разбирает содержимое %s (без тега и длины)\n", asnName)
+	fmt.Fprintf(out, "func (v *%s) decode(buffer []byte) error {\n", name)
+	if len(fields) == 0 {
+		fmt.Fprintf(out, "\tif len(buffer) > 0 {\n\t\treturn fmt.Errorf(\"unexpected content in %s\")\n\t}\n\treturn nil\n}\n\n", asnName)
+		return
+	}
+	out.WriteString("\tfor bufPos := 0; bufPos < len(buffer); {\n")
+	out.WriteString("\t\ttag, value, next, err := decodeTLV(buffer, bufPos, len(buffer))\n")
+	out.WriteString("\t\tif err != nil {\n\t\t\treturn err\n\t\t}\n")
+	out.WriteString("\t\tswitch tag {\n")
+	for _, f := range fields {
+		writeDecodeField(out, f)
+	}
+	out.WriteString("\t\tdefault:\n")
+	fmt.Fprintf(out, "\t\t\treturn fmt.Errorf(\"unexpected tag in %s: 0x%%02x\", tag)\n", asnName)
+	out.WriteString("\t\t}\n\t\tbufPos = next\n\t}\n\treturn nil\n}\n\n")
+}
+
+func writeEncodeField(out *bytes.Buffer, f field) {
+	value := "v." + f.GoName
+	indent := "\t"
+	switch {
+	case f.pointer():
+		fmt.Fprintf(out, "\tif %s != nil {\n", value)
+		if f.Kind != kindSequence {
+			value = "*" + value
+		}
+		indent = "\t\t"
+	case f.Optional:
+		fmt.Fprintf(out, "\tif len(%s) > 0 {\n", value)
+		indent = "\t\t"
+	}
+
+	if f.Kind == kindSequenceOf {
+		list := lowerFirst(f.GoName)
+		fmt.Fprintf(out, "%svar %s []byte\n", indent, list)
+		fmt.Fprintf(out, "%sfor i := range %s {\n", indent, value)
+		if f.Elem == kindString {
+			fmt.Fprintf(out, "%s\t%s = append(%s, wrapTL(0x%02x, []byte(%s[i]))...)\n", indent, list, list, f.ElemUniversal, value)
+		} else {
+			fmt.Fprintf(out, "%s\t%s = append(%s, wrapTL(0x30, %s[i].encode())...)\n", indent, list, list, value)
+		}
+		fmt.Fprintf(out, "%s}\n", indent)
+		value = list
+	}
+
+	tlv := f.contentTLV(f.Tag, value)
+	if f.Explicit {
+		tlv = fmt.Sprintf("wrapTL(0x%02x, %s)", f.Tag, f.contentTLV(f.Universal, value))
+	}
+	fmt.Fprintf(out, "%scontent = append(content, %s...)\n", indent, tlv)
+	if f.Optional {
+		out.WriteString("\t}\n")
+	}
+}
+
+func writeDecodeField(out *bytes.Buffer, f field) {
+	fmt.Fprintf(out, "\t\tcase 0x%02x: // %s\n", f.Tag, f.ASNName)
+	if f.Explicit {
+		fmt.Fprintf(out, "\t\t\tif value, err = expectTLV(value, 0x%02x, %q); err != nil {\n\t\t\t\treturn err\n\t\t\t}\n", f.Universal, f.ASNName)
+	}
+	target := "v." + f.GoName
+	var expr string
+	switch f.Kind {
+	case kindInteger:
+		expr = "decodeInt(value)"
+	case kindUnsigned:
+		expr = "ber.DecodeUint32(value, len(value), 0)"
+	case kindBoolean:
+		expr = "decodeBool(value)"
+	case kindString:
+		expr = "string(value)"
+	case kindOctetString:
+		expr = "append([]byte(nil), value...)"
+	case kindSequence:
+		if f.pointer() {
+			fmt.Fprintf(out, "\t\t\t%s = &%s{}\n", target, f.Struct)
+		}
+		fmt.Fprintf(out, "\t\t\tif err := %s.decode(value); err != nil {\n\t\t\t\treturn err\n\t\t\t}\n", target)
+		return
+	case kindSequenceOf:
+		if f.Elem == kindString {
+			fmt.Fprintf(out, "\t\t\tif %s, err = parseStringList(value, %q); err != nil {\n\t\t\t\treturn err\n\t\t\t}\n", target, f.ASNName)
+			return
+		}
+		fmt.Fprintf(out, "\t\t\t%s = []%s{}\n", target, f.Struct)
+		out.WriteString("\t\t\tfor elemPos := 0; elemPos < len(value); {\n")
+		out.WriteString("\t\t\t\telemTag, elem, elemNext, err := decodeTLV(value, elemPos, len(value))\n")
+		out.WriteString("\t\t\t\tif err != nil {\n\t\t\t\t\treturn err\n\t\t\t\t}\n")
+		fmt.Fprintf(out, "\t\t\t\tif elemTag != 0x30 {\n\t\t\t\t\treturn fmt.Errorf(\"unexpected %s element tag: 0x%%02x\", elemTag)\n\t\t\t\t}\n", f.ASNName)
+		fmt.Fprintf(out, "\t\t\t\tvar e %s\n", f.Struct)
+		out.WriteString("\t\t\t\tif err := e.decode(elem); err != nil {\n\t\t\t\t\treturn err\n\t\t\t\t}\n")
+		fmt.Fprintf(out, "\t\t\t\t%s = append(%s, e)\n", target, target)
+		out.WriteString("\t\t\t\telemPos = elemNext\n\t\t\t}\n")
+		return
+	}
+	if f.pointer() {
+		local := lowerFirst(f.GoName)
+		fmt.Fprintf(out, "\t\t\t%s := %s\n\t\t\t%s = &%s\n", local, expr, target, local)
+		return
+	}
+	fmt.Fprintf(out, "\t\t\t%s = %s\n", target, expr)
+}
+
+// goName строит экспортируемое имя Go из имени ASN.1:
+// "GetNameList-Response" -> "GetNameListResponse", "mmsDeletable" -> "MmsDeletable"
+func goName(name string) string {
+	var b strings.Builder
+	for _, part := range strings.Split(name, "-") {
+		if part == "" {
+			continue
+		}
+		r := []rune(part)
+		r[0] = unicode.ToUpper(r[0])
+		b.WriteString(string(r))
+	}
+	return b.String()
+}
+
+// lowerFirst возвращает имя локальной переменной для поля
+func lowerFirst(name string) string {
+	r := []rune(name)
+	r[0] = unicode.ToLower(r[0])
+	return string(r)
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"go/ast"
+	"go/importer"
+	goparser "go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerate(t *testing.T) {
+	text, err := os.ReadFile("testdata/mms.asn")
+	assert.NoError(t, err)
+	assignments, err := parseModule(string(text))
+	assert.NoError(t, err)
+
+	// Эталон обновляется командой:
+	// go run ./internal/cmd/asn1gen -i internal/cmd/asn1gen/testdata/mms.asn -o internal/cmd/asn1gen/testdata/mms.go.golden
+	want, err := os.ReadFile("testdata/mms.go.golden")
+	assert.NoError(t, err)
+
+	got, err := generate(assignments, config{Package: "mms", Source: "mms.asn", Prefix: "asn"})
+	assert.NoError(t, err)
+	assert.Equal(t, string(want), string(got))
+}
+
+// TestGenerateServices проверяет, что services_gen.go пакета osi/mms
+// соответствует services.asn (go generate ./osi/mms)
+func TestGenerateServices(t *testing.T) {
+	text, err := os.ReadFile("../../../osi/mms/services.asn")
+	assert.NoError(t, err)
+	assignments, err := parseModule(string(text))
+	assert.NoError(t, err)
+
+	want, err := os.ReadFile("../../../osi/mms/services_gen.go")
+	assert.NoError(t, err)
+
+	got, err := generate(assignments, config{Package: "mms", Source: "services.asn", Prefix: "asn"})
+	assert.NoError(t, err)
+	assert.Equal(t, string(want), string(got))
+}
+
+// TestGoldenCompiles проверяет типы эталона вместе с исходниками пакета osi/mms
+// (без services_gen.go, типы которого эталон содержит): сгенерированный код
+// использует вспомогательные функции BER пакета и не должен совпадать с его именами
+func TestGoldenCompiles(t *testing.T) {
+	if testing.Short() {
+		t.Skip("type-checks osi/mms and its dependencies from source")
+	}
+	dir := "../../../osi/mms"
+	entries, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, e := range entries {
+		name := e.Name()
+		if !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") || name == "services_gen.go" {
+			continue
+		}
+		file, err := goparser.ParseFile(fset, filepath.Join(dir, name), nil, 0)
+		assert.NoError(t, err)
+		files = append(files, file)
+	}
+	golden, err := goparser.ParseFile(fset, "testdata/mms.go.golden", nil, 0)
+	assert.NoError(t, err)
+	files = append(files, golden)
+
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	_, err = conf.Check("github.com/slonegd/go61850/osi/mms", fset, files, nil)
+	assert.NoError(t, err)
+}
+
+func TestParseModule(t *testing.T) {
+	assignments, err := parseModule(`
+		A-PDU ::= SEQUENCE {
+			first  [0] IMPLICIT Unsigned32, -- комментарий
+			second [1] SEQUENCE SIZE (1..8) OF Identifier OPTIONAL,
+			...
+		}
+		B ::= CHOICE { x [0] IMPLICIT NULL, y INTEGER }`)
+	assert.NoError(t, err)
+	assert.Len(t, assignments, 2)
+	a := assignments[0]
+	assert.Equal(t, "A-PDU", a.Name)
+	assert.Equal(t, kindSequence, a.Type.Kind)
+	assert.Equal(t, component{Name: "first", Type: &asnType{Kind: kindUnsigned, Name: "Unsigned32", Tag: 0, Implicit: true}}, a.Type.Components[0])
+	second := a.Type.Components[1]
+	assert.True(t, second.Optional)
+	assert.Equal(t, kindSequenceOf, second.Type.Kind)
+	assert.Equal(t, 1, second.Type.Tag)
+	assert.False(t, second.Type.Implicit)
+	assert.Equal(t, kindString, second.Type.Elem.Kind)
+	assert.Equal(t, kindChoice, assignments[1].Type.Kind)
+
+	_, err = parseModule("A ::= SEQUENCE { a INTEGER b INTEGER }")
+	assert.EqualError(t, err, `assignment A: expected "," or "}", got "b"`)
+	_, err = generate([]assignment{{Name: "A", Type: &asnType{Kind: kindInteger, Tag: -1}}, {Name: "A", Type: &asnType{Kind: kindInteger, Tag: -1}}}, config{})
+	assert.EqualError(t, err, "type A defined twice")
+}
+
+func TestGoName(t *testing.T) {
+	assert.Equal(t, "GetNameListResponse", goName("GetNameList-Response"))
+	assert.Equal(t, "MmsDeletable", goName("mmsDeletable"))
+}
@@ -0,0 +1,59 @@
+// Команда asn1gen генерирует Go структуры с методами encode/decode по модулю
+// ASN.1 (ISO 9506-2) для PDU MMS. Сгенерированный код использует
+// вспомогательные функции BER пакета osi/mms и размещается в нём; внешний тег
+// PDU и номер сервиса по-прежнему задаются вручную (см. GetDomainAttributesResponse).
+// Имена типов получают префикс -prefix (по умолчанию "asn"): неэкспортируемые
+// кодеки не совпадают с публичными типами пакета и не расширяют его API.
+//
+// Использование (см. go:generate в osi/mms/pdu.go):
+//
+//	go run ./internal/cmd/asn1gen -i osi/mms/services.asn -o osi/mms/services_gen.go -pkg mms
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+func main() {
+	input := flag.String("i", "", "входной модуль ASN.1")
+	output := flag.String("o", "", "выходной файл Go (по умолчанию stdout)")
+	pkg := flag.String("pkg", "mms", "имя пакета генерируемого кода")
+	prefix := flag.String("prefix", "asn", "префикс имён генерируемых типов")
+	flag.Parse()
+
+	if *input == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := run(*input, *output, config{Package: *pkg, Prefix: *prefix}); err != nil {
+		fmt.Fprintln(os.Stderr, "asn1gen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(input, output string, cfg config) error {
+	text, err := os.ReadFile(input)
+	if err != nil {
+		return err
+	}
+	assignments, err := parseModule(string(text))
+	if err != nil {
+		return fmt.Errorf("%s: %w", input, err)
+	}
+
+	cfg.Source = filepath.Base(input)
+	code, err := generate(assignments, cfg)
+	if err != nil {
+		return err
+	}
+
+	if output == "" {
+		_, err = os.Stdout.Write(code)
+		return err
+	}
+	return os.WriteFile(output, code, 0o644)
+}
@@ -0,0 +1,362 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// kind - вид типа ASN.1
+type kind int
+
+const (
+	kindSequence kind = iota
+	kindSequenceOf
+	kindChoice
+	kindInteger
+	kindUnsigned
+	kindBoolean
+	kindString
+	kindOctetString
+	kindUnsupported
+	kindRef
+)
+
+// asnType - тип ASN.1 из модуля
+type asnType struct {
+	Kind kind
+	// Name - имя встроенного типа (INTEGER, VisibleString ...) или ссылки
+	Name string
+	// Tag - номер context-specific тега; -1 - без тега
+	Tag int
+	// Implicit - тег заменяет тег типа (IMPLICIT), иначе оборачивает его
+	Implicit bool
+	// Elem - тип элемента SEQUENCE OF
+	Elem *asnType
+	// Components - поля SEQUENCE и варианты CHOICE
+	Components []component
+}
+
+// component - поле SEQUENCE или вариант CHOICE
+type component struct {
+	Name     string
+	Type     *asnType
+	Optional bool
+}
+
+// assignment - присваивание типа "Name ::= Type"
+type assignment struct {
+	Name string
+	Type *asnType
+}
+
+// builtins - встроенные и общеупотребимые в ISO 9506 типы
+var builtins = map[string]kind{
+	"INTEGER":       kindInteger,
+	"Integer8":      kindInteger,
+	"Integer16":     kindInteger,
+	"Integer32":     kindInteger,
+	"Unsigned8":     kindUnsigned,
+	"Unsigned16":    kindUnsigned,
+	"Unsigned32":    kindUnsigned,
+	"BOOLEAN":       kindBoolean,
+	"Identifier":    kindString,
+	"VisibleString": kindString,
+	"MMSString":     kindString,
+	"UTF8String":    kindString,
+	"GraphicString": kindString,
+	"NULL":          kindUnsupported,
+	"REAL":          kindUnsupported,
+}
+
+// parser разбирает подмножество ASN.1, достаточное для описания PDU MMS:
+// SEQUENCE, SEQUENCE OF, CHOICE, теги [n] IMPLICIT/EXPLICIT, OPTIONAL, DEFAULT.
+// Ограничения (SIZE, диапазоны), именованные числа и маркеры расширения пропускаются.
+type parser struct {
+	tokens []string
+	pos    int
+}
+
+// parseModule разбирает текст модуля ASN.1 и возвращает присваивания типов по порядку
+func parseModule(text string) ([]assignment, error) {
+	tokens, err := tokenize(text)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	// Заголовок модуля "Name DEFINITIONS ... ::= BEGIN" и IMPORTS/EXPORTS пропускаются
+	for i, t := range tokens {
+		if t == "BEGIN" {
+			p.pos = i + 1
+			break
+		}
+	}
+
+	var assignments []assignment
+	for !p.done() {
+		switch p.peek() {
+		case "END":
+			return assignments, nil
+		case "IMPORTS", "EXPORTS":
+			for !p.done() && p.next() != ";" {
+			}
+			continue
+		}
+		name := p.next()
+		if err := p.expect("::="); err != nil {
+			return nil, fmt.Errorf("assignment %s: %w", name, err)
+		}
+		t, err := p.parseType()
+		if err != nil {
+			return nil, fmt.Errorf("assignment %s: %w", name, err)
+		}
+		assignments = append(assignments, assignment{Name: name, Type: t})
+	}
+	return assignments, nil
+}
+
+func (p *parser) done() bool { return p.pos >= len(p.tokens) }
+
+func (p *parser) peek() string {
+	if p.done() {
+		return ""
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() string {
+	t := p.peek()
+	p.pos++
+	return t
+}
+
+func (p *parser) expect(token string) error {
+	if t := p.next(); t != token {
+		return fmt.Errorf("expected %q, got %q", token, t)
+	}
+	return nil
+}
+
+// skipGroup пропускает сбалансированную группу, начинающуюся с open
+func (p *parser) skipGroup(open, close string) error {
+	depth := 0
+	for !p.done() {
+		switch p.next() {
+		case open:
+			depth++
+		case close:
+			depth--
+			if depth == 0 {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("unbalanced %q", open)
+}
+
+// parseType разбирает тип с необязательным тегом и ограничениями
+func (p *parser) parseType() (*asnType, error) {
+	tag, implicit := -1, false
+	if p.peek() == "[" {
+		p.next()
+		number, err := strconv.Atoi(p.next())
+		if err != nil {
+			return nil, fmt.Errorf("only context-specific tags are supported: %w", err)
+		}
+		if err := p.expect("]"); err != nil {
+			return nil, err
+		}
+		tag = number
+		switch p.peek() {
+		case "IMPLICIT":
+			p.next()
+			implicit = true
+		case "EXPLICIT":
+			p.next()
+		}
+	}
+
+	t, err := p.parseUntagged()
+	if err != nil {
+		return nil, err
+	}
+	t.Tag, t.Implicit = tag, implicit
+	// Ограничения после типа: (SIZE (1..32)), (0..127)
+	if p.peek() == "(" {
+		if err := p.skipGroup("(", ")"); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+func (p *parser) parseUntagged() (*asnType, error) {
+	name := p.next()
+	switch name {
+	case "SEQUENCE", "SET":
+		if p.peek() == "SIZE" {
+			p.next()
+		}
+		if p.peek() == "(" {
+			if err := p.skipGroup("(", ")"); err != nil {
+				return nil, err
+			}
+		}
+		if p.peek() == "OF" {
+			p.next()
+			elem, err := p.parseType()
+			if err != nil {
+				return nil, err
+			}
+			return &asnType{Kind: kindSequenceOf, Name: name + " OF", Elem: elem}, nil
+		}
+		if name == "SET" {
+			return &asnType{Kind: kindUnsupported, Name: name}, p.skipGroup("{", "}")
+		}
+		components, err := p.parseComponents()
+		return &asnType{Kind: kindSequence, Name: name, Components: components}, err
+	case "CHOICE":
+		components, err := p.parseComponents()
+		return &asnType{Kind: kindChoice, Name: name, Components: components}, err
+	case "OCTET":
+		if err := p.expect("STRING"); err != nil {
+			return nil, err
+		}
+		return &asnType{Kind: kindOctetString, Name: "OCTET STRING"}, nil
+	case "BIT":
+		if err := p.expect("STRING"); err != nil {
+			return nil, err
+		}
+		if p.peek() == "{" {
+			if err := p.skipGroup("{", "}"); err != nil {
+				return nil, err
+			}
+		}
+		return &asnType{Kind: kindUnsupported, Name: "BIT STRING"}, nil
+	case "INTEGER":
+		// Именованные числа INTEGER { ready (2), ... } пропускаются
+		if p.peek() == "{" {
+			if err := p.skipGroup("{", "}"); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if name == "" || !isIdentifier(name) {
+		return nil, fmt.Errorf("unexpected token %q in type", name)
+	}
+	if k, ok := builtins[name]; ok {
+		return &asnType{Kind: k, Name: name}, nil
+	}
+	return &asnType{Kind: kindRef, Name: name}, nil
+}
+
+// parseComponents разбирает "{ name Type [OPTIONAL | DEFAULT value], ... }"
+func (p *parser) parseComponents() ([]component, error) {
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	var components []component
+	for {
+		if p.peek() == "}" {
+			p.next()
+			return components, nil
+		}
+		if p.peek() == "..." {
+			p.next()
+		} else {
+			name := p.next()
+			if !isIdentifier(name) {
+				return nil, fmt.Errorf("unexpected token %q in component list", name)
+			}
+			t, err := p.parseType()
+			if err != nil {
+				return nil, fmt.Errorf("component %s: %w", name, err)
+			}
+			c := component{Name: name, Type: t}
+			switch p.peek() {
+			case "OPTIONAL":
+				p.next()
+				c.Optional = true
+			case "DEFAULT":
+				// Значение по умолчанию не подставляется: поле считается необязательным
+				p.next()
+				if p.peek() == "{" {
+					if err := p.skipGroup("{", "}"); err != nil {
+						return nil, err
+					}
+				} else {
+					p.next()
+				}
+				c.Optional = true
+			}
+			components = append(components, c)
+		}
+		switch t := p.next(); t {
+		case ",":
+		case "}":
+			return components, nil
+		default:
+			return nil, fmt.Errorf("expected \",\" or \"}\", got %q", t)
+		}
+	}
+}
+
+// tokenize разбивает текст на лексемы; комментарии "--" удаляются
+func tokenize(text string) ([]string, error) {
+	var tokens []string
+	for _, line := range strings.Split(text, "\n") {
+		// Комментарий продолжается до конца строки или до следующего "--"
+		for {
+			start := strings.Index(line, "--")
+			if start < 0 {
+				break
+			}
+			end := strings.Index(line[start+2:], "--")
+			if end < 0 {
+				line = line[:start]
+				break
+			}
+			line = line[:start] + " " + line[start+2+end+2:]
+		}
+
+		for i := 0; i < len(line); {
+			r := rune(line[i])
+			switch {
+			case unicode.IsSpace(r):
+				i++
+			case strings.HasPrefix(line[i:], "::="):
+				tokens = append(tokens, "::=")
+				i += 3
+			case strings.HasPrefix(line[i:], "..."):
+				tokens = append(tokens, "...")
+				i += 3
+			case strings.HasPrefix(line[i:], ".."):
+				tokens = append(tokens, "..")
+				i += 2
+			case strings.ContainsRune("{}[](),;|", r):
+				tokens = append(tokens, string(r))
+				i++
+			case isIdentRune(r) || r == '-':
+				j := i
+				for j < len(line) && (isIdentRune(rune(line[j])) || line[j] == '-' && j+1 < len(line) && line[j+1] != '-') {
+					j++
+				}
+				tokens = append(tokens, line[i:j])
+				i = j
+			default:
+				return nil, fmt.Errorf("unexpected character %q", r)
+			}
+		}
+	}
+	return tokens, nil
+}
+
+func isIdentRune(r rune) bool {
+	return r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r))
+}
+
+// isIdentifier проверяет, что лексема - имя типа или поля
+func isIdentifier(token string) bool {
+	return token != "" && unicode.IsLetter(rune(token[0]))
+}
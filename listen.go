@@ -0,0 +1,75 @@
+package go61850
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/slonegd/go61850/osi/mms"
+)
+
+// WithReportHandler передаёт InformationReport, не относящиеся к выполняемым запросам,
+// в обработчик fn вместо очереди отчётов (WithReportQueue). fn вызывается в горутине
+// приёма (Listen или выполняемого запроса) и не должен обращаться к клиенту.
+func WithReportHandler(fn func(report *mms.InformationReportPDU)) MmsClientOption {
+	return func(c *MmsClient) {
+		c.reportHandler = fn
+	}
+}
+
+// Listen читает ассоциацию, пока клиент не выполняет запросов, и разбирает
+// незапрошенные PDU: InformationReport передаются обработчику WithReportHandler
+// или в очередь отчётов, запросы сервера - обработчикам WithRequestHandler,
+// а запоздавшие ответы на confirmed-запросы отбрасываются. Без Listen отчёты
+// принимаются только во время запросов. Запрос прерывает ожидание Listen и
+// получает ассоциацию без задержки; после ответа приём возобновляется.
+//
+// Listen запускается в отдельной горутине после Initiate и блокируется до отмены
+// ctx (возвращает ctx.Err()) или ошибки соединения. Транспорт должен поддерживать
+// SetReadDeadline (как net.Conn), иначе чтение нельзя прервать для запроса.
+func (c *MmsClient) Listen(ctx context.Context) error {
+	if c.capabilities == nil {
+		return fmt.Errorf("association not established, call Initiate first")
+	}
+	if _, ok := c.conn.(interface{ SetReadDeadline(time.Time) error }); !ok {
+		return errors.New("transport does not support SetReadDeadline")
+	}
+	c.diag("listen started")
+	for {
+		readCtx, preempt := context.WithCancel(ctx)
+		if err := c.queue.acquireIdle(ctx, preempt); err != nil {
+			preempt()
+			return err
+		}
+		mmsData, err := c.mmsClient.ReceiveAndParseMmsResponse(readCtx)
+		preempt()
+		if err == nil {
+			c.dispatchUnsolicited(ctx, mmsData)
+		}
+		c.queue.release()
+
+		switch {
+		case ctx.Err() != nil:
+			return ctx.Err()
+		case err != nil && readCtx.Err() != nil:
+			// Ассоциацию ожидает запрос: уже принятая часть PDU дочитывается им
+		case err != nil:
+			c.diag("listen stopped: %v", err)
+			c.connectionLost(ctx, err)
+			return fmt.Errorf("listen: %w", err)
+		}
+	}
+}
+
+// dispatchUnsolicited разбирает PDU, принятый вне запроса
+func (c *MmsClient) dispatchUnsolicited(ctx context.Context, mmsData []byte) {
+	if c.dispatchReport(ctx, mmsData, nil) || c.handleServerRequest(ctx, mmsData) {
+		return
+	}
+	if id, ok := mms.ResponseInvokeID(mmsData); ok {
+		c.logger.Debug("MMS response for invokeID %d dropped: no pending request", id)
+		return
+	}
+	c.logger.Debug("unsolicited MMS PDU dropped: %x", mmsData)
+}
@@ -0,0 +1,65 @@
+package go61850
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/slonegd/go61850/mmstest"
+	"github.com/slonegd/go61850/osi/mms"
+	"github.com/stretchr/testify/assert"
+)
+
+// reportTPKT - InformationReport с переменной LastApplError (см. osi/mms/information_report_test.go)
+const reportTPKT = "03 00 00 43 02 f0 80 01 00 01 00 61 36 30 34 02 01 03 a0 2f" +
+	" a3 2d a0 2b a0 21 30 11 a0 0f 80 0d 4c 61 73 74 41 70 70 6c 45 72 72 6f 72" +
+	" 30 0c a0 0a a1 08 1a 03 4c 44 30 1a 01 41 a0 06 85 01 01 83 01 ff"
+
+func TestListen(t *testing.T) {
+	f, err := os.Open("mmstest/testdata/read.txt")
+	assert.NoError(t, err)
+	defer f.Close()
+	exchanges, err := mmstest.ParseTranscript(f)
+	assert.NoError(t, err)
+	read := exchanges[2]
+
+	server := mmstest.NewTranscriptServer(t,
+		exchanges[0], exchanges[1],
+		// После ответа на чтение сервер отправляет отчёт, который читает уже Listen
+		mmstest.Exchange{Request: read.Request, Responses: append(read.Responses, reportTPKT)},
+		read,
+	)
+	conn := server.Dial()
+	ctx := context.Background()
+	reports := make(chan *mms.InformationReportPDU, 1)
+	client, err := NewMmsClient(ctx, conn, WithReportHandler(func(r *mms.InformationReportPDU) { reports <- r }))
+	assert.NoError(t, err)
+	assert.Error(t, client.Listen(ctx))
+	_, err = client.Initiate(ctx)
+	assert.NoError(t, err)
+
+	listenCtx, cancel := context.WithCancel(ctx)
+	done := make(chan error, 1)
+	go func() { done <- client.Listen(listenCtx) }()
+
+	result, err := client.ReadObject(ctx, &mms.ReadRequest{DomainID: "simpleIOGenericIO", ItemID: "GGIO1$MX"})
+	assert.NoError(t, err)
+	assert.True(t, result.Success)
+	select {
+	case report := <-reports:
+		assert.Equal(t, "LastApplError", report.Variables[0].ItemID)
+	case <-time.After(time.Second):
+		t.Fatal("report not delivered")
+	}
+
+	// Запрос прерывает ожидание Listen и получает свой ответ
+	result, err = client.ReadObject(ctx, &mms.ReadRequest{DomainID: "simpleIOGenericIO", ItemID: "GGIO1$MX"})
+	assert.NoError(t, err)
+	assert.True(t, result.Success)
+
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+	conn.Close()
+	assert.NoError(t, server.Wait())
+}
@@ -7,9 +7,28 @@ type Logger interface {
 	Debug(format string, v ...any)
 }
 
+// InfoLogger - Logger, различающий сообщения уровня INFO: редкие события,
+// полезные в эксплуатации (например, параметры установленной ассоциации).
+// Логгеры без метода Info получают такие сообщения через Debug.
+type InfoLogger interface {
+	Logger
+	Info(format string, v ...any)
+}
+
+// Level - минимальный уровень выводимых сообщений
+type Level int
+
+const (
+	// LevelDebug выводит все сообщения
+	LevelDebug Level = iota
+	// LevelInfo выводит только сообщения уровня INFO
+	LevelInfo
+)
+
 // stdLogger реализует Logger через стандартный пакет log
 type stdLogger struct {
 	category string
+	level    Level
 }
 
 // NewLogger создает новый логгер с указанной категорией
@@ -17,7 +36,24 @@ func NewLogger(category string) Logger {
 	return &stdLogger{category: category}
 }
 
+// NewLeveledLogger создает логгер с указанной категорией, выводящий сообщения
+// уровня level и выше
+func NewLeveledLogger(category string, level Level) InfoLogger {
+	return &stdLogger{category: category, level: level}
+}
+
 func (l *stdLogger) Debug(format string, v ...any) {
+	if l.level > LevelDebug {
+		return
+	}
+	l.print(format, v...)
+}
+
+func (l *stdLogger) Info(format string, v ...any) {
+	l.print(format, v...)
+}
+
+func (l *stdLogger) print(format string, v ...any) {
 	if l.category == "" {
 		log.Printf(format, v...)
 	} else {
@@ -25,3 +61,11 @@ func (l *stdLogger) Debug(format string, v ...any) {
 	}
 }
 
+// Info выводит сообщение уровня INFO, если l реализует InfoLogger, иначе через Debug
+func Info(l Logger, format string, v ...any) {
+	if info, ok := l.(InfoLogger); ok {
+		info.Info(format, v...)
+		return
+	}
+	l.Debug(format, v...)
+}
@@ -0,0 +1,144 @@
+package mmstest
+
+import (
+	"errors"
+	"net"
+	"sync"
+	"time"
+)
+
+// ErrInjectedDisconnect возвращается FaultConn после внедрённого разрыва соединения
+var ErrInjectedDisconnect = errors.New("mmstest: injected disconnect")
+
+// Fault настраивает внедряемые FaultConn ошибки
+type Fault func(*FaultConn)
+
+// PartialWrites разбивает каждую запись на части не длиннее n байт
+func PartialWrites(n int) Fault {
+	return func(c *FaultConn) {
+		c.writeChunk = n
+	}
+}
+
+// DelayReads задерживает каждое чтение на d
+func DelayReads(d time.Duration) Fault {
+	return func(c *FaultConn) {
+		c.readDelay = d
+	}
+}
+
+// CorruptTPKTLength заменяет поле длины packet-го (с 0) принятого TPKT пакета на length
+func CorruptTPKTLength(packet int, length uint16) Fault {
+	return func(c *FaultConn) {
+		c.corruptPacket = packet
+		c.corruptLength = length
+	}
+}
+
+// DisconnectAfterRead закрывает соединение после приёма n байт,
+// в том числе посреди PDU
+func DisconnectAfterRead(n int) Fault {
+	return func(c *FaultConn) {
+		c.disconnectAt = n
+	}
+}
+
+// FaultConn - обёртка соединения клиента, внедряющая ошибки транспорта
+// для проверки устойчивости: частичные записи, задержки чтения,
+// искажённую длину TPKT и разрыв посреди PDU.
+type FaultConn struct {
+	net.Conn
+
+	writeChunk    int
+	readDelay     time.Duration
+	corruptPacket int
+	corruptLength uint16
+	disconnectAt  int
+
+	mu sync.Mutex
+	// read - число принятых байт
+	read int
+	// packet, offset и size - номер текущего TPKT пакета, позиция в нём и его длина
+	packet int
+	offset int
+	size   int
+	header [4]byte
+}
+
+// NewFaultConn оборачивает conn с ошибками faults
+func NewFaultConn(conn net.Conn, faults ...Fault) *FaultConn {
+	c := &FaultConn{Conn: conn, corruptPacket: -1, disconnectAt: -1}
+	for _, f := range faults {
+		f(c)
+	}
+	return c
+}
+
+// Write реализует net.Conn
+func (c *FaultConn) Write(b []byte) (int, error) {
+	if c.writeChunk <= 0 {
+		return c.Conn.Write(b)
+	}
+	written := 0
+	for written < len(b) {
+		end := min(written+c.writeChunk, len(b))
+		n, err := c.Conn.Write(b[written:end])
+		written += n
+		if err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
+
+// Read реализует net.Conn
+func (c *FaultConn) Read(b []byte) (int, error) {
+	if c.readDelay > 0 {
+		time.Sleep(c.readDelay)
+	}
+	c.mu.Lock()
+	limit := len(b)
+	if c.disconnectAt >= 0 {
+		if c.read >= c.disconnectAt {
+			c.mu.Unlock()
+			c.Conn.Close()
+			return 0, ErrInjectedDisconnect
+		}
+		limit = min(limit, c.disconnectAt-c.read)
+	}
+	c.mu.Unlock()
+
+	n, err := c.Conn.Read(b[:limit])
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.read += n
+	c.track(b[:n])
+	return n, err
+}
+
+// track отслеживает границы TPKT пакетов в принятых данных и искажает длину
+func (c *FaultConn) track(b []byte) {
+	for i := 0; i < len(b); {
+		if c.offset < 4 {
+			// Границы пакетов определяются по исходной длине
+			c.header[c.offset] = b[i]
+			if c.offset >= 2 && c.packet == c.corruptPacket {
+				b[i] = byte(c.corruptLength >> (8 * (3 - c.offset)))
+			}
+			c.offset++
+			i++
+			if c.offset == 4 {
+				c.size = max(int(c.header[2])<<8|int(c.header[3]), 4)
+			}
+		} else {
+			skip := min(c.size-c.offset, len(b)-i)
+			c.offset += skip
+			i += skip
+		}
+		if c.offset >= 4 && c.offset == c.size {
+			c.packet++
+			c.offset = 0
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package mmstest_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/slonegd/go61850"
+	"github.com/slonegd/go61850/mmstest"
+	"github.com/slonegd/go61850/osi/mms"
+	"github.com/stretchr/testify/assert"
+)
+
+// readTranscript загружает транскрипт ассоциации и чтения
+func readTranscript(t *testing.T) []mmstest.Exchange {
+	f, err := os.Open("testdata/read.txt")
+	assert.NoError(t, err)
+	defer f.Close()
+	exchanges, err := mmstest.ParseTranscript(f)
+	assert.NoError(t, err)
+	return exchanges
+}
+
+// readObject выполняет ассоциацию и чтение через соединение с ошибками faults
+func readObject(t *testing.T, exchanges []mmstest.Exchange, faults ...mmstest.Fault) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	r := &recorder{TB: t}
+	server := mmstest.NewTranscriptServer(r, exchanges...)
+	conn := mmstest.NewFaultConn(server.Dial(), faults...)
+	defer conn.Close()
+
+	client, err := go61850.NewMmsClient(ctx, conn)
+	if err != nil {
+		return err
+	}
+	if _, err := client.Initiate(ctx); err != nil {
+		return err
+	}
+	result, err := client.ReadObject(ctx, &mms.ReadRequest{DomainID: "simpleIOGenericIO", ItemID: "GGIO1$MX"})
+	if err != nil {
+		return err
+	}
+	assert.Len(t, result.Value.Structure(), 4)
+	return nil
+}
+
+func TestFaultConn(t *testing.T) {
+	exchanges := readTranscript(t)
+
+	// Клиент справляется с дроблением записей и медленным чтением
+	assert.NoError(t, readObject(t, exchanges, mmstest.PartialWrites(3)))
+	assert.NoError(t, readObject(t, exchanges, mmstest.DelayReads(time.Millisecond)))
+
+	// Искажённая длина и разрыв посреди PDU завершаются ошибкой
+	err := readObject(t, exchanges, mmstest.CorruptTPKTLength(1, 0xffff))
+	assert.ErrorContains(t, err, "packet too large: 65535 bytes")
+	err = readObject(t, exchanges, mmstest.CorruptTPKTLength(1, 2))
+	assert.ErrorContains(t, err, "empty COTP message")
+	// Разрыв посреди Initiate Response (после Connection Confirm)
+	err = readObject(t, exchanges, mmstest.DisconnectAfterRead(22+50))
+	assert.ErrorIs(t, err, mmstest.ErrInjectedDisconnect)
+}
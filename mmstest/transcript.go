@@ -0,0 +1,235 @@
+// Package mmstest содержит тестовых двойников MMS сервера для детерминированных
+// тестов клиента.
+package mmstest
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Exchange - шаг транскрипта: ожидаемый запрос клиента и ответы сервера.
+// Пакеты задаются целиком (TPKT) в hex, пробелы и переводы строк игнорируются.
+// Байт "xx" в запросе совпадает с любым значением (например, invokeID).
+type Exchange struct {
+	Request string
+	// Responses отправляются по порядку после запроса; несколько ответов
+	// позволяют передать незапрошенные InformationReport перед ответом
+	Responses []string
+}
+
+// ParseTranscript читает транскрипт в текстовом виде: строки "> hex" - запрос клиента,
+// "< hex" - ответ сервера, строки без префикса продолжают предыдущий пакет,
+// "#" - комментарий. Ответы до первого запроса недопустимы.
+func ParseTranscript(r io.Reader) ([]Exchange, error) {
+	var exchanges []Exchange
+	// packet - пакет, к которому относятся строки продолжения
+	var packet *string
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		switch {
+		case text == "" || strings.HasPrefix(text, "#"):
+		case strings.HasPrefix(text, ">"):
+			exchanges = append(exchanges, Exchange{Request: strings.TrimSpace(text[1:])})
+			packet = &exchanges[len(exchanges)-1].Request
+		case strings.HasPrefix(text, "<"):
+			if len(exchanges) == 0 {
+				return nil, fmt.Errorf("line %d: response before first request", line)
+			}
+			e := &exchanges[len(exchanges)-1]
+			e.Responses = append(e.Responses, strings.TrimSpace(text[1:]))
+			packet = &e.Responses[len(e.Responses)-1]
+		default:
+			if packet == nil {
+				return nil, fmt.Errorf("line %d: data outside of packet", line)
+			}
+			*packet += " " + text
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return exchanges, nil
+}
+
+// TranscriptServer воспроизводит транскрипт запросов и ответов на соединении,
+// полученном через Dial. Неожиданный запрос завершает соединение и тест с ошибкой;
+// невыполненные шаги транскрипта проверяются при завершении теста,
+// после чего соединение закрывается.
+type TranscriptServer struct {
+	t         testing.TB
+	exchanges []Exchange
+
+	mu   sync.Mutex
+	conn net.Conn
+	step int
+	err  error
+	done chan struct{}
+}
+
+// NewTranscriptServer создаёт сервер с транскриптом exchanges
+func NewTranscriptServer(t testing.TB, exchanges ...Exchange) *TranscriptServer {
+	t.Helper()
+	s := &TranscriptServer{t: t, exchanges: exchanges}
+	t.Cleanup(func() {
+		s.mu.Lock()
+		if s.conn != nil {
+			s.conn.Close()
+		}
+		s.mu.Unlock()
+		if err := s.Wait(); err != nil {
+			t.Errorf("mmstest: %v", err)
+		}
+	})
+	return s
+}
+
+// Dial возвращает клиентскую сторону соединения и запускает воспроизведение.
+// Поддерживается одно соединение.
+func (s *TranscriptServer) Dial() net.Conn {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.done != nil {
+		s.t.Fatal("mmstest: TranscriptServer supports a single connection")
+	}
+	client, server := net.Pipe()
+	s.conn = server
+	s.done = make(chan struct{})
+	go s.serve(server)
+	return client
+}
+
+// Wait ожидает закрытия соединения клиентом и возвращает ошибку воспроизведения:
+// неожиданный запрос или невыполненные шаги транскрипта
+func (s *TranscriptServer) Wait() error {
+	s.mu.Lock()
+	done := s.done
+	s.mu.Unlock()
+	if done != nil {
+		<-done
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	if s.step < len(s.exchanges) {
+		return fmt.Errorf("transcript incomplete: %d of %d exchanges done, waiting for %s",
+			s.step, len(s.exchanges), compact(s.exchanges[s.step].Request))
+	}
+	return nil
+}
+
+// serve читает запросы клиента и отвечает по транскрипту
+func (s *TranscriptServer) serve(conn net.Conn) {
+	defer close(s.done)
+	defer conn.Close()
+	for {
+		request, err := readTPKT(conn)
+		if err != nil {
+			if !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrClosedPipe) {
+				s.fail(err)
+			}
+			return
+		}
+		s.mu.Lock()
+		step := s.step
+		s.mu.Unlock()
+		if step >= len(s.exchanges) {
+			s.fail(fmt.Errorf("unexpected request after end of transcript: %x", request))
+			return
+		}
+		e := s.exchanges[step]
+		if err := match(e.Request, request); err != nil {
+			s.fail(fmt.Errorf("exchange %d: %w", step, err))
+			return
+		}
+		for _, response := range e.Responses {
+			packet, err := decodeHex(response)
+			if err != nil {
+				s.fail(fmt.Errorf("exchange %d: response: %w", step, err))
+				return
+			}
+			if _, err := conn.Write(packet); err != nil {
+				s.fail(fmt.Errorf("exchange %d: write response: %w", step, err))
+				return
+			}
+		}
+		s.mu.Lock()
+		s.step++
+		s.mu.Unlock()
+	}
+}
+
+// fail сохраняет первую ошибку воспроизведения
+func (s *TranscriptServer) fail(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err == nil {
+		s.err = err
+	}
+}
+
+// readTPKT читает один TPKT пакет (RFC 1006)
+func readTPKT(r io.Reader) ([]byte, error) {
+	header := make([]byte, 4)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+	if header[0] != 0x03 || header[1] != 0x00 {
+		return nil, fmt.Errorf("invalid TPKT header: %x", header)
+	}
+	length := int(header[2])<<8 | int(header[3])
+	if length < 4 {
+		return nil, fmt.Errorf("invalid TPKT length: %d", length)
+	}
+	packet := make([]byte, length)
+	copy(packet, header)
+	if _, err := io.ReadFull(r, packet[4:]); err != nil {
+		return nil, fmt.Errorf("truncated TPKT packet: %w", err)
+	}
+	return packet, nil
+}
+
+// match сравнивает пакет с ожидаемым hex шаблоном ("xx" - любой байт)
+func match(pattern string, packet []byte) error {
+	pattern = compact(pattern)
+	if len(pattern)%2 != 0 {
+		return fmt.Errorf("odd length of request pattern %s", pattern)
+	}
+	if len(pattern)/2 != len(packet) {
+		return fmt.Errorf("unexpected request %x, want %s", packet, pattern)
+	}
+	for i := range packet {
+		hi := pattern[2*i : 2*i+2]
+		if hi == "xx" {
+			continue
+		}
+		b, err := hex.DecodeString(hi)
+		if err != nil {
+			return fmt.Errorf("request pattern: %w", err)
+		}
+		if b[0] != packet[i] {
+			return fmt.Errorf("unexpected request %x, want %s (first difference at byte %d)", packet, pattern, i)
+		}
+	}
+	return nil
+}
+
+// decodeHex декодирует hex строку с пробелами
+func decodeHex(s string) ([]byte, error) {
+	return hex.DecodeString(compact(s))
+}
+
+// compact удаляет пробельные символы и приводит hex к нижнему регистру
+func compact(s string) string {
+	return string(bytes.ToLower(bytes.Join(bytes.Fields([]byte(s)), nil)))
+}
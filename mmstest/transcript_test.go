@@ -0,0 +1,72 @@
+package mmstest_test
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/slonegd/go61850"
+	"github.com/slonegd/go61850/mmstest"
+	"github.com/slonegd/go61850/osi/mms"
+	"github.com/stretchr/testify/assert"
+)
+
+// recorder перехватывает ошибки и очистку TranscriptServer
+type recorder struct {
+	testing.TB
+	errors   []string
+	cleanups []func()
+}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, format)
+}
+
+func (r *recorder) Cleanup(f func()) { r.cleanups = append(r.cleanups, f) }
+
+func TestTranscriptServer(t *testing.T) {
+	f, err := os.Open("testdata/read.txt")
+	assert.NoError(t, err)
+	defer f.Close()
+	exchanges, err := mmstest.ParseTranscript(f)
+	assert.NoError(t, err)
+	assert.Len(t, exchanges, 3)
+
+	ctx := context.Background()
+	server := mmstest.NewTranscriptServer(t, exchanges...)
+	conn := server.Dial()
+	client, err := go61850.NewMmsClient(ctx, conn)
+	assert.NoError(t, err)
+	_, err = client.Initiate(ctx)
+	assert.NoError(t, err)
+	result, err := client.ReadObject(ctx, &mms.ReadRequest{DomainID: "simpleIOGenericIO", ItemID: "GGIO1$MX"})
+	assert.NoError(t, err)
+	assert.True(t, result.Success)
+	assert.Len(t, result.Value.Structure(), 4)
+
+	conn.Close()
+	assert.NoError(t, server.Wait())
+}
+
+func TestTranscriptServerUnexpected(t *testing.T) {
+	r := &recorder{TB: t}
+	server := mmstest.NewTranscriptServer(r, mmstest.Exchange{Request: "03 00 00 05 01"})
+	conn := server.Dial()
+	_, err := conn.Write([]byte{0x03, 0x00, 0x00, 0x05, 0x02})
+	assert.NoError(t, err)
+	assert.EqualError(t, server.Wait(),
+		"exchange 0: unexpected request 0300000502, want 0300000501 (first difference at byte 4)")
+
+	for _, f := range r.cleanups {
+		f()
+	}
+	assert.Len(t, r.errors, 1)
+}
+
+func TestParseTranscript(t *testing.T) {
+	_, err := mmstest.ParseTranscript(strings.NewReader("< 0300"))
+	assert.EqualError(t, err, "line 1: response before first request")
+	_, err = mmstest.ParseTranscript(strings.NewReader("0300"))
+	assert.EqualError(t, err, "line 1: data outside of packet")
+}
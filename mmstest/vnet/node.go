@@ -0,0 +1,69 @@
+package vnet
+
+import (
+	"context"
+	"errors"
+	"net"
+
+	"github.com/slonegd/go61850/server"
+)
+
+// pendingReport - отчёт блока домена, ожидающий отправки клиенту
+type pendingReport struct {
+	domainID string
+	report   server.Report
+}
+
+// node - серверный узел: обслуживает соединение server.ServeConn.
+// Отчёты отправляет отдельная горутина: запись в синхронный net.Pipe ожидает
+// чтения клиентом и не должна блокировать шаги сценария (SetValue).
+type node struct {
+	server  *server.Server
+	cancel  context.CancelFunc
+	reports chan pendingReport
+	// stopped закрывается после завершения ServeConn; err - её результат
+	stopped chan struct{}
+	err     error
+}
+
+func newNode(s *server.Server, conn net.Conn) *node {
+	ctx, cancel := context.WithCancel(context.Background())
+	n := &node{server: s, cancel: cancel, reports: make(chan pendingReport, 64), stopped: make(chan struct{})}
+	go func() {
+		defer close(n.stopped)
+		n.err = s.ServeConn(ctx, conn)
+		// Разрыв со стороны сервера (Disconnect) не считается ошибкой узла
+		if errors.Is(n.err, context.Canceled) {
+			n.err = nil
+		}
+	}()
+	go n.send()
+	return n
+}
+
+// report ставит отчёт в очередь отправки; после завершения ассоциации отчёт отбрасывается
+func (n *node) report(domainID string, r server.Report) {
+	select {
+	case n.reports <- pendingReport{domainID: domainID, report: r}:
+	case <-n.stopped:
+	}
+}
+
+// send отправляет отчёты из очереди до завершения ассоциации
+func (n *node) send() {
+	for {
+		select {
+		case p := <-n.reports:
+			// Ошибка означает разрыв соединения, его результат вернёт ServeConn
+			n.server.SendReport(p.domainID, p.report)
+		case <-n.stopped:
+			return
+		}
+	}
+}
+
+// close разрывает соединение со стороны сервера и ожидает завершения ServeConn
+func (n *node) close() {
+	n.cancel()
+	<-n.stopped
+}
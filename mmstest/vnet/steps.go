@@ -0,0 +1,134 @@
+package vnet
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/slonegd/go61850"
+	"github.com/slonegd/go61850/osi/mms"
+	"github.com/slonegd/go61850/osi/mms/variant"
+)
+
+// pollInterval - ожидание отчёта в очереди перед следующим опросом сервера
+const pollInterval = 20 * time.Millisecond
+
+// Step - шаг сценария. Шаги выполняются Run последовательно,
+// первая ошибка прерывает сценарий.
+type Step func(ctx context.Context, n *Network) error
+
+// Run выполняет шаги steps. Каждый шаг ограничен временем WithStepTimeout,
+// если ctx не задаёт срок раньше. Ошибка содержит номер шага (с 0).
+func (n *Network) Run(ctx context.Context, steps ...Step) error {
+	for i, step := range steps {
+		stepCtx, cancel := context.WithTimeout(ctx, n.stepTimeout)
+		err := step(stepCtx, n)
+		cancel()
+		if err != nil {
+			return fmt.Errorf("step %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// SetValue изменяет значение атрибута модели на сервере (server.SetValue),
+// запуская наблюдателей и блоки управления отчётами
+func SetValue(domainID, itemID string, value *variant.Variant) Step {
+	return func(_ context.Context, n *Network) error {
+		return n.server.SetValue(domainID, itemID, value)
+	}
+}
+
+// Sleep приостанавливает сценарий на d (например, для BufTm при системных часах)
+func Sleep(d time.Duration) Step {
+	return func(ctx context.Context, _ *Network) error {
+		select {
+		case <-time.After(d):
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// ExpectValue читает переменную клиентом и сравнивает значение с want
+func ExpectValue(domainID, itemID string, want *variant.Variant) Step {
+	return func(ctx context.Context, n *Network) error {
+		result, err := n.client.ReadObject(ctx, &mms.ReadRequest{DomainID: domainID, ItemID: itemID})
+		if err != nil {
+			return err
+		}
+		if !result.Success {
+			return fmt.Errorf("read %s/%s: %w", domainID, itemID, result.Error)
+		}
+		if !result.Value.Equal(want) {
+			return fmt.Errorf("read %s/%s: got %v, want %v", domainID, itemID, result.Value, want)
+		}
+		return nil
+	}
+}
+
+// ExpectReport ожидает отчёт rptID; отчёты других блоков пропускаются.
+// check, если задан, проверяет принятый отчёт.
+//
+// Клиент принимает отчёты, пока ожидает ответ на запрос, поэтому при пустой
+// очереди отчётов шаг опрашивает сервер запросом GetNameList доменов.
+func ExpectReport(rptID string, check func(*go61850.Report) error) Step {
+	return func(ctx context.Context, n *Network) error {
+		for {
+			report, err := n.nextReport(ctx)
+			if err != nil {
+				return fmt.Errorf("waiting for report %s: %w", rptID, err)
+			}
+			if report.RptID != rptID {
+				continue
+			}
+			if check != nil {
+				return check(report)
+			}
+			return nil
+		}
+	}
+}
+
+// ExpectNoReport проверяет, что за время d клиент не получил отчётов
+func ExpectNoReport(d time.Duration) Step {
+	return func(ctx context.Context, n *Network) error {
+		waitCtx, cancel := context.WithTimeout(ctx, d)
+		defer cancel()
+		report, err := n.nextReport(waitCtx)
+		if err == nil {
+			return fmt.Errorf("unexpected report %s (SeqNum %d)", report.RptID, report.SeqNum)
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return nil
+	}
+}
+
+// nextReport возвращает следующий отчёт из очереди клиента,
+// опрашивая сервер, пока очередь пуста
+func (n *Network) nextReport(ctx context.Context) (*go61850.Report, error) {
+	queue := n.client.Reports()
+	if queue == nil {
+		return nil, fmt.Errorf("client has no report queue")
+	}
+	for {
+		pollCtx, cancel := context.WithTimeout(ctx, pollInterval)
+		pdu, err := queue.Receive(pollCtx)
+		cancel()
+		if err == nil {
+			return go61850.ParseReport(pdu, 0)
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if _, err := n.client.GetNameList(ctx, mms.NewGetNameListRequest(mms.ObjectClassDomain, "")); err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			return nil, err
+		}
+	}
+}
@@ -0,0 +1,167 @@
+// Package vnet - виртуальная сеть из двух узлов для поведенческих тестов и примеров:
+// сервер server.Server и клиент go61850.MmsClient работают в одном процессе,
+// соединённые net.Pipe. Клиентская сторона соединения может внедрять ошибки
+// транспорта mmstest.Fault, сценарии описываются последовательностью шагов Step:
+//
+//	network := vnet.New(t, m)
+//	network.EnableReport("LD0", server.ReportControl{...})
+//	err := network.Run(ctx,
+//		vnet.SetValue("LD0", "GGIO1$ST$Ind1$stVal", variant.NewBoolVariant(true)),
+//		vnet.ExpectReport("Events", nil),
+//	)
+//
+// Вне тестов (например, в Example функциях) сеть создаётся Dial и закрывается Close.
+//
+// Серверный узел обслуживает соединение server.Server.ServeConn: ассоциация
+// и службы те же, что у сервера в сети, отчёты EnableReport передаются
+// клиенту через server.Server.SendReport.
+package vnet
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/slonegd/go61850"
+	"github.com/slonegd/go61850/mmstest"
+	"github.com/slonegd/go61850/model"
+	"github.com/slonegd/go61850/server"
+)
+
+// DefaultStepTimeout - время выполнения шага, если ctx Run не ограничен
+const DefaultStepTimeout = 5 * time.Second
+
+// Option представляет опцию для настройки Network
+type Option func(*Network)
+
+// WithFaults внедряет ошибки транспорта в клиентскую сторону соединения
+func WithFaults(faults ...mmstest.Fault) Option {
+	return func(n *Network) {
+		n.faults = append(n.faults, faults...)
+	}
+}
+
+// WithServerOptions задаёт опции сервера (например, server.WithClock)
+func WithServerOptions(opts ...server.Option) Option {
+	return func(n *Network) {
+		n.serverOpts = append(n.serverOpts, opts...)
+	}
+}
+
+// WithClientOptions задаёт опции клиента. По умолчанию клиент создаётся
+// с очередью отчётов на 64 элемента.
+func WithClientOptions(opts ...go61850.MmsClientOption) Option {
+	return func(n *Network) {
+		n.clientOpts = append(n.clientOpts, opts...)
+	}
+}
+
+// WithStepTimeout задаёт время выполнения шага вместо DefaultStepTimeout
+func WithStepTimeout(d time.Duration) Option {
+	return func(n *Network) {
+		n.stepTimeout = d
+	}
+}
+
+// Network - виртуальная сеть из сервера и клиента с установленной ассоциацией
+type Network struct {
+	t           testing.TB
+	faults      []mmstest.Fault
+	serverOpts  []server.Option
+	clientOpts  []go61850.MmsClientOption
+	stepTimeout time.Duration
+
+	server *server.Server
+	client *go61850.MmsClient
+	conn   net.Conn
+	node   *node
+}
+
+// New создаёт сервер модели m, подключает к нему клиента и устанавливает ассоциацию.
+// Соединение закрывается при завершении теста; ошибки серверного узла
+// (например, неразобранный пакет клиента) завершают тест с ошибкой.
+func New(t testing.TB, m *model.Model, opts ...Option) *Network {
+	t.Helper()
+	n, err := Dial(context.Background(), m, opts...)
+	if err != nil {
+		t.Fatalf("vnet: %v", err)
+	}
+	n.t = t
+	t.Cleanup(func() {
+		if err := n.Close(); err != nil {
+			t.Errorf("vnet: %v", err)
+		}
+	})
+	return n
+}
+
+// Dial создаёт сеть как New, но вне теста, например в Example функциях.
+// Установка ассоциации ограничена временем WithStepTimeout. Сеть закрывается Close.
+func Dial(ctx context.Context, m *model.Model, opts ...Option) (*Network, error) {
+	n := &Network{
+		stepTimeout: DefaultStepTimeout,
+		clientOpts:  []go61850.MmsClientOption{go61850.WithReportQueue(go61850.NewReportQueue(64))},
+	}
+	for _, opt := range opts {
+		opt(n)
+	}
+	n.server = server.New(m, n.serverOpts...)
+
+	clientConn, serverConn := net.Pipe()
+	n.node = newNode(n.server, serverConn)
+	n.conn = clientConn
+	if len(n.faults) > 0 {
+		n.conn = mmstest.NewFaultConn(clientConn, n.faults...)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, n.stepTimeout)
+	defer cancel()
+	client, err := go61850.NewMmsClient(ctx, n.conn, n.clientOpts...)
+	if err != nil {
+		n.Close()
+		return nil, fmt.Errorf("connect: %w", err)
+	}
+	if _, err := client.Initiate(ctx); err != nil {
+		n.Close()
+		return nil, fmt.Errorf("initiate: %w", err)
+	}
+	n.client = client
+	return n, nil
+}
+
+// Close закрывает соединение клиента, ожидает закрытия ассоциации на сервере
+// и возвращает ошибку серверного узла (например, неразобранный пакет клиента)
+func (n *Network) Close() error {
+	n.conn.Close()
+	<-n.node.stopped
+	if n.node.err != nil {
+		return fmt.Errorf("server node: %w", n.node.err)
+	}
+	return nil
+}
+
+// Server возвращает сервер сети
+func (n *Network) Server() *server.Server {
+	return n.server
+}
+
+// Client возвращает клиента с установленной ассоциацией
+func (n *Network) Client() *go61850.MmsClient {
+	return n.client
+}
+
+// EnableReport включает блок управления rc домена domainID: отчёты сервера
+// передаются клиенту как InformationReport. Возвращает функцию выключения.
+func (n *Network) EnableReport(domainID string, rc server.ReportControl) (disable func(), err error) {
+	return n.server.EnableReport(domainID, rc, func(r server.Report) {
+		n.node.report(domainID, r)
+	})
+}
+
+// Disconnect разрывает соединение со стороны сервера, как при отказе сети или IED,
+// и ожидает закрытия ассоциации на сервере
+func (n *Network) Disconnect() {
+	n.node.close()
+}
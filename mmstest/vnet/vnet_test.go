@@ -0,0 +1,128 @@
+package vnet_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/slonegd/go61850"
+	"github.com/slonegd/go61850/mmstest"
+	"github.com/slonegd/go61850/mmstest/vnet"
+	"github.com/slonegd/go61850/model"
+	"github.com/slonegd/go61850/osi/mms"
+	"github.com/slonegd/go61850/osi/mms/variant"
+	"github.com/slonegd/go61850/server"
+	"github.com/stretchr/testify/assert"
+)
+
+// newModel создаёт модель с двумя аналоговыми входами и набором данных Measurements
+func newModel() *model.Model {
+	anIn := func(name string, value float32) *model.DataObject {
+		return &model.DataObject{
+			Name: name,
+			Children: []model.DataNode{
+				&model.DataAttribute{Name: "mag", FC: mms.FCMX, Attributes: []*model.DataAttribute{
+					{Name: "f", FC: mms.FCMX, Value: variant.NewFloat32Variant(value), Triggers: model.TriggerDataChange},
+				}},
+				&model.DataAttribute{Name: "q", FC: mms.FCMX, Value: variant.NewBitStringVariant([]byte{0, 0}, 13)},
+			},
+		}
+	}
+	return &model.Model{
+		Name: "simpleIO",
+		LogicalDevices: []*model.LogicalDevice{{
+			Name: "simpleIOGenericIO",
+			LogicalNodes: []*model.LogicalNode{{
+				Name:        "GGIO1",
+				DataObjects: []*model.DataObject{anIn("AnIn1", 1.5), anIn("AnIn2", 2.5)},
+			}},
+			DataSets: []*model.DataSet{
+				{Name: "LLN0$Measurements", Members: []string{"GGIO1$MX$AnIn1", "GGIO1$MX$AnIn2"}},
+			},
+		}},
+	}
+}
+
+func TestValueChangeReport(t *testing.T) {
+	network := vnet.New(t, newModel())
+	_, err := network.EnableReport("simpleIOGenericIO", server.ReportControl{
+		Name: "LLN0$RP$MeasRCB", RptID: "Meas", DataSet: "LLN0$Measurements",
+		TrgOps: model.TriggerDataChange,
+	})
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	err = network.Run(ctx,
+		vnet.ExpectValue("simpleIOGenericIO", "GGIO1$MX$AnIn1$mag$f", variant.NewFloat32Variant(1.5)),
+		vnet.SetValue("simpleIOGenericIO", "GGIO1$MX$AnIn2$mag$f", variant.NewFloat32Variant(7)),
+		vnet.ExpectReport("Meas", func(r *go61850.Report) error {
+			assert.Equal(t, "simpleIOGenericIO/LLN0$Measurements", r.DataSet)
+			assert.Equal(t, []bool{false, true}, r.Inclusion)
+			assert.Equal(t, model.TriggerDataChange, r.Reasons[1])
+			assert.Equal(t, float32(7), r.Values[1].Structure()[0].Structure()[0].Float32())
+			return nil
+		}),
+		vnet.ExpectValue("simpleIOGenericIO", "GGIO1$MX$AnIn2$mag$f", variant.NewFloat32Variant(7)),
+		vnet.ExpectNoReport(50*time.Millisecond),
+	)
+	assert.NoError(t, err)
+
+	stats := network.Server().Stats()
+	assert.Equal(t, uint64(1), stats.Associations)
+	assert.Equal(t, uint64(1), stats.ReportsEmitted)
+}
+
+func TestRunStepError(t *testing.T) {
+	network := vnet.New(t, newModel(), vnet.WithStepTimeout(200*time.Millisecond))
+	err := network.Run(context.Background(),
+		vnet.ExpectValue("simpleIOGenericIO", "GGIO1$MX$AnIn1$mag$f", variant.NewFloat32Variant(2)),
+	)
+	assert.ErrorContains(t, err, "step 0: read simpleIOGenericIO/GGIO1$MX$AnIn1$mag$f: got")
+
+	// Отчёт не приходит: шаг завершается по времени
+	err = network.Run(context.Background(), vnet.ExpectReport("Meas", nil))
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+}
+
+func TestFaults(t *testing.T) {
+	network := vnet.New(t, newModel(), vnet.WithFaults(mmstest.PartialWrites(3), mmstest.DelayReads(time.Millisecond)))
+	err := network.Run(context.Background(),
+		vnet.ExpectValue("simpleIOGenericIO", "GGIO1$MX$AnIn2$mag$f", variant.NewFloat32Variant(2.5)),
+	)
+	assert.NoError(t, err)
+
+	network.Disconnect()
+	err = network.Run(context.Background(),
+		vnet.ExpectValue("simpleIOGenericIO", "GGIO1$MX$AnIn2$mag$f", variant.NewFloat32Variant(2.5)),
+	)
+	assert.Error(t, err)
+	assert.Equal(t, int64(0), network.Server().Stats().ActiveAssociations)
+}
+
+func TestNamesPagination(t *testing.T) {
+	network := vnet.New(t, newModel(), vnet.WithServerOptions(server.WithMaxNames(2)))
+	ctx := context.Background()
+
+	// Имена переменных приходят пятью ответами по два имени
+	var names []string
+	for name, err := range network.Client().Names(ctx, mms.ObjectClassNamedVariable, "simpleIOGenericIO") {
+		assert.NoError(t, err)
+		names = append(names, name)
+	}
+	assert.Equal(t, uint64(5), network.Server().Stats().Requests["getNameList"])
+	assert.Equal(t, []string{
+		"GGIO1", "GGIO1$MX", "GGIO1$MX$AnIn1", "GGIO1$MX$AnIn1$mag", "GGIO1$MX$AnIn1$mag$f",
+		"GGIO1$MX$AnIn1$q", "GGIO1$MX$AnIn2", "GGIO1$MX$AnIn2$mag", "GGIO1$MX$AnIn2$mag$f", "GGIO1$MX$AnIn2$q",
+	}, names)
+
+	// Перебор прерывается без лишних запросов
+	for range network.Client().Names(ctx, mms.ObjectClassNamedVariable, "simpleIOGenericIO") {
+		break
+	}
+	assert.Equal(t, uint64(6), network.Server().Stats().Requests["getNameList"])
+
+	for _, err := range network.Client().Names(ctx, mms.ObjectClassNamedVariable, "unknown") {
+		assert.Error(t, err)
+	}
+}
@@ -0,0 +1,173 @@
+package model
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"time"
+)
+
+// EntryIDSize - размер EntryID в байтах (OCTET STRING (SIZE(8)) согласно IEC 61850-8-1)
+const EntryIDSize = 8
+
+// EntryID представляет идентификатор записи буферизированного отчёта или журнала.
+// Для клиента значение непрозрачно: IEC 61850-8-1 не задаёт его структуру
+// и порядок байт (libiec61850, например, записывает счётчик в порядке байт
+// процессора, обычно little-endian). Ресинхронизация использует только
+// равенство EntryID.
+type EntryID [EntryIDSize]byte
+
+// NewEntryID создаёт EntryID из OCTET STRING.
+// Возвращает ошибку, если длина не равна EntryIDSize.
+func NewEntryID(data []byte) (EntryID, error) {
+	var id EntryID
+	if len(data) != EntryIDSize {
+		return id, fmt.Errorf("invalid EntryID length: expected %d bytes, got %d", EntryIDSize, len(data))
+	}
+	copy(id[:], data)
+	return id, nil
+}
+
+// ParseEntryID разбирает EntryID из hex-строки (формат String)
+func ParseEntryID(s string) (EntryID, error) {
+	data, err := hex.DecodeString(s)
+	if err != nil {
+		return EntryID{}, fmt.Errorf("invalid EntryID %q: %w", s, err)
+	}
+	return NewEntryID(data)
+}
+
+// IsZero возвращает true для нулевого EntryID (означает "с начала буфера")
+func (id EntryID) IsZero() bool {
+	return id == EntryID{}
+}
+
+// Compare сравнивает два EntryID побайтово.
+// Возвращает -1, 0 или 1, как bytes.Compare. Порядок совпадает с порядком
+// выдачи записей, только если сервер кодирует счётчик big-endian, как журналы
+// пакета server.
+func (id EntryID) Compare(other EntryID) int {
+	return bytes.Compare(id[:], other[:])
+}
+
+// Bytes возвращает EntryID как OCTET STRING
+func (id EntryID) Bytes() []byte {
+	return append([]byte(nil), id[:]...)
+}
+
+// String возвращает EntryID в виде hex-строки
+func (id EntryID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// MarshalText кодирует EntryID как hex-строку (например, для JSON)
+func (id EntryID) MarshalText() ([]byte, error) {
+	return []byte(id.String()), nil
+}
+
+// UnmarshalText разбирает EntryID из hex-строки
+func (id *EntryID) UnmarshalText(text []byte) error {
+	parsed, err := ParseEntryID(string(text))
+	if err != nil {
+		return err
+	}
+	*id = parsed
+	return nil
+}
+
+// TimeOfEntrySize - размер TimeOfEntry в байтах (BinaryTime с датой)
+const TimeOfEntrySize = 6
+
+// binaryTimeEpoch - начало отсчёта дней для BinaryTime (1 января 1984 года)
+var binaryTimeEpoch = time.Date(1984, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// TimeOfEntry представляет время записи (EntryTime) согласно IEC 61850-8-1.
+// Кодируется как BinaryTime из 6 байт:
+// - 4 байта: миллисекунды от полуночи
+// - 2 байта: дни с 1 января 1984 года
+type TimeOfEntry struct {
+	// MsOfDay - миллисекунды от полуночи (0..86399999)
+	MsOfDay uint32
+	// Days - количество дней с 1 января 1984 года
+	Days uint16
+}
+
+// NewTimeOfEntry создаёт TimeOfEntry из time.Time с точностью до миллисекунды.
+// Время вне диапазона BinaryTime (с 1984 года по 65535 день эпохи, 2163 год)
+// не представимо и приводится к его границе.
+func NewTimeOfEntry(t time.Time) TimeOfEntry {
+	t = t.UTC()
+	if t.Before(binaryTimeEpoch) {
+		return TimeOfEntry{}
+	}
+	d := t.Sub(binaryTimeEpoch)
+	days := d / (24 * time.Hour)
+	if days > math.MaxUint16 {
+		return TimeOfEntry{MsOfDay: 24*60*60*1000 - 1, Days: math.MaxUint16}
+	}
+	ms := (d - days*24*time.Hour) / time.Millisecond
+	return TimeOfEntry{
+		MsOfDay: uint32(ms),
+		Days:    uint16(days),
+	}
+}
+
+// ParseTimeOfEntry декодирует TimeOfEntry из 6 байт BinaryTime
+func ParseTimeOfEntry(data []byte) (TimeOfEntry, error) {
+	if len(data) != TimeOfEntrySize {
+		return TimeOfEntry{}, fmt.Errorf("invalid TimeOfEntry length: expected %d bytes, got %d", TimeOfEntrySize, len(data))
+	}
+	return TimeOfEntry{
+		MsOfDay: binary.BigEndian.Uint32(data[0:4]),
+		Days:    binary.BigEndian.Uint16(data[4:6]),
+	}, nil
+}
+
+// Bytes кодирует TimeOfEntry в 6 байт BinaryTime
+func (t TimeOfEntry) Bytes() []byte {
+	buffer := make([]byte, TimeOfEntrySize)
+	binary.BigEndian.PutUint32(buffer[0:4], t.MsOfDay)
+	binary.BigEndian.PutUint16(buffer[4:6], t.Days)
+	return buffer
+}
+
+// Time возвращает TimeOfEntry как time.Time в UTC
+func (t TimeOfEntry) Time() time.Time {
+	return binaryTimeEpoch.
+		AddDate(0, 0, int(t.Days)).
+		Add(time.Duration(t.MsOfDay) * time.Millisecond)
+}
+
+// Compare сравнивает два TimeOfEntry.
+// Возвращает -1, если t раньше other, 1 - если позже, и 0 при равенстве.
+func (t TimeOfEntry) Compare(other TimeOfEntry) int {
+	switch {
+	case t.Days < other.Days:
+		return -1
+	case t.Days > other.Days:
+		return 1
+	case t.MsOfDay < other.MsOfDay:
+		return -1
+	case t.MsOfDay > other.MsOfDay:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Before возвращает true, если t раньше other
+func (t TimeOfEntry) Before(other TimeOfEntry) bool {
+	return t.Compare(other) < 0
+}
+
+// After возвращает true, если t позже other
+func (t TimeOfEntry) After(other TimeOfEntry) bool {
+	return t.Compare(other) > 0
+}
+
+// String возвращает TimeOfEntry в формате RFC3339 с миллисекундами
+func (t TimeOfEntry) String() string {
+	return t.Time().Format("2006-01-02T15:04:05.000Z07:00")
+}
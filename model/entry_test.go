@@ -0,0 +1,82 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEntryID(t *testing.T) {
+	a, err := ParseEntryID("0000000000000001")
+	assert.NoError(t, err)
+	b, err := NewEntryID([]byte{0, 0, 0, 0, 0, 0, 1, 0})
+	assert.NoError(t, err)
+
+	assert.Equal(t, -1, a.Compare(b))
+	assert.Equal(t, 1, b.Compare(a))
+	assert.Equal(t, 0, a.Compare(a))
+	assert.Equal(t, "0000000000000100", b.String())
+	assert.True(t, EntryID{}.IsZero())
+	assert.False(t, a.IsZero())
+
+	_, err = NewEntryID([]byte{1, 2, 3})
+	assert.EqualError(t, err, "invalid EntryID length: expected 8 bytes, got 3")
+
+	_, err = ParseEntryID("zz")
+	assert.Error(t, err)
+
+	text, err := b.MarshalText()
+	assert.NoError(t, err)
+	assert.Equal(t, "0000000000000100", string(text))
+	var parsed EntryID
+	assert.NoError(t, parsed.UnmarshalText(text))
+	assert.Equal(t, b, parsed)
+	assert.Error(t, parsed.UnmarshalText([]byte("0102")))
+}
+
+func TestTimeOfEntry(t *testing.T) {
+	tests := []struct {
+		name  string
+		bytes []byte
+		want  time.Time
+	}{
+		{
+			name:  "начало эпохи",
+			bytes: []byte{0, 0, 0, 0, 0, 0},
+			want:  time.Date(1984, time.January, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			// 12:00:00.500 = 43200500 мс = 0x0293_2FF4, 2024-01-01 = 14610 дней = 0x3912
+			name:  "полдень 2024-01-01",
+			bytes: []byte{0x02, 0x93, 0x2f, 0xf4, 0x39, 0x12},
+			want:  time.Date(2024, time.January, 1, 12, 0, 0, 500_000_000, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTimeOfEntry(tt.bytes)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got.Time())
+			assert.Equal(t, tt.bytes, got.Bytes())
+			assert.Equal(t, got, NewTimeOfEntry(tt.want))
+		})
+	}
+
+	early := NewTimeOfEntry(time.Date(2024, time.January, 1, 23, 59, 59, 0, time.UTC))
+	late := NewTimeOfEntry(time.Date(2024, time.January, 2, 0, 0, 0, 0, time.UTC))
+	assert.True(t, early.Before(late))
+	assert.True(t, late.After(early))
+	assert.Equal(t, 0, early.Compare(early))
+	assert.Equal(t, "2024-01-02T00:00:00.000Z", late.String())
+
+	// Время вне диапазона приводится к границам, а не переполняет Days
+	assert.Equal(t, TimeOfEntry{}, NewTimeOfEntry(time.Date(1970, time.January, 1, 0, 0, 0, 0, time.UTC)))
+	last := TimeOfEntry{MsOfDay: 86399999, Days: 65535}
+	assert.Equal(t, last, NewTimeOfEntry(time.Date(2200, time.January, 1, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, last, NewTimeOfEntry(last.Time()))
+
+	_, err := ParseTimeOfEntry([]byte{1})
+	assert.EqualError(t, err, "invalid TimeOfEntry length: expected 6 bytes, got 1")
+}
@@ -0,0 +1,354 @@
+package model
+
+import (
+	"fmt"
+
+	"github.com/slonegd/go61850/osi/mms/variant"
+)
+
+// CtlModel представляет модель управления (ctlModel) согласно IEC 61850-7-2
+type CtlModel int32
+
+const (
+	// CtlModelStatusOnly - только статус, управление невозможно
+	CtlModelStatusOnly CtlModel = 0
+	// CtlModelDirectNormal - прямое управление с обычной безопасностью
+	CtlModelDirectNormal CtlModel = 1
+	// CtlModelSBONormal - select-before-operate с обычной безопасностью
+	CtlModelSBONormal CtlModel = 2
+	// CtlModelDirectEnhanced - прямое управление с усиленной безопасностью
+	CtlModelDirectEnhanced CtlModel = 3
+	// CtlModelSBOEnhanced - select-before-operate с усиленной безопасностью
+	CtlModelSBOEnhanced CtlModel = 4
+)
+
+// IsValid проверяет, что значение входит в диапазон стандарта
+func (m CtlModel) IsValid() bool {
+	return m >= CtlModelStatusOnly && m <= CtlModelSBOEnhanced
+}
+
+// String возвращает строковое представление CtlModel
+func (m CtlModel) String() string {
+	switch m {
+	case CtlModelStatusOnly:
+		return "status-only"
+	case CtlModelDirectNormal:
+		return "direct-with-normal-security"
+	case CtlModelSBONormal:
+		return "sbo-with-normal-security"
+	case CtlModelDirectEnhanced:
+		return "direct-with-enhanced-security"
+	case CtlModelSBOEnhanced:
+		return "sbo-with-enhanced-security"
+	default:
+		return fmt.Sprintf("CtlModel(%d)", int32(m))
+	}
+}
+
+// Beh представляет значение Beh.stVal и Mod.stVal согласно IEC 61850-7-4
+type Beh int32
+
+const (
+	// BehOn - включено
+	BehOn Beh = 1
+	// BehOnBlocked - включено, выходы заблокированы
+	BehOnBlocked Beh = 2
+	// BehTest - тестовый режим
+	BehTest Beh = 3
+	// BehTestBlocked - тестовый режим, выходы заблокированы
+	BehTestBlocked Beh = 4
+	// BehOff - выключено
+	BehOff Beh = 5
+)
+
+// IsValid проверяет, что значение входит в диапазон стандарта
+func (b Beh) IsValid() bool {
+	return b >= BehOn && b <= BehOff
+}
+
+// String возвращает строковое представление Beh
+func (b Beh) String() string {
+	switch b {
+	case BehOn:
+		return "on"
+	case BehOnBlocked:
+		return "on-blocked"
+	case BehTest:
+		return "test"
+	case BehTestBlocked:
+		return "test/blocked"
+	case BehOff:
+		return "off"
+	default:
+		return fmt.Sprintf("Beh(%d)", int32(b))
+	}
+}
+
+// OrCat представляет категорию источника команды (orCat) согласно IEC 61850-7-3
+type OrCat int32
+
+const (
+	OrCatNotSupported     OrCat = 0
+	OrCatBayControl       OrCat = 1
+	OrCatStationControl   OrCat = 2
+	OrCatRemoteControl    OrCat = 3
+	OrCatAutomaticBay     OrCat = 4
+	OrCatAutomaticStation OrCat = 5
+	OrCatAutomaticRemote  OrCat = 6
+	OrCatMaintenance      OrCat = 7
+	OrCatProcess          OrCat = 8
+)
+
+// IsValid проверяет, что значение входит в диапазон стандарта
+func (o OrCat) IsValid() bool {
+	return o >= OrCatNotSupported && o <= OrCatProcess
+}
+
+// String возвращает строковое представление OrCat
+func (o OrCat) String() string {
+	switch o {
+	case OrCatNotSupported:
+		return "not-supported"
+	case OrCatBayControl:
+		return "bay-control"
+	case OrCatStationControl:
+		return "station-control"
+	case OrCatRemoteControl:
+		return "remote-control"
+	case OrCatAutomaticBay:
+		return "automatic-bay"
+	case OrCatAutomaticStation:
+		return "automatic-station"
+	case OrCatAutomaticRemote:
+		return "automatic-remote"
+	case OrCatMaintenance:
+		return "maintenance"
+	case OrCatProcess:
+		return "process"
+	default:
+		return fmt.Sprintf("OrCat(%d)", int32(o))
+	}
+}
+
+// Health представляет значение Health.stVal согласно IEC 61850-7-4
+type Health int32
+
+const (
+	// HealthOk - нормальное состояние
+	HealthOk Health = 1
+	// HealthWarning - незначительные проблемы, работа продолжается
+	HealthWarning Health = 2
+	// HealthAlarm - серьёзная неисправность
+	HealthAlarm Health = 3
+)
+
+// IsValid проверяет, что значение входит в диапазон стандарта
+func (h Health) IsValid() bool {
+	return h >= HealthOk && h <= HealthAlarm
+}
+
+// String возвращает строковое представление Health
+func (h Health) String() string {
+	switch h {
+	case HealthOk:
+		return "ok"
+	case HealthWarning:
+		return "warning"
+	case HealthAlarm:
+		return "alarm"
+	default:
+		return fmt.Sprintf("Health(%d)", int32(h))
+	}
+}
+
+// AddCause представляет дополнительную причину отказа команды управления
+// (AddCause в LastApplError) согласно IEC 61850-7-2
+type AddCause int32
+
+const (
+	AddCauseUnknown                     AddCause = 0
+	AddCauseNotSupported                AddCause = 1
+	AddCauseBlockedBySwitchingHierarchy AddCause = 2
+	AddCauseSelectFailed                AddCause = 3
+	AddCauseInvalidPosition             AddCause = 4
+	AddCausePositionReached             AddCause = 5
+	AddCauseParameterChangeInExecution  AddCause = 6
+	AddCauseStepLimit                   AddCause = 7
+	AddCauseBlockedByMode               AddCause = 8
+	AddCauseBlockedByProcess            AddCause = 9
+	AddCauseBlockedByInterlocking       AddCause = 10
+	AddCauseBlockedBySynchrocheck       AddCause = 11
+	AddCauseCommandAlreadyInExecution   AddCause = 12
+	AddCauseBlockedByHealth             AddCause = 13
+	AddCauseOneOfNControl               AddCause = 14
+	AddCauseAbortionByCancel            AddCause = 15
+	AddCauseTimeLimitOver               AddCause = 16
+	AddCauseAbortionByTrip              AddCause = 17
+	AddCauseObjectNotSelected           AddCause = 18
+	AddCauseObjectAlreadySelected       AddCause = 19
+	AddCauseNoAccessAuthority           AddCause = 20
+	AddCauseEndedWithOvershoot          AddCause = 21
+	AddCauseAbortionDueToDeviation      AddCause = 22
+	AddCauseAbortionByCommunicationLoss AddCause = 23
+	AddCauseBlockedByCommand            AddCause = 24
+	AddCauseNone                        AddCause = 25
+	AddCauseInconsistentParameters      AddCause = 26
+	AddCauseLockedByOtherClient         AddCause = 27
+)
+
+// IsValid проверяет, что значение входит в диапазон стандарта
+func (a AddCause) IsValid() bool {
+	return a >= AddCauseUnknown && a <= AddCauseLockedByOtherClient
+}
+
+// String возвращает строковое представление AddCause
+func (a AddCause) String() string {
+	switch a {
+	case AddCauseUnknown:
+		return "unknown"
+	case AddCauseNotSupported:
+		return "not-supported"
+	case AddCauseBlockedBySwitchingHierarchy:
+		return "blocked-by-switching-hierarchy"
+	case AddCauseSelectFailed:
+		return "select-failed"
+	case AddCauseInvalidPosition:
+		return "invalid-position"
+	case AddCausePositionReached:
+		return "position-reached"
+	case AddCauseParameterChangeInExecution:
+		return "parameter-change-in-execution"
+	case AddCauseStepLimit:
+		return "step-limit"
+	case AddCauseBlockedByMode:
+		return "blocked-by-mode"
+	case AddCauseBlockedByProcess:
+		return "blocked-by-process"
+	case AddCauseBlockedByInterlocking:
+		return "blocked-by-interlocking"
+	case AddCauseBlockedBySynchrocheck:
+		return "blocked-by-synchrocheck"
+	case AddCauseCommandAlreadyInExecution:
+		return "command-already-in-execution"
+	case AddCauseBlockedByHealth:
+		return "blocked-by-health"
+	case AddCauseOneOfNControl:
+		return "1-of-n-control"
+	case AddCauseAbortionByCancel:
+		return "abortion-by-cancel"
+	case AddCauseTimeLimitOver:
+		return "time-limit-over"
+	case AddCauseAbortionByTrip:
+		return "abortion-by-trip"
+	case AddCauseObjectNotSelected:
+		return "object-not-selected"
+	case AddCauseObjectAlreadySelected:
+		return "object-already-selected"
+	case AddCauseNoAccessAuthority:
+		return "no-access-authority"
+	case AddCauseEndedWithOvershoot:
+		return "ended-with-overshoot"
+	case AddCauseAbortionDueToDeviation:
+		return "abortion-due-to-deviation"
+	case AddCauseAbortionByCommunicationLoss:
+		return "abortion-by-communication-loss"
+	case AddCauseBlockedByCommand:
+		return "blocked-by-command"
+	case AddCauseNone:
+		return "none"
+	case AddCauseInconsistentParameters:
+		return "inconsistent-parameters"
+	case AddCauseLockedByOtherClient:
+		return "locked-by-other-client"
+	default:
+		return fmt.Sprintf("AddCause(%d)", int32(a))
+	}
+}
+
+// ControlError представляет код ошибки команды управления (Error в LastApplError)
+// согласно IEC 61850-8-1
+type ControlError int32
+
+const (
+	// ControlErrorNoError - ошибки нет
+	ControlErrorNoError ControlError = 0
+	// ControlErrorUnknown - неизвестная ошибка
+	ControlErrorUnknown ControlError = 1
+	// ControlErrorTimeoutTestNotOk - истекло время проверки
+	ControlErrorTimeoutTestNotOk ControlError = 2
+	// ControlErrorOperatorTestNotOk - проверка оператором не пройдена
+	ControlErrorOperatorTestNotOk ControlError = 3
+)
+
+// IsValid проверяет, что значение входит в диапазон стандарта
+func (e ControlError) IsValid() bool {
+	return e >= ControlErrorNoError && e <= ControlErrorOperatorTestNotOk
+}
+
+// String возвращает строковое представление ControlError
+func (e ControlError) String() string {
+	switch e {
+	case ControlErrorNoError:
+		return "no-error"
+	case ControlErrorUnknown:
+		return "unknown"
+	case ControlErrorTimeoutTestNotOk:
+		return "timeout-test-not-ok"
+	case ControlErrorOperatorTestNotOk:
+		return "operator-test-not-ok"
+	default:
+		return fmt.Sprintf("ControlError(%d)", int32(e))
+	}
+}
+
+// enum ограничивает типы перечислений, которые можно получить из Variant
+type enum interface {
+	~int32
+	IsValid() bool
+}
+
+// enumFromVariant извлекает целочисленное значение из Variant и проверяет,
+// что оно входит в диапазон перечисления.
+func enumFromVariant[E enum](v *variant.Variant, name string) (E, error) {
+	if v == nil {
+		return 0, fmt.Errorf("%s: variant is nil", name)
+	}
+	if v.Type() != variant.Int32 {
+		return 0, fmt.Errorf("%s: expected int32 variant, got %s", name, v.Type())
+	}
+	e := E(v.Int32())
+	if !e.IsValid() {
+		return e, fmt.Errorf("%s: value %d out of range", name, v.Int32())
+	}
+	return e, nil
+}
+
+// CtlModelFromVariant преобразует целочисленный Variant в CtlModel
+func CtlModelFromVariant(v *variant.Variant) (CtlModel, error) {
+	return enumFromVariant[CtlModel](v, "ctlModel")
+}
+
+// BehFromVariant преобразует целочисленный Variant в Beh (подходит и для Mod.stVal)
+func BehFromVariant(v *variant.Variant) (Beh, error) {
+	return enumFromVariant[Beh](v, "beh")
+}
+
+// OrCatFromVariant преобразует целочисленный Variant в OrCat
+func OrCatFromVariant(v *variant.Variant) (OrCat, error) {
+	return enumFromVariant[OrCat](v, "orCat")
+}
+
+// HealthFromVariant преобразует целочисленный Variant в Health
+func HealthFromVariant(v *variant.Variant) (Health, error) {
+	return enumFromVariant[Health](v, "health")
+}
+
+// AddCauseFromVariant преобразует целочисленный Variant в AddCause
+func AddCauseFromVariant(v *variant.Variant) (AddCause, error) {
+	return enumFromVariant[AddCause](v, "addCause")
+}
+
+// ControlErrorFromVariant преобразует целочисленный Variant в ControlError
+func ControlErrorFromVariant(v *variant.Variant) (ControlError, error) {
+	return enumFromVariant[ControlError](v, "error")
+}
@@ -0,0 +1,100 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/slonegd/go61850/osi/mms/variant"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCtlModelFromVariant(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     *variant.Variant
+		want      CtlModel
+		wantError string
+	}{
+		{
+			name:  "sbo с усиленной безопасностью",
+			value: variant.NewInt32Variant(4),
+			want:  CtlModelSBOEnhanced,
+		},
+		{
+			name:  "только статус",
+			value: variant.NewInt32Variant(0),
+			want:  CtlModelStatusOnly,
+		},
+		{
+			name:      "значение вне диапазона",
+			value:     variant.NewInt32Variant(5),
+			want:      CtlModel(5),
+			wantError: "ctlModel: value 5 out of range",
+		},
+		{
+			name:      "не целочисленный тип",
+			value:     variant.NewFloat32Variant(1),
+			wantError: "ctlModel: expected int32 variant, got float32",
+		},
+		{
+			name:      "nil",
+			value:     nil,
+			wantError: "ctlModel: variant is nil",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CtlModelFromVariant(tt.value)
+			if tt.wantError != "" {
+				assert.EqualError(t, err, tt.wantError)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestEnumFromVariant(t *testing.T) {
+	beh, err := BehFromVariant(variant.NewInt32Variant(3))
+	assert.NoError(t, err)
+	assert.Equal(t, BehTest, beh)
+	assert.Equal(t, "test", beh.String())
+
+	_, err = BehFromVariant(variant.NewInt32Variant(0))
+	assert.EqualError(t, err, "beh: value 0 out of range")
+
+	orCat, err := OrCatFromVariant(variant.NewInt32Variant(3))
+	assert.NoError(t, err)
+	assert.Equal(t, "remote-control", orCat.String())
+
+	health, err := HealthFromVariant(variant.NewInt32Variant(2))
+	assert.NoError(t, err)
+	assert.Equal(t, HealthWarning, health)
+
+	assert.Equal(t, "Health(9)", Health(9).String())
+}
+
+func TestAddCause(t *testing.T) {
+	addCause, err := AddCauseFromVariant(variant.NewInt32Variant(10))
+	assert.NoError(t, err)
+	assert.Equal(t, AddCauseBlockedByInterlocking, addCause)
+	assert.Equal(t, "blocked-by-interlocking", addCause.String())
+	assert.Equal(t, "AddCause(28)", AddCause(28).String())
+
+	controlError, err := ControlErrorFromVariant(variant.NewInt32Variant(3))
+	assert.NoError(t, err)
+	assert.Equal(t, "operator-test-not-ok", controlError.String())
+}
+
+func TestTrigger(t *testing.T) {
+	trigger := TriggerDataChange | TriggerGI
+	assert.Equal(t, "dchg|gi", trigger.String())
+	assert.True(t, trigger.Has(TriggerGI))
+	assert.False(t, trigger.Has(TriggerGI|TriggerIntegrity))
+
+	v := trigger.BitString(6)
+	assert.Equal(t, []byte{0x44}, v.BitString().Data)
+	assert.Equal(t, trigger, TriggerFromBitString(v))
+	assert.Equal(t, TriggerApplication, TriggerFromBitString(TriggerApplication.BitString(7)))
+}
@@ -0,0 +1,223 @@
+package model
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/slonegd/go61850/osi/mms"
+)
+
+// ExportFormat - формат описания модели, формируемого Export
+type ExportFormat int
+
+const (
+	// ExportMarkdown - документ Markdown: раздел на логическое устройство с таблицами
+	ExportMarkdown ExportFormat = iota
+	// ExportCSV - одна строка на атрибут, набор данных, блок управления отчётами или журнал
+	ExportCSV
+)
+
+// Виды строк описания модели (колонка kind в CSV)
+const (
+	exportKindAttribute = "DA"
+	exportKindDataSet   = "DataSet"
+	exportKindRCB       = "RCB"
+	exportKindJournal   = "Journal"
+)
+
+// exportRow - строка описания модели
+type exportRow struct {
+	Kind      string
+	LDevice   string
+	Reference string // ссылка IEC 61850 без LD: "LN.DO.DA", для набора данных - MMS имя
+	FC        mms.FunctionalConstraint
+	Type      string
+	Triggers  string
+	Members   []string
+}
+
+// Export записывает описание модели: логические устройства, логические узлы, объекты
+// и атрибуты данных с FC, типами и условиями отчётов, наборы данных, блоки управления
+// отчётами и журналы. Модель может быть получена DiscoverModel или построена из SCL.
+// Тип атрибута известен, только если в модели заполнено значение Value.
+func Export(w io.Writer, m *Model, format ExportFormat) error {
+	switch format {
+	case ExportMarkdown:
+		return exportMarkdown(w, m)
+	case ExportCSV:
+		return exportCSV(w, m)
+	default:
+		return fmt.Errorf("unknown export format %d", format)
+	}
+}
+
+// exportRows формирует строки описания логического устройства
+func (ld *LogicalDevice) exportRows() []exportRow {
+	var rows []exportRow
+	for _, ln := range ld.LogicalNodes {
+		for _, do := range ln.DataObjects {
+			if fc, ok := do.rcbFC(); ok {
+				kind := "unbuffered"
+				if fc == mms.FCBR {
+					kind = "buffered"
+				}
+				rows = append(rows, exportRow{Kind: exportKindRCB, LDevice: ld.Name,
+					Reference: ln.Name + "." + do.Name, FC: fc, Type: kind})
+				continue
+			}
+			rows = do.appendExportRows(rows, ld.Name, ln.Name+"."+do.Name, 0)
+		}
+	}
+	for _, ds := range ld.DataSets {
+		rows = append(rows, exportRow{Kind: exportKindDataSet, LDevice: ld.Name,
+			Reference: ds.Name, Members: ds.Members})
+	}
+	for _, journal := range ld.Journals {
+		rows = append(rows, exportRow{Kind: exportKindJournal, LDevice: ld.Name, Reference: journal})
+	}
+	return rows
+}
+
+// rcbFC возвращает FC блока управления отчётами (BR или RP), если все атрибуты DO
+// имеют это FC: в отображении MMS RCB выглядит как DO логического узла с FC BR или RP
+func (do *DataObject) rcbFC() (mms.FunctionalConstraint, bool) {
+	var fc mms.FunctionalConstraint
+	for _, child := range do.Children {
+		da, ok := child.(*DataAttribute)
+		if !ok || (da.FC != mms.FCBR && da.FC != mms.FCRP) || (fc != "" && da.FC != fc) {
+			return "", false
+		}
+		fc = da.FC
+	}
+	return fc, fc != ""
+}
+
+// appendExportRows добавляет строки атрибутов DO и его SDO
+func (do *DataObject) appendExportRows(rows []exportRow, ldName, reference string, triggers Trigger) []exportRow {
+	for _, child := range do.Children {
+		switch node := child.(type) {
+		case *DataObject:
+			rows = node.appendExportRows(rows, ldName, reference+"."+node.Name, triggers)
+		case *DataAttribute:
+			rows = node.appendExportRows(rows, ldName, reference+"."+node.Name, node.FC, node.Triggers)
+		}
+	}
+	return rows
+}
+
+// appendExportRows добавляет строку атрибута и его компонентов; компоненты наследуют
+// условия отчётов атрибута верхнего уровня
+func (da *DataAttribute) appendExportRows(rows []exportRow, ldName, reference string, fc mms.FunctionalConstraint, triggers Trigger) []exportRow {
+	if da.Triggers != 0 {
+		triggers = da.Triggers
+	}
+	rows = append(rows, exportRow{Kind: exportKindAttribute, LDevice: ldName, Reference: reference,
+		FC: fc, Type: da.typeName(), Triggers: triggers.String()})
+	for _, component := range da.Attributes {
+		rows = component.appendExportRows(rows, ldName, reference+"."+component.Name, fc, triggers)
+	}
+	return rows
+}
+
+// typeName возвращает тип атрибута по значению или MMS типу (Type);
+// пусто, если оба неизвестны
+func (da *DataAttribute) typeName() string {
+	switch {
+	case len(da.Attributes) > 0:
+		return "structure"
+	case da.Value != nil:
+		return da.Value.Type().String()
+	case da.Type != nil:
+		return da.Type.Type.String()
+	default:
+		return ""
+	}
+}
+
+// exportCSV записывает описание модели в CSV с заголовком
+func exportCSV(w io.Writer, m *Model) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"kind", "logical_device", "reference", "fc", "type", "triggers", "members"})
+	for _, ld := range m.LogicalDevices {
+		for _, row := range ld.exportRows() {
+			writer.Write([]string{row.Kind, row.LDevice, row.Reference, string(row.FC), row.Type,
+				row.Triggers, strings.Join(row.Members, " ")})
+		}
+	}
+	for _, da := range m.VMDVariables {
+		writer.Write([]string{exportKindAttribute, "", da.Name, "", da.typeName(), "", ""})
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// exportMarkdown записывает описание модели в Markdown
+func exportMarkdown(w io.Writer, m *Model) error {
+	var b strings.Builder
+	title := m.Name
+	if title == "" {
+		title = "IED"
+	}
+	fmt.Fprintf(&b, "# %s\n", markdownCell(title))
+
+	for _, ld := range m.LogicalDevices {
+		fmt.Fprintf(&b, "\n## %s\n", markdownCell(ld.Name))
+
+		var attributes, dataSets, rcbs, journals []exportRow
+		for _, row := range ld.exportRows() {
+			switch row.Kind {
+			case exportKindAttribute:
+				attributes = append(attributes, row)
+			case exportKindDataSet:
+				dataSets = append(dataSets, row)
+			case exportKindRCB:
+				rcbs = append(rcbs, row)
+			case exportKindJournal:
+				journals = append(journals, row)
+			}
+		}
+
+		if len(attributes) > 0 {
+			b.WriteString("\n### Data attributes\n\n| Reference | FC | Type | Triggers |\n|---|---|---|---|\n")
+			for _, row := range attributes {
+				fmt.Fprintf(&b, "| %s | %s | %s | %s |\n",
+					markdownCell(row.Reference), row.FC, markdownCell(row.Type), markdownCell(row.Triggers))
+			}
+		}
+		if len(dataSets) > 0 {
+			b.WriteString("\n### Data sets\n\n| Name | Members |\n|---|---|\n")
+			for _, row := range dataSets {
+				fmt.Fprintf(&b, "| %s | %s |\n", markdownCell(row.Reference), markdownCell(strings.Join(row.Members, ", ")))
+			}
+		}
+		if len(rcbs) > 0 {
+			b.WriteString("\n### Report control blocks\n\n| Reference | FC | Kind |\n|---|---|---|\n")
+			for _, row := range rcbs {
+				fmt.Fprintf(&b, "| %s | %s | %s |\n", markdownCell(row.Reference), row.FC, row.Type)
+			}
+		}
+		if len(journals) > 0 {
+			b.WriteString("\n### Journals\n\n")
+			for _, row := range journals {
+				fmt.Fprintf(&b, "- %s\n", markdownCell(row.Reference))
+			}
+		}
+	}
+
+	if len(m.VMDVariables) > 0 {
+		b.WriteString("\n## VMD variables\n\n| Name | Type |\n|---|---|\n")
+		for _, da := range m.VMDVariables {
+			fmt.Fprintf(&b, "| %s | %s |\n", markdownCell(da.Name), markdownCell(da.typeName()))
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// markdownCell экранирует символы, нарушающие разметку таблицы Markdown
+func markdownCell(s string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ").Replace(s)
+}
@@ -0,0 +1,69 @@
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/slonegd/go61850/osi/mms"
+	"github.com/slonegd/go61850/osi/mms/variant"
+	"github.com/stretchr/testify/assert"
+)
+
+func newExportModel() *Model {
+	ld := NewLogicalDeviceFromNames("LD0", []string{
+		"LLN0", "LLN0$BR", "LLN0$BR$brcbEV01", "LLN0$BR$brcbEV01$RptEna",
+		"GGIO1", "GGIO1$MX", "GGIO1$MX$AnIn1", "GGIO1$MX$AnIn1$mag", "GGIO1$MX$AnIn1$mag$f",
+	}, nil)
+	da, _, _ := ld.LogicalNode("GGIO1").Attribute(mms.FCMX, "AnIn1", "mag", "f")
+	da.Value = variant.NewFloat32Variant(1.5)
+	ld.LogicalNode("GGIO1").DataObject("AnIn1").Children[0].(*DataAttribute).Triggers = TriggerDataChange
+	ld.DataSets = []*DataSet{{Name: "LLN0$Events", Members: []string{"GGIO1$MX$AnIn1"}}}
+	ld.Journals = []string{"LLN0$EventLog"}
+	return &Model{Name: "IED1", LogicalDevices: []*LogicalDevice{ld}}
+}
+
+func TestExportMarkdown(t *testing.T) {
+	var b strings.Builder
+	assert.NoError(t, Export(&b, newExportModel(), ExportMarkdown))
+	assert.Equal(t, `# IED1
+
+## LD0
+
+### Data attributes
+
+| Reference | FC | Type | Triggers |
+|---|---|---|---|
+| GGIO1.AnIn1.mag | MX | structure | dchg |
+| GGIO1.AnIn1.mag.f | MX | float32 | dchg |
+
+### Data sets
+
+| Name | Members |
+|---|---|
+| LLN0$Events | GGIO1$MX$AnIn1 |
+
+### Report control blocks
+
+| Reference | FC | Kind |
+|---|---|---|
+| LLN0.brcbEV01 | BR | buffered |
+
+### Journals
+
+- LLN0$EventLog
+`, b.String())
+}
+
+func TestExportCSV(t *testing.T) {
+	var b strings.Builder
+	assert.NoError(t, Export(&b, newExportModel(), ExportCSV))
+	assert.Equal(t, `kind,logical_device,reference,fc,type,triggers,members
+RCB,LD0,LLN0.brcbEV01,BR,buffered,,
+DA,LD0,GGIO1.AnIn1.mag,MX,structure,dchg,
+DA,LD0,GGIO1.AnIn1.mag.f,MX,float32,dchg,
+DataSet,LD0,LLN0$Events,,,,GGIO1$MX$AnIn1
+Journal,LD0,LLN0$EventLog,,,,
+`, b.String())
+
+	assert.Error(t, Export(&b, newExportModel(), ExportFormat(9)))
+}
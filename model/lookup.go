@@ -0,0 +1,165 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/slonegd/go61850/osi/mms"
+)
+
+// Reference - проверенная ссылка на элемент модели
+type Reference struct {
+	// LDevice - имя логического устройства (домена MMS)
+	LDevice string
+	// Path - путь от LN через точку, например "GGIO1.AnIn1.mag.f"
+	Path string
+	// FC - функциональное ограничение
+	FC mms.FunctionalConstraint
+}
+
+// String возвращает ссылку в формате IEC 61850: "LD/LN.DO.DA"
+func (r Reference) String() string {
+	return r.LDevice + "/" + r.Path
+}
+
+// ItemID возвращает MMS имя переменной: "LN$FC$DO$DA"
+func (r Reference) ItemID() string {
+	ln, rest, found := strings.Cut(r.Path, ".")
+	if !found {
+		return ln + "$" + string(r.FC)
+	}
+	return ln + "$" + string(r.FC) + "$" + strings.ReplaceAll(rest, ".", "$")
+}
+
+// ReadRequest создаёт MMS запрос чтения элемента
+func (r Reference) ReadRequest() *mms.ReadRequest {
+	return &mms.ReadRequest{InvokeID: 1, DomainID: r.LDevice, ItemID: r.ItemID()}
+}
+
+// ReferenceError - ссылка не найдена в модели или не имеет запрошенного FC
+type ReferenceError struct {
+	// Reference - запрошенная ссылка
+	Reference string
+	// FC - запрошенное функциональное ограничение
+	FC mms.FunctionalConstraint
+	// ValidFC - функциональные ограничения, с которыми ссылка существует
+	// (пусто, если ссылки нет в модели)
+	ValidFC []mms.FunctionalConstraint
+	// Suggestion - ближайшая существующая ссылка (может быть пустой)
+	Suggestion string
+}
+
+// Error реализует интерфейс error
+func (e *ReferenceError) Error() string {
+	if len(e.ValidFC) > 0 {
+		fcs := make([]string, len(e.ValidFC))
+		for i, fc := range e.ValidFC {
+			fcs[i] = string(fc)
+		}
+		return fmt.Sprintf("%s has no FC %s (available: %s)", e.Reference, e.FC, strings.Join(fcs, ", "))
+	}
+	if e.Suggestion != "" {
+		return fmt.Sprintf("%s not found in model, did you mean %s?", e.Reference, e.Suggestion)
+	}
+	return fmt.Sprintf("%s not found in model", e.Reference)
+}
+
+// Lookup находит элемент модели по ссылке "LD/LN.DO.DA" с функциональным ограничением fc.
+// Регистр букв в ссылке не учитывается, если совпадение однозначно; возвращается
+// ссылка с именами из модели. Если элемента нет или у него нет FC fc, возвращается
+// *ReferenceError с допустимыми FC или ближайшей похожей ссылкой.
+func (m *Model) Lookup(reference string, fc mms.FunctionalConstraint) (Reference, error) {
+	refs := m.references()
+
+	// Точное совпадение, затем совпадение без учёта регистра
+	for _, equal := range []func(a, b string) bool{
+		func(a, b string) bool { return a == b },
+		strings.EqualFold,
+	} {
+		var matches []Reference
+		for _, ref := range refs {
+			if equal(ref.String(), reference) {
+				matches = append(matches, ref)
+			}
+		}
+		if len(matches) == 0 || !sameName(matches) {
+			// Неоднозначное совпадение без учёта регистра не принимается
+			continue
+		}
+
+		var validFC []mms.FunctionalConstraint
+		for _, ref := range matches {
+			if ref.FC == fc {
+				return ref, nil
+			}
+			validFC = append(validFC, ref.FC)
+		}
+		return Reference{}, &ReferenceError{Reference: reference, FC: fc, ValidFC: validFC}
+	}
+
+	return Reference{}, &ReferenceError{Reference: reference, FC: fc, Suggestion: suggest(reference, refs)}
+}
+
+// sameName возвращает true, если все ссылки указывают на один элемент модели
+func sameName(refs []Reference) bool {
+	for _, ref := range refs[1:] {
+		if ref.String() != refs[0].String() {
+			return false
+		}
+	}
+	return true
+}
+
+// references возвращает все ссылки модели (LN, DO, DA на любой глубине) с их FC
+func (m *Model) references() []Reference {
+	var refs []Reference
+	for _, ld := range m.LogicalDevices {
+		for _, name := range ld.VariableNames() {
+			parts := strings.Split(name, "$")
+			if len(parts) < 3 {
+				// LN и LN$FC не являются ссылками на данные
+				continue
+			}
+			path := parts[0] + "." + strings.Join(parts[2:], ".")
+			refs = append(refs, Reference{LDevice: ld.Name, Path: path, FC: mms.FunctionalConstraint(parts[1])})
+		}
+	}
+	return refs
+}
+
+// suggest возвращает ближайшую по расстоянию Левенштейна ссылку,
+// если она достаточно похожа на запрошенную
+func suggest(reference string, refs []Reference) string {
+	best := ""
+	bestDistance := max(2, len(reference)/4) + 1
+	lower := strings.ToLower(reference)
+	for _, ref := range refs {
+		d := levenshtein(lower, strings.ToLower(ref.String()))
+		if d < bestDistance {
+			best, bestDistance = ref.String(), d
+		}
+	}
+	return best
+}
+
+// levenshtein вычисляет редакционное расстояние между строками
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
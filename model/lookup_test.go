@@ -0,0 +1,57 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/slonegd/go61850/osi/mms"
+	"github.com/stretchr/testify/assert"
+)
+
+func newLookupModel() *Model {
+	return &Model{LogicalDevices: []*LogicalDevice{
+		NewLogicalDeviceFromNames("simpleIOGenericIO", []string{
+			"GGIO1", "GGIO1$MX", "GGIO1$MX$AnIn1", "GGIO1$MX$AnIn1$mag", "GGIO1$MX$AnIn1$mag$f",
+			"GGIO1$DC", "GGIO1$DC$AnIn1", "GGIO1$DC$AnIn1$d",
+		}, nil),
+	}}
+}
+
+func TestModelLookup(t *testing.T) {
+	m := newLookupModel()
+	want := Reference{LDevice: "simpleIOGenericIO", Path: "GGIO1.AnIn1.mag.f", FC: mms.FCMX}
+
+	ref, err := m.Lookup("simpleIOGenericIO/GGIO1.AnIn1.mag.f", mms.FCMX)
+	assert.NoError(t, err)
+	assert.Equal(t, want, ref)
+	assert.Equal(t, "GGIO1$MX$AnIn1$mag$f", ref.ItemID())
+	assert.Equal(t, &mms.ReadRequest{InvokeID: 1, DomainID: "simpleIOGenericIO", ItemID: "GGIO1$MX$AnIn1$mag$f"}, ref.ReadRequest())
+
+	// Регистр не учитывается, возвращаются имена из модели
+	ref, err = m.Lookup("SIMPLEIOGENERICIO/ggio1.anin1.MAG.F", mms.FCMX)
+	assert.NoError(t, err)
+	assert.Equal(t, want, ref)
+
+	// DO существует с несколькими FC
+	ref, err = m.Lookup("simpleIOGenericIO/GGIO1.AnIn1", mms.FCDC)
+	assert.NoError(t, err)
+	assert.Equal(t, "GGIO1$DC$AnIn1", ref.ItemID())
+}
+
+func TestModelLookupErrors(t *testing.T) {
+	m := newLookupModel()
+
+	_, err := m.Lookup("simpleIOGenericIO/GGIO1.AnIn1.mag.f", mms.FCST)
+	assert.EqualError(t, err, "simpleIOGenericIO/GGIO1.AnIn1.mag.f has no FC ST (available: MX)")
+
+	_, err = m.Lookup("simpleIOGenericIO/GGIO1.AnIn1", mms.FCST)
+	assert.EqualError(t, err, "simpleIOGenericIO/GGIO1.AnIn1 has no FC ST (available: MX, DC)")
+
+	_, err = m.Lookup("simpleIOGenericIO/GGIO1.AnIn1.mg.f", mms.FCMX)
+	assert.EqualError(t, err, "simpleIOGenericIO/GGIO1.AnIn1.mg.f not found in model, did you mean simpleIOGenericIO/GGIO1.AnIn1.mag.f?")
+	var refErr *ReferenceError
+	assert.ErrorAs(t, err, &refErr)
+	assert.Equal(t, "simpleIOGenericIO/GGIO1.AnIn1.mag.f", refErr.Suggestion)
+
+	_, err = m.Lookup("otherLD/XCBR1.Pos.stVal", mms.FCST)
+	assert.EqualError(t, err, "otherLD/XCBR1.Pos.stVal not found in model")
+}
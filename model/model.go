@@ -0,0 +1,462 @@
+package model
+
+import (
+	"fmt"
+
+	"github.com/slonegd/go61850/osi/mms"
+	"github.com/slonegd/go61850/osi/mms/variant"
+)
+
+// Model представляет модель данных сервера IEC 61850.
+// Иерархия: LogicalDevice (домен MMS) -> LogicalNode (именованная переменная MMS)
+// -> DataObject -> DataAttribute.
+type Model struct {
+	// Name - имя IED
+	Name string
+	// LogicalDevices - логические устройства
+	LogicalDevices []*LogicalDevice
+	// VMDVariables - именованные переменные уровня VMD (vmd-specific),
+	// не принадлежащие ни одному домену. FC у них не используется.
+	VMDVariables []*DataAttribute
+}
+
+// LogicalDevice представляет логическое устройство (в MMS - домен)
+type LogicalDevice struct {
+	Name         string
+	LogicalNodes []*LogicalNode
+	// DataSets - наборы данных домена (в MMS - именованные списки переменных)
+	DataSets []*DataSet
+	// Journals - имена журналов домена (например, "LLN0$EventLog")
+	Journals []string
+}
+
+// DataSet представляет набор данных (в MMS - namedVariableList)
+type DataSet struct {
+	// Name - MMS имя набора, например "LLN0$Events"
+	Name string
+	// Members - MMS имена элементов набора, например "GGIO1$ST$Ind1"
+	Members []string
+}
+
+// fcOrder - порядок функциональных ограничений в именах MMS, как в libIEC61850
+var fcOrder = []mms.FunctionalConstraint{
+	mms.FCST, mms.FCMX, mms.FCSP, mms.FCSV, mms.FCCF, mms.FCDC, mms.FCSG,
+	mms.FCSE, mms.FCSR, mms.FCOR, mms.FCBL, mms.FCEX, mms.FCCO,
+}
+
+// LogicalNode представляет логический узел (в MMS - именованная переменная домена)
+type LogicalNode struct {
+	Name        string
+	DataObjects []*DataObject
+}
+
+// DataObject представляет объект данных.
+// Children хранит вложенные объекты (SDO) и атрибуты в порядке модели:
+// этот порядок определяет порядок элементов структур MMS.
+type DataObject struct {
+	Name     string
+	Children []DataNode
+}
+
+// DataNode - элемент объекта данных: *DataObject (SDO) или *DataAttribute
+type DataNode interface {
+	NodeName() string
+}
+
+// DataAttribute представляет атрибут данных.
+// Листовой атрибут хранит значение в Value, составной - вложенные атрибуты в Attributes.
+type DataAttribute struct {
+	Name       string
+	FC         mms.FunctionalConstraint
+	Value      *variant.Variant
+	Attributes []*DataAttribute
+	// Triggers - условия dchg, qchg и dupd, по которым изменение атрибута
+	// попадает в отчёты и журналы (атрибуты dchg/qchg/dupd DA в SCL).
+	// Компоненты составного атрибута наследуют условия атрибута верхнего уровня.
+	Triggers Trigger
+	// Type - MMS тип листового атрибута для GetVariableAccessAttributes;
+	// nil - тип выводится из Value (mms.TypeOf). Задаётся, когда тип модели
+	// отличается от выведенного, например q - bit-string переменной длины (-13).
+	Type *mms.TypeSpecification
+}
+
+// NodeName возвращает имя объекта данных
+func (do *DataObject) NodeName() string { return do.Name }
+
+// NodeName возвращает имя атрибута данных
+func (da *DataAttribute) NodeName() string { return da.Name }
+
+// LogicalDevice возвращает логическое устройство по имени или nil
+func (m *Model) LogicalDevice(name string) *LogicalDevice {
+	for _, ld := range m.LogicalDevices {
+		if ld.Name == name {
+			return ld
+		}
+	}
+	return nil
+}
+
+// VMDVariable возвращает переменную уровня VMD по имени или nil
+func (m *Model) VMDVariable(name string) *DataAttribute {
+	for _, da := range m.VMDVariables {
+		if da.Name == name {
+			return da
+		}
+	}
+	return nil
+}
+
+// VariableNames возвращает MMS имена всех переменных домена в порядке модели:
+// для каждого LN - имя LN, затем для каждого FC "LN$FC" и все вложенные
+// имена "LN$FC$DO$DA..." (ответ GetNameList для класса namedVariable).
+func (ld *LogicalDevice) VariableNames() []string {
+	var names []string
+	for _, ln := range ld.LogicalNodes {
+		names = append(names, ln.Name)
+		for _, fc := range fcOrder {
+			prefix := ln.Name + "$" + string(fc)
+			var fcNames []string
+			for _, do := range ln.DataObjects {
+				fcNames = do.appendNames(fcNames, prefix, fc)
+			}
+			if fcNames != nil {
+				names = append(names, prefix)
+				names = append(names, fcNames...)
+			}
+		}
+	}
+	return names
+}
+
+// DataSetNames возвращает имена наборов данных домена
+func (ld *LogicalDevice) DataSetNames() []string {
+	names := make([]string, len(ld.DataSets))
+	for i, ds := range ld.DataSets {
+		names[i] = ds.Name
+	}
+	return names
+}
+
+// appendNames добавляет имена DO и его элементов с данным FC.
+// DO без атрибутов с этим FC не добавляется.
+func (do *DataObject) appendNames(names []string, prefix string, fc mms.FunctionalConstraint) []string {
+	if do.fcValue(fc) == nil {
+		return names
+	}
+	name := prefix + "$" + do.Name
+	names = append(names, name)
+	for _, child := range do.Children {
+		switch node := child.(type) {
+		case *DataObject:
+			names = node.appendNames(names, name, fc)
+		case *DataAttribute:
+			if node.FC == fc {
+				names = node.appendNames(names, name)
+			}
+		}
+	}
+	return names
+}
+
+// appendNames добавляет имя атрибута и его вложенных атрибутов
+func (da *DataAttribute) appendNames(names []string, prefix string) []string {
+	name := prefix + "$" + da.Name
+	names = append(names, name)
+	for _, child := range da.Attributes {
+		names = child.appendNames(names, name)
+	}
+	return names
+}
+
+// LogicalNode возвращает логический узел по имени или nil
+func (ld *LogicalDevice) LogicalNode(name string) *LogicalNode {
+	for _, ln := range ld.LogicalNodes {
+		if ln.Name == name {
+			return ln
+		}
+	}
+	return nil
+}
+
+// DataObject возвращает объект данных по имени или nil
+func (ln *LogicalNode) DataObject(name string) *DataObject {
+	for _, do := range ln.DataObjects {
+		if do.Name == name {
+			return do
+		}
+	}
+	return nil
+}
+
+// FCValue возвращает значение логического узла в отображении MMS для функционального
+// ограничения fc. path - путь после FC (имена DO, SDO и DA), например для
+// "GGIO1$MX$AnIn1$mag" это ["AnIn1", "mag"].
+//
+// Если path указывает на DO (FCD - functional constrained data), возвращается структура
+// из всех его атрибутов с данным FC в порядке модели, как это делают реальные IED.
+// Пустой path возвращает структуру всех DO, у которых есть атрибуты с данным FC.
+func (ln *LogicalNode) FCValue(fc mms.FunctionalConstraint, path ...string) (*variant.Variant, error) {
+	if len(path) == 0 {
+		var elements []*variant.Variant
+		for _, do := range ln.DataObjects {
+			if value := do.fcValue(fc); value != nil {
+				elements = append(elements, value)
+			}
+		}
+		if elements == nil {
+			return nil, fmt.Errorf("logical node %s has no data with FC %s", ln.Name, fc)
+		}
+		return variant.NewStructureVariant(elements), nil
+	}
+
+	do := ln.DataObject(path[0])
+	if do == nil {
+		return nil, fmt.Errorf("data object %s not found in %s", path[0], ln.Name)
+	}
+	return do.resolve(fc, path[1:])
+}
+
+// FCType возвращает MMS тип значения FCValue(fc, path...) с именами компонентов структур
+// (ответ GetVariableAccessAttributes)
+func (ln *LogicalNode) FCType(fc mms.FunctionalConstraint, path ...string) (*mms.TypeSpecification, error) {
+	if len(path) == 0 {
+		var components []mms.ComponentSpec
+		for _, do := range ln.DataObjects {
+			if spec := do.fcType(fc); spec != nil {
+				components = append(components, mms.ComponentSpec{Name: do.Name, Type: spec})
+			}
+		}
+		if components == nil {
+			return nil, fmt.Errorf("logical node %s has no data with FC %s", ln.Name, fc)
+		}
+		return structureType(components), nil
+	}
+
+	do := ln.DataObject(path[0])
+	if do == nil {
+		return nil, fmt.Errorf("data object %s not found in %s", path[0], ln.Name)
+	}
+	return do.resolveType(fc, path[1:])
+}
+
+// Type возвращает MMS тип логического узла целиком: структуру, компоненты которой
+// названы по FC в порядке имён MMS (ответ GetVariableAccessAttributes для "LN")
+func (ln *LogicalNode) Type() (*mms.TypeSpecification, error) {
+	var components []mms.ComponentSpec
+	for _, fc := range fcOrder {
+		if spec, err := ln.FCType(fc); err == nil {
+			components = append(components, mms.ComponentSpec{Name: string(fc), Type: spec})
+		}
+	}
+	if components == nil {
+		return nil, fmt.Errorf("logical node %s has no data", ln.Name)
+	}
+	return structureType(components), nil
+}
+
+// Attribute возвращает атрибут (или компонент составного атрибута) по пути после FC,
+// например для "GGIO1$MX$AnIn1$mag$f" - ["AnIn1", "mag", "f"], и действующие
+// для него условия Triggers
+func (ln *LogicalNode) Attribute(fc mms.FunctionalConstraint, path ...string) (*DataAttribute, Trigger, error) {
+	if len(path) < 2 {
+		return nil, 0, fmt.Errorf("path %v does not refer to a data attribute", path)
+	}
+	do := ln.DataObject(path[0])
+	if do == nil {
+		return nil, 0, fmt.Errorf("data object %s not found in %s", path[0], ln.Name)
+	}
+	return do.attribute(fc, path[1:])
+}
+
+// attribute находит атрибут по пути внутри DO
+func (do *DataObject) attribute(fc mms.FunctionalConstraint, path []string) (*DataAttribute, Trigger, error) {
+	for _, child := range do.Children {
+		switch node := child.(type) {
+		case *DataObject:
+			if node.Name == path[0] && len(path) > 1 {
+				return node.attribute(fc, path[1:])
+			}
+		case *DataAttribute:
+			if node.Name == path[0] && node.FC == fc {
+				triggers := node.Triggers
+				da := node
+				for _, name := range path[1:] {
+					var next *DataAttribute
+					for _, component := range da.Attributes {
+						if component.Name == name {
+							next = component
+							break
+						}
+					}
+					if next == nil {
+						return nil, 0, fmt.Errorf("%s not found in %s", name, da.Name)
+					}
+					da = next
+					if da.Triggers != 0 {
+						triggers = da.Triggers
+					}
+				}
+				return da, triggers, nil
+			}
+		}
+	}
+	return nil, 0, fmt.Errorf("%s not found in %s with FC %s", path[0], do.Name, fc)
+}
+
+// resolve спускается по пути внутри DO
+func (do *DataObject) resolve(fc mms.FunctionalConstraint, path []string) (*variant.Variant, error) {
+	if len(path) == 0 {
+		value := do.fcValue(fc)
+		if value == nil {
+			return nil, fmt.Errorf("data object %s has no attributes with FC %s", do.Name, fc)
+		}
+		return value, nil
+	}
+
+	for _, child := range do.Children {
+		switch node := child.(type) {
+		case *DataObject:
+			if node.Name == path[0] {
+				return node.resolve(fc, path[1:])
+			}
+		case *DataAttribute:
+			if node.Name == path[0] && node.FC == fc {
+				return node.resolve(path[1:])
+			}
+		}
+	}
+	return nil, fmt.Errorf("%s not found in %s with FC %s", path[0], do.Name, fc)
+}
+
+// resolveType спускается по пути внутри DO, как resolve
+func (do *DataObject) resolveType(fc mms.FunctionalConstraint, path []string) (*mms.TypeSpecification, error) {
+	if len(path) == 0 {
+		spec := do.fcType(fc)
+		if spec == nil {
+			return nil, fmt.Errorf("data object %s has no attributes with FC %s", do.Name, fc)
+		}
+		return spec, nil
+	}
+
+	for _, child := range do.Children {
+		switch node := child.(type) {
+		case *DataObject:
+			if node.Name == path[0] {
+				return node.resolveType(fc, path[1:])
+			}
+		case *DataAttribute:
+			if node.Name == path[0] && node.FC == fc {
+				return node.LookupType(path[1:]...)
+			}
+		}
+	}
+	return nil, fmt.Errorf("%s not found in %s with FC %s", path[0], do.Name, fc)
+}
+
+// fcType возвращает тип структуры fcValue(fc) или nil
+func (do *DataObject) fcType(fc mms.FunctionalConstraint) *mms.TypeSpecification {
+	var components []mms.ComponentSpec
+	for _, child := range do.Children {
+		switch node := child.(type) {
+		case *DataObject:
+			if spec := node.fcType(fc); spec != nil {
+				components = append(components, mms.ComponentSpec{Name: node.Name, Type: spec})
+			}
+		case *DataAttribute:
+			if node.FC == fc {
+				components = append(components, mms.ComponentSpec{Name: node.Name, Type: node.typeSpec()})
+			}
+		}
+	}
+	if components == nil {
+		return nil
+	}
+	return structureType(components)
+}
+
+// fcValue собирает структуру из атрибутов DO (и его SDO) с функциональным ограничением fc.
+// Возвращает nil, если таких атрибутов нет.
+func (do *DataObject) fcValue(fc mms.FunctionalConstraint) *variant.Variant {
+	var elements []*variant.Variant
+	for _, child := range do.Children {
+		switch node := child.(type) {
+		case *DataObject:
+			if value := node.fcValue(fc); value != nil {
+				elements = append(elements, value)
+			}
+		case *DataAttribute:
+			if node.FC == fc {
+				elements = append(elements, node.value())
+			}
+		}
+	}
+	if elements == nil {
+		return nil
+	}
+	return variant.NewStructureVariant(elements)
+}
+
+// Lookup возвращает значение атрибута или его компонента по пути
+// (используется для переменных уровня VMD, у которых нет FC)
+func (da *DataAttribute) Lookup(path ...string) (*variant.Variant, error) {
+	return da.resolve(path)
+}
+
+// LookupType возвращает MMS тип атрибута или его компонента по пути
+// (используется для переменных уровня VMD, у которых нет FC)
+func (da *DataAttribute) LookupType(path ...string) (*mms.TypeSpecification, error) {
+	if len(path) == 0 {
+		return da.typeSpec(), nil
+	}
+	for _, child := range da.Attributes {
+		if child.Name == path[0] {
+			return child.LookupType(path[1:]...)
+		}
+	}
+	return nil, fmt.Errorf("%s not found in %s", path[0], da.Name)
+}
+
+// resolve спускается по пути внутри составного атрибута
+func (da *DataAttribute) resolve(path []string) (*variant.Variant, error) {
+	if len(path) == 0 {
+		return da.value(), nil
+	}
+	for _, child := range da.Attributes {
+		if child.Name == path[0] {
+			return child.resolve(path[1:])
+		}
+	}
+	return nil, fmt.Errorf("%s not found in %s", path[0], da.Name)
+}
+
+// value возвращает значение атрибута; для составного атрибута - структуру значений
+func (da *DataAttribute) value() *variant.Variant {
+	if len(da.Attributes) == 0 {
+		return da.Value
+	}
+	elements := make([]*variant.Variant, len(da.Attributes))
+	for i, child := range da.Attributes {
+		elements[i] = child.value()
+	}
+	return variant.NewStructureVariant(elements)
+}
+
+// typeSpec возвращает тип атрибута; для составного атрибута - структуру типов
+func (da *DataAttribute) typeSpec() *mms.TypeSpecification {
+	if len(da.Attributes) == 0 {
+		if da.Type != nil {
+			return da.Type
+		}
+		return mms.TypeOf(da.Value)
+	}
+	components := make([]mms.ComponentSpec, len(da.Attributes))
+	for i, child := range da.Attributes {
+		components[i] = mms.ComponentSpec{Name: child.Name, Type: child.typeSpec()}
+	}
+	return structureType(components)
+}
+
+// structureType создаёт тип структуры из компонентов
+func structureType(components []mms.ComponentSpec) *mms.TypeSpecification {
+	return &mms.TypeSpecification{Type: mms.TypeSpecStructure, Structure: &mms.StructureTypeSpec{Components: components}}
+}
@@ -0,0 +1,127 @@
+package model
+
+import (
+	"strings"
+
+	"github.com/slonegd/go61850/osi/mms"
+)
+
+// NewLogicalDeviceFromNames строит логическое устройство по MMS именам переменных
+// домена (ответ GetNameList для namedVariable) и именам наборов данных.
+//
+// Имена вида "LN$FC$DO$DA..." раскладываются в дерево LN -> DO -> DA с FC.
+// По именам нельзя отличить SDO от DA, поэтому все элементы глубже DO
+// представляются атрибутами; значения атрибутов не заполняются.
+// Состав наборов данных по именам неизвестен, заполняется только Name.
+func NewLogicalDeviceFromNames(name string, variables, dataSets []string) *LogicalDevice {
+	ld := &LogicalDevice{Name: name}
+	for _, variable := range variables {
+		parts := strings.Split(variable, "$")
+		ln := ld.LogicalNode(parts[0])
+		if ln == nil {
+			ln = &LogicalNode{Name: parts[0]}
+			ld.LogicalNodes = append(ld.LogicalNodes, ln)
+		}
+		if len(parts) < 3 {
+			continue
+		}
+
+		fc := mms.FunctionalConstraint(parts[1])
+		do := ln.DataObject(parts[2])
+		if do == nil {
+			do = &DataObject{Name: parts[2]}
+			ln.DataObjects = append(ln.DataObjects, do)
+		}
+		if len(parts) > 3 {
+			do.addAttribute(fc, parts[3:])
+		}
+	}
+	for _, ds := range dataSets {
+		ld.DataSets = append(ld.DataSets, &DataSet{Name: ds})
+	}
+	return ld
+}
+
+// addAttribute добавляет в DO атрибут по пути path с функциональным ограничением fc
+func (do *DataObject) addAttribute(fc mms.FunctionalConstraint, path []string) {
+	var da *DataAttribute
+	for _, child := range do.Children {
+		if node, ok := child.(*DataAttribute); ok && node.Name == path[0] && node.FC == fc {
+			da = node
+			break
+		}
+	}
+	if da == nil {
+		da = &DataAttribute{Name: path[0], FC: fc}
+		do.Children = append(do.Children, da)
+	}
+
+	for _, name := range path[1:] {
+		var child *DataAttribute
+		for _, a := range da.Attributes {
+			if a.Name == name {
+				child = a
+				break
+			}
+		}
+		if child == nil {
+			child = &DataAttribute{Name: name, FC: fc}
+			da.Attributes = append(da.Attributes, child)
+		}
+		da = child
+	}
+}
+
+// SetTypes задаёт MMS типы (DataAttribute.Type) листовым атрибутам узла,
+// построенного по именам (NewLogicalDeviceFromNames), по типу узла из ответа
+// GetVariableAccessAttributes: структуре функциональных ограничений с объектами
+// данных. Атрибуты, которых нет в узле, пропускаются.
+func (ln *LogicalNode) SetTypes(spec *mms.TypeSpecification) {
+	for _, fc := range structureComponents(spec) {
+		for _, component := range structureComponents(fc.Type) {
+			do := ln.DataObject(component.Name)
+			if do == nil {
+				continue
+			}
+			for _, child := range do.Children {
+				if da, ok := child.(*DataAttribute); ok && da.FC == mms.FunctionalConstraint(fc.Name) {
+					da.setType(componentType(component.Type, da.Name))
+				}
+			}
+		}
+	}
+}
+
+// setType задаёт тип атрибута и его компонентов
+func (da *DataAttribute) setType(spec *mms.TypeSpecification) {
+	if spec == nil {
+		return
+	}
+	if len(da.Attributes) == 0 {
+		if spec.Type != mms.TypeSpecStructure {
+			da.Type = spec
+		}
+		return
+	}
+	for _, child := range da.Attributes {
+		child.setType(componentType(spec, child.Name))
+	}
+}
+
+// structureComponents возвращает компоненты структуры или nil для других типов
+func structureComponents(spec *mms.TypeSpecification) []mms.ComponentSpec {
+	if spec == nil || spec.Structure == nil {
+		return nil
+	}
+	return spec.Structure.Components
+}
+
+// componentType возвращает тип компонента name структуры spec или nil
+func componentType(spec *mms.TypeSpecification, name string) *mms.TypeSpecification {
+	for _, component := range structureComponents(spec) {
+		if component.Name == name {
+			return component.Type
+		}
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/slonegd/go61850/osi/mms"
+	"github.com/slonegd/go61850/osi/mms/variant"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewLogicalDeviceFromNames(t *testing.T) {
+	ld := NewLogicalDeviceFromNames("simpleIOGenericIO", []string{
+		"GGIO1", "GGIO1$MX", "GGIO1$MX$AnIn1", "GGIO1$MX$AnIn1$mag", "GGIO1$MX$AnIn1$mag$f", "GGIO1$MX$AnIn1$q",
+		"GGIO1$DC", "GGIO1$DC$AnIn1", "GGIO1$DC$AnIn1$d",
+		"LLN0",
+	}, []string{"LLN0$Events"})
+
+	assert.Equal(t, &LogicalDevice{
+		Name: "simpleIOGenericIO",
+		LogicalNodes: []*LogicalNode{
+			{Name: "GGIO1", DataObjects: []*DataObject{{
+				Name: "AnIn1",
+				Children: []DataNode{
+					&DataAttribute{Name: "mag", FC: mms.FCMX, Attributes: []*DataAttribute{{Name: "f", FC: mms.FCMX}}},
+					&DataAttribute{Name: "q", FC: mms.FCMX},
+					&DataAttribute{Name: "d", FC: mms.FCDC},
+				},
+			}}},
+			{Name: "LLN0"},
+		},
+		DataSets: []*DataSet{{Name: "LLN0$Events"}},
+	}, ld)
+
+	// Имена, построенные обратно из модели, совпадают с исходными
+	assert.Equal(t, []string{
+		"GGIO1", "GGIO1$MX", "GGIO1$MX$AnIn1", "GGIO1$MX$AnIn1$mag", "GGIO1$MX$AnIn1$mag$f", "GGIO1$MX$AnIn1$q",
+		"GGIO1$DC", "GGIO1$DC$AnIn1", "GGIO1$DC$AnIn1$d",
+		"LLN0",
+	}, ld.VariableNames())
+}
+
+func TestSetTypes(t *testing.T) {
+	typed := &LogicalNode{Name: "GGIO1", DataObjects: []*DataObject{{
+		Name: "AnIn1",
+		Children: []DataNode{
+			&DataAttribute{Name: "mag", FC: mms.FCMX, Attributes: []*DataAttribute{
+				{Name: "f", FC: mms.FCMX, Value: variant.NewFloat32Variant(0)},
+			}},
+			&DataAttribute{Name: "q", FC: mms.FCMX, Value: variant.NewBitStringVariant(make([]byte, 2), 13)},
+			&DataAttribute{Name: "d", FC: mms.FCDC, Value: variant.NewVisibleStringVariant("")},
+		},
+	}}}
+	spec, err := typed.Type()
+	assert.NoError(t, err)
+
+	ld := NewLogicalDeviceFromNames("LD0", []string{
+		"GGIO1", "GGIO1$MX", "GGIO1$MX$AnIn1", "GGIO1$MX$AnIn1$mag", "GGIO1$MX$AnIn1$mag$f", "GGIO1$MX$AnIn1$q",
+		"GGIO1$DC", "GGIO1$DC$AnIn1", "GGIO1$DC$AnIn1$d", "GGIO1$DC$AnIn1$dU",
+	}, nil)
+	ln := ld.LogicalNode("GGIO1")
+	ln.SetTypes(spec)
+	do := ln.DataObject("AnIn1")
+	mag := do.Children[0].(*DataAttribute)
+	assert.Nil(t, mag.Type)
+	assert.Equal(t, "floating-point", mag.Attributes[0].typeName())
+	assert.Equal(t, "bit-string", do.Children[1].(*DataAttribute).typeName())
+	assert.Equal(t, "visible-string", do.Children[2].(*DataAttribute).typeName())
+	// Атрибута нет в типе узла
+	assert.Equal(t, "", do.Children[3].(*DataAttribute).typeName())
+}
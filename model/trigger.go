@@ -0,0 +1,71 @@
+package model
+
+import (
+	"strings"
+
+	"github.com/slonegd/go61850/osi/mms/variant"
+)
+
+// Trigger представляет условия включения данных в отчёт или журнал:
+// TrgOps блока управления и ReasonCode записи согласно IEC 61850-7-2.
+type Trigger uint8
+
+const (
+	// TriggerDataChange - изменение значения (dchg)
+	TriggerDataChange Trigger = 1 << iota
+	// TriggerQualityChange - изменение качества (qchg)
+	TriggerQualityChange
+	// TriggerDataUpdate - обновление значения без изменения (dupd)
+	TriggerDataUpdate
+	// TriggerIntegrity - периодическая передача (period, integrity)
+	TriggerIntegrity
+	// TriggerGI - общий опрос (gi)
+	TriggerGI
+	// TriggerApplication - запуск приложением (только ReasonCode)
+	TriggerApplication
+)
+
+// triggerNames - имена условий в порядке битов
+var triggerNames = []string{"dchg", "qchg", "dupd", "period", "gi", "application"}
+
+// Has проверяет, содержит ли t все условия other
+func (t Trigger) Has(other Trigger) bool {
+	return t&other == other
+}
+
+// String возвращает условия через "|", например "dchg|qchg"
+func (t Trigger) String() string {
+	var names []string
+	for i, name := range triggerNames {
+		if t&(1<<i) != 0 {
+			names = append(names, name)
+		}
+	}
+	return strings.Join(names, "|")
+}
+
+// BitString кодирует t как TrgOps (6 бит) или ReasonCode (7 бит):
+// бит 0 зарезервирован, далее условия в порядке констант Trigger
+func (t Trigger) BitString(bitSize int) *variant.Variant {
+	data := make([]byte, (bitSize+7)/8)
+	for i := range triggerNames {
+		bit := i + 1
+		if t&(1<<i) != 0 && bit < bitSize {
+			data[bit/8] |= 0x80 >> (bit % 8)
+		}
+	}
+	return variant.NewBitStringVariant(data, bitSize)
+}
+
+// TriggerFromBitString декодирует TrgOps или ReasonCode
+func TriggerFromBitString(v *variant.Variant) Trigger {
+	bs := v.BitString()
+	var t Trigger
+	for i := range triggerNames {
+		bit := i + 1
+		if bit < bs.BitSize && bit/8 < len(bs.Data) && bs.Data[bit/8]&(0x80>>(bit%8)) != 0 {
+			t |= 1 << i
+		}
+	}
+	return t
+}
@@ -0,0 +1,111 @@
+package acse
+
+import (
+	"fmt"
+
+	"github.com/slonegd/go61850/internal/ber"
+)
+
+// AbortSource represents ABRT-source of ABRT (ISO 8650-1)
+type AbortSource int32
+
+const (
+	AbortSourceServiceUser     AbortSource = 0
+	AbortSourceServiceProvider AbortSource = 1
+)
+
+// String returns the ASN.1 name of the source
+func (s AbortSource) String() string {
+	switch s {
+	case AbortSourceServiceUser:
+		return "acse-service-user"
+	case AbortSourceServiceProvider:
+		return "acse-service-provider"
+	default:
+		return fmt.Sprintf("unknown(%d)", int32(s))
+	}
+}
+
+// AbortDiagnostic represents ABRT-diagnostic of ABRT (ISO 8650-1).
+// Zero value means the optional diagnostic is absent.
+type AbortDiagnostic int32
+
+const (
+	AbortDiagnosticNoReasonGiven                  AbortDiagnostic = 1
+	AbortDiagnosticProtocolError                  AbortDiagnostic = 2
+	AbortDiagnosticAuthMechanismNameNotRecognized AbortDiagnostic = 3
+	AbortDiagnosticAuthMechanismNameRequired      AbortDiagnostic = 4
+	AbortDiagnosticAuthenticationFailure          AbortDiagnostic = 5
+	AbortDiagnosticAuthenticationRequired         AbortDiagnostic = 6
+)
+
+// String returns the ASN.1 name of the diagnostic
+func (d AbortDiagnostic) String() string {
+	switch d {
+	case 0:
+		return "none"
+	case AbortDiagnosticNoReasonGiven:
+		return "no-reason-given"
+	case AbortDiagnosticProtocolError:
+		return "protocol-error"
+	case AbortDiagnosticAuthMechanismNameNotRecognized:
+		return "authentication-mechanism-name-not-recognized"
+	case AbortDiagnosticAuthMechanismNameRequired:
+		return "authentication-mechanism-name-required"
+	case AbortDiagnosticAuthenticationFailure:
+		return "authentication-failure"
+	case AbortDiagnosticAuthenticationRequired:
+		return "authentication-required"
+	default:
+		return fmt.Sprintf("unknown(%d)", int32(d))
+	}
+}
+
+// AbortError is returned by ParseMessage for a received ABRT
+type AbortError struct {
+	Source     AbortSource
+	Diagnostic AbortDiagnostic
+}
+
+func (e *AbortError) Error() string {
+	if e.Diagnostic == 0 {
+		return fmt.Sprintf("association aborted by %s", e.Source)
+	}
+	return fmt.Sprintf("association aborted by %s: %s", e.Source, e.Diagnostic)
+}
+
+// IsProvider reports whether the abort was initiated by the ACSE service provider
+// (protocol stack) rather than by the peer application
+func (e *AbortError) IsProvider() bool {
+	return e.Source == AbortSourceServiceProvider
+}
+
+// parseAbort parses the content of ABRT: abort-source [0] and abort-diagnostic [3]
+func parseAbort(buffer []byte, bufPos, maxBufPos int) (*AbortError, error) {
+	abort := &AbortError{Source: -1}
+	for bufPos < maxBufPos {
+		tag := buffer[bufPos]
+		bufPos++
+
+		newPos, length, err := ber.DecodeLength(buffer, bufPos, maxBufPos)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ABRT PDU: %w", err)
+		}
+		bufPos = newPos
+
+		if (tag == 0x80 || tag == 0x83) && (length < 1 || length > 4) {
+			return nil, fmt.Errorf("invalid ABRT PDU: bad length %d of tag 0x%02x", length, tag)
+		}
+		switch tag {
+		case 0x80: // abort-source
+			abort.Source = AbortSource(ber.DecodeInt32(buffer, length, bufPos))
+		case 0x83: // abort-diagnostic
+			abort.Diagnostic = AbortDiagnostic(ber.DecodeInt32(buffer, length, bufPos))
+		}
+		bufPos += length
+	}
+	if abort.Source < 0 {
+		return nil, fmt.Errorf("invalid ABRT PDU: missing abort-source")
+	}
+	return abort, nil
+}
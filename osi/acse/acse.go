@@ -5,7 +5,7 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/slonegd/go61850/ber"
+	"github.com/slonegd/go61850/internal/ber"
 )
 
 // ConnectionState represents the state of an ACSE connection
@@ -67,6 +67,10 @@ type Connection struct {
 	UserDataBuffer     []byte
 	UserDataBufferSize int
 	ApplicationRef     ApplicationReference
+	// Association is the responder identity of the last received AARE
+	Association AssociationInfo
+	// ReleaseReason is the reason of the last received RLRQ/RLRE (-1 if absent)
+	ReleaseReason int32
 	// Note: Authenticator callback is not implemented in this version
 	// as it's not needed for basic functionality
 }
@@ -76,21 +80,25 @@ func NewConnection() *Connection {
 	return &Connection{
 		State:         StateIdle,
 		NextReference: 0,
+		ReleaseReason: -1,
 	}
 }
 
 // Constants for ACSE OIDs and values
 var (
 	// 1.0.9506.2.3 (mms-abstract-syntax-version3)
-	appContextNameMms = []byte{0x28, 0xca, 0x22, 0x02, 0x03}
+	appContextNameMms = ber.OIDMMSApplicationContext.Bytes()
 	// 2.2.3.1 (id-password)
-	authMechPasswordOID = []byte{0x52, 0x03, 0x01}
+	authMechPasswordOID = ber.OIDPasswordAuthentication.Bytes()
 	// Authentication requirements
 	requirementsAuthentication = []byte{0x80}
 )
 
 // BuildAARQ creates an AARQ (Association Request) PDU
 // This is a simplified version that matches the expected packet structure
+//
+// Deprecated: use mms.NewAssociateRequest, which builds the PDUs of all layers;
+// CreateAssociateRequestMessage remains for custom ISO connection parameters.
 func BuildAARQ(userData []byte) []byte {
 	conn := NewConnection()
 	isoParams := &IsoConnectionParameters{
@@ -301,7 +309,7 @@ func ParseMessage(conn *Connection, message []byte) (Indication, error) {
 	messageType := message[bufPos]
 	bufPos++
 
-	newPos, _, err := ber.DecodeLength(message, bufPos, len(message))
+	newPos, length, err := ber.DecodeLength(message, bufPos, len(message))
 	if err != nil {
 		return IndicationError, fmt.Errorf("invalid ACSE message: %w", err)
 	}
@@ -313,11 +321,28 @@ func ParseMessage(conn *Connection, message []byte) (Indication, error) {
 	case 0x61: // AARE
 		return parseAarePdu(conn, message, bufPos, len(message))
 	case 0x62: // A_RELEASE.request RLRQ-apdu
+		reason, err := parseReleaseReason(message, bufPos, bufPos+length)
+		if err != nil {
+			return IndicationError, err
+		}
+		conn.ReleaseReason = reason
 		return IndicationReleaseRequest, nil
 	case 0x63: // A_RELEASE.response RLRE-apdu
+		reason, err := parseReleaseReason(message, bufPos, bufPos+length)
+		if err != nil {
+			return IndicationError, err
+		}
+		conn.ReleaseReason = reason
+		if reason > 0 {
+			return IndicationReleaseResponse, &ReleaseError{Reason: ReleaseResponseReason(reason)}
+		}
 		return IndicationReleaseResponse, nil
 	case 0x64: // A_ABORT
-		return IndicationAbort, nil
+		abort, err := parseAbort(message, bufPos, bufPos+length)
+		if err != nil {
+			return IndicationError, err
+		}
+		return IndicationAbort, abort
 	case 0x00: // indefinite length end tag -> ignore
 		return IndicationError, errors.New("indefinite length end tag")
 	default:
@@ -366,7 +391,9 @@ func parseAarqPdu(conn *Connection, buffer []byte, bufPos, maxBufPos int) (Indic
 				if bufPos+1 < maxBufPos {
 					innerLength := int(buffer[bufPos+1])
 					if innerLength == length-2 {
-						ber.DecodeOID(buffer, bufPos+2, innerLength, &conn.ApplicationRef.APTitle)
+						if err := ber.DecodeOID(buffer, bufPos+2, innerLength, &conn.ApplicationRef.APTitle); err != nil {
+							return IndicationAssociateFailed, fmt.Errorf("invalid calling AP title: %w", err)
+						}
 					}
 				}
 			}
@@ -392,8 +419,9 @@ func parseAarqPdu(conn *Connection, buffer []byte, bufPos, maxBufPos int) (Indic
 			bufPos += length
 
 		case 0xac: // authentication value
+			end := bufPos + length
 			bufPos++ // skip tag
-			newPos, length, err := ber.DecodeLength(buffer, bufPos, maxBufPos)
+			newPos, length, err := ber.DecodeLength(buffer, bufPos, end)
 			if err != nil {
 				return IndicationAssociateFailed, fmt.Errorf("invalid PDU: %w", err)
 			}
@@ -406,8 +434,9 @@ func parseAarqPdu(conn *Connection, buffer []byte, bufPos, maxBufPos int) (Indic
 			if bufPos < maxBufPos && buffer[bufPos] != 0x28 {
 				bufPos += length
 			} else {
+				end := bufPos + length
 				bufPos++ // skip 0x28 tag
-				newPos, length, err := ber.DecodeLength(buffer, bufPos, maxBufPos)
+				newPos, length, err := ber.DecodeLength(buffer, bufPos, end)
 				if err != nil {
 					return IndicationAssociateFailed, fmt.Errorf("invalid PDU: %w", err)
 				}
@@ -442,6 +471,7 @@ func parseAarqPdu(conn *Connection, buffer []byte, bufPos, maxBufPos int) (Indic
 // Based on parseAarePdu from acse.c
 func parseAarePdu(conn *Connection, buffer []byte, bufPos, maxBufPos int) (Indication, error) {
 	userInfoValid := false
+	conn.Association = AssociationInfo{}
 	result := uint32(99)
 
 	for bufPos < maxBufPos {
@@ -467,8 +497,10 @@ func parseAarePdu(conn *Connection, buffer []byte, bufPos, maxBufPos int) (Indic
 			bufPos += length
 
 		case 0xa2: // result
+			// Вложенный INTEGER ограничен элементом result
+			end := bufPos + length
 			bufPos++ // skip tag
-			newPos, length, err := ber.DecodeLength(buffer, bufPos, maxBufPos)
+			newPos, length, err := ber.DecodeLength(buffer, bufPos, end)
 			if err != nil {
 				return IndicationError, fmt.Errorf("invalid PDU: %w", err)
 			}
@@ -480,12 +512,19 @@ func parseAarePdu(conn *Connection, buffer []byte, bufPos, maxBufPos int) (Indic
 		case 0xa3: // result source diagnostic
 			bufPos += length
 
+		case 0xa4, 0xa5: // responding AP title, responding AE qualifier
+			if err := conn.Association.parseResponder(tag, buffer[bufPos:bufPos+length]); err != nil {
+				return IndicationError, fmt.Errorf("invalid PDU: %w", err)
+			}
+			bufPos += length
+
 		case 0xbe: // user information
 			if bufPos < maxBufPos && buffer[bufPos] != 0x28 {
 				bufPos += length
 			} else {
+				end := bufPos + length
 				bufPos++ // skip 0x28 tag
-				newPos, length, err := ber.DecodeLength(buffer, bufPos, maxBufPos)
+				newPos, length, err := ber.DecodeLength(buffer, bufPos, end)
 				if err != nil {
 					return IndicationError, fmt.Errorf("invalid PDU: %w", err)
 				}
@@ -606,14 +645,11 @@ func CreateAssociateResponseMessage(conn *Connection, acseResult uint8, payload
 
 	contentLength := fixedContentLength + variableContentLength
 
-	buffer := make([]byte, 0, contentLength+10)
+	buffer := make([]byte, 1+ber.DetermineLengthSize(uint32(contentLength))+contentLength)
 	bufPos := 0
 
 	// Encode AARE tag and length
 	bufPos = ber.EncodeTL(0x61, uint32(contentLength), buffer, bufPos)
-	buffer = buffer[:bufPos+contentLength]
-	bufPos = 0
-	bufPos = ber.EncodeTL(0x61, uint32(contentLength), buffer, bufPos)
 
 	// Application context name
 	bufPos = ber.EncodeTL(0xa1, 7, buffer, bufPos)
@@ -659,40 +695,36 @@ func CreateAssociateFailedMessage(conn *Connection, payload []byte) []byte {
 	return CreateAssociateResponseMessage(conn, ResultRejectPermanent, payload)
 }
 
-// CreateAbortMessage creates an A_ABORT PDU
-func CreateAbortMessage(conn *Connection, isProvider bool) []byte {
-	buffer := make([]byte, 5)
-	buffer[0] = 0x64 // [APPLICATION 4]
-	buffer[1] = 3
-	buffer[2] = 0x80
-	buffer[3] = 1
-
-	if isProvider {
-		buffer[4] = 1
-	} else {
-		buffer[4] = 0
+// CreateAbortMessage creates an A_ABORT PDU (ABRT) with the source and
+// the optional diagnostic (0 - omitted)
+func CreateAbortMessage(conn *Connection, source AbortSource, diagnostic AbortDiagnostic) []byte {
+	sourceSize := determineIntegerEncodedSize(int32(source))
+	contentLength := 2 + sourceSize
+	diagnosticSize := 0
+	if diagnostic != 0 {
+		diagnosticSize = determineIntegerEncodedSize(int32(diagnostic))
+		contentLength += 2 + diagnosticSize
 	}
 
-	return buffer
+	buffer := make([]byte, 2+contentLength)
+	bufPos := ber.EncodeTL(ber.Tag(ABRT), uint32(contentLength), buffer, 0)
+	bufPos = ber.EncodeTL(0x80, uint32(sourceSize), buffer, bufPos)
+	bufPos = encodeInteger(int32(source), buffer, bufPos)
+	if diagnostic != 0 {
+		bufPos = ber.EncodeTL(0x83, uint32(diagnosticSize), buffer, bufPos)
+		bufPos = encodeInteger(int32(diagnostic), buffer, bufPos)
+	}
+	return buffer[:bufPos]
 }
 
-// CreateReleaseRequestMessage creates an A_RELEASE.request PDU
-func CreateReleaseRequestMessage(conn *Connection) []byte {
-	buffer := make([]byte, 5)
-	buffer[0] = 0x62
-	buffer[1] = 3
-	buffer[2] = 0x80
-	buffer[3] = 1
-	buffer[4] = 0
-	return buffer
+// CreateReleaseRequestMessage creates an A_RELEASE.request PDU (RLRQ) with the reason
+func CreateReleaseRequestMessage(conn *Connection, reason ReleaseRequestReason) []byte {
+	return createReleaseMessage(byte(RLRQ), int32(reason))
 }
 
-// CreateReleaseResponseMessage creates an A_RELEASE.response PDU
-func CreateReleaseResponseMessage(conn *Connection) []byte {
-	buffer := make([]byte, 2)
-	buffer[0] = 0x63
-	buffer[1] = 0
-	return buffer
+// CreateReleaseResponseMessage creates an A_RELEASE.response PDU (RLRE) with the reason
+func CreateReleaseResponseMessage(conn *Connection, reason ReleaseResponseReason) []byte {
+	return createReleaseMessage(byte(RLRE), int32(reason))
 }
 
 // determineIntegerEncodedSize determines the encoded size of an integer
@@ -744,6 +776,12 @@ type ACSEPDU struct {
 	IndirectReference      uint32 // Indirect reference from user information
 	Encoding               uint8  // Encoding type (0=single-ASN1-type)
 	Data                   []byte // MMS data (user data)
+	ReleaseReason          int32  // Reason of RLRQ/RLRE (-1 if absent)
+
+	// Abort holds the source and the diagnostic of ABRT
+	Abort *AbortError
+	// Association holds the responder identity of AARE (nil if absent)
+	Association *AssociationInfo
 }
 
 // ParseACSEPDU parses an ACSE PDU from byte buffer and returns a structure for logging
@@ -758,13 +796,14 @@ func ParseACSEPDU(data []byte) (*ACSEPDU, error) {
 	messageType := data[bufPos]
 	bufPos++
 
-	newPos, _, err := ber.DecodeLength(data, bufPos, len(data))
+	newPos, length, err := ber.DecodeLength(data, bufPos, len(data))
 	if err != nil {
 		return nil, fmt.Errorf("invalid ACSE message: %w", err)
 	}
 	bufPos = newPos
 
 	maxBufPos := len(data)
+	pdu.ReleaseReason = -1
 
 	pduType := ACSEPDUType(messageType)
 	switch pduType {
@@ -774,14 +813,20 @@ func ParseACSEPDU(data []byte) (*ACSEPDU, error) {
 	case AARE:
 		pdu.Type = AARE
 		return parseAarePduForLogging(pdu, data, bufPos, maxBufPos)
-	case RLRQ:
-		pdu.Type = RLRQ
-		return pdu, nil
-	case RLRE:
-		pdu.Type = RLRE
+	case RLRQ, RLRE:
+		pdu.Type = pduType
+		pdu.ReleaseReason, err = parseReleaseReason(data, bufPos, bufPos+length)
+		if err != nil {
+			return nil, err
+		}
 		return pdu, nil
 	case ABRT:
 		pdu.Type = ABRT
+		abort, err := parseAbort(data, bufPos, bufPos+length)
+		if err != nil {
+			return nil, err
+		}
+		pdu.Abort = abort
 		return pdu, nil
 	default:
 		return nil, fmt.Errorf("unknown ACSE message type: 0x%02x", messageType)
@@ -884,6 +929,15 @@ func parseAarePduForLogging(pdu *ACSEPDU, buffer []byte, bufPos, maxBufPos int)
 				bufPos += length
 			}
 
+		case 0xa4, 0xa5: // responding AP title, responding AE qualifier
+			if pdu.Association == nil {
+				pdu.Association = &AssociationInfo{}
+			}
+			if err := pdu.Association.parseResponder(tag, buffer[bufPos:bufPos+length]); err != nil {
+				return nil, fmt.Errorf("invalid PDU: %w", err)
+			}
+			bufPos += length
+
 		case 0xbe: // user information
 			if bufPos < maxBufPos && buffer[bufPos] != 0x28 {
 				bufPos += length
@@ -1120,6 +1174,22 @@ func (p *ACSEPDU) String() string {
 			}
 			fmt.Fprintf(&builder, ", ResultSourceDiagnostic: %s", diagStr)
 		}
+		if p.Association != nil {
+			fmt.Fprintf(&builder, ", Responder: %s", p.Association)
+		}
+	}
+
+	if p.ReleaseReason >= 0 {
+		switch p.Type {
+		case RLRQ:
+			fmt.Fprintf(&builder, ", Reason: %s", ReleaseRequestReason(p.ReleaseReason))
+		case RLRE:
+			fmt.Fprintf(&builder, ", Reason: %s", ReleaseResponseReason(p.ReleaseReason))
+		}
+	}
+
+	if p.Abort != nil {
+		fmt.Fprintf(&builder, ", Source: %s, Diagnostic: %s", p.Abort.Source, p.Abort.Diagnostic)
 	}
 
 	if p.IndirectReference != 0 {
@@ -1142,16 +1212,16 @@ func formatOID(oid []byte) string {
 	if len(oid) == 0 {
 		return "[]"
 	}
-	if len(oid) == 5 && oid[0] == 0x28 && oid[1] == 0xca && oid[2] == 0x22 && oid[3] == 0x02 && oid[4] == 0x03 {
-		return "1.0.9506.2.3 (MMS)"
-	}
-	if len(oid) == 4 && oid[0] == 0x52 && oid[1] == 0x01 && oid[2] == 0x00 && oid[3] == 0x01 {
-		return "2.2.1.0.1 (id-as-acse)"
+	var decoded ber.ItuObjectIdentifier
+	if err := ber.DecodeOID(oid, 0, len(oid), &decoded); err != nil {
+		var parts []string
+		for _, b := range oid {
+			parts = append(parts, fmt.Sprintf("%02x", b))
+		}
+		return fmt.Sprintf("[%s]", strings.Join(parts, " "))
 	}
-	// Generic OID formatting
-	var parts []string
-	for _, b := range oid {
-		parts = append(parts, fmt.Sprintf("%02x", b))
+	if name, ok := ber.LookupOID(decoded); ok {
+		return fmt.Sprintf("%s (%s)", decoded, name)
 	}
-	return fmt.Sprintf("[%s]", strings.Join(parts, " "))
+	return decoded.String()
 }
@@ -0,0 +1,191 @@
+package acse
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/slonegd/go61850/internal/ber"
+)
+
+func TestReleaseMessages(t *testing.T) {
+	conn := NewConnection()
+
+	rlrq := CreateReleaseRequestMessage(conn, ReleaseRequestUrgent)
+	if !bytes.Equal(rlrq, []byte{0x62, 0x03, 0x80, 0x01, 0x01}) {
+		t.Fatalf("RLRQ = %x", rlrq)
+	}
+	indication, err := ParseMessage(conn, rlrq)
+	if err != nil || indication != IndicationReleaseRequest || conn.ReleaseReason != int32(ReleaseRequestUrgent) {
+		t.Fatalf("ParseMessage(RLRQ) = %v, %v, reason %d", indication, err, conn.ReleaseReason)
+	}
+
+	rlre := CreateReleaseResponseMessage(conn, ReleaseResponseNormal)
+	if indication, err := ParseMessage(conn, rlre); err != nil || indication != IndicationReleaseResponse {
+		t.Fatalf("ParseMessage(RLRE) = %v, %v", indication, err)
+	}
+
+	// Отказ в освобождении возвращается ошибкой с причиной
+	rlre = CreateReleaseResponseMessage(conn, ReleaseResponseNotFinished)
+	_, err = ParseMessage(conn, rlre)
+	var releaseErr *ReleaseError
+	if !errors.As(err, &releaseErr) || releaseErr.Reason != ReleaseResponseNotFinished {
+		t.Fatalf("ParseMessage(RLRE not-finished) error = %v", err)
+	}
+	if err.Error() != "association release refused: not-finished" {
+		t.Errorf("error = %q", err)
+	}
+
+	// Причина необязательна
+	if _, err := ParseMessage(conn, []byte{0x63, 0x00}); err != nil || conn.ReleaseReason != -1 {
+		t.Fatalf("ParseMessage(RLRE without reason) = %v, reason %d", err, conn.ReleaseReason)
+	}
+
+	pdu, err := ParseACSEPDU(CreateReleaseRequestMessage(conn, ReleaseRequestUserDefined))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := pdu.String(); got != "ACSEPDU{Type: RLRQ (0x62), Reason: user-defined, Encoding: 0 (single-ASN1-type), DataLength: 0}" {
+		t.Errorf("String() = %q", got)
+	}
+}
+
+func TestAbortMessages(t *testing.T) {
+	conn := NewConnection()
+
+	abrt := CreateAbortMessage(conn, AbortSourceServiceProvider, AbortDiagnosticProtocolError)
+	if !bytes.Equal(abrt, []byte{0x64, 0x06, 0x80, 0x01, 0x01, 0x83, 0x01, 0x02}) {
+		t.Fatalf("ABRT = %x", abrt)
+	}
+	indication, err := ParseMessage(conn, abrt)
+	var abortErr *AbortError
+	if indication != IndicationAbort || !errors.As(err, &abortErr) || !abortErr.IsProvider() {
+		t.Fatalf("ParseMessage(ABRT) = %v, %v", indication, err)
+	}
+	if err.Error() != "association aborted by acse-service-provider: protocol-error" {
+		t.Errorf("error = %q", err)
+	}
+
+	// Диагностика необязательна
+	_, err = ParseMessage(conn, CreateAbortMessage(conn, AbortSourceServiceUser, 0))
+	if err == nil || err.Error() != "association aborted by acse-service-user" {
+		t.Errorf("error = %v", err)
+	}
+	if _, err := ParseMessage(conn, []byte{0x64, 0x00}); err == nil || errors.As(err, &abortErr) {
+		t.Errorf("ABRT without abort-source: error = %v", err)
+	}
+
+	pdu, err := ParseACSEPDU(abrt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := pdu.String(); got != "ACSEPDU{Type: ABRT (0x64), Source: acse-service-provider, Diagnostic: protocol-error, Encoding: 0 (single-ASN1-type), DataLength: 0}" {
+		t.Errorf("String() = %q", got)
+	}
+}
+
+func TestCallingAPTitle(t *testing.T) {
+	apTitle := ber.NewOID(1, 3, 9999, 1, 2, 3, 4, 5, 6, 7, 8, 0x10000000)
+	params := &IsoConnectionParameters{
+		LocalAPTitle:    apTitle.Bytes(),
+		LocalAPTitleLen: len(apTitle.Bytes()),
+	}
+	aarq := CreateAssociateRequestMessage(NewConnection(), params, []byte{0xa8, 0x00}, nil)
+
+	conn := NewConnection()
+	if _, err := ParseMessage(conn, aarq); err != nil {
+		t.Fatal(err)
+	}
+	if !conn.ApplicationRef.APTitle.Equal(apTitle) {
+		t.Errorf("APTitle = %v, want %v", conn.ApplicationRef.APTitle, apTitle)
+	}
+	if got := conn.ApplicationRef.APTitle.String(); got != "1.3.9999.1.2.3.4.5.6.7.8.268435456" {
+		t.Errorf("APTitle.String() = %q", got)
+	}
+
+	// Усечённый идентификатор отклоняется, а не обрезается
+	params.LocalAPTitle = []byte{0x2b, 0x86}
+	params.LocalAPTitleLen = 2
+	aarq = CreateAssociateRequestMessage(NewConnection(), params, []byte{0xa8, 0x00}, nil)
+	if _, err := ParseMessage(NewConnection(), aarq); !errors.Is(err, ber.ErrInvalidOID) {
+		t.Errorf("ParseMessage() error = %v, want ErrInvalidOID", err)
+	}
+}
+
+func TestRespondingAPTitle(t *testing.T) {
+	aare := CreateAssociateResponseMessage(NewConnection(), 0, []byte{0xa9, 0x00})
+	responder := []byte{
+		0xa4, 0x05, 0x06, 0x03, 0x29, 0x01, 0x07, // responding AP title 1.1.1.7
+		0xa5, 0x03, 0x02, 0x01, 0x0c, // responding AE qualifier 12
+	}
+	if aare[1] >= 0x80 || int(aare[1])+len(responder) >= 0x80 {
+		t.Fatalf("unexpected AARE length form: % x", aare[:2])
+	}
+	message := append([]byte{0x61, aare[1] + byte(len(responder))}, responder...)
+	message = append(message, aare[2:]...)
+
+	conn := NewConnection()
+	indication, err := ParseMessage(conn, message)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if indication != IndicationAssociate {
+		t.Errorf("indication = %v, want IndicationAssociate", indication)
+	}
+	want := AssociationInfo{
+		RespondingAPTitle:     ber.NewOID(1, 1, 1, 7),
+		RespondingAEQualifier: 12,
+		HasAEQualifier:        true,
+	}
+	if !conn.Association.RespondingAPTitle.Equal(want.RespondingAPTitle) ||
+		conn.Association.RespondingAEQualifier != 12 || !conn.Association.HasAEQualifier {
+		t.Errorf("Association = %v, want %v", conn.Association, want)
+	}
+
+	pdu, err := ParseACSEPDU(message)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pdu.Association == nil || pdu.Association.String() != "AP-title 1.1.1.7, AE-qualifier 12" {
+		t.Errorf("ParseACSEPDU().Association = %v", pdu.Association)
+	}
+
+	// Без полей отвечающей стороны идентификация пустая
+	conn = NewConnection()
+	if _, err := ParseMessage(conn, aare); err != nil {
+		t.Fatal(err)
+	}
+	if got := conn.Association.String(); got != "AP-title none" {
+		t.Errorf("Association.String() = %q", got)
+	}
+
+	// Формы, не представимые в AssociationInfo, пропускаются: ap-title-form1
+	// (имя каталога), ae-qualifier-form1 и AE-qualifier длиннее 4 байт
+	for name, responder := range map[string][]byte{
+		"ap-title-form1":       {0xa4, 0x04, 0x30, 0x02, 0x31, 0x00, 0xa5, 0x03, 0x02, 0x01, 0x0c},
+		"ae-qualifier-form1":   {0xa4, 0x05, 0x06, 0x03, 0x29, 0x01, 0x07, 0xa5, 0x04, 0x31, 0x02, 0x30, 0x00},
+		"long AE-qualifier":    {0xa4, 0x05, 0x06, 0x03, 0x29, 0x01, 0x07, 0xa5, 0x07, 0x02, 0x05, 0x01, 0x02, 0x03, 0x04, 0x05},
+		"AE-qualifier not int": {0xa5, 0x03, 0x04, 0x01, 0x0c},
+	} {
+		message := append([]byte{0x61, aare[1] + byte(len(responder))}, responder...)
+		message = append(message, aare[2:]...)
+		conn := NewConnection()
+		if _, err := ParseMessage(conn, message); err != nil {
+			t.Errorf("%s: ParseMessage() error = %v", name, err)
+			continue
+		}
+		if _, err := ParseACSEPDU(message); err != nil {
+			t.Errorf("%s: ParseACSEPDU() error = %v", name, err)
+		}
+		switch name {
+		case "ap-title-form1":
+			if len(conn.Association.RespondingAPTitle.Arc) != 0 || conn.Association.RespondingAEQualifier != 12 {
+				t.Errorf("%s: Association = %v", name, conn.Association)
+			}
+		default:
+			if conn.Association.HasAEQualifier {
+				t.Errorf("%s: Association = %v, want no AE-qualifier", name, conn.Association)
+			}
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package acse
+
+import (
+	"fmt"
+
+	"github.com/slonegd/go61850/internal/ber"
+)
+
+// AssociationInfo holds the identity of the responder taken from an AARE,
+// so that the client can verify it is talking to the expected IED
+type AssociationInfo struct {
+	// RespondingAPTitle is the responding AP-title (ap-title-form2); empty if absent
+	RespondingAPTitle ber.ItuObjectIdentifier
+	// RespondingAEQualifier is the responding AE-qualifier (ae-qualifier-form2)
+	RespondingAEQualifier int32
+	// HasAEQualifier reports whether the AARE contains the AE-qualifier
+	HasAEQualifier bool
+}
+
+// String returns the responder identity, e.g. "AP-title 1.1.1.999, AE-qualifier 12"
+func (i AssociationInfo) String() string {
+	apTitle := "none"
+	if len(i.RespondingAPTitle.Arc) > 0 {
+		apTitle = i.RespondingAPTitle.String()
+	}
+	if !i.HasAEQualifier {
+		return fmt.Sprintf("AP-title %s", apTitle)
+	}
+	return fmt.Sprintf("AP-title %s, AE-qualifier %d", apTitle, i.RespondingAEQualifier)
+}
+
+// parseResponder decodes responding-AP-title [4] or responding-AE-qualifier [5]
+// of an AARE element with the given tag and content. Forms that cannot be
+// represented in AssociationInfo (ap-title-form1 directory name, ae-qualifier-form1,
+// AE-qualifier longer than 4 bytes) are skipped: they do not make the AARE invalid.
+func (i *AssociationInfo) parseResponder(tag byte, content []byte) error {
+	switch tag {
+	case 0xa4: // responding AP title
+		if len(content) > 0 && content[0] != byte(ber.ObjectIdentifier) {
+			return nil // ap-title-form1
+		}
+		value, err := expectElement(content, byte(ber.ObjectIdentifier), "responding AP title")
+		if err != nil {
+			return err
+		}
+		if err := ber.DecodeOID(value, 0, len(value), &i.RespondingAPTitle); err != nil {
+			return fmt.Errorf("invalid responding AP title: %w", err)
+		}
+		return nil
+
+	case 0xa5: // responding AE qualifier
+		if len(content) > 0 && content[0] != byte(ber.Integer) {
+			return nil // ae-qualifier-form1
+		}
+		value, err := expectElement(content, byte(ber.Integer), "responding AE qualifier")
+		if err != nil {
+			return err
+		}
+		if len(value) < 1 || len(value) > 4 {
+			return nil // does not fit RespondingAEQualifier
+		}
+		i.RespondingAEQualifier = ber.DecodeInt32(value, len(value), 0)
+		i.HasAEQualifier = true
+		return nil
+	}
+	return nil
+}
+
+// expectElement decodes a single element with the expected tag and returns its content
+func expectElement(buffer []byte, tag byte, what string) ([]byte, error) {
+	if len(buffer) < 2 || buffer[0] != tag {
+		return nil, fmt.Errorf("invalid %s: expected tag 0x%02x", what, tag)
+	}
+	bufPos, length, err := ber.DecodeLength(buffer, 1, len(buffer))
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", what, err)
+	}
+	if bufPos+length > len(buffer) {
+		return nil, fmt.Errorf("invalid %s: buffer overflow", what)
+	}
+	return buffer[bufPos : bufPos+length], nil
+}
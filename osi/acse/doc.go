@@ -0,0 +1,15 @@
+// Package acse реализует ACSE (ISO 8650-1) - управление ассоциацией приложений:
+// AARQ и AARE при установлении, RLRQ и RLRE при освобождении, ABRT при разрыве.
+//
+// ACSE участвует только в установлении и завершении ассоциации: AARQ
+// (CreateAssociateRequestMessage) переносит initiate-RequestPDU MMS,
+// AARE (CreateAssociateResponseMessage) - initiate-ResponsePDU. Сами APDU
+// передаются в контексте ACSE (id 1) пакета osi/presentation; в фазе данных
+// MMS PDU идут напрямую в контексте MMS, минуя ACSE. ParseACSEPDU разбирает
+// APDU любого типа, ParseMessage дополнительно ведёт состояние Connection.
+// Запрос ассоциации на всех уровнях стека собирает mms.NewAssociateRequest.
+// Расположение уровней стека описано в документации osi/mms.
+//
+// Пакет не входит в стабильный публичный API модуля (см. документацию go61850):
+// его API может меняться без сохранения совместимости и не проверяется make apidiff.
+package acse
@@ -0,0 +1,38 @@
+package acse_test
+
+import (
+	"fmt"
+
+	"github.com/slonegd/go61850/osi/acse"
+)
+
+// AARQ переносит initiate-RequestPDU MMS в user-information
+func ExampleBuildAARQ() {
+	initiateRequest := []byte{0xa8, 0x00}
+	pdu, err := acse.ParseACSEPDU(acse.BuildAARQ(initiateRequest))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(pdu.Type == acse.AARQ)
+	fmt.Printf("context=% x data=% x\n", pdu.ApplicationContextName, pdu.Data)
+	// Output:
+	// true
+	// context=28 ca 22 02 03 data=a8 00
+}
+
+// Сервер принимает ассоциацию AARE с initiate-ResponsePDU
+func ExampleCreateAssociateResponseMessage() {
+	initiateResponse := []byte{0xa9, 0x00}
+	aare := acse.CreateAssociateResponseMessage(acse.NewConnection(), acse.ResultAccept, initiateResponse)
+	pdu, err := acse.ParseACSEPDU(aare)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(pdu.Type == acse.AARE, pdu.Result)
+	fmt.Printf("data=% x\n", pdu.Data)
+	// Output:
+	// true 0
+	// data=a9 00
+}
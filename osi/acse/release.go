@@ -0,0 +1,99 @@
+package acse
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/slonegd/go61850/internal/ber"
+)
+
+// ReleaseRequestReason represents Release-request-reason of RLRQ (ISO 8650-1)
+type ReleaseRequestReason int32
+
+const (
+	ReleaseRequestNormal      ReleaseRequestReason = 0
+	ReleaseRequestUrgent      ReleaseRequestReason = 1
+	ReleaseRequestUserDefined ReleaseRequestReason = 30
+)
+
+// String returns the ASN.1 name of the reason
+func (r ReleaseRequestReason) String() string {
+	switch r {
+	case ReleaseRequestNormal:
+		return "normal"
+	case ReleaseRequestUrgent:
+		return "urgent"
+	case ReleaseRequestUserDefined:
+		return "user-defined"
+	default:
+		return fmt.Sprintf("unknown(%d)", int32(r))
+	}
+}
+
+// ReleaseResponseReason represents Release-response-reason of RLRE (ISO 8650-1)
+type ReleaseResponseReason int32
+
+const (
+	ReleaseResponseNormal      ReleaseResponseReason = 0
+	ReleaseResponseNotFinished ReleaseResponseReason = 1
+	ReleaseResponseUserDefined ReleaseResponseReason = 30
+)
+
+// String returns the ASN.1 name of the reason
+func (r ReleaseResponseReason) String() string {
+	switch r {
+	case ReleaseResponseNormal:
+		return "normal"
+	case ReleaseResponseNotFinished:
+		return "not-finished"
+	case ReleaseResponseUserDefined:
+		return "user-defined"
+	default:
+		return fmt.Sprintf("unknown(%d)", int32(r))
+	}
+}
+
+// ReleaseError is returned by ParseMessage when the peer answers RLRQ
+// with a reason other than normal (the association is not released)
+type ReleaseError struct {
+	Reason ReleaseResponseReason
+}
+
+func (e *ReleaseError) Error() string {
+	return fmt.Sprintf("association release refused: %s", e.Reason)
+}
+
+// createReleaseMessage creates RLRQ/RLRE with the reason [0] IMPLICIT INTEGER
+func createReleaseMessage(tag byte, reason int32) []byte {
+	size := determineIntegerEncodedSize(reason)
+	buffer := make([]byte, 4+size)
+	bufPos := ber.EncodeTL(ber.Tag(tag), uint32(2+size), buffer, 0)
+	bufPos = ber.EncodeTL(0x80, uint32(size), buffer, bufPos)
+	bufPos = encodeInteger(reason, buffer, bufPos)
+	return buffer[:bufPos]
+}
+
+// parseReleaseReason parses the content of RLRQ/RLRE and returns the reason
+// (-1 if the optional reason is absent). User information is skipped.
+func parseReleaseReason(buffer []byte, bufPos, maxBufPos int) (int32, error) {
+	reason := int32(-1)
+	for bufPos < maxBufPos {
+		tag := buffer[bufPos]
+		bufPos++
+
+		newPos, length, err := ber.DecodeLength(buffer, bufPos, maxBufPos)
+		if err != nil {
+			return -1, fmt.Errorf("invalid release PDU: %w", err)
+		}
+		bufPos = newPos
+
+		if tag == 0x80 {
+			if length < 1 || length > 4 {
+				return -1, errors.New("invalid release PDU: bad reason length")
+			}
+			reason = ber.DecodeInt32(buffer, length, bufPos)
+		}
+		bufPos += length
+	}
+	return reason, nil
+}
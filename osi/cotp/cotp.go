@@ -5,6 +5,9 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/slonegd/go61850/logger"
 )
@@ -19,6 +22,28 @@ const (
 	defaultReadBufferSize      = 8192
 	defaultWriteBufferSize     = 8192
 	defaultSocketExtBufferSize = 8192
+	defaultLocalRef            = 1
+)
+
+// ErrReferenceMismatch возвращается, если dst-ref входящего TPDU не совпадает
+// с source reference соединения
+var ErrReferenceMismatch = errors.New("COTP reference mismatch")
+
+// ErrTPDURejected возвращается при получении ER TPDU: партнёр отклонил наш TPDU
+var ErrTPDURejected = errors.New("COTP TPDU rejected by peer")
+
+// ErrSocketClosed возвращается ReadToTpktBuffer, если партнёр закрыл соединение
+var ErrSocketClosed = errors.New("socket closed")
+
+// ErrBrokenConnection возвращается всеми операциями соединения, разорванного
+// после прерванной передачи TSDU (см. SendDataMessageContext)
+var ErrBrokenConnection = errors.New("COTP connection is broken")
+
+// Причина разрыва в DR TPDU и причина отклонения в ER TPDU (ISO 8073)
+const (
+	disconnectReasonNormal               = 0x80
+	disconnectReasonMismatchedReferences = 0x87
+	rejectCauseInvalidParameterValue     = 0x03
 )
 
 // connectionOptions содержит опции для создания Connection
@@ -27,7 +52,9 @@ type connectionOptions struct {
 	readBufferSize      int
 	writeBufferSize     int
 	socketExtBufferSize int
+	localRef            uint16
 	logger              logger.Logger
+	tap                 PacketTap
 }
 
 // defaultConnectionOptions возвращает опции по умолчанию
@@ -37,6 +64,7 @@ func defaultConnectionOptions() connectionOptions {
 		readBufferSize:      defaultReadBufferSize,
 		writeBufferSize:     defaultWriteBufferSize,
 		socketExtBufferSize: defaultSocketExtBufferSize,
+		localRef:            defaultLocalRef,
 		logger:              defaultLogger(),
 	}
 }
@@ -77,6 +105,27 @@ func WithSocketExtBufferSize(size int) ConnectionOption {
 	}
 }
 
+// WithLocalRef устанавливает source reference соединения (по умолчанию 1).
+// Серверы, обслуживающие несколько соединений, различают их по reference,
+// поэтому клиентам одного сервера можно задать разные значения.
+func WithLocalRef(ref uint16) ConnectionOption {
+	return func(opts *connectionOptions) {
+		opts.localRef = ref
+	}
+}
+
+// PacketTap получает каждый отправленный (outgoing) и принятый TPKT целиком,
+// включая TPDU установления и разрыва соединения. packet действителен только
+// на время вызова.
+type PacketTap func(outgoing bool, packet []byte)
+
+// WithPacketTap устанавливает PacketTap, например для внешнего анализатора трафика
+func WithPacketTap(tap PacketTap) ConnectionOption {
+	return func(opts *connectionOptions) {
+		opts.tap = tap
+	}
+}
+
 // WithLogger устанавливает логгер
 func WithLogger(l logger.Logger) ConnectionOption {
 	return func(opts *connectionOptions) {
@@ -126,13 +175,17 @@ type Connection struct {
 	conn            io.ReadWriteCloser // TCP соединение или TLS соединение
 	options         Options
 	isLastDataUnit  bool
-	payload         []byte        // Буфер для payload данных
-	writeBuffer     []byte        // Буфер для записи TPKT пакета
-	readBuffer      []byte        // Буфер для чтения TPKT пакета
-	packetSize      uint16        // Размер текущего пакета
-	socketExtBuffer []byte        // Буфер для данных, когда TCP сокет не принимает все данные
-	socketExtFill   int           // Количество байт в extension буфере
-	logger          logger.Logger // Логгер для отладки
+	payload         []byte                // Буфер для payload данных
+	writeBuffer     []byte                // Буфер для записи TPKT пакета
+	readBuffer      []byte                // Буфер для чтения TPKT пакета
+	packetSize      uint16                // Размер текущего пакета
+	socketExtBuffer []byte                // Буфер для данных, когда TCP сокет не принимает все данные
+	socketExtFill   int                   // Количество байт в extension буфере
+	logger          logger.Logger         // Логгер для отладки
+	tap             PacketTap             // Получатель отправленных и принятых TPKT
+	lastReceived    atomic.Int64          // Время приёма последнего TPKT (UnixNano), 0 - не было
+	receivedPackets atomic.Uint64         // Количество принятых TPKT
+	broken          atomic.Pointer[error] // Причина разрыва (оборачивает ErrBrokenConnection), nil - соединение исправно
 }
 
 // NewConnection создает новое COTP соединение
@@ -145,7 +198,7 @@ func NewConnection(conn io.ReadWriteCloser, opts ...ConnectionOption) *Connectio
 	c := &Connection{
 		state:           0,
 		remoteRef:       -1,
-		localRef:        1,
+		localRef:        int(options.localRef),
 		protocolClass:   -1,
 		conn:            conn,
 		payload:         make([]byte, 0, options.payloadBufferSize),
@@ -153,6 +206,7 @@ func NewConnection(conn io.ReadWriteCloser, opts ...ConnectionOption) *Connectio
 		readBuffer:      make([]byte, 0, options.readBufferSize),
 		socketExtBuffer: make([]byte, 0, options.socketExtBufferSize),
 		logger:          options.logger,
+		tap:             options.tap,
 	}
 
 	// Установка значений по умолчанию для TSelector
@@ -220,6 +274,36 @@ func (c *Connection) ResetPayload() {
 	c.payload = c.payload[:0]
 }
 
+// SizeBuffers подгоняет буферы соединения под согласованные параметры: payload
+// вмещает maxPayload байт собранного из фрагментов TSDU, extension буфер - все
+// фрагменты такого TSDU при отправке, буферы чтения и записи - TPKT с TPDU
+// согласованного размера. Буферы только увеличиваются, их содержимое сохраняется;
+// размеры из WithPayloadBufferSize и других опций остаются нижней границей.
+func (c *Connection) SizeBuffers(maxPayload int) {
+	packet := tpktRFC1006HeaderSize + c.GetTpduSize()
+	fragmentPayloadSize := c.GetTpduSize() - cotpDataHeaderSize
+	fragments := (maxPayload + fragmentPayloadSize - 1) / fragmentPayloadSize
+	c.payload = growBuffer(c.payload, maxPayload)
+	c.socketExtBuffer = growBuffer(c.socketExtBuffer, maxPayload+fragments*(cotpDataHeaderSize+tpktRFC1006HeaderSize))
+	c.readBuffer = growBuffer(c.readBuffer, packet)
+	c.writeBuffer = growBuffer(c.writeBuffer, packet)
+}
+
+// growBuffer возвращает буфер с содержимым buffer и ёмкостью не меньше size
+func growBuffer(buffer []byte, size int) []byte {
+	if cap(buffer) >= size {
+		return buffer
+	}
+	grown := make([]byte, len(buffer), size)
+	copy(grown, buffer)
+	return grown
+}
+
+// BufferSizes возвращает ёмкость буферов payload, чтения и записи
+func (c *Connection) BufferSizes() (payload, read, write int) {
+	return cap(c.payload), cap(c.readBuffer), cap(c.writeBuffer)
+}
+
 // FlushBuffer сбрасывает extension буфер
 func (c *Connection) FlushBuffer() error {
 	if c.socketExtFill > 0 {
@@ -304,6 +388,10 @@ func (c *Connection) flushBuffer() error {
 
 // sendBuffer отправляет буфер в сокет
 func (c *Connection) sendBuffer() error {
+	if err := c.Err(); err != nil {
+		c.writeBuffer = c.writeBuffer[:0]
+		return err
+	}
 	if err := c.flushBuffer(); err != nil {
 		return err
 	}
@@ -311,6 +399,9 @@ func (c *Connection) sendBuffer() error {
 	if len(c.writeBuffer) == 0 {
 		return nil
 	}
+	if c.tap != nil {
+		c.tap(true, c.writeBuffer)
+	}
 
 	var n int
 	var err error
@@ -538,7 +629,7 @@ func (c *Connection) parseConnectRequestTpdu(buffer []byte) error {
 		return errors.New("connect request TPDU too short")
 	}
 
-	c.remoteRef = int(buffer[0])<<8 | int(buffer[1])
+	c.remoteRef = int(buffer[2])<<8 | int(buffer[3])
 	c.protocolClass = int(buffer[4])
 
 	return c.parseOptions(buffer[5:])
@@ -550,12 +641,61 @@ func (c *Connection) parseConnectConfirmTpdu(buffer []byte) error {
 		return errors.New("connect confirm TPDU too short")
 	}
 
-	c.remoteRef = int(buffer[0])<<8 | int(buffer[1])
+	if err := c.checkDstRef(buffer); err != nil {
+		return err
+	}
+	c.remoteRef = int(buffer[2])<<8 | int(buffer[3])
 	c.protocolClass = int(buffer[4])
 
 	return c.parseOptions(buffer[5:])
 }
 
+// checkDstRef сверяет dst-ref в начале заголовка TPDU с source reference соединения
+func (c *Connection) checkDstRef(buffer []byte) error {
+	if len(buffer) < 2 {
+		return errors.New("TPDU too short: dst-ref missing")
+	}
+	dstRef := int(buffer[0])<<8 | int(buffer[1])
+	if dstRef != c.localRef {
+		return fmt.Errorf("%w: dst-ref 0x%04x, local ref 0x%04x", ErrReferenceMismatch, dstRef, c.localRef)
+	}
+	return nil
+}
+
+// Disconnect отправляет DR TPDU с причиной "normal disconnect initiated by
+// session entity" - разрыв соединения по инициативе стороны, например при
+// остановке сервера. Сокет не закрывается.
+func (c *Connection) Disconnect() error {
+	return c.sendDisconnectRequest(disconnectReasonNormal)
+}
+
+// sendDisconnectRequest отправляет DR TPDU с причиной reason
+func (c *Connection) sendDisconnectRequest(reason byte) error {
+	c.writeRfc1006Header(11)
+	c.writeBuffer = append(c.writeBuffer, 6, byte(COTPTypeDisconnectRequest),
+		byte(c.remoteRef>>8), byte(c.remoteRef&0xff),
+		byte(c.localRef>>8), byte(c.localRef&0xff),
+		reason)
+
+	if c.logger != nil {
+		c.logger.Debug("TX: % x", c.writeBuffer)
+	}
+	return c.sendBuffer()
+}
+
+// sendErrorTpdu отправляет ER TPDU с причиной cause
+func (c *Connection) sendErrorTpdu(cause byte) error {
+	c.writeRfc1006Header(9)
+	c.writeBuffer = append(c.writeBuffer, 4, byte(COTPTypeError),
+		byte(c.remoteRef>>8), byte(c.remoteRef&0xff),
+		cause)
+
+	if c.logger != nil {
+		c.logger.Debug("TX: % x", c.writeBuffer)
+	}
+	return c.sendBuffer()
+}
+
 // parseDataTpdu парсит Data TPDU
 func (c *Connection) parseDataTpdu(buffer []byte) error {
 	if len(buffer) < 1 {
@@ -616,6 +756,13 @@ func (c *Connection) parseCotpMessage() (Indication, error) {
 
 	case 0xd0: // Connect Confirm
 		if err := c.parseConnectConfirmTpdu(buffer[2:]); err != nil {
+			if errors.Is(err, ErrReferenceMismatch) {
+				// CC адресован другому соединению: разрываем его с причиной mismatched references
+				c.remoteRef = int(buffer[4])<<8 | int(buffer[5])
+				if sendErr := c.sendDisconnectRequest(disconnectReasonMismatchedReferences); sendErr != nil {
+					err = errors.Join(err, sendErr)
+				}
+			}
 			return IndicationError, err
 		}
 		return IndicationConnect, nil
@@ -640,11 +787,23 @@ func (c *Connection) parseCotpMessage() (Indication, error) {
 		}
 		return IndicationMoreFragmentsFollow, nil
 
-	case 0x80: // Disconnect Request
+	case 0x80, 0xc0: // Disconnect Request, Disconnect Confirm
+		if err := c.checkDstRef(buffer[2:]); err != nil {
+			if errors.Is(err, ErrReferenceMismatch) {
+				if sendErr := c.sendErrorTpdu(rejectCauseInvalidParameterValue); sendErr != nil {
+					err = errors.Join(err, sendErr)
+				}
+			}
+			return IndicationError, err
+		}
 		return IndicationDisconnect, nil
 
-	case 0xc0: // Disconnect Confirm
-		return IndicationDisconnect, nil
+	case 0x70: // TPDU Error
+		cause := byte(0)
+		if len(buffer) > 4 {
+			cause = buffer[4]
+		}
+		return IndicationError, fmt.Errorf("%w: reject cause %d", ErrTPDURejected, cause)
 
 	default:
 		return IndicationError, fmt.Errorf("unknown TPDU type: 0x%02x", tpduType)
@@ -653,6 +812,9 @@ func (c *Connection) parseCotpMessage() (Indication, error) {
 
 // ParseIncomingMessage парсит входящее сообщение
 func (c *Connection) ParseIncomingMessage() (Indication, error) {
+	if c.tap != nil && len(c.readBuffer) > 0 {
+		c.tap(false, c.readBuffer)
+	}
 	// Логирование полного TPKT пакета перед парсингом
 	if c.logger != nil && len(c.readBuffer) > 0 {
 		c.logger.Debug("RX: % x", c.readBuffer)
@@ -676,8 +838,24 @@ func (c *Connection) ParseIncomingMessage() (Indication, error) {
 	return indication, err
 }
 
-// SendDataMessage отправляет сообщение с данными
+// SendDataMessage отправляет сообщение с данными (SendDataMessageContext без отмены)
 func (c *Connection) SendDataMessage(payload []byte) error {
+	return c.SendDataMessageContext(context.Background(), payload)
+}
+
+// SendDataMessageContext отправляет сообщение с данными, деля его на Data TPDU.
+// Отмена ctx до отправки первого фрагмента возвращает ctx.Err(), соединение остаётся
+// исправным; для net.Conn отмена прерывает и начатую запись. Если часть TSDU уже
+// передана, партнёр не сможет его собрать: соединение разрывается - DR TPDU, если
+// поток TPKT не нарушен, затем закрытие сокета, - и этот и все последующие вызовы
+// возвращают ошибку, оборачивающую ErrBrokenConnection и причину разрыва.
+func (c *Connection) SendDataMessageContext(ctx context.Context, payload []byte) error {
+	if err := c.Err(); err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	fragmentPayloadSize := c.GetTpduSize() - cotpDataHeaderSize
 
 	fragments := 1
@@ -704,9 +882,19 @@ func (c *Connection) SendDataMessage(payload []byte) error {
 		return err
 	}
 
+	stop := c.interruptWrite(ctx)
+	defer stop()
 	currentBufPos := 0
 
 	for fragments > 0 {
+		if err := ctx.Err(); err != nil {
+			if currentBufPos == 0 {
+				return err
+			}
+			stop()
+			return c.abort(err, true)
+		}
+
 		var currentLimit int
 		var lastUnit bool
 
@@ -731,7 +919,12 @@ func (c *Connection) SendDataMessage(payload []byte) error {
 		}
 
 		if err := c.sendBuffer(); err != nil {
-			return fmt.Errorf("failed to send fragment: %w", err)
+			stop()
+			if ctx.Err() != nil {
+				err = ctx.Err()
+			}
+			// Запись могла оборваться посреди TPKT: DR уже не будет разобран партнёром
+			return c.abort(fmt.Errorf("failed to send fragment: %w", err), false)
 		}
 
 		currentBufPos = currentLimit
@@ -741,9 +934,104 @@ func (c *Connection) SendDataMessage(payload []byte) error {
 	return nil
 }
 
+// Err возвращает причину разрыва соединения (оборачивает ErrBrokenConnection)
+// или nil, если соединение исправно. Безопасен для вызова из другой горутины.
+func (c *Connection) Err() error {
+	if err := c.broken.Load(); err != nil {
+		return *err
+	}
+	return nil
+}
+
+// abortWriteTimeout ограничивает отправку DR TPDU при разрыве соединения
+const abortWriteTimeout = time.Second
+
+// abort разрывает соединение после прерванной передачи TSDU по причине cause:
+// отправляет DR TPDU, если поток TPKT не нарушен (sendDR), и закрывает сокет
+func (c *Connection) abort(cause error, sendDR bool) error {
+	if sendDR {
+		if conn, ok := c.conn.(writeDeadliner); ok {
+			conn.SetWriteDeadline(time.Now().Add(abortWriteTimeout))
+		}
+		c.writeBuffer = c.writeBuffer[:0]
+		if err := c.sendDisconnectRequest(disconnectReasonNormal); err != nil && c.logger != nil {
+			c.logger.Debug("failed to send DR: %v", err)
+		}
+	}
+	c.conn.Close()
+	err := fmt.Errorf("%w: %w", ErrBrokenConnection, cause)
+	c.broken.Store(&err)
+	return err
+}
+
+// writeDeadliner - соединение, блокирующую запись в которое можно прервать (net.Conn)
+type writeDeadliner interface {
+	SetWriteDeadline(t time.Time) error
+}
+
+// interruptWrite прерывает блокирующую запись при отмене ctx, если соединение
+// поддерживает SetWriteDeadline. Возвращённая функция снимает прерывание и дедлайн;
+// повторные вызовы ничего не делают.
+func (c *Connection) interruptWrite(ctx context.Context) (stop func()) {
+	conn, ok := c.conn.(writeDeadliner)
+	if !ok || ctx.Done() == nil {
+		return func() {}
+	}
+	interrupted := make(chan struct{})
+	stopAfter := context.AfterFunc(ctx, func() {
+		conn.SetWriteDeadline(time.Unix(1, 0))
+		close(interrupted)
+	})
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			if !stopAfter() {
+				<-interrupted
+				conn.SetWriteDeadline(time.Time{})
+			}
+		})
+	}
+}
+
+// readDeadliner - соединение, блокирующее чтение которого можно прервать (net.Conn)
+type readDeadliner interface {
+	SetReadDeadline(t time.Time) error
+}
+
+// read читает из соединения. Если соединение поддерживает SetReadDeadline,
+// отмена ctx прерывает блокирующее чтение, и возвращается ctx.Err().
+// Уже принятые данные пакета сохраняются в буфере, поэтому чтение можно продолжить
+// с другим контекстом.
+func (c *Connection) read(ctx context.Context, b []byte) (int, error) {
+	conn, ok := c.conn.(readDeadliner)
+	if !ok || ctx.Done() == nil {
+		return c.conn.Read(b)
+	}
+
+	interrupted := make(chan struct{})
+	stop := context.AfterFunc(ctx, func() {
+		conn.SetReadDeadline(time.Unix(1, 0))
+		close(interrupted)
+	})
+	n, err := c.conn.Read(b)
+	if !stop() {
+		// Дедлайн выставлен отменой контекста: снимаем его для следующих чтений
+		<-interrupted
+		conn.SetReadDeadline(time.Time{})
+		if err != nil {
+			return n, ctx.Err()
+		}
+	}
+	return n, err
+}
+
 // ReadToTpktBuffer читает данные в TPKT буфер
-// Проверяет контекст перед блокирующими операциями чтения
+// Проверяет контекст перед блокирующими операциями чтения; для net.Conn
+// отмена контекста прерывает и уже начатое чтение
 func (c *Connection) ReadToTpktBuffer(ctx context.Context) (TpktState, error) {
+	if err := c.Err(); err != nil {
+		return TpktError, err
+	}
 	if cap(c.readBuffer) < 4 {
 		return TpktError, errors.New("read buffer too small")
 	}
@@ -773,10 +1061,13 @@ func (c *Connection) ReadToTpktBuffer(ctx context.Context) (TpktState, error) {
 		}
 
 		readBytes := make([]byte, 4-bufPos)
-		n, err := c.conn.Read(readBytes)
+		n, err := c.read(ctx, readBytes)
 		if err != nil {
+			if ctx.Err() != nil && errors.Is(err, ctx.Err()) {
+				return TpktError, err
+			}
 			if err == io.EOF {
-				return TpktError, errors.New("socket closed")
+				return TpktError, ErrSocketClosed
 			}
 			return TpktError, fmt.Errorf("read error: %w", err)
 		}
@@ -820,10 +1111,13 @@ func (c *Connection) ReadToTpktBuffer(ctx context.Context) (TpktState, error) {
 	}
 
 	readBytes := make([]byte, int(c.packetSize)-bufPos)
-	n, err := c.conn.Read(readBytes)
+	n, err := c.read(ctx, readBytes)
 	if err != nil {
+		if ctx.Err() != nil && errors.Is(err, ctx.Err()) {
+			return TpktError, err
+		}
 		if err == io.EOF {
-			return TpktError, errors.New("socket closed")
+			return TpktError, ErrSocketClosed
 		}
 		return TpktError, fmt.Errorf("read error: %w", err)
 	}
@@ -840,9 +1134,27 @@ func (c *Connection) ReadToTpktBuffer(ctx context.Context) (TpktState, error) {
 	}
 
 	// Пакет полностью прочитан
+	c.lastReceived.Store(time.Now().UnixNano())
+	c.receivedPackets.Add(1)
 	return TpktPacketComplete, nil
 }
 
+// LastActivity возвращает время приёма последнего TPKT; нулевое, если пакетов не было.
+// Безопасен для вызова из другой горутины.
+func (c *Connection) LastActivity() time.Time {
+	nanos := c.lastReceived.Load()
+	if nanos == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, nanos)
+}
+
+// ReceivedPackets возвращает количество принятых TPKT.
+// Безопасен для вызова из другой горутины.
+func (c *Connection) ReceivedPackets() uint64 {
+	return c.receivedPackets.Load()
+}
+
 // TPKT представляет TPKT (RFC 1006) пакет
 type TPKT struct {
 	Version  uint8  // Версия протокола (обычно 3)
@@ -901,6 +1213,7 @@ const (
 	COTPTypeConnectionConfirm COTPType = 0xd0 // Connection Confirm
 	COTPTypeDisconnectRequest COTPType = 0x80 // Disconnect Request
 	COTPTypeDisconnectConfirm COTPType = 0xc0 // Disconnect Confirm
+	COTPTypeError             COTPType = 0x70 // TPDU Error
 )
 
 // COTP представляет COTP (ISO 8073/X.224) пакет
@@ -1048,6 +1361,8 @@ func (c *COTP) String() string {
 		typeStr = "DisconnectRequest"
 	case COTPTypeDisconnectConfirm:
 		typeStr = "DisconnectConfirm"
+	case COTPTypeError:
+		typeStr = "Error"
 	}
 
 	if c.Type == COTPTypeData {
@@ -0,0 +1,17 @@
+// Package cotp реализует транспорт ISO-on-TCP: TPKT (RFC 1006) и COTP класса 0
+// (ISO 8073) - нижний уровень стека MMS над TCP (порт 102) или TLS (порт 3782).
+//
+// Connection устанавливает транспортное соединение (Connect - клиент,
+// SendConnectionResponseMessage на IndicationConnect - сервер), делит TSDU
+// на Data TPDU согласованного размера (SendDataMessage) и собирает принятые
+// фрагменты: ReadToTpktBuffer читает один TPKT, ParseIncomingMessage разбирает
+// его, а GetPayload после IndicationData возвращает TSDU целиком - Session SPDU
+// для пакета osi/session. Разбор захваченных пакетов без соединения - ParseTPKT
+// и ParseCOTP. Расположение уровней стека описано в документации osi/mms.
+//
+// SendDataMessageContext, отменённый посреди TSDU, разрывает соединение:
+// Err и все последующие операции возвращают ошибку ErrBrokenConnection.
+//
+// Пакет не входит в стабильный публичный API модуля (см. документацию go61850):
+// его API может меняться без сохранения совместимости и не проверяется make apidiff.
+package cotp
@@ -0,0 +1,93 @@
+package cotp_test
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+
+	"github.com/slonegd/go61850/osi/cotp"
+)
+
+// nopLogger отключает отладочный вывод в примерах
+type nopLogger struct{}
+
+func (nopLogger) Debug(string, ...any) {}
+
+// Соединение в памяти: net.Pipe вместо TCP, например для тестов.
+// Подходит любой io.ReadWriteCloser.
+func ExampleNewConnectedConnection() {
+	ctx := context.Background()
+	client, server := net.Pipe()
+
+	// Серверная сторона: принять CR, ответить CC и вернуть полученные данные
+	go func() {
+		c := cotp.NewConnection(server, cotp.WithLogger(nopLogger{}))
+		for {
+			state, err := c.ReadToTpktBuffer(ctx)
+			if err != nil {
+				return
+			}
+			if state != cotp.TpktPacketComplete {
+				continue
+			}
+			indication, err := c.ParseIncomingMessage()
+			if err != nil {
+				return
+			}
+			switch indication {
+			case cotp.IndicationConnect:
+				c.SendConnectionResponseMessage()
+			case cotp.IndicationData:
+				c.SendDataMessage(c.GetPayload())
+			}
+		}
+	}()
+
+	params := &cotp.IsoConnectionParameters{
+		RemoteTSelector: cotp.TSelector{Value: []byte{0, 1}},
+		LocalTSelector:  cotp.TSelector{Value: []byte{0, 1}},
+	}
+	c, err := cotp.NewConnectedConnection(ctx, client, params, cotp.WithLogger(nopLogger{}))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer client.Close()
+
+	c.SendDataMessage([]byte("hello"))
+	for {
+		state, err := c.ReadToTpktBuffer(ctx)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		if state != cotp.TpktPacketComplete {
+			continue
+		}
+		if indication, _ := c.ParseIncomingMessage(); indication == cotp.IndicationData {
+			break
+		}
+	}
+	fmt.Printf("%s\n", c.GetPayload())
+	// Output: hello
+}
+
+// Соединение через TLS (IEC 62351-4): COTP работает поверх tls.Conn без изменений
+func ExampleNewConnectedConnection_tls() {
+	ctx := context.Background()
+	conn, err := (&tls.Dialer{Config: &tls.Config{ServerName: "ied"}}).DialContext(ctx, "tcp", "ied:3782")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer conn.Close()
+
+	params := &cotp.IsoConnectionParameters{
+		RemoteTSelector: cotp.TSelector{Value: []byte{0, 1}},
+		LocalTSelector:  cotp.TSelector{Value: []byte{0, 1}},
+	}
+	if _, err := cotp.NewConnectedConnection(ctx, conn, params); err != nil {
+		fmt.Println(err)
+	}
+}
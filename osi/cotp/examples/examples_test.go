@@ -363,3 +363,90 @@ func TestBidirectionalDataExchange(t *testing.T) {
 		}
 	}
 }
+
+func TestGracefulStop(t *testing.T) {
+	params := &cotp.IsoConnectionParameters{
+		RemoteTSelector: cotp.TSelector{Value: []byte{0, 1}},
+		LocalTSelector:  cotp.TSelector{Value: []byte{0, 1}},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Обработчик завершает обмен по Done: Stop не ждёт периода ожидания
+	server := NewServer("localhost:0")
+	server.SetGracePeriod(time.Minute)
+	connected := make(chan struct{})
+	server.SetHandler(func(conn *Connection) error {
+		close(connected)
+		<-conn.Done()
+		return conn.SendData([]byte("bye"))
+	})
+	if err := server.Start(); err != nil {
+		t.Fatalf("Failed to start server: %v", err)
+	}
+	client := NewClient(server.Addr().String(), nil)
+	if err := client.Connect(ctx, params); err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer client.Close()
+	<-connected
+
+	start := time.Now()
+	if err := server.Stop(); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Stop took %v, expected to return after handler", elapsed)
+	}
+	data, err := client.ReceiveData(time.Second)
+	if err != nil || string(data) != "bye" {
+		t.Errorf("Expected \"bye\" from handler, got %q, %v", data, err)
+	}
+	// Повторный Stop безопасен
+	if err := server.Stop(); err != nil {
+		t.Errorf("Second Stop failed: %v", err)
+	}
+}
+
+func TestStopAbortsAfterGracePeriod(t *testing.T) {
+	params := &cotp.IsoConnectionParameters{
+		RemoteTSelector: cotp.TSelector{Value: []byte{0, 1}},
+		LocalTSelector:  cotp.TSelector{Value: []byte{0, 1}},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Обработчик игнорирует Done и ждёт данных, пока соединение не будет разорвано
+	server := NewServer("localhost:0")
+	server.SetGracePeriod(100 * time.Millisecond)
+	connected := make(chan struct{})
+	server.SetHandler(func(conn *Connection) error {
+		close(connected)
+		_, err := conn.ReceiveData(time.Minute)
+		return err
+	})
+	if err := server.Start(); err != nil {
+		t.Fatalf("Failed to start server: %v", err)
+	}
+	client := NewClient(server.Addr().String(), nil)
+	if err := client.Connect(ctx, params); err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer client.Close()
+	<-connected
+
+	if err := server.Stop(); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+	// Сервер закрывает сокет, клиент видит закрытие соединения
+	if _, err := client.ReceiveData(time.Second); err == nil || err.Error() == "receive timeout" {
+		t.Errorf("Expected connection closed, got %v", err)
+	}
+
+	// Новые соединения не принимаются
+	late := NewClient(server.Addr().String(), nil)
+	if err := late.Connect(ctx, params); err == nil {
+		late.Close()
+		t.Error("Expected connection to stopped server to fail")
+	}
+}
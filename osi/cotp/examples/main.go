@@ -40,6 +40,14 @@ func main() {
 
 		// Обработка сообщений до закрытия соединения
 		for {
+			// Сервер останавливается: завершаем обработку
+			select {
+			case <-conn.Done():
+				serverLogger.Debug("Server is stopping, closing connection")
+				return nil
+			default:
+			}
+
 			// Получение ping с таймаутом
 			data, err := conn.ReceiveData(2 * time.Second)
 			if err != nil {
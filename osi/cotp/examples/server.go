@@ -2,21 +2,37 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net"
+	"sync"
 	"time"
 
 	"github.com/slonegd/go61850/logger"
 	"github.com/slonegd/go61850/osi/cotp"
 )
 
+// DefaultGracePeriod - время, которое Stop даёт обработчикам на завершение
+const DefaultGracePeriod = 5 * time.Second
+
 // Server представляет COTP сервер
 type Server struct {
-	listener net.Listener
-	address  string
-	handler  func(*Connection) error
-	logger   logger.Logger
+	listener    net.Listener
+	address     string
+	handler     func(*Connection) error
+	logger      logger.Logger
+	gracePeriod time.Duration
+
+	mu    sync.Mutex
+	conns map[*Connection]struct{}
+	// handlers - выполняющиеся обработчики соединений
+	handlers sync.WaitGroup
+	// acceptDone закрывается при выходе из acceptLoop
+	acceptDone chan struct{}
+	// draining закрывается в начале остановки сервера
+	draining chan struct{}
+	stopOnce sync.Once
 }
 
 // Addr возвращает адрес сервера
@@ -31,15 +47,26 @@ func (s *Server) Addr() net.Addr {
 type Connection struct {
 	conn     net.Conn
 	cotpConn *cotp.Connection
+	draining <-chan struct{}
 }
 
 // NewServer создает новый COTP сервер
 func NewServer(address string) *Server {
 	return &Server{
-		address: address,
+		address:     address,
+		gracePeriod: DefaultGracePeriod,
+		conns:       make(map[*Connection]struct{}),
+		acceptDone:  make(chan struct{}),
+		draining:    make(chan struct{}),
 	}
 }
 
+// SetGracePeriod устанавливает время, которое Stop ждёт завершения обработчиков
+// перед принудительным разрывом соединений
+func (s *Server) SetGracePeriod(d time.Duration) {
+	s.gracePeriod = d
+}
+
 // SetLogger устанавливает логгер для сервера
 func (s *Server) SetLogger(l logger.Logger) {
 	s.logger = l
@@ -66,18 +93,21 @@ func (s *Server) Start() error {
 
 // acceptLoop принимает входящие соединения
 func (s *Server) acceptLoop() {
+	defer close(s.acceptDone)
 	for {
 		conn, err := s.listener.Accept()
 		if err != nil {
 			return
 		}
 
+		s.handlers.Add(1)
 		go s.handleConnection(conn)
 	}
 }
 
 // handleConnection обрабатывает входящее соединение
 func (s *Server) handleConnection(conn net.Conn) {
+	defer s.handlers.Done()
 	defer conn.Close()
 
 	cotpConn := cotp.NewConnection(conn, cotp.WithLogger(s.logger))
@@ -96,7 +126,16 @@ func (s *Server) handleConnection(conn net.Conn) {
 	connection := &Connection{
 		conn:     conn,
 		cotpConn: cotpConn,
+		draining: s.draining,
 	}
+	s.mu.Lock()
+	s.conns[connection] = struct{}{}
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, connection)
+		s.mu.Unlock()
+	}()
 
 	// Вызов обработчика
 	if s.handler != nil {
@@ -204,6 +243,13 @@ func (c *Connection) ReceiveData(timeout time.Duration) ([]byte, error) {
 	return nil, fmt.Errorf("receive timeout")
 }
 
+// Done возвращает канал, закрываемый в начале остановки сервера (Stop).
+// Обработчик должен завершить обмен (например, отправить conclude или release
+// прикладного уровня) и вернуться до истечения периода SetGracePeriod.
+func (c *Connection) Done() <-chan struct{} {
+	return c.draining
+}
+
 // GetConnection возвращает COTP соединение
 func (c *Connection) GetConnection() *cotp.Connection {
 	return c.cotpConn
@@ -214,10 +260,49 @@ func (c *Connection) Close() error {
 	return c.conn.Close()
 }
 
-// Stop останавливает сервер
+// Stop останавливает сервер: прекращает приём соединений, сообщает обработчикам
+// об остановке через Connection.Done и ждёт их завершения в течение периода
+// SetGracePeriod. Сокеты оставшихся соединений закрываются: COTP соединение
+// принадлежит обработчику, поэтому Disconnect Request не отправляется, а
+// обработчик получает ошибку чтения или записи. Stop возвращается после
+// завершения всех обработчиков.
 func (s *Server) Stop() error {
-	if s.listener != nil {
-		return s.listener.Close()
+	if s.listener == nil {
+		return nil
 	}
-	return nil
+	var err error
+	s.stopOnce.Do(func() {
+		err = s.listener.Close()
+		if errors.Is(err, net.ErrClosed) {
+			err = nil
+		}
+		// После выхода из acceptLoop новые обработчики не добавляются
+		<-s.acceptDone
+		close(s.draining)
+
+		done := make(chan struct{})
+		go func() {
+			s.handlers.Wait()
+			close(done)
+		}()
+
+		timer := time.NewTimer(s.gracePeriod)
+		defer timer.Stop()
+		select {
+		case <-done:
+			return
+		case <-timer.C:
+		}
+
+		s.mu.Lock()
+		for c := range s.conns {
+			if s.logger != nil {
+				s.logger.Debug("aborting connection %s after grace period", c.conn.RemoteAddr())
+			}
+			c.conn.Close()
+		}
+		s.mu.Unlock()
+		<-done
+	})
+	return err
 }
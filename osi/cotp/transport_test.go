@@ -0,0 +1,351 @@
+package cotp
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"io"
+	"math/big"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// pipeConn - транспорт без методов net.Conn поверх io.Pipe
+type pipeConn struct {
+	io.Reader
+	io.Writer
+	closers []io.Closer
+}
+
+func (c *pipeConn) Close() error {
+	for _, closer := range c.closers {
+		closer.Close()
+	}
+	return nil
+}
+
+// memoryPipe возвращает два конца дуплексного канала в памяти
+func memoryPipe() (io.ReadWriteCloser, io.ReadWriteCloser) {
+	clientRead, serverWrite := io.Pipe()
+	serverRead, clientWrite := io.Pipe()
+	return &pipeConn{Reader: clientRead, Writer: clientWrite, closers: []io.Closer{clientRead, clientWrite}},
+		&pipeConn{Reader: serverRead, Writer: serverWrite, closers: []io.Closer{serverRead, serverWrite}}
+}
+
+// tlsPipe возвращает клиентский и серверный концы TLS соединения поверх TCP
+func tlsPipe(t *testing.T) (io.ReadWriteCloser, io.ReadWriteCloser) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "ied"},
+		DNSNames:     []string{"ied"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	roots := x509.NewCertPool()
+	roots.AddCert(cert)
+
+	client, server := socketPipe(t, "tcp", "127.0.0.1:0")
+	return tls.Client(client, &tls.Config{RootCAs: roots, ServerName: "ied"}),
+		tls.Server(server, &tls.Config{Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}}})
+}
+
+// socketPipe возвращает два конца соединения через сокет network
+func socketPipe(t *testing.T, network, address string) (net.Conn, net.Conn) {
+	listener, err := net.Listen(network, address)
+	if err != nil {
+		t.Skipf("%s sockets unavailable: %v", network, err)
+	}
+	defer listener.Close()
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		conn, _ := listener.Accept()
+		accepted <- conn
+	}()
+	client, err := net.Dial(network, listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := <-accepted
+	if server == nil {
+		t.Fatal("accept failed")
+	}
+	return client, server
+}
+
+// receive читает одно COTP сообщение
+func receive(ctx context.Context, c *Connection) (Indication, error) {
+	for {
+		state, err := c.ReadToTpktBuffer(ctx)
+		if err != nil {
+			return 0, err
+		}
+		if state == TpktPacketComplete {
+			return c.ParseIncomingMessage()
+		}
+	}
+}
+
+// echo принимает соединение и возвращает первое сообщение данных
+func echo(ctx context.Context, conn io.ReadWriteCloser) error {
+	c := NewConnection(conn)
+	if indication, err := receive(ctx, c); err != nil || indication != IndicationConnect {
+		return errors.Join(errors.New("connection request expected"), err)
+	}
+	if err := c.SendConnectionResponseMessage(); err != nil {
+		return err
+	}
+	if indication, err := receive(ctx, c); err != nil || indication != IndicationData {
+		return errors.Join(errors.New("data expected"), err)
+	}
+	return c.SendDataMessage(c.GetPayload())
+}
+
+func TestTransports(t *testing.T) {
+	transports := map[string]func(t *testing.T) (io.ReadWriteCloser, io.ReadWriteCloser){
+		"memory": func(*testing.T) (io.ReadWriteCloser, io.ReadWriteCloser) { return memoryPipe() },
+		"tls":    tlsPipe,
+		"unix": func(t *testing.T) (io.ReadWriteCloser, io.ReadWriteCloser) {
+			return socketPipe(t, "unix", filepath.Join(t.TempDir(), "cotp.sock"))
+		},
+	}
+	for name, pipe := range transports {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			client, server := pipe(t)
+			defer client.Close()
+			defer server.Close()
+
+			served := make(chan error, 1)
+			go func() { served <- echo(ctx, server) }()
+
+			params := &IsoConnectionParameters{
+				RemoteTSelector: TSelector{Value: []byte{0, 1}},
+				LocalTSelector:  TSelector{Value: []byte{0, 1}},
+			}
+			c, err := NewConnectedConnection(ctx, client, params)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Сообщение больше буфера чтения TLS записи и UNIX сокета по умолчанию
+			payload := bytes.Repeat([]byte("go61850 "), 750)
+			if err := c.SendDataMessage(payload); err != nil {
+				t.Fatal(err)
+			}
+			indication, err := receive(ctx, c)
+			if err != nil || indication != IndicationData {
+				t.Fatalf("indication %v, error %v", indication, err)
+			}
+			if !bytes.Equal(c.GetPayload(), payload) {
+				t.Errorf("payload mismatch: %d bytes received", len(c.GetPayload()))
+			}
+			if err := <-served; err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestReferences(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	params := &IsoConnectionParameters{
+		RemoteTSelector: TSelector{Value: []byte{0, 1}},
+		LocalTSelector:  TSelector{Value: []byte{0, 1}},
+	}
+
+	// Сервер отвечает на CR с src-ref клиента в dst-ref
+	client, server := memoryPipe()
+	served := make(chan int, 1)
+	go func() {
+		c := NewConnection(server, WithLocalRef(2))
+		receive(ctx, c)
+		served <- c.GetRemoteRef()
+		c.SendConnectionResponseMessage()
+	}()
+	c, err := NewConnectedConnection(ctx, client, params, WithLocalRef(7))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ref := <-served; ref != 7 {
+		t.Errorf("server remote ref = %d, want 7", ref)
+	}
+	if c.GetLocalRef() != 7 || c.GetRemoteRef() != 2 {
+		t.Errorf("client refs: local %d, remote %d", c.GetLocalRef(), c.GetRemoteRef())
+	}
+	if c.ReceivedPackets() != 1 || time.Since(c.LastActivity()) > time.Second {
+		t.Errorf("activity: %d packets, last at %v", c.ReceivedPackets(), c.LastActivity())
+	}
+	client.Close()
+	server.Close()
+
+	// CC для другого соединения: клиент отвечает DR с причиной mismatched references
+	client, server = memoryPipe()
+	defer client.Close()
+	defer server.Close()
+	disconnected := make(chan Indication, 1)
+	go func() {
+		c := NewConnection(server, WithLocalRef(2))
+		receive(ctx, c)
+		server.Write(parseHexString("03 00 00 0e 09 d0 00 09 00 02 00 c0 01 0d"))
+		indication, _ := receive(ctx, c)
+		disconnected <- indication
+	}()
+	_, err = NewConnectedConnection(ctx, client, params)
+	if !errors.Is(err, ErrReferenceMismatch) {
+		t.Errorf("error = %v, want ErrReferenceMismatch", err)
+	}
+	if indication := <-disconnected; indication != IndicationDisconnect {
+		t.Errorf("server indication = %v, want IndicationDisconnect", indication)
+	}
+}
+
+func TestSizeBuffers(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	// TSDU больше буферов по умолчанию передаётся несколькими TPDU максимального размера
+	payload := bytes.Repeat([]byte{0xa5}, 3*defaultPayloadBufferSize)
+	transfer := func(sizeReceiver bool) (*Connection, error) {
+		client, server := memoryPipe()
+		defer client.Close()
+		defer server.Close()
+		sender := NewConnection(client)
+		sender.SizeBuffers(len(payload))
+		go sender.SendDataMessage(payload)
+		c := NewConnection(server)
+		if sizeReceiver {
+			c.SizeBuffers(len(payload))
+		}
+		for {
+			indication, err := receive(ctx, c)
+			if err != nil || indication == IndicationData {
+				return c, err
+			}
+		}
+	}
+
+	if _, err := transfer(false); err == nil {
+		t.Error("error expected with default buffer sizes")
+	}
+
+	// После подгонки под согласованный размер TSDU собирается целиком
+	c, err := transfer(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(c.GetPayload(), payload) {
+		t.Errorf("payload mismatch: %d bytes received", len(c.GetPayload()))
+	}
+	payloadSize, readSize, writeSize := c.BufferSizes()
+	if payloadSize != len(payload) || readSize < c.GetTpduSize()+4 || writeSize < c.GetTpduSize()+4 {
+		t.Errorf("buffer sizes: payload %d, read %d, write %d", payloadSize, readSize, writeSize)
+	}
+}
+
+// recordingConn записывает отправленные данные и вызывает onWrite после каждой записи
+type recordingConn struct {
+	bytes.Buffer
+	onWrite func()
+	closed  bool
+}
+
+func (c *recordingConn) Write(b []byte) (int, error) {
+	n, err := c.Buffer.Write(b)
+	c.onWrite()
+	return n, err
+}
+
+func (c *recordingConn) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestSendDataMessageContext(t *testing.T) {
+	payload := bytes.Repeat([]byte{0xa5}, 1000)
+
+	// Отмена до первого фрагмента не нарушает соединение
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	conn := &recordingConn{onWrite: func() {}}
+	c := NewConnection(conn)
+	c.SetTpduSize(128)
+	if err := c.SendDataMessageContext(ctx, payload); !errors.Is(err, context.Canceled) || errors.Is(err, ErrBrokenConnection) {
+		t.Errorf("error = %v, want context.Canceled", err)
+	}
+	if c.Err() != nil || conn.Len() != 0 || conn.closed {
+		t.Errorf("connection changed: err %v, %d bytes sent, closed %v", c.Err(), conn.Len(), conn.closed)
+	}
+
+	// Отмена между фрагментами: DR после первого фрагмента и закрытие сокета
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	conn = &recordingConn{onWrite: cancel}
+	c = NewConnection(conn)
+	c.SetTpduSize(128)
+	err := c.SendDataMessageContext(ctx, payload)
+	if !errors.Is(err, ErrBrokenConnection) || !errors.Is(err, context.Canceled) {
+		t.Errorf("error = %v, want ErrBrokenConnection and context.Canceled", err)
+	}
+	if !conn.closed || !errors.Is(c.Err(), ErrBrokenConnection) {
+		t.Errorf("connection not aborted: closed %v, err %v", conn.closed, c.Err())
+	}
+	peer := NewConnection(&pipeConn{Reader: &conn.Buffer, Writer: io.Discard}, WithLocalRef(0xffff))
+	for _, want := range []Indication{IndicationMoreFragmentsFollow, IndicationDisconnect} {
+		if indication, err := receive(context.Background(), peer); err != nil || indication != want {
+			t.Errorf("peer indication %v, error %v, want %v", indication, err, want)
+		}
+	}
+
+	// Все последующие операции возвращают ErrBrokenConnection
+	if err := c.SendDataMessage(payload); !errors.Is(err, ErrBrokenConnection) {
+		t.Errorf("SendDataMessage error = %v, want ErrBrokenConnection", err)
+	}
+	if _, err := c.ReadToTpktBuffer(context.Background()); !errors.Is(err, ErrBrokenConnection) {
+		t.Errorf("ReadToTpktBuffer error = %v, want ErrBrokenConnection", err)
+	}
+}
+
+func TestSendDataMessageContextInterrupt(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	client, server := net.Pipe()
+	defer server.Close()
+
+	// Партнёр принимает первый фрагмент и перестаёт читать: отмена прерывает запись
+	sendCtx, cancelSend := context.WithCancel(ctx)
+	go func() {
+		receive(ctx, NewConnection(server))
+		cancelSend()
+	}()
+	c := NewConnection(client)
+	c.SetTpduSize(128)
+	err := c.SendDataMessageContext(sendCtx, bytes.Repeat([]byte{0xa5}, 1000))
+	if !errors.Is(err, ErrBrokenConnection) || !errors.Is(err, context.Canceled) {
+		t.Errorf("error = %v, want ErrBrokenConnection and context.Canceled", err)
+	}
+	if _, err := server.Read(make([]byte, 1)); err != io.EOF {
+		t.Errorf("peer read error = %v, want io.EOF after abort", err)
+	}
+}
@@ -0,0 +1,121 @@
+package mms
+
+import (
+	"fmt"
+
+	"github.com/slonegd/go61850/osi/acse"
+	"github.com/slonegd/go61850/osi/cotp"
+	"github.com/slonegd/go61850/osi/presentation"
+	"github.com/slonegd/go61850/osi/session"
+)
+
+// AssociateRequest - запрос ассоциации на всех уровнях стека: MMS initiate-RequestPDU,
+// вложенный в ACSE AARQ, Presentation CP-type и Session CONNECT SPDU.
+// Session передаётся через COTP; промежуточные уровни сохраняются для
+// журналирования и RawPDUTap.
+type AssociateRequest struct {
+	MMS          []byte
+	ACSE         []byte
+	Presentation []byte
+	Session      []byte
+}
+
+// NewAssociateRequest оборачивает MMS initiate-RequestPDU (например,
+// NewInitiateRequest().Bytes()) в PDU верхних уровней с параметрами по умолчанию
+func NewAssociateRequest(mmsPdu []byte) *AssociateRequest {
+	r := &AssociateRequest{MMS: mmsPdu}
+	r.ACSE = acse.BuildAARQ(r.MMS)
+	r.Presentation = presentation.BuildCPType(r.ACSE)
+	r.Session = session.BuildConnectSPDU(r.Presentation)
+	return r
+}
+
+// Packet возвращает запрос в том виде, в каком он передаётся по TCP:
+// TPKT и COTP Data TPDU (последний блок) с Session SPDU.
+// Для NewInitiateRequest() совпадает побайтно с пакетом libIEC61850 по умолчанию,
+// поэтому годится как эталон для проверки других стеков.
+func (r *AssociateRequest) Packet() []byte {
+	length := 4 + 3 + len(r.Session)
+	packet := make([]byte, 0, length)
+	packet = append(packet, 0x03, 0x00, byte(length>>8), byte(length))
+	packet = append(packet, 0x02, byte(cotp.COTPTypeData), 0x80)
+	return append(packet, r.Session...)
+}
+
+// AssociateResponse - ответ на запрос ассоциации на всех уровнях стека: MMS
+// initiate-ResponsePDU, вложенный в ACSE AARE, Presentation CPA-PPDU и Session ACCEPT SPDU
+type AssociateResponse struct {
+	MMS          []byte
+	ACSE         []byte
+	Presentation []byte
+	Session      []byte
+}
+
+// NewAssociateResponse оборачивает MMS initiate-ResponsePDU (например,
+// InitiateResponse.Bytes()) в PDU верхних уровней, принимая ассоциацию
+func NewAssociateResponse(mmsPdu []byte) *AssociateResponse {
+	r := &AssociateResponse{MMS: mmsPdu}
+	// indirect-reference - контекст MMS (3), как в AARQ клиента
+	r.ACSE = acse.CreateAssociateResponseMessage(&acse.Connection{NextReference: 3}, acse.ResultAccept, r.MMS)
+	r.Presentation = presentation.BuildCPAType(r.ACSE)
+	r.Session = session.BuildAcceptSPDU(r.Presentation)
+	return r
+}
+
+// Packet возвращает ответ в том виде, в каком он передаётся по TCP:
+// TPKT и COTP Data TPDU (последний блок) с Session SPDU
+func (r *AssociateResponse) Packet() []byte {
+	return (&AssociateRequest{Session: r.Session}).Packet()
+}
+
+// ParseAssociateRequest разбирает захваченный запрос ассоциации в InitiateRequest,
+// чтобы сравнить параметры другого клиента с типизированной формой.
+// packet может начинаться с любого уровня: TPKT (как из wireshark),
+// Session CONNECT, Presentation CP-type, ACSE AARQ или MMS initiate-RequestPDU.
+func ParseAssociateRequest(packet []byte) (*InitiateRequest, error) {
+	if len(packet) == 0 {
+		return nil, fmt.Errorf("empty associate request")
+	}
+	data := packet
+
+	if data[0] == 0x03 {
+		tpkt, err := cotp.ParseTPKT(data)
+		if err != nil {
+			return nil, err
+		}
+		tpdu, err := cotp.ParseCOTP(tpkt.Data)
+		if err != nil {
+			return nil, err
+		}
+		if tpdu.Type != cotp.COTPTypeData {
+			return nil, fmt.Errorf("expected COTP Data TPDU, got type 0x%02x", byte(tpdu.Type))
+		}
+		data = tpdu.Data
+	}
+
+	if len(data) > 0 && data[0] == byte(session.SessionSPDUTypeConnect) {
+		spdu, err := session.ParseSessionSPDU(data)
+		if err != nil {
+			return nil, err
+		}
+		data = spdu.Data
+	}
+
+	if len(data) > 0 && data[0] == byte(presentation.CP) {
+		ppdu, err := presentation.ParsePresentationPDU(data)
+		if err != nil {
+			return nil, err
+		}
+		data = ppdu.Data
+	}
+
+	if len(data) > 0 && data[0] == byte(acse.AARQ) {
+		apdu, err := acse.ParseACSEPDU(data)
+		if err != nil {
+			return nil, err
+		}
+		data = apdu.Data
+	}
+
+	return ParseInitiateRequest(data)
+}
@@ -0,0 +1,82 @@
+package mms
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// libiecInitiatePacket - MMS Initiate Request libIEC61850 с параметрами по умолчанию
+// (тот же пакет, что в poc/poc_test.go)
+const libiecInitiatePacket = `
+03 00 00 bb 02 f0 80 0d b2 05 06 13 01 00 16 01 02 14 02 00 02 33 02 00 01 34 02 00 01 c1 9c 31 81 99
+a0 03 80 01 01 a2 81 91 81 04 00 00 00 01 82 04 00 00 00 01 a4 23 30 0f 02 01 01 06 04 52 01 00 01 30
+04 06 02 51 01 30 10 02 01 03 06 05 28 ca 22 02 01 30 04 06 02 51 01 61 5e 30 5c 02 01 01 a0 57 60 55
+a1 07 06 05 28 ca 22 02 03 a2 07 06 05 29 01 87 67 01 a3 03 02 01 0c a6 06 06 04 29 01 87 67 a7 03 02
+01 0c be 2f 28 2d 02 01 03 a0 28 a8 26 80 03 00 fd e8 81 01 05 82 01 05 83 01 0a a4 16 80 01 01 81 03
+05 f1 00 82 0c 03 ee 1c 00 00 04 08 00 00 79 ef 18`
+
+func TestAssociateRequestPacket(t *testing.T) {
+	expected, err := hex.DecodeString(strings.Join(strings.Fields(libiecInitiatePacket), ""))
+	assert.NoError(t, err)
+
+	r := NewAssociateRequest(NewInitiateRequest().Bytes())
+	assert.Equal(t, expected, r.Packet())
+}
+
+func TestParseAssociateRequest(t *testing.T) {
+	original := NewInitiateRequest(
+		WithLocalDetailCalling(1024),
+		WithProposedMaxServOutstandingCalling(1),
+		WithProposedParameterCBB([]ParameterCBBBit{Str1, Vnam}),
+		WithServicesSupportedCalling([]ServiceSupportedBit{Read, Write, InformationReport}),
+	)
+	r := NewAssociateRequest(original.Bytes())
+
+	for name, packet := range map[string][]byte{
+		"TPKT":         r.Packet(),
+		"Session":      r.Session,
+		"Presentation": r.Presentation,
+		"ACSE":         r.ACSE,
+		"MMS":          r.MMS,
+	} {
+		t.Run(name, func(t *testing.T) {
+			parsed, err := ParseAssociateRequest(packet)
+			assert.NoError(t, err)
+			assert.Equal(t, original, parsed)
+		})
+	}
+
+	// Захваченный пакет libIEC61850 разбирается в параметры по умолчанию
+	captured, err := hex.DecodeString(strings.Join(strings.Fields(libiecInitiatePacket), ""))
+	assert.NoError(t, err)
+	parsed, err := ParseAssociateRequest(captured)
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultInitiateRequestParams(), parsed)
+
+	_, err = ParseAssociateRequest(nil)
+	assert.Error(t, err)
+	_, err = ParseAssociateRequest([]byte{0xa9, 0x00})
+	assert.Error(t, err)
+}
+
+// libiecAcceptPacket - ответ сервера libIEC61850 на libiecInitiatePacket
+// (Session ACCEPT, Presentation CPA, ACSE AARE, MMS Initiate Response)
+const libiecAcceptPacket = `
+03 00 00 8f 02 f0 80 0e 86 05 06 13 01 00 16 01 02 14 02 00 02 34 02 00 01 c1 74 31 72 a0 03 80 01 01
+a2 6b 83 04 00 00 00 01 a5 12 30 07 80 01 00 81 02 51 01 30 07 80 01 00 81 02 51 01 61 4f 30 4d 02 01
+01 a0 48 61 46 a1 07 06 05 28 ca 22 02 03 a2 03 02 01 00 a3 05 a1 03 02 01 00 be 2f 28 2d 02 01 03 a0
+28 a9 26 80 03 00 fd e8 81 01 05 82 01 05 83 01 0a a4 16 80 01 01 81 03 05 f1 00 82 0c 03 ee 1c 00 00
+00 02 00 00 40 ed 18`
+
+func TestAssociateResponsePacket(t *testing.T) {
+	expected, err := hex.DecodeString(strings.Join(strings.Fields(libiecAcceptPacket), ""))
+	assert.NoError(t, err)
+
+	initiate, err := ParseInitiateResponse(expected[len(expected)-0x28:])
+	assert.NoError(t, err)
+	r := NewAssociateResponse(initiate.Bytes())
+	assert.Equal(t, expected, r.Packet())
+}
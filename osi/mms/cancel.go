@@ -0,0 +1,174 @@
+package mms
+
+import (
+	"fmt"
+
+	"github.com/slonegd/go61850/internal/ber"
+)
+
+// CancelRequest представляет MMS cancel-RequestPDU: запрос отмены
+// выполняющегося confirmed-запроса с указанным invokeID
+//
+//	cancel-RequestPDU [5] IMPLICIT Unsigned32 -- originalInvokeID
+type CancelRequest struct {
+	InvokeID uint32
+}
+
+// Bytes кодирует cancel-RequestPDU: 85 (cancel-RequestPDU) invokeID
+func (r *CancelRequest) Bytes() []byte {
+	return encodeUnsigned(ber.ContextSpecific5Primitive, r.InvokeID)
+}
+
+// ParseCancelRequest парсит MMS cancel-RequestPDU
+func ParseCancelRequest(buffer []byte) (*CancelRequest, error) {
+	invokeID, err := parseCancelInvokeID(buffer, byte(ber.ContextSpecific5Primitive), "cancel-RequestPDU")
+	if err != nil {
+		return nil, err
+	}
+	return &CancelRequest{InvokeID: invokeID}, nil
+}
+
+// CancelResponse представляет MMS cancel-ResponsePDU: запрос успешно отменён
+//
+//	cancel-ResponsePDU [6] IMPLICIT Unsigned32 -- originalInvokeID
+type CancelResponse struct {
+	InvokeID uint32
+}
+
+// Bytes кодирует cancel-ResponsePDU: 86 (cancel-ResponsePDU) invokeID
+func (r *CancelResponse) Bytes() []byte {
+	return encodeUnsigned(ber.ContextSpecific6Primitive, r.InvokeID)
+}
+
+// CancelError представляет MMS cancel-ErrorPDU: отмена невозможна
+// (например, ответ уже отправлен или сервис не допускает отмены)
+//
+//	cancel-ErrorPDU [7] IMPLICIT SEQUENCE {
+//	  originalInvokeID [0] IMPLICIT Unsigned32,
+//	  serviceError [1] IMPLICIT ServiceError
+//	}
+type CancelError struct {
+	InvokeID     uint32
+	ServiceError ServiceError
+}
+
+func (e *CancelError) Error() string {
+	return fmt.Sprintf("cancel of invokeID %d failed: %v", e.InvokeID, &e.ServiceError)
+}
+
+// Bytes кодирует cancel-ErrorPDU: a7 (cancel-ErrorPDU) { 80 invokeID, a1 ServiceError }
+func (e *CancelError) Bytes() []byte {
+	content := encodeUnsigned(ber.ContextSpecific0Primitive, e.InvokeID)
+	content = append(content, wrapTL(ber.ContextSpecific1Constructed, e.ServiceError.content())...)
+	return wrapTL(ber.ContextSpecific7Constructed, content)
+}
+
+// IsCancelResult проверяет, является ли MMS PDU ответом на cancel-RequestPDU
+// (cancel-ResponsePDU или cancel-ErrorPDU)
+func IsCancelResult(buffer []byte) bool {
+	return len(buffer) > 0 &&
+		(buffer[0] == byte(ber.ContextSpecific6Primitive) || buffer[0] == byte(ber.ContextSpecific7Constructed))
+}
+
+// ParseCancelResult парсит ответ на cancel-RequestPDU и возвращает invokeID отменяемого запроса.
+// Для cancel-ErrorPDU возвращается также ошибка *CancelError.
+func ParseCancelResult(buffer []byte) (uint32, error) {
+	if len(buffer) > 0 && buffer[0] == byte(ber.ContextSpecific6Primitive) {
+		return parseCancelInvokeID(buffer, byte(ber.ContextSpecific6Primitive), "cancel-ResponsePDU")
+	}
+
+	content, err := expectTLV(buffer, byte(ber.ContextSpecific7Constructed), "cancel-ErrorPDU")
+	if err != nil {
+		return 0, err
+	}
+	cancelError := &CancelError{}
+	var hasServiceError bool
+	for bufPos := 0; bufPos < len(content); {
+		tag, value, next, err := decodeTLV(content, bufPos, len(content))
+		if err != nil {
+			return 0, err
+		}
+		switch tag {
+		case byte(ber.ContextSpecific0Primitive):
+			if len(value) < 1 || len(value) > 5 {
+				return 0, fmt.Errorf("invalid originalInvokeID length: %d", len(value))
+			}
+			cancelError.InvokeID = ber.DecodeUint32(value, len(value), 0)
+		case byte(ber.ContextSpecific1Constructed):
+			serviceError, err := parseServiceError(value)
+			if err != nil {
+				return 0, fmt.Errorf("failed to parse cancel-ErrorPDU: %w", err)
+			}
+			cancelError.ServiceError = *serviceError
+			hasServiceError = true
+		default:
+			return 0, fmt.Errorf("unexpected tag in cancel-ErrorPDU: 0x%02x", tag)
+		}
+		bufPos = next
+	}
+	if !hasServiceError {
+		return 0, fmt.Errorf("cancel-ErrorPDU does not contain serviceError")
+	}
+	return cancelError.InvokeID, cancelError
+}
+
+// CancelRejectInvokeID проверяет, является ли MMS PDU отказом (RejectPDU)
+// на cancel-RequestPDU: rejectReason cancel-requestPDU [6] или pdu-error [5],
+// которым отвечают серверы, не знающие cancel. Возвращает originalInvokeID,
+// 0 - если сервер его не указал.
+func CancelRejectInvokeID(buffer []byte) (uint32, bool) {
+	content, err := expectTLV(buffer, byte(ber.ContextSpecific4Constructed), "rejectPDU")
+	if err != nil {
+		return 0, false
+	}
+	var invokeID uint32
+	var cancelReject bool
+	for bufPos := 0; bufPos < len(content); {
+		tag, value, next, err := decodeTLV(content, bufPos, len(content))
+		if err != nil || len(value) < 1 || len(value) > 5 {
+			return 0, false
+		}
+		switch tag {
+		case byte(ber.ContextSpecific0Primitive): // originalInvokeID
+			invokeID = ber.DecodeUint32(value, len(value), 0)
+		case byte(ber.ContextSpecific5Primitive), byte(ber.ContextSpecific6Primitive): // pdu-error, cancel-requestPDU
+			cancelReject = true
+		}
+		bufPos = next
+	}
+	return invokeID, cancelReject
+}
+
+// ResponseInvokeID возвращает invokeID confirmed-ResponsePDU или confirmed-ErrorPDU.
+// Для остальных PDU возвращает false.
+func ResponseInvokeID(buffer []byte) (uint32, bool) {
+	if len(buffer) == 0 ||
+		(buffer[0] != byte(ber.ContextSpecific1Constructed) && buffer[0] != byte(ber.ContextSpecific2Constructed)) {
+		return 0, false
+	}
+	_, content, _, err := decodeTLV(buffer, 0, len(buffer))
+	if err != nil {
+		return 0, false
+	}
+	// invokeID - первый элемент; в confirmed-ErrorPDU он помечен [0]
+	tag, value, _, err := decodeTLV(content, 0, len(content))
+	if err != nil || (tag != byte(ber.Integer) && tag != byte(ber.ContextSpecific0Primitive)) {
+		return 0, false
+	}
+	if len(value) < 1 || len(value) > 5 {
+		return 0, false
+	}
+	return ber.DecodeUint32(value, len(value), 0), true
+}
+
+// parseCancelInvokeID разбирает PDU вида [n] IMPLICIT Unsigned32
+func parseCancelInvokeID(buffer []byte, tag byte, what string) (uint32, error) {
+	content, err := expectTLV(buffer, tag, what)
+	if err != nil {
+		return 0, err
+	}
+	if len(content) < 1 || len(content) > 5 {
+		return 0, fmt.Errorf("invalid invokeID length in %s: %d", what, len(content))
+	}
+	return ber.DecodeUint32(content, len(content), 0), nil
+}
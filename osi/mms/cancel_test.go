@@ -0,0 +1,74 @@
+package mms
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCancelRequest(t *testing.T) {
+	request := &CancelRequest{InvokeID: 300}
+	assert.Equal(t, parseHexString("85 02 012c"), request.Bytes())
+
+	parsed, err := ParseCancelRequest(request.Bytes())
+	assert.NoError(t, err)
+	assert.Equal(t, request, parsed)
+}
+
+func TestParseCancelResult(t *testing.T) {
+	response := (&CancelResponse{InvokeID: 7}).Bytes()
+	assert.Equal(t, parseHexString("86 01 07"), response)
+	assert.True(t, IsCancelResult(response))
+	invokeID, err := ParseCancelResult(response)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(7), invokeID)
+
+	expected := &CancelError{
+		InvokeID:     7,
+		ServiceError: ServiceError{Class: ErrorClassCancel, Code: CancelErrorCancelNotPossible},
+	}
+	buffer := parseHexString("a7 0a 800107 a105 a003 8a0102")
+	assert.Equal(t, buffer, expected.Bytes())
+	assert.True(t, IsCancelResult(buffer))
+	invokeID, err = ParseCancelResult(buffer)
+	assert.Equal(t, uint32(7), invokeID)
+	var cancelError *CancelError
+	assert.True(t, errors.As(err, &cancelError))
+	assert.Equal(t, expected, cancelError)
+	assert.EqualError(t, err, "cancel of invokeID 7 failed: MMS service error: class cancel, code cancel-not-possible")
+
+	_, err = ParseCancelResult(parseHexString("a7 03 800107"))
+	assert.EqualError(t, err, "cancel-ErrorPDU does not contain serviceError")
+	assert.False(t, IsCancelResult(parseHexString("85 01 07")))
+}
+
+func TestResponseInvokeID(t *testing.T) {
+	invokeID, ok := ResponseInvokeID(parseHexString("a10a 020105 a505 8100 800103"))
+	assert.True(t, ok)
+	assert.Equal(t, uint32(5), invokeID)
+
+	// confirmed-ErrorPDU: invokeID помечен [0]
+	invokeID, ok = ResponseInvokeID(parseHexString("a20a 800106 a205 a003 850101"))
+	assert.True(t, ok)
+	assert.Equal(t, uint32(6), invokeID)
+
+	_, ok = ResponseInvokeID(parseHexString("860107"))
+	assert.False(t, ok)
+}
+
+func TestCancelRejectInvokeID(t *testing.T) {
+	// rejectReason cancel-requestPDU [6] invalid-invokeID
+	invokeID, ok := CancelRejectInvokeID(parseHexString("a406 800107 860101"))
+	assert.True(t, ok)
+	assert.Equal(t, uint32(7), invokeID)
+
+	// pdu-error [5] без originalInvokeID: сервер не знает cancel-RequestPDU
+	invokeID, ok = CancelRejectInvokeID(parseHexString("a403 850101"))
+	assert.True(t, ok)
+	assert.Zero(t, invokeID)
+
+	// Отказ на confirmed-RequestPDU не относится к отмене
+	_, ok = CancelRejectInvokeID((&Reject{InvokeID: 7, Reason: 1}).Bytes())
+	assert.False(t, ok)
+}
@@ -0,0 +1,147 @@
+package mms
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+)
+
+// ErrServiceNotSupported возвращается для запросов услуги, которую сервер
+// не заявил в servicesSupportedCalled своего initiate-ResponsePDU
+var ErrServiceNotSupported = errors.New("service not supported by server")
+
+// Capabilities - действующие параметры ассоциации после Initiate.
+// Пределы - меньшее из предложенного клиентом и согласованного сервером,
+// параметры CBB и услуги - общие для обеих сторон. Если сервер вернул
+// меньше, чем предлагал клиент, клиент подстраивается под ответ:
+// запросы больше MaxPduSize и запросы незаявленных услуг не отправляются.
+type Capabilities struct {
+	// MaxPduSize - максимальный размер MMS PDU в байтах
+	MaxPduSize uint32
+	// MaxServOutstandingCalling - одновременные запросы клиента к серверу.
+	// Клиент выполняет confirmed-запросы по одному, поэтому значение 1
+	// поведения не меняет.
+	MaxServOutstandingCalling uint32
+	// MaxServOutstandingCalled - одновременные запросы сервера к клиенту
+	MaxServOutstandingCalled uint32
+	// DataStructureNestingLevel - максимальная вложенность структур данных; 0 - не ограничена
+	DataStructureNestingLevel uint32
+	// ParameterCBB - параметры, поддерживаемые обеими сторонами
+	ParameterCBB []ParameterCBBBit
+	// Services - услуги, заявленные сервером; nil - сервер не передал список
+	Services []ServiceSupportedBit
+	// Downgrades - описания параметров, уменьшенных сервером относительно запроса
+	Downgrades []string
+}
+
+// NegotiateCapabilities вычисляет действующие параметры ассоциации
+// из отправленного InitiateRequest и полученного InitiateResponse
+func NegotiateCapabilities(request *InitiateRequest, response *InitiateResponse) *Capabilities {
+	c := &Capabilities{
+		MaxPduSize:                request.LocalDetailCalling,
+		MaxServOutstandingCalling: request.ProposedMaxServOutstandingCalling,
+		MaxServOutstandingCalled:  request.ProposedMaxServOutstandingCalled,
+		DataStructureNestingLevel: request.ProposedDataStructureNestingLevel,
+	}
+	c.clamp("max PDU size", &c.MaxPduSize, response.LocalDetailCalled)
+	c.clamp("max serv outstanding calling", &c.MaxServOutstandingCalling, &response.NegotiatedMaxServOutstandingCalling)
+	c.clamp("max serv outstanding called", &c.MaxServOutstandingCalled, &response.NegotiatedMaxServOutstandingCalled)
+	c.clamp("data structure nesting level", &c.DataStructureNestingLevel, response.NegotiatedDataStructureNestingLevel)
+
+	for _, bit := range request.ProposedParameterCBB {
+		if slices.Contains(response.NegotiatedParameterCBB, bit) {
+			c.ParameterCBB = append(c.ParameterCBB, bit)
+		} else {
+			c.Downgrades = append(c.Downgrades, fmt.Sprintf("parameter %s not negotiated", bit))
+		}
+	}
+	if len(response.ServicesSupportedCalled) > 0 {
+		c.Services = response.ServicesSupportedCalled
+	}
+	return c
+}
+
+// NegotiateInitiateResponse формирует ответ сервера на InitiateRequest: пределы -
+// меньшее из предложенного клиентом и limits, параметры CBB - общие для обеих
+// сторон, услуги - limits.ServicesSupportedCalled. Клиент, вычислив по ответу
+// NegotiateCapabilities, получит те же пределы без Downgrades сверх уменьшенных сервером.
+func NegotiateInitiateResponse(request *InitiateRequest, limits *InitiateResponse) *InitiateResponse {
+	c := NegotiateCapabilities(request, limits)
+	return &InitiateResponse{
+		LocalDetailCalled:                   negotiatedLimit(c.MaxPduSize, limits.LocalDetailCalled),
+		NegotiatedMaxServOutstandingCalling: *negotiatedLimit(c.MaxServOutstandingCalling, &limits.NegotiatedMaxServOutstandingCalling),
+		NegotiatedMaxServOutstandingCalled:  *negotiatedLimit(c.MaxServOutstandingCalled, &limits.NegotiatedMaxServOutstandingCalled),
+		NegotiatedDataStructureNestingLevel: negotiatedLimit(c.DataStructureNestingLevel, limits.NegotiatedDataStructureNestingLevel),
+		NegotiatedVersionNumber:             SupportedVersionNumber,
+		NegotiatedParameterCBB:              c.ParameterCBB,
+		ServicesSupportedCalled:             limits.ServicesSupportedCalled,
+	}
+}
+
+// negotiatedLimit возвращает согласованный предел value; если клиент
+// не предложил значение (0), действует предел сервера limit
+func negotiatedLimit(value uint32, limit *uint32) *uint32 {
+	if value == 0 && limit != nil {
+		value = *limit
+	}
+	return &value
+}
+
+// clamp уменьшает value до negotiated (0 и nil - сервер не ограничил)
+// и запоминает уменьшение в Downgrades
+func (c *Capabilities) clamp(name string, value *uint32, negotiated *uint32) {
+	if negotiated == nil || *negotiated == 0 || *negotiated >= *value {
+		return
+	}
+	c.Downgrades = append(c.Downgrades, fmt.Sprintf("%s %d -> %d", name, *value, *negotiated))
+	*value = *negotiated
+}
+
+// SupportsService возвращает true, если сервер заявил услугу service
+// или не передал список услуг
+func (c *Capabilities) SupportsService(service ServiceSupportedBit) bool {
+	return c.Services == nil || slices.Contains(c.Services, service)
+}
+
+// SupportsParameter возвращает true, если параметр согласован обеими сторонами
+func (c *Capabilities) SupportsParameter(parameter ParameterCBBBit) bool {
+	return slices.Contains(c.ParameterCBB, parameter)
+}
+
+// CheckService возвращает ErrServiceNotSupported, если сервер не заявил услугу service
+func (c *Capabilities) CheckService(service ServiceSupportedBit) error {
+	if c.SupportsService(service) {
+		return nil
+	}
+	return fmt.Errorf("%s: %w", service, ErrServiceNotSupported)
+}
+
+// String возвращает действующие параметры одной строкой в формате key=value;
+// Services - число заявленных услуг или any, если сервер не передал список
+func (c *Capabilities) String() string {
+	return c.fields().compact()
+}
+
+// Format реализует fmt.Formatter: %+v выводит поле на строку
+func (c *Capabilities) Format(s fmt.State, verb rune) {
+	c.fields().format(s, verb)
+}
+
+func (c *Capabilities) fields() *fields {
+	f := newFields("Capabilities")
+	f.addUint("MaxPduSize", c.MaxPduSize)
+	f.addUint("MaxServOutstandingCalling", c.MaxServOutstandingCalling)
+	f.addUint("MaxServOutstandingCalled", c.MaxServOutstandingCalled)
+	f.addUint("DataStructureNestingLevel", c.DataStructureNestingLevel)
+	addList(f, "ParameterCBB", c.ParameterCBB)
+	if c.Services != nil {
+		f.add("Services", strconv.Itoa(len(c.Services)))
+	} else {
+		f.add("Services", "any")
+	}
+	for i, downgrade := range c.Downgrades {
+		f.add(fmt.Sprintf("Downgrades[%d]", i), downgrade)
+	}
+	return f
+}
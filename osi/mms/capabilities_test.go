@@ -0,0 +1,75 @@
+package mms
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegotiateCapabilities(t *testing.T) {
+	request := DefaultInitiateRequestParams()
+	pduSize, nesting := uint32(8000), uint32(4)
+	response := &InitiateResponse{
+		LocalDetailCalled:                   &pduSize,
+		NegotiatedMaxServOutstandingCalling: 1,
+		NegotiatedMaxServOutstandingCalled:  5,
+		NegotiatedDataStructureNestingLevel: &nesting,
+		NegotiatedParameterCBB:              []ParameterCBBBit{Str1, Str2, Vnam, Vlis},
+		ServicesSupportedCalled:             []ServiceSupportedBit{GetNameList, Read, Write},
+	}
+
+	c := NegotiateCapabilities(request, response)
+	assert.Equal(t, uint32(8000), c.MaxPduSize)
+	assert.Equal(t, uint32(1), c.MaxServOutstandingCalling)
+	assert.Equal(t, uint32(5), c.MaxServOutstandingCalled)
+	assert.Equal(t, uint32(4), c.DataStructureNestingLevel)
+	assert.Equal(t, []ParameterCBBBit{Str1, Str2, Vnam, Vlis}, c.ParameterCBB)
+	assert.Equal(t, []string{
+		"max PDU size 65000 -> 8000",
+		"max serv outstanding calling 5 -> 1",
+		"data structure nesting level 10 -> 4",
+		"parameter Valt not negotiated",
+	}, c.Downgrades)
+
+	assert.True(t, c.SupportsService(Read))
+	assert.False(t, c.SupportsParameter(Valt))
+	assert.NoError(t, c.CheckService(Write))
+	assert.ErrorIs(t, c.CheckService(GetDomainAttributes), ErrServiceNotSupported)
+	assert.EqualError(t, c.CheckService(GetDomainAttributes), "GetDomainAttributes: service not supported by server")
+
+	// Без списка услуг сервера ограничений нет
+	c = NegotiateCapabilities(request, &InitiateResponse{})
+	assert.Equal(t, uint32(65000), c.MaxPduSize)
+	assert.True(t, c.SupportsService(GetDomainAttributes))
+	assert.Empty(t, c.ParameterCBB)
+}
+
+func TestNegotiateInitiateResponse(t *testing.T) {
+	pduSize, nesting := uint32(8000), uint32(4)
+	limits := &InitiateResponse{
+		LocalDetailCalled:                   &pduSize,
+		NegotiatedMaxServOutstandingCalling: 1,
+		NegotiatedMaxServOutstandingCalled:  10,
+		NegotiatedDataStructureNestingLevel: &nesting,
+		NegotiatedParameterCBB:              []ParameterCBBBit{Str1, Str2, Vnam, Vlis, Vadr},
+		ServicesSupportedCalled:             []ServiceSupportedBit{GetNameList, Read, Write},
+	}
+	request := NewInitiateRequest(WithLocalDetailCalling(1024))
+
+	response := NegotiateInitiateResponse(request, limits)
+	assert.Equal(t, uint32(1024), *response.LocalDetailCalled)
+	assert.Equal(t, uint32(1), response.NegotiatedMaxServOutstandingCalling)
+	assert.Equal(t, uint32(5), response.NegotiatedMaxServOutstandingCalled)
+	assert.Equal(t, uint32(4), *response.NegotiatedDataStructureNestingLevel)
+	assert.Equal(t, uint32(SupportedVersionNumber), response.NegotiatedVersionNumber)
+	assert.Equal(t, []ParameterCBBBit{Str1, Str2, Vnam, Vlis}, response.NegotiatedParameterCBB)
+	assert.Equal(t, limits.ServicesSupportedCalled, response.ServicesSupportedCalled)
+
+	// Ответ переживает кодирование, и клиент приходит к тем же пределам
+	parsed, err := ParseInitiateResponse(response.Bytes())
+	assert.NoError(t, err)
+	assert.Equal(t, response, parsed)
+	c := NegotiateCapabilities(request, parsed)
+	assert.Equal(t, uint32(1024), c.MaxPduSize)
+	assert.Equal(t, uint32(4), c.DataStructureNestingLevel)
+}
@@ -2,8 +2,11 @@ package mms
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
+	"github.com/slonegd/go61850/internal/ber"
 	"github.com/slonegd/go61850/logger"
 	"github.com/slonegd/go61850/osi/acse"
 	"github.com/slonegd/go61850/osi/cotp"
@@ -11,36 +14,173 @@ import (
 	"github.com/slonegd/go61850/osi/session"
 )
 
+// ErrPduTooLarge возвращается, если MMS PDU превышает согласованный при Initiate
+// максимальный размер (localDetail). Такой запрос сервер отклонил бы разрывом ассоциации.
+var ErrPduTooLarge = errors.New("MMS PDU exceeds negotiated maximum size")
+
+// ErrIndefiniteLength возвращается для MMS PDU с неопределённой формой длины BER,
+// если клиент создан с WithDefiniteLengthOnly
+var ErrIndefiniteLength = ber.ErrIndefiniteLength
+
+// ErrNotDER возвращается для отправляемого MMS PDU с кодированием, отличным от DER,
+// если клиент создан с WithDEREncoding
+var ErrNotDER = ber.ErrNotDER
+
 // Client представляет клиент для работы с MMS протоколом на уровне OSI стека.
 // Инкапсулирует логику отправки и получения MMS PDU через стеки протоколов
 // (Presentation -> Session -> COTP и обратно).
 type Client struct {
-	cotpConn *cotp.Connection
-	logger   logger.Logger
+	cotpConn  *cotp.Connection
+	logger    logger.Logger
+	invokeIDs *InvokeIDAllocator
+	// maxPduSize - согласованный максимальный размер MMS PDU; 0 - без ограничения
+	maxPduSize uint32
+	// definiteLengthOnly - отклонять MMS PDU с неопределённой длиной BER
+	definiteLengthOnly bool
+	// der - проверять отправляемые MMS PDU на соответствие DER
+	der bool
+	// association - идентификация отвечающей стороны из AARE
+	association *acse.AssociationInfo
+	// tap - получатель PDU всех уровней (см. tap.go)
+	tap RawPDUTap
+}
+
+// ClientOption представляет опцию для настройки Client
+type ClientOption func(*Client)
+
+// WithInvokeIDAllocator устанавливает аллокатор invokeID (например, с фиксированным seed для тестов)
+func WithInvokeIDAllocator(a *InvokeIDAllocator) ClientOption {
+	return func(c *Client) {
+		c.invokeIDs = a
+	}
+}
+
+// WithDefiniteLengthOnly включает строгий разбор: принятые MMS PDU с неопределённой
+// формой длины BER (0x80) отклоняются с ErrIndefiniteLength, как в DER.
+// Уровни ACSE и Presentation по-прежнему допускают неопределённую длину.
+func WithDefiniteLengthOnly() ClientOption {
+	return func(c *Client) {
+		c.definiteLengthOnly = true
+	}
+}
+
+// WithDEREncoding включает проверку отправляемых MMS PDU на кодирование DER:
+// минимальные определённые длины, минимальные INTEGER и BOOLEAN TRUE = 0xFF.
+// Кодировщики пакета формируют такие PDU всегда; опция гарантирует это для
+// тестеров соответствия, отклоняя PDU с ErrNotDER до отправки.
+func WithDEREncoding() ClientOption {
+	return func(c *Client) {
+		c.der = true
+	}
 }
 
 // NewClient создаёт новый MMS клиент с указанными параметрами.
-func NewClient(cotpConn *cotp.Connection, logger logger.Logger) *Client {
-	return &Client{
-		cotpConn: cotpConn,
-		logger:   logger,
+func NewClient(cotpConn *cotp.Connection, logger logger.Logger, opts ...ClientOption) *Client {
+	c := &Client{
+		cotpConn:  cotpConn,
+		logger:    logger,
+		invokeIDs: NewInvokeIDAllocator(),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// AllocateInvokeID резервирует invokeID для нового confirmed-запроса.
+// После получения ответа invokeID нужно освободить через ReleaseInvokeID.
+func (c *Client) AllocateInvokeID() (uint32, error) {
+	return c.invokeIDs.Allocate()
+}
+
+// ReleaseInvokeID освобождает invokeID, зарезервированный AllocateInvokeID
+func (c *Client) ReleaseInvokeID(id uint32) {
+	c.invokeIDs.Release(id)
+}
+
+// upperLayerOverhead - запас payload COTP на заголовки Session, Presentation
+// и ACSE вокруг MMS PDU в фазе передачи данных
+const upperLayerOverhead = 64
+
+// SetMaxPduSize задаёт максимальный размер MMS PDU, согласованный при Initiate
+// (меньшее из localDetailCalling и localDetailCalled). 0 снимает ограничение.
+// Буферы COTP соединения увеличиваются так, чтобы вместить PDU этого размера.
+func (c *Client) SetMaxPduSize(size uint32) {
+	c.maxPduSize = size
+	if size > 0 && c.cotpConn != nil {
+		c.cotpConn.SizeBuffers(int(size) + upperLayerOverhead)
 	}
 }
 
+// MaxPduSize возвращает согласованный максимальный размер MMS PDU (0 - без ограничения)
+func (c *Client) MaxPduSize() uint32 {
+	return c.maxPduSize
+}
+
+// CheckPduSize проверяет, что MMS PDU не превышает согласованный размер.
+// Возвращает ошибку, оборачивающую ErrPduTooLarge.
+func (c *Client) CheckPduSize(mmsPdu []byte) error {
+	if c.maxPduSize > 0 && uint64(len(mmsPdu)) > uint64(c.maxPduSize) {
+		return fmt.Errorf("%w: %d bytes, maximum %d", ErrPduTooLarge, len(mmsPdu), c.maxPduSize)
+	}
+	return nil
+}
+
+// CheckEncoding проверяет кодирование отправляемого MMS PDU, если включена
+// опция WithDEREncoding. Возвращает ошибку, оборачивающую ErrNotDER.
+func (c *Client) CheckEncoding(mmsPdu []byte) error {
+	if !c.der {
+		return nil
+	}
+	if err := ber.CheckDER(mmsPdu); err != nil {
+		return fmt.Errorf("MMS PDU is not DER encoded: %w", err)
+	}
+	return nil
+}
+
 // SendMmsPdu отправляет MMS PDU через стеки протоколов (Presentation -> Session -> COTP).
 // Эта функция инкапсулирует общую логику отправки MMS PDU, которая используется
 // в функциях ReadObject и GetTypeSpecification.
+// PDU больше согласованного размера не отправляется (ErrPduTooLarge).
 func (c *Client) SendMmsPdu(mmsPdu []byte) error {
+	return c.SendMmsPduContext(context.Background(), mmsPdu)
+}
+
+// SendMmsPduContext отправляет MMS PDU как SendMmsPdu с отменой по ctx.
+// Отмена после отправки части фрагментов разрывает соединение: этот и все
+// последующие вызовы возвращают ошибку, оборачивающую cotp.ErrBrokenConnection.
+func (c *Client) SendMmsPduContext(ctx context.Context, mmsPdu []byte) error {
+	if err := c.CheckPduSize(mmsPdu); err != nil {
+		return err
+	}
+	if err := c.CheckEncoding(mmsPdu); err != nil {
+		return err
+	}
+
 	// Обёртываем в Presentation user-data
 	// contextID = 3 для MMS (mms-abstract-syntax-version1)
+	c.Tap(DirectionSend, LayerMMS, mmsPdu)
 	presentationPdu := presentation.BuildUserData(mmsPdu, 3)
+	c.Tap(DirectionSend, LayerPresentation, presentationPdu)
 
 	// Обёртываем в Session: Give tokens PDU + DT SPDU + Presentation PDU
 	// Это соответствует структуре из wireshark: 01 00 01 00 <Presentation PDU>
 	sessionPdu := session.BuildDataTransferWithTokens(presentationPdu)
+	c.Tap(DirectionSend, LayerSession, sessionPdu)
 
 	// Отправляем через COTP
-	return c.cotpConn.SendDataMessage(sessionPdu)
+	return c.cotpConn.SendDataMessageContext(ctx, sessionPdu)
+}
+
+// LastActivity возвращает время приёма последнего TPKT от сервера
+func (c *Client) LastActivity() time.Time {
+	return c.cotpConn.LastActivity()
+}
+
+// Association возвращает AP-title и AE-qualifier отвечающей стороны из AARE
+// установленной ассоциации; nil, если сервер их не передал
+func (c *Client) Association() *acse.AssociationInfo {
+	return c.association
 }
 
 // ExtractMmsDataFromPresentation извлекает MMS данные из уже распарсенной Presentation PDU.
@@ -50,15 +190,29 @@ func (c *Client) ExtractMmsDataFromPresentation(presentationPdu *presentation.Pr
 	// Определяем, что содержится в Presentation PDU
 	// После установления соединения данные могут идти напрямую как MMS PDU (contextId = 3)
 	// или через ACSE (contextId = 1)
-	if presentationPdu.PresentationContextId == 3 {
+	if presentationPdu.Data == nil {
+		return nil, presentation.ErrNoUserData
+	}
+	contextId := presentationPdu.PresentationContextId
+	if contextId == 0 {
+		// simply-encoded-data в контексте по умолчанию (default-context-name)
+		switch {
+		case presentationPdu.DefaultContextName.Equal(ber.OIDACSE):
+			contextId = 1
+		case presentationPdu.DefaultContextName.Equal(ber.OIDMMSAbstractSyntax):
+			contextId = 3
+		}
+	}
+	if contextId == 3 {
 		// MMS context - данные идут напрямую как MMS PDU
 		return presentationPdu.Data, nil
-	} else if presentationPdu.PresentationContextId == 1 {
+	} else if contextId == 1 {
 		// ACSE context - нужно парсить ACSE PDU
 		if len(presentationPdu.Data) == 0 {
 			return nil, fmt.Errorf("presentation PDU data is empty")
 		}
 
+		c.Tap(DirectionReceive, LayerACSE, presentationPdu.Data)
 		acsePdu, err := acse.ParseACSEPDU(presentationPdu.Data)
 		if err != nil {
 			return nil, fmt.Errorf("failed to parse ACSE PDU: %w", err)
@@ -71,6 +225,17 @@ func (c *Client) ExtractMmsDataFromPresentation(presentationPdu *presentation.Pr
 		if c.logger != nil {
 			c.logger.Debug("  %s", acsePdu)
 		}
+		// Разрыв ассоциации: источник и диагностика передаются приложению
+		if acsePdu.Abort != nil {
+			return nil, acsePdu.Abort
+		}
+		if acsePdu.Type == acse.AARE {
+			c.association = acsePdu.Association
+		}
+		// Отказ в освобождении ассоциации (RLRE с причиной not-finished и т.п.)
+		if acsePdu.Type == acse.RLRE && acsePdu.ReleaseReason > 0 {
+			return nil, &acse.ReleaseError{Reason: acse.ReleaseResponseReason(acsePdu.ReleaseReason)}
+		}
 
 		return acsePdu.Data, nil
 	} else {
@@ -128,6 +293,7 @@ func (c *Client) ReceiveAndParseMmsResponse(ctx context.Context) ([]byte, error)
 		}
 
 		// Парсим Session SPDU
+		c.Tap(DirectionReceive, LayerSession, payload)
 		sessionPdu, err := session.ParseSessionSPDU(payload)
 		if err != nil {
 			return nil, fmt.Errorf("failed to parse Session SPDU: %w", err)
@@ -146,6 +312,7 @@ func (c *Client) ReceiveAndParseMmsResponse(ctx context.Context) ([]byte, error)
 			return nil, fmt.Errorf("session SPDU data is empty")
 		}
 
+		c.Tap(DirectionReceive, LayerPresentation, sessionPdu.Data)
 		presentationPdu, err := presentation.ParsePresentationPDU(sessionPdu.Data)
 		if err != nil {
 			return nil, fmt.Errorf("failed to parse Presentation PDU: %w", err)
@@ -164,6 +331,12 @@ func (c *Client) ReceiveAndParseMmsResponse(ctx context.Context) ([]byte, error)
 		if err != nil {
 			return nil, err
 		}
+		c.Tap(DirectionReceive, LayerMMS, mmsData)
+		if c.definiteLengthOnly {
+			if err := ber.CheckDefiniteLength(mmsData); err != nil {
+				return nil, fmt.Errorf("invalid MMS PDU: %w", err)
+			}
+		}
 
 		return mmsData, nil
 	}
@@ -0,0 +1,68 @@
+package mms
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/slonegd/go61850/osi/cotp"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientCheckPduSize(t *testing.T) {
+	c := NewClient(nil, nil)
+	pdu := (&ReadRequest{InvokeID: 1, DomainID: "simpleIOGenericIO", ItemID: "GGIO1$MX$AnIn1$mag$f"}).Bytes()
+
+	// Без согласованного размера ограничения нет
+	assert.NoError(t, c.CheckPduSize(pdu))
+
+	c.SetMaxPduSize(uint32(len(pdu)))
+	assert.NoError(t, c.CheckPduSize(pdu))
+
+	c.SetMaxPduSize(uint32(len(pdu) - 1))
+	err := c.CheckPduSize(pdu)
+	assert.ErrorIs(t, err, ErrPduTooLarge)
+	assert.ErrorIs(t, c.SendMmsPdu(pdu), ErrPduTooLarge)
+}
+
+// readConn - транспорт, отдающий заранее записанные данные
+type readConn struct{ *bytes.Reader }
+
+func (readConn) Write(b []byte) (int, error) { return len(b), nil }
+func (readConn) Close() error                { return nil }
+
+func TestClientDefiniteLengthOnly(t *testing.T) {
+	// confirmed-ResponsePDU с неопределённой длиной: a1 80 ... 00 00
+	packet := parseHexString("0300001b 02f080 01000100 610e 300c 020103 a007 a180020101 0000")
+	mmsPdu := parseHexString("a180020101 0000")
+
+	c := NewClient(cotp.NewConnection(readConn{bytes.NewReader(packet)}), nil)
+	mmsData, err := c.ReceiveAndParseMmsResponse(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, mmsPdu, mmsData)
+
+	c = NewClient(cotp.NewConnection(readConn{bytes.NewReader(packet)}), nil, WithDefiniteLengthOnly())
+	_, err = c.ReceiveAndParseMmsResponse(context.Background())
+	assert.ErrorIs(t, err, ErrIndefiniteLength)
+	assert.EqualError(t, err, "invalid MMS PDU: indefinite length not allowed")
+}
+
+func TestClientDEREncoding(t *testing.T) {
+	c := NewClient(nil, nil, WithDEREncoding())
+	pdus := [][]byte{
+		NewInitiateRequest().Bytes(),
+		(&ReadRequest{InvokeID: 1, DomainID: "simpleIOGenericIO", ItemID: "GGIO1$MX$AnIn1$mag$f"}).Bytes(),
+		NewGetNameListRequest(ObjectClassNamedVariable, "simpleIOGenericIO").Bytes(),
+		(&CancelRequest{InvokeID: 128}).Bytes(),
+	}
+	for _, pdu := range pdus {
+		assert.NoError(t, c.CheckEncoding(pdu), "% x", pdu)
+	}
+
+	// invokeID 1 с лишним ведущим нулём
+	err := c.CheckEncoding(parseHexString("a00a 02020001 a404 a102 a000"))
+	assert.ErrorIs(t, err, ErrNotDER)
+	assert.EqualError(t, err, "MMS PDU is not DER encoded: not DER encoded: non-minimal integer at 2")
+	assert.NoError(t, NewClient(nil, nil).CheckEncoding(parseHexString("a00a 02020001 a404 a102 a000")))
+}
@@ -0,0 +1,343 @@
+package mms
+
+import (
+	"fmt"
+
+	"github.com/slonegd/go61850/internal/ber"
+)
+
+// ConfirmedService представляет номер варианта ConfirmedServiceRequest/ConfirmedServiceResponse
+type ConfirmedService uint8
+
+const (
+	ServiceStatus                         ConfirmedService = 0
+	ServiceGetNameList                    ConfirmedService = 1
+	ServiceIdentify                       ConfirmedService = 2
+	ServiceRename                         ConfirmedService = 3
+	ServiceRead                           ConfirmedService = 4
+	ServiceWrite                          ConfirmedService = 5
+	ServiceGetVariableAccessAttributes    ConfirmedService = 6
+	ServiceDefineNamedVariableList        ConfirmedService = 11
+	ServiceGetNamedVariableListAttributes ConfirmedService = 12
+	ServiceDeleteNamedVariableList        ConfirmedService = 13
+	ServiceGetDomainAttributes            ConfirmedService = 37
+	ServiceObtainFile                     ConfirmedService = 46
+	ServiceFileOpen                       ConfirmedService = 72
+	ServiceFileRead                       ConfirmedService = 73
+	ServiceFileClose                      ConfirmedService = 74
+	ServiceFileDirectory                  ConfirmedService = 77
+)
+
+var confirmedServiceNames = map[ConfirmedService]string{
+	ServiceStatus:                         "status",
+	ServiceGetNameList:                    "getNameList",
+	ServiceIdentify:                       "identify",
+	ServiceRename:                         "rename",
+	ServiceRead:                           "read",
+	ServiceWrite:                          "write",
+	ServiceGetVariableAccessAttributes:    "getVariableAccessAttributes",
+	ServiceDefineNamedVariableList:        "defineNamedVariableList",
+	ServiceGetNamedVariableListAttributes: "getNamedVariableListAttributes",
+	ServiceDeleteNamedVariableList:        "deleteNamedVariableList",
+	ServiceGetDomainAttributes:            "getDomainAttributes",
+	ServiceObtainFile:                     "obtainFile",
+	ServiceFileOpen:                       "fileOpen",
+	ServiceFileRead:                       "fileRead",
+	ServiceFileClose:                      "fileClose",
+	ServiceFileDirectory:                  "fileDirectory",
+}
+
+// String возвращает имя сервиса согласно ASN.1
+func (s ConfirmedService) String() string {
+	if name, ok := confirmedServiceNames[s]; ok {
+		return name
+	}
+	return fmt.Sprintf("service(%d)", uint8(s))
+}
+
+// ConfirmedRequest представляет confirmed-RequestPDU, полученный от сервера
+//
+//	Confirmed-RequestPDU ::= SEQUENCE {
+//	  invokeID Unsigned32,
+//	  listOfModifiers SEQUENCE OF Modifier OPTIONAL,
+//	  service ConfirmedServiceRequest,
+//	  ...
+//	}
+type ConfirmedRequest struct {
+	InvokeID uint32
+	Service  ConfirmedService
+	// Argument - содержимое аргумента сервиса (без тега и длины)
+	Argument []byte
+}
+
+// IsConfirmedRequest проверяет, является ли MMS PDU confirmed-RequestPDU
+func IsConfirmedRequest(buffer []byte) bool {
+	return len(buffer) > 0 && buffer[0] == byte(ber.ContextSpecific0Constructed)
+}
+
+// ParseConfirmedRequest парсит MMS confirmed-RequestPDU: a0 { 02 invokeID, [modifiers], service }
+func ParseConfirmedRequest(buffer []byte) (*ConfirmedRequest, error) {
+	content, err := expectTLV(buffer, byte(ber.ContextSpecific0Constructed), "Confirmed-RequestPDU")
+	if err != nil {
+		return nil, err
+	}
+
+	tag, value, bufPos, err := decodeTLV(content, 0, len(content))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode invokeID: %w", err)
+	}
+	if tag != byte(ber.Integer) || len(value) < 1 || len(value) > 5 {
+		return nil, fmt.Errorf("invalid invokeID in Confirmed-RequestPDU")
+	}
+	request := &ConfirmedRequest{InvokeID: ber.DecodeUint32(value, len(value), 0)}
+
+	if bufPos < len(content) && content[bufPos] == byte(ber.SequenceConstructed) {
+		// listOfModifiers пропускается
+		if _, _, bufPos, err = decodeTLV(content, bufPos, len(content)); err != nil {
+			return nil, fmt.Errorf("failed to decode listOfModifiers of invokeID %d: %w", request.InvokeID, err)
+		}
+	}
+	if bufPos < len(content) && content[bufPos]&0xc0 != 0x80 {
+		return nil, fmt.Errorf("unsupported service tag in Confirmed-RequestPDU: 0x%02x", content[bufPos])
+	}
+	// Номера сервисов от 31 (fileOpen [72] и др.) - в расширенной форме тега
+	service, value, _, err := decodeServiceTLV(content, bufPos, len(content))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode service of invokeID %d: %w", request.InvokeID, err)
+	}
+	request.Service = service
+	request.Argument = value
+	return request, nil
+}
+
+// RequestInvokeID возвращает invokeID confirmed-RequestPDU, даже если сервис
+// в нём не удаётся разобрать. Для остальных PDU возвращает false.
+func RequestInvokeID(buffer []byte) (uint32, bool) {
+	if !IsConfirmedRequest(buffer) {
+		return 0, false
+	}
+	_, content, _, err := decodeTLV(buffer, 0, len(buffer))
+	if err != nil {
+		return 0, false
+	}
+	tag, value, _, err := decodeTLV(content, 0, len(content))
+	if err != nil || tag != byte(ber.Integer) || len(value) < 1 || len(value) > 5 {
+		return 0, false
+	}
+	return ber.DecodeUint32(value, len(value), 0), true
+}
+
+// ConfirmedResponse представляет confirmed-ResponsePDU клиента на запрос сервера
+type ConfirmedResponse struct {
+	InvokeID uint32
+	// Service - закодированный ConfirmedServiceResponse (тег, длина и содержимое)
+	Service []byte
+}
+
+// Bytes кодирует confirmed-ResponsePDU: a1 { 02 invokeID, service }
+func (r *ConfirmedResponse) Bytes() []byte {
+	return wrapTL(ber.ContextSpecific1Constructed, append(encodeInvokeID(r.InvokeID), r.Service...))
+}
+
+// ConfirmedError представляет confirmed-ErrorPDU: сервис не выполнен
+//
+//	Confirmed-ErrorPDU ::= SEQUENCE {
+//	  invokeID [0] IMPLICIT Unsigned32,
+//	  modifierPosition [1] IMPLICIT Unsigned32 OPTIONAL,
+//	  serviceError [2] IMPLICIT ServiceError
+//	}
+type ConfirmedError struct {
+	InvokeID     uint32
+	ServiceError ServiceError
+}
+
+func (e *ConfirmedError) Error() string {
+	return fmt.Sprintf("invokeID %d failed: %v", e.InvokeID, &e.ServiceError)
+}
+
+// Unwrap возвращает ServiceError для errors.As
+func (e *ConfirmedError) Unwrap() error {
+	return &e.ServiceError
+}
+
+// Bytes кодирует confirmed-ErrorPDU: a2 { 80 invokeID, a2 ServiceError }
+func (e *ConfirmedError) Bytes() []byte {
+	content := encodeUnsigned(ber.ContextSpecific0Primitive, e.InvokeID)
+	content = append(content, wrapTL(ber.ContextSpecific2Constructed, e.ServiceError.content())...)
+	return wrapTL(ber.ContextSpecific2Constructed, content)
+}
+
+// IsConfirmedError проверяет, является ли MMS PDU confirmed-ErrorPDU
+func IsConfirmedError(buffer []byte) bool {
+	return len(buffer) > 0 && buffer[0] == byte(ber.ContextSpecific2Constructed)
+}
+
+// checkConfirmedError возвращает *ConfirmedError, если buffer - confirmed-ErrorPDU
+// (сервер отказал в выполнении сервиса), иначе nil
+func checkConfirmedError(buffer []byte) error {
+	if !IsConfirmedError(buffer) {
+		return nil
+	}
+	confirmedError, err := ParseConfirmedError(buffer)
+	if err != nil {
+		return err
+	}
+	return confirmedError
+}
+
+// ParseConfirmedError парсит confirmed-ErrorPDU (обратная операция к Bytes)
+func ParseConfirmedError(buffer []byte) (*ConfirmedError, error) {
+	content, err := expectTLV(buffer, byte(ber.ContextSpecific2Constructed), "confirmed-ErrorPDU")
+	if err != nil {
+		return nil, err
+	}
+	confirmedError := &ConfirmedError{}
+	var hasServiceError bool
+	for bufPos := 0; bufPos < len(content); {
+		tag, value, next, err := decodeTLV(content, bufPos, len(content))
+		if err != nil {
+			return nil, err
+		}
+		switch tag {
+		case byte(ber.ContextSpecific0Primitive): // invokeID
+			if len(value) < 1 || len(value) > 5 {
+				return nil, fmt.Errorf("invalid invokeID length: %d", len(value))
+			}
+			confirmedError.InvokeID = ber.DecodeUint32(value, len(value), 0)
+		case byte(ber.ContextSpecific1Primitive): // modifierPosition
+		case byte(ber.ContextSpecific2Constructed): // serviceError
+			serviceError, err := parseServiceError(value)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse confirmed-ErrorPDU: %w", err)
+			}
+			confirmedError.ServiceError = *serviceError
+			hasServiceError = true
+		default:
+			return nil, fmt.Errorf("unexpected tag in confirmed-ErrorPDU: 0x%02x", tag)
+		}
+		bufPos = next
+	}
+	if !hasServiceError {
+		return nil, fmt.Errorf("confirmed-ErrorPDU does not contain serviceError")
+	}
+	return confirmedError, nil
+}
+
+// RejectReason представляет причину отклонения confirmed-RequestPDU
+// (вариант confirmed-requestPDU поля rejectReason)
+type RejectReason uint8
+
+const (
+	RejectOther                      RejectReason = 0
+	RejectUnrecognizedService        RejectReason = 1
+	RejectUnrecognizedModifier       RejectReason = 2
+	RejectInvalidInvokeID            RejectReason = 3
+	RejectInvalidArgument            RejectReason = 4
+	RejectInvalidModifier            RejectReason = 5
+	RejectMaxServOutstandingExceeded RejectReason = 6
+	RejectMaxRecursionExceeded       RejectReason = 8
+	RejectValueOutOfRange            RejectReason = 9
+)
+
+// Reject представляет RejectPDU на confirmed-RequestPDU
+//
+//	RejectPDU ::= SEQUENCE {
+//	  originalInvokeID [0] IMPLICIT Unsigned32 OPTIONAL,
+//	  rejectReason CHOICE { confirmed-requestPDU [1] IMPLICIT INTEGER, ... }
+//	}
+type Reject struct {
+	InvokeID uint32
+	Reason   RejectReason
+}
+
+// Bytes кодирует RejectPDU: a4 { 80 invokeID, 81 reason }
+func (r *Reject) Bytes() []byte {
+	content := encodeUnsigned(ber.ContextSpecific0Primitive, r.InvokeID)
+	content = append(content, encodeUnsigned(ber.ContextSpecific1Primitive, uint32(r.Reason))...)
+	return wrapTL(ber.ContextSpecific4Constructed, content)
+}
+
+// IdentifyRequest представляет MMS Identify Request PDU (Identify-Request ::= NULL)
+type IdentifyRequest struct {
+	InvokeID uint32
+}
+
+// Bytes кодирует IdentifyRequest: a0 { 02 invokeID, 82 00 }
+func (r *IdentifyRequest) Bytes() []byte {
+	return encodeConfirmedRequest(r.InvokeID, ServiceIdentify, false, nil)
+}
+
+// IdentifyResponse представляет ответ сервиса Identify
+//
+//	Identify-Response ::= SEQUENCE {
+//	  vendorName [0] IMPLICIT MMSString,
+//	  modelName [1] IMPLICIT MMSString,
+//	  revision [2] IMPLICIT MMSString,
+//	  ...
+//	}
+type IdentifyResponse struct {
+	VendorName string
+	ModelName  string
+	Revision   string
+}
+
+// Bytes кодирует ConfirmedServiceResponse identify: a2 { 80 vendor, 81 model, 82 revision }
+func (r *IdentifyResponse) Bytes() []byte {
+	var content []byte
+	content = append(content, wrapTL(ber.ContextSpecific0Primitive, []byte(r.VendorName))...)
+	content = append(content, wrapTL(ber.ContextSpecific1Primitive, []byte(r.ModelName))...)
+	content = append(content, wrapTL(ber.ContextSpecific2Primitive, []byte(r.Revision))...)
+	return wrapTL(ber.ContextSpecific2Constructed, content)
+}
+
+// ParseIdentifyResponse парсит MMS Identify Response PDU: a1 { 02 invokeID, a2 { 80, 81, 82 } }.
+// Список listOfAbstractSyntaxes [3] пропускается.
+// Для confirmed-ErrorPDU возвращается *ConfirmedError.
+func ParseIdentifyResponse(buffer []byte) (*IdentifyResponse, error) {
+	content, err := parseServiceResponse(buffer, ServiceIdentify)
+	if err != nil {
+		return nil, err
+	}
+	response := &IdentifyResponse{}
+	for bufPos := 0; bufPos < len(content); {
+		tag, value, next, err := decodeTLV(content, bufPos, len(content))
+		if err != nil {
+			return nil, err
+		}
+		switch tag {
+		case byte(ber.ContextSpecific0Primitive):
+			response.VendorName = string(value)
+		case byte(ber.ContextSpecific1Primitive):
+			response.ModelName = string(value)
+		case byte(ber.ContextSpecific2Primitive):
+			response.Revision = string(value)
+		}
+		bufPos = next
+	}
+	return response, nil
+}
+
+// Значения vmdLogicalStatus и vmdPhysicalStatus сервиса Status
+const (
+	LogicalStatusStateChangesAllowed uint8 = 0
+	PhysicalStatusOperational        uint8 = 0
+)
+
+// StatusResponse представляет ответ сервиса Status
+//
+//	Status-Response ::= SEQUENCE {
+//	  vmdLogicalStatus [0] IMPLICIT INTEGER,
+//	  vmdPhysicalStatus [1] IMPLICIT INTEGER,
+//	  localDetail [2] IMPLICIT BIT STRING (SIZE(0..128)) OPTIONAL
+//	}
+type StatusResponse struct {
+	LogicalStatus  uint8
+	PhysicalStatus uint8
+}
+
+// Bytes кодирует ConfirmedServiceResponse status: a0 { 80 logical, 81 physical }
+func (r *StatusResponse) Bytes() []byte {
+	content := encodeUnsigned(ber.ContextSpecific0Primitive, uint32(r.LogicalStatus))
+	content = append(content, encodeUnsigned(ber.ContextSpecific1Primitive, uint32(r.PhysicalStatus))...)
+	return wrapTL(ber.ContextSpecific0Constructed, content)
+}
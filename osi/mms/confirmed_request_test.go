@@ -0,0 +1,82 @@
+package mms
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseConfirmedRequest(t *testing.T) {
+	buffer := parseHexString("a0 05 020107 8200")
+	assert.True(t, IsConfirmedRequest(buffer))
+	request, err := ParseConfirmedRequest(buffer)
+	assert.NoError(t, err)
+	assert.Equal(t, &ConfirmedRequest{InvokeID: 7, Service: ServiceIdentify, Argument: []byte{}}, request)
+	assert.Equal(t, "identify", request.Service.String())
+
+	// listOfModifiers пропускается
+	request, err = ParseConfirmedRequest(parseHexString("a0 0a 020108 3000 a1 03 a0 01 00"))
+	assert.NoError(t, err)
+	assert.Equal(t, ServiceGetNameList, request.Service)
+	assert.Equal(t, parseHexString("a00100"), request.Argument)
+
+	_, err = ParseConfirmedRequest(parseHexString("a0 05 020109 1f00"))
+	assert.EqualError(t, err, "unsupported service tag in Confirmed-RequestPDU: 0x1f")
+	invokeID, ok := RequestInvokeID(parseHexString("a0 05 020109 1f00"))
+	assert.True(t, ok)
+	assert.Equal(t, uint32(9), invokeID)
+
+	_, ok = RequestInvokeID(parseHexString("a1 03 020109"))
+	assert.False(t, ok)
+	assert.Equal(t, "service(99)", ConfirmedService(99).String())
+}
+
+func TestServerRequestAnswers(t *testing.T) {
+	identify := &ConfirmedResponse{
+		InvokeID: 7,
+		Service:  (&IdentifyResponse{VendorName: "V", ModelName: "M", Revision: "1"}).Bytes(),
+	}
+	assert.Equal(t, parseHexString("a1 0e 020107 a2 09 800156 81014d 820131"), identify.Bytes())
+
+	status := &StatusResponse{LogicalStatus: LogicalStatusStateChangesAllowed, PhysicalStatus: PhysicalStatusOperational}
+	assert.Equal(t, parseHexString("a0 06 800100 810100"), status.Bytes())
+
+	reject := &Reject{InvokeID: 8, Reason: RejectUnrecognizedService}
+	assert.Equal(t, parseHexString("a4 06 800108 810101"), reject.Bytes())
+
+	confirmedError := &ConfirmedError{InvokeID: 2, ServiceError: ServiceError{Class: ErrorClassAccess, Code: 2}}
+	assert.Equal(t, parseHexString("a2 0a 800102 a205 a003 870102"), confirmedError.Bytes())
+	assert.EqualError(t, confirmedError, "invokeID 2 failed: MMS service error: class access, code object-non-existent")
+}
+
+func TestIdentify(t *testing.T) {
+	assert.Equal(t, parseHexString("a0 05 020103 8200"), (&IdentifyRequest{InvokeID: 3}).Bytes())
+
+	// listOfAbstractSyntaxes пропускается
+	buffer := parseHexString("a1 15 020103 a2 10 800156 81014d 820131 a3 05 0603 2a0304")
+	response, err := ParseIdentifyResponse(buffer)
+	assert.NoError(t, err)
+	assert.Equal(t, &IdentifyResponse{VendorName: "V", ModelName: "M", Revision: "1"}, response)
+
+	_, err = ParseIdentifyResponse(parseHexString("a1 07 020103 a5 02 8100"))
+	assert.EqualError(t, err, "unexpected service in confirmed-ResponsePDU: write")
+
+	buffer = (&ConfirmedError{InvokeID: 3, ServiceError: ServiceError{Class: ErrorClassService, Code: ServiceErrorOther}}).Bytes()
+	_, err = ParseIdentifyResponse(buffer)
+	var confirmedError *ConfirmedError
+	assert.ErrorAs(t, err, &confirmedError)
+}
+
+func TestSummary(t *testing.T) {
+	request, err := (&GetDomainAttributesRequest{InvokeID: 3, DomainID: "LD"}).Bytes()
+	assert.NoError(t, err)
+	assert.Equal(t, "confirmed-RequestPDU invokeID 3 getDomainAttributes", Summary(request))
+	assert.Equal(t, "confirmed-ResponsePDU invokeID 1 getNameList",
+		Summary((&GetNameListResponse{InvokeID: 1, Identifiers: []string{"LD0"}}).Bytes()))
+	assert.Equal(t, "cancel-RequestPDU invokeID 7", Summary(parseHexString("850107")))
+	assert.Equal(t, "unconfirmed-PDU informationReport", Summary(parseHexString("a304a0020000")))
+	assert.Equal(t, "initiate-RequestPDU", Summary(NewInitiateRequest().Bytes()))
+	assert.Equal(t, "confirmed-RequestPDU (malformed)", Summary(parseHexString("a005")))
+	assert.Equal(t, "unknown PDU tag 0x30", Summary(parseHexString("3000")))
+	assert.Equal(t, "empty PDU", Summary(nil))
+}
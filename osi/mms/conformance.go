@@ -0,0 +1,236 @@
+package mms
+
+import (
+	"encoding/csv"
+	"io"
+	"slices"
+	"strconv"
+)
+
+// ServiceRole - роль стороны в сервисе MMS
+type ServiceRole uint8
+
+const (
+	// RoleRequester - сторона отправляет запрос сервиса и разбирает ответ (клиент)
+	RoleRequester ServiceRole = iota
+	// RoleResponder - сторона разбирает запрос сервиса и отвечает на него (сервер)
+	RoleResponder
+)
+
+// String возвращает название роли
+func (r ServiceRole) String() string {
+	switch r {
+	case RoleRequester:
+		return "requester"
+	case RoleResponder:
+		return "responder"
+	default:
+		return "unknown"
+	}
+}
+
+// ServiceConformance - строка матрицы соответствия: роли стека в сервисе MMS
+type ServiceConformance struct {
+	// Bit - номер бита сервиса в ServicesSupported
+	Bit       ServiceSupportedBit `json:"bit"`
+	Service   string              `json:"service"`
+	Requester bool                `json:"requester"`
+	Responder bool                `json:"responder"`
+}
+
+// ParameterConformance - строка матрицы соответствия: поддержка параметра CBB
+type ParameterConformance struct {
+	// Bit - номер бита параметра в ParameterSupportOptions
+	Bit       ParameterCBBBit `json:"bit"`
+	Parameter string          `json:"parameter"`
+	Supported bool            `json:"supported"`
+}
+
+// ACSIBlock - блок соответствия ACSI (IEC 61850-7-2, приложение A)
+type ACSIBlock string
+
+const (
+	// ACSIDataModel - каталог модели: логические устройства, узлы и данные
+	ACSIDataModel ACSIBlock = "dataModel"
+	// ACSIDataSet - чтение наборов данных и их состава
+	ACSIDataSet ACSIBlock = "dataSet"
+	// ACSIBRCB и ACSIURCB - буферизованные и небуферизованные отчёты
+	ACSIBRCB ACSIBlock = "BRCB"
+	ACSIURCB ACSIBlock = "URCB"
+	// ACSIControl - прямое управление, ACSISBO - управление с предварительным выбором
+	ACSIControl ACSIBlock = "control"
+	ACSISBO     ACSIBlock = "controlSBO"
+	// ACSIFiles - передача файлов
+	ACSIFiles ACSIBlock = "files"
+	// ACSIGOOSE и ACSISV - GSE и Sampled Values, передаются без MMS
+	ACSIGOOSE ACSIBlock = "GOOSE"
+	ACSISV    ACSIBlock = "SV"
+)
+
+// ACSIConformance - строка матрицы соответствия: роли стороны в блоке ACSI.
+// Requester - клиент (для GOOSE и SV - подписчик), Responder - сервер (издатель).
+type ACSIConformance struct {
+	Block     ACSIBlock `json:"block"`
+	Requester bool      `json:"requester"`
+	Responder bool      `json:"responder"`
+}
+
+// acsiBlocks - сервисы MMS, через которые IEC 61850-8-1 отображает блоки ACSI:
+// confirmed запрашивает клиент, unconfirmed отправляет сервер. GOOSE и SV
+// не отображаются на MMS и заполняются стороной (SetACSI).
+var acsiBlocks = []struct {
+	block       ACSIBlock
+	confirmed   []ServiceSupportedBit
+	unconfirmed []ServiceSupportedBit
+}{
+	{ACSIDataModel, []ServiceSupportedBit{GetNameList, GetVariableAccessAttributes}, nil},
+	{ACSIDataSet, []ServiceSupportedBit{GetNamedVariableListAttributes, Read}, nil},
+	{ACSIBRCB, []ServiceSupportedBit{Read, Write}, []ServiceSupportedBit{InformationReport}},
+	{ACSIURCB, []ServiceSupportedBit{Read, Write}, []ServiceSupportedBit{InformationReport}},
+	// CommandTermination и LastApplError передаются в InformationReport
+	{ACSIControl, []ServiceSupportedBit{Write}, []ServiceSupportedBit{InformationReport}},
+	{ACSISBO, []ServiceSupportedBit{Read, Write}, []ServiceSupportedBit{InformationReport}},
+	{ACSIFiles, []ServiceSupportedBit{FileDirectory, FileOpen, FileRead, FileClose}, nil},
+	{ACSIGOOSE, nil, nil},
+	{ACSISV, nil, nil},
+}
+
+// Conformance - машиночитаемая матрица соответствия (PICS) стороны MMS:
+// строка на каждый сервис ServicesSupported (ISO 9506-2) с ролями, строка
+// на каждый параметр CBB и строка на каждый блок ACSI. Матрицу можно сравнить
+// с PIXIT устройства (Missing) или сохранить в JSON (теги полей) и CSV (WriteCSV).
+type Conformance struct {
+	Services     []ServiceConformance   `json:"services"`
+	ParameterCBB []ParameterConformance `json:"parameterCBB"`
+	ACSI         []ACSIConformance      `json:"acsi"`
+}
+
+// NewConformance строит матрицу соответствия по сервисам, которые сторона
+// запрашивает (requester) и на которые отвечает (responder), и параметрам CBB.
+// Блок ACSI поддерживается клиентом, если тот запрашивает его confirmed сервисы
+// и принимает unconfirmed, и сервером - в обратных ролях.
+func NewConformance(requester, responder []ServiceSupportedBit, parameters []ParameterCBBBit) *Conformance {
+	c := &Conformance{}
+	for bit := Status; bit <= Cancel; bit++ {
+		c.Services = append(c.Services, ServiceConformance{
+			Bit:       bit,
+			Service:   bit.String(),
+			Requester: slices.Contains(requester, bit),
+			Responder: slices.Contains(responder, bit),
+		})
+	}
+	for bit := Str1; bit <= Cei; bit++ {
+		c.ParameterCBB = append(c.ParameterCBB, ParameterConformance{
+			Bit:       bit,
+			Parameter: bit.String(),
+			Supported: slices.Contains(parameters, bit),
+		})
+	}
+	for _, block := range acsiBlocks {
+		row := ACSIConformance{Block: block.block}
+		if block.confirmed != nil {
+			row.Requester = c.supportsAll(block.confirmed, RoleRequester) && c.supportsAll(block.unconfirmed, RoleResponder)
+			row.Responder = c.supportsAll(block.confirmed, RoleResponder) && c.supportsAll(block.unconfirmed, RoleRequester)
+		}
+		c.ACSI = append(c.ACSI, row)
+	}
+	return c
+}
+
+// supportsAll проверяет, выполняет ли сторона все сервисы services в роли role
+func (c *Conformance) supportsAll(services []ServiceSupportedBit, role ServiceRole) bool {
+	for _, service := range services {
+		if !c.Supports(service, role) {
+			return false
+		}
+	}
+	return true
+}
+
+// SetACSI задаёт роли стороны в блоке ACSI, не выводимые из сервисов MMS (GOOSE, SV)
+func (c *Conformance) SetACSI(block ACSIBlock, requester, responder bool) {
+	for i := range c.ACSI {
+		if c.ACSI[i].Block == block {
+			c.ACSI[i].Requester, c.ACSI[i].Responder = requester, responder
+			return
+		}
+	}
+	c.ACSI = append(c.ACSI, ACSIConformance{Block: block, Requester: requester, Responder: responder})
+}
+
+// SupportsACSI проверяет, выполняет ли сторона блок ACSI block в роли role
+func (c *Conformance) SupportsACSI(block ACSIBlock, role ServiceRole) bool {
+	for _, row := range c.ACSI {
+		if row.Block == block {
+			return role == RoleRequester && row.Requester || role == RoleResponder && row.Responder
+		}
+	}
+	return false
+}
+
+// Supports проверяет, выполняет ли сторона сервис service в роли role
+func (c *Conformance) Supports(service ServiceSupportedBit, role ServiceRole) bool {
+	for _, row := range c.Services {
+		if row.Bit == service {
+			return role == RoleRequester && row.Requester || role == RoleResponder && row.Responder
+		}
+	}
+	return false
+}
+
+// ServicesIn возвращает сервисы, которые сторона выполняет в роли role, в порядке битов
+func (c *Conformance) ServicesIn(role ServiceRole) []ServiceSupportedBit {
+	var services []ServiceSupportedBit
+	for _, row := range c.Services {
+		if c.Supports(row.Bit, role) {
+			services = append(services, row.Bit)
+		}
+	}
+	return services
+}
+
+// Missing возвращает сервисы из services (например, заявленные в PIXIT устройства
+// или в servicesSupportedCalled его ответа Initiate), которые сторона
+// не выполняет в роли role
+func (c *Conformance) Missing(services []ServiceSupportedBit, role ServiceRole) []ServiceSupportedBit {
+	var missing []ServiceSupportedBit
+	for _, service := range services {
+		if !c.Supports(service, role) {
+			missing = append(missing, service)
+		}
+	}
+	return missing
+}
+
+// WriteCSV записывает матрицу в CSV: строка на сервис (kind "service"),
+// на параметр CBB (kind "parameterCBB", роли совпадают) и на блок ACSI
+// (kind "acsi", без номера бита)
+func (c *Conformance) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"kind", "bit", "name", "requester", "responder"})
+	for _, row := range c.Services {
+		writer.Write([]string{"service", strconv.Itoa(int(row.Bit)), row.Service,
+			strconv.FormatBool(row.Requester), strconv.FormatBool(row.Responder)})
+	}
+	for _, row := range c.ParameterCBB {
+		supported := strconv.FormatBool(row.Supported)
+		writer.Write([]string{"parameterCBB", strconv.Itoa(int(row.Bit)), row.Parameter, supported, supported})
+	}
+	for _, row := range c.ACSI {
+		writer.Write([]string{"acsi", "", string(row.Block),
+			strconv.FormatBool(row.Requester), strconv.FormatBool(row.Responder)})
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// Conformance возвращает матрицу соответствия сервера: сервисы с обработчиками
+// и conclude в роли responder, InformationReport в роли requester при
+// WithUnconfirmedHook, параметры CBB из WithInitiateLimits
+func (s *Server) Conformance() *Conformance {
+	var requester []ServiceSupportedBit
+	if s.unconfirmed != nil {
+		requester = append(requester, InformationReport)
+	}
+	return NewConformance(requester, s.services(), s.limits.NegotiatedParameterCBB)
+}
@@ -0,0 +1,62 @@
+package mms
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConformance(t *testing.T) {
+	c := NewConformance([]ServiceSupportedBit{Read, Cancel}, []ServiceSupportedBit{InformationReport}, []ParameterCBBBit{Str1})
+	assert.Len(t, c.Services, int(Cancel)+1)
+	assert.Len(t, c.ParameterCBB, int(Cei)+1)
+	assert.Equal(t, ServiceConformance{Bit: Read, Service: "Read", Requester: true}, c.Services[Read])
+	assert.True(t, c.Supports(InformationReport, RoleResponder))
+	assert.False(t, c.Supports(InformationReport, RoleRequester))
+	assert.Equal(t, []ServiceSupportedBit{Read, Cancel}, c.ServicesIn(RoleRequester))
+
+	// Сравнение с сервисами, заявленными устройством
+	assert.Equal(t, []ServiceSupportedBit{Write, FileOpen}, c.Missing([]ServiceSupportedBit{Read, Write, FileOpen}, RoleRequester))
+
+	var csv bytes.Buffer
+	assert.NoError(t, c.WriteCSV(&csv))
+	lines := strings.Split(csv.String(), "\n")
+	assert.Equal(t, "kind,bit,name,requester,responder", lines[0])
+	assert.Equal(t, "service,4,Read,true,false", lines[1+int(Read)])
+	assert.Contains(t, lines, "parameterCBB,0,Str1,true,true")
+	assert.Contains(t, lines, "acsi,,GOOSE,false,false")
+
+	c.SetACSI(ACSISV, true, false)
+	assert.True(t, c.SupportsACSI(ACSISV, RoleRequester))
+	assert.False(t, c.SupportsACSI(ACSISV, RoleResponder))
+
+	data, err := json.Marshal(c)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `{"bit":79,"service":"InformationReport","requester":false,"responder":true}`)
+}
+
+func TestServerConformance(t *testing.T) {
+	s := NewServer(nil, WithHandler(ServiceRead, nil), WithHandler(ServiceFileOpen, nil))
+	c := s.Conformance()
+	assert.Equal(t, []ServiceSupportedBit{Read, FileOpen, Conclude}, c.ServicesIn(RoleResponder))
+	assert.Empty(t, c.ServicesIn(RoleRequester))
+	assert.True(t, c.ParameterCBB[Vlis].Supported)
+	assert.False(t, c.ParameterCBB[Real].Supported)
+
+	// Блоки ACSI выводятся из сервисов: отчёты требуют отправки InformationReport
+	s = NewServer(nil, WithHandler(ServiceRead, nil), WithHandler(ServiceWrite, nil),
+		WithHandler(ServiceGetNamedVariableListAttributes, nil))
+	assert.True(t, s.Conformance().SupportsACSI(ACSIDataSet, RoleResponder))
+	assert.False(t, s.Conformance().SupportsACSI(ACSIBRCB, RoleResponder))
+	s = NewServer(nil, WithHandler(ServiceRead, nil), WithHandler(ServiceWrite, nil),
+		WithUnconfirmedHook(func(func([]byte) error) func() { return func() {} }))
+	c = s.Conformance()
+	assert.Equal(t, []ServiceSupportedBit{InformationReport}, c.ServicesIn(RoleRequester))
+	assert.True(t, c.SupportsACSI(ACSIBRCB, RoleResponder))
+	assert.True(t, c.SupportsACSI(ACSISBO, RoleResponder))
+	assert.False(t, c.SupportsACSI(ACSIFiles, RoleResponder))
+	assert.False(t, c.SupportsACSI(ACSIBRCB, RoleRequester))
+}
@@ -0,0 +1,156 @@
+package mms
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"unicode/utf8"
+
+	"github.com/slonegd/go61850/internal/ber"
+	"github.com/slonegd/go61850/osi/mms/variant"
+)
+
+// Теги MMS Data, используемые при кодировании (см. описание Data в read_response.go)
+const (
+	dataTagStructure     ber.Tag = 0xA2
+	dataTagBoolean       ber.Tag = 0x83
+	dataTagBitString     ber.Tag = 0x84
+	dataTagInteger       ber.Tag = 0x85
+	dataTagUnsigned      ber.Tag = 0x86
+	dataTagFloatingPoint ber.Tag = 0x87
+	dataTagOctetString   ber.Tag = 0x89
+	dataTagBinaryTime    ber.Tag = 0x8C
+	dataTagVisibleString ber.Tag = 0x8A
+	dataTagMMSString     ber.Tag = 0x90
+	dataTagUTCTime       ber.Tag = 0x91
+)
+
+// EncodeData кодирует Variant в BER-кодированный элемент MMS Data (тег + длина + значение).
+// Структуры кодируются рекурсивно.
+// Обратная операция выполняется parseDataElement.
+func EncodeData(v *variant.Variant) ([]byte, error) {
+	if v == nil {
+		return nil, fmt.Errorf("cannot encode nil variant")
+	}
+
+	var tag ber.Tag
+	var content []byte
+
+	switch v.Type() {
+	case variant.Float32:
+		// Формат 0x08 (IEEE 754 single precision) + 4 байта big-endian
+		tag = dataTagFloatingPoint
+		content = make([]byte, 5)
+		content[0] = 0x08
+		binary.BigEndian.PutUint32(content[1:], math.Float32bits(v.Float32()))
+
+	case variant.Int32:
+		// INTEGER в минимальном дополнительном коде big-endian
+		tag = dataTagInteger
+		content = make([]byte, 4)
+		binary.BigEndian.PutUint32(content, uint32(v.Int32()))
+		content = content[:ber.CompressInteger(content)]
+
+	case variant.Int64:
+		tag = dataTagInteger
+		content = make([]byte, 8)
+		binary.BigEndian.PutUint64(content, uint64(v.Int64()))
+		content = content[:ber.CompressInteger(content)]
+
+	case variant.Bool:
+		tag = dataTagBoolean
+		content = []byte{0x00}
+		if v.Bool() {
+			content[0] = 0xff
+		}
+
+	case variant.Uint32:
+		// Unsigned кодируется как неотрицательный INTEGER: старший бит
+		// первого байта должен быть 0, поэтому допускается ведущий 0x00
+		tag = dataTagUnsigned
+		content = make([]byte, 5)
+		binary.BigEndian.PutUint32(content[1:], v.Uint32())
+		content = content[:ber.CompressInteger(content)]
+
+	case variant.Uint64:
+		tag = dataTagUnsigned
+		content = make([]byte, 9)
+		binary.BigEndian.PutUint64(content[1:], v.Uint64())
+		content = content[:ber.CompressInteger(content)]
+
+	case variant.OctetString:
+		tag = dataTagOctetString
+		content = append([]byte{}, v.OctetString()...)
+
+	case variant.BinaryTime:
+		tag = dataTagBinaryTime
+		content = append([]byte{}, v.OctetString()...)
+
+	case variant.UTCTime:
+		tag = dataTagUTCTime
+		content = encodeUTCTime(v)
+
+	case variant.BitString:
+		val := v.BitString()
+		byteSize := (val.BitSize + 7) / 8
+		if byteSize > len(val.Data) {
+			return nil, fmt.Errorf("bit-string data too short: %d bits in %d bytes", val.BitSize, len(val.Data))
+		}
+		tag = dataTagBitString
+		content = make([]byte, 1+byteSize)
+		content[0] = byte(byteSize*8 - val.BitSize)
+		copy(content[1:], val.Data[:byteSize])
+
+	case variant.VisibleString:
+		// visible-string допускает только печатаемые символы ASCII;
+		// для Unicode используется MMSString
+		val := v.StringValue()
+		for i := 0; i < len(val); i++ {
+			if val[i] < 0x20 || val[i] > 0x7e {
+				return nil, fmt.Errorf("visible-string contains non-printable or non-ASCII character at %d, use MMSString", i)
+			}
+		}
+		tag = dataTagVisibleString
+		content = []byte(val)
+
+	case variant.MMSString:
+		val := v.StringValue()
+		if !utf8.ValidString(val) {
+			return nil, fmt.Errorf("MMSString is not valid UTF-8")
+		}
+		tag = dataTagMMSString
+		content = []byte(val)
+
+	case variant.Structure:
+		tag = dataTagStructure
+		for i, elem := range v.Structure() {
+			encoded, err := EncodeData(elem)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode structure element %d: %w", i, err)
+			}
+			content = append(content, encoded...)
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported variant type: %s", v.Type())
+	}
+
+	return wrapTL(tag, content), nil
+}
+
+// encodeUTCTime кодирует UTC time в 8 байт (обратная операция к parseUTCTime).
+// Последний байт - качество времени значения (по умолчанию variant.DefaultTimeQuality).
+func encodeUTCTime(v *variant.Variant) []byte {
+	t := v.Time()
+	buffer := make([]byte, 8)
+	binary.BigEndian.PutUint32(buffer[0:4], uint32(t.Unix()))
+
+	// Доля секунды в единицах 1/2^24 секунды
+	fraction := uint64(t.Nanosecond()) * 0x1000000 / 1_000_000_000
+	buffer[4] = byte(fraction >> 16)
+	buffer[5] = byte(fraction >> 8)
+	buffer[6] = byte(fraction)
+	buffer[7] = byte(v.TimeQuality())
+
+	return buffer
+}
@@ -0,0 +1,61 @@
+package mms
+
+import (
+	"fmt"
+
+	"github.com/slonegd/go61850/internal/ber"
+)
+
+// ReadDataSetRequest представляет MMS Read Request набора данных: вместо listOfVariable
+// variableAccessSpecification содержит variableListName - имя namedVariableList.
+// Сервер возвращает по одному AccessResult на элемент набора в порядке его определения.
+//
+//	VariableAccessSpecification ::= CHOICE {
+//	  listOfVariable   [0] IMPLICIT SEQUENCE OF ...,
+//	  variableListName [1] ObjectName
+//	}
+type ReadDataSetRequest struct {
+	InvokeID uint32
+	// DataSet - имя набора данных, например {"simpleIOGenericIO", "LLN0$Events"}
+	DataSet ObjectName
+}
+
+// Bytes кодирует ReadDataSetRequest в BER-кодированный пакет MMS confirmed-RequestPDU
+// a0 (confirmed-RequestPDU)
+//
+//	02 (invokeID)
+//	a4 (read)
+//	   a1 (variableAccessSpecification)
+//	      a1 (variableListName)
+//	         a1 (domain-specific) { 1a domainId 1a itemId }
+func (r *ReadDataSetRequest) Bytes() ([]byte, error) {
+	if err := r.DataSet.Validate(); err != nil {
+		return nil, err
+	}
+	name := wrapTL(ber.ContextSpecific1Constructed, encodeObjectName(r.DataSet))
+	pdu := encodeInvokeID(r.InvokeID)
+	pdu = append(pdu, wrapTL(ber.ContextSpecific4Constructed, wrapTL(ber.ContextSpecific1Constructed, name))...)
+	return wrapTL(ber.ContextSpecific0Constructed, pdu), nil
+}
+
+// DataSetValue - результат чтения одного элемента набора данных
+type DataSetValue struct {
+	// Member - элемент набора (например, "simpleIOGenericIO/GGIO1$ST$Ind1")
+	Member DataSetMember
+	Result AccessResult
+}
+
+// MapDataSetResults сопоставляет результаты чтения набора данных его элементам.
+// Результаты в ответе Read не содержат имён и идут в порядке элементов набора
+// (GetNamedVariableListAttributesResponse.Members), поэтому порядок members должен
+// совпадать с определением набора на сервере. Порядок результатов сохраняется.
+func MapDataSetResults(members []DataSetMember, results []AccessResult) ([]DataSetValue, error) {
+	if len(members) != len(results) {
+		return nil, fmt.Errorf("data set has %d members, read returned %d results", len(members), len(results))
+	}
+	values := make([]DataSetValue, len(members))
+	for i, member := range members {
+		values[i] = DataSetValue{Member: member, Result: results[i]}
+	}
+	return values, nil
+}
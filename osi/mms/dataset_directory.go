@@ -0,0 +1,354 @@
+package mms
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/slonegd/go61850/internal/ber"
+)
+
+// GetNamedVariableListAttributesRequest представляет MMS GetNamedVariableListAttributes
+// Request PDU - запрос элементов набора данных (GetDataSetDirectory в IEC 61850)
+//
+//	confirmedServiceRequest ::= CHOICE {
+//	  getNamedVariableListAttributes [12] GetNamedVariableListAttributes-Request
+//	}
+//
+//	GetNamedVariableListAttributes-Request ::= ObjectName
+type GetNamedVariableListAttributesRequest struct {
+	InvokeID uint32
+	// DataSet - имя набора данных
+	DataSet ObjectName
+}
+
+// Bytes кодирует GetNamedVariableListAttributesRequest в BER-кодированный пакет MMS confirmed-RequestPDU
+// a0 (confirmed-RequestPDU)
+//
+//	02 (invokeID)
+//	ac (getNamedVariableListAttributes)
+//	   a1 (domain-specific) { 1a domainId 1a itemId }
+func (r *GetNamedVariableListAttributesRequest) Bytes() ([]byte, error) {
+	if err := r.DataSet.Validate(); err != nil {
+		return nil, err
+	}
+	pdu := encodeInvokeID(r.InvokeID)
+	pdu = append(pdu, wrapTL(ber.ContextSpecific12Constructed, encodeObjectName(r.DataSet))...)
+	return wrapTL(ber.ContextSpecific0Constructed, pdu), nil
+}
+
+// AccessSelectionKind - вариант выбора альтернативного доступа
+type AccessSelectionKind uint8
+
+const (
+	// AccessComponent выбирает компонент структуры по имени
+	AccessComponent AccessSelectionKind = iota
+	// AccessIndex выбирает элемент массива по индексу
+	AccessIndex
+	// AccessIndexRange выбирает диапазон элементов массива
+	AccessIndexRange
+	// AccessAllElements выбирает все элементы массива
+	AccessAllElements
+)
+
+// AccessSelection - один шаг альтернативного доступа (AlternateAccessSelection)
+//
+//	selectAccess ::= CHOICE {
+//	  component   [1] IMPLICIT Identifier,
+//	  index       [2] IMPLICIT Unsigned32,
+//	  indexRange  [3] IMPLICIT SEQUENCE { lowIndex [0] Unsigned32, numberOfElements [1] Unsigned32 },
+//	  allElements [4] IMPLICIT NULL
+//	}
+//
+// В selectAlternateAccess те же варианты идут с тегами на единицу меньше.
+type AccessSelection struct {
+	Kind AccessSelectionKind
+	// Component - имя компонента для AccessComponent
+	Component string
+	// Index - индекс элемента (AccessIndex) или первый индекс диапазона (AccessIndexRange)
+	Index uint32
+	// Count - число элементов диапазона для AccessIndexRange
+	Count uint32
+}
+
+// String возвращает шаг доступа: "$comp", "(3)", "(3..5)" или "(*)"
+func (s AccessSelection) String() string {
+	switch s.Kind {
+	case AccessComponent:
+		return "$" + s.Component
+	case AccessIndex:
+		return fmt.Sprintf("(%d)", s.Index)
+	case AccessIndexRange:
+		return fmt.Sprintf("(%d..%d)", s.Index, s.Index+s.Count-1)
+	default:
+		return "(*)"
+	}
+}
+
+// DataSetMember - элемент набора данных (VariableSpecification с необязательным alternateAccess)
+type DataSetMember struct {
+	Name ObjectName
+	// AlternateAccess - путь альтернативного доступа от переменной Name к элементу набора;
+	// вложенные selectAlternateAccess развёрнуты в последовательность шагов
+	AlternateAccess []AccessSelection
+}
+
+// String возвращает ссылку на элемент, например "LD/GGIO1$ST$Ind1" или "LD/MMXU1$MX$PhV(0)$cVal"
+func (m DataSetMember) String() string {
+	var b strings.Builder
+	b.WriteString(m.Name.String())
+	for _, s := range m.AlternateAccess {
+		b.WriteString(s.String())
+	}
+	return b.String()
+}
+
+// GetNamedVariableListAttributesResponse представляет ответ GetNamedVariableListAttributes
+//
+//	GetNamedVariableListAttributes-Response ::= SEQUENCE {
+//	  mmsDeletable   [0] IMPLICIT BOOLEAN,
+//	  listOfVariable [1] IMPLICIT SEQUENCE OF SEQUENCE {
+//	    variableSpecification VariableSpecification,
+//	    alternateAccess [5] IMPLICIT AlternateAccess OPTIONAL
+//	  }
+//	}
+type GetNamedVariableListAttributesResponse struct {
+	InvokeID     uint32
+	MmsDeletable bool
+	// Members - элементы набора в порядке определения на сервере
+	Members []DataSetMember
+}
+
+// Bytes кодирует ответ в confirmed-ResponsePDU (используется серверной стороной и в тестах)
+func (r *GetNamedVariableListAttributesResponse) Bytes() []byte {
+	var list []byte
+	for _, m := range r.Members {
+		item := wrapTL(ber.ContextSpecific0Constructed, encodeObjectName(m.Name))
+		if len(m.AlternateAccess) > 0 {
+			item = append(item, wrapTL(ber.ContextSpecific5Constructed, encodeAccessPath(m.AlternateAccess))...)
+		}
+		list = append(list, wrapTL(ber.SequenceConstructed, item)...)
+	}
+	deletable := byte(0)
+	if r.MmsDeletable {
+		deletable = 0xff
+	}
+	service := wrapTL(ber.ContextSpecific0Primitive, []byte{deletable})
+	service = append(service, wrapTL(ber.ContextSpecific1Constructed, list)...)
+
+	pdu := encodeInvokeID(r.InvokeID)
+	pdu = append(pdu, wrapTL(ber.ContextSpecific12Constructed, service)...)
+	return wrapTL(ber.ContextSpecific1Constructed, pdu)
+}
+
+// encodeAccessPath кодирует путь как одно AlternateAccessSelection:
+// последний шаг - selectAccess, предыдущие - вложенные selectAlternateAccess
+func encodeAccessPath(path []AccessSelection) []byte {
+	if len(path) == 1 {
+		return encodeAccessSelection(path[0], 1)
+	}
+	content := encodeAccessSelection(path[0], 0)
+	content = append(content, wrapTL(ber.SequenceConstructed, encodeAccessPath(path[1:]))...)
+	return wrapTL(ber.ContextSpecific0Constructed, content)
+}
+
+// encodeAccessSelection кодирует шаг доступа; base - номер тега component (0 или 1)
+func encodeAccessSelection(s AccessSelection, base byte) []byte {
+	tag := ber.Tag(0x80 | (base + byte(s.Kind)))
+	switch s.Kind {
+	case AccessComponent:
+		return wrapTL(tag, []byte(s.Component))
+	case AccessIndex:
+		return encodeUnsigned(tag, s.Index)
+	case AccessIndexRange:
+		content := encodeUnsigned(ber.ContextSpecific0Primitive, s.Index)
+		content = append(content, encodeUnsigned(ber.ContextSpecific1Primitive, s.Count)...)
+		return wrapTL(tag|0x20, content)
+	default:
+		return wrapTL(tag, nil)
+	}
+}
+
+// ParseGetNamedVariableListAttributesResponse парсит ответ GetNamedVariableListAttributes
+//
+//	a1 (confirmed-ResponsePDU)
+//	  02 (invokeID)
+//	  ac (getNamedVariableListAttributes)
+//	    80 (mmsDeletable)
+//	    a1 (listOfVariable)
+//	      30 { a0 (name) ObjectName [a5 (alternateAccess)] } ...
+//
+// Для confirmed-ErrorPDU возвращается *ConfirmedError.
+func ParseGetNamedVariableListAttributesResponse(buffer []byte) (*GetNamedVariableListAttributesResponse, error) {
+	if err := checkConfirmedError(buffer); err != nil {
+		return nil, err
+	}
+	content, err := expectTLV(buffer, byte(ber.ContextSpecific1Constructed), "confirmed-ResponsePDU")
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetNamedVariableListAttributesResponse{}
+	var service []byte
+	for bufPos := 0; bufPos < len(content); {
+		tag, value, next, err := decodeTLV(content, bufPos, len(content))
+		if err != nil {
+			return nil, err
+		}
+		switch tag {
+		case byte(ber.Integer):
+			response.InvokeID = ber.DecodeUint32(value, len(value), 0)
+		case byte(ber.ContextSpecific12Constructed):
+			service = value
+		default:
+			return nil, fmt.Errorf("unexpected tag in confirmed-ResponsePDU: 0x%02x", tag)
+		}
+		bufPos = next
+	}
+	if service == nil {
+		return nil, fmt.Errorf("confirmed-ResponsePDU does not contain getNamedVariableListAttributes response")
+	}
+
+	for bufPos := 0; bufPos < len(service); {
+		tag, value, next, err := decodeTLV(service, bufPos, len(service))
+		if err != nil {
+			return nil, err
+		}
+		switch tag {
+		case byte(ber.ContextSpecific0Primitive): // mmsDeletable
+			response.MmsDeletable = len(value) > 0 && value[0] != 0
+		case byte(ber.ContextSpecific1Constructed): // listOfVariable
+			response.Members = []DataSetMember{}
+			for itemPos := 0; itemPos < len(value); {
+				itemTag, item, itemNext, err := decodeTLV(value, itemPos, len(value))
+				if err != nil {
+					return nil, err
+				}
+				if itemTag != byte(ber.SequenceConstructed) {
+					return nil, fmt.Errorf("unexpected listOfVariable item tag: 0x%02x", itemTag)
+				}
+				member, err := parseDataSetMember(item)
+				if err != nil {
+					return nil, fmt.Errorf("member %d: %w", len(response.Members), err)
+				}
+				response.Members = append(response.Members, member)
+				itemPos = itemNext
+			}
+		default:
+			return nil, fmt.Errorf("unexpected tag in getNamedVariableListAttributes response: 0x%02x", tag)
+		}
+		bufPos = next
+	}
+	if response.Members == nil {
+		return nil, fmt.Errorf("getNamedVariableListAttributes response does not contain listOfVariable")
+	}
+
+	return response, nil
+}
+
+// parseDataSetMember разбирает элемент listOfVariable
+func parseDataSetMember(buffer []byte) (DataSetMember, error) {
+	var member DataSetMember
+	hasName := false
+	for bufPos := 0; bufPos < len(buffer); {
+		tag, value, next, err := decodeTLV(buffer, bufPos, len(buffer))
+		if err != nil {
+			return member, err
+		}
+		switch tag {
+		case byte(ber.ContextSpecific0Constructed): // variableSpecification: name
+			nameTag, name, _, err := decodeTLV(value, 0, len(value))
+			if err != nil {
+				return member, fmt.Errorf("failed to decode object name: %w", err)
+			}
+			if member.Name, err = parseObjectName(nameTag, name); err != nil {
+				return member, err
+			}
+			hasName = true
+		case byte(ber.ContextSpecific5Constructed): // alternateAccess
+			accessTag, access, accessNext, err := decodeTLV(value, 0, len(value))
+			if err != nil {
+				return member, fmt.Errorf("failed to decode alternate access: %w", err)
+			}
+			if accessNext != len(value) {
+				return member, fmt.Errorf("alternate access with several selections is not supported")
+			}
+			if member.AlternateAccess, err = parseAccessPath(accessTag, access); err != nil {
+				return member, err
+			}
+		default:
+			return member, fmt.Errorf("unsupported variable specification tag: 0x%02x", tag)
+		}
+		bufPos = next
+	}
+	if !hasName {
+		return member, fmt.Errorf("variable specification does not contain name")
+	}
+	return member, nil
+}
+
+// parseAccessPath разбирает AlternateAccessSelection в последовательность шагов
+func parseAccessPath(tag byte, value []byte) ([]AccessSelection, error) {
+	if tag != byte(ber.ContextSpecific0Constructed) {
+		selection, err := parseAccessSelection(tag, value, 1)
+		if err != nil {
+			return nil, err
+		}
+		return []AccessSelection{selection}, nil
+	}
+
+	// selectAlternateAccess: accessSelection и вложенный AlternateAccess
+	tag, first, next, err := decodeTLV(value, 0, len(value))
+	if err != nil {
+		return nil, err
+	}
+	selection, err := parseAccessSelection(tag, first, 0)
+	if err != nil {
+		return nil, err
+	}
+	nested, err := expectTLV(value[next:], byte(ber.SequenceConstructed), "alternateAccess")
+	if err != nil {
+		return nil, err
+	}
+	tag, inner, innerNext, err := decodeTLV(nested, 0, len(nested))
+	if err != nil {
+		return nil, err
+	}
+	if innerNext != len(nested) {
+		return nil, fmt.Errorf("alternate access with several selections is not supported")
+	}
+	rest, err := parseAccessPath(tag, inner)
+	if err != nil {
+		return nil, err
+	}
+	return append([]AccessSelection{selection}, rest...), nil
+}
+
+// parseAccessSelection разбирает шаг доступа; base - номер тега component (0 или 1)
+func parseAccessSelection(tag byte, value []byte, base byte) (AccessSelection, error) {
+	kind := AccessSelectionKind(tag&0x1f - base)
+	if tag&0xc0 != 0x80 || tag&0x1f < base || kind > AccessAllElements {
+		return AccessSelection{}, fmt.Errorf("unsupported access selection tag: 0x%02x", tag)
+	}
+	selection := AccessSelection{Kind: kind}
+	switch kind {
+	case AccessComponent:
+		selection.Component = string(value)
+	case AccessIndex:
+		selection.Index = ber.DecodeUint32(value, len(value), 0)
+	case AccessIndexRange:
+		for bufPos := 0; bufPos < len(value); {
+			rangeTag, bound, next, err := decodeTLV(value, bufPos, len(value))
+			if err != nil {
+				return selection, err
+			}
+			switch rangeTag {
+			case byte(ber.ContextSpecific0Primitive):
+				selection.Index = ber.DecodeUint32(bound, len(bound), 0)
+			case byte(ber.ContextSpecific1Primitive):
+				selection.Count = ber.DecodeUint32(bound, len(bound), 0)
+			}
+			bufPos = next
+		}
+	}
+	return selection, nil
+}
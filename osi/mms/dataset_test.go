@@ -0,0 +1,92 @@
+package mms
+
+import (
+	"testing"
+
+	"github.com/slonegd/go61850/osi/mms/variant"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadDataSetRequestBytes(t *testing.T) {
+	request := &ReadDataSetRequest{InvokeID: 5, DataSet: ObjectName{DomainID: "LD", ItemID: "DS"}}
+	got, err := request.Bytes()
+	assert.NoError(t, err)
+	assert.Equal(t, parseHexString("a0 13 02 01 05 a4 0e a1 0c a1 0a a1 08 1a 02 4c44 1a 02 4453"), got)
+
+	_, err = (&ReadDataSetRequest{}).Bytes()
+	assert.Error(t, err)
+}
+
+func TestMapDataSetResults(t *testing.T) {
+	members := []DataSetMember{
+		{Name: ObjectName{DomainID: "LD", ItemID: "GGIO1$ST$Ind1"}},
+		{Name: ObjectName{DomainID: "LD", ItemID: "GGIO1$ST$Ind2"}},
+	}
+	results := []AccessResult{
+		{Success: true, Value: variant.NewBoolVariant(true)},
+		{Error: &DataAccessError{ErrorCode: ObjectNonExistent}},
+	}
+	got, err := MapDataSetResults(members, results)
+	assert.NoError(t, err)
+	assert.Equal(t, []DataSetValue{
+		{Member: members[0], Result: results[0]},
+		{Member: members[1], Result: results[1]},
+	}, got)
+
+	// Число результатов не совпадает с числом элементов
+	_, err = MapDataSetResults(members, results[:1])
+	assert.EqualError(t, err, "data set has 2 members, read returned 1 results")
+}
+
+func TestGetNamedVariableListAttributesRequestBytes(t *testing.T) {
+	request := &GetNamedVariableListAttributesRequest{InvokeID: 7, DataSet: ObjectName{DomainID: "LD", ItemID: "DS"}}
+	got, err := request.Bytes()
+	assert.NoError(t, err)
+	assert.Equal(t, parseHexString("a0 0f 02 01 07 ac 0a a1 08 1a 02 4c44 1a 02 4453"), got)
+}
+
+func TestParseGetNamedVariableListAttributesResponse(t *testing.T) {
+	// Второй элемент - PhV(0)$cVal: selectAlternateAccess с index и вложенным component
+	buffer := parseHexString("a1 4a 02 01 07 ac 45 80 01 00 a1 40" +
+		"30 17 a0 15 a1 13 1a 02 4c44 1a 0d 4747494f3124535424496e6431" +
+		"30 25 a0 14 a1 12 1a 02 4c44 1a 0c 4d4d58553124 4d5824 506856" +
+		"a5 0d a0 0b 81 01 00 30 06 81 04 6356616c")
+	want := &GetNamedVariableListAttributesResponse{
+		InvokeID: 7,
+		Members: []DataSetMember{
+			{Name: ObjectName{DomainID: "LD", ItemID: "GGIO1$ST$Ind1"}},
+			{
+				Name: ObjectName{DomainID: "LD", ItemID: "MMXU1$MX$PhV"},
+				AlternateAccess: []AccessSelection{
+					{Kind: AccessIndex},
+					{Kind: AccessComponent, Component: "cVal"},
+				},
+			},
+		},
+	}
+	got, err := ParseGetNamedVariableListAttributesResponse(buffer)
+	assert.NoError(t, err)
+	assert.Equal(t, want, got)
+	assert.Equal(t, "LD/GGIO1$ST$Ind1", got.Members[0].String())
+	assert.Equal(t, "LD/MMXU1$MX$PhV(0)$cVal", got.Members[1].String())
+	assert.Equal(t, buffer, want.Bytes())
+
+	// Диапазон и все элементы массива кодируются и разбираются обратно
+	want = &GetNamedVariableListAttributesResponse{
+		InvokeID:     8,
+		MmsDeletable: true,
+		Members: []DataSetMember{
+			{Name: ObjectName{ItemID: "Arr"}, AlternateAccess: []AccessSelection{{Kind: AccessIndexRange, Index: 2, Count: 3}}},
+			{Name: ObjectName{ItemID: "Arr"}, AlternateAccess: []AccessSelection{{Kind: AccessAllElements}}},
+		},
+	}
+	got, err = ParseGetNamedVariableListAttributesResponse(want.Bytes())
+	assert.NoError(t, err)
+	assert.Equal(t, want, got)
+	assert.Equal(t, "Arr(2..4)", got.Members[0].String())
+	assert.Equal(t, "Arr(*)", got.Members[1].String())
+
+	// Ответ без listOfVariable
+	_, err = ParseGetNamedVariableListAttributesResponse(parseHexString("a1 08 02 01 07 ac 03 80 01 00"))
+	assert.Error(t, err)
+}
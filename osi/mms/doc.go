@@ -0,0 +1,33 @@
+// Package mms реализует PDU протокола MMS (ISO 9506) в объёме IEC 61850-8-1:
+// кодирование запросов и разбор ответов на стороне клиента, разбор запросов
+// и кодирование ответов на стороне сервера, а также транспорт обеих сторон
+// (Client и Server) поверх нижних уровней стека OSI.
+//
+// # Стек протоколов
+//
+// MMS PDU передаётся через уровни, каждый из которых реализован отдельным пакетом:
+//
+//	уровень        пакет             установление ассоциации     фаза данных
+//	MMS            osi/mms           initiate-Request/Response   confirmed-Request/Response, informationReport
+//	ACSE           osi/acse          AARQ / AARE                 -
+//	Presentation   osi/presentation  CP-type / CPA-PPDU          fully-encoded-data (контекст 3)
+//	Session        osi/session       CONNECT / ACCEPT            Give Tokens + DATA TRANSFER
+//	COTP           osi/cotp          CR / CC, затем DT           DT (с фрагментацией)
+//	TPKT, TCP      osi/cotp          RFC 1006, порт 102
+//
+// При установлении ассоциации MMS PDU последовательно оборачивается всеми уровнями
+// (NewAssociateRequest, NewAssociateResponse), в фазе данных ACSE пропускается.
+// Client.SendMmsPdu и Client.ReceiveAndParseMmsResponse проходят стек в обе
+// стороны; Server принимает соединения и передаёт confirmed-RequestPDU
+// обработчикам Handler.
+//
+// # PDU
+//
+// Типы запросов (ReadRequest, WriteRequest, GetNameListRequest и др.) кодируются
+// методом Bytes и разбираются функциями Parse*Request; ответы - наоборот.
+// Отказ сервера в выполнении сервиса возвращается как *ConfirmedError
+// (с *ServiceError), отказ в доступе к переменной - как *DataAccessError.
+// ParsePDU определяет вариант принятого PDU и возвращает его типом PDU;
+// Client.ReceivePDU принимает и разбирает PDU для ручного управления приёмом.
+// Значения данных MMS представлены пакетом osi/mms/variant.
+package mms
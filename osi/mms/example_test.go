@@ -0,0 +1,56 @@
+package mms_test
+
+import (
+	"fmt"
+
+	"github.com/slonegd/go61850/osi/mms"
+)
+
+// Запрос ассоциации проходит все уровни стека: каждый уровень вкладывает PDU
+// верхнего уровня в свои пользовательские данные
+func ExampleNewAssociateRequest() {
+	r := mms.NewAssociateRequest(mms.NewInitiateRequest().Bytes())
+	for _, layer := range []struct {
+		name string
+		pdu  []byte
+	}{
+		{"Session", r.Session},
+		{"Presentation", r.Presentation},
+		{"ACSE", r.ACSE},
+		{"MMS", r.MMS},
+	} {
+		fmt.Printf("%-12s %3d bytes, tag %02x\n", layer.name, len(layer.pdu), layer.pdu[0])
+	}
+
+	initiate, err := mms.ParseAssociateRequest(r.Packet())
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(initiate.LocalDetailCalling)
+	// Output:
+	// Session      180 bytes, tag 0d
+	// Presentation 156 bytes, tag 31
+	// ACSE          87 bytes, tag 60
+	// MMS           40 bytes, tag a8
+	// 65000
+}
+
+// Клиент кодирует запрос методом Bytes, сервер разбирает его Parse*Request
+func ExampleParseReadRequest() {
+	request, err := mms.NewReadRequest("simpleIOGenericIO/GGIO1.AnIn1.mag.f", mms.FCMX)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	request.InvokeID = 7
+
+	parsed, err := mms.ParseReadRequest(request.Bytes())
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(parsed.InvokeID, parsed.DomainID, parsed.ItemID)
+	// Output:
+	// 7 simpleIOGenericIO GGIO1$MX$AnIn1$mag$f
+}
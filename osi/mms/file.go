@@ -0,0 +1,501 @@
+package mms
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/slonegd/go61850/internal/ber"
+)
+
+// FileAttributes представляет атрибуты файла
+//
+//	FileAttributes ::= SEQUENCE {
+//	  sizeOfFile   [0] IMPLICIT Unsigned32,
+//	  lastModified [1] IMPLICIT GeneralizedTime OPTIONAL
+//	}
+type FileAttributes struct {
+	Size uint32
+	// LastModified - время изменения; нулевое, если сервер его не передал
+	LastModified time.Time
+}
+
+// DirectoryEntry представляет элемент ответа FileDirectory
+type DirectoryEntry struct {
+	Name string
+	FileAttributes
+}
+
+// generalizedTimeLayout - формат GeneralizedTime, используемый libIEC61850
+const generalizedTimeLayout = "20060102150405.000Z"
+
+// encodeFileName кодирует содержимое FileName ::= SEQUENCE OF GraphicString.
+// Имя передаётся одним элементом, как это делают серверы IEC 61850.
+func encodeFileName(name string) []byte {
+	return wrapTL(ber.GraphicString, []byte(name))
+}
+
+// parseFileName разбирает содержимое FileName, соединяя элементы
+func parseFileName(buffer []byte) (string, error) {
+	var b strings.Builder
+	for bufPos := 0; bufPos < len(buffer); {
+		tag, value, next, err := decodeTLV(buffer, bufPos, len(buffer))
+		if err != nil {
+			return "", err
+		}
+		if tag != byte(ber.GraphicString) && tag != byte(ber.VisibleString) {
+			return "", fmt.Errorf("unexpected file name tag: 0x%02x", tag)
+		}
+		b.Write(value)
+		bufPos = next
+	}
+	return b.String(), nil
+}
+
+// encodeFileAttributes кодирует содержимое FileAttributes
+func encodeFileAttributes(a FileAttributes) []byte {
+	content := encodeUnsigned(ber.ContextSpecific0Primitive, a.Size)
+	if !a.LastModified.IsZero() {
+		lastModified := a.LastModified.UTC().Format(generalizedTimeLayout)
+		content = append(content, wrapTL(ber.ContextSpecific1Primitive, []byte(lastModified))...)
+	}
+	return content
+}
+
+// parseFileAttributes разбирает содержимое FileAttributes
+func parseFileAttributes(buffer []byte) (FileAttributes, error) {
+	var a FileAttributes
+	for bufPos := 0; bufPos < len(buffer); {
+		tag, value, next, err := decodeTLV(buffer, bufPos, len(buffer))
+		if err != nil {
+			return a, err
+		}
+		switch tag {
+		case byte(ber.ContextSpecific0Primitive): // sizeOfFile
+			if len(value) < 1 || len(value) > 5 {
+				return a, fmt.Errorf("invalid sizeOfFile length: %d", len(value))
+			}
+			a.Size = ber.DecodeUint32(value, len(value), 0)
+		case byte(ber.ContextSpecific1Primitive): // lastModified
+			a.LastModified, err = parseGeneralizedTime(string(value))
+			if err != nil {
+				return a, err
+			}
+		default:
+			return a, fmt.Errorf("unexpected tag in fileAttributes: 0x%02x", tag)
+		}
+		bufPos = next
+	}
+	return a, nil
+}
+
+// parseGeneralizedTime разбирает GeneralizedTime в UTC с долями секунды или без
+func parseGeneralizedTime(value string) (time.Time, error) {
+	for _, layout := range []string{generalizedTimeLayout, "20060102150405Z", "20060102150405.999999999Z"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid GeneralizedTime: %q", value)
+}
+
+// encodeConfirmedRequest кодирует confirmed-RequestPDU с аргументом сервиса service
+func encodeConfirmedRequest(invokeID uint32, service ConfirmedService, constructed bool, argument []byte) []byte {
+	pdu := encodeInvokeID(invokeID)
+	pdu = append(pdu, wrapServiceTL(service, constructed, argument)...)
+	return wrapTL(ber.ContextSpecific0Constructed, pdu)
+}
+
+// parseServiceResponse проверяет, что buffer - ответ сервиса service, и возвращает
+// содержимое ConfirmedServiceResponse. Для confirmed-ErrorPDU возвращается *ConfirmedError.
+func parseServiceResponse(buffer []byte, service ConfirmedService) ([]byte, error) {
+	if err := checkConfirmedError(buffer); err != nil {
+		return nil, err
+	}
+	content, err := expectTLV(buffer, byte(ber.ContextSpecific1Constructed), "confirmed-ResponsePDU")
+	if err != nil {
+		return nil, err
+	}
+
+	var value []byte
+	for bufPos := 0; bufPos < len(content); {
+		if content[bufPos] == byte(ber.Integer) {
+			_, _, next, err := decodeTLV(content, bufPos, len(content))
+			if err != nil {
+				return nil, err
+			}
+			bufPos = next
+			continue
+		}
+		number, serviceValue, next, err := decodeServiceTLV(content, bufPos, len(content))
+		if err != nil {
+			return nil, err
+		}
+		if number != service {
+			return nil, fmt.Errorf("unexpected service in confirmed-ResponsePDU: %s", number)
+		}
+		value = serviceValue
+		bufPos = next
+	}
+	if value == nil {
+		return nil, fmt.Errorf("confirmed-ResponsePDU does not contain %s response", service)
+	}
+	return value, nil
+}
+
+// NullResponse кодирует ConfirmedServiceResponse с пустым результатом
+// (obtainFile, fileClose и другие сервисы с ответом NULL)
+func NullResponse(service ConfirmedService) []byte {
+	return wrapServiceTL(service, false, nil)
+}
+
+// ParseNullResponse проверяет ответ сервиса service с результатом NULL
+func ParseNullResponse(buffer []byte, service ConfirmedService) error {
+	_, err := parseServiceResponse(buffer, service)
+	return err
+}
+
+// ObtainFileRequest представляет MMS ObtainFile Request PDU: клиент просит сервер
+// получить файл SourceFile и сохранить его как DestinationFile. Сервер читает файл
+// у клиента запросами FileOpen, FileRead и FileClose, затем отвечает на ObtainFile.
+//
+//	ObtainFile-Request ::= SEQUENCE {
+//	  sourceFileServer [0] IMPLICIT ApplicationReference OPTIONAL,
+//	  sourceFile       [1] IMPLICIT FileName,
+//	  destinationFile  [2] IMPLICIT FileName
+//	}
+type ObtainFileRequest struct {
+	InvokeID        uint32
+	SourceFile      string
+	DestinationFile string
+}
+
+// Bytes кодирует ObtainFileRequest: a0 { 02 invokeID, bf 2e { a1 FileName, a2 FileName } }
+func (r *ObtainFileRequest) Bytes() []byte {
+	argument := wrapTL(ber.ContextSpecific1Constructed, encodeFileName(r.SourceFile))
+	argument = append(argument, wrapTL(ber.ContextSpecific2Constructed, encodeFileName(r.DestinationFile))...)
+	return encodeConfirmedRequest(r.InvokeID, ServiceObtainFile, true, argument)
+}
+
+// ParseObtainFileRequest разбирает аргумент ObtainFile из ConfirmedRequest.Argument
+func ParseObtainFileRequest(argument []byte) (*ObtainFileRequest, error) {
+	request := &ObtainFileRequest{}
+	for bufPos := 0; bufPos < len(argument); {
+		tag, value, next, err := decodeTLV(argument, bufPos, len(argument))
+		if err != nil {
+			return nil, err
+		}
+		switch tag {
+		case byte(ber.ContextSpecific0Constructed): // sourceFileServer не поддерживается
+		case byte(ber.ContextSpecific1Constructed):
+			request.SourceFile, err = parseFileName(value)
+		case byte(ber.ContextSpecific2Constructed):
+			request.DestinationFile, err = parseFileName(value)
+		default:
+			return nil, fmt.Errorf("unexpected tag in obtainFile request: 0x%02x", tag)
+		}
+		if err != nil {
+			return nil, err
+		}
+		bufPos = next
+	}
+	return request, nil
+}
+
+// FileOpenRequest представляет MMS FileOpen Request PDU
+//
+//	FileOpen-Request ::= SEQUENCE {
+//	  fileName        [0] IMPLICIT FileName,
+//	  initialPosition [1] IMPLICIT Unsigned32
+//	}
+type FileOpenRequest struct {
+	InvokeID        uint32
+	FileName        string
+	InitialPosition uint32
+}
+
+// Bytes кодирует FileOpenRequest: a0 { 02 invokeID, bf 48 { a0 FileName, 81 position } }
+func (r *FileOpenRequest) Bytes() []byte {
+	argument := wrapTL(ber.ContextSpecific0Constructed, encodeFileName(r.FileName))
+	argument = append(argument, encodeUnsigned(ber.ContextSpecific1Primitive, r.InitialPosition)...)
+	return encodeConfirmedRequest(r.InvokeID, ServiceFileOpen, true, argument)
+}
+
+// ParseFileOpenRequest разбирает аргумент FileOpen из ConfirmedRequest.Argument
+func ParseFileOpenRequest(argument []byte) (*FileOpenRequest, error) {
+	request := &FileOpenRequest{}
+	for bufPos := 0; bufPos < len(argument); {
+		tag, value, next, err := decodeTLV(argument, bufPos, len(argument))
+		if err != nil {
+			return nil, err
+		}
+		switch tag {
+		case byte(ber.ContextSpecific0Constructed):
+			request.FileName, err = parseFileName(value)
+		case byte(ber.ContextSpecific1Primitive):
+			if len(value) < 1 || len(value) > 5 {
+				return nil, fmt.Errorf("invalid initialPosition length: %d", len(value))
+			}
+			request.InitialPosition = ber.DecodeUint32(value, len(value), 0)
+		default:
+			return nil, fmt.Errorf("unexpected tag in fileOpen request: 0x%02x", tag)
+		}
+		if err != nil {
+			return nil, err
+		}
+		bufPos = next
+	}
+	return request, nil
+}
+
+// FileOpenResponse представляет ответ FileOpen
+//
+//	FileOpen-Response ::= SEQUENCE {
+//	  frsmID         [0] IMPLICIT Integer32,
+//	  fileAttributes [1] IMPLICIT FileAttributes
+//	}
+type FileOpenResponse struct {
+	FrsmID     int32
+	Attributes FileAttributes
+}
+
+// Bytes кодирует ConfirmedServiceResponse fileOpen: bf 48 { 80 frsmID, a1 FileAttributes }
+func (r *FileOpenResponse) Bytes() []byte {
+	content := wrapTL(ber.ContextSpecific0Primitive, encodeInt(r.FrsmID))
+	content = append(content, wrapTL(ber.ContextSpecific1Constructed, encodeFileAttributes(r.Attributes))...)
+	return wrapServiceTL(ServiceFileOpen, true, content)
+}
+
+// ParseFileOpenResponse парсит MMS FileOpen Response PDU
+func ParseFileOpenResponse(buffer []byte) (*FileOpenResponse, error) {
+	service, err := parseServiceResponse(buffer, ServiceFileOpen)
+	if err != nil {
+		return nil, err
+	}
+	response := &FileOpenResponse{}
+	for bufPos := 0; bufPos < len(service); {
+		tag, value, next, err := decodeTLV(service, bufPos, len(service))
+		if err != nil {
+			return nil, err
+		}
+		switch tag {
+		case byte(ber.ContextSpecific0Primitive):
+			response.FrsmID = decodeInt(value)
+		case byte(ber.ContextSpecific1Constructed):
+			response.Attributes, err = parseFileAttributes(value)
+			if err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("unexpected tag in fileOpen response: 0x%02x", tag)
+		}
+		bufPos = next
+	}
+	return response, nil
+}
+
+// FileReadRequest представляет MMS FileRead Request PDU
+//
+//	FileRead-Request ::= Integer32 -- frsmID
+type FileReadRequest struct {
+	InvokeID uint32
+	FrsmID   int32
+}
+
+// Bytes кодирует FileReadRequest: a0 { 02 invokeID, 9f 49 frsmID }
+func (r *FileReadRequest) Bytes() []byte {
+	return encodeConfirmedRequest(r.InvokeID, ServiceFileRead, false, encodeInt(r.FrsmID))
+}
+
+// ParseFrsmID разбирает аргумент FileRead и FileClose (Integer32 frsmID)
+// из ConfirmedRequest.Argument
+func ParseFrsmID(argument []byte) (int32, error) {
+	if len(argument) < 1 || len(argument) > 4 {
+		return 0, fmt.Errorf("invalid frsmID length: %d", len(argument))
+	}
+	return decodeInt(argument), nil
+}
+
+// FileReadResponse представляет ответ FileRead
+//
+//	FileRead-Response ::= SEQUENCE {
+//	  fileData    [0] IMPLICIT OCTET STRING,
+//	  moreFollows [1] IMPLICIT BOOLEAN DEFAULT TRUE
+//	}
+type FileReadResponse struct {
+	Data        []byte
+	MoreFollows bool
+}
+
+// Bytes кодирует ConfirmedServiceResponse fileRead: bf 49 { 80 data, 81 moreFollows }
+func (r *FileReadResponse) Bytes() []byte {
+	content := wrapTL(ber.ContextSpecific0Primitive, r.Data)
+	content = append(content, wrapTL(ber.ContextSpecific1Primitive, encodeBool(r.MoreFollows))...)
+	return wrapServiceTL(ServiceFileRead, true, content)
+}
+
+// ParseFileReadResponse парсит MMS FileRead Response PDU
+func ParseFileReadResponse(buffer []byte) (*FileReadResponse, error) {
+	service, err := parseServiceResponse(buffer, ServiceFileRead)
+	if err != nil {
+		return nil, err
+	}
+	response := &FileReadResponse{MoreFollows: true}
+	for bufPos := 0; bufPos < len(service); {
+		tag, value, next, err := decodeTLV(service, bufPos, len(service))
+		if err != nil {
+			return nil, err
+		}
+		switch tag {
+		case byte(ber.ContextSpecific0Primitive):
+			response.Data = append([]byte{}, value...)
+		case byte(ber.ContextSpecific1Primitive):
+			response.MoreFollows = decodeBool(value)
+		default:
+			return nil, fmt.Errorf("unexpected tag in fileRead response: 0x%02x", tag)
+		}
+		bufPos = next
+	}
+	return response, nil
+}
+
+// FileCloseRequest представляет MMS FileClose Request PDU
+//
+//	FileClose-Request ::= Integer32 -- frsmID
+type FileCloseRequest struct {
+	InvokeID uint32
+	FrsmID   int32
+}
+
+// Bytes кодирует FileCloseRequest: a0 { 02 invokeID, 9f 4a frsmID }
+func (r *FileCloseRequest) Bytes() []byte {
+	return encodeConfirmedRequest(r.InvokeID, ServiceFileClose, false, encodeInt(r.FrsmID))
+}
+
+// FileDirectoryRequest представляет MMS FileDirectory Request PDU
+//
+//	FileDirectory-Request ::= SEQUENCE {
+//	  fileSpecification [0] IMPLICIT FileName OPTIONAL,
+//	  continueAfter     [1] IMPLICIT FileName OPTIONAL
+//	}
+type FileDirectoryRequest struct {
+	InvokeID uint32
+	// FileSpecification - каталог или файл; пустая строка - корень хранилища
+	FileSpecification string
+	// ContinueAfter - последнее полученное имя при продолжении списка
+	ContinueAfter string
+}
+
+// Bytes кодирует FileDirectoryRequest: a0 { 02 invokeID, bf 4d { a0 FileName, a1 FileName } }
+func (r *FileDirectoryRequest) Bytes() []byte {
+	var argument []byte
+	if r.FileSpecification != "" {
+		argument = wrapTL(ber.ContextSpecific0Constructed, encodeFileName(r.FileSpecification))
+	}
+	if r.ContinueAfter != "" {
+		argument = append(argument, wrapTL(ber.ContextSpecific1Constructed, encodeFileName(r.ContinueAfter))...)
+	}
+	return encodeConfirmedRequest(r.InvokeID, ServiceFileDirectory, true, argument)
+}
+
+// FileDirectoryResponse представляет ответ FileDirectory
+//
+//	FileDirectory-Response ::= SEQUENCE {
+//	  listOfDirectoryEntry [0] SEQUENCE OF DirectoryEntry,
+//	  moreFollows          [1] IMPLICIT BOOLEAN DEFAULT FALSE
+//	}
+//
+//	DirectoryEntry ::= SEQUENCE {
+//	  fileName       [0] IMPLICIT FileName,
+//	  fileAttributes [1] IMPLICIT FileAttributes
+//	}
+type FileDirectoryResponse struct {
+	Entries     []DirectoryEntry
+	MoreFollows bool
+}
+
+// Bytes кодирует ConfirmedServiceResponse fileDirectory:
+// bf 4d { a0 { 30 { 30 { a0 FileName, a1 FileAttributes } ... } }, 81 moreFollows }
+func (r *FileDirectoryResponse) Bytes() []byte {
+	var entries []byte
+	for _, entry := range r.Entries {
+		content := wrapTL(ber.ContextSpecific0Constructed, encodeFileName(entry.Name))
+		content = append(content, wrapTL(ber.ContextSpecific1Constructed, encodeFileAttributes(entry.FileAttributes))...)
+		entries = append(entries, wrapTL(ber.SequenceConstructed, content)...)
+	}
+	content := wrapTL(ber.ContextSpecific0Constructed, wrapTL(ber.SequenceConstructed, entries))
+	if r.MoreFollows {
+		content = append(content, wrapTL(ber.ContextSpecific1Primitive, encodeBool(true))...)
+	}
+	return wrapServiceTL(ServiceFileDirectory, true, content)
+}
+
+// ParseFileDirectoryResponse парсит MMS FileDirectory Response PDU
+func ParseFileDirectoryResponse(buffer []byte) (*FileDirectoryResponse, error) {
+	service, err := parseServiceResponse(buffer, ServiceFileDirectory)
+	if err != nil {
+		return nil, err
+	}
+	response := &FileDirectoryResponse{Entries: []DirectoryEntry{}}
+	for bufPos := 0; bufPos < len(service); {
+		tag, value, next, err := decodeTLV(service, bufPos, len(service))
+		if err != nil {
+			return nil, err
+		}
+		switch tag {
+		case byte(ber.ContextSpecific0Constructed):
+			// Тег [0] явный и содержит SEQUENCE OF; встречается и кодирование
+			// с элементами DirectoryEntry непосредственно в [0]
+			list, err := expectTLV(value, byte(ber.SequenceConstructed), "listOfDirectoryEntry")
+			if err == nil && (len(list) == 0 || list[0] == byte(ber.SequenceConstructed)) {
+				value = list
+			}
+			response.Entries, err = parseDirectoryEntries(value)
+			if err != nil {
+				return nil, err
+			}
+		case byte(ber.ContextSpecific1Primitive):
+			response.MoreFollows = decodeBool(value)
+		default:
+			return nil, fmt.Errorf("unexpected tag in fileDirectory response: 0x%02x", tag)
+		}
+		bufPos = next
+	}
+	return response, nil
+}
+
+// parseDirectoryEntries разбирает последовательность DirectoryEntry
+func parseDirectoryEntries(buffer []byte) ([]DirectoryEntry, error) {
+	entries := []DirectoryEntry{}
+	for bufPos := 0; bufPos < len(buffer); {
+		tag, value, next, err := decodeTLV(buffer, bufPos, len(buffer))
+		if err != nil {
+			return nil, err
+		}
+		if tag != byte(ber.SequenceConstructed) {
+			return nil, fmt.Errorf("unexpected directory entry tag: 0x%02x", tag)
+		}
+		var entry DirectoryEntry
+		for entryPos := 0; entryPos < len(value); {
+			tag, field, entryNext, err := decodeTLV(value, entryPos, len(value))
+			if err != nil {
+				return nil, err
+			}
+			switch tag {
+			case byte(ber.ContextSpecific0Constructed):
+				entry.Name, err = parseFileName(field)
+			case byte(ber.ContextSpecific1Constructed):
+				entry.FileAttributes, err = parseFileAttributes(field)
+			default:
+				return nil, fmt.Errorf("unexpected tag in directory entry: 0x%02x", tag)
+			}
+			if err != nil {
+				return nil, err
+			}
+			entryPos = entryNext
+		}
+		entries = append(entries, entry)
+		bufPos = next
+	}
+	return entries, nil
+}
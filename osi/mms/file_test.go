@@ -0,0 +1,109 @@
+package mms
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestObtainFileRequest(t *testing.T) {
+	request := &ObtainFileRequest{InvokeID: 4, SourceFile: "a.cid", DestinationFile: "b.cid"}
+	buffer := request.Bytes()
+	assert.Equal(t, parseHexString("a0 18 020104 bf2e 12 a107 1905 612e636964 a207 1905 622e636964"), buffer)
+
+	// Высокий номер сервиса (46) разбирается ParseConfirmedRequest
+	confirmed, err := ParseConfirmedRequest(buffer)
+	assert.NoError(t, err)
+	assert.Equal(t, ServiceObtainFile, confirmed.Service)
+	assert.Equal(t, "obtainFile", confirmed.Service.String())
+	parsed, err := ParseObtainFileRequest(confirmed.Argument)
+	assert.NoError(t, err)
+	assert.Equal(t, &ObtainFileRequest{SourceFile: "a.cid", DestinationFile: "b.cid"}, parsed)
+
+	assert.NoError(t, ParseNullResponse(parseHexString("a1 06 020104 9f2e00"), ServiceObtainFile))
+	assert.EqualError(t, ParseNullResponse(parseHexString("a1 06 020104 9f4a00"), ServiceObtainFile),
+		"unexpected service in confirmed-ResponsePDU: fileClose")
+}
+
+func TestFileOpen(t *testing.T) {
+	request := &FileOpenRequest{InvokeID: 1, FileName: "COMTRADE/r.cfg", InitialPosition: 10}
+	confirmed, err := ParseConfirmedRequest(request.Bytes())
+	assert.NoError(t, err)
+	assert.Equal(t, ServiceFileOpen, confirmed.Service)
+	parsed, err := ParseFileOpenRequest(confirmed.Argument)
+	assert.NoError(t, err)
+	assert.Equal(t, &FileOpenRequest{FileName: "COMTRADE/r.cfg", InitialPosition: 10}, parsed)
+
+	modified := time.Date(2024, 1, 2, 3, 4, 5, 600e6, time.UTC)
+	response := &FileOpenResponse{FrsmID: 3, Attributes: FileAttributes{Size: 1000, LastModified: modified}}
+	pdu := (&ConfirmedResponse{InvokeID: 1, Service: response.Bytes()}).Bytes()
+	assert.Equal(t, parseHexString("a1 24 020101 bf48 1e 800103 a119 800203e8 8113 32303234303130323033303430352e3630305a"), pdu)
+	parsedResponse, err := ParseFileOpenResponse(pdu)
+	assert.NoError(t, err)
+	assert.Equal(t, response, parsedResponse)
+}
+
+func TestFileReadClose(t *testing.T) {
+	read := &FileReadRequest{InvokeID: 2, FrsmID: 3}
+	assert.Equal(t, parseHexString("a0 07 020102 9f4901 03"), read.Bytes())
+	confirmed, err := ParseConfirmedRequest(read.Bytes())
+	assert.NoError(t, err)
+	assert.Equal(t, ServiceFileRead, confirmed.Service)
+	frsmID, err := ParseFrsmID(confirmed.Argument)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(3), frsmID)
+
+	response := &FileReadResponse{Data: []byte("abc")}
+	pdu := (&ConfirmedResponse{InvokeID: 2, Service: response.Bytes()}).Bytes()
+	parsed, err := ParseFileReadResponse(pdu)
+	assert.NoError(t, err)
+	assert.Equal(t, response, parsed)
+
+	// moreFollows по умолчанию TRUE
+	parsed, err = ParseFileReadResponse(parseHexString("a1 0a 020102 bf49 04 8002 6162"))
+	assert.NoError(t, err)
+	assert.True(t, parsed.MoreFollows)
+
+	closeRequest := &FileCloseRequest{InvokeID: 5, FrsmID: 3}
+	confirmed, err = ParseConfirmedRequest(closeRequest.Bytes())
+	assert.NoError(t, err)
+	assert.Equal(t, ServiceFileClose, confirmed.Service)
+}
+
+func TestFileDirectory(t *testing.T) {
+	request := &FileDirectoryRequest{InvokeID: 1, FileSpecification: "COMTRADE", ContinueAfter: "a"}
+	assert.Equal(t, parseHexString("a0 17 020101 bf4d 11 a00a 1908 434f4d5452414445 a103 190161"), request.Bytes())
+	assert.Equal(t, parseHexString("a0 06 020101 bf4d 00"), (&FileDirectoryRequest{InvokeID: 1}).Bytes())
+
+	response := &FileDirectoryResponse{
+		Entries: []DirectoryEntry{
+			{Name: "a.cfg", FileAttributes: FileAttributes{Size: 1}},
+			{Name: "b.dat", FileAttributes: FileAttributes{Size: 2, LastModified: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}},
+		},
+		MoreFollows: true,
+	}
+	parsed, err := ParseFileDirectoryResponse((&ConfirmedResponse{InvokeID: 1, Service: response.Bytes()}).Bytes())
+	assert.NoError(t, err)
+	assert.Equal(t, response, parsed)
+
+	// Элементы DirectoryEntry непосредственно в [0], без SEQUENCE OF
+	parsed, err = ParseFileDirectoryResponse(parseHexString("a1 16 020101 bf4d 10 a00e 300c a005 1903 612e63 a103 800101"))
+	assert.NoError(t, err)
+	assert.Equal(t, []DirectoryEntry{{Name: "a.c", FileAttributes: FileAttributes{Size: 1}}}, parsed.Entries)
+	assert.False(t, parsed.MoreFollows)
+}
+
+func TestParseConfirmedError(t *testing.T) {
+	buffer := (&ConfirmedError{InvokeID: 9, ServiceError: ServiceError{Class: ErrorClassFile, Code: FileErrorFileNonExistent}}).Bytes()
+	assert.True(t, IsConfirmedError(buffer))
+	confirmedError, err := ParseConfirmedError(buffer)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(9), confirmedError.InvokeID)
+	assert.Equal(t, ServiceError{Class: ErrorClassFile, Code: FileErrorFileNonExistent}, confirmedError.ServiceError)
+
+	_, err = ParseFileOpenResponse(buffer)
+	var serviceError *ServiceError
+	assert.ErrorAs(t, err, &serviceError)
+	assert.Equal(t, FileErrorFileNonExistent, serviceError.Code)
+}
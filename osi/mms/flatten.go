@@ -0,0 +1,79 @@
+package mms
+
+import (
+	"fmt"
+
+	"github.com/slonegd/go61850/osi/mms/variant"
+)
+
+// FlattenValue раскладывает значение value составного типа spec (ответ GetVariableAccessAttributes)
+// на простые значения с ключами-путями: компоненты структур разделяются точкой ("mag.f", "q", "t"),
+// элементы массивов обозначаются индексом ("phsA.cVal(0)"). Значение простого типа
+// возвращается с пустым ключом.
+//
+// Компонент без типа в spec считается простым и передаётся целиком.
+// Если число элементов значения не совпадает со spec, возвращается ошибка.
+func FlattenValue(spec *TypeSpecification, value *variant.Variant) (map[string]*variant.Variant, error) {
+	values := map[string]*variant.Variant{}
+	if err := flattenValue(values, "", spec, value); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+func flattenValue(values map[string]*variant.Variant, path string, spec *TypeSpecification, value *variant.Variant) error {
+	if spec == nil || (spec.Type != TypeSpecStructure && spec.Type != TypeSpecArray) {
+		values[path] = value
+		return nil
+	}
+	if value.Type() != variant.Structure {
+		return fmt.Errorf("%s: expected %s, got %s", pathName(path), typeSpecName(spec), value.Type())
+	}
+	elements := value.Structure()
+
+	if spec.Type == TypeSpecArray {
+		if spec.Array == nil {
+			return fmt.Errorf("%s: array without element type", pathName(path))
+		}
+		for i, element := range elements {
+			if err := flattenValue(values, fmt.Sprintf("%s(%d)", path, i), spec.Array.ElementType, element); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	var components []ComponentSpec
+	if spec.Structure != nil {
+		components = spec.Structure.Components
+	}
+	if len(components) != len(elements) {
+		return fmt.Errorf("%s: type has %d components, value has %d", pathName(path), len(components), len(elements))
+	}
+	for i, component := range components {
+		name := component.Name
+		if path != "" {
+			name = path + "." + name
+		}
+		if err := flattenValue(values, name, component.Type, elements[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pathName возвращает путь для сообщения об ошибке
+func pathName(path string) string {
+	if path == "" {
+		return "value"
+	}
+	return path
+}
+
+// typeSpecName возвращает название составного типа для сообщения об ошибке
+func typeSpecName(spec *TypeSpecification) string {
+	if spec.Type == TypeSpecArray {
+		return "array"
+	}
+	return "structure"
+}
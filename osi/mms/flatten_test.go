@@ -0,0 +1,51 @@
+package mms
+
+import (
+	"testing"
+	"time"
+
+	"github.com/slonegd/go61850/osi/mms/variant"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlattenValue(t *testing.T) {
+	// AnIn: mag.f, q, t; тип f не указан (простой компонент)
+	spec := &TypeSpecification{Type: TypeSpecStructure, Structure: &StructureTypeSpec{Components: []ComponentSpec{
+		{Name: "mag", Type: &TypeSpecification{Type: TypeSpecStructure, Structure: &StructureTypeSpec{Components: []ComponentSpec{
+			{Name: "f"},
+		}}}},
+		{Name: "q", Type: &TypeSpecification{Type: TypeSpecBitString, BitStringSize: 13}},
+		{Name: "t", Type: &TypeSpecification{Type: TypeSpecUTCTime}},
+		{Name: "vals", Type: &TypeSpecification{Type: TypeSpecArray, Array: &ArrayTypeSpec{
+			ElementCount: 2, ElementType: &TypeSpecification{Type: TypeSpecInteger, IntegerSize: 32},
+		}}},
+	}}}
+	f := variant.NewFloat32Variant(1.5)
+	q := variant.NewBitStringVariant([]byte{0, 0}, 13)
+	ts := variant.NewUTCTimeVariant(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+	value := variant.NewStructureVariant([]*variant.Variant{
+		variant.NewStructureVariant([]*variant.Variant{f}), q, ts,
+		variant.NewStructureVariant([]*variant.Variant{variant.NewInt32Variant(1), variant.NewInt32Variant(2)}),
+	})
+
+	values, err := FlattenValue(spec, value)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]*variant.Variant{
+		"mag.f":   f,
+		"q":       q,
+		"t":       ts,
+		"vals(0)": variant.NewInt32Variant(1),
+		"vals(1)": variant.NewInt32Variant(2),
+	}, values)
+
+	// Простое значение
+	values, err = FlattenValue(&TypeSpecification{Type: TypeSpecFloatingPoint}, f)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]*variant.Variant{"": f}, values)
+
+	// Значение не соответствует типу
+	_, err = FlattenValue(spec, variant.NewStructureVariant([]*variant.Variant{f}))
+	assert.EqualError(t, err, "value: type has 4 components, value has 1")
+	_, err = FlattenValue(spec, variant.NewStructureVariant([]*variant.Variant{f, q, ts, f}))
+	assert.EqualError(t, err, "mag: expected structure, got float32")
+}
@@ -0,0 +1,101 @@
+package mms
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// fields - упорядоченный список полей PDU для String и Format.
+// Вывод стабилен и разбирается как key=value: значения с пробелами,
+// кавычками или знаком "=" заключаются в кавычки (strconv.Quote).
+//
+// Компактный формат (%v, %s, String) - одна строка:
+//
+//	ReadResponse{InvokeID=1 Result[0]=float32(4.2)}
+//
+// Развёрнутый формат (%+v) - поле на строку:
+//
+//	ReadResponse{
+//	  InvokeID=1
+//	  Result[0]=float32(4.2)
+//	}
+type fields struct {
+	name   string
+	keys   []string
+	values []string
+}
+
+func newFields(name string) *fields {
+	return &fields{name: name}
+}
+
+// add добавляет поле key со значением value
+func (f *fields) add(key, value string) {
+	if value == "" || strings.ContainsAny(value, " \t\n\"=") {
+		value = strconv.Quote(value)
+	}
+	f.keys = append(f.keys, key)
+	f.values = append(f.values, value)
+}
+
+// addUint добавляет числовое поле
+func (f *fields) addUint(key string, value uint32) {
+	f.add(key, strconv.FormatUint(uint64(value), 10))
+}
+
+// addList добавляет список значений в виде [a,b,c]
+func addList[T fmt.Stringer](f *fields, key string, list []T) {
+	names := make([]string, len(list))
+	for i, item := range list {
+		names[i] = item.String()
+	}
+	f.add(key, "["+strings.Join(names, ",")+"]")
+}
+
+// compact возвращает поля одной строкой
+func (f *fields) compact() string {
+	var b strings.Builder
+	b.WriteString(f.name)
+	b.WriteByte('{')
+	for i, key := range f.keys {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(key)
+		b.WriteByte('=')
+		b.WriteString(f.values[i])
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+// expanded возвращает поля по одному на строку
+func (f *fields) expanded() string {
+	var b strings.Builder
+	b.WriteString(f.name)
+	b.WriteString("{\n")
+	for i, key := range f.keys {
+		b.WriteString("  ")
+		b.WriteString(key)
+		b.WriteByte('=')
+		b.WriteString(f.values[i])
+		b.WriteByte('\n')
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+// format реализует fmt.Formatter: %+v - развёрнутый формат, %q - компактный
+// в кавычках, остальные глаголы - компактный
+func (f *fields) format(s fmt.State, verb rune) {
+	switch {
+	case verb == 'v' && s.Flag('+'):
+		io.WriteString(s, f.expanded())
+	case verb == 'q':
+		io.WriteString(s, strconv.Quote(f.compact()))
+	default:
+		io.WriteString(s, f.compact())
+	}
+}
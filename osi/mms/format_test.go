@@ -0,0 +1,84 @@
+package mms
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/slonegd/go61850/osi/mms/variant"
+	"github.com/stretchr/testify/assert"
+)
+
+var update = flag.Bool("update", false, "обновить эталоны testdata/*.golden")
+
+// assertGolden сравнивает got с эталоном testdata/name.golden.
+// Эталоны обновляются командой:
+// go test ./osi/mms -run TestStringGolden -update
+func assertGolden(t *testing.T, name, got string) {
+	t.Helper()
+	path := "testdata/" + name + ".golden"
+	if *update {
+		assert.NoError(t, os.WriteFile(path, []byte(got), 0o644))
+	}
+	want, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, string(want), got)
+}
+
+func TestStringGolden(t *testing.T) {
+	nestingLevel := uint32(4)
+	localDetail := uint32(32000)
+	request := NewInitiateRequest()
+	response := &InitiateResponse{
+		LocalDetailCalled:                   &localDetail,
+		NegotiatedMaxServOutstandingCalling: 5,
+		NegotiatedMaxServOutstandingCalled:  5,
+		NegotiatedDataStructureNestingLevel: &nestingLevel,
+		NegotiatedVersionNumber:             1,
+		NegotiatedParameterCBB:              []ParameterCBBBit{Str1, Str2, Vnam},
+		ServicesSupportedCalled:             []ServiceSupportedBit{GetNameList, Read, Write},
+	}
+	read := &ReadResponse{InvokeID: 7, ListOfAccessResult: []AccessResult{
+		{Success: true, Value: variant.NewFloat32Variant(4.2)},
+		{Success: true, Value: variant.NewVisibleStringVariant("LD0/LLN0$Mod")},
+		{Success: true, Value: variant.NewStructureVariant([]*variant.Variant{
+			variant.NewInt32Variant(-1),
+			variant.NewBitStringVariant([]byte{0x00, 0x00}, 13),
+			variant.NewUTCTimeVariant(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)),
+		})},
+		{Success: false, Error: &DataAccessError{ErrorCode: ObjectNonExistent}},
+	}}
+
+	tests := []struct {
+		name  string
+		value any
+	}{
+		{"initiate_request", request},
+		{"initiate_response", response},
+		{"initiate_response_empty", &InitiateResponse{}},
+		{"capabilities", NegotiateCapabilities(request, response)},
+		{"read_response", read},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			compact := fmt.Sprint(tt.value)
+			assert.Equal(t, tt.value.(fmt.Stringer).String(), compact)
+			assert.NotContains(t, compact, "\n")
+			assertGolden(t, tt.name, compact+"\n"+fmt.Sprintf("%+v", tt.value)+"\n")
+		})
+	}
+}
+
+func TestFieldsParseable(t *testing.T) {
+	f := newFields("PDU")
+	f.addUint("N", 1)
+	f.add("Empty", "")
+	f.add("Text", `a "b" c`)
+	f.add("Pair", "k=v")
+	assert.Equal(t, `PDU{N=1 Empty="" Text="a \"b\" c" Pair="k=v"}`, f.compact())
+	assert.Equal(t, "PDU{\n  N=1\n  Empty=\"\"\n  Text=\"a \\\"b\\\" c\"\n  Pair=\"k=v\"\n}", f.expanded())
+	assert.Equal(t, `"ReadResponse{InvokeID=1}"`, fmt.Sprintf("%q", &ReadResponse{InvokeID: 1}))
+	assert.Equal(t, "ReadResponse{InvokeID=1}", fmt.Sprintf("%s", &ReadResponse{InvokeID: 1}))
+}
@@ -0,0 +1,160 @@
+package mms
+
+import (
+	"fmt"
+
+	"github.com/slonegd/go61850/internal/ber"
+)
+
+// DomainState представляет состояние домена MMS
+//
+//	DomainState ::= INTEGER {
+//	  non-existent (0), loading (1), ready (2), in-use (3),
+//	  complete (4), incomplete (5), d1 (7), ... d9 (15)
+//	}
+type DomainState int
+
+const (
+	DomainStateNonExistent DomainState = 0
+	DomainStateLoading     DomainState = 1
+	DomainStateReady       DomainState = 2
+	DomainStateInUse       DomainState = 3
+	DomainStateComplete    DomainState = 4
+	DomainStateIncomplete  DomainState = 5
+)
+
+// String возвращает имя состояния согласно ASN.1
+func (s DomainState) String() string {
+	switch s {
+	case DomainStateNonExistent:
+		return "non-existent"
+	case DomainStateLoading:
+		return "loading"
+	case DomainStateReady:
+		return "ready"
+	case DomainStateInUse:
+		return "in-use"
+	case DomainStateComplete:
+		return "complete"
+	case DomainStateIncomplete:
+		return "incomplete"
+	}
+	if s >= 7 && s <= 15 {
+		return fmt.Sprintf("d%d", int(s)-6)
+	}
+	return fmt.Sprintf("DomainState(%d)", int(s))
+}
+
+// GetDomainAttributesRequest представляет MMS GetDomainAttributes Request PDU
+//
+//	GetDomainAttributes-Request ::= Identifier -- имя домена
+type GetDomainAttributesRequest struct {
+	InvokeID uint32
+	DomainID string
+}
+
+// Bytes кодирует GetDomainAttributesRequest в BER-кодированный пакет MMS confirmed-RequestPDU.
+// Номер сервиса 37 кодируется в расширенной форме тега.
+// Пример:
+// a0 08 - confirmed-RequestPDU
+//
+//	02 01 01 - invokeID
+//	9f 25 02 4c 44 - getDomainAttributes: "LD"
+func (r *GetDomainAttributesRequest) Bytes() ([]byte, error) {
+	if err := ValidateIdentifier(r.DomainID); err != nil {
+		return nil, fmt.Errorf("domain: %w", err)
+	}
+	pdu := encodeInvokeID(r.InvokeID)
+	pdu = append(pdu, wrapServiceTL(ServiceGetDomainAttributes, false, []byte(r.DomainID))...)
+	return wrapTL(ber.ContextSpecific0Constructed, pdu), nil
+}
+
+// GetDomainAttributesResponse представляет MMS GetDomainAttributes Response PDU
+//
+//	GetDomainAttributes-Response ::= SEQUENCE {
+//	  listOfCapabilities       [0] IMPLICIT SEQUENCE OF MMSString,
+//	  state                    [1] IMPLICIT DomainState,
+//	  mmsDeletable             [2] IMPLICIT BOOLEAN,
+//	  sharable                 [3] IMPLICIT BOOLEAN,
+//	  listOfProgramInvocations [4] IMPLICIT SEQUENCE OF Identifier,
+//	  uploadInProgress         [5] IMPLICIT Integer8
+//	}
+type GetDomainAttributesResponse struct {
+	InvokeID     uint32
+	Capabilities []string
+	State        DomainState
+	MmsDeletable bool
+	Sharable     bool
+	// ProgramInvocations - имена программ, использующих домен
+	ProgramInvocations []string
+	// UploadInProgress - число выполняющихся выгрузок домена
+	UploadInProgress int8
+}
+
+// Bytes кодирует GetDomainAttributesResponse в BER-кодированный пакет MMS confirmed-ResponsePDU
+// a1 (confirmed-ResponsePDU) + invokeID + bf 25 (getDomainAttributes) { a0 81 82 83 a4 85 }.
+// Содержимое сервиса кодирует asnGetDomainAttributesResponse (services_gen.go).
+func (r *GetDomainAttributesResponse) Bytes() []byte {
+	content := (&asnGetDomainAttributesResponse{
+		ListOfCapabilities:       r.Capabilities,
+		State:                    int32(r.State),
+		MmsDeletable:             r.MmsDeletable,
+		Sharable:                 r.Sharable,
+		ListOfProgramInvocations: r.ProgramInvocations,
+		UploadInProgress:         int32(r.UploadInProgress),
+	}).encode()
+
+	pdu := encodeInvokeID(r.InvokeID)
+	pdu = append(pdu, wrapServiceTL(ServiceGetDomainAttributes, true, content)...)
+	return wrapTL(ber.ContextSpecific1Constructed, pdu)
+}
+
+// ParseGetDomainAttributesResponse парсит MMS GetDomainAttributes Response PDU.
+// Для confirmed-ErrorPDU возвращается *ConfirmedError.
+func ParseGetDomainAttributesResponse(buffer []byte) (*GetDomainAttributesResponse, error) {
+	if err := checkConfirmedError(buffer); err != nil {
+		return nil, err
+	}
+	content, err := expectTLV(buffer, byte(ber.ContextSpecific1Constructed), "confirmed-ResponsePDU")
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetDomainAttributesResponse{}
+	var service []byte
+	for bufPos := 0; bufPos < len(content); {
+		if content[bufPos] == byte(ber.Integer) {
+			_, value, next, err := decodeTLV(content, bufPos, len(content))
+			if err != nil {
+				return nil, err
+			}
+			response.InvokeID = ber.DecodeUint32(value, len(value), 0)
+			bufPos = next
+			continue
+		}
+		number, value, next, err := decodeServiceTLV(content, bufPos, len(content))
+		if err != nil {
+			return nil, err
+		}
+		if number != ServiceGetDomainAttributes {
+			return nil, fmt.Errorf("unexpected service in confirmed-ResponsePDU: %s", number)
+		}
+		service = value
+		bufPos = next
+	}
+	if service == nil {
+		return nil, fmt.Errorf("confirmed-ResponsePDU does not contain getDomainAttributes response")
+	}
+
+	var decoded asnGetDomainAttributesResponse
+	if err := decoded.decode(service); err != nil {
+		return nil, fmt.Errorf("failed to parse getDomainAttributes response: %w", err)
+	}
+	response.Capabilities = decoded.ListOfCapabilities
+	response.State = DomainState(decoded.State)
+	response.MmsDeletable = decoded.MmsDeletable
+	response.Sharable = decoded.Sharable
+	response.ProgramInvocations = decoded.ListOfProgramInvocations
+	response.UploadInProgress = int8(decoded.UploadInProgress)
+	return response, nil
+}
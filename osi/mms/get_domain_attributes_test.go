@@ -0,0 +1,57 @@
+package mms
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetDomainAttributesRequest(t *testing.T) {
+	request := &GetDomainAttributesRequest{InvokeID: 1, DomainID: "LD"}
+	pdu, err := request.Bytes()
+	assert.NoError(t, err)
+	assert.Equal(t, parseHexString("a008020101 9f25024c44"), pdu)
+
+	_, err = (&GetDomainAttributesRequest{InvokeID: 1, DomainID: "L D"}).Bytes()
+	assert.Error(t, err)
+}
+
+func TestGetDomainAttributesResponse(t *testing.T) {
+	response := &GetDomainAttributesResponse{
+		InvokeID:           1,
+		Capabilities:       []string{},
+		State:              DomainStateReady,
+		Sharable:           true,
+		ProgramInvocations: []string{},
+	}
+	assert.Equal(t, parseHexString("a1160201 01bf2510 a000810102 820100 8301ff a400 850100"), response.Bytes())
+
+	response = &GetDomainAttributesResponse{
+		InvokeID:           7,
+		Capabilities:       []string{"cap1", "cap2"},
+		State:              DomainStateInUse,
+		MmsDeletable:       true,
+		ProgramInvocations: []string{"PI1"},
+		UploadInProgress:   -1,
+	}
+	got, err := ParseGetDomainAttributesResponse(response.Bytes())
+	assert.NoError(t, err)
+	assert.Equal(t, response, got)
+
+	// MMSString в редакции 2 кодируется как UTF8String
+	got, err = ParseGetDomainAttributesResponse(parseHexString("a1100201 01bf250a a0050c03636170 810101"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"cap"}, got.Capabilities)
+	assert.Equal(t, DomainStateLoading, got.State)
+
+	_, err = ParseGetDomainAttributesResponse(parseHexString("a1070201 01a1020000"))
+	assert.EqualError(t, err, "unexpected service in confirmed-ResponsePDU: getNameList")
+}
+
+func TestDomainStateString(t *testing.T) {
+	assert.Equal(t, "non-existent", DomainStateNonExistent.String())
+	assert.Equal(t, "in-use", DomainStateInUse.String())
+	assert.Equal(t, "d1", DomainState(7).String())
+	assert.Equal(t, "d9", DomainState(15).String())
+	assert.Equal(t, "DomainState(6)", DomainState(6).String())
+}
@@ -0,0 +1,325 @@
+package mms
+
+import (
+	"fmt"
+
+	"github.com/slonegd/go61850/internal/ber"
+)
+
+// ObjectClass представляет класс объектов MMS для GetNameList
+// Значения согласно ISO/IEC 9506-2 (basicObjectClass)
+type ObjectClass int
+
+const (
+	ObjectClassNamedVariable     ObjectClass = 0
+	ObjectClassScatteredAccess   ObjectClass = 1
+	ObjectClassNamedVariableList ObjectClass = 2
+	ObjectClassNamedType         ObjectClass = 3
+	ObjectClassSemaphore         ObjectClass = 4
+	ObjectClassEventCondition    ObjectClass = 5
+	ObjectClassEventAction       ObjectClass = 6
+	ObjectClassEventEnrollment   ObjectClass = 7
+	ObjectClassJournal           ObjectClass = 8
+	ObjectClassDomain            ObjectClass = 9
+	ObjectClassProgramInvocation ObjectClass = 10
+	ObjectClassOperatorStation   ObjectClass = 11
+)
+
+// String возвращает строковое представление ObjectClass
+func (c ObjectClass) String() string {
+	switch c {
+	case ObjectClassNamedVariable:
+		return "namedVariable"
+	case ObjectClassScatteredAccess:
+		return "scatteredAccess"
+	case ObjectClassNamedVariableList:
+		return "namedVariableList"
+	case ObjectClassNamedType:
+		return "namedType"
+	case ObjectClassSemaphore:
+		return "semaphore"
+	case ObjectClassEventCondition:
+		return "eventCondition"
+	case ObjectClassEventAction:
+		return "eventAction"
+	case ObjectClassEventEnrollment:
+		return "eventEnrollment"
+	case ObjectClassJournal:
+		return "journal"
+	case ObjectClassDomain:
+		return "domain"
+	case ObjectClassProgramInvocation:
+		return "programInvocation"
+	case ObjectClassOperatorStation:
+		return "operatorStation"
+	default:
+		return fmt.Sprintf("ObjectClass(%d)", int(c))
+	}
+}
+
+// ObjectScope представляет область поиска объектов GetNameList
+type ObjectScope int
+
+const (
+	// ScopeVMD - объекты уровня VMD (vmdSpecific)
+	ScopeVMD ObjectScope = 0
+	// ScopeDomain - объекты домена (domainSpecific)
+	ScopeDomain ObjectScope = 1
+	// ScopeAA - объекты ассоциации (aaSpecific)
+	ScopeAA ObjectScope = 2
+)
+
+// String возвращает строковое представление ObjectScope
+func (s ObjectScope) String() string {
+	switch s {
+	case ScopeVMD:
+		return "vmdSpecific"
+	case ScopeDomain:
+		return "domainSpecific"
+	case ScopeAA:
+		return "aaSpecific"
+	default:
+		return fmt.Sprintf("ObjectScope(%d)", int(s))
+	}
+}
+
+// GetNameListRequest представляет MMS GetNameList Request PDU
+// Структура согласно ISO/IEC 9506-2:
+//
+//	GetNameList-Request ::= SEQUENCE {
+//	  objectClass   [0] ObjectClass,
+//	  objectScope   [1] CHOICE {
+//	    vmdSpecific    [0] IMPLICIT NULL,
+//	    domainSpecific [1] IMPLICIT Identifier,
+//	    aaSpecific     [2] IMPLICIT NULL
+//	  },
+//	  continueAfter [2] IMPLICIT Identifier OPTIONAL
+//	}
+//
+//	ObjectClass ::= CHOICE {
+//	  basicObjectClass [0] IMPLICIT INTEGER
+//	}
+type GetNameListRequest struct {
+	InvokeID    uint32
+	ObjectClass ObjectClass
+	ObjectScope ObjectScope
+	// DomainID - имя домена для ScopeDomain
+	DomainID string
+	// ContinueAfter - имя, после которого продолжить список (пусто - с начала)
+	ContinueAfter string
+}
+
+// NewGetNameListRequest создаёт запрос имён объектов класса class.
+// Если domainID пуст, запрашиваются объекты уровня VMD (vmdSpecific),
+// иначе - объекты указанного домена (domainSpecific).
+// Например, для обнаружения модели сначала запрашиваются домены
+// (ObjectClassDomain без домена), затем переменные и наборы данных каждого домена.
+func NewGetNameListRequest(class ObjectClass, domainID string) *GetNameListRequest {
+	request := &GetNameListRequest{
+		InvokeID:    1,
+		ObjectClass: class,
+		ObjectScope: ScopeVMD,
+	}
+	if domainID != "" {
+		request.ObjectScope = ScopeDomain
+		request.DomainID = domainID
+	}
+	return request
+}
+
+// Bytes кодирует GetNameListRequest в BER-кодированный пакет MMS confirmed-RequestPDU
+// Пример (список доменов):
+// a0 0e - confirmed-RequestPDU
+//
+//	02 01 01 - invokeID
+//	a1 09 - confirmedServiceRequest: getNameList (Context-specific 1, Constructed)
+//	   a0 03 80 01 09 - objectClass: basicObjectClass domain (9)
+//	   a1 02 80 00 - objectScope: vmdSpecific
+func (r *GetNameListRequest) Bytes() []byte {
+	buffer := make([]byte, 8)
+	bufPos := ber.EncodeUInt32(uint32(r.ObjectClass), buffer, 0)
+	content := wrapTL(ber.ContextSpecific0Constructed, wrapTL(ber.ContextSpecific0Primitive, buffer[:bufPos]))
+
+	var scope []byte
+	switch r.ObjectScope {
+	case ScopeDomain:
+		scope = wrapTL(ber.ContextSpecific1Primitive, []byte(r.DomainID))
+	case ScopeAA:
+		scope = wrapTL(ber.ContextSpecific2Primitive, nil)
+	default:
+		scope = wrapTL(ber.ContextSpecific0Primitive, nil)
+	}
+	content = append(content, wrapTL(ber.ContextSpecific1Constructed, scope)...)
+
+	if r.ContinueAfter != "" {
+		content = append(content, wrapTL(ber.ContextSpecific2Primitive, []byte(r.ContinueAfter))...)
+	}
+
+	pdu := encodeInvokeID(r.InvokeID)
+	pdu = append(pdu, wrapTL(ber.ContextSpecific1Constructed, content)...)
+	return wrapTL(ber.ContextSpecific0Constructed, pdu)
+}
+
+// ParseGetNameListRequest парсит MMS GetNameList Request PDU (обратная операция к Bytes).
+// Используется серверной стороной.
+func ParseGetNameListRequest(buffer []byte) (*GetNameListRequest, error) {
+	content, err := expectTLV(buffer, byte(ber.ContextSpecific0Constructed), "confirmed-RequestPDU")
+	if err != nil {
+		return nil, err
+	}
+
+	request := &GetNameListRequest{}
+	var service []byte
+	for bufPos := 0; bufPos < len(content); {
+		tag, value, next, err := decodeTLV(content, bufPos, len(content))
+		if err != nil {
+			return nil, err
+		}
+		switch tag {
+		case byte(ber.Integer):
+			request.InvokeID = ber.DecodeUint32(value, len(value), 0)
+		case byte(ber.ContextSpecific1Constructed):
+			service = value
+		default:
+			return nil, fmt.Errorf("unexpected tag in confirmed-RequestPDU: 0x%02x", tag)
+		}
+		bufPos = next
+	}
+	if service == nil {
+		return nil, fmt.Errorf("confirmed-RequestPDU does not contain getNameList request")
+	}
+
+	for bufPos := 0; bufPos < len(service); {
+		tag, value, next, err := decodeTLV(service, bufPos, len(service))
+		if err != nil {
+			return nil, err
+		}
+		switch tag {
+		case byte(ber.ContextSpecific0Constructed): // objectClass
+			class, err := expectTLV(value, byte(ber.ContextSpecific0Primitive), "basicObjectClass")
+			if err != nil {
+				return nil, err
+			}
+			request.ObjectClass = ObjectClass(ber.DecodeUint32(class, len(class), 0))
+		case byte(ber.ContextSpecific1Constructed): // objectScope
+			scopeTag, scopeValue, _, err := decodeTLV(value, 0, len(value))
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode objectScope: %w", err)
+			}
+			switch scopeTag {
+			case byte(ber.ContextSpecific0Primitive):
+				request.ObjectScope = ScopeVMD
+			case byte(ber.ContextSpecific1Primitive):
+				request.ObjectScope = ScopeDomain
+				request.DomainID = string(scopeValue)
+			case byte(ber.ContextSpecific2Primitive):
+				request.ObjectScope = ScopeAA
+			default:
+				return nil, fmt.Errorf("unknown objectScope tag: 0x%02x", scopeTag)
+			}
+		case byte(ber.ContextSpecific2Primitive): // continueAfter
+			request.ContinueAfter = string(value)
+		default:
+			return nil, fmt.Errorf("unexpected tag in getNameList request: 0x%02x", tag)
+		}
+		bufPos = next
+	}
+
+	return request, nil
+}
+
+// GetNameListResponse представляет MMS GetNameList Response PDU
+//
+//	GetNameList-Response ::= SEQUENCE {
+//	  listOfIdentifier [0] IMPLICIT SEQUENCE OF Identifier,
+//	  moreFollows      [1] IMPLICIT BOOLEAN DEFAULT TRUE
+//	}
+type GetNameListResponse struct {
+	InvokeID    uint32
+	Identifiers []string
+	// MoreFollows - сервер вернул не все имена, нужен повторный запрос с ContinueAfter
+	MoreFollows bool
+}
+
+// Bytes кодирует GetNameListResponse в BER-кодированный пакет MMS confirmed-ResponsePDU
+// a1 (confirmed-ResponsePDU) + invokeID + a1 (getNameList) { a0 (listOfIdentifier) 81 (moreFollows) }
+func (r *GetNameListResponse) Bytes() []byte {
+	var identifiers []byte
+	for _, id := range r.Identifiers {
+		identifiers = append(identifiers, wrapTL(ber.VisibleString, []byte(id))...)
+	}
+
+	content := wrapTL(ber.ContextSpecific0Constructed, identifiers)
+	moreFollows := byte(0x00)
+	if r.MoreFollows {
+		moreFollows = 0xff
+	}
+	content = append(content, wrapTL(ber.ContextSpecific1Primitive, []byte{moreFollows})...)
+
+	pdu := encodeInvokeID(r.InvokeID)
+	pdu = append(pdu, wrapTL(ber.ContextSpecific1Constructed, content)...)
+	return wrapTL(ber.ContextSpecific1Constructed, pdu)
+}
+
+// ParseGetNameListResponse парсит MMS GetNameList Response PDU.
+// Для confirmed-ErrorPDU возвращается *ConfirmedError.
+func ParseGetNameListResponse(buffer []byte) (*GetNameListResponse, error) {
+	if err := checkConfirmedError(buffer); err != nil {
+		return nil, err
+	}
+	content, err := expectTLV(buffer, byte(ber.ContextSpecific1Constructed), "confirmed-ResponsePDU")
+	if err != nil {
+		return nil, err
+	}
+
+	// moreFollows по умолчанию TRUE согласно ASN.1 определению
+	response := &GetNameListResponse{MoreFollows: true}
+	var service []byte
+	for bufPos := 0; bufPos < len(content); {
+		tag, value, next, err := decodeTLV(content, bufPos, len(content))
+		if err != nil {
+			return nil, err
+		}
+		switch tag {
+		case byte(ber.Integer):
+			response.InvokeID = ber.DecodeUint32(value, len(value), 0)
+		case byte(ber.ContextSpecific1Constructed):
+			service = value
+		default:
+			return nil, fmt.Errorf("unexpected tag in confirmed-ResponsePDU: 0x%02x", tag)
+		}
+		bufPos = next
+	}
+	if service == nil {
+		return nil, fmt.Errorf("confirmed-ResponsePDU does not contain getNameList response")
+	}
+
+	for bufPos := 0; bufPos < len(service); {
+		tag, value, next, err := decodeTLV(service, bufPos, len(service))
+		if err != nil {
+			return nil, err
+		}
+		switch tag {
+		case byte(ber.ContextSpecific0Constructed): // listOfIdentifier
+			response.Identifiers = []string{}
+			for idPos := 0; idPos < len(value); {
+				idTag, id, idNext, err := decodeTLV(value, idPos, len(value))
+				if err != nil {
+					return nil, err
+				}
+				if idTag != byte(ber.VisibleString) {
+					return nil, fmt.Errorf("unexpected identifier tag: 0x%02x", idTag)
+				}
+				response.Identifiers = append(response.Identifiers, string(id))
+				idPos = idNext
+			}
+		case byte(ber.ContextSpecific1Primitive): // moreFollows
+			response.MoreFollows = len(value) > 0 && value[0] != 0
+		default:
+			return nil, fmt.Errorf("unexpected tag in getNameList response: 0x%02x", tag)
+		}
+		bufPos = next
+	}
+
+	return response, nil
+}
@@ -0,0 +1,49 @@
+package mms
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetNameListRequest(t *testing.T) {
+	request := &GetNameListRequest{InvokeID: 1, ObjectClass: ObjectClassDomain, ObjectScope: ScopeVMD}
+	assert.Equal(t, parseHexString("a00e020101a109a003800109a1028000"), request.Bytes())
+
+	tests := []*GetNameListRequest{
+		request,
+		{InvokeID: 2, ObjectClass: ObjectClassNamedVariable, ObjectScope: ScopeDomain, DomainID: "simpleIOGenericIO"},
+		{InvokeID: 3, ObjectClass: ObjectClassJournal, ObjectScope: ScopeDomain, DomainID: "LD0", ContinueAfter: "LLN0$Log"},
+		{InvokeID: 4, ObjectClass: ObjectClassNamedVariableList, ObjectScope: ScopeAA},
+	}
+	for _, want := range tests {
+		got, err := ParseGetNameListRequest(want.Bytes())
+		assert.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+}
+
+func TestNewGetNameListRequest(t *testing.T) {
+	assert.Equal(t,
+		&GetNameListRequest{InvokeID: 1, ObjectClass: ObjectClassDomain, ObjectScope: ScopeVMD},
+		NewGetNameListRequest(ObjectClassDomain, ""),
+	)
+	assert.Equal(t,
+		&GetNameListRequest{InvokeID: 1, ObjectClass: ObjectClassJournal, ObjectScope: ScopeDomain, DomainID: "LD0"},
+		NewGetNameListRequest(ObjectClassJournal, "LD0"),
+	)
+}
+
+func TestGetNameListResponse(t *testing.T) {
+	response := &GetNameListResponse{InvokeID: 1, Identifiers: []string{"LD0", "LD1"}}
+	assert.Equal(t, parseHexString("a1140201 01a10fa0 0a1a034c44301a034c4431810100"), response.Bytes())
+
+	got, err := ParseGetNameListResponse(response.Bytes())
+	assert.NoError(t, err)
+	assert.Equal(t, response, got)
+
+	// moreFollows по умолчанию TRUE
+	got, err = ParseGetNameListResponse(parseHexString("a1090201 01a104a0021a00"))
+	assert.NoError(t, err)
+	assert.Equal(t, &GetNameListResponse{InvokeID: 1, Identifiers: []string{""}, MoreFollows: true}, got)
+}
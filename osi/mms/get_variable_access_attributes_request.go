@@ -1,7 +1,9 @@
 package mms
 
 import (
-	"github.com/slonegd/go61850/ber"
+	"fmt"
+
+	"github.com/slonegd/go61850/internal/ber"
 )
 
 // GetVariableAccessAttributesRequest представляет MMS GetVariableAccessAttributes Request PDU
@@ -129,11 +131,38 @@ func (r *GetVariableAccessAttributesRequest) buildDomainSpecificName() []byte {
 }
 
 // NewGetVariableAccessAttributesRequest создаёт MMS GetVariableAccessAttributesRequest из domainID и itemID.
-// invokeID устанавливается в 2 (стандартное значение для второго запроса после Initiate).
+// invokeID устанавливается в 2 (значение для второго запроса после Initiate);
+// MmsClient перезаписывает его значением из InvokeIDAllocator.
 func NewGetVariableAccessAttributesRequest(domainID, itemID string) *GetVariableAccessAttributesRequest {
 	return &GetVariableAccessAttributesRequest{
-		InvokeID: 2, // TODO разхардкодить. Должен проставлять клиент
+		InvokeID: 2,
 		DomainID: domainID,
 		ItemID:   itemID,
 	}
 }
+
+// ParseGetVariableAccessAttributesRequest парсит MMS GetVariableAccessAttributes Request PDU
+// (обратная операция к GetVariableAccessAttributesRequest.Bytes). Используется серверной стороной.
+func ParseGetVariableAccessAttributesRequest(buffer []byte) (*GetVariableAccessAttributesRequest, error) {
+	confirmed, err := ParseConfirmedRequest(buffer)
+	if err != nil {
+		return nil, err
+	}
+	if confirmed.Service != ServiceGetVariableAccessAttributes {
+		return nil, fmt.Errorf("confirmed-RequestPDU does not contain getVariableAccessAttributes request: %s", confirmed.Service)
+	}
+
+	content, err := expectTLV(confirmed.Argument, byte(ber.ContextSpecific0Constructed), "name")
+	if err != nil {
+		return nil, err
+	}
+	tag, value, _, err := decodeTLV(content, 0, len(content))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode object name: %w", err)
+	}
+	name, err := parseObjectName(tag, value)
+	if err != nil {
+		return nil, err
+	}
+	return &GetVariableAccessAttributesRequest{InvokeID: confirmed.InvokeID, DomainID: name.DomainID, ItemID: name.ItemID}, nil
+}
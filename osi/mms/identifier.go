@@ -0,0 +1,85 @@
+package mms
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrInvalidIdentifier возвращается для имени, которое нельзя закодировать как MMS Identifier
+var ErrInvalidIdentifier = errors.New("invalid MMS identifier")
+
+// ErrInvalidFileName возвращается для имени файла с символами вне GraphicString
+var ErrInvalidFileName = errors.New("invalid MMS file name")
+
+// ErrMissingDomain возвращается для ссылки на объект без домена (логического устройства),
+// если домен по умолчанию не задан
+var ErrMissingDomain = errors.New("object reference has no domain")
+
+// ValidateIdentifier проверяет имя домена, переменной, журнала или списка переменных
+// на соответствие MMS Identifier (ISO/IEC 9506-2):
+//
+//	Identifier ::= VisibleString -- символы A-Z, a-z, 0-9, '_', '$'; первый символ не цифра
+//
+// Экранирование в Identifier не предусмотрено, поэтому такие имена отклоняются.
+func ValidateIdentifier(name string) error {
+	if name == "" {
+		return fmt.Errorf("%w: empty name", ErrInvalidIdentifier)
+	}
+	for i, r := range name {
+		switch {
+		case r >= 'A' && r <= 'Z', r >= 'a' && r <= 'z', r == '_', r == '$':
+		case r >= '0' && r <= '9':
+			if i == 0 {
+				return fmt.Errorf("%w %q: starts with digit", ErrInvalidIdentifier, name)
+			}
+		case r == utf8.RuneError:
+			return fmt.Errorf("%w %q: invalid UTF-8 at position %d", ErrInvalidIdentifier, name, i)
+		default:
+			return fmt.Errorf("%w %q: character %q at position %d is not allowed", ErrInvalidIdentifier, name, r, i)
+		}
+	}
+	return nil
+}
+
+// Validate проверяет DomainID (если задан) и ItemID на соответствие MMS Identifier
+func (n ObjectName) Validate() error {
+	if n.DomainID != "" {
+		if err := ValidateIdentifier(n.DomainID); err != nil {
+			return err
+		}
+	}
+	return ValidateIdentifier(n.ItemID)
+}
+
+// ValidateFileName проверяет компонент имени файла: FileName ::= SEQUENCE OF GraphicString,
+// допустимы печатаемые символы ASCII и пробел
+func ValidateFileName(name string) error {
+	if name == "" {
+		return fmt.Errorf("%w: empty name", ErrInvalidFileName)
+	}
+	for i := 0; i < len(name); i++ {
+		if name[i] < 0x20 || name[i] > 0x7e {
+			return fmt.Errorf("%w %q: byte 0x%02x at position %d is outside GraphicString, use EscapeFileName",
+				ErrInvalidFileName, name, name[i], i)
+		}
+	}
+	return nil
+}
+
+// EscapeFileName кодирует байты имени файла вне GraphicString (управляющие символы,
+// не-ASCII, в том числе UTF-8) и сам символ '%' как %XX. Применяется, если сервер
+// принимает имена в такой записи; иначе такое имя отклоняется ValidateFileName.
+func EscapeFileName(name string) string {
+	var builder strings.Builder
+	for i := 0; i < len(name); i++ {
+		b := name[i]
+		if b < 0x20 || b > 0x7e || b == '%' {
+			fmt.Fprintf(&builder, "%%%02X", b)
+			continue
+		}
+		builder.WriteByte(b)
+	}
+	return builder.String()
+}
@@ -0,0 +1,50 @@
+package mms
+
+import (
+	"testing"
+
+	"github.com/slonegd/go61850/osi/mms/variant"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateIdentifier(t *testing.T) {
+	for _, name := range []string{"simpleIOGenericIO", "GGIO1$MX$AnIn1$mag$f", "_x", "$", "LD0"} {
+		assert.NoError(t, ValidateIdentifier(name), name)
+	}
+
+	tests := map[string]string{
+		"":         "invalid MMS identifier: empty name",
+		"1LD":      `invalid MMS identifier "1LD": starts with digit`,
+		"GGIO1.Mx": `invalid MMS identifier "GGIO1.Mx": character '.' at position 5 is not allowed`,
+		"Журнал":   `invalid MMS identifier "Журнал": character 'Ж' at position 0 is not allowed`,
+		"LD 0":     `invalid MMS identifier "LD 0": character ' ' at position 2 is not allowed`,
+		"LD\xff":   `invalid MMS identifier "LD\xff": invalid UTF-8 at position 2`,
+	}
+	for name, want := range tests {
+		err := ValidateIdentifier(name)
+		assert.ErrorIs(t, err, ErrInvalidIdentifier, name)
+		assert.EqualError(t, err, want)
+	}
+
+	assert.NoError(t, ObjectName{ItemID: "RPT"}.Validate())
+	assert.ErrorIs(t, ObjectName{DomainID: "LD-0", ItemID: "LLN0"}.Validate(), ErrInvalidIdentifier)
+}
+
+func TestFileName(t *testing.T) {
+	assert.NoError(t, ValidateFileName("COMTRADE/rec 01.cfg"))
+	err := ValidateFileName("осц.cfg")
+	assert.ErrorIs(t, err, ErrInvalidFileName)
+	assert.EqualError(t, err, `invalid MMS file name "осц.cfg": byte 0xd0 at position 0 is outside GraphicString, use EscapeFileName`)
+
+	escaped := EscapeFileName("осц 100%.cfg")
+	assert.Equal(t, "%D0%BE%D1%81%D1%86 100%25.cfg", escaped)
+	assert.NoError(t, ValidateFileName(escaped))
+}
+
+func TestEncodeInvalidNames(t *testing.T) {
+	_, err := (&WriteRequest{InvokeID: 1, DomainID: "LD0", ItemID: "GGIO1$CO$SPCSO1\n", Value: variant.NewInt32Variant(1)}).Bytes()
+	assert.ErrorIs(t, err, ErrInvalidIdentifier)
+
+	_, err = (&InformationReportPDU{VariableListName: &ObjectName{ItemID: "Отчёт"}}).Bytes()
+	assert.ErrorIs(t, err, ErrInvalidIdentifier)
+}
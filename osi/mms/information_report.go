@@ -0,0 +1,169 @@
+package mms
+
+import (
+	"fmt"
+
+	"github.com/slonegd/go61850/internal/ber"
+)
+
+// ObjectName представляет имя объекта MMS.
+// Пустой DomainID означает vmd-specific имя (например, "RPT" или "LastApplError").
+type ObjectName struct {
+	DomainID string
+	ItemID   string
+}
+
+// String возвращает имя в формате "domain/item" или "item" для vmd-specific
+func (n ObjectName) String() string {
+	if n.DomainID == "" {
+		return n.ItemID
+	}
+	return n.DomainID + "/" + n.ItemID
+}
+
+// encodeObjectName кодирует ObjectName: 80 (vmd-specific) или a1 (domain-specific)
+func encodeObjectName(n ObjectName) []byte {
+	return (&ReadRequest{DomainID: n.DomainID, ItemID: n.ItemID}).buildObjectName()
+}
+
+// parseObjectName разбирает ObjectName (тег и содержимое элемента)
+func parseObjectName(tag byte, value []byte) (ObjectName, error) {
+	switch tag {
+	case byte(ber.ContextSpecific0Primitive):
+		return ObjectName{ItemID: intern(value)}, nil
+	case byte(ber.ContextSpecific1Constructed):
+		tag, domainID, next, err := decodeTLV(value, 0, len(value))
+		if err != nil || tag != byte(ber.VisibleString) {
+			return ObjectName{}, fmt.Errorf("invalid domainId in object name")
+		}
+		tag, itemID, _, err := decodeTLV(value, next, len(value))
+		if err != nil || tag != byte(ber.VisibleString) {
+			return ObjectName{}, fmt.Errorf("invalid itemId in object name")
+		}
+		return ObjectName{DomainID: intern(domainID), ItemID: intern(itemID)}, nil
+	default:
+		return ObjectName{}, fmt.Errorf("unsupported object name tag: 0x%02x", tag)
+	}
+}
+
+// InformationReportPDU представляет MMS InformationReport (unconfirmed-PDU).
+// Сервер IEC 61850 использует его для отчётов (variableListName "RPT")
+// и для CommandTermination и LastApplError (listOfVariable).
+//
+//	unconfirmed-PDU ::= SEQUENCE {
+//	  unconfirmedService [0] CHOICE {
+//	    informationReport [0] InformationReport
+//	  }
+//	}
+//
+//	InformationReport ::= SEQUENCE {
+//	  variableAccessSpecification VariableAccessSpecification,
+//	  listOfAccessResult [0] IMPLICIT SEQUENCE OF AccessResult
+//	}
+type InformationReportPDU struct {
+	// VariableListName - имя именованного списка переменных; nil, если задан Variables
+	VariableListName *ObjectName
+	// Variables - список переменных (listOfVariable)
+	Variables []ObjectName
+	// Results - значения переменных в порядке Variables или элементов списка
+	Results []AccessResult
+}
+
+// IsInformationReport возвращает true, если MMS PDU - unconfirmed-PDU (тег a3)
+func IsInformationReport(buffer []byte) bool {
+	return len(buffer) > 0 && buffer[0] == byte(ber.ContextSpecific3Constructed)
+}
+
+// Bytes кодирует InformationReport в unconfirmed-PDU
+// a3 (unconfirmed-PDU) a0 (informationReport) { a0 (listOfVariable) | a1 (variableListName), a0 (listOfAccessResult) }
+func (r *InformationReportPDU) Bytes() ([]byte, error) {
+	var spec []byte
+	if r.VariableListName != nil {
+		if err := r.VariableListName.Validate(); err != nil {
+			return nil, err
+		}
+		spec = wrapTL(ber.ContextSpecific1Constructed, encodeObjectName(*r.VariableListName))
+	} else {
+		var variables []byte
+		for _, name := range r.Variables {
+			if err := name.Validate(); err != nil {
+				return nil, err
+			}
+			variable := wrapTL(ber.ContextSpecific0Constructed, encodeObjectName(name))
+			variables = append(variables, wrapTL(ber.SequenceConstructed, variable)...)
+		}
+		spec = wrapTL(ber.ContextSpecific0Constructed, variables)
+	}
+
+	results, err := encodeAccessResults(r.Results)
+	if err != nil {
+		return nil, err
+	}
+
+	content := append(spec, wrapTL(ber.ContextSpecific0Constructed, results)...)
+	return wrapTL(ber.ContextSpecific3Constructed, wrapTL(ber.ContextSpecific0Constructed, content)), nil
+}
+
+// ParseInformationReport парсит MMS InformationReport (обратная операция к Bytes)
+func ParseInformationReport(buffer []byte) (*InformationReportPDU, error) {
+	content, err := expectTLV(buffer, byte(ber.ContextSpecific3Constructed), "unconfirmed-PDU")
+	if err != nil {
+		return nil, err
+	}
+	content, err = expectTLV(content, byte(ber.ContextSpecific0Constructed), "informationReport")
+	if err != nil {
+		return nil, err
+	}
+
+	report := &InformationReportPDU{}
+	tag, spec, next, err := decodeTLV(content, 0, len(content))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode variableAccessSpecification: %w", err)
+	}
+	switch tag {
+	case byte(ber.ContextSpecific0Constructed): // listOfVariable
+		for bufPos := 0; bufPos < len(spec); {
+			_, variable, itemNext, err := decodeTLV(spec, bufPos, len(spec))
+			if err != nil {
+				return nil, err
+			}
+			variable, err = expectTLV(variable, byte(ber.ContextSpecific0Constructed), "variableSpecification")
+			if err != nil {
+				return nil, err
+			}
+			nameTag, nameValue, _, err := decodeTLV(variable, 0, len(variable))
+			if err != nil {
+				return nil, err
+			}
+			name, err := parseObjectName(nameTag, nameValue)
+			if err != nil {
+				return nil, err
+			}
+			report.Variables = append(report.Variables, name)
+			bufPos = itemNext
+		}
+	case byte(ber.ContextSpecific1Constructed): // variableListName
+		nameTag, nameValue, _, err := decodeTLV(spec, 0, len(spec))
+		if err != nil {
+			return nil, err
+		}
+		name, err := parseObjectName(nameTag, nameValue)
+		if err != nil {
+			return nil, err
+		}
+		report.VariableListName = &name
+	default:
+		return nil, fmt.Errorf("unsupported variableAccessSpecification tag: 0x%02x", tag)
+	}
+
+	results, err := expectTLV(content[next:], byte(ber.ContextSpecific0Constructed), "listOfAccessResult")
+	if err != nil {
+		return nil, err
+	}
+	report.Results, err = parseListOfAccessResult(results)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse listOfAccessResult: %w", err)
+	}
+
+	return report, nil
+}
@@ -0,0 +1,87 @@
+package mms
+
+import (
+	"testing"
+	"time"
+
+	"github.com/slonegd/go61850/osi/mms/variant"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInformationReport(t *testing.T) {
+	report := &InformationReportPDU{
+		Variables: []ObjectName{
+			{ItemID: "LastApplError"},
+			{DomainID: "LD0", ItemID: "A"},
+		},
+		Results: []AccessResult{
+			{Success: true, Value: variant.NewInt32Variant(1)},
+			{Success: true, Value: variant.NewBoolVariant(true)},
+		},
+	}
+
+	buffer, err := report.Bytes()
+	assert.NoError(t, err)
+	assert.Equal(t, parseHexString("a32d a02b a021 3011 a00f 800d4c6173744170706c4572726f72 300c a00a a108 1a034c4430 1a0141 a006 850101 8301ff"), buffer)
+	assert.True(t, IsInformationReport(buffer))
+
+	parsed, err := ParseInformationReport(buffer)
+	assert.NoError(t, err)
+	assert.Equal(t, report, parsed)
+
+	// Отчёт по именованному списку "RPT"
+	report = &InformationReportPDU{
+		VariableListName: &ObjectName{ItemID: "RPT"},
+		Results:          []AccessResult{{Success: true, Value: variant.NewVisibleStringVariant("rpt")}},
+	}
+	buffer, err = report.Bytes()
+	assert.NoError(t, err)
+	parsed, err = ParseInformationReport(buffer)
+	assert.NoError(t, err)
+	assert.Equal(t, report, parsed)
+	assert.Equal(t, "RPT", parsed.VariableListName.String())
+
+	assert.False(t, IsInformationReport(parseHexString("a1050201018100")))
+}
+
+// benchmarkReport возвращает отчёт шлюзового сценария: набор данных из 8 MV
+// (mag.f, q, t) с причинами включения
+func benchmarkReport(b *testing.B) []byte {
+	results := []AccessResult{
+		{Success: true, Value: variant.NewVisibleStringVariant("LD0/LLN0$RP$MeasRCB01")},
+		{Success: true, Value: variant.NewBitStringVariant([]byte{0x7c, 0x00}, 10)},
+		{Success: true, Value: variant.NewUint32Variant(42)},
+		{Success: true, Value: variant.NewBinaryTimeVariant([]byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06})},
+		{Success: true, Value: variant.NewVisibleStringVariant("LD0/LLN0$Measurements")},
+		{Success: true, Value: variant.NewBitStringVariant([]byte{0xff}, 8)},
+	}
+	for i := range 8 {
+		results = append(results, AccessResult{Success: true, Value: variant.NewStructureVariant([]*variant.Variant{
+			variant.NewStructureVariant([]*variant.Variant{variant.NewFloat32Variant(float32(i) + 0.5)}),
+			variant.NewBitStringVariant([]byte{0x00, 0x00}, 13),
+			variant.NewUTCTimeVariant(time.Unix(1700000000+int64(i), 0).UTC()),
+		})})
+	}
+	for range 8 {
+		results = append(results, AccessResult{Success: true, Value: variant.NewBitStringVariant([]byte{0x08}, 6)})
+	}
+	buffer, err := (&InformationReportPDU{
+		VariableListName: &ObjectName{ItemID: "RPT"},
+		Results:          results,
+	}).Bytes()
+	if err != nil {
+		b.Fatal(err)
+	}
+	return buffer
+}
+
+func BenchmarkParseInformationReport(b *testing.B) {
+	buffer := benchmarkReport(b)
+	b.ReportAllocs()
+	b.SetBytes(int64(len(buffer)))
+	for b.Loop() {
+		if _, err := ParseInformationReport(buffer); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
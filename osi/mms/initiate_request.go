@@ -1,10 +1,11 @@
 package mms
 
 import (
+	"errors"
 	"fmt"
-	"strings"
+	"math"
 
-	"github.com/slonegd/go61850/ber"
+	"github.com/slonegd/go61850/internal/ber"
 )
 
 // ServiceSupportedBit представляет номер бита в битовой маске ServicesSupportedCalling
@@ -453,41 +454,27 @@ func WithServicesSupportedCalling(services []ServiceSupportedBit) InitiateReques
 	}
 }
 
-// String реализует интерфейс fmt.Stringer для InitiateRequest.
-// Для ProposedParameterCBB и ServicesSupportedCalling выводит список установленных битов,
-// остальные поля выводятся как при %+v.
+// String возвращает поля InitiateRequest одной строкой в формате key=value;
+// для ProposedParameterCBB и ServicesSupportedCalling выводятся установленные биты
 func (r *InitiateRequest) String() string {
-	var parts []string
-
-	parts = append(parts, fmt.Sprintf("LocalDetailCalling:%d", r.LocalDetailCalling))
-	parts = append(parts, fmt.Sprintf("ProposedMaxServOutstandingCalling:%d", r.ProposedMaxServOutstandingCalling))
-	parts = append(parts, fmt.Sprintf("ProposedMaxServOutstandingCalled:%d", r.ProposedMaxServOutstandingCalled))
-	parts = append(parts, fmt.Sprintf("ProposedDataStructureNestingLevel:%d", r.ProposedDataStructureNestingLevel))
-	parts = append(parts, fmt.Sprintf("ProposedVersionNumber:%d", r.ProposedVersionNumber))
-
-	// ProposedParameterCBB - список установленных битов
-	if len(r.ProposedParameterCBB) > 0 {
-		bitNames := make([]string, len(r.ProposedParameterCBB))
-		for i, bit := range r.ProposedParameterCBB {
-			bitNames[i] = bit.String()
-		}
-		parts = append(parts, fmt.Sprintf("ProposedParameterCBB:[%s]", strings.Join(bitNames, " ")))
-	} else {
-		parts = append(parts, "ProposedParameterCBB:[]")
-	}
+	return r.fields().compact()
+}
 
-	// ServicesSupportedCalling - список установленных битов
-	if len(r.ServicesSupportedCalling) > 0 {
-		bitNames := make([]string, len(r.ServicesSupportedCalling))
-		for i, bit := range r.ServicesSupportedCalling {
-			bitNames[i] = bit.String()
-		}
-		parts = append(parts, fmt.Sprintf("ServicesSupportedCalling:[%s]", strings.Join(bitNames, " ")))
-	} else {
-		parts = append(parts, "ServicesSupportedCalling:[]")
-	}
+// Format реализует fmt.Formatter: %+v выводит поле на строку
+func (r *InitiateRequest) Format(s fmt.State, verb rune) {
+	r.fields().format(s, verb)
+}
 
-	return fmt.Sprintf("InitiateRequest{%s}", strings.Join(parts, " "))
+func (r *InitiateRequest) fields() *fields {
+	f := newFields("InitiateRequest")
+	f.addUint("LocalDetailCalling", r.LocalDetailCalling)
+	f.addUint("ProposedMaxServOutstandingCalling", r.ProposedMaxServOutstandingCalling)
+	f.addUint("ProposedMaxServOutstandingCalled", r.ProposedMaxServOutstandingCalled)
+	f.addUint("ProposedDataStructureNestingLevel", r.ProposedDataStructureNestingLevel)
+	f.addUint("ProposedVersionNumber", r.ProposedVersionNumber)
+	addList(f, "ProposedParameterCBB", r.ProposedParameterCBB)
+	addList(f, "ServicesSupportedCalling", r.ServicesSupportedCalling)
+	return f
 }
 
 // Bytes кодирует InitiateRequest в BER-кодированный пакет.
@@ -517,6 +504,74 @@ func (r *InitiateRequest) Bytes() []byte {
 	return buffer[:bufPos]
 }
 
+// Ограничения параметров InitiateRequest согласно ISO/IEC 9506-2
+const (
+	// MinLocalDetailCalling - минимальный размер PDU, который принимают серверы
+	MinLocalDetailCalling = 64
+	// MaxLocalDetailCalling - localDetailCalling кодируется как Integer32
+	MaxLocalDetailCalling = math.MaxInt32
+	// MaxServOutstanding - proposedMaxServOutstanding* кодируются как Integer16
+	MaxServOutstanding = math.MaxInt16
+	// MaxDataStructureNestingLevel - proposedDataStructureNestingLevel кодируется как Integer8
+	MaxDataStructureNestingLevel = math.MaxInt8
+	// SupportedVersionNumber - единственная версия MMS, используемая в IEC 61850
+	SupportedVersionNumber = 1
+)
+
+// Normalize приводит параметры к допустимым диапазонам там, где это безопасно:
+// количество одновременных запросов - не меньше 1 и не больше Integer16,
+// размер PDU и уровень вложенности - не больше максимума своего типа.
+// Значения, которые нельзя исправить без изменения смысла, проверяет Validate.
+func (r *InitiateRequest) Normalize() {
+	r.ProposedMaxServOutstandingCalling = clampUint32(r.ProposedMaxServOutstandingCalling, 1, MaxServOutstanding)
+	r.ProposedMaxServOutstandingCalled = clampUint32(r.ProposedMaxServOutstandingCalled, 1, MaxServOutstanding)
+	r.LocalDetailCalling = min(r.LocalDetailCalling, MaxLocalDetailCalling)
+	r.ProposedDataStructureNestingLevel = min(r.ProposedDataStructureNestingLevel, MaxDataStructureNestingLevel)
+}
+
+// Validate проверяет параметры перед кодированием, чтобы вместо отказа
+// в ассоциации со стороны сервера получить понятную ошибку.
+// Возвращает все найденные ошибки, объединённые через errors.Join.
+func (r *InitiateRequest) Validate() error {
+	var errs []error
+	if r.LocalDetailCalling < MinLocalDetailCalling || r.LocalDetailCalling > MaxLocalDetailCalling {
+		errs = append(errs, fmt.Errorf("localDetailCalling %d out of range [%d, %d]",
+			r.LocalDetailCalling, MinLocalDetailCalling, MaxLocalDetailCalling))
+	}
+	if r.ProposedMaxServOutstandingCalling < 1 || r.ProposedMaxServOutstandingCalling > MaxServOutstanding {
+		errs = append(errs, fmt.Errorf("proposedMaxServOutstandingCalling %d out of range [1, %d]",
+			r.ProposedMaxServOutstandingCalling, MaxServOutstanding))
+	}
+	if r.ProposedMaxServOutstandingCalled < 1 || r.ProposedMaxServOutstandingCalled > MaxServOutstanding {
+		errs = append(errs, fmt.Errorf("proposedMaxServOutstandingCalled %d out of range [1, %d]",
+			r.ProposedMaxServOutstandingCalled, MaxServOutstanding))
+	}
+	if r.ProposedDataStructureNestingLevel > MaxDataStructureNestingLevel {
+		errs = append(errs, fmt.Errorf("proposedDataStructureNestingLevel %d exceeds %d",
+			r.ProposedDataStructureNestingLevel, MaxDataStructureNestingLevel))
+	}
+	if r.ProposedVersionNumber != SupportedVersionNumber {
+		errs = append(errs, fmt.Errorf("proposedVersionNumber %d is not supported, expected %d",
+			r.ProposedVersionNumber, SupportedVersionNumber))
+	}
+	for _, bit := range r.ProposedParameterCBB {
+		if bit > Cei {
+			errs = append(errs, fmt.Errorf("unknown proposedParameterCBB bit %d", uint(bit)))
+		}
+	}
+	for _, bit := range r.ServicesSupportedCalling {
+		if bit > Cancel {
+			errs = append(errs, fmt.Errorf("unknown servicesSupportedCalling bit %d", uint(bit)))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// clampUint32 ограничивает значение диапазоном [lo, hi]
+func clampUint32(value, lo, hi uint32) uint32 {
+	return max(lo, min(value, hi))
+}
+
 // NewInitiateRequest создаёт MMS InitiateRequest с параметрами по умолчанию.
 // Можно передать опции для переопределения отдельных параметров.
 func NewInitiateRequest(opts ...InitiateRequestOption) *InitiateRequest {
@@ -643,3 +698,76 @@ func (r *InitiateRequest) buildMMSInitRequestDetail() []byte {
 
 	return result[:resultPos]
 }
+
+// ParseInitiateRequest разбирает BER-кодированный initiate-RequestPDU (тег 0xA8),
+// например захваченный у другого клиента, в InitiateRequest.
+// Обратная операция к InitiateRequest.Bytes; неизвестные элементы пропускаются.
+func ParseInitiateRequest(buffer []byte) (*InitiateRequest, error) {
+	content, err := expectTLV(buffer, 0xA8, "initiate-RequestPDU")
+	if err != nil {
+		return nil, err
+	}
+
+	request := &InitiateRequest{}
+	for bufPos := 0; bufPos < len(content); {
+		tag, value, next, err := decodeTLV(content, bufPos, len(content))
+		if err != nil {
+			return nil, fmt.Errorf("initiate-RequestPDU: %w", err)
+		}
+		bufPos = next
+
+		switch tag {
+		case 0x80: // localDetailCalling
+			request.LocalDetailCalling = ber.DecodeUint32(value, len(value), 0)
+		case 0x81: // proposedMaxServOutstandingCalling
+			request.ProposedMaxServOutstandingCalling = ber.DecodeUint32(value, len(value), 0)
+		case 0x82: // proposedMaxServOutstandingCalled
+			request.ProposedMaxServOutstandingCalled = ber.DecodeUint32(value, len(value), 0)
+		case 0x83: // proposedDataStructureNestingLevel
+			request.ProposedDataStructureNestingLevel = ber.DecodeUint32(value, len(value), 0)
+		case 0xA4: // mmsInitRequestDetail
+			if err := request.parseMMSInitRequestDetail(value); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return request, nil
+}
+
+// parseMMSInitRequestDetail разбирает содержимое mmsInitRequestDetail
+// (см. buildMMSInitRequestDetail)
+func (r *InitiateRequest) parseMMSInitRequestDetail(detail []byte) error {
+	for bufPos := 0; bufPos < len(detail); {
+		tag, value, next, err := decodeTLV(detail, bufPos, len(detail))
+		if err != nil {
+			return fmt.Errorf("mmsInitRequestDetail: %w", err)
+		}
+		bufPos = next
+
+		switch tag {
+		case 0x80: // proposedVersionNumber
+			r.ProposedVersionNumber = ber.DecodeUint32(value, len(value), 0)
+		case 0x81: // proposedParameterCBB (BIT STRING)
+			if len(value) < 1 {
+				return errors.New("invalid proposedParameterCBB: missing padding byte")
+			}
+			r.ProposedParameterCBB = nil
+			for _, offset := range ber.DecodeBitmaskFromBytes(value[1:], value[0], ProposedParameterCBBBitmaskSize) {
+				if offset <= uint(Cei) {
+					r.ProposedParameterCBB = append(r.ProposedParameterCBB, ParameterCBBBit(offset))
+				}
+			}
+		case 0x82: // servicesSupportedCalling (BIT STRING)
+			if len(value) < 1 {
+				return errors.New("invalid servicesSupportedCalling: missing padding byte")
+			}
+			r.ServicesSupportedCalling = nil
+			for _, offset := range ber.DecodeBitmaskFromBytes(value[1:], value[0], ServicesSupportedCallingBitmaskSize) {
+				if offset <= uint(Cancel) {
+					r.ServicesSupportedCalling = append(r.ServicesSupportedCalling, ServiceSupportedBit(offset))
+				}
+			}
+		}
+	}
+	return nil
+}
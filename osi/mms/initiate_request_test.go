@@ -0,0 +1,63 @@
+package mms
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInitiateRequestNormalize(t *testing.T) {
+	r := NewInitiateRequest(
+		WithProposedMaxServOutstandingCalling(0),
+		WithProposedMaxServOutstandingCalled(100000),
+		WithProposedDataStructureNestingLevel(1000),
+	)
+	r.Normalize()
+
+	assert.Equal(t, uint32(1), r.ProposedMaxServOutstandingCalling)
+	assert.Equal(t, uint32(MaxServOutstanding), r.ProposedMaxServOutstandingCalled)
+	assert.Equal(t, uint32(MaxDataStructureNestingLevel), r.ProposedDataStructureNestingLevel)
+	assert.NoError(t, r.Validate())
+}
+
+func TestInitiateRequestValidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      []InitiateRequestOption
+		wantError string
+	}{
+		{
+			name: "параметры по умолчанию",
+		},
+		{
+			name:      "слишком маленький PDU",
+			opts:      []InitiateRequestOption{WithLocalDetailCalling(10)},
+			wantError: "localDetailCalling 10 out of range [64, 2147483647]",
+		},
+		{
+			name:      "неподдерживаемая версия",
+			opts:      []InitiateRequestOption{WithProposedVersionNumber(2)},
+			wantError: "proposedVersionNumber 2 is not supported, expected 1",
+		},
+		{
+			name: "несколько ошибок",
+			opts: []InitiateRequestOption{
+				WithProposedMaxServOutstandingCalling(0),
+				WithServicesSupportedCalling([]ServiceSupportedBit{Read, 200}),
+			},
+			wantError: "proposedMaxServOutstandingCalling 0 out of range [1, 32767]\n" +
+				"unknown servicesSupportedCalling bit 200",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewInitiateRequest(tt.opts...).Validate()
+			if tt.wantError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.wantError)
+			}
+		})
+	}
+}
@@ -3,9 +3,8 @@ package mms
 import (
 	"errors"
 	"fmt"
-	"strings"
 
-	"github.com/slonegd/go61850/ber"
+	"github.com/slonegd/go61850/internal/ber"
 )
 
 // InitiateResponse содержит параметры из MMS Initiate Response PDU
@@ -26,49 +25,63 @@ type InitiateResponse struct {
 	ServicesSupportedCalled []ServiceSupportedBit
 }
 
-// String реализует интерфейс fmt.Stringer для InitiateResponse.
-// Для NegotiatedParameterCBB и ServicesSupportedCalled выводит список установленных битов,
-// остальные поля выводятся как при %+v.
+// String возвращает поля InitiateResponse одной строкой в формате key=value;
+// для NegotiatedParameterCBB и ServicesSupportedCalled выводятся установленные биты,
+// для отсутствующих необязательных полей - <nil>
 func (r *InitiateResponse) String() string {
-	var parts []string
+	return r.fields().compact()
+}
+
+// Format реализует fmt.Formatter: %+v выводит поле на строку
+func (r *InitiateResponse) Format(s fmt.State, verb rune) {
+	r.fields().format(s, verb)
+}
 
+func (r *InitiateResponse) fields() *fields {
+	f := newFields("InitiateResponse")
 	if r.LocalDetailCalled != nil {
-		parts = append(parts, fmt.Sprintf("LocalDetailCalled:%d", *r.LocalDetailCalled))
+		f.addUint("LocalDetailCalled", *r.LocalDetailCalled)
 	} else {
-		parts = append(parts, "LocalDetailCalled:<nil>")
+		f.add("LocalDetailCalled", "<nil>")
 	}
-	parts = append(parts, fmt.Sprintf("NegotiatedMaxServOutstandingCalling:%d", r.NegotiatedMaxServOutstandingCalling))
-	parts = append(parts, fmt.Sprintf("NegotiatedMaxServOutstandingCalled:%d", r.NegotiatedMaxServOutstandingCalled))
+	f.addUint("NegotiatedMaxServOutstandingCalling", r.NegotiatedMaxServOutstandingCalling)
+	f.addUint("NegotiatedMaxServOutstandingCalled", r.NegotiatedMaxServOutstandingCalled)
 	if r.NegotiatedDataStructureNestingLevel != nil {
-		parts = append(parts, fmt.Sprintf("NegotiatedDataStructureNestingLevel:%d", *r.NegotiatedDataStructureNestingLevel))
+		f.addUint("NegotiatedDataStructureNestingLevel", *r.NegotiatedDataStructureNestingLevel)
 	} else {
-		parts = append(parts, "NegotiatedDataStructureNestingLevel:<nil>")
+		f.add("NegotiatedDataStructureNestingLevel", "<nil>")
 	}
-	parts = append(parts, fmt.Sprintf("NegotiatedVersionNumber:%d", r.NegotiatedVersionNumber))
+	f.addUint("NegotiatedVersionNumber", r.NegotiatedVersionNumber)
+	addList(f, "NegotiatedParameterCBB", r.NegotiatedParameterCBB)
+	addList(f, "ServicesSupportedCalled", r.ServicesSupportedCalled)
+	return f
+}
 
-	// NegotiatedParameterCBB - список установленных битов
-	if len(r.NegotiatedParameterCBB) > 0 {
-		bitNames := make([]string, len(r.NegotiatedParameterCBB))
-		for i, bit := range r.NegotiatedParameterCBB {
-			bitNames[i] = bit.String()
-		}
-		parts = append(parts, fmt.Sprintf("NegotiatedParameterCBB:[%s]", strings.Join(bitNames, " ")))
-	} else {
-		parts = append(parts, "NegotiatedParameterCBB:[]")
+// Bytes кодирует InitiateResponse в initiate-ResponsePDU (обратная операция
+// к ParseInitiateResponse): a9 { 80, 81, 82, 83, a4 { 80, 81, 82 } }.
+// Необязательные LocalDetailCalled и NegotiatedDataStructureNestingLevel
+// кодируются, только если заданы.
+func (r *InitiateResponse) Bytes() []byte {
+	var content []byte
+	if r.LocalDetailCalled != nil {
+		content = append(content, encodeUnsigned(ber.ContextSpecific0Primitive, *r.LocalDetailCalled)...)
 	}
-
-	// ServicesSupportedCalled - список установленных битов
-	if len(r.ServicesSupportedCalled) > 0 {
-		bitNames := make([]string, len(r.ServicesSupportedCalled))
-		for i, bit := range r.ServicesSupportedCalled {
-			bitNames[i] = bit.String()
-		}
-		parts = append(parts, fmt.Sprintf("ServicesSupportedCalled:[%s]", strings.Join(bitNames, " ")))
-	} else {
-		parts = append(parts, "ServicesSupportedCalled:[]")
+	content = append(content, encodeUnsigned(ber.ContextSpecific1Primitive, r.NegotiatedMaxServOutstandingCalling)...)
+	content = append(content, encodeUnsigned(ber.ContextSpecific2Primitive, r.NegotiatedMaxServOutstandingCalled)...)
+	if r.NegotiatedDataStructureNestingLevel != nil {
+		content = append(content, encodeUnsigned(ber.ContextSpecific3Primitive, *r.NegotiatedDataStructureNestingLevel)...)
 	}
 
-	return fmt.Sprintf("InitiateResponse{%s}", strings.Join(parts, " "))
+	// BIT STRING: число неиспользуемых бит (5 для параметров, 3 для услуг) и битовая маска
+	detail := encodeUnsigned(ber.ContextSpecific0Primitive, r.NegotiatedVersionNumber)
+	parameters := ber.EncodeBitmaskFromOffsets(r.NegotiatedParameterCBB, ProposedParameterCBBBitmaskSize)
+	detail = append(detail, wrapTL(ber.ContextSpecific1Primitive, append([]byte{0x05}, parameters...))...)
+	services := ber.EncodeBitmaskFromOffsets(r.ServicesSupportedCalled, ServicesSupportedCallingBitmaskSize)
+	detail = append(detail, wrapTL(ber.ContextSpecific2Primitive, append([]byte{0x03}, services...))...)
+	content = append(content, wrapTL(ber.ContextSpecific4Constructed, detail)...)
+
+	// 0xA9 = Context-specific 9, Constructed (initiate-ResponsePDU)
+	return wrapTL(0xA9, content)
 }
 
 // ParseInitiateResponse парсит BER-кодированный MMS Initiate Response PDU.
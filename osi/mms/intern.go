@@ -0,0 +1,45 @@
+package mms
+
+import "sync"
+
+const (
+	// maxInternedStrings ограничивает таблицу интернирования, чтобы поток
+	// уникальных строк не приводил к неограниченному росту памяти
+	maxInternedStrings = 4096
+	// maxInternedLength - максимальная длина интернируемой строки
+	// (ObjectReference IEC 61850 не длиннее 129 символов)
+	maxInternedLength = 129
+)
+
+// internTable - строки, повторяющиеся от PDU к PDU: RptID, ссылки на наборы данных
+// и переменные, имена объектов с функциональными связями ("LLN0$ST$Mod$stVal")
+var internTable = struct {
+	sync.RWMutex
+	strings map[string]string
+}{strings: make(map[string]string)}
+
+// intern возвращает строку с содержимым b, не выделяя память, если такая
+// строка уже встречалась
+func intern(b []byte) string {
+	if len(b) == 0 {
+		return ""
+	}
+	if len(b) > maxInternedLength {
+		return string(b)
+	}
+
+	internTable.RLock()
+	s, ok := internTable.strings[string(b)]
+	internTable.RUnlock()
+	if ok {
+		return s
+	}
+
+	s = string(b)
+	internTable.Lock()
+	if len(internTable.strings) < maxInternedStrings {
+		internTable.strings[s] = s
+	}
+	internTable.Unlock()
+	return s
+}
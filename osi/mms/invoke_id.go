@@ -0,0 +1,79 @@
+package mms
+
+import (
+	"errors"
+	"math"
+	"sync"
+)
+
+// ErrNoFreeInvokeID возвращается, когда все invokeID заняты запросами в работе
+var ErrNoFreeInvokeID = errors.New("no free invokeID")
+
+// InvokeIDAllocator выдаёт invokeID для confirmed-RequestPDU.
+// Значения выдаются последовательно с переходом через 2^32 обратно к 0,
+// при этом пропускаются invokeID запросов, ответ на которые ещё не получен.
+// Безопасен для конкурентного использования.
+type InvokeIDAllocator struct {
+	mu       sync.Mutex
+	next     uint32
+	inFlight map[uint32]struct{}
+}
+
+// InvokeIDAllocatorOption представляет опцию для настройки InvokeIDAllocator
+type InvokeIDAllocatorOption func(*InvokeIDAllocator)
+
+// WithInvokeIDSeed задаёт первый выдаваемый invokeID.
+// Полезно для воспроизводимых тестов и для сравнения с дампами wireshark.
+func WithInvokeIDSeed(seed uint32) InvokeIDAllocatorOption {
+	return func(a *InvokeIDAllocator) {
+		a.next = seed
+	}
+}
+
+// NewInvokeIDAllocator создаёт аллокатор invokeID.
+// По умолчанию первый invokeID равен 1, как у libIEC61850.
+func NewInvokeIDAllocator(opts ...InvokeIDAllocatorOption) *InvokeIDAllocator {
+	a := &InvokeIDAllocator{
+		next:     1,
+		inFlight: make(map[uint32]struct{}),
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+// Allocate резервирует и возвращает следующий свободный invokeID.
+// Зарезервированный invokeID нужно освободить через Release после получения ответа.
+func (a *InvokeIDAllocator) Allocate() (uint32, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	// Поиск ведётся, только пока гарантированно есть свободный invokeID
+	if uint64(len(a.inFlight)) >= math.MaxUint32 {
+		return 0, ErrNoFreeInvokeID
+	}
+
+	for {
+		id := a.next
+		a.next++ // переполнение uint32 даёт переход к 0
+		if _, busy := a.inFlight[id]; !busy {
+			a.inFlight[id] = struct{}{}
+			return id, nil
+		}
+	}
+}
+
+// Release освобождает invokeID после получения ответа или отмены запроса
+func (a *InvokeIDAllocator) Release(id uint32) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	delete(a.inFlight, id)
+}
+
+// InFlight возвращает количество зарезервированных invokeID
+func (a *InvokeIDAllocator) InFlight() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return len(a.inFlight)
+}
@@ -0,0 +1,51 @@
+package mms
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInvokeIDAllocator(t *testing.T) {
+	a := NewInvokeIDAllocator()
+	id, err := a.Allocate()
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(1), id)
+	id, err = a.Allocate()
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(2), id)
+	assert.Equal(t, 2, a.InFlight())
+
+	a.Release(1)
+	a.Release(2)
+	assert.Equal(t, 0, a.InFlight())
+}
+
+func TestInvokeIDAllocatorWraparound(t *testing.T) {
+	a := NewInvokeIDAllocator(WithInvokeIDSeed(math.MaxUint32 - 1))
+
+	var got []uint32
+	for range 3 {
+		id, err := a.Allocate()
+		assert.NoError(t, err)
+		got = append(got, id)
+	}
+	assert.Equal(t, []uint32{math.MaxUint32 - 1, math.MaxUint32, 0}, got)
+}
+
+func TestInvokeIDAllocatorSkipsInFlight(t *testing.T) {
+	a := NewInvokeIDAllocator(WithInvokeIDSeed(math.MaxUint32))
+
+	// Занимаем MaxUint32 и 0, освобождаем только MaxUint32
+	first, _ := a.Allocate()
+	second, _ := a.Allocate()
+	assert.Equal(t, uint32(0), second)
+	a.Release(first)
+
+	// Проходим круг до 0 вручную: 0 всё ещё в работе и должен быть пропущен
+	a.next = 0
+	id, err := a.Allocate()
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(1), id)
+}
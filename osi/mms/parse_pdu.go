@@ -0,0 +1,198 @@
+package mms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/slonegd/go61850/internal/ber"
+)
+
+// PDU - разобранный MMS PDU (ParsePDU, Client.ReceivePDU). Конкретный тип
+// определяется вариантом MmsPdu:
+//
+//	confirmed-RequestPDU    *ConfirmedRequest
+//	confirmed-ResponsePDU   *ConfirmedResponse
+//	confirmed-ErrorPDU      *ConfirmedError
+//	unconfirmed-PDU         *InformationReportPDU
+//	rejectPDU               *Reject
+//	cancel-RequestPDU       *CancelRequest
+//	cancel-ResponsePDU      *CancelResponse
+//	cancel-ErrorPDU         *CancelError
+//	initiate-RequestPDU     *InitiateRequest
+//	initiate-ResponsePDU    *InitiateResponse
+//	conclude-RequestPDU     *ConcludeRequest
+//	conclude-ResponsePDU    *ConcludeResponse
+type PDU interface {
+	mmsPDU()
+}
+
+func (*ConfirmedRequest) mmsPDU()     {}
+func (*ConfirmedResponse) mmsPDU()    {}
+func (*ConfirmedError) mmsPDU()       {}
+func (*InformationReportPDU) mmsPDU() {}
+func (*Reject) mmsPDU()               {}
+func (*CancelRequest) mmsPDU()        {}
+func (*CancelResponse) mmsPDU()       {}
+func (*CancelError) mmsPDU()          {}
+func (*InitiateRequest) mmsPDU()      {}
+func (*InitiateResponse) mmsPDU()     {}
+func (*ConcludeRequest) mmsPDU()      {}
+func (*ConcludeResponse) mmsPDU()     {}
+
+// ConcludeRequest представляет conclude-RequestPDU [11] IMPLICIT NULL
+type ConcludeRequest struct{}
+
+// Bytes кодирует conclude-RequestPDU: 8b 00
+func (*ConcludeRequest) Bytes() []byte {
+	return []byte{0x8b, 0x00}
+}
+
+// ConcludeResponse представляет conclude-ResponsePDU [12] IMPLICIT NULL
+type ConcludeResponse struct{}
+
+// Bytes кодирует conclude-ResponsePDU: 8c 00
+func (*ConcludeResponse) Bytes() []byte {
+	return []byte{0x8c, 0x00}
+}
+
+// ParsePDU разбирает MMS PDU любого варианта, перечисленного в PDU.
+// Аргумент confirmed-RequestPDU и ответ confirmed-ResponsePDU не разбираются:
+// для них предназначены функции Parse* соответствующего сервиса.
+// initiate-ErrorPDU, conclude-ErrorPDU и unconfirmed-PDU, отличные
+// от informationReport, не поддерживаются.
+func ParsePDU(buffer []byte) (PDU, error) {
+	if len(buffer) == 0 {
+		return nil, fmt.Errorf("empty MMS PDU")
+	}
+	switch buffer[0] {
+	case byte(ber.ContextSpecific0Constructed):
+		return ParseConfirmedRequest(buffer)
+	case byte(ber.ContextSpecific1Constructed):
+		return ParseConfirmedResponse(buffer)
+	case byte(ber.ContextSpecific2Constructed):
+		return ParseConfirmedError(buffer)
+	case byte(ber.ContextSpecific3Constructed):
+		// unconfirmed-PDU { service: informationReport [0] }
+		_, content, _, err := decodeTLV(buffer, 0, len(buffer))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode unconfirmed-PDU: %w", err)
+		}
+		if len(content) == 0 || content[0] != byte(ber.ContextSpecific0Constructed) {
+			return nil, fmt.Errorf("unsupported unconfirmed-PDU service")
+		}
+		return ParseInformationReport(buffer)
+	case byte(ber.ContextSpecific4Constructed):
+		return ParseReject(buffer)
+	case byte(ber.ContextSpecific5Primitive):
+		return ParseCancelRequest(buffer)
+	case byte(ber.ContextSpecific6Primitive):
+		invokeID, err := ParseCancelResult(buffer)
+		if err != nil {
+			return nil, err
+		}
+		return &CancelResponse{InvokeID: invokeID}, nil
+	case byte(ber.ContextSpecific7Constructed):
+		_, err := ParseCancelResult(buffer)
+		var cancelError *CancelError
+		if errors.As(err, &cancelError) {
+			return cancelError, nil
+		}
+		return nil, err
+	case 0xa8:
+		return ParseInitiateRequest(buffer)
+	case 0xa9:
+		return ParseInitiateResponse(buffer)
+	case 0x8b, 0x8c:
+		content, err := expectTLV(buffer, buffer[0], pduNames[buffer[0]])
+		if err != nil {
+			return nil, err
+		}
+		if len(content) != 0 {
+			return nil, fmt.Errorf("%s is not NULL", pduNames[buffer[0]])
+		}
+		if buffer[0] == 0x8b {
+			return &ConcludeRequest{}, nil
+		}
+		return &ConcludeResponse{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported MMS PDU: %s", Summary(buffer))
+	}
+}
+
+// ParseConfirmedResponse парсит confirmed-ResponsePDU: a1 { 02 invokeID, service }.
+// Service содержит закодированный ответ сервиса целиком (см. ServiceType).
+func ParseConfirmedResponse(buffer []byte) (*ConfirmedResponse, error) {
+	content, err := expectTLV(buffer, byte(ber.ContextSpecific1Constructed), "confirmed-ResponsePDU")
+	if err != nil {
+		return nil, err
+	}
+	tag, value, bufPos, err := decodeTLV(content, 0, len(content))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode invokeID: %w", err)
+	}
+	if tag != byte(ber.Integer) || len(value) < 1 || len(value) > 5 {
+		return nil, fmt.Errorf("invalid invokeID in confirmed-ResponsePDU")
+	}
+	response := &ConfirmedResponse{InvokeID: ber.DecodeUint32(value, len(value), 0)}
+	if _, _, _, err := decodeServiceTLV(content, bufPos, len(content)); err != nil {
+		return nil, fmt.Errorf("failed to decode service of invokeID %d: %w", response.InvokeID, err)
+	}
+	response.Service = content[bufPos:]
+	return response, nil
+}
+
+// ServiceType возвращает сервис, на который получен ответ
+func (r *ConfirmedResponse) ServiceType() (ConfirmedService, error) {
+	service, _, _, err := decodeServiceTLV(r.Service, 0, len(r.Service))
+	return service, err
+}
+
+// ParseReject парсит RejectPDU на confirmed-RequestPDU (обратная операция к Bytes)
+func ParseReject(buffer []byte) (*Reject, error) {
+	content, err := expectTLV(buffer, byte(ber.ContextSpecific4Constructed), "rejectPDU")
+	if err != nil {
+		return nil, err
+	}
+	reject := &Reject{}
+	var hasReason bool
+	for bufPos := 0; bufPos < len(content); {
+		tag, value, next, err := decodeTLV(content, bufPos, len(content))
+		if err != nil {
+			return nil, err
+		}
+		if len(value) < 1 || len(value) > 5 {
+			return nil, fmt.Errorf("invalid length of tag 0x%02x in rejectPDU: %d", tag, len(value))
+		}
+		switch tag {
+		case byte(ber.ContextSpecific0Primitive): // originalInvokeID
+			reject.InvokeID = ber.DecodeUint32(value, len(value), 0)
+		case byte(ber.ContextSpecific1Primitive): // confirmed-requestPDU
+			reject.Reason = RejectReason(ber.DecodeUint32(value, len(value), 0))
+			hasReason = true
+		default:
+			return nil, fmt.Errorf("unsupported rejectReason in rejectPDU: 0x%02x", tag)
+		}
+		bufPos = next
+	}
+	if !hasReason {
+		return nil, fmt.Errorf("rejectPDU does not contain rejectReason")
+	}
+	return reject, nil
+}
+
+// ReceivePDU принимает следующий MMS PDU и разбирает его ParsePDU. Предназначен
+// для ручного управления приёмом, например в тестерах соответствия, проверяющих
+// точную последовательность PDU сервера. Ошибка разбора оборачивается вместе
+// с кратким описанием PDU (Summary).
+func (c *Client) ReceivePDU(ctx context.Context) (PDU, error) {
+	mmsData, err := c.ReceiveAndParseMmsResponse(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pdu, err := ParsePDU(mmsData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", Summary(mmsData), err)
+	}
+	return pdu, nil
+}
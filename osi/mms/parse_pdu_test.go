@@ -0,0 +1,71 @@
+package mms
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/slonegd/go61850/osi/cotp"
+	"github.com/slonegd/go61850/osi/mms/variant"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePDU(t *testing.T) {
+	report, err := (&InformationReportPDU{
+		Variables: []ObjectName{{ItemID: "LastApplError"}},
+		Results:   []AccessResult{{Success: true, Value: variant.NewInt32Variant(1)}},
+	}).Bytes()
+	assert.NoError(t, err)
+	limits := defaultInitiateLimits()
+	serviceError := ServiceError{Class: ErrorClassDefinition, Code: DefinitionErrorObjectUndefined}
+
+	for _, buffer := range [][]byte{
+		(&ReadRequest{InvokeID: 1, DomainID: "simpleIOGenericIO", ItemID: "GGIO1$MX"}).Bytes(),
+		(&ConfirmedError{InvokeID: 2, ServiceError: serviceError}).Bytes(),
+		report,
+		(&Reject{InvokeID: 3, Reason: RejectUnrecognizedService}).Bytes(),
+		(&CancelRequest{InvokeID: 4}).Bytes(),
+		(&CancelResponse{InvokeID: 4}).Bytes(),
+		(&CancelError{InvokeID: 5, ServiceError: serviceError}).Bytes(),
+		NewInitiateRequest().Bytes(),
+		limits.Bytes(),
+		(&ConcludeRequest{}).Bytes(),
+		(&ConcludeResponse{}).Bytes(),
+	} {
+		t.Run(Summary(buffer), func(t *testing.T) {
+			pdu, err := ParsePDU(buffer)
+			assert.NoError(t, err)
+			assert.NotNil(t, pdu)
+		})
+	}
+
+	pdu, err := ParsePDU(parseHexString("a1 0e 020107 a4 09 a1 07 87 05 08 3f c0 00 00"))
+	assert.NoError(t, err)
+	response := pdu.(*ConfirmedResponse)
+	assert.Equal(t, uint32(7), response.InvokeID)
+	service, err := response.ServiceType()
+	assert.NoError(t, err)
+	assert.Equal(t, ServiceRead, service)
+
+	pdu, err = ParsePDU((&Reject{InvokeID: 3, Reason: RejectInvalidArgument}).Bytes())
+	assert.NoError(t, err)
+	assert.Equal(t, &Reject{InvokeID: 3, Reason: RejectInvalidArgument}, pdu)
+
+	_, err = ParsePDU(parseHexString("a3 03 a1 01 00"))
+	assert.EqualError(t, err, "unsupported unconfirmed-PDU service")
+	_, err = ParsePDU(parseHexString("aa 00"))
+	assert.EqualError(t, err, "unsupported MMS PDU: initiate-ErrorPDU")
+}
+
+func TestClientReceivePDU(t *testing.T) {
+	packet := parseHexString("0300001c 02f080 01000100 610f 300d 020103 a008 a406 800109 810101")
+	c := NewClient(cotp.NewConnection(readConn{bytes.NewReader(packet)}), nil)
+	pdu, err := c.ReceivePDU(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, &Reject{InvokeID: 9, Reason: RejectUnrecognizedService}, pdu)
+
+	packet = parseHexString("03000016 02f080 01000100 6109 3007 020103 a002 aa00")
+	c = NewClient(cotp.NewConnection(readConn{bytes.NewReader(packet)}), nil)
+	_, err = c.ReceivePDU(context.Background())
+	assert.EqualError(t, err, "failed to parse initiate-ErrorPDU: unsupported MMS PDU: initiate-ErrorPDU")
+}
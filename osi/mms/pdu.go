@@ -0,0 +1,163 @@
+package mms
+
+//go:generate go run ../../internal/cmd/asn1gen -i services.asn -o services_gen.go -pkg mms
+
+import (
+	"fmt"
+
+	"github.com/slonegd/go61850/internal/ber"
+)
+
+// wrapTL оборачивает содержимое в тег и длину
+func wrapTL(tag ber.Tag, content []byte) []byte {
+	buffer := make([]byte, 1+ber.DetermineLengthSize(uint32(len(content)))+len(content))
+	bufPos := ber.EncodeTL(tag, uint32(len(content)), buffer, 0)
+	copy(buffer[bufPos:], content)
+	return buffer
+}
+
+// encodeInvokeID кодирует invokeID как INTEGER (0x02), как в wireshark
+func encodeInvokeID(invokeID uint32) []byte {
+	tempBuf := make([]byte, 8)
+	tempPos := ber.EncodeUInt32(invokeID, tempBuf, 0)
+	return wrapTL(ber.Integer, tempBuf[:tempPos])
+}
+
+// encodeUnsigned кодирует value как Unsigned32 с тегом tag
+func encodeUnsigned(tag ber.Tag, value uint32) []byte {
+	tempBuf := make([]byte, 8)
+	tempPos := ber.EncodeUInt32(value, tempBuf, 0)
+	return wrapTL(tag, tempBuf[:tempPos])
+}
+
+// decodeTLV читает один BER элемент (тег + длина + значение), начиная с bufPos.
+// Возвращает тег, содержимое элемента и позицию следующего элемента.
+func decodeTLV(buffer []byte, bufPos, maxBufPos int) (tag byte, content []byte, next int, err error) {
+	if bufPos >= maxBufPos {
+		return 0, nil, 0, fmt.Errorf("unexpected end of buffer at %d", bufPos)
+	}
+	tag = buffer[bufPos]
+	bufPos++
+
+	newPos, length, err := ber.DecodeLength(buffer, bufPos, maxBufPos)
+	if err != nil {
+		return 0, nil, 0, fmt.Errorf("failed to decode length for tag 0x%02x: %w", tag, err)
+	}
+	if newPos+length > maxBufPos {
+		return 0, nil, 0, fmt.Errorf("invalid length for tag 0x%02x: exceeds buffer size", tag)
+	}
+
+	return tag, buffer[newPos : newPos+length], newPos + length, nil
+}
+
+// expectTLV читает один BER элемент и проверяет его тег
+func expectTLV(buffer []byte, expected byte, what string) ([]byte, error) {
+	tag, content, _, err := decodeTLV(buffer, 0, len(buffer))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", what, err)
+	}
+	if tag != expected {
+		return nil, fmt.Errorf("invalid tag for %s: expected 0x%02x, got 0x%02x", what, expected, tag)
+	}
+	return content, nil
+}
+
+// wrapServiceTL оборачивает содержимое в context-specific тег сервиса service.
+// Номера сервисов от 31 (например, getDomainAttributes [37]) кодируются
+// в расширенной форме: 0x9f/0xbf и номер вторым байтом.
+func wrapServiceTL(service ConfirmedService, constructed bool, content []byte) []byte {
+	class := byte(0x80)
+	if constructed {
+		class |= 0x20
+	}
+	if service < 31 {
+		return wrapTL(ber.Tag(class|byte(service)), content)
+	}
+	length := make([]byte, ber.DetermineLengthSize(uint32(len(content))))
+	ber.EncodeLength(uint32(len(content)), length, 0)
+	buffer := append([]byte{class | 0x1f, byte(service)}, length...)
+	return append(buffer, content...)
+}
+
+// decodeServiceTLV читает элемент с context-specific тегом сервиса,
+// в том числе в расширенной форме (номер до 127).
+// Возвращает номер сервиса, содержимое и позицию следующего элемента.
+func decodeServiceTLV(buffer []byte, bufPos, maxBufPos int) (service ConfirmedService, content []byte, next int, err error) {
+	if bufPos >= maxBufPos {
+		return 0, nil, 0, fmt.Errorf("unexpected end of buffer at %d", bufPos)
+	}
+	tag := buffer[bufPos]
+	if tag&0xc0 != 0x80 {
+		return 0, nil, 0, fmt.Errorf("unexpected service tag 0x%02x", tag)
+	}
+	if tag&0x1f != 0x1f {
+		_, content, next, err = decodeTLV(buffer, bufPos, maxBufPos)
+		return ConfirmedService(tag & 0x1f), content, next, err
+	}
+	if bufPos+1 >= maxBufPos || buffer[bufPos+1]&0x80 != 0 {
+		return 0, nil, 0, fmt.Errorf("unsupported high tag number at %d", bufPos)
+	}
+	service = ConfirmedService(buffer[bufPos+1])
+	newPos, length, err := ber.DecodeLength(buffer, bufPos+2, maxBufPos)
+	if err != nil {
+		return 0, nil, 0, fmt.Errorf("failed to decode length for service %s: %w", service, err)
+	}
+	if newPos+length > maxBufPos {
+		return 0, nil, 0, fmt.Errorf("invalid length for service %s: exceeds buffer size", service)
+	}
+	return service, buffer[newPos : newPos+length], newPos + length, nil
+}
+
+// encodeInt возвращает содержимое INTEGER (минимальный дополнительный код)
+func encodeInt(value int32) []byte {
+	buffer := make([]byte, 8)
+	bufPos := ber.EncodeInt32(value, buffer, 0)
+	return buffer[:bufPos]
+}
+
+// decodeInt декодирует содержимое INTEGER; пустое содержимое даёт 0
+func decodeInt(value []byte) int32 {
+	if len(value) == 0 {
+		return 0
+	}
+	return ber.DecodeInt32(value, len(value), 0)
+}
+
+// encodeBool возвращает содержимое BOOLEAN
+func encodeBool(value bool) []byte {
+	if value {
+		return []byte{0xff}
+	}
+	return []byte{0x00}
+}
+
+// decodeBool декодирует содержимое BOOLEAN
+func decodeBool(value []byte) bool {
+	return len(value) > 0 && value[0] != 0
+}
+
+// encodeStringList кодирует содержимое SEQUENCE OF строк как VisibleString
+func encodeStringList(list []string) []byte {
+	var content []byte
+	for _, s := range list {
+		content = append(content, wrapTL(ber.VisibleString, []byte(s))...)
+	}
+	return content
+}
+
+// parseStringList парсит SEQUENCE OF строк (VisibleString или UTF8String для MMSString)
+func parseStringList(buffer []byte, what string) ([]string, error) {
+	list := []string{}
+	for bufPos := 0; bufPos < len(buffer); {
+		tag, value, next, err := decodeTLV(buffer, bufPos, len(buffer))
+		if err != nil {
+			return nil, err
+		}
+		if tag != byte(ber.VisibleString) && tag != byte(ber.UTF8String) {
+			return nil, fmt.Errorf("unexpected %s tag: 0x%02x", what, tag)
+		}
+		list = append(list, string(value))
+		bufPos = next
+	}
+	return list, nil
+}
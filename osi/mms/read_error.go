@@ -0,0 +1,78 @@
+package mms
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ReadFailure - неуспешный результат многоэлементного чтения
+type ReadFailure struct {
+	// Index - позиция результата в listOfAccessResult
+	Index int
+	// Variable - имя переменной, если известно (например, ссылка элемента набора данных)
+	Variable string
+	// Err - причина отказа; nil, если сервер вернул failure без кода
+	Err *DataAccessError
+}
+
+// ReadError - отказ в доступе к части переменных многоэлементного чтения.
+// Успешные значения возвращаются вместе с ReadError; Unwrap отдаёт ошибки
+// отдельных переменных, поэтому errors.As(err, &*DataAccessError) находит первую
+// из них, а errors.Is(err, &DataAccessError{ErrorCode: ...}) проверяет наличие кода.
+type ReadError struct {
+	// Total - число результатов в ответе
+	Total    int
+	Failures []ReadFailure
+}
+
+// NewReadError возвращает ReadError для неуспешных results или nil, если все
+// результаты успешны. names - необязательные имена переменных в порядке results.
+func NewReadError(results []AccessResult, names []string) *ReadError {
+	var failures []ReadFailure
+	for i, result := range results {
+		if result.Success {
+			continue
+		}
+		failure := ReadFailure{Index: i, Err: result.Error}
+		if i < len(names) {
+			failure.Variable = names[i]
+		}
+		failures = append(failures, failure)
+	}
+	if failures == nil {
+		return nil
+	}
+	return &ReadError{Total: len(results), Failures: failures}
+}
+
+// Error реализует интерфейс error
+func (e *ReadError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "read failed for %d of %d variables:", len(e.Failures), e.Total)
+	for i, f := range e.Failures {
+		if i > 0 {
+			b.WriteString(";")
+		}
+		name := f.Variable
+		if name == "" {
+			name = fmt.Sprintf("#%d", f.Index)
+		}
+		reason := "failure"
+		if f.Err != nil {
+			reason = f.Err.String()
+		}
+		fmt.Fprintf(&b, " %s: %s", name, reason)
+	}
+	return b.String()
+}
+
+// Unwrap возвращает ошибки доступа отдельных переменных
+func (e *ReadError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Failures))
+	for _, f := range e.Failures {
+		if f.Err != nil {
+			errs = append(errs, f.Err)
+		}
+	}
+	return errs
+}
@@ -0,0 +1,32 @@
+package mms
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/slonegd/go61850/osi/mms/variant"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadError(t *testing.T) {
+	results := []AccessResult{
+		{Success: true, Value: variant.NewBoolVariant(true)},
+		{Error: &DataAccessError{ErrorCode: ObjectNonExistent}},
+		{Success: true, Value: variant.NewInt32Variant(5)},
+		{Error: &DataAccessError{ErrorCode: ObjectAccessDenied}},
+	}
+	assert.Nil(t, NewReadError(results[:1], nil))
+
+	var err error = NewReadError(results, []string{"LD/A", "LD/B"})
+	assert.EqualError(t, err, "read failed for 2 of 4 variables: LD/B: object-non-existent; #3: object-access-denied")
+	assert.True(t, errors.Is(err, &DataAccessError{ErrorCode: ObjectAccessDenied}))
+	assert.False(t, errors.Is(err, &DataAccessError{ErrorCode: HardwareFault}))
+
+	var accessErr *DataAccessError
+	assert.True(t, errors.As(err, &accessErr))
+	assert.Equal(t, ObjectNonExistent, accessErr.ErrorCode)
+
+	var readErr *ReadError
+	assert.True(t, errors.As(err, &readErr))
+	assert.Equal(t, []int{1, 3}, []int{readErr.Failures[0].Index, readErr.Failures[1].Index})
+}
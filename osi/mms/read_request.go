@@ -1,9 +1,10 @@
 package mms
 
 import (
+	"fmt"
 	"strings"
 
-	"github.com/slonegd/go61850/ber"
+	"github.com/slonegd/go61850/internal/ber"
 )
 
 // ReadRequest представляет MMS Read Request PDU
@@ -18,7 +19,8 @@ import (
 //
 //	Read-Request ::= SEQUENCE {
 //	  variableAccessSpecification [0] CHOICE {
-//	    listOfVariable [0] SEQUENCE OF VariableAccessSpecification
+//	    listOfVariable   [0] SEQUENCE OF VariableAccessSpecification,
+//	    variableListName [1] ObjectName
 //	  }
 //	}
 //
@@ -28,10 +30,30 @@ import (
 type ReadRequest struct {
 	// InvokeID - идентификатор вызова (обычно 1 для первого запроса)
 	InvokeID uint32
-	// DomainID - имя домена (например, "simpleIOGenericIO")
+	// DomainID - имя домена (например, "simpleIOGenericIO").
+	// Пустой DomainID означает переменную уровня VMD (vmd-specific имя).
 	DomainID string
 	// ItemID - имя элемента (например, "GGIO1$MX$AnIn1$mag$f" или "GGIO1.AnIn1.mag.f")
 	ItemID string
+	// Items - переменные запроса нескольких переменных; если задан,
+	// DomainID и ItemID не используются
+	Items []ObjectName
+	// DataSet - имя набора данных (variableListName, см. ReadDataSetRequest);
+	// если задан, DomainID, ItemID и Items не используются
+	DataSet *ObjectName
+}
+
+// Variables возвращает переменные запроса в порядке listOfVariable:
+// Items или одну переменную DomainID/ItemID. Для чтения набора данных - nil.
+func (r *ReadRequest) Variables() []ObjectName {
+	switch {
+	case r.DataSet != nil:
+		return nil
+	case len(r.Items) > 0:
+		return r.Items
+	default:
+		return []ObjectName{{DomainID: r.DomainID, ItemID: r.ItemID}}
+	}
 }
 
 // Bytes кодирует ReadRequest в BER-кодированный пакет MMS confirmed-RequestPDU
@@ -48,94 +70,49 @@ type ReadRequest struct {
 //	                  1a 11 - domainId (VisibleString, длина 17 байт): "simpleIOGenericIO"
 //	                  1a 14 - itemId (VisibleString, длина 20 байт): "GGIO1$MX$AnIn1$mag$f"
 func (r *ReadRequest) Bytes() []byte {
-	buffer := make([]byte, 512)
-	bufPos := 0
-
-	// Сначала строим внутреннее содержимое, чтобы знать его размер
-	innerContent := r.buildReadRequestContent()
-
 	// Кодируем confirmed-RequestPDU (Context-specific 0, Constructed)
 	// 0xa0 = Context-specific 0, Constructed
-	bufPos = ber.EncodeTL(ber.ContextSpecific0Constructed, uint32(len(innerContent)), buffer, bufPos)
-	copy(buffer[bufPos:], innerContent)
-	bufPos += len(innerContent)
-
-	return buffer[:bufPos]
+	return wrapTL(ber.ContextSpecific0Constructed, r.buildReadRequestContent())
 }
 
 // buildReadRequestContent собирает содержимое confirmed-RequestPDU
 func (r *ReadRequest) buildReadRequestContent() []byte {
-	buffer := make([]byte, 512)
-	bufPos := 0
-
-	// invokeID (Context-specific 0, INTEGER)
-	// В MMS invokeID кодируется как INTEGER с контекстно-зависимым тегом [0] IMPLICIT
-	// В wireshark видно 02 01 01, что означает INTEGER (0x02) с длиной 1 и значением 1
-	// Это означает, что используется обычный INTEGER, а не Context-specific 0
-	// Возможно, это особенность кодирования IMPLICIT или отображения wireshark
-	// Используем обычный INTEGER, как показано в wireshark
-	tempBuf := make([]byte, 256)
-	tempPos := ber.EncodeUInt32(r.InvokeID, tempBuf, 0)
-	intValue := tempBuf[0:tempPos]
-	// invokeID кодируется как обычный INTEGER (0x02), как в wireshark
-	bufPos = ber.EncodeTL(ber.Integer, uint32(len(intValue)), buffer, bufPos)
-	copy(buffer[bufPos:], intValue)
-	bufPos += len(intValue)
+	// invokeID кодируется как обычный INTEGER (0x02), как в wireshark:
+	// 02 01 01 - INTEGER с длиной 1 и значением 1
+	buffer := encodeInvokeID(r.InvokeID)
 
 	// confirmedServiceRequest: read (Context-specific 4, Constructed)
 	// В wireshark видно a4, что означает Context-specific 4, Constructed
-	// Это соответствует тегу [1] в структуре confirmed-RequestPDU
-	// Но в wireshark показано a4 (Context-specific 4), используем его
-	readRequestContent := r.buildReadServiceRequest()
-	bufPos = ber.EncodeTL(ber.ContextSpecific4Constructed, uint32(len(readRequestContent)), buffer, bufPos)
-	copy(buffer[bufPos:], readRequestContent)
-	bufPos += len(readRequestContent)
-
-	return buffer[:bufPos]
+	return append(buffer, wrapTL(ber.ContextSpecific4Constructed, r.buildReadServiceRequest())...)
 }
 
 // buildReadServiceRequest собирает содержимое read service request
 func (r *ReadRequest) buildReadServiceRequest() []byte {
-	buffer := make([]byte, 512)
-	bufPos := 0
-
-	// read (Context-specific 1, Constructed)
+	// variableAccessSpecification (Context-specific 1, Constructed)
 	// В wireshark видно a1 31, что означает Context-specific 1, Constructed, длина 49
-	// Это соответствует тегу [1] для read в структуре confirmedServiceRequest
-	readContent := r.buildReadContent()
-	bufPos = ber.EncodeTL(ber.ContextSpecific1Constructed, uint32(len(readContent)), buffer, bufPos)
-	copy(buffer[bufPos:], readContent)
-	bufPos += len(readContent)
-
-	return buffer[:bufPos]
+	return wrapTL(ber.ContextSpecific1Constructed, r.buildReadContent())
 }
 
-// buildReadContent собирает содержимое Read-Request
+// buildReadContent собирает содержимое variableAccessSpecification:
+// listOfVariable или variableListName набора данных
 func (r *ReadRequest) buildReadContent() []byte {
-	buffer := make([]byte, 512)
-	bufPos := 0
-
+	if r.DataSet != nil {
+		// variableListName (Context-specific 1, Constructed)
+		return wrapTL(ber.ContextSpecific1Constructed, encodeObjectName(*r.DataSet))
+	}
 	// variableAccessSpecification: listOfVariable (Context-specific 0, Constructed)
-	listOfVariableContent := r.buildListOfVariable()
-	bufPos = ber.EncodeTL(ber.ContextSpecific0Constructed, uint32(len(listOfVariableContent)), buffer, bufPos)
-	copy(buffer[bufPos:], listOfVariableContent)
-	bufPos += len(listOfVariableContent)
-
-	return buffer[:bufPos]
+	return wrapTL(ber.ContextSpecific0Constructed, r.buildListOfVariable())
 }
 
 // buildListOfVariable собирает SEQUENCE OF VariableAccessSpecification
 func (r *ReadRequest) buildListOfVariable() []byte {
-	buffer := make([]byte, 512)
-	bufPos := 0
-
-	// listOfVariable (SEQUENCE, Constructed)
-	variableSpecContent := r.buildVariableSpecification()
-	bufPos = ber.EncodeTL(ber.SequenceConstructed, uint32(len(variableSpecContent)), buffer, bufPos)
-	copy(buffer[bufPos:], variableSpecContent)
-	bufPos += len(variableSpecContent)
-
-	return buffer[:bufPos]
+	var buffer []byte
+	for _, variable := range r.Variables() {
+		// listOfVariable (SEQUENCE, Constructed)
+		name := &ReadRequest{DomainID: variable.DomainID, ItemID: variable.ItemID}
+		buffer = append(buffer, wrapTL(ber.SequenceConstructed, name.buildVariableSpecification())...)
+	}
+	return buffer
 }
 
 // buildVariableSpecification собирает VariableAccessSpecification
@@ -152,8 +129,14 @@ func (r *ReadRequest) buildVariableSpecification() []byte {
 	return buffer[:bufPos]
 }
 
-// buildObjectName собирает ObjectName в формате domain-specific
+// buildObjectName собирает ObjectName в формате domain-specific,
+// а при пустом DomainID - vmd-specific
 func (r *ReadRequest) buildObjectName() []byte {
+	if r.DomainID == "" {
+		// name: vmd-specific (Context-specific 0, Primitive, Identifier)
+		return wrapTL(ber.ContextSpecific0Primitive, []byte(r.ItemID))
+	}
+
 	buffer := make([]byte, 512)
 	bufPos := 0
 
@@ -180,6 +163,107 @@ func (r *ReadRequest) buildDomainSpecificName() []byte {
 	return buffer[:bufPos]
 }
 
+// ParseReadRequest парсит MMS Read Request PDU (обратная операция к Bytes).
+// Используется серверной стороной. Запрос одной переменной заполняет DomainID
+// и ItemID, нескольких - Items, чтение набора данных (variableListName) - DataSet.
+// Имена переменных - domain-specific или vmd-specific (80, только itemId):
+//
+//	a0 (confirmed-RequestPDU)
+//	  02 (invokeID)
+//	  a4 (read)
+//	    a1 (variableAccessSpecification)
+//	      a0 (listOfVariable)
+//	        30 (SEQUENCE)
+//	          a0 (name)
+//	            a1 (domain-specific)
+//	              1a (domainId) 1a (itemId)
+//	      | a1 (variableListName) a1 (domain-specific) { 1a domainId 1a itemId }
+func ParseReadRequest(buffer []byte) (*ReadRequest, error) {
+	content, err := expectTLV(buffer, byte(ber.ContextSpecific0Constructed), "confirmed-RequestPDU")
+	if err != nil {
+		return nil, err
+	}
+
+	request := &ReadRequest{}
+	var read []byte
+	for bufPos := 0; bufPos < len(content); {
+		tag, value, next, err := decodeTLV(content, bufPos, len(content))
+		if err != nil {
+			return nil, err
+		}
+		switch tag {
+		case byte(ber.Integer):
+			request.InvokeID = ber.DecodeUint32(value, len(value), 0)
+		case byte(ber.ContextSpecific4Constructed):
+			read = value
+		default:
+			return nil, fmt.Errorf("unexpected tag in confirmed-RequestPDU: 0x%02x", tag)
+		}
+		bufPos = next
+	}
+	if read == nil {
+		return nil, fmt.Errorf("confirmed-RequestPDU does not contain read request")
+	}
+
+	specification, err := expectTLV(read, byte(ber.ContextSpecific1Constructed), "variableAccessSpecification")
+	if err != nil {
+		return nil, err
+	}
+	tag, list, _, err := decodeTLV(specification, 0, len(specification))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode variableAccessSpecification: %w", err)
+	}
+	switch tag {
+	case byte(ber.ContextSpecific0Constructed): // listOfVariable
+	case byte(ber.ContextSpecific1Constructed): // variableListName
+		tag, value, _, err := decodeTLV(list, 0, len(list))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode variableListName: %w", err)
+		}
+		name, err := parseObjectName(tag, value)
+		if err != nil {
+			return nil, err
+		}
+		request.DataSet = &name
+		return request, nil
+	default:
+		return nil, fmt.Errorf("unsupported variableAccessSpecification tag: 0x%02x", tag)
+	}
+
+	var items []ObjectName
+	for bufPos := 0; bufPos < len(list); {
+		if list[bufPos] != byte(ber.SequenceConstructed) {
+			return nil, fmt.Errorf("expected listOfVariable item, got tag 0x%02x", list[bufPos])
+		}
+		_, item, next, err := decodeTLV(list, bufPos, len(list))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode listOfVariable item: %w", err)
+		}
+		bufPos = next
+		if item, err = expectTLV(item, byte(ber.ContextSpecific0Constructed), "variableSpecification"); err != nil {
+			return nil, err
+		}
+		tag, value, _, err := decodeTLV(item, 0, len(item))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode object name: %w", err)
+		}
+		name, err := parseObjectName(tag, value)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, name)
+	}
+	switch len(items) {
+	case 0:
+		return nil, fmt.Errorf("read request contains no variables")
+	case 1:
+		request.DomainID, request.ItemID = items[0].DomainID, items[0].ItemID
+	default:
+		request.Items = items
+	}
+	return request, nil
+}
+
 // FunctionalConstraint представляет функциональное ограничение IEC 61850
 type FunctionalConstraint string
 
@@ -198,63 +282,60 @@ const (
 	FCBL   FunctionalConstraint = "BL" // Blocking (блокировка)
 	FCEX   FunctionalConstraint = "EX" // Extended Definition (расширенное определение)
 	FCCO   FunctionalConstraint = "CO" // Control (управление)
+	FCBR   FunctionalConstraint = "BR" // Buffered Report (блок управления буферизованными отчётами)
+	FCRP   FunctionalConstraint = "RP" // Unbuffered Report (блок управления небуферизованными отчётами)
 )
 
-// NewReadRequestFromObjectName создаёт MMS ReadRequest из objectName и FunctionalConstraint.
-// Разбирает objectName на domainID и itemID, преобразует itemID в формат MMS с учётом функционального ограничения.
-//
-// Формат objectName: "domain/item" или "item"
-// Пример: "simpleIOGenericIO/GGIO1.AnIn1.mag.f" -> domainID="simpleIOGenericIO", itemID="GGIO1.AnIn1.mag.f"
-// Или: "GGIO1.AnIn1.mag.f" -> domainID="simpleIOGenericIO" (дефолтный), itemID="GGIO1.AnIn1.mag.f"
+// NewReadRequest создаёт MMS ReadRequest из ссылки objectName и FunctionalConstraint.
+// Ссылка должна содержать домен: "domain/item"; без домена возвращается ErrMissingDomain
+// (см. NewReadRequestInDomain).
 //
 // Преобразование itemID в формат MMS:
 // - Если указан FunctionalConstraint (fc), первая точка заменяется на $FC$ (например, $MX$)
 // - Остальные точки заменяются на $
 // - Пример: "GGIO1.AnIn1.mag.f" с FC_MX -> "GGIO1$MX$AnIn1$mag$f"
 // - Если FC не указан, все точки заменяются на $
+// - itemID, уже содержащий $ ("GGIO1$MX$AnIn1"), передаётся без изменений
 //
-// invokeID устанавливается в 1 (стандартное значение для первого запроса).
-func NewReadRequest(objectName string, fc FunctionalConstraint) *ReadRequest {
-	// Разбираем objectName на domainID и itemID
-	// Формат: "domain/item" или просто "item"
-	var domainID, itemID string
-	if idx := strings.Index(objectName, "/"); idx >= 0 {
-		domainID = objectName[:idx]
-		itemID = objectName[idx+1:]
-	} else {
-		// Если разделителя нет, используем весь objectName как itemID
-		// domainID будет пустым (или можно использовать дефолтный)
-		itemID = objectName
-		// Для примера из wireshark используем "simpleIOGenericIO" как дефолтный domain
-		// В реальности это должно быть настроено или получено из конфигурации
-		if domainID == "" {
-			domainID = "simpleIOGenericIO"
-		}
-	}
+// invokeID устанавливается в 1 (стандартное значение для первого запроса);
+// MmsClient перезаписывает его значением из InvokeIDAllocator.
+func NewReadRequest(objectName string, fc FunctionalConstraint) (*ReadRequest, error) {
+	return NewReadRequestInDomain("", objectName, fc)
+}
 
-	// Преобразуем точки в доллары для itemID с учетом функционального ограничения
-	// Логика согласно MmsMapping_createMmsVariableNameFromObjectReference:
-	// - Первая точка заменяется на $FC$ (например, $MX$)
-	// - Остальные точки заменяются на $
-	// Пример: "GGIO1.AnIn1.mag.f" с FC_MX -> "GGIO1$MX$AnIn1$mag$f"
-	// Если FC не указан, все точки заменяются на $
-	if !strings.Contains(itemID, "$") {
-		if fc != FCNone && fc != "" {
-			// Заменяем первую точку на $FC$
-			if idx := strings.Index(itemID, "."); idx >= 0 {
-				itemID = itemID[:idx] + "$" + string(fc) + "$" + itemID[idx+1:]
-				// Заменяем остальные точки на $
-				itemID = strings.ReplaceAll(itemID, ".", "$")
-			} else {
-				// Если точек нет, добавляем $FC$ в конец
-				itemID = itemID + "$" + string(fc)
-			}
-		} else {
-			// Если FC не указан, просто заменяем все точки на $
-			itemID = strings.ReplaceAll(itemID, ".", "$")
+// NewReadRequestInDomain создаёт ReadRequest как NewReadRequest; ссылка без домена
+// ("GGIO1.AnIn1.mag.f") относится к домену defaultDomain. Пустой defaultDomain
+// означает, что домен обязателен.
+func NewReadRequestInDomain(defaultDomain, objectName string, fc FunctionalConstraint) (*ReadRequest, error) {
+	domainID, itemID, found := strings.Cut(objectName, "/")
+	if !found {
+		if defaultDomain == "" {
+			return nil, fmt.Errorf("%w: %q", ErrMissingDomain, objectName)
 		}
+		domainID, itemID = defaultDomain, objectName
+	}
+	if domainID == "" {
+		return nil, fmt.Errorf("%w: %q", ErrMissingDomain, objectName)
+	}
+	if itemID == "" {
+		return nil, fmt.Errorf("object reference %q has no item", objectName)
 	}
+	return &ReadRequest{InvokeID: 1, DomainID: domainID, ItemID: mmsItemID(itemID, fc)}, nil
+}
 
-	// invokeID = 1 для первого запроса
-	return &ReadRequest{1, domainID, itemID}
+// mmsItemID преобразует точечную запись itemID в MMS имя с функциональным
+// ограничением fc согласно MmsMapping_createMmsVariableNameFromObjectReference
+// (libIEC61850). Имена в $-записи не изменяются.
+func mmsItemID(itemID string, fc FunctionalConstraint) string {
+	if strings.Contains(itemID, "$") {
+		return itemID
+	}
+	if fc == FCNone || fc == "" {
+		return strings.ReplaceAll(itemID, ".", "$")
+	}
+	ln, rest, found := strings.Cut(itemID, ".")
+	if !found {
+		return itemID + "$" + string(fc)
+	}
+	return ln + "$" + string(fc) + "$" + strings.ReplaceAll(rest, ".", "$")
 }
@@ -0,0 +1,83 @@
+package mms
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseReadRequest(t *testing.T) {
+	// Пакет из wireshark (см. expectedReadPacket в poc_test.go)
+	buffer := parseHexString("a02c020101a427a125a0233021a01fa11d1a1173696d706c65494f47656e65726963494f1a084747494f31244d58")
+
+	got, err := ParseReadRequest(buffer)
+	assert.NoError(t, err)
+	assert.Equal(t, &ReadRequest{InvokeID: 1, DomainID: "simpleIOGenericIO", ItemID: "GGIO1$MX"}, got)
+
+	request, err := NewReadRequest("simpleIOGenericIO/GGIO1.AnIn1.mag.f", FCMX)
+	assert.NoError(t, err)
+	got, err = ParseReadRequest(request.Bytes())
+	assert.NoError(t, err)
+	assert.Equal(t, request, got)
+
+	// vmd-specific имя
+	request = &ReadRequest{InvokeID: 3, ItemID: "Clock"}
+	got, err = ParseReadRequest(request.Bytes())
+	assert.NoError(t, err)
+	assert.Equal(t, request, got)
+
+	// Несколько переменных listOfVariable
+	request = &ReadRequest{InvokeID: 4, Items: []ObjectName{
+		{DomainID: "simpleIOGenericIO", ItemID: "GGIO1$MX$AnIn1"},
+		{ItemID: "Clock"},
+	}}
+	got, err = ParseReadRequest(request.Bytes())
+	assert.NoError(t, err)
+	assert.Equal(t, request, got)
+
+	// Набор данных (variableListName) кодируется как ReadDataSetRequest
+	dataSet := ObjectName{DomainID: "simpleIOGenericIO", ItemID: "LLN0$Events"}
+	encoded, err := (&ReadDataSetRequest{InvokeID: 5, DataSet: dataSet}).Bytes()
+	assert.NoError(t, err)
+	got, err = ParseReadRequest(encoded)
+	assert.NoError(t, err)
+	assert.Equal(t, &ReadRequest{InvokeID: 5, DataSet: &dataSet}, got)
+	assert.Equal(t, encoded, got.Bytes())
+	assert.Nil(t, got.Variables())
+
+	_, err = ParseReadRequest(parseHexString("a1020101"))
+	assert.EqualError(t, err, "invalid tag for confirmed-RequestPDU: expected 0xa0, got 0xa1")
+	_, err = ParseReadRequest(parseHexString("a009020101a404a102a000"))
+	assert.EqualError(t, err, "read request contains no variables")
+}
+
+func TestNewReadRequest(t *testing.T) {
+	request, err := NewReadRequest("simpleIOGenericIO/GGIO1.AnIn1.mag.f", FCMX)
+	assert.NoError(t, err)
+	assert.Equal(t, &ReadRequest{InvokeID: 1, DomainID: "simpleIOGenericIO", ItemID: "GGIO1$MX$AnIn1$mag$f"}, request)
+
+	// Имя в $-записи передаётся без изменений, FC не добавляется
+	request, err = NewReadRequest("simpleIOGenericIO/GGIO1$MX$AnIn1", FCMX)
+	assert.NoError(t, err)
+	assert.Equal(t, "GGIO1$MX$AnIn1", request.ItemID)
+
+	request, err = NewReadRequest("LD0/LLN0.RP.EventsRCB", FCNone)
+	assert.NoError(t, err)
+	assert.Equal(t, "LLN0$RP$EventsRCB", request.ItemID)
+
+	_, err = NewReadRequest("GGIO1.AnIn1.mag.f", FCMX)
+	assert.ErrorIs(t, err, ErrMissingDomain)
+	_, err = NewReadRequest("/GGIO1.AnIn1", FCMX)
+	assert.ErrorIs(t, err, ErrMissingDomain)
+	_, err = NewReadRequest("LD0/", FCMX)
+	assert.EqualError(t, err, `object reference "LD0/" has no item`)
+
+	request, err = NewReadRequestInDomain("LD0", "GGIO1.AnIn1", FCMX)
+	assert.NoError(t, err)
+	assert.Equal(t, &ReadRequest{InvokeID: 1, DomainID: "LD0", ItemID: "GGIO1$MX$AnIn1"}, request)
+
+	// Явный домен важнее домена по умолчанию
+	request, err = NewReadRequestInDomain("LD0", "LD1/GGIO1.AnIn1", FCMX)
+	assert.NoError(t, err)
+	assert.Equal(t, "LD1", request.DomainID)
+}
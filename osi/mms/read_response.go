@@ -6,8 +6,9 @@ import (
 	"fmt"
 	"math"
 	"time"
+	"unicode/utf8"
 
-	"github.com/slonegd/go61850/ber"
+	"github.com/slonegd/go61850/internal/ber"
 	"github.com/slonegd/go61850/osi/mms/variant"
 )
 
@@ -136,6 +137,19 @@ func (e *DataAccessError) String() string {
 	return e.ErrorCode.String()
 }
 
+// Error реализует интерфейс error, чтобы отказ сервера (например, в ответе
+// на Write) можно было вернуть как ошибку и проверить через errors.As
+func (e *DataAccessError) Error() string {
+	return "data access error: " + e.String()
+}
+
+// Is сообщает, совпадает ли код ошибки с target, например
+// errors.Is(err, &DataAccessError{ErrorCode: ObjectNonExistent})
+func (e *DataAccessError) Is(target error) bool {
+	t, ok := target.(*DataAccessError)
+	return ok && e != nil && t != nil && e.ErrorCode == t.ErrorCode
+}
+
 // ParseReadResponse парсит MMS Read Response PDU из BER-кодированного буфера
 // Структура из wireshark:
 // a0 10 - confirmed-ResponsePDU (Context-specific 0, Constructed, длина 16 байт)
@@ -153,11 +167,16 @@ func (e *DataAccessError) String() string {
 //	a4 09 - confirmedServiceResponse: read
 //	   a1 07 - read
 //	      87 05 - success
+//
+// Для confirmed-ErrorPDU возвращается *ConfirmedError.
 func ParseReadResponse(buffer []byte) (ReadResponse, error) {
 	var response ReadResponse
 	if len(buffer) == 0 {
 		return response, errors.New("empty buffer")
 	}
+	if err := checkConfirmedError(buffer); err != nil {
+		return response, err
+	}
 
 	// Проверяем формат данных
 	// После установления соединения данные могут приходить в разных форматах:
@@ -281,216 +300,144 @@ func parseReadServiceResponse(buffer []byte, maxLength int) ([]AccessResult, err
 
 	maxBufPos = bufPos + length
 
-	var results []AccessResult
-
-	// Парсим listOfAccessResult (SEQUENCE OF AccessResult)
-	// В wireshark видно, что listOfAccessResult может быть закодирован напрямую как success (tag 0x87)
+	// listOfAccessResult может быть закодирован как элементы AccessResult напрямую
 	// или как SEQUENCE (tag 0x30) с элементами
-	for bufPos < maxBufPos {
-		tag := buffer[bufPos]
-		bufPos++
+	return parseListOfAccessResult(buffer[bufPos:maxBufPos])
+}
 
-		newPos, length, err := ber.DecodeLength(buffer, bufPos, maxBufPos)
-		if err != nil {
-			return nil, fmt.Errorf("failed to decode length for tag 0x%02x: %w", tag, err)
-		}
-		bufPos = newPos
+// parseListOfAccessResult парсит SEQUENCE OF AccessResult
+func parseListOfAccessResult(buffer []byte) ([]AccessResult, error) {
+	var sizes dataSizes
+	count := sizes.count(buffer)
+	d := newDataDecoder(sizes)
+	results := make([]AccessResult, 0, count)
+	return d.accessResults(buffer, results)
+}
 
-		if bufPos+length > maxBufPos {
-			return nil, fmt.Errorf("invalid length for tag 0x%02x: exceeds buffer size", tag)
-		}
+// dataSizes - размеры, вычисляемые предварительным проходом по PDU,
+// чтобы dataDecoder выделил память один раз
+type dataSizes struct {
+	// variants - число элементов Data
+	variants int
+	// elements - суммарное число элементов структур
+	elements int
+	// bytes - суммарный размер bit-string, octet-string и binary-time
+	bytes int
+}
 
+// count учитывает элементы Data из buffer и возвращает число элементов верхнего
+// уровня; SEQUENCE (0x30) раскрывается. Ошибки кодирования пропускаются -
+// они обнаруживаются при разборе.
+func (s *dataSizes) count(buffer []byte) int {
+	n := 0
+	for bufPos := 0; bufPos < len(buffer); {
+		tag, content, next, err := decodeTLV(buffer, bufPos, len(buffer))
+		if err != nil {
+			break
+		}
 		switch tag {
-		case 0x30: // SEQUENCE (listOfAccessResult)
-			// Парсим элементы SEQUENCE
-			seqResults, err := parseListOfAccessResult(buffer[bufPos:bufPos+length], length)
-			if err != nil {
-				return nil, fmt.Errorf("failed to parse listOfAccessResult: %w", err)
-			}
-			results = append(results, seqResults...)
-			bufPos += length
-
-		case 0x87: // success (Context-specific 7) - floating-point
-			// Парсим floating-point значение
-			value, err := parseFloatingPoint(buffer[bufPos:bufPos+length], length)
-			if err != nil {
-				return nil, fmt.Errorf("failed to parse floating-point: %w", err)
-			}
-			results = append(results, AccessResult{
-				Success: true,
-				Value:   variant.NewFloat32Variant(value),
-			})
-			bufPos += length
-
-		case 0x84: // success (Context-specific 4) - bit-string
-			// Парсим bit-string значение
-			value, err := parseBitString(buffer[bufPos:bufPos+length], length)
-			if err != nil {
-				return nil, fmt.Errorf("failed to parse bit-string: %w", err)
-			}
-			results = append(results, AccessResult{
-				Success: true,
-				Value:   value,
-			})
-			bufPos += length
-
-		case 0x85: // success (Context-specific 5) - integer
-			// Парсим integer значение
-			value, err := parseInteger(buffer[bufPos:bufPos+length], length)
-			if err != nil {
-				return nil, fmt.Errorf("failed to parse integer: %w", err)
-			}
-			results = append(results, AccessResult{
-				Success: true,
-				Value:   variant.NewInt32Variant(value),
-			})
-			bufPos += length
-
-		case 0x91: // success (Context-specific 17) - utc-time
-			// Парсим UTC time значение
-			value, err := parseUTCTime(buffer[bufPos:bufPos+length], length)
-			if err != nil {
-				return nil, fmt.Errorf("failed to parse utc-time: %w", err)
-			}
-			results = append(results, AccessResult{
-				Success: true,
-				Value:   variant.NewUTCTimeVariant(value),
-			})
-			bufPos += length
-
-		case 0xA2: // success (Context-specific 2) - structure
-			// Парсим structure значение
-			value, err := parseStructure(buffer[bufPos:bufPos+length], length)
-			if err != nil {
-				return nil, fmt.Errorf("failed to parse structure: %w", err)
-			}
-			results = append(results, AccessResult{
-				Success: true,
-				Value:   value,
-			})
-			bufPos += length
-
-		case 0x80: // failure (Context-specific 0) - DataAccessError
-			// Парсим ошибку доступа к данным
-			errorCode := DataAccessErrorCode(ber.DecodeUint32(buffer, length, bufPos))
-			results = append(results, AccessResult{
-				Success: false,
-				Error: &DataAccessError{
-					ErrorCode: errorCode,
-				},
-			})
-			bufPos += length
-
+		case 0x30:
+			n += s.count(content)
+		case 0xA2:
+			s.elements += s.count(content)
+			fallthrough
 		default:
-			// Неподдерживаемый тег - возвращаем ошибку с указанием номера тега
-			return nil, fmt.Errorf("unsupported tag: 0x%02x", tag)
+			n++
+			s.variants++
+		}
+		switch tag {
+		case 0x84, 0x89, 0x8C:
+			s.bytes += len(content)
 		}
+		bufPos = next
 	}
+	return n
+}
 
-	return results, nil
+// dataDecoder разбирает элементы Data одного PDU без выделения памяти на каждый
+// элемент: Variant, срезы элементов структур и байты bit-string и octet-string
+// выдаются из блоков, выделенных по dataSizes, а строки интернируются.
+// Значения одного PDU разделяют эти блоки и удерживают их от сборки мусора.
+type dataDecoder struct {
+	variants []variant.Variant
+	elements []*variant.Variant
+	bytes    []byte
 }
 
-// parseListOfAccessResult парсит SEQUENCE OF AccessResult
-func parseListOfAccessResult(buffer []byte, maxLength int) ([]AccessResult, error) {
-	var results []AccessResult
+func newDataDecoder(sizes dataSizes) *dataDecoder {
+	return &dataDecoder{
+		variants: make([]variant.Variant, sizes.variants),
+		elements: make([]*variant.Variant, sizes.elements),
+		bytes:    make([]byte, sizes.bytes),
+	}
+}
 
-	bufPos := 0
-	maxBufPos := len(buffer)
-	if maxLength < maxBufPos {
-		maxBufPos = maxLength
+// newVariant размещает v в блоке декодера
+func (d *dataDecoder) newVariant(v variant.Variant) *variant.Variant {
+	if len(d.variants) == 0 {
+		d.variants = make([]variant.Variant, 1)
 	}
+	p := &d.variants[0]
+	*p = v
+	d.variants = d.variants[1:]
+	return p
+}
 
-	for bufPos < maxBufPos {
-		tag := buffer[bufPos]
-		bufPos++
+// newElements возвращает срез для n элементов структуры
+func (d *dataDecoder) newElements(n int) []*variant.Variant {
+	if len(d.elements) < n {
+		return make([]*variant.Variant, 0, n)
+	}
+	// Ограничение ёмкости не даёт append пользователя затереть соседние структуры
+	elements := d.elements[:0:n]
+	d.elements = d.elements[n:]
+	return elements
+}
 
-		newPos, length, err := ber.DecodeLength(buffer, bufPos, maxBufPos)
-		if err != nil {
-			return nil, fmt.Errorf("failed to decode length for tag 0x%02x: %w", tag, err)
-		}
-		bufPos = newPos
+// copyBytes копирует b в блок декодера
+func (d *dataDecoder) copyBytes(b []byte) []byte {
+	if len(d.bytes) < len(b) {
+		return append([]byte{}, b...)
+	}
+	n := copy(d.bytes, b)
+	data := d.bytes[:n:n]
+	d.bytes = d.bytes[n:]
+	return data
+}
 
-		if bufPos+length > maxBufPos {
-			return nil, fmt.Errorf("invalid length for tag 0x%02x: exceeds buffer size", tag)
+// accessResults разбирает элементы AccessResult из buffer и добавляет их к results
+func (d *dataDecoder) accessResults(buffer []byte, results []AccessResult) ([]AccessResult, error) {
+	for bufPos := 0; bufPos < len(buffer); {
+		tag, content, next, err := decodeTLV(buffer, bufPos, len(buffer))
+		if err != nil {
+			return nil, err
 		}
-
 		switch tag {
-		case 0x87: // success (Context-specific 7) - floating-point
-			value, err := parseFloatingPoint(buffer[bufPos:bufPos+length], length)
-			if err != nil {
-				return nil, fmt.Errorf("failed to parse floating-point: %w", err)
-			}
-			results = append(results, AccessResult{
-				Success: true,
-				Value:   variant.NewFloat32Variant(value),
-			})
-			bufPos += length
-
-		case 0x84: // success (Context-specific 4) - bit-string
-			// Парсим bit-string значение
-			value, err := parseBitString(buffer[bufPos:bufPos+length], length)
-			if err != nil {
-				return nil, fmt.Errorf("failed to parse bit-string: %w", err)
-			}
-			results = append(results, AccessResult{
-				Success: true,
-				Value:   value,
-			})
-			bufPos += length
-
-		case 0x85: // success (Context-specific 5) - integer
-			// Парсим integer значение
-			value, err := parseInteger(buffer[bufPos:bufPos+length], length)
-			if err != nil {
-				return nil, fmt.Errorf("failed to parse integer: %w", err)
-			}
-			results = append(results, AccessResult{
-				Success: true,
-				Value:   variant.NewInt32Variant(value),
-			})
-			bufPos += length
-
-		case 0x91: // success (Context-specific 17) - utc-time
-			// Парсим UTC time значение
-			value, err := parseUTCTime(buffer[bufPos:bufPos+length], length)
+		case 0x30: // SEQUENCE (listOfAccessResult)
+			results, err = d.accessResults(content, results)
 			if err != nil {
-				return nil, fmt.Errorf("failed to parse utc-time: %w", err)
+				return nil, fmt.Errorf("failed to parse listOfAccessResult: %w", err)
 			}
+		case 0x80: // failure (Context-specific 0) - DataAccessError
+			errorCode := DataAccessErrorCode(ber.DecodeUint32(content, len(content), 0))
 			results = append(results, AccessResult{
-				Success: true,
-				Value:   variant.NewUTCTimeVariant(value),
+				Success: false,
+				Error: &DataAccessError{
+					ErrorCode: errorCode,
+				},
 			})
-			bufPos += length
-
-		case 0xA2: // success (Context-specific 2) - structure
-			// Парсим structure значение
-			value, err := parseStructure(buffer[bufPos:bufPos+length], length)
+		default: // success - Data
+			value, err := d.data(tag, content)
 			if err != nil {
-				return nil, fmt.Errorf("failed to parse structure: %w", err)
+				return nil, fmt.Errorf("failed to parse access result with tag 0x%02x: %w", tag, err)
 			}
 			results = append(results, AccessResult{
 				Success: true,
 				Value:   value,
 			})
-			bufPos += length
-
-		case 0x80: // failure (Context-specific 0) - DataAccessError
-			// Парсим ошибку
-			errorCode := DataAccessErrorCode(ber.DecodeUint32(buffer, length, bufPos))
-			results = append(results, AccessResult{
-				Success: false,
-				Error: &DataAccessError{
-					ErrorCode: errorCode,
-				},
-			})
-			bufPos += length
-
-		default:
-			// Неподдерживаемый тег - возвращаем ошибку с указанием номера тега
-			return nil, fmt.Errorf("unsupported tag: 0x%02x", tag)
 		}
+		bufPos = next
 	}
-
 	return results, nil
 }
 
@@ -533,14 +480,14 @@ func parseInteger(buffer []byte, length int) (int32, error) {
 	return value, nil
 }
 
-// parseBitString парсит bit-string значение
+// bitString парсит bit-string значение
 // Структура согласно ISO/IEC 9506-2:
 // - 1 байт: padding (количество неиспользуемых бит в последнем байте, 0-7)
 // - N байт: данные bit-string
 // Основано на mms_access_result.c case 0x84
-func parseBitString(buffer []byte, length int) (*variant.Variant, error) {
-	if length < 1 {
-		return nil, fmt.Errorf("invalid bit-string length: expected at least 1 byte, got %d", length)
+func (d *dataDecoder) bitString(buffer []byte) (*variant.Variant, error) {
+	if len(buffer) < 1 {
+		return nil, fmt.Errorf("invalid bit-string length: expected at least 1 byte, got %d", len(buffer))
 	}
 
 	padding := int(buffer[0])
@@ -549,30 +496,24 @@ func parseBitString(buffer []byte, length int) (*variant.Variant, error) {
 	}
 
 	// Данные начинаются со второго байта
-	dataLength := length - 1
-	if dataLength < 0 {
-		return nil, fmt.Errorf("invalid bit-string length: no data bytes")
-	}
-
-	data := make([]byte, dataLength)
-	copy(data, buffer[1:1+dataLength])
+	data := d.copyBytes(buffer[1:])
 
 	// Вычисляем количество значащих бит
-	bitSize := (8 * dataLength) - padding
+	bitSize := (8 * len(data)) - padding
 
-	return variant.NewBitStringVariant(data, bitSize), nil
+	return d.newVariant(*variant.NewBitStringVariant(data, bitSize)), nil
 }
 
 // parseUTCTime парсит UTC time значение
 // Структура согласно ISO/IEC 9506-2:
 // - 4 байта: секунды с 1 января 1970 00:00:00 UTC (big-endian uint32)
 // - 3 байта: доля секунды (fraction of second) в единицах 1/2^24 секунды
-// - 1 байт: качество времени (time quality)
+// - 1 байт: качество времени (time quality): флаги синхронизации и точность
 // Итого 8 байт
 // Основано на MmsValue_getUtcTimeInMsWithUs из mms_value.c
-func parseUTCTime(buffer []byte, length int) (time.Time, error) {
+func parseUTCTime(buffer []byte, length int) (time.Time, variant.TimeQuality, error) {
 	if length != 8 {
-		return time.Time{}, fmt.Errorf("invalid utc-time length: expected 8 bytes, got %d", length)
+		return time.Time{}, 0, fmt.Errorf("invalid utc-time length: expected 8 bytes, got %d", length)
 	}
 
 	// Декодируем секунды (первые 4 байта, big-endian)
@@ -587,159 +528,214 @@ func parseUTCTime(buffer []byte, length int) (time.Time, error) {
 	// Используем uint64 для избежания переполнения
 	nanoseconds := uint64(fractionOfSecond) * 1_000_000_000 / 0x1000000
 
-	// Качество времени (байт 7) игнорируем, так как оно не влияет на значение времени
-
 	// Создаём time.Time из секунд и наносекунд
 	t := time.Unix(int64(seconds), int64(nanoseconds)).UTC()
 
-	return t, nil
+	return t, variant.TimeQuality(buffer[7]), nil
 }
 
-// parseStructure парсит structure значение
+// simpleData парсит boolean (0x83), unsigned (0x86), octet-string (0x89) и binary-time (0x8C)
+func (d *dataDecoder) simpleData(tag byte, buffer []byte) (*variant.Variant, error) {
+	switch tag {
+	case 0x83:
+		if len(buffer) != 1 {
+			return nil, fmt.Errorf("invalid boolean length: %d", len(buffer))
+		}
+		return d.newVariant(*variant.NewBoolVariant(buffer[0] != 0)), nil
+	case 0x86:
+		// Unsigned может иметь ведущий 0x00, поэтому допустимо до 9 байт;
+		// значения шире 32 бит (INT64U) разбираются в Uint64
+		if len(buffer) < 1 || len(buffer) > 9 || (len(buffer) == 9 && buffer[0] != 0) {
+			return nil, fmt.Errorf("invalid unsigned length: %d", len(buffer))
+		}
+		var value uint64
+		for _, b := range buffer {
+			value = value<<8 | uint64(b)
+		}
+		if value > math.MaxUint32 {
+			return d.newVariant(*variant.NewUint64Variant(value)), nil
+		}
+		return d.newVariant(*variant.NewUint32Variant(uint32(value))), nil
+	case 0x89:
+		return d.newVariant(*variant.NewOctetStringVariant(d.copyBytes(buffer))), nil
+	case 0x8C:
+		if len(buffer) != 4 && len(buffer) != 6 {
+			return nil, fmt.Errorf("invalid binary-time length: %d", len(buffer))
+		}
+		return d.newVariant(*variant.NewBinaryTimeVariant(d.copyBytes(buffer))), nil
+	default:
+		return nil, fmt.Errorf("unsupported Data tag: 0x%02x", tag)
+	}
+}
+
+// structure парсит structure значение
 // Структура согласно ISO/IEC 9506-2:
 // - structure [2] IMPLICIT SEQUENCE OF Data (тег 0xA2, Context-specific 2, Constructed)
 // Структура содержит последовательность элементов Data, которые парсятся рекурсивно
 // Основано на MmsValue_decodeMmsDataRecursive из mms_access_result.c case 0xa2
 // buffer содержит данные структуры БЕЗ внешнего тега 0xA2 и длины
-func parseStructure(buffer []byte, length int) (*variant.Variant, error) {
-	if length == 0 {
-		// Пустая структура
-		return variant.NewStructureVariant([]*variant.Variant{}), nil
-	}
-
-	var elements []*variant.Variant
-	bufPos := 0
-	maxBufPos := length
-	if maxBufPos > len(buffer) {
-		maxBufPos = len(buffer)
-	}
-
-	// Парсим все элементы структуры
-	for bufPos < maxBufPos {
-		// Сохраняем позицию начала элемента (с тегом)
-		elementStart := bufPos
-		tag := buffer[bufPos]
-		bufPos++
-
-		newPos, elementLength, err := ber.DecodeLength(buffer, bufPos, maxBufPos)
+func (d *dataDecoder) structure(buffer []byte) (*variant.Variant, error) {
+	n := 0
+	for bufPos := 0; bufPos < len(buffer); n++ {
+		_, _, next, err := decodeTLV(buffer, bufPos, len(buffer))
 		if err != nil {
-			return nil, fmt.Errorf("failed to decode length for tag 0x%02x: %w", tag, err)
-		}
-		bufPos = newPos
-
-		if bufPos+elementLength > maxBufPos {
-			return nil, fmt.Errorf("invalid length for tag 0x%02x: exceeds buffer size", tag)
+			return nil, err
 		}
+		bufPos = next
+	}
 
-		// Парсим элемент Data рекурсивно
-		// Создаём срез, который начинается с тега и содержит полный элемент (тег + длина + данные)
-		elementEnd := bufPos + elementLength
-		elementBuffer := buffer[elementStart:elementEnd]
-		element, err := parseDataElement(elementBuffer)
+	elements := d.newElements(n)
+	for bufPos := 0; bufPos < len(buffer); {
+		tag, content, next, _ := decodeTLV(buffer, bufPos, len(buffer))
+		element, err := d.data(tag, content)
 		if err != nil {
 			return nil, fmt.Errorf("failed to parse structure element with tag 0x%02x: %w", tag, err)
 		}
-
 		elements = append(elements, element)
-		bufPos = elementEnd
+		bufPos = next
 	}
 
-	return variant.NewStructureVariant(elements), nil
+	return d.newVariant(*variant.NewStructureVariant(elements)), nil
 }
 
-// parseDataElement парсит один элемент Data
-// buffer должен начинаться с тега элемента и содержать полный элемент (тег + длина + данные)
-// Рекурсивно парсит структуры и массивы
-func parseDataElement(buffer []byte) (*variant.Variant, error) {
-	if len(buffer) < 1 {
-		return nil, errors.New("empty buffer for Data element")
-	}
-
-	tag := buffer[0]
-	bufPos := 1
-
-	newPos, length, err := ber.DecodeLength(buffer, bufPos, len(buffer))
-	if err != nil {
-		return nil, fmt.Errorf("failed to decode length for tag 0x%02x: %w", tag, err)
-	}
-	bufPos = newPos
-
-	if bufPos+length > len(buffer) {
-		return nil, fmt.Errorf("invalid length for tag 0x%02x: exceeds buffer size", tag)
-	}
-
+// data парсит содержимое элемента Data с тегом tag
+// Рекурсивно парсит структуры
+func (d *dataDecoder) data(tag byte, buffer []byte) (*variant.Variant, error) {
 	switch tag {
 	case 0x87: // floating-point
-		value, err := parseFloatingPoint(buffer[bufPos:bufPos+length], length)
+		value, err := parseFloatingPoint(buffer, len(buffer))
 		if err != nil {
 			return nil, err
 		}
-		return variant.NewFloat32Variant(value), nil
+		return d.newVariant(*variant.NewFloat32Variant(value)), nil
 
 	case 0x84: // bit-string
-		return parseBitString(buffer[bufPos:bufPos+length], length)
+		return d.bitString(buffer)
 
 	case 0x85: // integer
-		value, err := parseInteger(buffer[bufPos:bufPos+length], length)
+		if len(buffer) > 4 && len(buffer) <= 8 {
+			// INT64: значение шире 32 бит
+			var value int64
+			if buffer[0]&0x80 != 0 {
+				value = -1
+			}
+			for _, b := range buffer {
+				value = value<<8 | int64(b)
+			}
+			return d.newVariant(*variant.NewInt64Variant(value)), nil
+		}
+		value, err := parseInteger(buffer, len(buffer))
 		if err != nil {
 			return nil, err
 		}
-		return variant.NewInt32Variant(value), nil
+		return d.newVariant(*variant.NewInt32Variant(value)), nil
+
+	case 0x8A: // visible-string
+		return d.newVariant(*variant.NewVisibleStringVariant(intern(buffer))), nil
+
+	case 0x90: // MMSString (UTF8String)
+		// В отличие от visible-string содержимое - UTF-8, невалидные последовательности отклоняются
+		if !utf8.Valid(buffer) {
+			return nil, errors.New("invalid UTF-8")
+		}
+		return d.newVariant(*variant.NewMMSStringVariant(intern(buffer))), nil
+
+	case 0x83, 0x86, 0x89, 0x8C: // boolean, unsigned, octet-string, binary-time
+		return d.simpleData(tag, buffer)
 
 	case 0x91: // utc-time
-		value, err := parseUTCTime(buffer[bufPos:bufPos+length], length)
+		value, quality, err := parseUTCTime(buffer, len(buffer))
 		if err != nil {
 			return nil, err
 		}
-		return variant.NewUTCTimeVariant(value), nil
+		return d.newVariant(*variant.NewUTCTimeVariantWithQuality(value, quality)), nil
 
 	case 0xA2: // structure (рекурсивный вызов)
-		return parseStructure(buffer[bufPos:bufPos+length], length)
+		return d.structure(buffer)
 
 	default:
 		return nil, fmt.Errorf("unsupported Data tag: 0x%02x", tag)
 	}
 }
 
-// String возвращает строковое представление ReadResponse
-func (r *ReadResponse) String() string {
-	if len(r.ListOfAccessResult) == 0 {
-		return fmt.Sprintf("ReadResponse{InvokeID: %d, Results: []}", r.InvokeID)
+// parseDataElement парсит один элемент Data
+// buffer должен начинаться с тега элемента и содержать полный элемент (тег + длина + данные)
+func parseDataElement(buffer []byte) (*variant.Variant, error) {
+	if len(buffer) < 1 {
+		return nil, errors.New("empty buffer for Data element")
+	}
+	tag, content, _, err := decodeTLV(buffer, 0, len(buffer))
+	if err != nil {
+		return nil, err
 	}
+	var sizes dataSizes
+	sizes.count(buffer)
+	return newDataDecoder(sizes).data(tag, content)
+}
 
-	var results []string
-	for i, result := range r.ListOfAccessResult {
-		if result.Success {
-			if result.Value == nil {
-				results = append(results, fmt.Sprintf("Result[%d]: <nil>", i))
-			} else {
-				switch result.Value.Type() {
-				case variant.Float32:
-					val := result.Value.Float32()
-					results = append(results, fmt.Sprintf("Result[%d]: %f", i, val))
-				case variant.Int32:
-					val := result.Value.Int32()
-					results = append(results, fmt.Sprintf("Result[%d]: %d", i, val))
-				case variant.UTCTime:
-					val := result.Value.Time()
-					results = append(results, fmt.Sprintf("Result[%d]: %s", i, val.Format(time.RFC3339Nano)))
-				case variant.BitString:
-					val := result.Value.BitString()
-					results = append(results, fmt.Sprintf("Result[%d]: bit-string(%d bits)", i, val.BitSize))
-				case variant.Structure:
-					results = append(results, fmt.Sprintf("Result[%d]: %s", i, result.Value.String()))
-				default:
-					results = append(results, fmt.Sprintf("Result[%d]: <unknown type: %v>", i, result.Value.Type()))
-				}
-			}
-		} else {
+// Bytes кодирует ReadResponse в BER-кодированный пакет MMS confirmed-ResponsePDU.
+// Используется серверной стороной; формат совпадает с тем, что разбирает ParseReadResponse:
+// a1 (confirmed-ResponsePDU) + invokeID + a4 (read) + a1 (listOfAccessResult) + результаты
+func (r *ReadResponse) Bytes() ([]byte, error) {
+	results, err := encodeAccessResults(r.ListOfAccessResult)
+	if err != nil {
+		return nil, err
+	}
+
+	listOfAccessResult := wrapTL(ber.ContextSpecific1Constructed, results)
+	readResponse := wrapTL(ber.ContextSpecific4Constructed, listOfAccessResult)
+
+	content := encodeInvokeID(r.InvokeID)
+	content = append(content, readResponse...)
+
+	return wrapTL(ber.ContextSpecific1Constructed, content), nil
+}
+
+// encodeAccessResults кодирует элементы SEQUENCE OF AccessResult:
+// успешный результат - элемент Data, отказ - 80 (DataAccessError)
+func encodeAccessResults(list []AccessResult) ([]byte, error) {
+	var results []byte
+	for i, result := range list {
+		if !result.Success {
+			code := ObjectNonExistent
 			if result.Error != nil {
-				results = append(results, fmt.Sprintf("Result[%d]: Error(%s)", i, result.Error.ErrorCode))
-			} else {
-				results = append(results, fmt.Sprintf("Result[%d]: Error(<nil>)", i))
+				code = result.Error.ErrorCode
 			}
+			tempBuf := make([]byte, 8)
+			tempPos := ber.EncodeUInt32(uint32(code), tempBuf, 0)
+			results = append(results, wrapTL(ber.ContextSpecific0Primitive, tempBuf[:tempPos])...)
+			continue
 		}
+		encoded, err := EncodeData(result.Value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode access result %d: %w", i, err)
+		}
+		results = append(results, encoded...)
 	}
+	return results, nil
+}
+
+// String возвращает ReadResponse одной строкой в формате key=value:
+// успешные результаты - как Variant.String, отказы - error(код)
+func (r *ReadResponse) String() string {
+	return r.fields().compact()
+}
 
-	return fmt.Sprintf("ReadResponse{InvokeID: %d, Results: [%s]}", r.InvokeID, fmt.Sprint(results))
+// Format реализует fmt.Formatter: %+v выводит результат на строку
+func (r *ReadResponse) Format(s fmt.State, verb rune) {
+	r.fields().format(s, verb)
+}
+
+func (r *ReadResponse) fields() *fields {
+	f := newFields("ReadResponse")
+	f.addUint("InvokeID", r.InvokeID)
+	for i, result := range r.ListOfAccessResult {
+		key := fmt.Sprintf("Result[%d]", i)
+		if result.Success {
+			f.add(key, result.Value.String())
+		} else {
+			f.add(key, "error("+result.Error.String()+")")
+		}
+	}
+	return f
 }
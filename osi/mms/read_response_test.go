@@ -6,6 +6,7 @@ import (
 	"strings"
 	"testing"
 	"time"
+	"unsafe"
 
 	"github.com/slonegd/go61850/osi/mms/variant"
 	"github.com/stretchr/testify/assert"
@@ -137,7 +138,7 @@ func TestParseReadResponse(t *testing.T) {
 				InvokeID: 1,
 				ListOfAccessResult: []AccessResult{{
 					Success: true,
-					Value:   variant.NewUTCTimeVariant(time.Date(2026, 1, 5, 8, 27, 51, 153999984, time.UTC)),
+					Value:   variant.NewUTCTimeVariantWithQuality(time.Date(2026, 1, 5, 8, 27, 51, 153999984, time.UTC), variant.TimeLeapSecondsKnown),
 				}},
 			},
 		},
@@ -189,7 +190,7 @@ func TestParseReadResponse(t *testing.T) {
 						// bit-string
 						variant.NewBitStringVariant([]byte{0x00, 0x00}, 13),
 						// utc-time
-						variant.NewUTCTimeVariant(time.Date(2026, 1, 5, 11, 21, 52, 670999944, time.UTC)),
+						variant.NewUTCTimeVariantWithQuality(time.Date(2026, 1, 5, 11, 21, 52, 670999944, time.UTC), variant.TimeLeapSecondsKnown),
 					}),
 				}},
 			},
@@ -210,3 +211,180 @@ func TestParseReadResponse(t *testing.T) {
 		})
 	}
 }
+
+func TestReadResponseBytes(t *testing.T) {
+	response := ReadResponse{
+		InvokeID: 7,
+		ListOfAccessResult: []AccessResult{
+			{
+				Success: true,
+				Value: variant.NewStructureVariant([]*variant.Variant{
+					variant.NewStructureVariant([]*variant.Variant{
+						variant.NewFloat32Variant(0.9471655488014221),
+					}),
+					variant.NewBitStringVariant([]byte{0x00, 0x00}, 13),
+					variant.NewInt32Variant(-300),
+				}),
+			},
+			{
+				Success: false,
+				Error:   &DataAccessError{ErrorCode: ObjectNonExistent},
+			},
+		},
+	}
+
+	buffer, err := response.Bytes()
+	assert.NoError(t, err)
+	assert.Equal(t,
+		"a11e020107a419a117a212a2078705083f72797184030300008502fed480010a",
+		fmt.Sprintf("%x", buffer),
+	)
+
+	got, err := ParseReadResponse(buffer)
+	assert.NoError(t, err)
+	assert.Equal(t, response, got)
+}
+
+func TestStringData(t *testing.T) {
+	value := variant.NewStructureVariant([]*variant.Variant{
+		variant.NewVisibleStringVariant("AnIn1"),
+		variant.NewMMSStringVariant("Ввод ≈1"),
+	})
+
+	encoded, err := EncodeData(value)
+	assert.NoError(t, err)
+	assert.Equal(t, parseHexString("a216 8a05416e496e31 900dd092d0b2d0bed0b420e2898831"), encoded)
+
+	decoded, err := parseDataElement(encoded)
+	assert.NoError(t, err)
+	assert.Equal(t, value, decoded)
+	assert.Equal(t, `struct{visible-string("AnIn1"), mms-string("Ввод ≈1")}`, decoded.String())
+
+	_, err = EncodeData(variant.NewVisibleStringVariant("Ввод"))
+	assert.EqualError(t, err, "visible-string contains non-printable or non-ASCII character at 0, use MMSString")
+
+	_, err = parseDataElement(parseHexString("9002c328"))
+	assert.EqualError(t, err, "invalid UTF-8")
+}
+
+func TestSimpleData(t *testing.T) {
+	value := variant.NewStructureVariant([]*variant.Variant{
+		variant.NewBoolVariant(true),
+		variant.NewUint32Variant(0x80),
+		variant.NewUint32Variant(0xffffffff),
+		variant.NewOctetStringVariant([]byte{0x01, 0x02}),
+		variant.NewBinaryTimeVariant([]byte{0x00, 0x00, 0x03, 0xe8, 0x00, 0x01}),
+	})
+
+	encoded, err := EncodeData(value)
+	assert.NoError(t, err)
+	assert.Equal(t, parseHexString("a21a 8301ff 86020080 860500ffffffff 89020102 8c06000003e80001"), encoded)
+
+	decoded, err := parseDataElement(encoded)
+	assert.NoError(t, err)
+	assert.Equal(t, value, decoded)
+	assert.Equal(t, "struct{bool(true), uint32(128), uint32(4294967295), octet-string(0102), binary-time(000003e80001)}", decoded.String())
+
+	_, err = parseDataElement(parseHexString("8609010000000000000000"))
+	assert.EqualError(t, err, "invalid unsigned length: 9")
+}
+
+func TestInt64Data(t *testing.T) {
+	value := variant.NewStructureVariant([]*variant.Variant{
+		variant.NewInt64Variant(-1 << 40),
+		variant.NewInt64Variant(math.MaxInt64),
+		variant.NewUint64Variant(1 << 32),
+		variant.NewUint64Variant(math.MaxUint64),
+	})
+
+	encoded, err := EncodeData(value)
+	assert.NoError(t, err)
+	assert.Equal(t, parseHexString("a224 8506ff0000000000 85087fffffffffffffff 86050100000000 860900ffffffffffffffff"), encoded)
+
+	decoded, err := parseDataElement(encoded)
+	assert.NoError(t, err)
+	assert.Equal(t, value, decoded)
+	assert.Equal(t, "struct{int64(-1099511627776), int64(9223372036854775807), uint64(4294967296), uint64(18446744073709551615)}", decoded.String())
+
+	// Значения, умещающиеся в 32 бита, разбираются как Int32 и Uint32;
+	// Int64 и Uint64 возвращают их без потерь
+	small, err := parseDataElement(parseHexString("8501ff"))
+	assert.NoError(t, err)
+	assert.Equal(t, variant.Int32, small.Type())
+	assert.Equal(t, int64(-1), small.Int64())
+	assert.Equal(t, uint64(7), variant.NewUint32Variant(7).Uint64())
+	assert.Equal(t, float32(1<<40), variant.NewInt64Variant(1<<40).Float32())
+
+	assert.Equal(t, &TypeSpecification{Type: TypeSpecInteger, IntegerSize: 64}, TypeOf(variant.NewInt64Variant(0)))
+	assert.Equal(t, &TypeSpecification{Type: TypeSpecUnsigned, UnsignedSize: 64}, TypeOf(variant.NewUint64Variant(0)))
+}
+
+func TestUTCTimeQuality(t *testing.T) {
+	at := time.Unix(1700000000, 0).UTC()
+	value := variant.NewUTCTimeVariantWithQuality(at, variant.TimeClockNotSynchronized|12)
+
+	encoded, err := EncodeData(value)
+	assert.NoError(t, err)
+	assert.Equal(t, parseHexString("9108 6553f100 000000 2c"), encoded)
+
+	decoded, err := parseDataElement(encoded)
+	assert.NoError(t, err)
+	assert.True(t, value.Equal(decoded))
+	quality := decoded.TimeQuality()
+	assert.True(t, quality.ClockNotSynchronized())
+	assert.False(t, quality.ClockFailure())
+	assert.False(t, quality.LeapSecondsKnown())
+	assert.Equal(t, 12, quality.Accuracy())
+	assert.False(t, quality.Reliable())
+
+	// Качество времени входит в значение
+	assert.False(t, variant.NewUTCTimeVariant(at).Equal(decoded))
+	assert.True(t, variant.NewUTCTimeVariant(at).TimeQuality().Reliable())
+}
+
+func BenchmarkParseReadResponse(b *testing.B) {
+	results := make([]AccessResult, 16)
+	for i := range results {
+		results[i] = AccessResult{Success: true, Value: variant.NewStructureVariant([]*variant.Variant{
+			variant.NewStructureVariant([]*variant.Variant{variant.NewFloat32Variant(float32(i))}),
+			variant.NewBitStringVariant([]byte{0x00, 0x00}, 13),
+			variant.NewUTCTimeVariant(time.Unix(1700000000, 0).UTC()),
+		})}
+	}
+	buffer, err := (&ReadResponse{InvokeID: 1, ListOfAccessResult: results}).Bytes()
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.SetBytes(int64(len(buffer)))
+	for b.Loop() {
+		if _, err := ParseReadResponse(buffer); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestParseListOfAccessResultSharedBlocks(t *testing.T) {
+	// Значения одного PDU размещаются в общих блоках декодера;
+	// append к одному значению не должен затрагивать соседние
+	results, err := parseListOfAccessResult(parseHexString("8902aabb 8902ccdd a208 840200008402 00ff"))
+	assert.NoError(t, err)
+	assert.Len(t, results, 3)
+
+	first := append(results[0].Value.OctetString(), 0x11)
+	assert.Equal(t, []byte{0xaa, 0xbb, 0x11}, first)
+	assert.Equal(t, []byte{0xcc, 0xdd}, results[1].Value.OctetString())
+
+	elements := append(results[2].Value.Structure(), variant.NewBoolVariant(true))
+	assert.Len(t, elements, 3)
+	assert.Len(t, results[2].Value.Structure(), 2)
+	assert.Equal(t, variant.NewBitStringVariant([]byte{0xff}, 8), results[2].Value.Structure()[1])
+}
+
+func TestIntern(t *testing.T) {
+	a := intern([]byte("LD0/LLN0$RP$MeasRCB01"))
+	b := intern([]byte("LD0/LLN0$RP$MeasRCB01"))
+	assert.Equal(t, "LD0/LLN0$RP$MeasRCB01", a)
+	assert.Equal(t, unsafe.StringData(a), unsafe.StringData(b))
+	assert.Equal(t, "", intern(nil))
+}
@@ -0,0 +1,290 @@
+package mms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"slices"
+	"sync"
+
+	"github.com/slonegd/go61850/logger"
+	"github.com/slonegd/go61850/osi/cotp"
+	"github.com/slonegd/go61850/osi/presentation"
+	"github.com/slonegd/go61850/osi/session"
+)
+
+// Handler обрабатывает BER-кодированный MMS confirmed-RequestPDU и возвращает
+// BER-кодированный confirmed-ResponsePDU. Если ошибка оборачивает *ServiceError,
+// клиент получает confirmed-ErrorPDU с этой ошибкой, иначе - RejectPDU
+// с причиной invalid-argument.
+type Handler func(pdu []byte) ([]byte, error)
+
+// Server принимает соединения ISO-on-TCP (RFC 1006), устанавливает ассоциации MMS
+// и передаёт confirmed-RequestPDU обработчикам служб. Модель данных сервер не знает:
+// обработчики регистрируются WithHandler (готовый набор - server.Server.Serve).
+type Server struct {
+	logger   logger.Logger
+	handlers map[ConfirmedService]Handler
+	limits   InitiateResponse
+	// associate вызывается при установлении ассоциации (см. WithAssociateHook)
+	associate func() (release func())
+	// unconfirmed получает отправку unconfirmed-PDU ассоциации (см. WithUnconfirmedHook)
+	unconfirmed func(send func(pdu []byte) error) (release func())
+}
+
+// ServerOption представляет опцию для настройки Server
+type ServerOption func(*Server)
+
+// WithHandler регистрирует обработчик службы service. Службы с обработчиками
+// и conclude объявляются клиенту в servicesSupportedCalled ответа Initiate;
+// на запросы остальных служб сервер отвечает RejectPDU unrecognized-service.
+func WithHandler(service ConfirmedService, h Handler) ServerOption {
+	return func(s *Server) {
+		s.handlers[service] = h
+	}
+}
+
+// WithInitiateLimits задаёт пределы ассоциации сервера: максимальный размер PDU,
+// число одновременных запросов, уровень вложенности и параметры CBB.
+// ServicesSupportedCalled не используется: его определяют обработчики.
+func WithInitiateLimits(limits InitiateResponse) ServerOption {
+	return func(s *Server) {
+		s.limits = limits
+	}
+}
+
+// WithAssociateHook задаёт функцию, вызываемую при установлении каждой ассоциации;
+// возвращённая функция release вызывается при её завершении
+func WithAssociateHook(fn func() (release func())) ServerOption {
+	return func(s *Server) {
+		s.associate = fn
+	}
+}
+
+// WithUnconfirmedHook задаёт функцию, вызываемую при установлении каждой ассоциации
+// с функцией send, которая отправляет клиенту MMS PDU вне ответов на запросы,
+// например unconfirmed-PDU с InformationReport. send безопасна для вызова из других
+// горутин и блокируется до записи PDU в соединение; после завершения ассоциации
+// возвращает ошибку. Возвращённая функция release вызывается при завершении ассоциации.
+func WithUnconfirmedHook(fn func(send func(pdu []byte) error) (release func())) ServerOption {
+	return func(s *Server) {
+		s.unconfirmed = fn
+	}
+}
+
+// defaultInitiateLimits - пределы ассоциации по умолчанию, как у сервера libIEC61850
+func defaultInitiateLimits() InitiateResponse {
+	pduSize, nesting := uint32(65000), uint32(10)
+	return InitiateResponse{
+		LocalDetailCalled:                   &pduSize,
+		NegotiatedMaxServOutstandingCalling: 5,
+		NegotiatedMaxServOutstandingCalled:  5,
+		NegotiatedDataStructureNestingLevel: &nesting,
+		NegotiatedParameterCBB:              []ParameterCBBBit{Str1, Str2, Vnam, Valt, Vlis},
+	}
+}
+
+// NewServer создаёт MMS сервер
+func NewServer(logger logger.Logger, opts ...ServerOption) *Server {
+	s := &Server{
+		logger:   logger,
+		handlers: make(map[ConfirmedService]Handler),
+		limits:   defaultInitiateLimits(),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Serve принимает соединения l и обслуживает каждое в отдельной горутине (ServeConn).
+// Возвращает ошибку Accept, например после закрытия l.
+func (s *Server) Serve(l net.Listener) error {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return err
+		}
+		go func() {
+			if err := s.ServeConn(context.Background(), conn); err != nil {
+				s.logger.Debug("MMS server: %s: %v", conn.RemoteAddr(), err)
+			}
+		}()
+	}
+}
+
+// ServeConn обслуживает одно соединение: отвечает на COTP CR, устанавливает
+// ассоциацию по CONNECT с InitiateRequest (см. NegotiateInitiateResponse)
+// и отвечает на запросы до закрытия соединения клиентом, FINISH или отмены ctx.
+// conn закрывается по завершении. Закрытие клиентом не считается ошибкой.
+func (s *Server) ServeConn(ctx context.Context, conn io.ReadWriteCloser) error {
+	defer conn.Close()
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	c := cotp.NewConnection(conn, cotp.WithLogger(s.logger))
+	// sendMu упорядочивает ответы ServeConn и PDU, отправляемые через WithUnconfirmedHook
+	var sendMu sync.Mutex
+	send := func(pdu []byte) error {
+		sendMu.Lock()
+		defer sendMu.Unlock()
+		userData := presentation.BuildUserData(pdu, 3)
+		return c.SendDataMessageContext(ctx, session.BuildDataTransferWithTokens(userData))
+	}
+	associated := false
+	for {
+		indication, payload, err := receiveTSDU(ctx, c)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if errors.Is(err, cotp.ErrSocketClosed) {
+				return nil
+			}
+			return err
+		}
+		switch indication {
+		case cotp.IndicationConnect:
+			if err := c.SendConnectionResponseMessage(); err != nil {
+				return fmt.Errorf("failed to send COTP CC: %w", err)
+			}
+			continue
+		case cotp.IndicationDisconnect:
+			return nil
+		}
+
+		spdu, err := session.ParseSessionSPDU(payload)
+		if err != nil {
+			return fmt.Errorf("failed to parse Session SPDU: %w", err)
+		}
+		switch spdu.Type {
+		case session.SessionSPDUTypeConnect:
+			if associated {
+				return fmt.Errorf("association is already established")
+			}
+			if err := s.accept(c, payload); err != nil {
+				return err
+			}
+			associated = true
+			if s.associate != nil {
+				defer s.associate()()
+			}
+			if s.unconfirmed != nil {
+				defer s.unconfirmed(send)()
+			}
+		case session.SessionSPDUTypeData:
+			if !associated {
+				return fmt.Errorf("data before association")
+			}
+			ppdu, err := presentation.ParsePresentationPDU(spdu.Data)
+			if err != nil {
+				return fmt.Errorf("failed to parse Presentation PDU: %w", err)
+			}
+			response := s.handle(ppdu.Data)
+			if response == nil {
+				continue
+			}
+			if err := send(response); err != nil {
+				return fmt.Errorf("failed to send MMS PDU: %w", err)
+			}
+		case session.SessionSPDUTypeFinish, session.SessionSPDUTypeDisconnect:
+			return nil
+		default:
+			return fmt.Errorf("unexpected Session SPDU: %s", spdu)
+		}
+	}
+}
+
+// accept отвечает на CONNECT с InitiateRequest согласованным InitiateResponse
+// и подгоняет буферы соединения под согласованный размер PDU
+func (s *Server) accept(c *cotp.Connection, payload []byte) error {
+	request, err := ParseAssociateRequest(payload)
+	if err != nil {
+		return fmt.Errorf("failed to parse associate request: %w", err)
+	}
+	limits := s.limits
+	limits.ServicesSupportedCalled = s.services()
+	response := NegotiateInitiateResponse(request, &limits)
+	s.logger.Debug("MMS Initiate: %s", response)
+
+	if err := c.SendDataMessage(NewAssociateResponse(response.Bytes()).Session); err != nil {
+		return fmt.Errorf("failed to send associate response: %w", err)
+	}
+	if response.LocalDetailCalled != nil {
+		c.SizeBuffers(int(*response.LocalDetailCalled) + upperLayerOverhead)
+	}
+	return nil
+}
+
+// services возвращает службы с обработчиками и conclude в порядке битов
+func (s *Server) services() []ServiceSupportedBit {
+	services := []ServiceSupportedBit{Conclude}
+	for service := range s.handlers {
+		services = append(services, ServiceSupportedBit(service))
+	}
+	slices.Sort(services)
+	return services
+}
+
+// MMS PDU conclude: conclude-RequestPDU [11] и conclude-ResponsePDU [12] (NULL)
+var (
+	concludeRequest  = []byte{0x8b, 0x00}
+	concludeResponse = []byte{0x8c, 0x00}
+)
+
+// handle возвращает ответ на MMS PDU; nil - ответ не нужен
+func (s *Server) handle(pdu []byte) []byte {
+	if slices.Equal(pdu, concludeRequest) {
+		return concludeResponse
+	}
+	if !IsConfirmedRequest(pdu) {
+		s.logger.Debug("MMS server: ignored PDU % x", pdu)
+		return nil
+	}
+	request, err := ParseConfirmedRequest(pdu)
+	if err != nil {
+		s.logger.Debug("MMS server: %v", err)
+		return (&Reject{Reason: RejectOther}).Bytes()
+	}
+	handler, ok := s.handlers[request.Service]
+	if !ok {
+		return (&Reject{InvokeID: request.InvokeID, Reason: RejectUnrecognizedService}).Bytes()
+	}
+
+	response, err := handler(pdu)
+	if err != nil {
+		var serviceError *ServiceError
+		if errors.As(err, &serviceError) {
+			return (&ConfirmedError{InvokeID: request.InvokeID, ServiceError: *serviceError}).Bytes()
+		}
+		s.logger.Debug("MMS server: %s: %v", request.Service, err)
+		return (&Reject{InvokeID: request.InvokeID, Reason: RejectInvalidArgument}).Bytes()
+	}
+	return response
+}
+
+// receiveTSDU читает TPKT до COTP CR, DR или последнего фрагмента данных
+// и возвращает индикацию и собранный payload
+func receiveTSDU(ctx context.Context, c *cotp.Connection) (cotp.Indication, []byte, error) {
+	for {
+		state, err := c.ReadToTpktBuffer(ctx)
+		if err != nil {
+			return cotp.IndicationError, nil, err
+		}
+		if state != cotp.TpktPacketComplete {
+			continue
+		}
+		indication, err := c.ParseIncomingMessage()
+		if err != nil {
+			return cotp.IndicationError, nil, fmt.Errorf("failed to parse COTP message: %w", err)
+		}
+		if indication == cotp.IndicationMoreFragmentsFollow {
+			continue
+		}
+		payload := slices.Clone(c.GetPayload())
+		c.ResetPayload()
+		return indication, payload, nil
+	}
+}
@@ -0,0 +1,29 @@
+package mms
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/slonegd/go61850/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServerHandle(t *testing.T) {
+	denied := &ServiceError{Class: ErrorClassAccess, Code: AccessErrorObjectAccessDenied}
+	s := NewServer(logger.NewLogger("mms-server"),
+		WithHandler(ServiceRead, func(pdu []byte) ([]byte, error) { return []byte{0xa1, 0x00}, nil }),
+		WithHandler(ServiceWrite, func(pdu []byte) ([]byte, error) { return nil, denied }),
+		WithHandler(ServiceGetNameList, func(pdu []byte) ([]byte, error) { return nil, errors.New("bad") }),
+	)
+	assert.Equal(t, []ServiceSupportedBit{GetNameList, Read, Write, Conclude}, s.services())
+
+	request := func(service ConfirmedService) []byte {
+		return encodeConfirmedRequest(9, service, false, nil)
+	}
+	assert.Equal(t, []byte{0xa1, 0x00}, s.handle(request(ServiceRead)))
+	assert.Equal(t, (&ConfirmedError{InvokeID: 9, ServiceError: *denied}).Bytes(), s.handle(request(ServiceWrite)))
+	assert.Equal(t, (&Reject{InvokeID: 9, Reason: RejectInvalidArgument}).Bytes(), s.handle(request(ServiceGetNameList)))
+	assert.Equal(t, (&Reject{InvokeID: 9, Reason: RejectUnrecognizedService}).Bytes(), s.handle(request(ServiceIdentify)))
+	assert.Equal(t, []byte{0x8c, 0x00}, s.handle([]byte{0x8b, 0x00}))
+	assert.Nil(t, s.handle([]byte{0xa3, 0x00}))
+}
@@ -0,0 +1,357 @@
+package mms
+
+import (
+	"fmt"
+	"io/fs"
+	"strconv"
+	"strings"
+
+	"github.com/slonegd/go61850/internal/ber"
+)
+
+// ErrorClass представляет класс ошибки ServiceError (номер варианта errorClass)
+type ErrorClass uint8
+
+const (
+	ErrorClassVMDState             ErrorClass = 0
+	ErrorClassApplicationReference ErrorClass = 1
+	ErrorClassDefinition           ErrorClass = 2
+	ErrorClassResource             ErrorClass = 3
+	ErrorClassService              ErrorClass = 4
+	ErrorClassServicePreempt       ErrorClass = 5
+	ErrorClassTimeResolution       ErrorClass = 6
+	ErrorClassAccess               ErrorClass = 7
+	ErrorClassInitiate             ErrorClass = 8
+	ErrorClassConclude             ErrorClass = 9
+	ErrorClassCancel               ErrorClass = 10
+	ErrorClassFile                 ErrorClass = 11
+	ErrorClassOthers               ErrorClass = 12
+)
+
+var errorClassNames = [...]string{
+	"vmd-state", "application-reference", "definition", "resource", "service",
+	"service-preempt", "time-resolution", "access", "initiate", "conclude",
+	"cancel", "file", "others",
+}
+
+// String возвращает имя класса ошибки согласно ASN.1
+func (c ErrorClass) String() string {
+	if int(c) < len(errorClassNames) {
+		return errorClassNames[c]
+	}
+	return fmt.Sprintf("unknown(%d)", uint8(c))
+}
+
+// Коды ошибок класса vmd-state
+const (
+	VMDStateErrorOther                 uint32 = 0
+	VMDStateErrorVMDStateConflict      uint32 = 1
+	VMDStateErrorVMDOperationalProblem uint32 = 2
+	VMDStateErrorDomainTransferProblem uint32 = 3
+	VMDStateErrorStateMachineIDInvalid uint32 = 4
+)
+
+// Коды ошибок класса application-reference
+const (
+	ApplicationReferenceErrorOther                       uint32 = 0
+	ApplicationReferenceErrorApplicationUnreachable      uint32 = 1
+	ApplicationReferenceErrorConnectionLost              uint32 = 2
+	ApplicationReferenceErrorApplicationReferenceInvalid uint32 = 3
+	ApplicationReferenceErrorContextUnsupported          uint32 = 4
+)
+
+// Коды ошибок класса definition
+const (
+	DefinitionErrorOther                       uint32 = 0
+	DefinitionErrorObjectUndefined             uint32 = 1
+	DefinitionErrorInvalidAddress              uint32 = 2
+	DefinitionErrorTypeUnsupported             uint32 = 3
+	DefinitionErrorTypeInconsistent            uint32 = 4
+	DefinitionErrorObjectExists                uint32 = 5
+	DefinitionErrorObjectAttributeInconsistent uint32 = 6
+)
+
+// Коды ошибок класса resource
+const (
+	ResourceErrorOther                        uint32 = 0
+	ResourceErrorMemoryUnavailable            uint32 = 1
+	ResourceErrorProcessorResourceUnavailable uint32 = 2
+	ResourceErrorMassStorageUnavailable       uint32 = 3
+	ResourceErrorCapabilityUnavailable        uint32 = 4
+	ResourceErrorCapabilityUnknown            uint32 = 5
+)
+
+// Коды ошибок класса service
+const (
+	ServiceErrorOther                    uint32 = 0
+	ServiceErrorPrimitivesOutOfSequence  uint32 = 1
+	ServiceErrorObjectStateConflict      uint32 = 2
+	ServiceErrorPDUSize                  uint32 = 3
+	ServiceErrorContinuationInvalid      uint32 = 4
+	ServiceErrorObjectConstraintConflict uint32 = 5
+)
+
+// Коды ошибок класса service-preempt
+const (
+	ServicePreemptErrorOther    uint32 = 0
+	ServicePreemptErrorTimeout  uint32 = 1
+	ServicePreemptErrorDeadlock uint32 = 2
+	ServicePreemptErrorCancel   uint32 = 3
+)
+
+// Коды ошибок класса time-resolution
+const (
+	TimeResolutionErrorOther                       uint32 = 0
+	TimeResolutionErrorUnsupportableTimeResolution uint32 = 1
+)
+
+// Коды ошибок класса access
+const (
+	AccessErrorOther                   uint32 = 0
+	AccessErrorObjectAccessUnsupported uint32 = 1
+	AccessErrorObjectNonExistent       uint32 = 2
+	AccessErrorObjectAccessDenied      uint32 = 3
+	AccessErrorObjectInvalidated       uint32 = 4
+)
+
+// Коды ошибок класса initiate
+const (
+	InitiateErrorOther                                     uint32 = 0
+	InitiateErrorVersionIncompatible                       uint32 = 1
+	InitiateErrorMaxSegmentInsufficient                    uint32 = 2
+	InitiateErrorMaxServicesOutstandingCallingInsufficient uint32 = 3
+	InitiateErrorMaxServicesOutstandingCalledInsufficient  uint32 = 4
+	InitiateErrorServiceCBBInsufficient                    uint32 = 5
+	InitiateErrorParameterCBBInsufficient                  uint32 = 6
+	InitiateErrorNestingLevelInsufficient                  uint32 = 7
+)
+
+// Коды ошибок класса conclude
+const (
+	ConcludeErrorOther                        uint32 = 0
+	ConcludeErrorFurtherCommunicationRequired uint32 = 1
+)
+
+// Коды ошибок класса cancel
+const (
+	CancelErrorOther             uint32 = 0
+	CancelErrorInvalidInvokeID   uint32 = 1
+	CancelErrorCancelNotPossible uint32 = 2
+)
+
+// Коды ошибок класса file
+const (
+	FileErrorOther               uint32 = 0
+	FileErrorFilenameAmbiguous   uint32 = 1
+	FileErrorFileBusy            uint32 = 2
+	FileErrorFilenameSyntaxError uint32 = 3
+	FileErrorContentTypeInvalid  uint32 = 4
+	FileErrorPositionInvalid     uint32 = 5
+	FileErrorFileAccessDenied    uint32 = 6
+	FileErrorFileNonExistent     uint32 = 7
+	FileErrorDuplicateFilename   uint32 = 8
+	FileErrorInsufficientSpace   uint32 = 9
+)
+
+// errorCodeNames - имена кодов ошибок по классам согласно ASN.1 (ISO/IEC 9506-2).
+// Коды класса others не именованы.
+var errorCodeNames = [...][]string{
+	ErrorClassVMDState: {"other", "vmd-state-conflict", "vmd-operational-problem",
+		"domain-transfer-problem", "state-machine-id-invalid"},
+	ErrorClassApplicationReference: {"other", "application-unreachable", "connection-lost",
+		"application-reference-invalid", "context-unsupported"},
+	ErrorClassDefinition: {"other", "object-undefined", "invalid-address", "type-unsupported",
+		"type-inconsistent", "object-exists", "object-attribute-inconsistent"},
+	ErrorClassResource: {"other", "memory-unavailable", "processor-resource-unavailable",
+		"mass-storage-unavailable", "capability-unavailable", "capability-unknown"},
+	ErrorClassService: {"other", "primitives-out-of-sequence", "object-state-conflict", "pdu-size",
+		"continuation-invalid", "object-constraint-conflict"},
+	ErrorClassServicePreempt: {"other", "timeout", "deadlock", "cancel"},
+	ErrorClassTimeResolution: {"other", "unsupportable-time-resolution"},
+	ErrorClassAccess: {"other", "object-access-unsupported", "object-non-existent",
+		"object-access-denied", "object-invalidated"},
+	ErrorClassInitiate: {"other", "version-incompatible", "max-segment-insufficient",
+		"max-services-outstanding-calling-insufficient", "max-services-outstanding-called-insufficient",
+		"service-CBB-insufficient", "parameter-CBB-insufficient", "nesting-level-insufficient"},
+	ErrorClassConclude: {"other", "further-communication-required"},
+	ErrorClassCancel:   {"other", "invalid-invokeID", "cancel-not-possible"},
+	ErrorClassFile: {"other", "filename-ambiguous", "file-busy", "filename-syntax-error",
+		"content-type-invalid", "position-invalid", "file-access-denied", "file-non-existent",
+		"duplicate-filename", "insufficient-space-in-filestore"},
+	ErrorClassOthers: nil,
+}
+
+// ServiceError представляет ошибку выполнения MMS сервиса (ISO/IEC 9506-2)
+//
+//	ServiceError ::= SEQUENCE {
+//	  errorClass [0] CHOICE { vmd-state [0] INTEGER, ..., others [12] INTEGER },
+//	  additionalCode [1] IMPLICIT INTEGER OPTIONAL,
+//	  additionalDescription [2] IMPLICIT VisibleString OPTIONAL,
+//	  serviceSpecificInfo [3] CHOICE { ... } OPTIONAL
+//	}
+//
+// errors.Is сравнивает ServiceError по классу и коду, а также сопоставляет
+// коды классов access и definition с *DataAccessError (например,
+// errors.Is(err, &DataAccessError{ErrorCode: ObjectNonExistent}); errors.As
+// возвращает такой *DataAccessError) и коды класса file с ошибками io/fs
+// (fs.ErrNotExist, fs.ErrPermission, fs.ErrExist).
+type ServiceError struct {
+	Class ErrorClass
+	Code  uint32
+	// AdditionalCode - дополнительный код, определяемый сервером; nil - не передан
+	AdditionalCode *int32
+	// AdditionalDescription - дополнительное описание ошибки; пусто - не передано
+	AdditionalDescription string
+}
+
+// CodeString возвращает имя кода ошибки в классе Class или его номер
+func (e *ServiceError) CodeString() string {
+	if int(e.Class) < len(errorCodeNames) {
+		if names := errorCodeNames[e.Class]; int64(e.Code) < int64(len(names)) {
+			return names[e.Code]
+		}
+	}
+	return strconv.FormatUint(uint64(e.Code), 10)
+}
+
+func (e *ServiceError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "MMS service error: class %s, code %s", e.Class, e.CodeString())
+	if e.AdditionalCode != nil {
+		fmt.Fprintf(&b, ", additional code %d", *e.AdditionalCode)
+	}
+	if e.AdditionalDescription != "" {
+		fmt.Fprintf(&b, ": %s", e.AdditionalDescription)
+	}
+	return b.String()
+}
+
+// Is сообщает, соответствует ли ошибка target: *ServiceError с теми же классом
+// и кодом, *DataAccessError с соответствующим кодом доступа или ошибка io/fs
+func (e *ServiceError) Is(target error) bool {
+	if e == nil {
+		return false
+	}
+	switch t := target.(type) {
+	case *ServiceError:
+		return t != nil && e.Class == t.Class && e.Code == t.Code
+	case *DataAccessError:
+		code, ok := e.dataAccessErrorCode()
+		return ok && t != nil && code == t.ErrorCode
+	}
+	if e.Class != ErrorClassFile {
+		return false
+	}
+	switch e.Code {
+	case FileErrorFileNonExistent:
+		return target == fs.ErrNotExist
+	case FileErrorFileAccessDenied:
+		return target == fs.ErrPermission
+	case FileErrorDuplicateFilename:
+		return target == fs.ErrExist
+	}
+	return false
+}
+
+// As позволяет получить через errors.As *DataAccessError для ошибок классов
+// access и definition, имеющих соответствующий код доступа
+func (e *ServiceError) As(target any) bool {
+	t, ok := target.(**DataAccessError)
+	if !ok || e == nil {
+		return false
+	}
+	code, ok := e.dataAccessErrorCode()
+	if ok {
+		*t = &DataAccessError{ErrorCode: code}
+	}
+	return ok
+}
+
+// dataAccessErrorCode возвращает код DataAccessError, соответствующий ошибке
+// классов access и definition
+func (e *ServiceError) dataAccessErrorCode() (DataAccessErrorCode, bool) {
+	switch e.Class {
+	case ErrorClassAccess:
+		switch e.Code {
+		case AccessErrorObjectAccessUnsupported:
+			return ObjectAccessUnsupported, true
+		case AccessErrorObjectNonExistent:
+			return ObjectNonExistent, true
+		case AccessErrorObjectAccessDenied:
+			return ObjectAccessDenied, true
+		case AccessErrorObjectInvalidated:
+			return ObjectInvalidated, true
+		}
+	case ErrorClassDefinition:
+		switch e.Code {
+		case DefinitionErrorObjectUndefined:
+			return ObjectUndefined, true
+		case DefinitionErrorInvalidAddress:
+			return InvalidAddress, true
+		case DefinitionErrorTypeUnsupported:
+			return TypeUnsupported, true
+		case DefinitionErrorTypeInconsistent:
+			return TypeInconsistent, true
+		case DefinitionErrorObjectAttributeInconsistent:
+			return ObjectAttributeInconsistent, true
+		}
+	}
+	return 0, false
+}
+
+// content кодирует содержимое ServiceError (без внешнего тега)
+func (e *ServiceError) content() []byte {
+	tempBuf := make([]byte, 8)
+	tempPos := ber.EncodeUInt32(e.Code, tempBuf, 0)
+	class := wrapTL(ber.MakeContextSpecificTag(byte(e.Class), false), tempBuf[:tempPos])
+	content := wrapTL(ber.ContextSpecific0Constructed, class)
+	if e.AdditionalCode != nil {
+		content = append(content, wrapTL(ber.ContextSpecific1Primitive, encodeInt(*e.AdditionalCode))...)
+	}
+	if e.AdditionalDescription != "" {
+		content = append(content, wrapTL(ber.ContextSpecific2Primitive, []byte(e.AdditionalDescription))...)
+	}
+	return content
+}
+
+// parseServiceError разбирает содержимое ServiceError; serviceSpecificInfo пропускается
+func parseServiceError(buffer []byte) (*ServiceError, error) {
+	var serviceError *ServiceError
+	var additionalCode *int32
+	var additionalDescription string
+	for bufPos := 0; bufPos < len(buffer); {
+		tag, value, next, err := decodeTLV(buffer, bufPos, len(buffer))
+		if err != nil {
+			return nil, err
+		}
+		switch tag {
+		case byte(ber.ContextSpecific0Constructed): // errorClass
+			classTag, code, _, err := decodeTLV(value, 0, len(value))
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode errorClass: %w", err)
+			}
+			if len(code) < 1 || len(code) > 5 {
+				return nil, fmt.Errorf("invalid errorClass code length: %d", len(code))
+			}
+			serviceError = &ServiceError{
+				Class: ErrorClass(classTag &^ 0xe0),
+				Code:  ber.DecodeUint32(code, len(code), 0),
+			}
+		case byte(ber.ContextSpecific1Primitive): // additionalCode
+			if len(value) < 1 || len(value) > 4 {
+				return nil, fmt.Errorf("invalid additionalCode length: %d", len(value))
+			}
+			code := decodeInt(value)
+			additionalCode = &code
+		case byte(ber.ContextSpecific2Primitive): // additionalDescription
+			additionalDescription = string(value)
+		}
+		bufPos = next
+	}
+	if serviceError == nil {
+		return nil, fmt.Errorf("ServiceError does not contain errorClass")
+	}
+	serviceError.AdditionalCode = additionalCode
+	serviceError.AdditionalDescription = additionalDescription
+	return serviceError, nil
+}
@@ -0,0 +1,49 @@
+package mms
+
+import (
+	"errors"
+	"io/fs"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServiceError(t *testing.T) {
+	additionalCode := int32(-5)
+	confirmedError := &ConfirmedError{InvokeID: 5, ServiceError: ServiceError{
+		Class: ErrorClassAccess, Code: AccessErrorObjectNonExistent,
+		AdditionalCode: &additionalCode, AdditionalDescription: "busy",
+	}}
+	buffer := confirmedError.Bytes()
+	assert.Equal(t, parseHexString("a2 13 800105 a20e a003870102 8101fb 820462757379"), buffer)
+
+	// Отказ сервера в ответе на Read разбирается полностью
+	_, err := ParseReadResponse(buffer)
+	assert.Equal(t, confirmedError, err)
+	assert.EqualError(t, err, "invokeID 5 failed: MMS service error: class access, code object-non-existent, additional code -5: busy")
+
+	// Коды классов access и definition соответствуют DataAccessError
+	assert.ErrorIs(t, err, &DataAccessError{ErrorCode: ObjectNonExistent})
+	assert.NotErrorIs(t, err, &DataAccessError{ErrorCode: ObjectAccessDenied})
+	var dataAccessError *DataAccessError
+	assert.ErrorAs(t, err, &dataAccessError)
+	assert.Equal(t, ObjectNonExistent, dataAccessError.ErrorCode)
+	assert.ErrorIs(t, &ServiceError{Class: ErrorClassDefinition, Code: DefinitionErrorTypeInconsistent},
+		&DataAccessError{ErrorCode: TypeInconsistent})
+	assert.False(t, errors.As(&ServiceError{Class: ErrorClassResource, Code: ResourceErrorMemoryUnavailable}, &dataAccessError))
+
+	// ServiceError сравнивается по классу и коду, коды класса file - с ошибками io/fs
+	assert.ErrorIs(t, err, &ServiceError{Class: ErrorClassAccess, Code: AccessErrorObjectNonExistent})
+	assert.ErrorIs(t, &ServiceError{Class: ErrorClassFile, Code: FileErrorFileNonExistent}, fs.ErrNotExist)
+	assert.ErrorIs(t, &ServiceError{Class: ErrorClassFile, Code: FileErrorFileAccessDenied}, fs.ErrPermission)
+	assert.NotErrorIs(t, &ServiceError{Class: ErrorClassFile, Code: FileErrorFileBusy}, fs.ErrNotExist)
+
+	assert.Equal(t, "service-CBB-insufficient", (&ServiceError{Class: ErrorClassInitiate, Code: InitiateErrorServiceCBBInsufficient}).CodeString())
+	assert.Equal(t, "17", (&ServiceError{Class: ErrorClassOthers, Code: 17}).CodeString())
+
+	// Ответы других сервисов возвращают тот же *ConfirmedError
+	_, err = ParseWriteResponse(buffer)
+	assert.Equal(t, confirmedError, err)
+	_, err = ParseGetNameListResponse(buffer)
+	assert.Equal(t, confirmedError, err)
+}
@@ -0,0 +1,67 @@
+// Code generated by asn1gen from services.asn. DO NOT EDIT.
+
+package mms
+
+import "fmt"
+
+// asnGetDomainAttributesResponse - GetDomainAttributes-Response
+type asnGetDomainAttributesResponse struct {
+	ListOfCapabilities       []string
+	State                    int32
+	MmsDeletable             bool
+	Sharable                 bool
+	ListOfProgramInvocations []string
+	UploadInProgress         int32
+}
+
+// encode кодирует содержимое GetDomainAttributes-Response (без тега и длины)
+func (v *asnGetDomainAttributesResponse) encode() []byte {
+	var content []byte
+	var listOfCapabilities []byte
+	for i := range v.ListOfCapabilities {
+		listOfCapabilities = append(listOfCapabilities, wrapTL(0x0c, []byte(v.ListOfCapabilities[i]))...)
+	}
+	content = append(content, wrapTL(0xa0, listOfCapabilities)...)
+	content = append(content, wrapTL(0x81, encodeInt(v.State))...)
+	content = append(content, wrapTL(0x82, encodeBool(v.MmsDeletable))...)
+	content = append(content, wrapTL(0x83, encodeBool(v.Sharable))...)
+	var listOfProgramInvocations []byte
+	for i := range v.ListOfProgramInvocations {
+		listOfProgramInvocations = append(listOfProgramInvocations, wrapTL(0x1a, []byte(v.ListOfProgramInvocations[i]))...)
+	}
+	content = append(content, wrapTL(0xa4, listOfProgramInvocations)...)
+	content = append(content, wrapTL(0x85, encodeInt(v.UploadInProgress))...)
+	return content
+}
+
+// decode разбирает содержимое GetDomainAttributes-Response (без тега и длины)
+func (v *asnGetDomainAttributesResponse) decode(buffer []byte) error {
+	for bufPos := 0; bufPos < len(buffer); {
+		tag, value, next, err := decodeTLV(buffer, bufPos, len(buffer))
+		if err != nil {
+			return err
+		}
+		switch tag {
+		case 0xa0: // listOfCapabilities
+			if v.ListOfCapabilities, err = parseStringList(value, "listOfCapabilities"); err != nil {
+				return err
+			}
+		case 0x81: // state
+			v.State = decodeInt(value)
+		case 0x82: // mmsDeletable
+			v.MmsDeletable = decodeBool(value)
+		case 0x83: // sharable
+			v.Sharable = decodeBool(value)
+		case 0xa4: // listOfProgramInvocations
+			if v.ListOfProgramInvocations, err = parseStringList(value, "listOfProgramInvocations"); err != nil {
+				return err
+			}
+		case 0x85: // uploadInProgress
+			v.UploadInProgress = decodeInt(value)
+		default:
+			return fmt.Errorf("unexpected tag in GetDomainAttributes-Response: 0x%02x", tag)
+		}
+		bufPos = next
+	}
+	return nil
+}
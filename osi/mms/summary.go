@@ -0,0 +1,64 @@
+package mms
+
+import (
+	"fmt"
+
+	"github.com/slonegd/go61850/internal/ber"
+)
+
+// pduNames - варианты MmsPdu по тегу
+var pduNames = map[byte]string{
+	0xa0: "confirmed-RequestPDU",
+	0xa1: "confirmed-ResponsePDU",
+	0xa2: "confirmed-ErrorPDU",
+	0xa3: "unconfirmed-PDU",
+	0xa4: "rejectPDU",
+	0x85: "cancel-RequestPDU",
+	0x86: "cancel-ResponsePDU",
+	0xa7: "cancel-ErrorPDU",
+	0xa8: "initiate-RequestPDU",
+	0xa9: "initiate-ResponsePDU",
+	0xaa: "initiate-ErrorPDU",
+	0x8b: "conclude-RequestPDU",
+	0x8c: "conclude-ResponsePDU",
+	0xad: "conclude-ErrorPDU",
+}
+
+// Summary возвращает краткое описание MMS PDU для журналов:
+// вариант PDU, invokeID и сервис, например "confirmed-RequestPDU invokeID 3 read".
+// Разбирается только заголовок PDU; нераспознанные данные описываются тегом.
+func Summary(pdu []byte) string {
+	if len(pdu) == 0 {
+		return "empty PDU"
+	}
+	name, ok := pduNames[pdu[0]]
+	if !ok {
+		return fmt.Sprintf("unknown PDU tag 0x%02x", pdu[0])
+	}
+	tag, content, _, err := decodeTLV(pdu, 0, len(pdu))
+	if err != nil {
+		return name + " (malformed)"
+	}
+
+	switch tag {
+	case 0xa0, 0xa1, 0xa2:
+		_, invokeID, next, err := decodeTLV(content, 0, len(content))
+		if err != nil {
+			return name + " (malformed)"
+		}
+		name = fmt.Sprintf("%s invokeID %d", name, ber.DecodeUint32(invokeID, len(invokeID), 0))
+		if tag == 0xa2 || next >= len(content) {
+			return name
+		}
+		if service, _, _, err := decodeServiceTLV(content, next, len(content)); err == nil {
+			name += " " + service.String()
+		}
+	case 0xa3:
+		if len(content) > 0 && content[0] == 0xa0 {
+			name += " informationReport"
+		}
+	case 0x85, 0x86:
+		name = fmt.Sprintf("%s invokeID %d", name, ber.DecodeUint32(content, len(content), 0))
+	}
+	return name
+}
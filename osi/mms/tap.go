@@ -0,0 +1,80 @@
+package mms
+
+import "fmt"
+
+// Direction - направление PDU относительно клиента
+type Direction uint8
+
+const (
+	// DirectionSend - PDU закодирован для отправки
+	DirectionSend Direction = iota
+	// DirectionReceive - PDU принят и выделен из PDU нижнего уровня
+	DirectionReceive
+)
+
+// String возвращает "send" или "receive"
+func (d Direction) String() string {
+	switch d {
+	case DirectionSend:
+		return "send"
+	case DirectionReceive:
+		return "receive"
+	default:
+		return fmt.Sprintf("Direction(%d)", uint8(d))
+	}
+}
+
+// Layer - уровень стека, PDU которого передаётся в RawPDUTap
+type Layer uint8
+
+const (
+	// LayerCOTP - TPKT с TPDU COTP целиком (по одному на фрагмент)
+	LayerCOTP Layer = iota
+	// LayerSession - SPDU (собранный из фрагментов TSDU)
+	LayerSession
+	// LayerPresentation - PPDU
+	LayerPresentation
+	// LayerACSE - APDU ACSE (только при установлении и освобождении ассоциации)
+	LayerACSE
+	// LayerMMS - MMS PDU
+	LayerMMS
+)
+
+// String возвращает имя уровня
+func (l Layer) String() string {
+	switch l {
+	case LayerCOTP:
+		return "COTP"
+	case LayerSession:
+		return "Session"
+	case LayerPresentation:
+		return "Presentation"
+	case LayerACSE:
+		return "ACSE"
+	case LayerMMS:
+		return "MMS"
+	default:
+		return fmt.Sprintf("Layer(%d)", uint8(l))
+	}
+}
+
+// RawPDUTap получает каждый закодированный и разобранный PDU на каждом уровне стека.
+// data действителен только на время вызова; для сохранения его нужно скопировать.
+// Вызывается синхронно в горутине, выполняющей запрос, поэтому не должен блокироваться.
+type RawPDUTap func(direction Direction, layer Layer, data []byte)
+
+// WithRawPDUTap устанавливает RawPDUTap для уровней Session, Presentation, ACSE и MMS.
+// PDU уровня COTP передаются через cotp.WithPacketTap соединения.
+func WithRawPDUTap(tap RawPDUTap) ClientOption {
+	return func(c *Client) {
+		c.tap = tap
+	}
+}
+
+// Tap передаёт PDU в RawPDUTap, если он задан. Используется при формировании
+// PDU вне Client (например, AARQ в Initiate).
+func (c *Client) Tap(direction Direction, layer Layer, data []byte) {
+	if c.tap != nil {
+		c.tap(direction, layer, data)
+	}
+}
@@ -4,7 +4,8 @@ import (
 	"errors"
 	"fmt"
 
-	"github.com/slonegd/go61850/ber"
+	"github.com/slonegd/go61850/internal/ber"
+	"github.com/slonegd/go61850/osi/mms/variant"
 )
 
 // TypeSpecification представляет спецификацию типа MMS
@@ -36,7 +37,7 @@ type TypeSpecification struct {
 	FloatingPoint *FloatingPointTypeSpec
 	// OctetStringSize - для octet-string: размер в октетах
 	OctetStringSize int
-	// VisibleStringSize - для visible-string: максимальный размер
+	// VisibleStringSize - для visible-string и mmsString: максимальный размер
 	VisibleStringSize int
 	// Array - для массива: количество элементов и тип элемента
 	Array *ArrayTypeSpec
@@ -60,6 +61,38 @@ const (
 	TypeSpecBinaryTime
 )
 
+// String возвращает имя типа по ISO/IEC 9506-2, например "floating-point"
+func (t TypeSpecType) String() string {
+	switch t {
+	case TypeSpecStructure:
+		return "structure"
+	case TypeSpecArray:
+		return "array"
+	case TypeSpecBoolean:
+		return "boolean"
+	case TypeSpecBitString:
+		return "bit-string"
+	case TypeSpecInteger:
+		return "integer"
+	case TypeSpecUnsigned:
+		return "unsigned"
+	case TypeSpecFloatingPoint:
+		return "floating-point"
+	case TypeSpecOctetString:
+		return "octet-string"
+	case TypeSpecVisibleString:
+		return "visible-string"
+	case TypeSpecMMSString:
+		return "mms-string"
+	case TypeSpecUTCTime:
+		return "utc-time"
+	case TypeSpecBinaryTime:
+		return "binary-time"
+	default:
+		return fmt.Sprintf("unknown(%d)", int(t))
+	}
+}
+
 // StructureTypeSpec представляет спецификацию структуры
 type StructureTypeSpec struct {
 	Components []ComponentSpec
@@ -171,12 +204,16 @@ type VariableAccessAttributesResponse struct {
 //	  80 01 00 - mmsDeletable: false (tag 0x80, boolean, длина 1, значение 0x00)
 //	  a2 81 fe - typeSpecification: structure (tag 0xa2), длина 0x01fe
 //
-// После установления соединения данные могут приходить без внешнего тега confirmed-ResponsePDU
+// После установления соединения данные могут приходить без внешнего тега confirmed-ResponsePDU.
+// Для confirmed-ErrorPDU возвращается *ConfirmedError.
 func ParseGetVariableAccessAttributesResponse(buffer []byte) (*VariableAccessAttributesResponse, error) {
 	var response VariableAccessAttributesResponse
 	if len(buffer) == 0 {
 		return nil, errors.New("empty buffer")
 	}
+	if err := checkConfirmedError(buffer); err != nil {
+		return nil, err
+	}
 
 	// Проверяем формат данных
 	// После установления соединения данные могут приходить в разных форматах:
@@ -443,6 +480,9 @@ func parseTypeSpecification(buffer []byte, maxLength int) (*TypeSpecification, e
 	case 0x8B: // mmsString
 		return &TypeSpecification{Type: TypeSpecMMSString}, nil
 
+	case 0x90: // mMSString [16] согласно ISO/IEC 9506-2 (UTF8String)
+		return &TypeSpecification{Type: TypeSpecMMSString}, nil
+
 	case 0x8C: // utc-time
 		return &TypeSpecification{Type: TypeSpecUTCTime}, nil
 
@@ -841,3 +881,112 @@ func parseFloatingPointTypeSpec(buffer []byte, maxLength int) (*TypeSpecificatio
 		},
 	}, nil
 }
+
+// Размеры строк по умолчанию в TypeOf: отрицательный размер в TypeDescription
+// означает строку переменной длины не длиннее модуля значения, как у
+// VisibleString255, Unicode255 и Octet64 IEC 61850-8-1
+const (
+	defaultStringSize      = -255
+	defaultOctetStringSize = -64
+)
+
+// TypeOf возвращает спецификацию типа значения v: integer и unsigned - 32 или 64 бита,
+// floating-point - IEEE 754 single, bit-string - размер значения, строки -
+// переменной длины по умолчанию (VisibleString255, Unicode255, Octet64).
+// Компоненты структуры не имеют имён; их задаёт модель данных сервера.
+func TypeOf(v *variant.Variant) *TypeSpecification {
+	switch v.Type() {
+	case variant.Float32:
+		return &TypeSpecification{Type: TypeSpecFloatingPoint, FloatingPoint: &FloatingPointTypeSpec{FormatWidth: 32, ExponentWidth: 8}}
+	case variant.Int32:
+		return &TypeSpecification{Type: TypeSpecInteger, IntegerSize: 32}
+	case variant.Uint32:
+		return &TypeSpecification{Type: TypeSpecUnsigned, UnsignedSize: 32}
+	case variant.Int64:
+		return &TypeSpecification{Type: TypeSpecInteger, IntegerSize: 64}
+	case variant.Uint64:
+		return &TypeSpecification{Type: TypeSpecUnsigned, UnsignedSize: 64}
+	case variant.Bool:
+		return &TypeSpecification{Type: TypeSpecBoolean}
+	case variant.BitString:
+		return &TypeSpecification{Type: TypeSpecBitString, BitStringSize: v.BitString().BitSize}
+	case variant.VisibleString:
+		return &TypeSpecification{Type: TypeSpecVisibleString, VisibleStringSize: defaultStringSize}
+	case variant.MMSString:
+		return &TypeSpecification{Type: TypeSpecMMSString, VisibleStringSize: defaultStringSize}
+	case variant.OctetString:
+		return &TypeSpecification{Type: TypeSpecOctetString, OctetStringSize: defaultOctetStringSize}
+	case variant.UTCTime:
+		return &TypeSpecification{Type: TypeSpecUTCTime}
+	case variant.BinaryTime:
+		return &TypeSpecification{Type: TypeSpecBinaryTime}
+	case variant.Structure:
+		elements := v.Structure()
+		components := make([]ComponentSpec, len(elements))
+		for i, element := range elements {
+			components[i].Type = TypeOf(element)
+		}
+		return &TypeSpecification{Type: TypeSpecStructure, Structure: &StructureTypeSpec{Components: components}}
+	}
+	return nil
+}
+
+// Bytes кодирует спецификацию как TypeDescription ISO/IEC 9506-2 (сторона сервера):
+//
+//	array [1] { numberOfElements [1], elementType [2] }, structure [2] { components [1] {
+//	  SEQUENCE { componentName [0], componentType [1] } } }, boolean [3], bit-string [4],
+//	integer [5], unsigned [6], floating-point [7] { format-width, exponent-width },
+//	octet-string [9], visible-string [10], binary-time [12], mMSString [16], utc-time [17]
+//
+// binary-time кодируется с датой (6 байт, как TimeOfEntry)
+func (t *TypeSpecification) Bytes() []byte {
+	switch t.Type {
+	case TypeSpecStructure:
+		var components []byte
+		if t.Structure != nil {
+			for _, component := range t.Structure.Components {
+				content := wrapTL(ber.ContextSpecific0Primitive, []byte(component.Name))
+				content = append(content, wrapTL(ber.ContextSpecific1Constructed, component.Type.Bytes())...)
+				components = append(components, wrapTL(ber.SequenceConstructed, content)...)
+			}
+		}
+		return wrapTL(ber.ContextSpecific2Constructed, wrapTL(ber.ContextSpecific1Constructed, components))
+	case TypeSpecArray:
+		content := encodeUnsigned(ber.ContextSpecific1Primitive, uint32(t.Array.ElementCount))
+		content = append(content, wrapTL(ber.ContextSpecific2Constructed, t.Array.ElementType.Bytes())...)
+		return wrapTL(ber.ContextSpecific1Constructed, content)
+	case TypeSpecBoolean:
+		return wrapTL(ber.ContextSpecific3Primitive, nil)
+	case TypeSpecBitString:
+		return wrapTL(0x84, encodeInt(int32(t.BitStringSize)))
+	case TypeSpecInteger:
+		return wrapTL(ber.ContextSpecific5Primitive, encodeInt(int32(t.IntegerSize)))
+	case TypeSpecUnsigned:
+		return wrapTL(ber.ContextSpecific6Primitive, encodeInt(int32(t.UnsignedSize)))
+	case TypeSpecFloatingPoint:
+		content := wrapTL(ber.Integer, encodeInt(int32(t.FloatingPoint.FormatWidth)))
+		content = append(content, wrapTL(ber.Integer, encodeInt(int32(t.FloatingPoint.ExponentWidth)))...)
+		return wrapTL(ber.ContextSpecific7Constructed, content)
+	case TypeSpecOctetString:
+		return wrapTL(0x89, encodeInt(int32(t.OctetStringSize)))
+	case TypeSpecVisibleString:
+		return wrapTL(ber.ContextSpecific10Primitive, encodeInt(int32(t.VisibleStringSize)))
+	case TypeSpecBinaryTime:
+		return wrapTL(0x8c, encodeBool(true))
+	case TypeSpecMMSString:
+		return wrapTL(0x90, encodeInt(int32(t.VisibleStringSize)))
+	case TypeSpecUTCTime:
+		return wrapTL(0x91, nil)
+	}
+	return nil
+}
+
+// Bytes кодирует ответ в confirmed-ResponsePDU (сторона сервера):
+// a1 { 02 invokeID, a6 { 80 mmsDeletable, a2 TypeDescription } }
+func (r *VariableAccessAttributesResponse) Bytes() []byte {
+	content := wrapTL(ber.ContextSpecific0Primitive, encodeBool(r.MmsDeletable))
+	content = append(content, wrapTL(ber.ContextSpecific2Constructed, r.TypeSpecification.Bytes())...)
+	pdu := encodeInvokeID(r.InvokeID)
+	pdu = append(pdu, wrapTL(ber.ContextSpecific6Constructed, content)...)
+	return wrapTL(ber.ContextSpecific1Constructed, pdu)
+}
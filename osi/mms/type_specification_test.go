@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/slonegd/go61850/osi/mms/variant"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -261,3 +263,74 @@ func TestParseGetVariableAccessAttributesResponse(t *testing.T) {
 		})
 	}
 }
+
+func TestVariableAccessAttributesResponseBytes(t *testing.T) {
+	analogue := func(name string) ComponentSpec {
+		return ComponentSpec{Name: name, Type: &TypeSpecification{
+			Type: TypeSpecStructure,
+			Structure: &StructureTypeSpec{Components: []ComponentSpec{
+				{Name: "mag", Type: &TypeSpecification{
+					Type: TypeSpecStructure,
+					Structure: &StructureTypeSpec{Components: []ComponentSpec{
+						{Name: "f", Type: TypeOf(variant.NewFloat32Variant(0))},
+					}},
+				}},
+				{Name: "q", Type: &TypeSpecification{Type: TypeSpecBitString, BitStringSize: -13}},
+				{Name: "t", Type: TypeOf(variant.NewUTCTimeVariant(time.Time{}))},
+			}},
+		}}
+	}
+	response := &VariableAccessAttributesResponse{
+		InvokeID: 2,
+		TypeSpecification: &TypeSpecification{
+			Type: TypeSpecStructure,
+			Structure: &StructureTypeSpec{Components: []ComponentSpec{
+				analogue("AnIn1"), analogue("AnIn2"), analogue("AnIn3"), analogue("AnIn4"),
+			}},
+		},
+	}
+	// GGIO1$MX из wireshark (см. TestParseGetVariableAccessAttributesResponse)
+	want := "a182010b020102a6820104800100a281fea281fba181f8" +
+		strings.Repeat("303c8005416e496e3Xa133a231a12f301a80036d6167a113a211a10f300d800166a108a7060201200201083008800171a1038401f33007800174a1029100", 4)
+	for i := 1; i <= 4; i++ {
+		want = strings.Replace(want, "3X", fmt.Sprintf("3%d", i), 1)
+	}
+	assert.Equal(t, want, fmt.Sprintf("%x", response.Bytes()))
+
+	// Имена компонентов переживают разбор
+	parsed, err := ParseGetVariableAccessAttributesResponse(response.Bytes())
+	assert.NoError(t, err)
+	assert.Equal(t, "AnIn4", parsed.TypeSpecification.Structure.Components[3].Name)
+
+	for _, tt := range []struct {
+		spec *TypeSpecification
+		want string
+	}{
+		{TypeOf(variant.NewBoolVariant(true)), "8300"},
+		{TypeOf(variant.NewInt32Variant(0)), "850120"},
+		{TypeOf(variant.NewUint32Variant(0)), "860120"},
+		{TypeOf(variant.NewVisibleStringVariant("")), "8a02ff01"},
+		{TypeOf(variant.NewOctetStringVariant(nil)), "8901c0"},
+		{&TypeSpecification{Type: TypeSpecArray, Array: &ArrayTypeSpec{ElementCount: 3, ElementType: TypeOf(variant.NewBoolVariant(true))}}, "a1078101 03a2028300"},
+	} {
+		assert.Equal(t, strings.ReplaceAll(tt.want, " ", ""), fmt.Sprintf("%x", tt.spec.Bytes()))
+	}
+}
+
+func TestParseGetVariableAccessAttributesRequest(t *testing.T) {
+	request := NewGetVariableAccessAttributesRequest("simpleIOGenericIO", "GGIO1$MX")
+	request.InvokeID = 7
+	parsed, err := ParseGetVariableAccessAttributesRequest(request.Bytes())
+	assert.NoError(t, err)
+	assert.Equal(t, request, parsed)
+
+	read := (&ReadRequest{InvokeID: 1, DomainID: "LD0", ItemID: "A"}).Bytes()
+	_, err = ParseGetVariableAccessAttributesRequest(read)
+	assert.EqualError(t, err, "confirmed-RequestPDU does not contain getVariableAccessAttributes request: read")
+}
+
+func TestTypeSpecTypeString(t *testing.T) {
+	assert.Equal(t, "floating-point", TypeSpecFloatingPoint.String())
+	assert.Equal(t, "visible-string", TypeSpecVisibleString.String())
+	assert.Equal(t, "unknown(99)", TypeSpecType(99).String())
+}
@@ -0,0 +1,53 @@
+package variant
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Path - путь к элементу значения: индексы элементов вложенных структур от корня.
+// Пустой путь обозначает само значение.
+type Path []int
+
+// String возвращает путь в виде "[0][2]"; пустой путь - "."
+func (p Path) String() string {
+	if len(p) == 0 {
+		return "."
+	}
+	var b strings.Builder
+	for _, i := range p {
+		b.WriteByte('[')
+		b.WriteString(strconv.Itoa(i))
+		b.WriteByte(']')
+	}
+	return b.String()
+}
+
+// Change - различие двух значений по пути Path
+type Change struct {
+	Path Path
+	// Old и New - значения по пути Path; nil, если элемента нет
+	Old, New *Variant
+}
+
+// Diff возвращает различия значений a и b. Структуры одинаковой длины сравниваются
+// поэлементно, поэтому результат содержит только изменившиеся листовые значения;
+// структуры разной длины и значения разных типов дают одно различие на своём пути.
+// Значения сравниваются Equal. Для равных значений возвращается nil.
+func Diff(a, b *Variant) []Change {
+	return appendDiff(nil, nil, a, b)
+}
+
+// appendDiff добавляет к changes различия значений a и b по пути path
+func appendDiff(changes []Change, path Path, a, b *Variant) []Change {
+	if a != nil && b != nil && a.typ == Structure && b.typ == Structure && len(a.elements) == len(b.elements) {
+		for i := range a.elements {
+			changes = appendDiff(changes, append(path[:len(path):len(path)], i), a.elements[i], b.elements[i])
+		}
+		return changes
+	}
+	if a.Equal(b) {
+		return changes
+	}
+	return append(changes, Change{Path: path, Old: a, New: b})
+}
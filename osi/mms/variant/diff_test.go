@@ -0,0 +1,35 @@
+package variant
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	mag := func(f float32) *Variant {
+		return NewStructureVariant([]*Variant{NewFloat32Variant(f)})
+	}
+	quality := NewBitStringVariant([]byte{0, 0}, 13)
+	a := NewStructureVariant([]*Variant{mag(1.5), quality, NewInt32Variant(3)})
+
+	assert.Nil(t, Diff(a, NewStructureVariant([]*Variant{mag(1.5), quality, NewInt32Variant(3)})))
+	assert.Nil(t, Diff(nil, nil))
+
+	// Изменились вложенный и листовой элементы
+	b := NewStructureVariant([]*Variant{mag(2), quality, NewInt32Variant(4)})
+	changes := Diff(a, b)
+	assert.Equal(t, []Change{
+		{Path: Path{0, 0}, Old: a.Structure()[0].Structure()[0], New: b.Structure()[0].Structure()[0]},
+		{Path: Path{2}, Old: a.Structure()[2], New: b.Structure()[2]},
+	}, changes)
+	assert.Equal(t, "[0][0]", changes[0].Path.String())
+
+	// Структуры разной длины, разные типы и nil - одно различие на своём пути
+	c := NewStructureVariant([]*Variant{mag(1.5), quality})
+	assert.Equal(t, []Change{{Path: nil, Old: a, New: c}}, Diff(a, c))
+	assert.Equal(t, []Change{{Path: nil, Old: NewInt32Variant(1), New: NewUint32Variant(1)}},
+		Diff(NewInt32Variant(1), NewUint32Variant(1)))
+	assert.Equal(t, []Change{{Path: nil, Old: nil, New: a}}, Diff(nil, a))
+	assert.Equal(t, ".", Path(nil).String())
+}
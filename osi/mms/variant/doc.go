@@ -0,0 +1,7 @@
+// Package variant представляет значения данных MMS (Data ISO 9506-2): простые
+// типы IEC 61850 (boolean, integer, unsigned, float, bit-string, строки, время)
+// и структуры из них. Variant создаётся функциями New*Variant, значение читается
+// методом соответствующего типа (Float32, BitString, Structure...) после проверки
+// Type. String выводит значение в виде, используемом в журналах и тестах,
+// Equal и Diff сравнивают значения.
+package variant
@@ -0,0 +1,33 @@
+package variant_test
+
+import (
+	"fmt"
+
+	"github.com/slonegd/go61850/osi/mms/variant"
+)
+
+// Значение MV: структура {mag{f}, q, t}
+func ExampleNewStructureVariant() {
+	mag := variant.NewStructureVariant([]*variant.Variant{variant.NewFloat32Variant(1.5)})
+	v := variant.NewStructureVariant([]*variant.Variant{mag, variant.NewBitStringVariant([]byte{0x00, 0x00}, 13)})
+	fmt.Println(v)
+	if v.Type() == variant.Structure {
+		fmt.Println(v.Structure()[0].Structure()[0].Float32())
+	}
+	// Output:
+	// struct{struct{float32(1.5)}, bit-string(0b0_0000_0000_0000)}
+	// 1.5
+}
+
+// Сравнение значений: Diff перечисляет отличающиеся элементы структуры
+func ExampleDiff() {
+	a := variant.NewStructureVariant([]*variant.Variant{variant.NewInt32Variant(1), variant.NewBoolVariant(true)})
+	b := variant.NewStructureVariant([]*variant.Variant{variant.NewInt32Variant(2), variant.NewBoolVariant(true)})
+	fmt.Println(a.Equal(b))
+	for _, change := range variant.Diff(a, b) {
+		fmt.Println(change.Path, change.Old, change.New)
+	}
+	// Output:
+	// false
+	// [0] int32(1) int32(2)
+}
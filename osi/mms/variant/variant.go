@@ -1,6 +1,9 @@
 package variant
 
 import (
+	"bytes"
+	"encoding/hex"
+	"math"
 	"strconv"
 	"strings"
 	"time"
@@ -20,8 +23,22 @@ const (
 	BitString
 	// Structure - structure (структура) согласно ISO/IEC 9506-2, содержит последовательность элементов Data
 	Structure
-	// Bool - boolean (будет добавлено позже)
-	// String - visible string (будет добавлено позже)
+	// VisibleString - visible-string: печатаемые символы ASCII
+	VisibleString
+	// MMSString - MMSString (UTF8String) для строк Unicode, например атрибутов dU в Edition 2
+	MMSString
+	// Bool - boolean
+	Bool
+	// Uint32 - unsigned integer (INT8U..INT32U в IEC 61850)
+	Uint32
+	// OctetString - octet-string (например, orIdent)
+	OctetString
+	// BinaryTime - binary-time (TimeOfEntry отчётов и журналов): 4 или 6 байт
+	BinaryTime
+	// Int64 - 64-bit signed integer (INT64 в IEC 61850)
+	Int64
+	// Uint64 - 64-bit unsigned integer (INT64U)
+	Uint64
 )
 
 // String возвращает строковое представление Type
@@ -37,6 +54,22 @@ func (t Type) String() string {
 		return "bit-string"
 	case Structure:
 		return "structure"
+	case VisibleString:
+		return "visible-string"
+	case MMSString:
+		return "mms-string"
+	case Bool:
+		return "bool"
+	case Uint32:
+		return "uint32"
+	case OctetString:
+		return "octet-string"
+	case BinaryTime:
+		return "binary-time"
+	case Int64:
+		return "int64"
+	case Uint64:
+		return "uint64"
 	default:
 		// Используем strings.Builder вместо fmt.Sprintf для лучшей производительности
 		var b strings.Builder
@@ -50,15 +83,28 @@ func (t Type) String() string {
 // Variant представляет типизированное значение MMS Data
 // Согласно ISO/IEC 9506-2, Data может быть разных типов:
 // - floating-point (IEEE 754 single precision)
-// - integer (32-bit signed)
+// - integer (32-bit или 64-bit signed), unsigned
 // - utc-time (UTC time, 8 байт)
 // - bit-string (BIT STRING)
 // - bool (boolean)
 // - visible-string
 // и т.д.
+//
+// Значения хранятся в типизированных полях, а не в interface{}: создание Variant
+// не требует дополнительного выделения памяти под упакованное значение, что важно
+// при разборе большого потока отчётов.
 type Variant struct {
-	typ   Type
-	value interface{}
+	typ Type
+	// num - float32 (биты IEEE 754), int32, uint32, int64, uint64, bool или число бит bit-string
+	num uint64
+	// str - visible-string и MMSString
+	str string
+	// data - bit-string, octet-string и binary-time
+	data []byte
+	// elements - элементы структуры
+	elements []*Variant
+	// time - utc-time
+	time time.Time
 }
 
 // Type возвращает тип значения Variant
@@ -74,11 +120,15 @@ func (v *Variant) Float32() float32 {
 		return 0.0
 	}
 
-	switch val := v.value.(type) {
-	case float32:
-		return val
-	case int32:
-		return float32(val)
+	switch v.typ {
+	case Float32:
+		return math.Float32frombits(uint32(v.num))
+	case Int32:
+		return float32(int32(v.num))
+	case Int64:
+		return float32(int64(v.num))
+	case Uint32, Uint64:
+		return float32(v.num)
 	default:
 		return 0.0
 	}
@@ -92,11 +142,31 @@ func (v *Variant) Int32() int32 {
 		return 0
 	}
 
-	switch val := v.value.(type) {
-	case int32:
-		return val
-	case float32:
-		return int32(val)
+	switch v.typ {
+	case Int32:
+		return int32(v.num)
+	case Float32:
+		return int32(math.Float32frombits(uint32(v.num)))
+	default:
+		return 0
+	}
+}
+
+// Int64 возвращает значение как int64
+// Если тип не совпадает, пытается преобразовать значение к int64
+// Возвращает 0 если преобразование невозможно
+func (v *Variant) Int64() int64 {
+	if v == nil {
+		return 0
+	}
+
+	switch v.typ {
+	case Int64, Uint64, Uint32:
+		return int64(v.num)
+	case Int32:
+		return int64(int32(v.num))
+	case Float32:
+		return int64(math.Float32frombits(uint32(v.num)))
 	default:
 		return 0
 	}
@@ -105,42 +175,104 @@ func (v *Variant) Int32() int32 {
 // NewFloat32Variant создаёт новый Variant с float32 значением
 func NewFloat32Variant(value float32) *Variant {
 	return &Variant{
-		typ:   Float32,
-		value: value,
+		typ: Float32,
+		num: uint64(math.Float32bits(value)),
 	}
 }
 
 // NewInt32Variant создаёт новый Variant с int32 значением
 func NewInt32Variant(value int32) *Variant {
 	return &Variant{
-		typ:   Int32,
-		value: value,
+		typ: Int32,
+		num: uint64(uint32(value)),
+	}
+}
+
+// NewInt64Variant создаёт новый Variant с int64 значением
+func NewInt64Variant(value int64) *Variant {
+	return &Variant{
+		typ: Int64,
+		num: uint64(value),
 	}
 }
 
 // Time возвращает значение как time.Time
 // Если тип не совпадает, возвращает нулевое время
 func (v *Variant) Time() time.Time {
-	if v == nil {
-		return time.Time{}
-	}
-
-	switch val := v.value.(type) {
-	case time.Time:
-		return val
-	default:
+	if v == nil || v.typ != UTCTime {
 		return time.Time{}
 	}
+	return v.time
 }
 
 // NewUTCTimeVariant создаёт новый Variant с time.Time значением
+// и качеством времени DefaultTimeQuality
 func NewUTCTimeVariant(value time.Time) *Variant {
+	return NewUTCTimeVariantWithQuality(value, DefaultTimeQuality)
+}
+
+// NewUTCTimeVariantWithQuality создаёт новый Variant с time.Time значением и качеством времени quality
+func NewUTCTimeVariantWithQuality(value time.Time, quality TimeQuality) *Variant {
 	return &Variant{
-		typ:   UTCTime,
-		value: value,
+		typ:  UTCTime,
+		num:  uint64(quality),
+		time: value,
 	}
 }
 
+// TimeQuality возвращает качество времени utc-time
+// Если тип не совпадает, возвращает 0
+func (v *Variant) TimeQuality() TimeQuality {
+	if v == nil || v.typ != UTCTime {
+		return 0
+	}
+	return TimeQuality(v.num)
+}
+
+// TimeQuality - качество времени utc-time (последний байт значения, IEC 61850-8-1):
+// флаги в старших битах и точность в младших 5 битах
+type TimeQuality uint8
+
+const (
+	// TimeLeapSecondsKnown - известны високосные секунды
+	TimeLeapSecondsKnown TimeQuality = 0x80
+	// TimeClockFailure - отказ источника времени
+	TimeClockFailure TimeQuality = 0x40
+	// TimeClockNotSynchronized - часы не синхронизированы с внешним источником
+	TimeClockNotSynchronized TimeQuality = 0x20
+	// TimeAccuracyUnspecified - точность не задана
+	TimeAccuracyUnspecified = 31
+	// DefaultTimeQuality - качество времени по умолчанию: точность 10 бит, как в libIEC61850
+	DefaultTimeQuality TimeQuality = 0x0a
+)
+
+// LeapSecondsKnown сообщает, известны ли високосные секунды
+func (q TimeQuality) LeapSecondsKnown() bool {
+	return q&TimeLeapSecondsKnown != 0
+}
+
+// ClockFailure сообщает об отказе источника времени
+func (q TimeQuality) ClockFailure() bool {
+	return q&TimeClockFailure != 0
+}
+
+// ClockNotSynchronized сообщает, что часы не синхронизированы
+func (q TimeQuality) ClockNotSynchronized() bool {
+	return q&TimeClockNotSynchronized != 0
+}
+
+// Accuracy возвращает точность времени - число значащих бит доли секунды (0..24)
+// или TimeAccuracyUnspecified
+func (q TimeQuality) Accuracy() int {
+	return int(q & 0x1f)
+}
+
+// Reliable сообщает, можно ли доверять отметке времени: источник времени
+// исправен и часы синхронизированы
+func (q TimeQuality) Reliable() bool {
+	return !q.ClockFailure() && !q.ClockNotSynchronized()
+}
+
 // BitStringValue представляет значение bit-string
 // Содержит данные и количество бит (размер может быть не кратен 8)
 type BitStringValue struct {
@@ -153,50 +285,167 @@ type BitStringValue struct {
 // NewBitStringVariant создаёт новый Variant с BitStringValue значением
 func NewBitStringVariant(data []byte, bitSize int) *Variant {
 	return &Variant{
-		typ: BitString,
-		value: BitStringValue{
-			Data:    data,
-			BitSize: bitSize,
-		},
+		typ:  BitString,
+		num:  uint64(bitSize),
+		data: data,
 	}
 }
 
 // NewStructureVariant создаёт новый Variant с структурой (массивом элементов)
 func NewStructureVariant(elements []*Variant) *Variant {
 	return &Variant{
-		typ:   Structure,
-		value: elements,
+		typ:      Structure,
+		elements: elements,
 	}
 }
 
-// Structure возвращает значение как []*Variant (элементы структуры)
+// NewVisibleStringVariant создаёт новый Variant с visible-string значением
+func NewVisibleStringVariant(value string) *Variant {
+	return &Variant{
+		typ: VisibleString,
+		str: value,
+	}
+}
+
+// NewMMSStringVariant создаёт новый Variant с MMSString (UTF-8) значением
+func NewMMSStringVariant(value string) *Variant {
+	return &Variant{
+		typ: MMSString,
+		str: value,
+	}
+}
+
+// StringValue возвращает значение visible-string или MMSString
+// Если тип не совпадает, возвращает пустую строку
+func (v *Variant) StringValue() string {
+	if v == nil {
+		return ""
+	}
+
+	switch v.typ {
+	case VisibleString, MMSString:
+		return v.str
+	default:
+		return ""
+	}
+}
+
+// NewBoolVariant создаёт новый Variant с boolean значением
+func NewBoolVariant(value bool) *Variant {
+	var num uint64
+	if value {
+		num = 1
+	}
+	return &Variant{
+		typ: Bool,
+		num: num,
+	}
+}
+
+// Bool возвращает значение как bool
+// Если тип не совпадает, возвращает false
+func (v *Variant) Bool() bool {
+	return v != nil && v.typ == Bool && v.num != 0
+}
+
+// NewUint32Variant создаёт новый Variant с unsigned значением
+func NewUint32Variant(value uint32) *Variant {
+	return &Variant{
+		typ: Uint32,
+		num: uint64(value),
+	}
+}
+
+// Uint32 возвращает значение как uint32
+// Если тип не совпадает, пытается преобразовать значение к uint32
+// Возвращает 0 если преобразование невозможно
+func (v *Variant) Uint32() uint32 {
+	if v == nil {
+		return 0
+	}
+
+	switch v.typ {
+	case Uint32, Int32, Int64, Uint64:
+		return uint32(v.num)
+	default:
+		return 0
+	}
+}
+
+// NewUint64Variant создаёт новый Variant с unsigned значением INT64U
+func NewUint64Variant(value uint64) *Variant {
+	return &Variant{
+		typ: Uint64,
+		num: value,
+	}
+}
+
+// Uint64 возвращает значение как uint64
+// Если тип не совпадает, пытается преобразовать значение к uint64
+// Возвращает 0 если преобразование невозможно
+func (v *Variant) Uint64() uint64 {
+	if v == nil {
+		return 0
+	}
+
+	switch v.typ {
+	case Uint32, Int64, Uint64:
+		return v.num
+	case Int32:
+		return uint64(int64(int32(v.num)))
+	default:
+		return 0
+	}
+}
+
+// NewOctetStringVariant создаёт новый Variant с octet-string значением
+func NewOctetStringVariant(value []byte) *Variant {
+	return &Variant{
+		typ:  OctetString,
+		data: value,
+	}
+}
+
+// NewBinaryTimeVariant создаёт новый Variant с binary-time значением
+// (миллисекунды от полуночи и, для 6 байт, дни с 1 января 1984 года)
+func NewBinaryTimeVariant(value []byte) *Variant {
+	return &Variant{
+		typ:  BinaryTime,
+		data: value,
+	}
+}
+
+// OctetString возвращает значение как []byte (octet-string или binary-time)
 // Если тип не совпадает, возвращает nil
-func (v *Variant) Structure() []*Variant {
+func (v *Variant) OctetString() []byte {
 	if v == nil {
 		return nil
 	}
 
-	switch val := v.value.(type) {
-	case []*Variant:
-		return val
+	switch v.typ {
+	case OctetString, BinaryTime:
+		return v.data
 	default:
 		return nil
 	}
 }
 
+// Structure возвращает значение как []*Variant (элементы структуры)
+// Если тип не совпадает, возвращает nil
+func (v *Variant) Structure() []*Variant {
+	if v == nil || v.typ != Structure {
+		return nil
+	}
+	return v.elements
+}
+
 // BitString возвращает значение как BitStringValue
 // Если тип не совпадает, возвращает пустое значение
 func (v *Variant) BitString() BitStringValue {
-	if v == nil {
-		return BitStringValue{}
-	}
-
-	switch val := v.value.(type) {
-	case BitStringValue:
-		return val
-	default:
+	if v == nil || v.typ != BitString {
 		return BitStringValue{}
 	}
+	return BitStringValue{Data: v.data, BitSize: int(v.num)}
 }
 
 // String возвращает строковое представление Variant в формате "тип(значение)"
@@ -244,6 +493,18 @@ func (v *Variant) String() string {
 		val := v.Time()
 		// Форматируем время в RFC3339 с наносекундами
 		b.WriteString(val.Format(time.RFC3339Nano))
+	case VisibleString, MMSString:
+		b.WriteString(strconv.Quote(v.StringValue()))
+	case Bool:
+		b.WriteString(strconv.FormatBool(v.Bool()))
+	case Uint32:
+		b.WriteString(strconv.FormatUint(uint64(v.Uint32()), 10))
+	case Int64:
+		b.WriteString(strconv.FormatInt(v.Int64(), 10))
+	case Uint64:
+		b.WriteString(strconv.FormatUint(v.Uint64(), 10))
+	case OctetString, BinaryTime:
+		b.WriteString(hex.EncodeToString(v.OctetString()))
 	case BitString:
 		val := v.BitString()
 		// Форматируем bit-string в бинарном формате с подчеркиваниями для читаемости
@@ -301,3 +562,58 @@ func (v *Variant) String() string {
 	b.WriteByte(')')
 	return b.String()
 }
+
+// Equal сравнивает тип и значение двух Variant. Время сравнивается как момент
+// (без учёта часового пояса) вместе с качеством времени, bit-string - по значащим битам.
+func (v *Variant) Equal(other *Variant) bool {
+	if v == nil || other == nil {
+		return v == other
+	}
+	if v.typ != other.typ {
+		return false
+	}
+
+	switch v.typ {
+	case Structure:
+		a, b := v.Structure(), other.Structure()
+		if len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !a[i].Equal(b[i]) {
+				return false
+			}
+		}
+		return true
+	case UTCTime:
+		return v.Time().Equal(other.Time()) && v.num == other.num
+	case OctetString, BinaryTime:
+		return bytes.Equal(v.OctetString(), other.OctetString())
+	case BitString:
+		a, b := v.BitString(), other.BitString()
+		if a.BitSize != b.BitSize {
+			return false
+		}
+		for bit := 0; bit < a.BitSize; bit++ {
+			mask := byte(0x80) >> (bit % 8)
+			if byteAt(a.Data, bit/8)&mask != byteAt(b.Data, bit/8)&mask {
+				return false
+			}
+		}
+		return true
+	case Float32:
+		return v.Float32() == other.Float32()
+	case VisibleString, MMSString:
+		return v.str == other.str
+	default:
+		return v.num == other.num
+	}
+}
+
+// byteAt возвращает байт data[i] или 0, если data короче
+func byteAt(data []byte, i int) byte {
+	if i < len(data) {
+		return data[i]
+	}
+	return 0
+}
@@ -0,0 +1,289 @@
+package mms
+
+import (
+	"fmt"
+
+	"github.com/slonegd/go61850/internal/ber"
+	"github.com/slonegd/go61850/osi/mms/variant"
+)
+
+// WriteRequest представляет MMS Write Request PDU для одной или нескольких переменных
+// Структура согласно ISO/IEC 9506-2:
+//
+//	Write-Request ::= SEQUENCE {
+//	  variableAccessSpecification VariableAccessSpecification,
+//	  listOfData [0] IMPLICIT SEQUENCE OF Data
+//	}
+type WriteRequest struct {
+	InvokeID uint32
+	// DomainID - имя домена; пустой DomainID означает переменную уровня VMD
+	DomainID string
+	// ItemID - имя элемента в формате MMS (например, "CSWI1$CO$Pos$Oper")
+	ItemID string
+	// Value - записываемое значение
+	Value *variant.Variant
+	// Items - переменные запроса нескольких переменных; если задан,
+	// DomainID, ItemID и Value не используются
+	Items []WriteItem
+}
+
+// WriteItem - переменная и записываемое значение запроса нескольких переменных
+type WriteItem struct {
+	Variable ObjectName
+	Value    *variant.Variant
+}
+
+// Variables возвращает переменные запроса в порядке listOfVariable:
+// Items или одну переменную DomainID/ItemID
+func (r *WriteRequest) Variables() []WriteItem {
+	if len(r.Items) > 0 {
+		return r.Items
+	}
+	return []WriteItem{{Variable: ObjectName{DomainID: r.DomainID, ItemID: r.ItemID}, Value: r.Value}}
+}
+
+// Bytes кодирует WriteRequest в BER-кодированный пакет MMS confirmed-RequestPDU
+// a0 (confirmed-RequestPDU)
+//
+//	02 (invokeID)
+//	a5 (write)
+//	   a0 (listOfVariable) 30 a0 a1 { 1a domainId 1a itemId } ...
+//	   a0 (listOfData) Data ...
+func (r *WriteRequest) Bytes() ([]byte, error) {
+	var variables, data []byte
+	for _, item := range r.Variables() {
+		if err := item.Variable.Validate(); err != nil {
+			return nil, err
+		}
+		value, err := EncodeData(item.Value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode write value: %w", err)
+		}
+		name := (&ReadRequest{DomainID: item.Variable.DomainID, ItemID: item.Variable.ItemID}).buildVariableSpecification()
+		variables = append(variables, wrapTL(ber.SequenceConstructed, name)...)
+		data = append(data, value...)
+	}
+
+	content := wrapTL(ber.ContextSpecific0Constructed, variables)
+	content = append(content, wrapTL(ber.ContextSpecific0Constructed, data)...)
+
+	pdu := encodeInvokeID(r.InvokeID)
+	pdu = append(pdu, wrapTL(ber.ContextSpecific5Constructed, content)...)
+	return wrapTL(ber.ContextSpecific0Constructed, pdu), nil
+}
+
+// ParseWriteRequest парсит MMS Write Request PDU; запрос одной переменной
+// заполняет DomainID, ItemID и Value, нескольких - Items
+// (обратная операция к WriteRequest.Bytes). Используется серверной стороной.
+func ParseWriteRequest(buffer []byte) (*WriteRequest, error) {
+	confirmed, err := ParseConfirmedRequest(buffer)
+	if err != nil {
+		return nil, err
+	}
+	if confirmed.Service != ServiceWrite {
+		return nil, fmt.Errorf("confirmed-RequestPDU does not contain write request: %s", confirmed.Service)
+	}
+
+	specification, err := expectTLV(confirmed.Argument, byte(ber.ContextSpecific0Constructed), "listOfVariable")
+	if err != nil {
+		return nil, err
+	}
+	_, _, next, err := decodeTLV(confirmed.Argument, 0, len(confirmed.Argument))
+	if err != nil {
+		return nil, err
+	}
+	data, err := expectTLV(confirmed.Argument[next:], byte(ber.ContextSpecific0Constructed), "listOfData")
+	if err != nil {
+		return nil, err
+	}
+
+	var items []WriteItem
+	for bufPos := 0; bufPos < len(specification); {
+		_, item, next, err := decodeTLV(specification, bufPos, len(specification))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode listOfVariable item: %w", err)
+		}
+		if specification[bufPos] != byte(ber.SequenceConstructed) {
+			return nil, fmt.Errorf("expected listOfVariable item, got tag 0x%02x", specification[bufPos])
+		}
+		bufPos = next
+		if item, err = expectTLV(item, byte(ber.ContextSpecific0Constructed), "variableSpecification"); err != nil {
+			return nil, err
+		}
+		tag, value, _, err := decodeTLV(item, 0, len(item))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode object name: %w", err)
+		}
+		name, err := parseObjectName(tag, value)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, WriteItem{Variable: name})
+	}
+
+	var i int
+	for bufPos := 0; bufPos < len(data); i++ {
+		_, _, next, err := decodeTLV(data, bufPos, len(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode write value: %w", err)
+		}
+		if i < len(items) {
+			if items[i].Value, err = parseDataElement(data[bufPos:next]); err != nil {
+				return nil, fmt.Errorf("failed to parse write value of %s: %w", items[i].Variable, err)
+			}
+		}
+		bufPos = next
+	}
+	if len(items) == 0 || i != len(items) {
+		return nil, fmt.Errorf("write request contains %d values for %d variables", i, len(items))
+	}
+
+	request := &WriteRequest{InvokeID: confirmed.InvokeID}
+	if len(items) == 1 {
+		request.DomainID, request.ItemID, request.Value = items[0].Variable.DomainID, items[0].Variable.ItemID, items[0].Value
+	} else {
+		request.Items = items
+	}
+	return request, nil
+}
+
+// WriteResult представляет результат записи одной переменной
+type WriteResult struct {
+	// Variable - имя переменной; заполняется Bind по запросу, при разборе ответа пустое
+	Variable ObjectName
+	// Error - причина отказа сервера; nil при успешной записи
+	Error *DataAccessError
+}
+
+// Success сообщает, успешна ли запись переменной
+func (r WriteResult) Success() bool {
+	return r.Error == nil
+}
+
+// String возвращает результат в виде "domain/item: ok" или "domain/item: <код ошибки>"
+func (r WriteResult) String() string {
+	if r.Error == nil {
+		return r.Variable.String() + ": ok"
+	}
+	return r.Variable.String() + ": " + r.Error.String()
+}
+
+// WriteResponse представляет MMS Write Response PDU
+//
+//	Write-Response ::= SEQUENCE OF CHOICE {
+//	  failure [0] IMPLICIT DataAccessError,
+//	  success [1] IMPLICIT NULL
+//	}
+type WriteResponse struct {
+	InvokeID uint32
+	// Results - результаты записи в порядке переменных запроса
+	Results []WriteResult
+}
+
+// Bind сопоставляет результаты с переменными запроса request.
+// Возвращает ошибку, если число результатов не совпадает с числом переменных.
+func (r *WriteResponse) Bind(request *WriteRequest) error {
+	variables := request.Variables()
+	if len(r.Results) != len(variables) {
+		return fmt.Errorf("Write Response contains %d results for %d variables", len(r.Results), len(variables))
+	}
+	for i := range r.Results {
+		r.Results[i].Variable = variables[i].Variable
+	}
+	return nil
+}
+
+// Failed возвращает неуспешные результаты записи
+func (r *WriteResponse) Failed() []WriteResult {
+	var failed []WriteResult
+	for _, result := range r.Results {
+		if !result.Success() {
+			failed = append(failed, result)
+		}
+	}
+	return failed
+}
+
+// Err возвращает *DataAccessError первой неуспешной записи или nil
+func (r *WriteResponse) Err() error {
+	for _, result := range r.Results {
+		if !result.Success() {
+			return result.Error
+		}
+	}
+	return nil
+}
+
+// Bytes кодирует WriteResponse в BER-кодированный пакет MMS confirmed-ResponsePDU
+// a1 (confirmed-ResponsePDU) + invokeID + a5 (write) { 81 00 (success) | 80 (failure) }
+func (r *WriteResponse) Bytes() []byte {
+	var results []byte
+	for _, result := range r.Results {
+		if result.Success() {
+			results = append(results, wrapTL(ber.ContextSpecific1Primitive, nil)...)
+			continue
+		}
+		tempBuf := make([]byte, 8)
+		tempPos := ber.EncodeUInt32(uint32(result.Error.ErrorCode), tempBuf, 0)
+		results = append(results, wrapTL(ber.ContextSpecific0Primitive, tempBuf[:tempPos])...)
+	}
+
+	pdu := encodeInvokeID(r.InvokeID)
+	pdu = append(pdu, wrapTL(ber.ContextSpecific5Constructed, results)...)
+	return wrapTL(ber.ContextSpecific1Constructed, pdu)
+}
+
+// ParseWriteResponse парсит MMS Write Response PDU.
+// Для confirmed-ErrorPDU возвращается *ConfirmedError.
+func ParseWriteResponse(buffer []byte) (*WriteResponse, error) {
+	if err := checkConfirmedError(buffer); err != nil {
+		return nil, err
+	}
+	content, err := expectTLV(buffer, byte(ber.ContextSpecific1Constructed), "confirmed-ResponsePDU")
+	if err != nil {
+		return nil, err
+	}
+
+	response := &WriteResponse{}
+	var service []byte
+	for bufPos := 0; bufPos < len(content); {
+		tag, value, next, err := decodeTLV(content, bufPos, len(content))
+		if err != nil {
+			return nil, err
+		}
+		switch tag {
+		case byte(ber.Integer):
+			response.InvokeID = ber.DecodeUint32(value, len(value), 0)
+		case byte(ber.ContextSpecific5Constructed):
+			service = value
+		default:
+			return nil, fmt.Errorf("unexpected tag in confirmed-ResponsePDU: 0x%02x", tag)
+		}
+		bufPos = next
+	}
+	if service == nil {
+		return nil, fmt.Errorf("confirmed-ResponsePDU does not contain write response")
+	}
+
+	for bufPos := 0; bufPos < len(service); {
+		tag, value, next, err := decodeTLV(service, bufPos, len(service))
+		if err != nil {
+			return nil, err
+		}
+		switch tag {
+		case byte(ber.ContextSpecific1Primitive): // success
+			response.Results = append(response.Results, WriteResult{})
+		case byte(ber.ContextSpecific0Primitive): // failure
+			if len(value) < 1 || len(value) > 5 {
+				return nil, fmt.Errorf("invalid DataAccessError length: %d", len(value))
+			}
+			code := DataAccessErrorCode(ber.DecodeUint32(value, len(value), 0))
+			response.Results = append(response.Results, WriteResult{Error: &DataAccessError{ErrorCode: code}})
+		default:
+			return nil, fmt.Errorf("unexpected tag in write response: 0x%02x", tag)
+		}
+		bufPos = next
+	}
+
+	return response, nil
+}
@@ -0,0 +1,98 @@
+package mms
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/slonegd/go61850/osi/mms/variant"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteRequestBytes(t *testing.T) {
+	request := &WriteRequest{InvokeID: 1, DomainID: "LD0", ItemID: "A", Value: variant.NewBoolVariant(true)}
+	pdu, err := request.Bytes()
+	assert.NoError(t, err)
+	assert.Equal(t, parseHexString("a01a 020101 a515 a00e 300c a00a a108 1a034c4430 1a0141 a003 8301ff"), pdu)
+
+	_, err = (&WriteRequest{InvokeID: 1, ItemID: "A"}).Bytes()
+	assert.EqualError(t, err, "failed to encode write value: cannot encode nil variant")
+}
+
+func TestParseWriteRequest(t *testing.T) {
+	request := &WriteRequest{InvokeID: 7, DomainID: "LD0", ItemID: "CSWI1$CO$Pos$Oper",
+		Value: variant.NewStructureVariant([]*variant.Variant{variant.NewBoolVariant(true), variant.NewUint32Variant(3)})}
+	pdu, err := request.Bytes()
+	assert.NoError(t, err)
+	parsed, err := ParseWriteRequest(pdu)
+	assert.NoError(t, err)
+	assert.Equal(t, request, parsed)
+
+	// Значений больше, чем переменных
+	_, err = ParseWriteRequest(parseHexString("a01d 020101 a518 a00e 300c a00a a108 1a034c4430 1a0141 a006 8301ff 8301ff"))
+	assert.EqualError(t, err, "write request contains 2 values for 1 variables")
+	_, err = ParseWriteRequest(NewGetVariableAccessAttributesRequest("LD0", "A").Bytes())
+	assert.EqualError(t, err, "confirmed-RequestPDU does not contain write request: getVariableAccessAttributes")
+}
+
+func TestWriteRequestItems(t *testing.T) {
+	request := &WriteRequest{InvokeID: 3, Items: []WriteItem{
+		{Variable: ObjectName{DomainID: "LD0", ItemID: "A"}, Value: variant.NewBoolVariant(true)},
+		{Variable: ObjectName{ItemID: "B"}, Value: variant.NewInt32Variant(5)},
+	}}
+	pdu, err := request.Bytes()
+	assert.NoError(t, err)
+	assert.Equal(t, parseHexString("a024 020103 a51f a015 300c a00a a108 1a034c4430 1a0141 3005 a003 800142 a006 8301ff 850105"), pdu)
+
+	parsed, err := ParseWriteRequest(pdu)
+	assert.NoError(t, err)
+	assert.Equal(t, request, parsed)
+	assert.Equal(t, request.Items, parsed.Variables())
+
+	// Результаты сопоставляются с переменными запроса по порядку
+	response := &WriteResponse{InvokeID: 3, Results: []WriteResult{{}, {Error: &DataAccessError{ErrorCode: ObjectAccessDenied}}}}
+	assert.NoError(t, response.Bind(request))
+	assert.Equal(t, []WriteResult{{Variable: ObjectName{ItemID: "B"}, Error: &DataAccessError{ErrorCode: ObjectAccessDenied}}}, response.Failed())
+}
+
+func TestParseWriteResponse(t *testing.T) {
+	expected := &WriteResponse{
+		InvokeID: 1,
+		Results: []WriteResult{
+			{},
+			{Error: &DataAccessError{ErrorCode: ObjectAccessDenied}},
+		},
+	}
+	buffer := parseHexString("a10a 020101 a505 8100 800103")
+	assert.Equal(t, buffer, expected.Bytes())
+
+	response, err := ParseWriteResponse(buffer)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, response)
+
+	var accessErr *DataAccessError
+	assert.True(t, errors.As(response.Err(), &accessErr))
+	assert.Equal(t, ObjectAccessDenied, accessErr.ErrorCode)
+	assert.EqualError(t, response.Err(), "data access error: object-access-denied")
+
+	response, err = ParseWriteResponse(parseHexString("a107 020102 a5028100"))
+	assert.NoError(t, err)
+	assert.NoError(t, response.Err())
+}
+
+func TestWriteResponseBind(t *testing.T) {
+	response, err := ParseWriteResponse(parseHexString("a108 020101 a503 80010a"))
+	assert.NoError(t, err)
+
+	request := &WriteRequest{DomainID: "LD0", ItemID: "GGIO1$SP$NamPlt"}
+	assert.NoError(t, response.Bind(request))
+	assert.Equal(t, []WriteResult{{
+		Variable: ObjectName{DomainID: "LD0", ItemID: "GGIO1$SP$NamPlt"},
+		Error:    &DataAccessError{ErrorCode: ObjectNonExistent},
+	}}, response.Failed())
+	assert.Equal(t, "LD0/GGIO1$SP$NamPlt: object-non-existent", response.Results[0].String())
+
+	response, err = ParseWriteResponse(parseHexString("a109 020101 a504 8100 8100"))
+	assert.NoError(t, err)
+	assert.Empty(t, response.Failed())
+	assert.EqualError(t, response.Bind(request), "Write Response contains 2 results for 1 variables")
+}
@@ -0,0 +1,14 @@
+// Package presentation реализует уровень представления ISO 8823 в объёме,
+// используемом MMS: нормальный режим с двумя контекстами - ACSE (id 1)
+// и MMS (id 3), кодирование BER.
+//
+// При установлении ассоциации клиент передаёт CP-type (mms.NewAssociateRequest) с AARQ
+// пакета osi/acse, сервер отвечает CPA-PPDU (BuildCPAType) с AARE. Затем MMS PDU
+// передаются в fully-encoded-data: BuildUserData(pdu, 3) на отправке,
+// ParsePresentationPDU на приёме (PresentationPDU.Data и PresentationContextId).
+// PPDU переносятся SPDU пакета osi/session. Расположение уровней стека описано
+// в документации osi/mms.
+//
+// Пакет не входит в стабильный публичный API модуля (см. документацию go61850):
+// его API может меняться без сохранения совместимости и не проверяется make apidiff.
+package presentation
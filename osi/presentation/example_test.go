@@ -0,0 +1,37 @@
+package presentation_test
+
+import (
+	"fmt"
+
+	"github.com/slonegd/go61850/osi/presentation"
+)
+
+// MMS PDU в фазе данных передаётся в fully-encoded-data контекста MMS (id 3)
+func ExampleBuildUserData() {
+	concludeRequest := []byte{0x8b, 0x00}
+	ppdu := presentation.BuildUserData(concludeRequest, 3)
+	fmt.Printf("% x\n", ppdu)
+
+	parsed, err := presentation.ParsePresentationPDU(ppdu)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Printf("context=%d data=% x\n", parsed.PresentationContextId, parsed.Data)
+	// Output:
+	// 61 09 30 07 02 01 03 a0 02 8b 00
+	// context=3 data=8b 00
+}
+
+// CP-type переносит AARQ уровня ACSE в контексте ACSE (id 1)
+func ExampleBuildCPType() {
+	aarq := []byte{0x60, 0x00}
+	parsed, err := presentation.ParsePresentationPDU(presentation.BuildCPType(aarq))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Printf("%t data=% x\n", parsed.Type == presentation.CP, parsed.Data)
+	// Output:
+	// true data=60 00
+}
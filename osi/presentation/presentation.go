@@ -5,7 +5,7 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/slonegd/go61850/ber"
+	"github.com/slonegd/go61850/internal/ber"
 )
 
 // PSelector представляет селектор представления
@@ -43,11 +43,30 @@ func NewPresentation() *Presentation {
 
 // Константы для OID
 var (
-	asnIDAsACSE = []byte{0x52, 0x01, 0x00, 0x01}       // 2.2.1.0.1 (id-as-acse)
-	asnIDMMS    = []byte{0x28, 0xca, 0x22, 0x02, 0x01} // 1.0.9506.2.1 (mms-abstract-syntax-version1)
-	berID       = []byte{0x51, 0x01}                   // 2.1.1 (basic-encoding)
+	asnIDAsACSE = ber.OIDACSE.Bytes()              // 2.2.1.0.1 (id-as-acse)
+	asnIDMMS    = ber.OIDMMSAbstractSyntax.Bytes() // 1.0.9506.2.1 (mms-abstract-syntax-version1)
+	berID       = ber.OIDBER.Bytes()               // 2.1.1 (basic-encoding)
 )
 
+// abstractSyntaxes - встроенные abstract-syntax-name и поля PresentationPDU,
+// в которые записывается идентификатор их контекста
+var abstractSyntaxes = []struct {
+	oid          ber.ItuObjectIdentifier
+	setContextId func(pdu *PresentationPDU, contextId uint8)
+}{
+	{ber.OIDACSE, func(pdu *PresentationPDU, contextId uint8) { pdu.AcseContextId = contextId }},
+	{ber.OIDMMSAbstractSyntax, func(pdu *PresentationPDU, contextId uint8) { pdu.MmsContextId = contextId }},
+}
+
+// RegisterAbstractSyntax регистрирует abstract-syntax-name с дугами arcs под именем name
+// в реестре известных OID. Контексты зарегистрированного синтаксиса попадают
+// в PresentationPDU.Contexts, что позволяет добавить новый абстрактный синтаксис
+// (например, отображение IEC 61850-8-2) вне модуля. Повторная регистрация
+// переименовывает синтаксис.
+func RegisterAbstractSyntax(name string, arcs ...uint64) {
+	ber.RegisterOID(ber.NewOID(arcs...), name)
+}
+
 // encodeUserData кодирует user data согласно encodeUserData из C библиотеки (строки 59-97)
 func encodeUserData(presentation *Presentation, userData []byte, buf []byte, bufPos int, encode bool) int {
 	payloadLength := len(userData)
@@ -203,11 +222,53 @@ func createConnectPdu(presentation *Presentation, userData []byte) []byte {
 // BuildCPType создаёт CP-type (Presentation Protocol Data Unit).
 // Реализация основана на IsoPresentation_createConnectPdu из C библиотеки (строки 892-901).
 // Использует значения по умолчанию, соответствующие createConnectPdu.
+//
+// Deprecated: используйте mms.NewAssociateRequest, который собирает запрос
+// ассоциации на всех уровнях стека (поле Presentation).
 func BuildCPType(userData []byte) []byte {
 	presentation := NewPresentation()
 	return createConnectPdu(presentation, userData)
 }
 
+// BuildCPAType создаёт CPA-PPDU (Connect Presentation Accept) - ответ сервера на CP-type.
+// Реализация основана на IsoPresentation_createCpaMessage из C библиотеки:
+// normal-mode, responding-presentation-selector [0, 0, 0, 1], приняты оба контекста
+// (ACSE и MMS) с basic-encoding, user data - в контексте ACSE (1).
+// Из wireshark: 31 72 a0 03 80 01 01 a2 6b 83 04 00 00 00 01 a5 12 30 07 80 01 00 81 02 51 01
+// 30 07 80 01 00 81 02 51 01 61 4f 30 4d 02 01 01 a0 48 <ACSE AARE>
+func BuildCPAType(userData []byte) []byte {
+	presentation := NewPresentation()
+
+	// presentation-context-definition-result-list: acceptance (0) + basic-encoding для каждого контекста
+	result := make([]byte, 0, 9)
+	result = append(result, byte(ber.SequenceConstructed), 7,
+		byte(ber.ContextSpecific0Primitive), 1, 0,
+		byte(ber.ContextSpecific1Primitive), byte(len(berID)))
+	result = append(result, berID...)
+
+	var normalMode []byte
+	normalMode = append(normalMode, byte(ber.ContextSpecific3Primitive), byte(len(presentation.calledPresentationSelector.Value)))
+	normalMode = append(normalMode, presentation.calledPresentationSelector.Value...)
+	normalMode = append(normalMode, byte(ber.ContextSpecific5Constructed), byte(2*len(result)))
+	normalMode = append(normalMode, result...)
+	normalMode = append(normalMode, result...)
+	normalMode = append(normalMode, BuildUserData(userData, presentation.acseContextId)...)
+
+	content := []byte{byte(ber.ContextSpecific0Constructed), 3, byte(ber.ContextSpecific0Primitive), 1, ModeNormal}
+	content = appendTL(content, ber.ContextSpecific2Constructed, len(normalMode))
+	content = append(content, normalMode...)
+
+	buffer := appendTL(nil, ber.SetConstructed, len(content))
+	return append(buffer, content...)
+}
+
+// appendTL добавляет к buffer тег и длину BER
+func appendTL(buffer []byte, tag ber.Tag, length int) []byte {
+	header := make([]byte, 1+ber.DetermineLengthSize(uint32(length)))
+	ber.EncodeTL(tag, uint32(length), header, 0)
+	return append(buffer, header...)
+}
+
 // BuildUserData создаёт Presentation user-data для отправки данных после установления соединения.
 // Структура согласно ISO 8823:
 // - fully-encoded-data (Application 1, Constructed) = 0x61
@@ -257,6 +318,19 @@ func BuildUserData(userData []byte, contextID uint8) []byte {
 	return buffer[:bufPos]
 }
 
+// Значения mode-value в mode-selector CP-type/CPA-PPDU (ISO 8823)
+const (
+	ModeX410   uint8 = 0 // x410-1984-mode
+	ModeNormal uint8 = 1 // normal-mode
+)
+
+// ErrX410Mode возвращается, если партнёр предлагает режим x410-1984 вместо normal-mode:
+// в этом режиме нет контекстов представления, и ACSE/MMS поверх него не работают
+var ErrX410Mode = errors.New("presentation x410-1984 mode is not supported")
+
+// ErrNoUserData возвращается для CP/CPA без user-data, например без normal-mode-parameters
+var ErrNoUserData = errors.New("presentation user-data is missing")
+
 // PresentationPDUType представляет тип Presentation PDU
 type PresentationPDUType uint8
 
@@ -267,16 +341,18 @@ const (
 
 // PresentationPDU представляет Presentation Protocol Data Unit (ISO 8823)
 type PresentationPDU struct {
-	Type                           PresentationPDUType // Тип PDU (0x31 для CP/CPA)
-	ModeValue                      uint8               // Mode value (1 = normal-mode)
-	RespondingPresentationSelector []byte              // Responding Presentation Selector (в CPA)
-	CallingPresentationSelector    []byte              // Calling Presentation Selector (в CP)
-	CalledPresentationSelector     []byte              // Called Presentation Selector (в CP)
-	AcseContextId                  uint8               // ACSE context identifier
-	MmsContextId                   uint8               // MMS context identifier
-	PresentationContextId          uint8               // Presentation context identifier из user-data (например, 1 = id-as-acse)
-	PresentationDataValuesType     uint8               // Presentation data values type (0 = single-ASN1-type)
-	Data                           []byte              // Данные следующего уровня (ACSE)
+	Type                           PresentationPDUType     // Тип PDU (0x31 для CP/CPA)
+	ModeValue                      uint8                   // Mode value (1 = normal-mode)
+	RespondingPresentationSelector []byte                  // Responding Presentation Selector (в CPA)
+	CallingPresentationSelector    []byte                  // Calling Presentation Selector (в CP)
+	CalledPresentationSelector     []byte                  // Called Presentation Selector (в CP)
+	AcseContextId                  uint8                   // ACSE context identifier
+	MmsContextId                   uint8                   // MMS context identifier
+	PresentationContextId          uint8                   // Presentation context identifier из user-data (например, 1 = id-as-acse)
+	PresentationDataValuesType     uint8                   // Presentation data values type (0 = single-ASN1-type)
+	DefaultContextName             ber.ItuObjectIdentifier // abstract-syntax-name контекста по умолчанию (default-context-name)
+	Contexts                       map[uint8]string        // Имена зарегистрированных абстрактных синтаксисов контекстов CP-type (RegisterAbstractSyntax)
+	Data                           []byte                  // Данные следующего уровня (ACSE); nil, если user-data нет
 }
 
 // parseFullyEncodedData парсит fully-encoded-data согласно parseFullyEncodedData из C библиотеки (строки 191-279)
@@ -417,7 +493,6 @@ func parseNormalModeParameters(buffer []byte, bufPos, maxBufPos int) (newPos int
 	if endPos > maxBufPos {
 		endPos = maxBufPos
 	}
-	hasUserData := false
 
 	for bufPos < endPos && bufPos < maxBufPos {
 
@@ -459,9 +534,8 @@ func parseNormalModeParameters(buffer []byte, bufPos, maxBufPos int) (newPos int
 			} else {
 				bufPos += length
 			}
-		case 0xa4: // presentation-context-definition-list (Context-specific 4, Constructed) - в CP-type
-		case 0xa5: // context-definition-result-list (Context-specific 5, Constructed) - в CPA-PPDU
-			// Парсим список контекстов для определения acseContextId и mmsContextId
+		case 0xa4, 0xa5: // presentation-context-definition-list (в CP-type) и context-definition-result-list (в CPA-PPDU)
+			// Парсим список контекстов для определения их абстрактных синтаксисов
 			contextListEnd := bufPos + length
 			for bufPos < contextListEnd && bufPos < maxBufPos {
 				if buffer[bufPos] != 0x30 { // SEQUENCE
@@ -479,8 +553,7 @@ func parseNormalModeParameters(buffer []byte, bufPos, maxBufPos int) (newPos int
 
 				seqEnd := bufPos + seqLength
 				contextId := uint8(0)
-				isAcse := false
-				isMms := false
+				var abstractSyntax ber.ItuObjectIdentifier
 
 				for bufPos < seqEnd && bufPos < maxBufPos {
 					seqTag := buffer[bufPos]
@@ -506,20 +579,9 @@ func parseNormalModeParameters(buffer []byte, bufPos, maxBufPos int) (newPos int
 							bufPos += seqTagLength
 						}
 					case 0x06: // abstract-syntax-name
-						if seqTagLength == 4 && bufPos+4 <= maxBufPos {
-							// Проверяем на ACSE OID
-							if buffer[bufPos] == 0x52 && buffer[bufPos+1] == 0x01 &&
-								buffer[bufPos+2] == 0x00 && buffer[bufPos+3] == 0x01 {
-								isAcse = true
-							}
-						}
-						if seqTagLength == 5 && bufPos+5 <= maxBufPos {
-							// Проверяем на MMS OID
-							if buffer[bufPos] == 0x28 && buffer[bufPos+1] == 0xca &&
-								buffer[bufPos+2] == 0x22 && buffer[bufPos+3] == 0x02 &&
-								buffer[bufPos+4] == 0x01 {
-								isMms = true
-							}
+						if bufPos+seqTagLength > maxBufPos ||
+							ber.DecodeOID(buffer, bufPos, seqTagLength, &abstractSyntax) != nil {
+							abstractSyntax = ber.ItuObjectIdentifier{}
 						}
 						bufPos += seqTagLength
 					case 0x30: // transfer-syntax-name-list
@@ -529,11 +591,16 @@ func parseNormalModeParameters(buffer []byte, bufPos, maxBufPos int) (newPos int
 					}
 				}
 
-				if isAcse {
-					pdu.AcseContextId = contextId
-				}
-				if isMms {
-					pdu.MmsContextId = contextId
+				if name, ok := ber.LookupOID(abstractSyntax); ok {
+					if pdu.Contexts == nil {
+						pdu.Contexts = make(map[uint8]string)
+					}
+					pdu.Contexts[contextId] = name
+					for _, builtin := range abstractSyntaxes {
+						if abstractSyntax.Equal(builtin.oid) {
+							builtin.setContextId(pdu, contextId)
+						}
+					}
 				}
 			}
 			bufPos = contextListEnd
@@ -545,8 +612,19 @@ func parseNormalModeParameters(buffer []byte, bufPos, maxBufPos int) (newPos int
 			pdu.PresentationContextId = contextId
 			pdu.PresentationDataValuesType = dataValuesType
 			pdu.Data = data
-			hasUserData = true
 			bufPos = newPos
+		case 0x40: // user-data (Application 0) - simply-encoded-data в контексте по умолчанию
+			if bufPos+length > maxBufPos {
+				return -1, nil, errors.New("simply-encoded-data: buffer overflow")
+			}
+			pdu.Data = make([]byte, length)
+			copy(pdu.Data, buffer[bufPos:bufPos+length])
+			bufPos += length
+		case 0xa6: // default-context-name (Context-specific 6, Constructed)
+			if err := parseDefaultContextName(buffer[bufPos:min(bufPos+length, maxBufPos)], pdu); err != nil {
+				return -1, nil, err
+			}
+			bufPos += length
 		case 0x00: // indefinite length end tag
 			// ignore
 		default:
@@ -554,16 +632,36 @@ func parseNormalModeParameters(buffer []byte, bufPos, maxBufPos int) (newPos int
 		}
 	}
 
-	if !hasUserData {
-		return -1, nil, errors.New("user-data is missing")
-	}
-
 	return bufPos, pdu, nil
 }
 
+// parseDefaultContextName извлекает abstract-syntax-name [0] из default-context-name.
+// transfer-syntax-name [1] не проверяется: поддерживается только BER.
+func parseDefaultContextName(buffer []byte, pdu *PresentationPDU) error {
+	for bufPos := 0; bufPos < len(buffer); {
+		tag := buffer[bufPos]
+		newPos, length, err := ber.DecodeLength(buffer, bufPos+1, len(buffer))
+		if err != nil {
+			return fmt.Errorf("failed to decode default-context-name: %w", err)
+		}
+		if newPos+length > len(buffer) {
+			return errors.New("default-context-name: buffer overflow")
+		}
+		if tag == 0x80 { // abstract-syntax-name (Context-specific 0, OBJECT IDENTIFIER)
+			if err := ber.DecodeOID(buffer, newPos, length, &pdu.DefaultContextName); err != nil {
+				return fmt.Errorf("invalid default-context-name: %w", err)
+			}
+		}
+		bufPos = newPos + length
+	}
+	return nil
+}
+
 // ParsePresentationPDU парсит Presentation PDU из байтового буфера
 // Реализация основана на IsoPresentation_parseAcceptMessage из C библиотеки (строки 545-612)
-// Может парсить как CP/CPA сообщения (tag 0x31), так и user-data сообщения (tag 0x61)
+// Может парсить как CP/CPA сообщения (tag 0x31), так и user-data сообщения (tag 0x61).
+// Для режима x410-1984 возвращает ErrX410Mode. CP/CPA без normal-mode-parameters
+// или без user-data разбирается без ошибки, Data в этом случае nil.
 func ParsePresentationPDU(data []byte) (*PresentationPDU, error) {
 	if len(data) < 1 {
 		return nil, errors.New("Presentation PDU too short: need at least 1 byte")
@@ -586,6 +684,8 @@ func ParsePresentationPDU(data []byte) (*PresentationPDU, error) {
 
 	bufPos := 1
 	maxBufPos := len(data)
+	modeSelected := false
+	x410Parameters := false
 
 	// Декодируем длину CP-type (пропускаем, так как парсим по тегам)
 	newPos, _, err := ber.DecodeLength(data, bufPos, maxBufPos)
@@ -622,6 +722,7 @@ func ParsePresentationPDU(data []byte) (*PresentationPDU, error) {
 				newPos, modeLength, err := ber.DecodeLength(data, bufPos, maxBufPos)
 				if err == nil && modeLength > 0 && newPos < maxBufPos {
 					pdu.ModeValue = data[newPos]
+					modeSelected = true
 					bufPos = newPos + modeLength
 				} else {
 					bufPos += length
@@ -644,8 +745,13 @@ func ParsePresentationPDU(data []byte) (*PresentationPDU, error) {
 			pdu.MmsContextId = parsedPdu.MmsContextId
 			pdu.PresentationContextId = parsedPdu.PresentationContextId
 			pdu.PresentationDataValuesType = parsedPdu.PresentationDataValuesType
+			pdu.DefaultContextName = parsedPdu.DefaultContextName
+			pdu.Contexts = parsedPdu.Contexts
 			pdu.Data = parsedPdu.Data
 			bufPos = newPos
+		case 0xa1: // x410-mode-parameters (Context-specific 1, Constructed)
+			x410Parameters = true
+			bufPos += length
 		case 0x00: // indefinite length end tag
 			// ignore
 		default:
@@ -653,9 +759,23 @@ func ParsePresentationPDU(data []byte) (*PresentationPDU, error) {
 		}
 	}
 
+	if x410Parameters || (modeSelected && pdu.ModeValue == ModeX410) {
+		return nil, ErrX410Mode
+	}
+
 	return pdu, nil
 }
 
+// ContextName возвращает зарегистрированное имя абстрактного синтаксиса user-data:
+// по PresentationContextId из Contexts или по DefaultContextName для simply-encoded-data
+func (p *PresentationPDU) ContextName() (string, bool) {
+	if p.PresentationContextId != 0 {
+		name, ok := p.Contexts[p.PresentationContextId]
+		return name, ok
+	}
+	return ber.LookupOID(p.DefaultContextName)
+}
+
 // String реализует интерфейс fmt.Stringer для PresentationPDU
 func (p *PresentationPDU) String() string {
 	var builder strings.Builder
@@ -735,6 +855,10 @@ func (p *PresentationPDU) String() string {
 		fmt.Fprintf(&builder, ", PresentationDataValuesType: %d", p.PresentationDataValuesType)
 	}
 
+	if len(p.DefaultContextName.Arc) > 0 {
+		fmt.Fprintf(&builder, ", DefaultContextName: %s", p.DefaultContextName)
+	}
+
 	fmt.Fprintf(&builder, ", DataLength: %d}", len(p.Data))
 
 	return builder.String()
@@ -1,8 +1,10 @@
 package presentation
 
 import (
+	"errors"
 	"testing"
 
+	"github.com/slonegd/go61850/internal/ber"
 	"github.com/slonegd/go61850/osi/session"
 )
 
@@ -135,3 +137,75 @@ func TestParsePresentationPDU_AcceptFromComment(t *testing.T) {
 	}
 }
 
+
+func TestParsePresentationPDU_Modes(t *testing.T) {
+	// Режим x410-1984: mode-value 0
+	x410 := []byte{0x31, 0x09, 0xa0, 0x03, 0x80, 0x01, 0x00, 0xa1, 0x02, 0x30, 0x00}
+	if _, err := ParsePresentationPDU(x410); !errors.Is(err, ErrX410Mode) {
+		t.Errorf("x410 mode: error = %v, want ErrX410Mode", err)
+	}
+
+	// CPA без normal-mode-parameters разбирается без ошибки
+	noParameters := []byte{0x31, 0x05, 0xa0, 0x03, 0x80, 0x01, 0x01}
+	pdu, err := ParsePresentationPDU(noParameters)
+	if err != nil {
+		t.Fatalf("without normal-mode-parameters: %v", err)
+	}
+	if pdu.ModeValue != ModeNormal || pdu.Data != nil {
+		t.Errorf("without normal-mode-parameters: ModeValue = %d, Data = %x", pdu.ModeValue, pdu.Data)
+	}
+
+	// Контекст по умолчанию id-as-acse и simply-encoded-data
+	defaultContext := []byte{
+		0x31, 0x17, 0xa0, 0x03, 0x80, 0x01, 0x01,
+		0xa2, 0x10,
+		0xa6, 0x0a, 0x80, 0x04, 0x52, 0x01, 0x00, 0x01, 0x81, 0x02, 0x51, 0x01, // default-context-name
+		0x40, 0x02, 0x61, 0x00, // simply-encoded-data
+	}
+	pdu, err = ParsePresentationPDU(defaultContext)
+	if err != nil {
+		t.Fatalf("default context: %v", err)
+	}
+	if got := pdu.DefaultContextName.String(); got != "2.2.1.0.1" {
+		t.Errorf("DefaultContextName = %s, want 2.2.1.0.1", got)
+	}
+	if len(pdu.Data) != 2 || pdu.Data[0] != 0x61 || pdu.PresentationContextId != 0 {
+		t.Errorf("default context: Data = %x, PresentationContextId = %d", pdu.Data, pdu.PresentationContextId)
+	}
+}
+
+func TestRegisterAbstractSyntax(t *testing.T) {
+	// CP-type с контекстами id-as-acse (1) и неизвестного синтаксиса (5)
+	context := func(id byte, oid ber.ItuObjectIdentifier) []byte {
+		name := oid.Bytes()
+		definition := append([]byte{0x02, 0x01, id, 0x06, byte(len(name))}, name...)
+		definition = append(definition, 0x30, 0x04, 0x06, 0x02, 0x51, 0x01)
+		return append([]byte{0x30, byte(len(definition))}, definition...)
+	}
+	syntax := []uint64{1, 0, 61850, 8, 2, 1}
+	list := append(context(1, ber.OIDACSE), context(5, ber.NewOID(syntax...))...)
+	parameters := append([]byte{0xa2, byte(len(list) + 2), 0xa4, byte(len(list))}, list...)
+	body := append([]byte{0xa0, 0x03, 0x80, 0x01, 0x01}, parameters...)
+	cp := append([]byte{0x31, byte(len(body))}, body...)
+
+	pdu, err := ParsePresentationPDU(cp)
+	if err != nil {
+		t.Fatalf("ParsePresentationPDU: %v", err)
+	}
+	if pdu.AcseContextId != 1 || pdu.Contexts[1] != "id-as-acse" {
+		t.Errorf("AcseContextId = %d, Contexts = %v", pdu.AcseContextId, pdu.Contexts)
+	}
+	if _, ok := pdu.Contexts[5]; ok {
+		t.Errorf("unregistered context 5 in Contexts = %v", pdu.Contexts)
+	}
+
+	RegisterAbstractSyntax("iec61850-8-2", syntax...)
+	pdu, err = ParsePresentationPDU(cp)
+	if err != nil {
+		t.Fatalf("ParsePresentationPDU: %v", err)
+	}
+	pdu.PresentationContextId = 5
+	if name, ok := pdu.ContextName(); !ok || name != "iec61850-8-2" {
+		t.Errorf("ContextName() = %q, %v, want iec61850-8-2", name, ok)
+	}
+}
@@ -0,0 +1,132 @@
+package session
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Parameter - PI-единица параметров SPDU (ISO 8327-1, 8.2)
+type Parameter struct {
+	PI    uint8
+	Value []byte
+}
+
+// Коды PI, используемые в фазе данных и в CONNECT
+const (
+	PITokenItem     uint8 = 16 // Token Item: передаваемые токены (GT, PT)
+	PIEnclosureItem uint8 = 25 // Enclosure Item: начало/конец сегментированного SSDU (DT)
+	PIDataOverflow  uint8 = 60 // Data Overflow: user data CONNECT не поместились в 10240 байт
+)
+
+// SPDU категории 0 (ISO 8327-1, 6.3.7). Передаются только в одном TSDU со следующим
+// за ними SPDU категории 2; SI у GT и DT совпадают и различаются позицией в TSDU.
+const (
+	spduGiveTokens   = 1 // GT
+	spduPleaseTokens = 2 // PT
+)
+
+// decodeLI декодирует поле LI: один байт или 0xff и два байта для длины от 255
+func decodeLI(data []byte, offset int) (length, newOffset int, err error) {
+	if offset >= len(data) {
+		return 0, 0, errors.New("LI missing")
+	}
+	if data[offset] != 0xff {
+		return int(data[offset]), offset + 1, nil
+	}
+	if offset+3 > len(data) {
+		return 0, 0, errors.New("extended LI truncated")
+	}
+	return int(data[offset+1])<<8 | int(data[offset+2]), offset + 3, nil
+}
+
+// appendLI кодирует поле LI
+func appendLI(buf []byte, length int) []byte {
+	if length < 0xff {
+		return append(buf, byte(length))
+	}
+	return append(buf, 0xff, byte(length>>8), byte(length))
+}
+
+// parseParameters разбирает последовательность PI-единиц
+func parseParameters(data []byte) ([]Parameter, error) {
+	var parameters []Parameter
+	for offset := 0; offset < len(data); {
+		pi := data[offset]
+		length, valueStart, err := decodeLI(data, offset+1)
+		if err != nil {
+			return nil, fmt.Errorf("PI %d: %w", pi, err)
+		}
+		if valueStart+length > len(data) {
+			return nil, fmt.Errorf("PI %d: value exceeds SPDU", pi)
+		}
+		parameters = append(parameters, Parameter{PI: pi, Value: data[valueStart : valueStart+length]})
+		offset = valueStart + length
+	}
+	return parameters, nil
+}
+
+// appendSPDU кодирует SPDU с типом spduType и PI-единицами parameters
+func appendSPDU(buf []byte, spduType uint8, parameters []Parameter) []byte {
+	length := 0
+	for _, p := range parameters {
+		length += 1 + len(appendLI(nil, len(p.Value))) + len(p.Value)
+	}
+	buf = append(buf, spduType)
+	buf = appendLI(buf, length)
+	for _, p := range parameters {
+		buf = append(buf, p.PI)
+		buf = appendLI(buf, len(p.Value))
+		buf = append(buf, p.Value...)
+	}
+	return buf
+}
+
+// BuildConcatenatedDataSPDU кодирует TSDU фазы данных по правилам базовой конкатенации:
+// GT с параметрами tokenParameters, DT с параметрами dataParameters, затем user information.
+// BuildConcatenatedDataSPDU(nil, nil, data) совпадает с BuildDataTransferWithTokens(data).
+func BuildConcatenatedDataSPDU(tokenParameters, dataParameters []Parameter, userData []byte) []byte {
+	buf := make([]byte, 0, 4+len(userData))
+	buf = appendSPDU(buf, spduGiveTokens, tokenParameters)
+	buf = appendSPDU(buf, uint8(SessionSPDUTypeData), dataParameters)
+	return append(buf, userData...)
+}
+
+// parseConcatenated разбирает TSDU фазы данных: SPDU категории 0 (GT или PT) и следующий
+// за ним DT. LI каждого SPDU покрывает только его PI-единицы, user information DT
+// занимает остаток TSDU. Если за SPDU категории 0 следует не DT, остаток TSDU
+// считается user information DT без заголовка - так передают данные некоторые стеки.
+func parseConcatenated(data []byte) (*SessionSPDU, error) {
+	length, offset, err := decodeLI(data, 1)
+	if err != nil {
+		return nil, fmt.Errorf("invalid category 0 SPDU: %w", err)
+	}
+	if offset+length > len(data) {
+		return nil, fmt.Errorf("Session SPDU incomplete: need %d bytes, got %d", offset+length, len(data))
+	}
+	tokenParameters, err := parseParameters(data[offset : offset+length])
+	if err != nil {
+		return nil, fmt.Errorf("invalid category 0 SPDU: %w", err)
+	}
+	offset += length
+
+	spdu := &SessionSPDU{Type: SessionSPDUTypeData, TokenParameters: tokenParameters}
+	if offset < len(data) && data[offset] == byte(SessionSPDUTypeData) {
+		length, valueStart, err := decodeLI(data, offset+1)
+		if err != nil {
+			return nil, fmt.Errorf("invalid DT SPDU: %w", err)
+		}
+		if valueStart+length > len(data) {
+			return nil, fmt.Errorf("DT SPDU incomplete: need %d bytes, got %d", valueStart+length, len(data))
+		}
+		spdu.Parameters, err = parseParameters(data[valueStart : valueStart+length])
+		if err != nil {
+			return nil, fmt.Errorf("invalid DT SPDU: %w", err)
+		}
+		spdu.Length = uint8(min(length, 0xff))
+		offset = valueStart + length
+	}
+
+	spdu.Data = make([]byte, len(data)-offset)
+	copy(spdu.Data, data[offset:])
+	return spdu, nil
+}
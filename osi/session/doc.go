@@ -0,0 +1,14 @@
+// Package session реализует необходимое MMS подмножество протокола сеансового
+// уровня ISO 8327-1 (ядро и дуплексный функциональный блок).
+//
+// Сеанс открывается обменом CONNECT (mms.NewAssociateRequest, клиент) и ACCEPT
+// (BuildAcceptSPDU, сервер), в пользовательских данных которых передаются
+// CP-type и CPA-PPDU пакета osi/presentation. В фазе данных каждый TSDU
+// содержит Give Tokens и DATA TRANSFER с PPDU: BuildDataTransferWithTokens
+// формирует его, ParseSessionSPDU разбирает любой SPDU и возвращает данные
+// уровня представления в SessionSPDU.Data. FINISH и DISCONNECT завершают сеанс.
+// Расположение уровней стека описано в документации osi/mms.
+//
+// Пакет не входит в стабильный публичный API модуля (см. документацию go61850):
+// его API может меняться без сохранения совместимости и не проверяется make apidiff.
+package session
@@ -0,0 +1,40 @@
+package session_test
+
+import (
+	"fmt"
+
+	"github.com/slonegd/go61850/osi/session"
+)
+
+// TSDU фазы данных: Give Tokens, DATA TRANSFER и PPDU уровня представления
+func ExampleBuildDataTransferWithTokens() {
+	ppdu := []byte{0x61, 0x03, 0x30, 0x01, 0x00}
+	tsdu := session.BuildDataTransferWithTokens(ppdu)
+	fmt.Printf("% x\n", tsdu)
+
+	spdu, err := session.ParseSessionSPDU(tsdu)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(spdu.Type == session.SessionSPDUTypeData)
+	fmt.Printf("% x\n", spdu.Data)
+	// Output:
+	// 01 00 01 00 61 03 30 01 00
+	// true
+	// 61 03 30 01 00
+}
+
+// CONNECT с пользовательскими данными (CP-type) открывает сеанс
+func ExampleBuildConnectSPDU() {
+	spdu, err := session.ParseSessionSPDU(session.BuildConnectSPDU([]byte{0x31, 0x00}))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(spdu.Type == session.SessionSPDUTypeConnect)
+	fmt.Printf("called=% x calling=% x data=% x\n", spdu.CalledSessionSelector, spdu.CallingSessionSelector, spdu.Data)
+	// Output:
+	// true
+	// called=00 01 calling=00 01 data=31 00
+}
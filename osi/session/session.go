@@ -127,6 +127,9 @@ func encodeSessionUserData(buf []byte, offset int, payloadLength int) int {
 // BuildConnectSPDU создаёт CONNECT SPDU (Session Protocol Data Unit).
 // Реализация основана на IsoSession_createConnectSpdu из C библиотеки (строки 335-367).
 // Использует значения по умолчанию, соответствующие IsoSession_init.
+//
+// Deprecated: используйте mms.NewAssociateRequest, который собирает запрос
+// ассоциации на всех уровнях стека (поле Session).
 func BuildConnectSPDU(userData []byte) []byte {
 	session := NewSession()
 	return buildConnectSPDUWithSession(session, userData)
@@ -187,6 +190,33 @@ func buildConnectSPDUWithSession(session *Session, userData []byte) []byte {
 	return buf[:offset]
 }
 
+// BuildAcceptSPDU создаёт ACCEPT SPDU - ответ на CONNECT (сторона сервера).
+// Реализация основана на IsoSession_createAcceptSpdu из C библиотеки:
+// Connect Accept Item, Session Requirement, Responding Session Selector
+// и Session User Data с параметрами по умолчанию, как в NewSession.
+// Из wireshark: 0e 86 05 06 13 01 00 16 01 02 14 02 00 02 34 02 00 01 c1 74 <Presentation CPA>
+func BuildAcceptSPDU(userData []byte) []byte {
+	session := NewSession()
+	userDataHeaderLen := 2
+	if len(userData) > 0xFF {
+		userDataHeaderLen = 4
+	}
+	buf := make([]byte, 2+8+4+2+len(session.calledSessionSelector.Value)+userDataHeaderLen+len(userData))
+
+	// SPDU Type: ACCEPT (AC) = 14
+	buf[0] = byte(SessionSPDUTypeAccept)
+	offset := 2
+	offset = encodeConnectAcceptItem(buf, offset, session.protocolOptions)
+	offset = encodeSessionRequirement(session, buf, offset)
+	// Responding Session Selector кодируется тем же PI, что и Called Session Selector
+	offset = encodeCalledSessionSelector(session, buf, offset)
+	offset = encodeSessionUserData(buf, offset, len(userData))
+	offset += copy(buf[offset:], userData)
+
+	buf[1] = byte(offset - 2)
+	return buf[:offset]
+}
+
 // BuildGiveTokensSPDU создаёт Give tokens PDU (GT SPDU) для передачи токенов.
 // Структура согласно ISO 8327-1:
 // - SPDU Type: 1 (GT)
@@ -230,15 +260,7 @@ func BuildDataTransferSPDU(userData []byte) []byte {
 // Give tokens PDU + DT SPDU + Presentation PDU
 // Это соответствует структуре из wireshark: 01 00 01 00 <Presentation PDU>
 func BuildDataTransferWithTokens(presentationData []byte) []byte {
-	gtSPDU := BuildGiveTokensSPDU()
-	dtSPDU := BuildDataTransferSPDU(presentationData)
-	
-	// Объединяем: GT SPDU + DT SPDU (который уже включает Presentation PDU)
-	buf := make([]byte, len(gtSPDU)+len(dtSPDU))
-	copy(buf, gtSPDU)
-	copy(buf[len(gtSPDU):], dtSPDU)
-	
-	return buf
+	return BuildConcatenatedDataSPDU(nil, nil, presentationData)
 }
 
 // SessionSPDUType представляет тип Session SPDU
@@ -262,82 +284,27 @@ type SessionSPDU struct {
 	SessionRequirement     uint16          // Session Requirement
 	CalledSessionSelector  []byte          // Called Session Selector
 	CallingSessionSelector []byte          // Calling Session Selector (может отсутствовать в ACCEPT)
+	DataOverflow           bool            // Data Overflow: user data CONNECT продолжаются в следующих SPDU
+	TokenParameters        []Parameter     // PI-единицы GT/PT, предшествующего DT
+	Parameters             []Parameter     // PI-единицы DT (например, Enclosure Item)
 	Data                   []byte          // Данные следующего уровня (Presentation)
 }
 
-// ParseSessionSPDU парсит Session SPDU из байтового буфера
-// Может содержать несколько SPDU подряд (например, GT SPDU + DT SPDU)
-// Парсит последний DATA SPDU с данными
+// ParseSessionSPDU парсит Session SPDU из байтового буфера.
+// В фазе данных TSDU содержит несколько SPDU подряд (GT или PT, затем DT):
+// PI-единицы GT/PT возвращаются в TokenParameters, PI-единицы DT - в Parameters,
+// user information DT - в Data.
 func ParseSessionSPDU(data []byte) (*SessionSPDU, error) {
 	if len(data) < 2 {
 		return nil, errors.New("Session SPDU too short: need at least 2 bytes")
 	}
 
-	// Ищем последний DATA SPDU в payload
-	// Может быть несколько SPDU подряд: GT (01 00) + DT (01 00) + данные
-	offset := 0
-	lastDataSPDUOffset := -1
-	
-	for offset < len(data) {
-		if offset+2 > len(data) {
-			break
-		}
-		
-		spduType := SessionSPDUType(data[offset])
-		spduLength := data[offset+1]
-		
-		// Если это DATA SPDU, запоминаем его позицию
-		if spduType == SessionSPDUTypeData {
-			lastDataSPDUOffset = offset
-		}
-		
-		// Переходим к следующему SPDU
-		// Для DATA SPDU с длиной 0, данные идут после SPDU, поэтому ищем следующий SPDU
-		if spduLength == 0 {
-			// Если длина 0, следующий SPDU может идти сразу после (2 байта)
-			offset += 2
-		} else {
-			// Если длина не 0, следующий SPDU идет после Type + Length + Length bytes
-			offset += 2 + int(spduLength)
-		}
-		
-		// Если после DATA SPDU с длиной 0 больше нет SPDU, выходим
-		if spduType == SessionSPDUTypeData && spduLength == 0 && offset >= len(data) {
-			break
-		}
-	}
-	
-	// Если нашли DATA SPDU, парсим его
-	if lastDataSPDUOffset >= 0 {
-		spdu := &SessionSPDU{
-			Type:   SessionSPDUTypeData,
-			Length: data[lastDataSPDUOffset+1],
-		}
-		
-		// Для DATA SPDU с длиной 0, данные идут сразу после SPDU
-		if spdu.Length == 0 {
-			dataStart := lastDataSPDUOffset + 2
-			if dataStart < len(data) {
-				spdu.Data = make([]byte, len(data)-dataStart)
-				copy(spdu.Data, data[dataStart:])
-			} else {
-				spdu.Data = []byte{}
-			}
-		} else {
-			// Если длина не 0, данные включены в длину
-			dataStart := lastDataSPDUOffset + 2
-			dataLength := int(spdu.Length)
-			if dataStart+dataLength <= len(data) {
-				spdu.Data = make([]byte, dataLength)
-				copy(spdu.Data, data[dataStart:dataStart+dataLength])
-			} else {
-				spdu.Data = []byte{}
-			}
-		}
-		return spdu, nil
+	// Фаза данных: SPDU категории 0 (GT/PT) и следующий за ним DT в одном TSDU
+	if data[0] == spduGiveTokens || data[0] == spduPleaseTokens {
+		return parseConcatenated(data)
 	}
-	
-	// Если DATA SPDU не найден, парсим первый SPDU как обычно
+
+	// Остальные SPDU разбираются по PI-единицам
 	spdu := &SessionSPDU{
 		Type:   SessionSPDUType(data[0]),
 		Length: data[1],
@@ -354,7 +321,7 @@ func ParseSessionSPDU(data []byte) (*SessionSPDU, error) {
 	}
 
 	// Для других типов SPDU парсим параметры
-	offset = 2 // Начинаем после Type и Length
+	offset := 2 // Начинаем после Type и Length
 	userDataStart := -1
 	userDataLength := 0
 
@@ -427,6 +394,12 @@ parseLoop:
 				offset += paramLength
 			}
 
+		case 60: // Data Overflow
+			if paramLength == 1 && offset < len(data) {
+				spdu.DataOverflow = data[offset]&1 != 0
+			}
+			offset += paramLength
+
 		case 52: // Called Session Selector
 			if paramLength > 0 && paramLength <= 16 && offset+paramLength <= len(data) {
 				spdu.CalledSessionSelector = make([]byte, paramLength)
@@ -536,6 +509,14 @@ func (s *SessionSPDU) String() string {
 		builder.WriteString(formatSelector(s.CalledSessionSelector))
 	}
 
+	if s.DataOverflow {
+		builder.WriteString(", DataOverflow")
+	}
+
+	if len(s.TokenParameters) > 0 || len(s.Parameters) > 0 {
+		fmt.Fprintf(&builder, ", TokenParameters: %d, Parameters: %d", len(s.TokenParameters), len(s.Parameters))
+	}
+
 	fmt.Fprintf(&builder, ", DataLength: %d}", len(s.Data))
 
 	return builder.String()
@@ -103,3 +103,58 @@ func TestParseSessionSPDU_AcceptFromComment(t *testing.T) {
 		}
 	}
 }
+
+func TestParseSessionSPDU_Concatenation(t *testing.T) {
+	userData := []byte{0x61, 0x03, 0x01, 0x00, 0x01} // содержит байты, похожие на GT/DT
+
+	tests := []struct {
+		name            string
+		data            []byte
+		tokenParameters int
+		parameters      int
+	}{
+		{"GT+DT", BuildDataTransferWithTokens(userData), 0, 0},
+		{"GT with Token Item + DT with Enclosure Item",
+			BuildConcatenatedDataSPDU(
+				[]Parameter{{PI: PITokenItem, Value: []byte{0x01}}},
+				[]Parameter{{PI: PIEnclosureItem, Value: []byte{0x02}}},
+				userData), 1, 1},
+		{"PT+DT", append([]byte{0x02, 0x00, 0x01, 0x00}, userData...), 0, 0},
+		{"DT without category 0 header", append([]byte{0x01, 0x00}, userData...), 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spdu, err := ParseSessionSPDU(tt.data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if spdu.Type != SessionSPDUTypeData {
+				t.Errorf("Type = %d, want DATA", spdu.Type)
+			}
+			if string(spdu.Data) != string(userData) {
+				t.Errorf("Data = % x, want % x", spdu.Data, userData)
+			}
+			if len(spdu.TokenParameters) != tt.tokenParameters || len(spdu.Parameters) != tt.parameters {
+				t.Errorf("TokenParameters = %v, Parameters = %v", spdu.TokenParameters, spdu.Parameters)
+			}
+		})
+	}
+
+	if got := BuildDataTransferWithTokens(userData)[:4]; string(got) != "\x01\x00\x01\x00" {
+		t.Errorf("BuildDataTransferWithTokens header = % x", got)
+	}
+	if _, err := ParseSessionSPDU([]byte{0x01, 0x05, 0x10}); err == nil {
+		t.Error("truncated GT: expected error")
+	}
+}
+
+func TestParseSessionSPDU_DataOverflow(t *testing.T) {
+	connect := []byte{0x0d, 0x06, 0x3c, 0x01, 0x01, 0xc1, 0x01, 0x31}
+	spdu, err := ParseSessionSPDU(connect)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !spdu.DataOverflow || len(spdu.Data) != 1 {
+		t.Errorf("DataOverflow = %v, Data = % x", spdu.DataOverflow, spdu.Data)
+	}
+}
@@ -0,0 +1,217 @@
+package go61850
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"testing"
+
+	"github.com/slonegd/go61850/mmstest"
+	"github.com/slonegd/go61850/model"
+	"github.com/slonegd/go61850/osi/acse"
+	"github.com/slonegd/go61850/osi/cotp"
+	"github.com/slonegd/go61850/osi/mms"
+	"github.com/slonegd/go61850/osi/mms/variant"
+	"github.com/slonegd/go61850/osi/presentation"
+	"github.com/slonegd/go61850/osi/session"
+	"github.com/slonegd/go61850/server"
+	"github.com/slonegd/go61850/sv"
+)
+
+// maxParserInput - максимальный размер произвольного входа парсеров
+const maxParserInput = 64 * 1024
+
+// parsers - все разборщики входящих данных; ошибка допустима, паника - нет
+var parsers = map[string]func([]byte){
+	"cotp.ParseTPKT":                      func(b []byte) { cotp.ParseTPKT(b) },
+	"cotp.ParseCOTP":                      func(b []byte) { cotp.ParseCOTP(b) },
+	"cotp.Connection":                     parseCotpConnection,
+	"session.SPDU":                        func(b []byte) { session.ParseSessionSPDU(b) },
+	"presentation.PDU":                    func(b []byte) { presentation.ParsePresentationPDU(b) },
+	"acse.ParseACSEPDU":                   func(b []byte) { acse.ParseACSEPDU(b) },
+	"acse.ParseMessage":                   func(b []byte) { acse.ParseMessage(acse.NewConnection(), b) },
+	"mms.AssociateRequest":                func(b []byte) { mms.ParseAssociateRequest(b) },
+	"mms.InitiateRequest":                 func(b []byte) { mms.ParseInitiateRequest(b) },
+	"mms.InitiateResponse":                func(b []byte) { mms.ParseInitiateResponse(b) },
+	"mms.PDU":                             func(b []byte) { mms.ParsePDU(b) },
+	"mms.ConfirmedResponse":               func(b []byte) { mms.ParseConfirmedResponse(b) },
+	"mms.ConfirmedError":                  func(b []byte) { mms.ParseConfirmedError(b) },
+	"mms.Reject":                          func(b []byte) { mms.ParseReject(b) },
+	"mms.NullResponse":                    func(b []byte) { mms.ParseNullResponse(b, mms.ServiceFileClose) },
+	"mms.IdentifyResponse":                func(b []byte) { mms.ParseIdentifyResponse(b) },
+	"mms.ReadRequest":                     func(b []byte) { mms.ParseReadRequest(b) },
+	"mms.ReadResponse":                    func(b []byte) { mms.ParseReadResponse(b) },
+	"mms.WriteRequest":                    func(b []byte) { mms.ParseWriteRequest(b) },
+	"mms.WriteResponse":                   func(b []byte) { mms.ParseWriteResponse(b) },
+	"mms.GetNameListRequest":              func(b []byte) { mms.ParseGetNameListRequest(b) },
+	"mms.GetNameListResponse":             func(b []byte) { mms.ParseGetNameListResponse(b) },
+	"mms.DomainAttributes":                func(b []byte) { mms.ParseGetDomainAttributesResponse(b) },
+	"mms.VariableAccessAttributesRequest": func(b []byte) { mms.ParseGetVariableAccessAttributesRequest(b) },
+	"mms.TypeSpecification":               func(b []byte) { mms.ParseGetVariableAccessAttributesResponse(b) },
+	"mms.NamedVariableListAttributes":     func(b []byte) { mms.ParseGetNamedVariableListAttributesResponse(b) },
+	"mms.ObtainFileRequest":               func(b []byte) { mms.ParseObtainFileRequest(b) },
+	"mms.FileOpenRequest":                 func(b []byte) { mms.ParseFileOpenRequest(b) },
+	"mms.FileOpenResponse":                func(b []byte) { mms.ParseFileOpenResponse(b) },
+	"mms.FrsmID":                          func(b []byte) { mms.ParseFrsmID(b) },
+	"mms.FileReadResponse":                func(b []byte) { mms.ParseFileReadResponse(b) },
+	"mms.FileDirectoryResponse":           func(b []byte) { mms.ParseFileDirectoryResponse(b) },
+	"mms.CancelRequest":                   func(b []byte) { mms.ParseCancelRequest(b) },
+	"mms.CancelResult":                    func(b []byte) { mms.ParseCancelResult(b) },
+	"mms.ResponseInvokeID":                func(b []byte) { mms.ResponseInvokeID(b) },
+	"mms.ConfirmedRequest":                func(b []byte) { mms.ParseConfirmedRequest(b) },
+	"mms.RequestInvokeID":                 func(b []byte) { mms.RequestInvokeID(b) },
+	"mms.InformationReport": func(b []byte) {
+		if pdu, err := mms.ParseInformationReport(b); err == nil {
+			ParseReport(pdu, QuirkReportSegmentationUnset|QuirkReportEntryIDAlwaysPresent)
+		}
+	},
+	"server.Read":                     func(b []byte) { parserServer.HandleReadRequest(b) },
+	"server.Write":                    func(b []byte) { parserServer.HandleWriteRequest(b) },
+	"server.GetNameList":              func(b []byte) { parserServer.HandleGetNameListRequest(b) },
+	"server.VariableAccessAttributes": func(b []byte) { parserServer.HandleGetVariableAccessAttributesRequest(b) },
+	"model.TimeOfEntry":               func(b []byte) { model.ParseTimeOfEntry(b) },
+	"model.EntryID":                   func(b []byte) { model.ParseEntryID(string(b)) },
+	"sv.ParseFrame":                   func(b []byte) { sv.ParseFrame(b) },
+}
+
+// parserServer - сервер для разбора запросов
+var parserServer = server.New(&model.Model{
+	Name: "simpleIO",
+	LogicalDevices: []*model.LogicalDevice{{
+		Name: "simpleIOGenericIO",
+		LogicalNodes: []*model.LogicalNode{{
+			Name: "GGIO1",
+			DataObjects: []*model.DataObject{{Name: "AnIn1", Children: []model.DataNode{
+				&model.DataAttribute{Name: "f", FC: mms.FCMX, Value: variant.NewFloat32Variant(1)},
+			}}},
+		}},
+	}},
+})
+
+// parseCotpConnection читает и разбирает входящий поток как COTP соединение
+func parseCotpConnection(b []byte) {
+	conn := cotp.NewConnection(nopCloser{bytes.NewReader(b)})
+	for range 16 {
+		state, err := conn.ReadToTpktBuffer(context.Background())
+		if err != nil || state != cotp.TpktPacketComplete {
+			return
+		}
+		if _, err := conn.ParseIncomingMessage(); err != nil {
+			return
+		}
+	}
+}
+
+// nopCloser добавляет Write и Close к io.Reader
+type nopCloser struct{ *bytes.Reader }
+
+func (nopCloser) Write(b []byte) (int, error) { return len(b), nil }
+func (nopCloser) Close() error                { return nil }
+
+// parserCorpus возвращает корректные пакеты из транскрипта и все их суффиксы,
+// чтобы каждый уровень стека получил и корректные, и искажённые данные
+func parserCorpus(t *testing.T) [][]byte {
+	f, err := os.Open("mmstest/testdata/read.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	exchanges, err := mmstest.ParseTranscript(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var corpus [][]byte
+	add := func(s string) {
+		packet, err := hex.DecodeString(string(bytes.ReplaceAll(bytes.Join(bytes.Fields([]byte(s)), nil), []byte("xx"), []byte("01"))))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := range packet {
+			corpus = append(corpus, packet[i:])
+		}
+	}
+	for _, e := range exchanges {
+		add(e.Request)
+		for _, r := range e.Responses {
+			add(r)
+		}
+	}
+	return corpus
+}
+
+// mutate возвращает искажённую копию sample: усечение, замена и вставка байт
+func mutate(r *rand.Rand, sample []byte) []byte {
+	b := append([]byte(nil), sample...)
+	switch r.IntN(4) {
+	case 0:
+		b = b[:r.IntN(len(b)+1)]
+	case 1:
+		for range 1 + r.IntN(4) {
+			if len(b) > 0 {
+				b[r.IntN(len(b))] = byte(r.Uint32())
+			}
+		}
+	case 2:
+		// Длины BER и TPKT: крайние значения
+		if len(b) > 0 {
+			b[r.IntN(len(b))] = []byte{0x00, 0x7f, 0x80, 0x81, 0x82, 0x84, 0xff}[r.IntN(7)]
+		}
+	default:
+		at := r.IntN(len(b) + 1)
+		extra := make([]byte, r.IntN(8))
+		for i := range extra {
+			extra[i] = byte(r.Uint32())
+		}
+		b = append(b[:at], append(extra, b[at:]...)...)
+	}
+	return b
+}
+
+// callParser вызывает парсер и возвращает ошибку при панике
+func callParser(parse func([]byte), input []byte) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	parse(input)
+	return nil
+}
+
+// TestParsersNoPanic проверяет, что ни один парсер не паникует на произвольном входе
+// до 64 КБ: случайные данные и мутации корректных пакетов
+func TestParsersNoPanic(t *testing.T) {
+	iterations := 3000
+	if testing.Short() {
+		iterations = 300
+	}
+	corpus := parserCorpus(t)
+	for name, parse := range parsers {
+		t.Run(name, func(t *testing.T) {
+			r := rand.New(rand.NewPCG(1, 2))
+			check := func(input []byte) {
+				if err := callParser(parse, input); err != nil {
+					t.Fatalf("%v on input %x", err, input[:min(len(input), 256)])
+				}
+			}
+			check(nil)
+			for _, sample := range corpus {
+				check(sample)
+			}
+			for i := range iterations {
+				if i%100 == 0 {
+					random := make([]byte, r.IntN(maxParserInput+1))
+					for j := range random {
+						random[j] = byte(r.Uint32())
+					}
+					check(random)
+					continue
+				}
+				check(mutate(r, corpus[r.IntN(len(corpus))]))
+			}
+		})
+	}
+}
@@ -227,7 +227,10 @@ func proofOfConcept(conn net.Conn, l logger.Logger) error {
 
 	// Читаем объект из сервера
 	objectName := "simpleIOGenericIO/GGIO1"
-	readRequest := mms.NewReadRequest(objectName, mms.FCMX)
+	readRequest, err := mms.NewReadRequest(objectName, mms.FCMX)
+	if err != nil {
+		return err
+	}
 	readResult, err := client.ReadObject(ctx, readRequest)
 	if err != nil {
 		return err
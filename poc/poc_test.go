@@ -1,15 +1,15 @@
 package main
 
 import (
-	"context"
 	"fmt"
-	"io"
 	"net"
 	"strings"
 	"testing"
-	"time"
 
-	"github.com/slonegd/go61850/osi/cotp"
+	"github.com/slonegd/go61850/model"
+	"github.com/slonegd/go61850/osi/mms"
+	"github.com/slonegd/go61850/osi/mms/variant"
+	"github.com/slonegd/go61850/server"
 )
 
 // Эталонный пакет MMS Initiate Request из комментария в main.go
@@ -29,16 +29,7 @@ var expectedReadPacket = parseHexString(`
 
 func TestProofOfConcept(t *testing.T) {
 	// Создаём тестовый сервер
-	server, err := newTestServer()
-	if err != nil {
-		t.Fatalf("Failed to create test server: %v", err)
-	}
-	defer server.Stop()
-
-	server.Start()
-
-	// Небольшая задержка для запуска сервера
-	time.Sleep(100 * time.Millisecond)
+	listener := newTestServer(t)
 
 	// Создаём мок логгер
 	mockLogger := &mockLogger{
@@ -48,7 +39,7 @@ func TestProofOfConcept(t *testing.T) {
 	}
 
 	// Подключаемся к серверу
-	conn, err := net.Dial("tcp", server.Addr())
+	conn, err := net.Dial("tcp", listener.Addr().String())
 	if err != nil {
 		t.Fatalf("Failed to connect to test server: %v", err)
 	}
@@ -229,177 +220,35 @@ func (m *mockLogger) getAllTXPackets() [][]byte {
 	return m.txPackets
 }
 
-// testServer представляет простой COTP сервер для тестирования
-type testServer struct {
-	listener net.Listener
-	address  string
-}
-
-func newTestServer() (*testServer, error) {
+// newTestServer запускает сервер IEC 61850 с моделью simpleIOGenericIO
+// из libIEC61850 (GGIO1 с аналоговым входом AnIn1) на случайном порту.
+// Соединения обслуживает server.ServeConn, как у симулятора IED.
+func newTestServer(t *testing.T) net.Listener {
+	m := &model.Model{
+		Name: "simpleIO",
+		LogicalDevices: []*model.LogicalDevice{{
+			Name: "simpleIOGenericIO",
+			LogicalNodes: []*model.LogicalNode{{
+				Name: "GGIO1",
+				DataObjects: []*model.DataObject{{
+					Name: "AnIn1",
+					Children: []model.DataNode{
+						&model.DataAttribute{Name: "mag", FC: mms.FCMX, Attributes: []*model.DataAttribute{
+							{Name: "f", FC: mms.FCMX, Value: variant.NewFloat32Variant(1.5)},
+						}},
+					},
+				}},
+			}},
+		}},
+	}
+	s := server.New(m, server.WithLogger(&mockLogger{}))
 	listener, err := net.Listen("tcp", "localhost:0")
 	if err != nil {
-		return nil, err
-	}
-	return &testServer{
-		listener: listener,
-		address:  listener.Addr().String(),
-	}, nil
-}
-
-func (s *testServer) Addr() string {
-	return s.address
-}
-
-func (s *testServer) Start() {
-	go s.acceptLoop()
-}
-
-func (s *testServer) acceptLoop() {
-	for {
-		conn, err := s.listener.Accept()
-		if err != nil {
-			return
-		}
-		go s.handleConnection(conn)
-	}
-}
-
-func (s *testServer) handleConnection(conn net.Conn) {
-	defer conn.Close()
-
-	cotpConn := cotp.NewConnection(conn)
-
-	// Ожидание Connection Request
-	if err := s.waitForConnectionRequest(cotpConn, 5*time.Second); err != nil {
-		return
-	}
-
-	// Отправка Connection Confirm
-	if err := cotpConn.SendConnectionResponseMessage(); err != nil {
-		return
-	}
-
-	// Ожидание данных (MMS Initiate Request)
-	if err := s.waitForData(cotpConn, 5*time.Second); err != nil {
-		return
-	}
-
-	// Отправка правильного ответа (из комментария в go61850.go)
-	// RX: 03 00 00 8f 02 f0 80 0e 86 05 06 13 01 00 16 01 02 14 02 00 02 34 02 00 01 c1 74 31 72 a0 03 80 01 01 a2 6b 83 04 00 00 00 01 a5 12 30 07 80 01 00 81 02 51 01 30 07 80 01 00 81 02 51 01 61 4f 30 4d 02 01 01 a0 48 61 46 a1 07 06 05 28 ca 22 02 03 a2 03 02 01 00 a3 05 a1 03 02 01 00 be 2f 28 2d 02 01 03 a0 28 a9 26 80 03 00 fd e8 81 01 05 82 01 05 83 01 0a a4 16 80 01 01 81 03 05 f1 00 82 0c 03 ee 1c 00 00 00 02 00 00 40 ed 18
-	initiateResponse := parseHexString("03 00 00 8f 02 f0 80 0e 86 05 06 13 01 00 16 01 02 14 02 00 02 34 02 00 01 c1 74 31 72 a0 03 80 01 01 a2 6b 83 04 00 00 00 01 a5 12 30 07 80 01 00 81 02 51 01 30 07 80 01 00 81 02 51 01 61 4f 30 4d 02 01 01 a0 48 61 46 a1 07 06 05 28 ca 22 02 03 a2 03 02 01 00 a3 05 a1 03 02 01 00 be 2f 28 2d 02 01 03 a0 28 a9 26 80 03 00 fd e8 81 01 05 82 01 05 83 01 0a a4 16 80 01 01 81 03 05 f1 00 82 0c 03 ee 1c 00 00 00 02 00 00 40 ed 18")
-	conn.Write(initiateResponse)
-
-	// Ожидание Read Request и отправка Read Response
-	// Формат ответа из комментария в go61850.go:
-	// 0300002402f08001000100611730150201030a10a10e020101a409a1078705083edf52cc
-	// Но в комментарии указано "0a10", что неправильно - должно быть "a010" для Context-specific 0, Constructed
-	// Исправленный формат: 0300002402f0800100010061173015020103a010a10e020101a409a1078705083edf52cc
-	if err := s.waitForData(cotpConn, 5*time.Second); err != nil {
-		return
-	}
-
-	// Отправка Read Response со структурой (из wireshark)
-	// TPKT: 03 00 00 87 (135 bytes total)
-	// COTP: 02 f0 80
-	// Session: 01 00 01 00 (Give tokens + DT SPDU)
-	// Presentation: 61 7a 30 78 02 01 03 a0 73
-	// MMS: a171020101a46ca16aa268a218a207870508bf79d56984030300009108695ba6ce6a3d7080a218a207870508bf35fb3884030300009108695ba6ce6a3d7080a218a2078705083e54bc2f84030300009108695ba6ce6a3d7080a218a2078705083f6f73b484030300009108695ba6ce6a3d7080
-	readResponse := parseHexString("03 00 00 87 02 f0 80 01 00 01 00 61 7a 30 78 02 01 03 a0 73 a171020101a46ca16aa268a218a207870508bf79d56984030300009108695ba6ce6a3d7080a218a207870508bf35fb3884030300009108695ba6ce6a3d7080a218a2078705083e54bc2f84030300009108695ba6ce6a3d7080a218a2078705083f6f73b484030300009108695ba6ce6a3d7080")
-	conn.Write(readResponse)
-
-	// Небольшая задержка перед закрытием соединения, чтобы клиент успел прочитать ответ
-	time.Sleep(100 * time.Millisecond)
-}
-
-func (s *testServer) waitForConnectionRequest(cotpConn *cotp.Connection, timeout time.Duration) error {
-	deadline := time.Now().Add(timeout)
-	ticker := time.NewTicker(10 * time.Millisecond)
-	defer ticker.Stop()
-
-	for time.Now().Before(deadline) {
-		select {
-		case <-ticker.C:
-			state, err := cotpConn.ReadToTpktBuffer(context.Background())
-			if err != nil {
-				if err == io.EOF {
-					return fmt.Errorf("connection closed")
-				}
-				return fmt.Errorf("read error: %w", err)
-			}
-
-			if state == cotp.TpktWaiting {
-				continue
-			}
-
-			if state == cotp.TpktError {
-				return fmt.Errorf("tpkt error")
-			}
-
-			indication, err := cotpConn.ParseIncomingMessage()
-			if err != nil {
-				return fmt.Errorf("parse error: %w", err)
-			}
-
-			if indication == cotp.IndicationConnect {
-				return nil
-			}
-
-			if indication == cotp.IndicationError {
-				return fmt.Errorf("connection error")
-			}
-		}
-	}
-
-	return fmt.Errorf("connection timeout")
-}
-
-func (s *testServer) waitForData(cotpConn *cotp.Connection, timeout time.Duration) error {
-	deadline := time.Now().Add(timeout)
-	ticker := time.NewTicker(10 * time.Millisecond)
-	defer ticker.Stop()
-
-	for time.Now().Before(deadline) {
-		select {
-		case <-ticker.C:
-			state, err := cotpConn.ReadToTpktBuffer(context.Background())
-			if err != nil {
-				if err == io.EOF {
-					return fmt.Errorf("connection closed")
-				}
-				return fmt.Errorf("read error: %w", err)
-			}
-
-			if state == cotp.TpktWaiting {
-				continue
-			}
-
-			if state == cotp.TpktError {
-				return fmt.Errorf("tpkt error")
-			}
-
-			indication, err := cotpConn.ParseIncomingMessage()
-			if err != nil {
-				return fmt.Errorf("parse error: %w", err)
-			}
-
-			if indication == cotp.IndicationData {
-				return nil
-			}
-
-			if indication == cotp.IndicationMoreFragmentsFollow {
-				continue
-			}
-		}
-	}
-
-	return fmt.Errorf("data timeout")
-}
-
-func (s *testServer) Stop() error {
-	if s.listener != nil {
-		return s.listener.Close()
+		t.Fatalf("Failed to create test server: %v", err)
 	}
-	return nil
+	t.Cleanup(func() { listener.Close() })
+	go s.Serve(listener)
+	return listener
 }
 
 // parseHexString парсит hex строку из комментария
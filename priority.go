@@ -0,0 +1,186 @@
+package go61850
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Priority - класс приоритета запроса к серверу. Клиент выполняет confirmed-запросы
+// по одному; ожидающие запросы получают ассоциацию в порядке приоритета,
+// при равном приоритете - в порядке поступления.
+type Priority uint8
+
+const (
+	// PriorityBackground - фоновые запросы: обзор модели (DiscoverModel)
+	PriorityBackground Priority = iota
+	// PriorityPoll - циклический опрос; приоритет по умолчанию
+	PriorityPoll
+	// PriorityReport - управление отчётами: настройка и подтверждение RCB
+	PriorityReport
+	// PriorityControl - команды управления: Select, SelectWithValue, Operate
+	PriorityControl
+
+	priorityCount = int(PriorityControl) + 1
+)
+
+// String возвращает строковое представление Priority
+func (p Priority) String() string {
+	switch p {
+	case PriorityBackground:
+		return "background"
+	case PriorityPoll:
+		return "poll"
+	case PriorityReport:
+		return "report"
+	case PriorityControl:
+		return "control"
+	default:
+		return fmt.Sprintf("Priority(%d)", uint8(p))
+	}
+}
+
+type priorityKey struct{}
+
+// WithPriority возвращает контекст, запросы с которым выполняются с приоритетом p.
+// Так большой фоновый обзор модели не задерживает команду оператора,
+// поставленную в очередь после него.
+func WithPriority(ctx context.Context, p Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, p)
+}
+
+// withDefaultPriority задаёт приоритет p, если он не задан вызывающим
+func withDefaultPriority(ctx context.Context, p Priority) context.Context {
+	if _, ok := ctx.Value(priorityKey{}).(Priority); ok {
+		return ctx
+	}
+	return WithPriority(ctx, p)
+}
+
+// priorityFromContext возвращает приоритет запроса, по умолчанию PriorityPoll
+func priorityFromContext(ctx context.Context) Priority {
+	if p, ok := ctx.Value(priorityKey{}).(Priority); ok && int(p) < priorityCount {
+		return p
+	}
+	return PriorityPoll
+}
+
+// requestQueue выдаёт ассоциацию одному запросу за раз в порядке приоритета
+type requestQueue struct {
+	mu      sync.Mutex
+	busy    bool
+	waiting [priorityCount][]chan struct{}
+	// preempt прерывает фоновый приём, занимающий свободную ассоциацию (см. Listen)
+	preempt func()
+	// free закрывается при освобождении ассоциации без ожидающих запросов
+	free chan struct{}
+}
+
+// acquire ожидает очереди запроса с приоритетом p
+func (q *requestQueue) acquire(ctx context.Context, p Priority) error {
+	q.mu.Lock()
+	if !q.busy {
+		q.busy = true
+		q.mu.Unlock()
+		return nil
+	}
+	ready := make(chan struct{})
+	q.waiting[p] = append(q.waiting[p], ready)
+	if q.preempt != nil {
+		q.preempt()
+	}
+	q.mu.Unlock()
+
+	select {
+	case <-ready:
+		return nil
+	case <-ctx.Done():
+		q.mu.Lock()
+		for i, ch := range q.waiting[p] {
+			if ch == ready {
+				q.waiting[p] = append(q.waiting[p][:i], q.waiting[p][i+1:]...)
+				q.mu.Unlock()
+				return ctx.Err()
+			}
+		}
+		q.mu.Unlock()
+		// Очередь выдана одновременно с отменой: передаём её следующему
+		q.release()
+		return ctx.Err()
+	}
+}
+
+// acquireIdle занимает ассоциацию, когда её не ожидает ни один запрос.
+// Пока ассоциация занята, постановка запроса в очередь вызывает preempt.
+func (q *requestQueue) acquireIdle(ctx context.Context, preempt func()) error {
+	for {
+		q.mu.Lock()
+		if !q.busy {
+			q.busy = true
+			q.preempt = preempt
+			q.mu.Unlock()
+			return nil
+		}
+		if q.free == nil {
+			q.free = make(chan struct{})
+		}
+		free := q.free
+		q.mu.Unlock()
+
+		select {
+		case <-free:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// release передаёт ассоциацию ожидающему запросу с наибольшим приоритетом
+func (q *requestQueue) release() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.preempt = nil
+	for p := priorityCount - 1; p >= 0; p-- {
+		if len(q.waiting[p]) > 0 {
+			ready := q.waiting[p][0]
+			q.waiting[p] = q.waiting[p][1:]
+			close(ready)
+			return
+		}
+	}
+	q.busy = false
+	if q.free != nil {
+		close(q.free)
+		q.free = nil
+	}
+}
+
+// beginRequest ставит confirmed-запрос в очередь по приоритету из ctx и ожидает
+// ограничителя частоты (WithRateLimit). Возвращает функцию завершения запроса.
+// Если ассоциация уже занята вызывающим (holdAssociation), ожидается только ограничитель.
+func (c *MmsClient) beginRequest(ctx context.Context) (func(), error) {
+	if held, _ := ctx.Value(heldKey{}).(bool); held {
+		return func() {}, c.limiter.Wait(ctx)
+	}
+	if err := c.queue.acquire(ctx, priorityFromContext(ctx)); err != nil {
+		return nil, err
+	}
+	if err := c.limiter.Wait(ctx); err != nil {
+		c.queue.release()
+		return nil, err
+	}
+	return c.queue.release, nil
+}
+
+type heldKey struct{}
+
+// holdAssociation занимает ассоциацию для нескольких запросов подряд и чтения
+// между ними, например ожидания CommandTermination после Operate. Запросы
+// с возвращённым контекстом не ставятся в очередь повторно.
+func (c *MmsClient) holdAssociation(ctx context.Context) (context.Context, func(), error) {
+	if err := c.queue.acquire(ctx, priorityFromContext(ctx)); err != nil {
+		return nil, nil, err
+	}
+	return context.WithValue(ctx, heldKey{}, true), c.queue.release, nil
+}
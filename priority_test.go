@@ -0,0 +1,105 @@
+package go61850
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestQueuePriority(t *testing.T) {
+	var q requestQueue
+	ctx := context.Background()
+	assert.NoError(t, q.acquire(ctx, PriorityPoll))
+
+	var mu sync.Mutex
+	var order []string
+	var wg sync.WaitGroup
+	queued := 0
+	enqueue := func(name string, p Priority) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, q.acquire(ctx, p))
+			mu.Lock()
+			order = append(order, name)
+			mu.Unlock()
+			q.release()
+		}()
+		// Дожидаемся постановки в очередь, чтобы порядок поступления был определён
+		queued++
+		assert.Eventually(t, func() bool {
+			q.mu.Lock()
+			defer q.mu.Unlock()
+			n := 0
+			for _, waiting := range q.waiting {
+				n += len(waiting)
+			}
+			return n == queued
+		}, time.Second, time.Millisecond)
+	}
+	enqueue("discovery-1", PriorityBackground)
+	enqueue("poll-1", PriorityPoll)
+	enqueue("discovery-2", PriorityBackground)
+	enqueue("poll-2", PriorityPoll)
+	enqueue("operate", PriorityControl)
+	enqueue("report", PriorityReport)
+
+	q.release()
+	wg.Wait()
+	assert.Equal(t, []string{"operate", "report", "poll-1", "poll-2", "discovery-1", "discovery-2"}, order)
+	assert.False(t, q.busy)
+}
+
+func TestRequestQueueCancel(t *testing.T) {
+	var q requestQueue
+	ctx := context.Background()
+	assert.NoError(t, q.acquire(ctx, PriorityPoll))
+
+	cancelled, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, q.acquire(cancelled, PriorityControl), context.DeadlineExceeded)
+	assert.Empty(t, q.waiting[PriorityControl])
+
+	q.release()
+	assert.False(t, q.busy)
+	assert.NoError(t, q.acquire(ctx, PriorityBackground))
+}
+
+func TestPriorityFromContext(t *testing.T) {
+	ctx := context.Background()
+	assert.Equal(t, PriorityPoll, priorityFromContext(ctx))
+	assert.Equal(t, PriorityControl, priorityFromContext(withDefaultPriority(ctx, PriorityControl)))
+
+	// Приоритет вызывающего не переопределяется
+	background := WithPriority(ctx, PriorityBackground)
+	assert.Equal(t, PriorityBackground, priorityFromContext(withDefaultPriority(background, PriorityControl)))
+	assert.Equal(t, "control", PriorityControl.String())
+	assert.Equal(t, "Priority(9)", Priority(9).String())
+}
+
+func TestRequestQueueIdle(t *testing.T) {
+	var q requestQueue
+	ctx := context.Background()
+	assert.NoError(t, q.acquire(ctx, PriorityPoll))
+
+	// Фоновый приём ожидает освобождения ассоциации
+	preempted := make(chan struct{}, 1)
+	acquired := make(chan error, 1)
+	go func() { acquired <- q.acquireIdle(ctx, func() { preempted <- struct{}{} }) }()
+	time.Sleep(10 * time.Millisecond)
+	assert.Empty(t, acquired)
+	q.release()
+	assert.NoError(t, <-acquired)
+
+	// Запрос прерывает фоновый приём и получает ассоциацию после него
+	requested := make(chan error, 1)
+	go func() { requested <- q.acquire(ctx, PriorityBackground) }()
+	<-preempted
+	q.release()
+	assert.NoError(t, <-requested)
+	q.release()
+	assert.False(t, q.busy)
+}
@@ -0,0 +1,122 @@
+package go61850
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Quirk представляет документированное отклонение устройства от стандарта
+type Quirk uint32
+
+const (
+	// QuirkReportSegmentationUnset - устройство отправляет сегментированные отчёты,
+	// не выставляя бит segmentation в OptFlds; признаком сегментации служит
+	// наличие MoreSegmentsFollow.
+	QuirkReportSegmentationUnset Quirk = 1 << iota
+	// QuirkReportEntryIDAlwaysPresent - устройство включает EntryID в отчёты
+	// небуферизированных RCB независимо от OptFlds.
+	QuirkReportEntryIDAlwaysPresent
+	// QuirkNoFCDRead - устройство не отвечает на чтение FCD (DO с FC целиком),
+	// атрибуты нужно читать по отдельности.
+	QuirkNoFCDRead
+)
+
+// String возвращает строковое представление Quirk
+func (q Quirk) String() string {
+	switch q {
+	case QuirkReportSegmentationUnset:
+		return "report-segmentation-unset"
+	case QuirkReportEntryIDAlwaysPresent:
+		return "report-entry-id-always-present"
+	case QuirkNoFCDRead:
+		return "no-fcd-read"
+	default:
+		return fmt.Sprintf("Quirk(%#x)", uint32(q))
+	}
+}
+
+const (
+	// MaxNameLengthEd1 - максимальная длина MMS идентификатора в IEC 61850 Edition 1
+	MaxNameLengthEd1 = 32
+	// MaxNameLengthEd2 - максимальная длина MMS идентификатора в IEC 61850 Edition 2
+	MaxNameLengthEd2 = 64
+)
+
+// ServerProfile описывает особенности семейства устройств (сервера),
+// которые клиент учитывает при работе с ним. Все отклонения от стандарта
+// собраны здесь, чтобы не разносить проверки по коду.
+type ServerProfile struct {
+	// Name - название профиля
+	Name string
+	// LocalTSelector - T-selector клиента, который ожидает устройство
+	LocalTSelector []byte
+	// RemoteTSelector - T-selector устройства
+	RemoteTSelector []byte
+	// MaxNameLength - максимальная длина domainId и itemId
+	MaxNameLength int
+	// Quirks - набор отклонений от стандарта
+	Quirks Quirk
+}
+
+// Has возвращает true, если профиль содержит отклонение q
+func (p ServerProfile) Has(q Quirk) bool {
+	return p.Quirks&q != 0
+}
+
+// CheckName проверяет, что MMS имя не превышает ограничение профиля
+func (p ServerProfile) CheckName(name string) error {
+	if p.MaxNameLength > 0 && len(name) > p.MaxNameLength {
+		return fmt.Errorf("name %q exceeds %d characters allowed by profile %s", name, p.MaxNameLength, p.Name)
+	}
+	return nil
+}
+
+// String возвращает строковое представление ServerProfile
+func (p ServerProfile) String() string {
+	var quirks []string
+	for q := QuirkReportSegmentationUnset; q <= QuirkNoFCDRead; q <<= 1 {
+		if p.Has(q) {
+			quirks = append(quirks, q.String())
+		}
+	}
+	return fmt.Sprintf("ServerProfile{Name:%s MaxNameLength:%d Quirks:[%s]}", p.Name, p.MaxNameLength, strings.Join(quirks, " "))
+}
+
+var (
+	// ProfileDefault - устройство, строго следующее стандарту Edition 2
+	ProfileDefault = ServerProfile{
+		Name:            "default",
+		LocalTSelector:  []byte{0, 1},
+		RemoteTSelector: []byte{0, 1},
+		MaxNameLength:   MaxNameLengthEd2,
+	}
+
+	// ProfileSiemens - Siemens SIPROTEC 4/5.
+	// SIPROTEC 4 (Edition 1) ограничивает имена 32 символами и сегментирует
+	// большие отчёты без бита segmentation в OptFlds.
+	ProfileSiemens = ServerProfile{
+		Name:            "siemens",
+		LocalTSelector:  []byte{0, 1},
+		RemoteTSelector: []byte{0, 1},
+		MaxNameLength:   MaxNameLengthEd1,
+		Quirks:          QuirkReportSegmentationUnset,
+	}
+
+	// ProfileABB - ABB Relion 615/670.
+	// Включает EntryID в отчёты небуферизированных RCB и не поддерживает
+	// чтение FCD для части логических узлов.
+	ProfileABB = ServerProfile{
+		Name:            "abb",
+		LocalTSelector:  []byte{0, 1},
+		RemoteTSelector: []byte{0, 1},
+		MaxNameLength:   MaxNameLengthEd1,
+		Quirks:          QuirkReportEntryIDAlwaysPresent | QuirkNoFCDRead,
+	}
+)
+
+// WithServerProfile устанавливает профиль устройства, с которым работает MmsClient
+func WithServerProfile(p ServerProfile) MmsClientOption {
+	return func(c *MmsClient) {
+		c.profile = p
+	}
+}
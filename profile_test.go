@@ -0,0 +1,121 @@
+package go61850
+
+import (
+	"context"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/slonegd/go61850/mmstest"
+	"github.com/slonegd/go61850/osi/mms"
+	"github.com/slonegd/go61850/osi/mms/variant"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithServerProfile(t *testing.T) {
+	c := &MmsClient{profile: ProfileDefault}
+	WithServerProfile(ProfileSiemens)(c)
+	assert.Equal(t, ProfileSiemens, c.Profile())
+
+	// Edition 1: имена длиннее 32 символов отклоняются до отправки запроса
+	name := "LLN0$BR$" + strings.Repeat("b", 30)
+	assert.Error(t, c.checkNames(name))
+	WithServerProfile(ProfileABB)(c)
+	assert.Error(t, c.checkNames(name))
+	WithServerProfile(ProfileDefault)(c)
+	assert.NoError(t, c.checkNames(name))
+}
+
+// observeReport передаёт клиенту с профилем profile отчёт со значениями values
+// и возвращает число отметок времени, учтённых ClockSkew
+func observeReport(t *testing.T, profile ServerProfile, values ...*variant.Variant) uint64 {
+	skew := NewClockSkew()
+	c := &MmsClient{logger: defaultLogger()}
+	WithServerProfile(profile)(c)
+	WithClockSkew(skew)(c)
+
+	results := make([]mms.AccessResult, len(values))
+	for i, value := range values {
+		results[i] = mms.AccessResult{Success: true, Value: value}
+	}
+	pdu, err := (&mms.InformationReportPDU{VariableListName: &mms.ObjectName{ItemID: "RPT"}, Results: results}).Bytes()
+	assert.NoError(t, err)
+	assert.True(t, c.dispatchReport(context.Background(), pdu, nil))
+	return skew.Observations()
+}
+
+func TestProfileReportQuirks(t *testing.T) {
+	value := variant.NewStructureVariant([]*variant.Variant{variant.NewUTCTimeVariant(time.Now())})
+
+	// Siemens: сегментированный отчёт без бита segmentation в OptFlds
+	segmented := []*variant.Variant{
+		variant.NewVisibleStringVariant("Meas"),
+		OptSeqNum.BitString(),
+		variant.NewUint32Variant(3),
+		variant.NewUint32Variant(0),
+		variant.NewBoolVariant(true),
+		variant.NewBitStringVariant([]byte{0x80}, 1),
+		value,
+	}
+	assert.Equal(t, uint64(0), observeReport(t, ProfileDefault, segmented...))
+	assert.Equal(t, uint64(1), observeReport(t, ProfileSiemens, segmented...))
+
+	// ABB: EntryID в отчёте небуферизированного RCB без бита entryID в OptFlds
+	withEntryID := []*variant.Variant{
+		variant.NewVisibleStringVariant("Meas"),
+		OptSeqNum.BitString(),
+		variant.NewUint32Variant(3),
+		variant.NewOctetStringVariant([]byte{0, 0, 0, 0, 0, 0, 0, 1}),
+		variant.NewBitStringVariant([]byte{0x80}, 1),
+		value,
+	}
+	assert.Equal(t, uint64(0), observeReport(t, ProfileDefault, withEntryID...))
+	assert.Equal(t, uint64(1), observeReport(t, ProfileABB, withEntryID...))
+}
+
+func TestProfileNoFCDRead(t *testing.T) {
+	// Тип GGIO1$MX$AnIn1 из ответа server_example_basic_io: mag.f, q, t
+	attributes, err := hex.DecodeString("a13d020101a638800100a233" +
+		"a231a12f301a80036d6167a113a211a10f300d800166a108a7060201200201083008800171a1038401f33007800174a1029100")
+	assert.NoError(t, err)
+
+	f := variant.NewFloat32Variant(1.5)
+	q := variant.NewBitStringVariant([]byte{0, 0}, 13)
+	ts := variant.NewUTCTimeVariant(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+	exchanges := append(fileTranscript(t), mmstest.Exchange{
+		Request:   mmsRequest(mms.NewGetVariableAccessAttributesRequest("simpleIOGenericIO", "GGIO1$MX$AnIn1").Bytes()),
+		Responses: []string{mmsFrame(attributes)},
+	})
+	// ABB: FCD не читается, каждый атрибут - отдельным запросом
+	for i, attribute := range []struct {
+		itemID string
+		value  *variant.Variant
+	}{
+		{"GGIO1$MX$AnIn1$mag$f", f},
+		{"GGIO1$MX$AnIn1$q", q},
+		{"GGIO1$MX$AnIn1$t", ts},
+	} {
+		response, err := (&mms.ReadResponse{InvokeID: uint32(i + 2), ListOfAccessResult: []mms.AccessResult{{
+			Success: true,
+			Value:   attribute.value,
+		}}}).Bytes()
+		assert.NoError(t, err)
+		request := &mms.ReadRequest{DomainID: "simpleIOGenericIO", ItemID: attribute.itemID}
+		exchanges = append(exchanges, mmstest.Exchange{Request: mmsRequest(request.Bytes()), Responses: []string{mmsFrame(response)}})
+	}
+	server := mmstest.NewTranscriptServer(t, exchanges...)
+	conn := server.Dial()
+	ctx := context.Background()
+	client, err := NewMmsClient(ctx, conn, WithDefaultDomain("simpleIOGenericIO"), WithServerProfile(ProfileABB))
+	assert.NoError(t, err)
+	_, err = client.Initiate(ctx)
+	assert.NoError(t, err)
+
+	values, err := client.ReadDataObject(ctx, "GGIO1.AnIn1", mms.FCMX)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]*variant.Variant{"mag.f": f, "q": q, "t": ts}, values)
+
+	conn.Close()
+	assert.NoError(t, server.Wait())
+}
@@ -0,0 +1,98 @@
+package go61850
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// RateLimiter ограничивает частоту запросов к серверу алгоритмом token bucket.
+// Безопасен для конкурентного использования: один RateLimiter можно разделить
+// между горутинами одного клиента и между несколькими клиентами одного IED.
+type RateLimiter struct {
+	mu     sync.Mutex
+	rate   float64 // токенов в секунду
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter создаёт ограничитель на requestsPerSecond запросов в секунду,
+// допускающий серию до burst запросов без ожидания. requestsPerSecond <= 0 не ограничивает.
+func NewRateLimiter(requestsPerSecond float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimiter{
+		rate:   requestsPerSecond,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// WithRateLimit ограничивает частоту confirmed-запросов клиента (Read, Write,
+// GetNameList, GetVariableAccessAttributes) до requestsPerSecond в секунду, защищая
+// небольшие встроенные IED от перегрузки частым опросом. Запрос сверх лимита ждёт
+// своей очереди или отмены контекста. 0 и отрицательные значения не ограничивают.
+func WithRateLimit(requestsPerSecond float64) MmsClientOption {
+	return func(c *MmsClient) {
+		c.limiter = nil
+		if requestsPerSecond > 0 {
+			c.limiter = NewRateLimiter(requestsPerSecond, 1)
+		}
+	}
+}
+
+// WithRateLimiter задаёт общий ограничитель частоты запросов,
+// например для нескольких клиентов, опрашивающих одно устройство
+func WithRateLimiter(l *RateLimiter) MmsClientOption {
+	return func(c *MmsClient) {
+		c.limiter = l
+	}
+}
+
+// Wait ожидает разрешения на запрос. Возвращает ошибку контекста, если он отменён
+// раньше; в этом случае разрешение возвращается в bucket. Для nil RateLimiter
+// возвращается сразу.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+	delay := l.reserve(time.Now())
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		l.tokens = math.Min(l.tokens+1, l.burst)
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// reserve забирает токен и возвращает время ожидания до его появления
+func (l *RateLimiter) reserve(now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.rate <= 0 {
+		return 0
+	}
+	if elapsed := now.Sub(l.last); elapsed > 0 {
+		l.tokens = math.Min(l.burst, l.tokens+elapsed.Seconds()*l.rate)
+		l.last = now
+	}
+	l.tokens--
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.rate * float64(time.Second))
+}
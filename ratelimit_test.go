@@ -0,0 +1,69 @@
+package go61850
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiterReserve(t *testing.T) {
+	l := NewRateLimiter(10, 2)
+	now := l.last
+
+	// Серия до burst без ожидания, далее - по 100 мс на запрос
+	assert.Equal(t, time.Duration(0), l.reserve(now))
+	assert.Equal(t, time.Duration(0), l.reserve(now))
+	assert.Equal(t, 100*time.Millisecond, l.reserve(now))
+	assert.Equal(t, 200*time.Millisecond, l.reserve(now))
+
+	// За секунду bucket наполняется, но не больше burst
+	now = now.Add(time.Second)
+	assert.Equal(t, time.Duration(0), l.reserve(now))
+	assert.Equal(t, time.Duration(0), l.reserve(now))
+	now = now.Add(10 * time.Second)
+	assert.Equal(t, time.Duration(0), l.reserve(now))
+	assert.Equal(t, time.Duration(0), l.reserve(now))
+	assert.Equal(t, 100*time.Millisecond, l.reserve(now))
+}
+
+func TestRateLimiterWait(t *testing.T) {
+	l := NewRateLimiter(200, 1)
+	ctx := context.Background()
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, l.Wait(ctx))
+		}()
+	}
+	wg.Wait()
+	// Первый запрос сразу, остальные четыре - через 5 мс каждый
+	assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+
+	// Отменённое ожидание возвращает токен
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	assert.ErrorIs(t, l.Wait(cancelled), context.Canceled)
+
+	var nilLimiter *RateLimiter
+	assert.NoError(t, nilLimiter.Wait(ctx))
+	assert.NoError(t, NewRateLimiter(0, 1).Wait(ctx))
+}
+
+func TestWithRateLimit(t *testing.T) {
+	client := &MmsClient{}
+	WithRateLimit(5)(client)
+	assert.NotNil(t, client.limiter)
+	WithRateLimit(0)(client)
+	assert.Nil(t, client.limiter)
+
+	shared := NewRateLimiter(1, 1)
+	WithRateLimiter(shared)(client)
+	assert.Same(t, shared, client.limiter)
+}
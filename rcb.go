@@ -0,0 +1,101 @@
+package go61850
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/slonegd/go61850/model"
+	"github.com/slonegd/go61850/osi/mms"
+	"github.com/slonegd/go61850/osi/mms/variant"
+)
+
+// RCBSettings - параметры блока управления отчётами для ConfigureRCB.
+// Записываются только заданные (не nil) атрибуты.
+type RCBSettings struct {
+	// RptID - идентификатор отчётов; пустая строка - сервер подставит ссылку на RCB
+	RptID *string
+	// DatSet - ссылка на набор данных в формате MMS, например "LD0/LLN0$Events"
+	DatSet  *string
+	OptFlds *OptFlds
+	TrgOps  *model.Trigger
+	// IntgPd и BufTm - период целостности и время буферизации в мс
+	IntgPd *uint32
+	BufTm  *uint32
+	// EntryID - запись буферизированного RCB (BR), после которой сервер передаёт
+	// отчёты из буфера; используется для продолжения после переподключения
+	EntryID *model.EntryID
+	// GI - запросить общий опрос после включения, чтобы получить
+	// текущие значения нового набора данных
+	GI bool
+}
+
+// rcbAttribute - атрибут RCB и его значение для записи
+type rcbAttribute struct {
+	name  string
+	value *variant.Variant
+}
+
+// attributes возвращает атрибуты для записи в порядке IEC 61850-8-1
+func (s RCBSettings) attributes() []rcbAttribute {
+	var attributes []rcbAttribute
+	if s.RptID != nil {
+		attributes = append(attributes, rcbAttribute{"RptID", variant.NewVisibleStringVariant(*s.RptID)})
+	}
+	if s.DatSet != nil {
+		attributes = append(attributes, rcbAttribute{"DatSet", variant.NewVisibleStringVariant(*s.DatSet)})
+	}
+	if s.OptFlds != nil {
+		attributes = append(attributes, rcbAttribute{"OptFlds", s.OptFlds.BitString()})
+	}
+	if s.BufTm != nil {
+		attributes = append(attributes, rcbAttribute{"BufTm", variant.NewUint32Variant(*s.BufTm)})
+	}
+	if s.TrgOps != nil {
+		attributes = append(attributes, rcbAttribute{"TrgOps", s.TrgOps.BitString(6)})
+	}
+	if s.IntgPd != nil {
+		attributes = append(attributes, rcbAttribute{"IntgPd", variant.NewUint32Variant(*s.IntgPd)})
+	}
+	if s.EntryID != nil {
+		attributes = append(attributes, rcbAttribute{"EntryID", variant.NewOctetStringVariant(s.EntryID.Bytes())})
+	}
+	return attributes
+}
+
+// ConfigureRCB перенастраивает блок управления отчётами rcbRef и включает его.
+// Сервер принимает изменение DatSet, RptID и прочих атрибутов только у выключенного RCB,
+// поэтому выполняется последовательность: RptEna=false, запись атрибутов settings,
+// RptEna=true и, если задано settings.GI, GI=true.
+//
+// rcbRef задаётся как "LD0/LLN0.RP.EventsRCB" или в формате MMS "LD0/LLN0$RP$EventsRCB";
+// функциональная связь RP - небуферизированный, BR - буферизированный RCB.
+// При ошибке возвращается атрибут, запись которого не удалась; RCB может остаться выключенным.
+//
+// Запросы выполняются с приоритетом PriorityReport, если в ctx не задан другой.
+func (c *MmsClient) ConfigureRCB(ctx context.Context, rcbRef string, settings RCBSettings) error {
+	ctx = withDefaultPriority(ctx, PriorityReport)
+
+	request, err := c.NewReadRequest(rcbRef, mms.FCNone)
+	if err != nil {
+		return err
+	}
+	if !strings.Contains(request.ItemID, "$RP$") && !strings.Contains(request.ItemID, "$BR$") {
+		return fmt.Errorf("%s is not a report control block reference", rcbRef)
+	}
+
+	attributes := []rcbAttribute{{"RptEna", variant.NewBoolVariant(false)}}
+	attributes = append(attributes, settings.attributes()...)
+	attributes = append(attributes, rcbAttribute{"RptEna", variant.NewBoolVariant(true)})
+	if settings.GI {
+		attributes = append(attributes, rcbAttribute{"GI", variant.NewBoolVariant(true)})
+	}
+
+	for _, attribute := range attributes {
+		err := c.writeVariable(ctx, request.DomainID, request.ItemID+"$"+attribute.name, attribute.value, nil)
+		if err != nil {
+			return fmt.Errorf("configure %s: write %s: %w", rcbRef, attribute.name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package go61850
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/slonegd/go61850/mmstest"
+	"github.com/slonegd/go61850/osi/mms"
+	"github.com/stretchr/testify/assert"
+)
+
+// rcbWriteOK - успешный ответ на Write одной переменной
+const rcbWriteOK = "03 00 00 1d 02 f0 80 01 00 01 00 61 10 30 0e 02 01 03 a0 09 a1 07 02 01 01 a5 02 81 00"
+
+func TestConfigureRCB(t *testing.T) {
+	f, err := os.Open("mmstest/testdata/read.txt")
+	assert.NoError(t, err)
+	defer f.Close()
+	exchanges, err := mmstest.ParseTranscript(f)
+	assert.NoError(t, err)
+
+	server := mmstest.NewTranscriptServer(t,
+		exchanges[0], exchanges[1],
+		// LD/LLN0$RP$rcb01$RptEna := false
+		mmstest.Exchange{
+			Request: "03 00 00 42 02 f0 80 01 00 01 00 61 35 30 33 02 01 03 a0 2e a0 2c 02 01 xx a5 27 a0 20 30 1e a0 1c a1 1a " +
+				"1a 02 4c 44 1a 14 4c 4c 4e 30 24 52 50 24 72 63 62 30 31 24 52 70 74 45 6e 61 a0 03 83 01 00",
+			Responses: []string{rcbWriteOK},
+		},
+		// RptID := "rpt"
+		mmstest.Exchange{
+			Request: "03 00 00 43 02 f0 80 01 00 01 00 61 36 30 34 02 01 03 a0 2f a0 2d 02 01 xx a5 28 a0 1f 30 1d a0 1b a1 19 " +
+				"1a 02 4c 44 1a 13 4c 4c 4e 30 24 52 50 24 72 63 62 30 31 24 52 70 74 49 44 a0 05 8a 03 72 70 74",
+			Responses: []string{rcbWriteOK},
+		},
+		// DatSet := "LD/LLN0$DS"
+		mmstest.Exchange{
+			Request: "03 00 00 4b 02 f0 80 01 00 01 00 61 3e 30 3c 02 01 03 a0 37 a0 35 02 01 xx a5 30 a0 20 30 1e a0 1c a1 1a " +
+				"1a 02 4c 44 1a 14 4c 4c 4e 30 24 52 50 24 72 63 62 30 31 24 44 61 74 53 65 74 a0 0c 8a 0a 4c 44 2f 4c 4c 4e 30 24 44 53",
+			Responses: []string{rcbWriteOK},
+		},
+		// OptFlds := sequence-number|reason-for-inclusion|data-set-name
+		mmstest.Exchange{
+			Request: "03 00 00 45 02 f0 80 01 00 01 00 61 38 30 36 02 01 03 a0 31 a0 2f 02 01 xx a5 2a a0 21 30 1f a0 1d a1 1b " +
+				"1a 02 4c 44 1a 15 4c 4c 4e 30 24 52 50 24 72 63 62 30 31 24 4f 70 74 46 6c 64 73 a0 05 84 03 06 58 00",
+			Responses: []string{rcbWriteOK},
+		},
+		// RptEna := true
+		mmstest.Exchange{
+			Request: "03 00 00 42 02 f0 80 01 00 01 00 61 35 30 33 02 01 03 a0 2e a0 2c 02 01 xx a5 27 a0 20 30 1e a0 1c a1 1a " +
+				"1a 02 4c 44 1a 14 4c 4c 4e 30 24 52 50 24 72 63 62 30 31 24 52 70 74 45 6e 61 a0 03 83 01 ff",
+			Responses: []string{rcbWriteOK},
+		},
+		// GI := true
+		mmstest.Exchange{
+			Request: "03 00 00 3e 02 f0 80 01 00 01 00 61 31 30 2f 02 01 03 a0 2a a0 28 02 01 xx a5 23 a0 1c 30 1a a0 18 a1 16 " +
+				"1a 02 4c 44 1a 10 4c 4c 4e 30 24 52 50 24 72 63 62 30 31 24 47 49 a0 03 83 01 ff",
+			Responses: []string{rcbWriteOK},
+		},
+		// Повторная настройка: сервер отклоняет запись RptEna
+		mmstest.Exchange{
+			Request: "03 00 00 42 02 f0 80 01 00 01 00 61 35 30 33 02 01 03 a0 2e a0 2c 02 01 xx a5 27 a0 20 30 1e a0 1c a1 1a " +
+				"1a 02 4c 44 1a 14 4c 4c 4e 30 24 52 50 24 72 63 62 30 31 24 52 70 74 45 6e 61 a0 03 83 01 00",
+			Responses: []string{"03 00 00 1e 02 f0 80 01 00 01 00 61 11 30 0f 02 01 03 a0 0a a1 08 02 01 01 a5 03 80 01 03"},
+		},
+	)
+	conn := server.Dial()
+	ctx := context.Background()
+	client, err := NewMmsClient(ctx, conn)
+	assert.NoError(t, err)
+	_, err = client.Initiate(ctx)
+	assert.NoError(t, err)
+
+	rptID, datSet := "rpt", "LD/LLN0$DS"
+	optFlds := OptSeqNum | OptReasonCode | OptDataSet
+	err = client.ConfigureRCB(ctx, "LD/LLN0.RP.rcb01", RCBSettings{RptID: &rptID, DatSet: &datSet, OptFlds: &optFlds, GI: true})
+	assert.NoError(t, err)
+
+	err = client.ConfigureRCB(ctx, "LD/LLN0$RP$rcb01", RCBSettings{DatSet: &datSet})
+	assert.ErrorContains(t, err, "write RptEna")
+
+	conn.Close()
+	assert.NoError(t, server.Wait())
+
+	err = client.ConfigureRCB(ctx, "LD/GGIO1.ST.Ind1", RCBSettings{})
+	assert.ErrorContains(t, err, "not a report control block")
+}
+
+func TestOptFldsBitString(t *testing.T) {
+	o := OptSeqNum | OptDataSet | OptConfRev
+	v := o.BitString()
+	assert.Equal(t, 10, v.BitString().BitSize)
+	assert.Equal(t, o, OptFldsFromVariant(v))
+	_, err := mms.EncodeData(v)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,29 @@
+package go61850
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrReadOnlyClient возвращается операциями записи, управления и передачи файлов
+// на сервер клиента, созданного с WithReadOnly. Запрос на сервер не отправляется.
+var ErrReadOnlyClient = errors.New("read-only client")
+
+// WithReadOnly запрещает клиенту изменять состояние сервера: запись переменных
+// (в том числе настройку RCB в ConfigureRCB и Subscriptions), Select, SelectWithValue,
+// Operate и ObtainFile завершаются ошибкой ErrReadOnlyClient без обращения к серверу.
+// Предназначен для систем мониторинга, где случайная запись в устройства защиты
+// недопустима; отчёты в этом режиме принимаются только от заранее включённых RCB.
+func WithReadOnly() MmsClientOption {
+	return func(c *MmsClient) {
+		c.readOnly = true
+	}
+}
+
+// checkWritable возвращает ErrReadOnlyClient для операции operation клиента WithReadOnly
+func (c *MmsClient) checkWritable(operation string) error {
+	if c.readOnly {
+		return fmt.Errorf("%w: %s", ErrReadOnlyClient, operation)
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package go61850
+
+import (
+	"context"
+	"testing"
+
+	"github.com/slonegd/go61850/osi/mms"
+	"github.com/slonegd/go61850/osi/mms/variant"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithReadOnly(t *testing.T) {
+	ctx := context.Background()
+	c := &MmsClient{logger: defaultLogger()}
+	WithReadOnly()(c)
+
+	// Операции отклоняются до проверки соединения
+	err := c.Operate(ctx, "LD0/CSWI1.Pos", variant.NewBoolVariant(true))
+	assert.ErrorIs(t, err, ErrReadOnlyClient)
+	assert.EqualError(t, err, "read-only client: write LD0/CSWI1$CO$Pos$Oper")
+	assert.ErrorIs(t, c.Select(ctx, "LD0/CSWI1.Pos"), ErrReadOnlyClient)
+	assert.ErrorIs(t, c.SelectWithValue(ctx, "LD0/CSWI1.Pos", variant.NewBoolVariant(true)), ErrReadOnlyClient)
+	assert.ErrorIs(t, c.ConfigureRCB(ctx, "LD0/LLN0.RP.EventsRCB", RCBSettings{}), ErrReadOnlyClient)
+	assert.ErrorIs(t, c.ObtainFile(ctx, "local.cid", "IED.cid"), ErrReadOnlyClient)
+
+	// Чтение не ограничено
+	_, err = c.ReadObject(ctx, &mms.ReadRequest{DomainID: "LD0", ItemID: "GGIO1$MX$AnIn1"})
+	assert.EqualError(t, err, "connection not established, call Initiate first")
+}
@@ -0,0 +1,228 @@
+package go61850
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// Backoff задаёт экспоненциальную задержку между попытками установить ассоциацию
+type Backoff struct {
+	// Initial - задержка после первой неудачной попытки; нулевая заменяется
+	// DefaultBackoff.Initial, чтобы переподключение не шло без пауз
+	Initial time.Duration
+	// Max - верхняя граница задержки без учёта разброса; нулевая заменяется
+	// DefaultBackoff.Max (но не меньше Initial)
+	Max time.Duration
+	// Multiplier - множитель задержки после каждой следующей неудачи
+	Multiplier float64
+	// Jitter - доля случайного разброса задержки (0..1): при 0.2 задержка
+	// выбирается в пределах ±20%. Разброс не даёт парку шлюзов, потерявших связь
+	// одновременно, переподключаться к IED одной волной.
+	Jitter float64
+}
+
+// DefaultBackoff - задержка по умолчанию: 1 с, 2 с, 4 с ... до 1 минуты, разброс ±20%
+var DefaultBackoff = Backoff{Initial: time.Second, Max: time.Minute, Multiplier: 2, Jitter: 0.2}
+
+// maxBackoffExponent ограничивает показатель степени Multiplier: задержка
+// достигает Max задолго до него, а math.Pow не переполняется до +Inf
+const maxBackoffExponent = 64
+
+// Delay возвращает задержку перед попыткой attempt+1 после attempt неудачных попыток
+// (attempt >= 1). random возвращает число из [0, 1) и определяет разброс.
+func (b Backoff) Delay(attempt int, random func() float64) time.Duration {
+	initial, limit := b.Initial, b.Max
+	if initial <= 0 {
+		initial = DefaultBackoff.Initial
+	}
+	if limit <= 0 {
+		limit = max(DefaultBackoff.Max, initial)
+	}
+	delay := float64(initial)
+	if attempt > 1 && b.Multiplier > 1 {
+		delay *= math.Pow(b.Multiplier, float64(min(attempt-1, maxBackoffExponent)))
+	}
+	delay = math.Min(delay, float64(limit))
+	if b.Jitter > 0 {
+		delay *= 1 + b.Jitter*(2*random()-1)
+	}
+	return time.Duration(math.Max(delay, 0))
+}
+
+// ReconnectEvent описывает результат одной попытки установить ассоциацию
+type ReconnectEvent struct {
+	// Attempt - номер попытки с начала Connect, начиная с 1
+	Attempt int
+	// Time - время завершения попытки
+	Time time.Time
+	// Err - ошибка попытки; nil при успешной установке ассоциации
+	Err error
+	// NextRetryAt - время следующей попытки; нулевое при успехе или если попыток больше не будет
+	NextRetryAt time.Time
+}
+
+// String возвращает строковое представление ReconnectEvent
+func (e ReconnectEvent) String() string {
+	if e.Err == nil {
+		return fmt.Sprintf("attempt %d: connected", e.Attempt)
+	}
+	if e.NextRetryAt.IsZero() {
+		return fmt.Sprintf("attempt %d: %v, giving up", e.Attempt, e.Err)
+	}
+	return fmt.Sprintf("attempt %d: %v, next retry at %s", e.Attempt, e.Err, e.NextRetryAt.Format(time.RFC3339))
+}
+
+// ReconnectMetrics - накопленная статистика попыток установить ассоциацию
+type ReconnectMetrics struct {
+	Attempts  uint64
+	Successes uint64
+	Failures  uint64
+	// LastError - ошибка последней неудачной попытки
+	LastError error
+	// LastConnected - время последней успешной установки ассоциации
+	LastConnected time.Time
+	// NextRetryAt - время ближайшей запланированной попытки; нулевое, если её нет
+	NextRetryAt time.Time
+}
+
+// ConnectFunc устанавливает соединение и ассоциацию, например Dial и Initiate
+type ConnectFunc func(ctx context.Context) (*MmsClient, error)
+
+// Reconnector устанавливает ассоциацию с повторными попытками и сообщает об их
+// результатах, чтобы внешний уровень мог показать состояние связи.
+// Безопасен для конкурентного использования.
+type Reconnector struct {
+	connect     ConnectFunc
+	backoff     Backoff
+	maxAttempts int
+	onEvent     func(ReconnectEvent)
+	onConnect   func(ctx context.Context, client *MmsClient) error
+	random      func() float64
+
+	mu      sync.Mutex
+	metrics ReconnectMetrics
+}
+
+// ReconnectOption представляет опцию для настройки Reconnector
+type ReconnectOption func(*Reconnector)
+
+// WithBackoff задаёт задержку между попытками (по умолчанию DefaultBackoff)
+func WithBackoff(b Backoff) ReconnectOption {
+	return func(r *Reconnector) {
+		r.backoff = b
+	}
+}
+
+// WithMaxAttempts ограничивает число попыток одного вызова Connect; 0 - без ограничения
+func WithMaxAttempts(n int) ReconnectOption {
+	return func(r *Reconnector) {
+		r.maxAttempts = n
+	}
+}
+
+// WithReconnectEvents задаёт обработчик результата каждой попытки.
+// Обработчик вызывается синхронно и не должен блокироваться надолго;
+// для передачи в канал используйте неблокирующую отправку.
+func WithReconnectEvents(onEvent func(ReconnectEvent)) ReconnectOption {
+	return func(r *Reconnector) {
+		r.onEvent = onEvent
+	}
+}
+
+// WithOnConnect задаёт действие после установки ассоциации, например
+// восстановление подписок на отчёты (Subscriptions.Resume). Ошибка onConnect
+// считается неудачной попыткой: соединение закрывается и попытка повторяется.
+func WithOnConnect(onConnect func(ctx context.Context, client *MmsClient) error) ReconnectOption {
+	return func(r *Reconnector) {
+		r.onConnect = onConnect
+	}
+}
+
+// NewReconnector создаёт Reconnector, устанавливающий ассоциацию функцией connect
+func NewReconnector(connect ConnectFunc, opts ...ReconnectOption) *Reconnector {
+	r := &Reconnector{
+		connect: connect,
+		backoff: DefaultBackoff,
+		random:  rand.Float64,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Connect вызывает connect до успешной установки ассоциации, отмены ctx
+// или исчерпания WithMaxAttempts, выдерживая между попытками задержку Backoff.
+// Возвращает ошибку последней попытки или ошибку контекста.
+func (r *Reconnector) Connect(ctx context.Context) (*MmsClient, error) {
+	for attempt := 1; ; attempt++ {
+		client, err := r.connect(ctx)
+		if err == nil && client.valueCache != nil {
+			// Значения прежней ассоциации не подтверждены новой до чтения или отчёта
+			client.valueCache.ConnectionLost()
+		}
+		if err == nil && r.onConnect != nil {
+			if err = r.onConnect(ctx, client); err != nil && client.conn != nil {
+				client.conn.Close()
+			}
+		}
+		now := time.Now()
+		if err == nil {
+			r.record(ReconnectEvent{Attempt: attempt, Time: now})
+			return client, nil
+		}
+
+		event := ReconnectEvent{Attempt: attempt, Time: now, Err: err}
+		giveUp := ctx.Err() != nil || (r.maxAttempts > 0 && attempt >= r.maxAttempts)
+		var delay time.Duration
+		if !giveUp {
+			delay = r.backoff.Delay(attempt, r.random)
+			event.NextRetryAt = now.Add(delay)
+		}
+		r.record(event)
+		if giveUp {
+			return nil, err
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			r.mu.Lock()
+			r.metrics.NextRetryAt = time.Time{}
+			r.mu.Unlock()
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// Metrics возвращает накопленную статистику попыток
+func (r *Reconnector) Metrics() ReconnectMetrics {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.metrics
+}
+
+// record учитывает попытку в статистике и передаёт событие обработчику
+func (r *Reconnector) record(event ReconnectEvent) {
+	r.mu.Lock()
+	r.metrics.Attempts++
+	if event.Err == nil {
+		r.metrics.Successes++
+		r.metrics.LastConnected = event.Time
+	} else {
+		r.metrics.Failures++
+		r.metrics.LastError = event.Err
+	}
+	r.metrics.NextRetryAt = event.NextRetryAt
+	r.mu.Unlock()
+
+	if r.onEvent != nil {
+		r.onEvent(event)
+	}
+}
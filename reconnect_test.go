@@ -0,0 +1,145 @@
+package go61850
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/slonegd/go61850/osi/mms/variant"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackoffDelay(t *testing.T) {
+	b := Backoff{Initial: time.Second, Max: 10 * time.Second, Multiplier: 2, Jitter: 0.2}
+	middle := func() float64 { return 0.5 }
+	assert.Equal(t, time.Second, b.Delay(1, middle))
+	assert.Equal(t, 2*time.Second, b.Delay(2, middle))
+	assert.Equal(t, 8*time.Second, b.Delay(4, middle))
+	assert.Equal(t, 10*time.Second, b.Delay(5, middle))
+	assert.Equal(t, 10*time.Second, b.Delay(100, middle))
+
+	// Разброс ±20% вокруг задержки
+	assert.Equal(t, 1600*time.Millisecond, b.Delay(2, func() float64 { return 0 }))
+	assert.Equal(t, 2400*time.Millisecond, b.Delay(2, func() float64 { return 1 }))
+
+	assert.Equal(t, time.Second, Backoff{Initial: time.Second}.Delay(3, middle))
+
+	// Без Max задержка ограничена DefaultBackoff.Max, без Initial - не нулевая
+	assert.Equal(t, time.Minute, Backoff{Initial: time.Second, Multiplier: 2}.Delay(10000, middle))
+	assert.Equal(t, time.Hour, Backoff{Initial: time.Hour, Multiplier: 2}.Delay(5, middle))
+	assert.Equal(t, time.Second, Backoff{}.Delay(1, middle))
+	assert.Equal(t, time.Minute, Backoff{Multiplier: 1e300}.Delay(3, middle))
+}
+
+func TestReconnector(t *testing.T) {
+	errRefused := errors.New("connection refused")
+	attempts := 0
+	connect := func(ctx context.Context) (*MmsClient, error) {
+		attempts++
+		if attempts < 3 {
+			return nil, errRefused
+		}
+		return &MmsClient{}, nil
+	}
+
+	var events []ReconnectEvent
+	r := NewReconnector(connect,
+		WithBackoff(Backoff{Initial: time.Millisecond, Multiplier: 2}),
+		WithReconnectEvents(func(e ReconnectEvent) { events = append(events, e) }),
+	)
+	client, err := r.Connect(context.Background())
+	assert.NoError(t, err)
+	assert.NotNil(t, client)
+
+	assert.Len(t, events, 3)
+	assert.ErrorIs(t, events[0].Err, errRefused)
+	assert.Equal(t, time.Millisecond, events[0].NextRetryAt.Sub(events[0].Time))
+	assert.Equal(t, 2*time.Millisecond, events[1].NextRetryAt.Sub(events[1].Time))
+	assert.NoError(t, events[2].Err)
+	assert.True(t, events[2].NextRetryAt.IsZero())
+	assert.Equal(t, "attempt 3: connected", events[2].String())
+
+	metrics := r.Metrics()
+	assert.Equal(t, uint64(3), metrics.Attempts)
+	assert.Equal(t, uint64(1), metrics.Successes)
+	assert.Equal(t, uint64(2), metrics.Failures)
+	assert.ErrorIs(t, metrics.LastError, errRefused)
+	assert.Equal(t, events[2].Time, metrics.LastConnected)
+	assert.True(t, metrics.NextRetryAt.IsZero())
+}
+
+func TestReconnectorGiveUp(t *testing.T) {
+	errRefused := errors.New("connection refused")
+	connect := func(ctx context.Context) (*MmsClient, error) { return nil, errRefused }
+
+	var last ReconnectEvent
+	r := NewReconnector(connect,
+		WithBackoff(Backoff{Initial: time.Millisecond}),
+		WithMaxAttempts(2),
+		WithReconnectEvents(func(e ReconnectEvent) { last = e }),
+	)
+	_, err := r.Connect(context.Background())
+	assert.ErrorIs(t, err, errRefused)
+	assert.Equal(t, 2, last.Attempt)
+	assert.Equal(t, "attempt 2: connection refused, giving up", last.String())
+	assert.Equal(t, uint64(2), r.Metrics().Failures)
+
+	// Отмена контекста прерывает ожидание следующей попытки
+	r = NewReconnector(connect, WithBackoff(Backoff{Initial: time.Hour}))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = r.Connect(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.True(t, r.Metrics().NextRetryAt.IsZero())
+}
+
+func TestReconnectorOnConnect(t *testing.T) {
+	errResume := errors.New("resume failed")
+	var conns []net.Conn
+	connect := func(ctx context.Context) (*MmsClient, error) {
+		conn, peer := net.Pipe()
+		peer.Close()
+		conns = append(conns, conn)
+		return &MmsClient{conn: conn}, nil
+	}
+	calls := 0
+	r := NewReconnector(connect,
+		WithBackoff(Backoff{Initial: time.Millisecond}),
+		WithOnConnect(func(ctx context.Context, client *MmsClient) error {
+			calls++
+			if calls == 1 {
+				return errResume
+			}
+			return nil
+		}),
+	)
+	client, err := r.Connect(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, conns[1], client.conn)
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, uint64(1), r.Metrics().Failures)
+	assert.ErrorIs(t, r.Metrics().LastError, errResume)
+
+	// Соединение неудачной попытки закрыто
+	_, err = conns[0].Write([]byte{0})
+	assert.ErrorIs(t, err, io.ErrClosedPipe)
+}
+
+func TestReconnectorValueCache(t *testing.T) {
+	cache := NewValueCache()
+	cache.Update("LD/GGIO1$ST$Ind1$stVal", variant.NewBoolVariant(true))
+	r := NewReconnector(func(ctx context.Context) (*MmsClient, error) {
+		client := &MmsClient{}
+		WithValueCache(cache)(client)
+		return client, nil
+	})
+	_, err := r.Connect(context.Background())
+	assert.NoError(t, err)
+
+	// Значение прежней ассоциации не подтверждено новой
+	value, _ := cache.Get("LD/GGIO1$ST$Ind1$stVal")
+	assert.Equal(t, StaleConnectionLost, value.Reason)
+}
@@ -0,0 +1,351 @@
+package go61850
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/slonegd/go61850/model"
+	"github.com/slonegd/go61850/osi/mms"
+	"github.com/slonegd/go61850/osi/mms/variant"
+)
+
+// OptFlds представляет необязательные поля отчёта согласно IEC 61850-8-1
+type OptFlds uint16
+
+const (
+	OptSeqNum OptFlds = 1 << iota
+	OptTimeOfEntry
+	OptReasonCode
+	OptDataSet
+	OptDataRef
+	OptBufOvfl
+	OptEntryID
+	OptConfRev
+	OptSegmentation
+)
+
+// optFldsNames - имена полей в порядке битов OptFlds (бит 0 зарезервирован)
+var optFldsNames = []string{
+	"sequence-number", "report-time-stamp", "reason-for-inclusion", "data-set-name",
+	"data-reference", "buffer-overflow", "entryID", "conf-revision", "segmentation",
+}
+
+// OptFldsFromVariant декодирует OptFlds из bit-string
+func OptFldsFromVariant(v *variant.Variant) OptFlds {
+	bs := v.BitString()
+	var o OptFlds
+	for i := range optFldsNames {
+		bit := i + 1
+		if bit < bs.BitSize && bit/8 < len(bs.Data) && bs.Data[bit/8]&(0x80>>(bit%8)) != 0 {
+			o |= 1 << i
+		}
+	}
+	return o
+}
+
+// BitString кодирует OptFlds как bit-string из 10 бит (бит 0 зарезервирован)
+func (o OptFlds) BitString() *variant.Variant {
+	data := make([]byte, 2)
+	for i := range optFldsNames {
+		bit := i + 1
+		if o&(1<<i) != 0 {
+			data[bit/8] |= 0x80 >> (bit % 8)
+		}
+	}
+	return variant.NewBitStringVariant(data, len(optFldsNames)+1)
+}
+
+// Has проверяет наличие поля
+func (o OptFlds) Has(f OptFlds) bool { return o&f == f }
+
+// String возвращает поля через "|"
+func (o OptFlds) String() string {
+	var names []string
+	for i, name := range optFldsNames {
+		if o&(1<<i) != 0 {
+			names = append(names, name)
+		}
+	}
+	return strings.Join(names, "|")
+}
+
+// Report представляет отчёт, принятый от сервера (InformationReport "RPT").
+// Values, Reasons и DataRefs выровнены по элементам набора данных:
+// для невключённых элементов значения пустые.
+type Report struct {
+	RptID   string
+	OptFlds OptFlds
+	SeqNum  uint32
+	// TimeOfEntry - время формирования отчёта, если передано
+	TimeOfEntry *model.TimeOfEntry
+	DataSet     string
+	BufOvfl     bool
+	EntryID     model.EntryID
+	ConfRev     uint32
+	// SubSeqNum и MoreSegmentsFollow - признаки сегментации
+	SubSeqNum          uint32
+	MoreSegmentsFollow bool
+	// Inclusion - включённые элементы набора данных
+	Inclusion []bool
+	DataRefs  []string
+	Values    []*variant.Variant
+	Reasons   []model.Trigger
+}
+
+// IsSegmented возвращает true, если отчёт является сегментом
+func (r *Report) IsSegmented() bool {
+	return r.MoreSegmentsFollow || r.SubSeqNum != 0
+}
+
+// ParseReport разбирает отчёт из InformationReport с именем списка "RPT".
+// quirks учитывают отклонения устройства (QuirkReportSegmentationUnset,
+// QuirkReportEntryIDAlwaysPresent). Неуспешные элементы отчёта
+// перечисляются в *mms.ReadError.
+func ParseReport(pdu *mms.InformationReportPDU, quirks Quirk) (*Report, error) {
+	if pdu.VariableListName == nil || pdu.VariableListName.DomainID != "" || pdu.VariableListName.ItemID != "RPT" {
+		return nil, errors.New("information report is not a report (RPT)")
+	}
+	if readErr := mms.NewReadError(pdu.Results, nil); readErr != nil {
+		return nil, fmt.Errorf("report: %w", readErr)
+	}
+	values := make([]*variant.Variant, len(pdu.Results))
+	for i, result := range pdu.Results {
+		values[i] = result.Value
+	}
+
+	p := reportParser{values: values}
+	r := &Report{}
+	r.RptID = p.next(variant.VisibleString, "RptID").StringValue()
+	r.OptFlds = OptFldsFromVariant(p.next(variant.BitString, "OptFlds"))
+	if r.OptFlds.Has(OptSeqNum) {
+		r.SeqNum = p.next(variant.Uint32, "SeqNum").Uint32()
+	}
+	if r.OptFlds.Has(OptTimeOfEntry) {
+		t, err := model.ParseTimeOfEntry(p.next(variant.BinaryTime, "TimeOfEntry").OctetString())
+		if err != nil && p.err == nil {
+			p.err = err
+		}
+		r.TimeOfEntry = &t
+	}
+	if r.OptFlds.Has(OptDataSet) {
+		r.DataSet = p.next(variant.VisibleString, "DatSet").StringValue()
+	}
+	if r.OptFlds.Has(OptBufOvfl) {
+		r.BufOvfl = p.next(variant.Bool, "BufOvfl").Bool()
+	}
+	if r.OptFlds.Has(OptEntryID) || quirks&QuirkReportEntryIDAlwaysPresent != 0 && p.peekOctets(model.EntryIDSize) {
+		id, err := model.NewEntryID(p.next(variant.OctetString, "EntryID").OctetString())
+		if err != nil && p.err == nil {
+			p.err = err
+		}
+		r.EntryID = id
+	}
+	if r.OptFlds.Has(OptConfRev) {
+		r.ConfRev = p.next(variant.Uint32, "ConfRev").Uint32()
+	}
+	if r.OptFlds.Has(OptSegmentation) || quirks&QuirkReportSegmentationUnset != 0 && p.peekSegmentation() {
+		r.SubSeqNum = p.next(variant.Uint32, "SubSeqNum").Uint32()
+		r.MoreSegmentsFollow = p.next(variant.Bool, "MoreSegmentsFollow").Bool()
+	}
+
+	inclusion := p.next(variant.BitString, "Inclusion").BitString()
+	if p.err != nil {
+		return nil, p.err
+	}
+	members := inclusion.BitSize
+	r.Inclusion = make([]bool, members)
+	var included []int
+	for i := range members {
+		if i/8 < len(inclusion.Data) && inclusion.Data[i/8]&(0x80>>(i%8)) != 0 {
+			r.Inclusion[i] = true
+			included = append(included, i)
+		}
+	}
+
+	r.DataRefs = make([]string, members)
+	r.Values = make([]*variant.Variant, members)
+	r.Reasons = make([]model.Trigger, members)
+	if r.OptFlds.Has(OptDataRef) {
+		for _, i := range included {
+			r.DataRefs[i] = p.next(variant.VisibleString, "data reference").StringValue()
+		}
+	}
+	for _, i := range included {
+		r.Values[i] = p.nextAny("value")
+	}
+	if r.OptFlds.Has(OptReasonCode) {
+		for _, i := range included {
+			r.Reasons[i] = model.TriggerFromBitString(p.next(variant.BitString, "ReasonCode"))
+		}
+	}
+	if p.err != nil {
+		return nil, p.err
+	}
+	if p.pos != len(values) {
+		return nil, fmt.Errorf("report %s: %d unexpected trailing elements", r.RptID, len(values)-p.pos)
+	}
+	return r, nil
+}
+
+// reportParser последовательно читает элементы отчёта; первая ошибка сохраняется в err
+type reportParser struct {
+	values []*variant.Variant
+	pos    int
+	err    error
+}
+
+// nextAny возвращает следующий элемент любого типа
+func (p *reportParser) nextAny(what string) *variant.Variant {
+	if p.err != nil {
+		return nil
+	}
+	if p.pos >= len(p.values) {
+		p.err = fmt.Errorf("report truncated: missing %s", what)
+		return nil
+	}
+	p.pos++
+	return p.values[p.pos-1]
+}
+
+// next возвращает следующий элемент, проверяя его тип
+func (p *reportParser) next(typ variant.Type, what string) *variant.Variant {
+	v := p.nextAny(what)
+	if v != nil && v.Type() != typ {
+		p.err = fmt.Errorf("report %s: expected %s, got %s", what, typ, v.Type())
+		return nil
+	}
+	return v
+}
+
+// peekOctets проверяет, что следующий элемент - octet-string длины n
+func (p *reportParser) peekOctets(n int) bool {
+	return p.pos < len(p.values) && p.values[p.pos].Type() == variant.OctetString &&
+		len(p.values[p.pos].OctetString()) == n
+}
+
+// peekSegmentation проверяет, что далее следуют SubSeqNum, MoreSegmentsFollow и Inclusion
+func (p *reportParser) peekSegmentation() bool {
+	return p.pos+2 < len(p.values) && p.values[p.pos].Type() == variant.Uint32 &&
+		p.values[p.pos+1].Type() == variant.Bool && p.values[p.pos+2].Type() == variant.BitString
+}
+
+// DefaultSegmentTimeout - время ожидания оставшихся сегментов отчёта по умолчанию
+const DefaultSegmentTimeout = 10 * time.Second
+
+// ReportAssembler собирает сегментированные отчёты (SubSeqNum, MoreSegmentsFollow)
+// в один логический отчёт. Незавершённая последовательность отбрасывается при
+// истечении таймаута, нарушении порядка сегментов или начале новой.
+// Безопасен для конкурентного использования.
+type ReportAssembler struct {
+	mu        sync.Mutex
+	timeout   time.Duration
+	now       func() time.Time
+	pending   map[string]*segmentedReport
+	discarded uint64
+}
+
+// segmentedReport - собираемый отчёт
+type segmentedReport struct {
+	report  *Report
+	nextSub uint32
+	started time.Time
+}
+
+// NewReportAssembler создаёт сборщик с таймаутом timeout (0 - DefaultSegmentTimeout)
+func NewReportAssembler(timeout time.Duration) *ReportAssembler {
+	if timeout <= 0 {
+		timeout = DefaultSegmentTimeout
+	}
+	return &ReportAssembler{timeout: timeout, now: time.Now, pending: make(map[string]*segmentedReport)}
+}
+
+// Add учитывает отчёт или сегмент и возвращает собранный отчёт,
+// если он завершён, иначе nil
+func (a *ReportAssembler) Add(r *Report) *Report {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	now := a.now()
+	a.expire(now)
+
+	if !r.IsSegmented() {
+		return r
+	}
+
+	s, ok := a.pending[r.RptID]
+	if ok && (r.SubSeqNum == 0 || r.SeqNum != s.report.SeqNum || r.SubSeqNum != s.nextSub ||
+		len(r.Inclusion) != len(s.report.Inclusion)) {
+		// Новая последовательность или потерянный сегмент
+		delete(a.pending, r.RptID)
+		a.discarded++
+		ok = false
+	}
+	if !ok {
+		if r.SubSeqNum != 0 {
+			// Начало последовательности потеряно
+			a.discarded++
+			return nil
+		}
+		s = &segmentedReport{report: r, started: now}
+		a.pending[r.RptID] = s
+	} else {
+		mergeReport(s.report, r)
+	}
+	s.nextSub = r.SubSeqNum + 1
+
+	if r.MoreSegmentsFollow {
+		return nil
+	}
+	delete(a.pending, r.RptID)
+	complete := s.report
+	complete.SubSeqNum = 0
+	complete.MoreSegmentsFollow = false
+	return complete
+}
+
+// mergeReport переносит включённые элементы сегмента src в dst
+func mergeReport(dst, src *Report) {
+	for i, included := range src.Inclusion {
+		if !included {
+			continue
+		}
+		dst.Inclusion[i] = true
+		dst.DataRefs[i] = src.DataRefs[i]
+		dst.Values[i] = src.Values[i]
+		dst.Reasons[i] = src.Reasons[i]
+	}
+	if src.BufOvfl {
+		dst.BufOvfl = true
+	}
+	if !src.EntryID.IsZero() {
+		dst.EntryID = src.EntryID
+	}
+}
+
+// Expire отбрасывает последовательности, не завершённые за таймаут.
+// Вызывается периодически, если сегменты могут перестать приходить.
+func (a *ReportAssembler) Expire() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.expire(a.now())
+}
+
+// expire отбрасывает просроченные последовательности
+func (a *ReportAssembler) expire(now time.Time) {
+	for rptID, s := range a.pending {
+		if now.Sub(s.started) > a.timeout {
+			delete(a.pending, rptID)
+			a.discarded++
+		}
+	}
+}
+
+// Discarded возвращает число отброшенных незавершённых последовательностей
+// и сегментов, пришедших без начала последовательности
+func (a *ReportAssembler) Discarded() uint64 {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.discarded
+}
@@ -0,0 +1,143 @@
+package go61850
+
+import (
+	"testing"
+	"time"
+
+	"github.com/slonegd/go61850/model"
+	"github.com/slonegd/go61850/osi/mms"
+	"github.com/slonegd/go61850/osi/mms/variant"
+	"github.com/stretchr/testify/assert"
+)
+
+// reportPDU кодирует и разбирает InformationReport "RPT" с элементами values
+func reportPDU(t *testing.T, values ...*variant.Variant) *mms.InformationReportPDU {
+	pdu := &mms.InformationReportPDU{VariableListName: &mms.ObjectName{ItemID: "RPT"}}
+	for _, v := range values {
+		pdu.Results = append(pdu.Results, mms.AccessResult{Success: true, Value: v})
+	}
+	encoded, err := pdu.Bytes()
+	assert.NoError(t, err)
+	parsed, err := mms.ParseInformationReport(encoded)
+	assert.NoError(t, err)
+	return parsed
+}
+
+// segment создаёт сегмент отчёта "Meas" набора из 3 элементов.
+// OptFlds: seq-num, reason-for-inclusion, segmentation.
+func segment(t *testing.T, seqNum, subSeqNum uint32, more bool, member int, value float32) *mms.InformationReportPDU {
+	inclusion := []byte{0x80 >> member}
+	return reportPDU(t,
+		variant.NewVisibleStringVariant("Meas"),
+		variant.NewBitStringVariant([]byte{0x50, 0x40}, 10),
+		variant.NewUint32Variant(seqNum),
+		variant.NewUint32Variant(subSeqNum),
+		variant.NewBoolVariant(more),
+		variant.NewBitStringVariant(inclusion, 3),
+		variant.NewFloat32Variant(value),
+		model.TriggerDataChange.BitString(7),
+	)
+}
+
+func TestParseReport(t *testing.T) {
+	tm := model.NewTimeOfEntry(time.Date(2026, 1, 5, 11, 21, 52, 0, time.UTC))
+	pdu := reportPDU(t,
+		variant.NewVisibleStringVariant("Events"),
+		// seq-num, report-time-stamp, data-set-name, data-reference, entryID, conf-revision
+		variant.NewBitStringVariant([]byte{0x6d, 0x80}, 10),
+		variant.NewUint32Variant(7),
+		variant.NewBinaryTimeVariant(tm.Bytes()),
+		variant.NewVisibleStringVariant("IEDLD0/LLN0$Events"),
+		variant.NewOctetStringVariant([]byte{0, 0, 0, 0, 0, 0, 0, 9}),
+		variant.NewUint32Variant(1),
+		variant.NewBitStringVariant([]byte{0x40}, 2),
+		variant.NewVisibleStringVariant("IEDLD0/GGIO1$ST$Ind2$stVal"),
+		variant.NewBoolVariant(true),
+	)
+	r, err := ParseReport(pdu, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, "sequence-number|report-time-stamp|data-set-name|data-reference|entryID|conf-revision", r.OptFlds.String())
+	assert.Equal(t, uint32(7), r.SeqNum)
+	assert.Equal(t, &tm, r.TimeOfEntry)
+	assert.Equal(t, "IEDLD0/LLN0$Events", r.DataSet)
+	assert.Equal(t, "0000000000000009", r.EntryID.String())
+	assert.Equal(t, uint32(1), r.ConfRev)
+	assert.Equal(t, []bool{false, true}, r.Inclusion)
+	assert.Equal(t, []string{"", "IEDLD0/GGIO1$ST$Ind2$stVal"}, r.DataRefs)
+	assert.Nil(t, r.Values[0])
+	assert.True(t, r.Values[1].Bool())
+	assert.False(t, r.IsSegmented())
+
+	_, err = ParseReport(reportPDU(t, variant.NewVisibleStringVariant("Events")), 0)
+	assert.EqualError(t, err, "report truncated: missing OptFlds")
+	_, err = ParseReport(&mms.InformationReportPDU{Variables: []mms.ObjectName{{ItemID: "x"}}}, 0)
+	assert.EqualError(t, err, "information report is not a report (RPT)")
+
+	// Все неуспешные элементы перечисляются в одной ошибке
+	pdu = reportPDU(t, variant.NewVisibleStringVariant("Events"))
+	pdu.Results = append(pdu.Results,
+		mms.AccessResult{Error: &mms.DataAccessError{ErrorCode: mms.ObjectAccessDenied}},
+		mms.AccessResult{Error: &mms.DataAccessError{ErrorCode: mms.ObjectNonExistent}})
+	_, err = ParseReport(pdu, 0)
+	var readErr *mms.ReadError
+	assert.ErrorAs(t, err, &readErr)
+	assert.Len(t, readErr.Failures, 2)
+	assert.ErrorIs(t, err, &mms.DataAccessError{ErrorCode: mms.ObjectNonExistent})
+}
+
+func TestParseReportQuirks(t *testing.T) {
+	// сегментация и EntryID без соответствующих битов OptFlds
+	pdu := reportPDU(t,
+		variant.NewVisibleStringVariant("Meas"),
+		variant.NewBitStringVariant([]byte{0x40, 0x00}, 10),
+		variant.NewUint32Variant(3),
+		variant.NewOctetStringVariant([]byte{0, 0, 0, 0, 0, 0, 0, 1}),
+		variant.NewUint32Variant(0),
+		variant.NewBoolVariant(true),
+		variant.NewBitStringVariant([]byte{0x80}, 1),
+		variant.NewFloat32Variant(1),
+	)
+	_, err := ParseReport(pdu, 0)
+	assert.Error(t, err)
+
+	r, err := ParseReport(pdu, QuirkReportSegmentationUnset|QuirkReportEntryIDAlwaysPresent)
+	assert.NoError(t, err)
+	assert.True(t, r.MoreSegmentsFollow)
+	assert.Equal(t, "0000000000000001", r.EntryID.String())
+}
+
+func TestReportAssembler(t *testing.T) {
+	now := time.Date(2026, 1, 5, 11, 21, 52, 0, time.UTC)
+	a := NewReportAssembler(time.Second)
+	a.now = func() time.Time { return now }
+
+	add := func(pdu *mms.InformationReportPDU) *Report {
+		r, err := ParseReport(pdu, 0)
+		assert.NoError(t, err)
+		return a.Add(r)
+	}
+
+	assert.Nil(t, add(segment(t, 1, 0, true, 0, 10)))
+	assert.Nil(t, add(segment(t, 1, 1, true, 2, 30)))
+	r := add(segment(t, 1, 2, false, 1, 20))
+	assert.NotNil(t, r)
+	assert.Equal(t, []bool{true, true, true}, r.Inclusion)
+	assert.Equal(t, float32(20), r.Values[1].Float32())
+	assert.Equal(t, float32(30), r.Values[2].Float32())
+	assert.Equal(t, model.TriggerDataChange, r.Reasons[2])
+	assert.False(t, r.IsSegmented())
+
+	// пропущенный сегмент отбрасывает последовательность
+	assert.Nil(t, add(segment(t, 2, 0, true, 0, 10)))
+	assert.Nil(t, add(segment(t, 2, 2, false, 1, 20)))
+	// отброшены последовательность и сегмент без её начала
+	assert.Equal(t, uint64(2), a.Discarded())
+
+	// незавершённая последовательность отбрасывается по таймауту
+	assert.Nil(t, add(segment(t, 3, 0, true, 0, 10)))
+	now = now.Add(2 * time.Second)
+	a.Expire()
+	assert.Equal(t, uint64(3), a.Discarded())
+	assert.Nil(t, add(segment(t, 3, 1, false, 1, 20)))
+	assert.Equal(t, uint64(4), a.Discarded())
+}
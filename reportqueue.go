@@ -0,0 +1,225 @@
+package go61850
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/slonegd/go61850/osi/mms"
+)
+
+// ErrReportQueueClosed возвращается при обращении к закрытой очереди отчётов
+var ErrReportQueueClosed = errors.New("report queue closed")
+
+// DefaultReportQueueCapacity - ёмкость очереди отчётов по умолчанию
+const DefaultReportQueueCapacity = 256
+
+// OverflowPolicy определяет поведение очереди отчётов при заполнении
+type OverflowPolicy int
+
+const (
+	// OverflowDropOldest отбрасывает самый старый отчёт очереди
+	OverflowDropOldest OverflowPolicy = iota
+	// OverflowBlock приостанавливает приём до освобождения места.
+	// Пока потребитель не разберёт очередь, ответы ассоциации не читаются.
+	OverflowBlock
+	// OverflowCallback передаёт не поместившийся отчёт в обработчик
+	// (см. WithOverflowHandler) и не ставит его в очередь
+	OverflowCallback
+)
+
+// String возвращает название политики
+func (p OverflowPolicy) String() string {
+	switch p {
+	case OverflowDropOldest:
+		return "drop-oldest"
+	case OverflowBlock:
+		return "block"
+	case OverflowCallback:
+		return "callback"
+	default:
+		return "unknown"
+	}
+}
+
+// ReportQueueStats - метрики очереди отчётов
+type ReportQueueStats struct {
+	// Enqueued - количество отчётов, поставленных в очередь
+	Enqueued uint64
+	// Delivered - количество отчётов, полученных потребителем
+	Delivered uint64
+	// Dropped - количество отброшенных или переданных в обработчик переполнения отчётов
+	Dropped uint64
+	// Blocked - количество постановок, ожидавших освобождения места
+	Blocked uint64
+	// Len и MaxLen - текущая и максимальная длина очереди
+	Len    int
+	MaxLen int
+}
+
+// ReportQueueOption представляет опцию для настройки ReportQueue
+type ReportQueueOption func(*ReportQueue)
+
+// WithOverflowPolicy задаёт политику переполнения (по умолчанию OverflowDropOldest)
+func WithOverflowPolicy(p OverflowPolicy) ReportQueueOption {
+	return func(q *ReportQueue) {
+		q.policy = p
+	}
+}
+
+// WithOverflowHandler включает политику OverflowCallback с обработчиком fn.
+// fn вызывается в горутине приёма и не должен блокироваться.
+func WithOverflowHandler(fn func(*mms.InformationReportPDU)) ReportQueueOption {
+	return func(q *ReportQueue) {
+		q.policy = OverflowCallback
+		q.onOverflow = fn
+	}
+}
+
+// ReportQueue - ограниченная очередь InformationReport, принятых ассоциацией.
+// Защищает от исчерпания памяти при потоке отчётов, который приложение
+// не успевает обрабатывать. Безопасна для конкурентного использования.
+type ReportQueue struct {
+	mu         sync.Mutex
+	capacity   int
+	policy     OverflowPolicy
+	onOverflow func(*mms.InformationReportPDU)
+	items      []*mms.InformationReportPDU
+	// changed закрывается и заменяется при каждом изменении очереди
+	changed chan struct{}
+	closed  bool
+	stats   ReportQueueStats
+}
+
+// NewReportQueue создаёт очередь ёмкостью capacity отчётов (0 - DefaultReportQueueCapacity)
+func NewReportQueue(capacity int, opts ...ReportQueueOption) *ReportQueue {
+	if capacity <= 0 {
+		capacity = DefaultReportQueueCapacity
+	}
+	q := &ReportQueue{capacity: capacity, changed: make(chan struct{})}
+	for _, opt := range opts {
+		opt(q)
+	}
+	return q
+}
+
+// signal будит ожидающих; вызывается под mu
+func (q *ReportQueue) signal() {
+	close(q.changed)
+	q.changed = make(chan struct{})
+}
+
+// Push ставит отчёт в очередь согласно политике переполнения.
+// При OverflowBlock ожидает освобождения места до отмены ctx.
+func (q *ReportQueue) Push(ctx context.Context, report *mms.InformationReportPDU) error {
+	q.mu.Lock()
+	blocked := false
+	for !q.closed && len(q.items) >= q.capacity {
+		switch q.policy {
+		case OverflowBlock:
+			if !blocked {
+				blocked = true
+				q.stats.Blocked++
+			}
+			changed := q.changed
+			q.mu.Unlock()
+			select {
+			case <-changed:
+			case <-ctx.Done():
+				q.mu.Lock()
+				q.stats.Dropped++
+				q.mu.Unlock()
+				return ctx.Err()
+			}
+			q.mu.Lock()
+		case OverflowCallback:
+			q.stats.Dropped++
+			fn := q.onOverflow
+			q.mu.Unlock()
+			if fn != nil {
+				fn(report)
+			}
+			return nil
+		default:
+			q.items[0] = nil
+			q.items = q.items[1:]
+			q.stats.Dropped++
+		}
+	}
+	defer q.mu.Unlock()
+	if q.closed {
+		return ErrReportQueueClosed
+	}
+	q.items = append(q.items, report)
+	q.stats.Enqueued++
+	q.stats.MaxLen = max(q.stats.MaxLen, len(q.items))
+	q.signal()
+	return nil
+}
+
+// Receive возвращает следующий отчёт, ожидая его до отмены ctx.
+// После Close возвращает оставшиеся отчёты, затем ErrReportQueueClosed.
+func (q *ReportQueue) Receive(ctx context.Context) (*mms.InformationReportPDU, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for len(q.items) == 0 {
+		if q.closed {
+			return nil, ErrReportQueueClosed
+		}
+		changed := q.changed
+		q.mu.Unlock()
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			q.mu.Lock()
+			return nil, ctx.Err()
+		}
+		q.mu.Lock()
+	}
+	report := q.items[0]
+	q.items[0] = nil
+	q.items = q.items[1:]
+	q.stats.Delivered++
+	q.signal()
+	return report, nil
+}
+
+// Len возвращает число отчётов в очереди
+func (q *ReportQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.items)
+}
+
+// Stats возвращает метрики очереди
+func (q *ReportQueue) Stats() ReportQueueStats {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	stats := q.stats
+	stats.Len = len(q.items)
+	return stats
+}
+
+// Close закрывает очередь: новые отчёты не принимаются, ожидающие Push завершаются
+func (q *ReportQueue) Close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if !q.closed {
+		q.closed = true
+		q.signal()
+	}
+}
+
+// WithReportQueue направляет в очередь q InformationReport, не относящиеся
+// к выполняемым запросам (отчёты RCB и прочий незапрошенный трафик).
+// Без очереди такие отчёты отбрасываются.
+func WithReportQueue(q *ReportQueue) MmsClientOption {
+	return func(c *MmsClient) {
+		c.reports = q
+	}
+}
+
+// Reports возвращает очередь отчётов или nil, если она не задана (см. WithReportQueue)
+func (c *MmsClient) Reports() *ReportQueue {
+	return c.reports
+}
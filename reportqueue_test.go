@@ -0,0 +1,78 @@
+package go61850
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/slonegd/go61850/osi/mms"
+	"github.com/stretchr/testify/assert"
+)
+
+// rpt создаёт InformationReport с именем списка name
+func rpt(name string) *mms.InformationReportPDU {
+	return &mms.InformationReportPDU{VariableListName: &mms.ObjectName{ItemID: name}}
+}
+
+func TestReportQueueDropOldest(t *testing.T) {
+	ctx := context.Background()
+	q := NewReportQueue(2)
+	for _, name := range []string{"a", "b", "c"} {
+		assert.NoError(t, q.Push(ctx, rpt(name)))
+	}
+	r, err := q.Receive(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "b", r.VariableListName.ItemID)
+	assert.Equal(t, ReportQueueStats{Enqueued: 3, Delivered: 1, Dropped: 1, Len: 1, MaxLen: 2}, q.Stats())
+
+	q.Close()
+	r, err = q.Receive(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "c", r.VariableListName.ItemID)
+	_, err = q.Receive(ctx)
+	assert.ErrorIs(t, err, ErrReportQueueClosed)
+	assert.ErrorIs(t, q.Push(ctx, rpt("d")), ErrReportQueueClosed)
+}
+
+func TestReportQueueBlock(t *testing.T) {
+	ctx := context.Background()
+	q := NewReportQueue(1, WithOverflowPolicy(OverflowBlock))
+	assert.NoError(t, q.Push(ctx, rpt("a")))
+
+	done := make(chan error)
+	go func() { done <- q.Push(ctx, rpt("b")) }()
+	select {
+	case <-done:
+		t.Fatal("push must block while the queue is full")
+	case <-time.After(20 * time.Millisecond):
+	}
+	r, err := q.Receive(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "a", r.VariableListName.ItemID)
+	assert.NoError(t, <-done)
+	assert.Equal(t, uint64(1), q.Stats().Blocked)
+
+	timeout, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, q.Push(timeout, rpt("c")), context.DeadlineExceeded)
+	assert.Equal(t, uint64(1), q.Stats().Dropped)
+}
+
+func TestReportQueueCallback(t *testing.T) {
+	ctx := context.Background()
+	var overflow []string
+	q := NewReportQueue(1, WithOverflowHandler(func(r *mms.InformationReportPDU) {
+		overflow = append(overflow, r.VariableListName.ItemID)
+	}))
+	assert.NoError(t, q.Push(ctx, rpt("a")))
+	assert.NoError(t, q.Push(ctx, rpt("b")))
+	assert.Equal(t, []string{"b"}, overflow)
+	assert.Equal(t, 1, q.Len())
+	assert.Equal(t, "callback", OverflowCallback.String())
+
+	timeout, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	q.Receive(ctx)
+	_, err := q.Receive(timeout)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -0,0 +1,175 @@
+package go61850
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/slonegd/go61850/osi/mms"
+	"github.com/slonegd/go61850/osi/mms/variant"
+)
+
+// DefaultSBOTimeout - время действия выбора объекта по умолчанию
+// (типовое значение sboTimeout, 30 с)
+const DefaultSBOTimeout = 30 * time.Second
+
+// ErrSelectionExpired возвращается Operate, если с момента выбора объекта
+// прошло больше sboTimeout: сервер уже снял выбор и отклонил бы команду.
+var ErrSelectionExpired = errors.New("selection expired")
+
+// WithSBOTimeout задаёт sboTimeout, по которому клиент отслеживает выбор объектов.
+// Значение должно совпадать с sboTimeout (CF) на устройстве.
+func WithSBOTimeout(timeout time.Duration) MmsClientOption {
+	return func(c *MmsClient) {
+		c.selections.timeout = timeout
+	}
+}
+
+// WithAutoReselect включает повторный выбор объекта перед Operate,
+// если sboTimeout истёк, вместо ошибки ErrSelectionExpired
+func WithAutoReselect() MmsClientOption {
+	return func(c *MmsClient) {
+		c.selections.autoReselect = true
+	}
+}
+
+// selection - выбранный объект управления
+type selection struct {
+	selectedAt time.Time
+	// withValue - объект выбран записью SBOw (select-with-value), а не чтением SBO
+	withValue bool
+	// ctlNum и origin записаны в SBOw и повторяются в Oper и повторном выборе
+	ctlNum uint8
+	origin ControlOrigin
+}
+
+// selectionTracker отслеживает выбранные объекты и sboTimeout.
+// Нулевое значение готово к использованию.
+type selectionTracker struct {
+	mu           sync.Mutex
+	timeout      time.Duration
+	autoReselect bool
+	// now - источник времени (подменяется в тестах)
+	now      func() time.Time
+	selected map[string]selection
+}
+
+// currentTime возвращает текущее время источника now
+func (t *selectionTracker) currentTime() time.Time {
+	if t.now != nil {
+		return t.now()
+	}
+	return time.Now()
+}
+
+// sboTimeout возвращает sboTimeout с учётом значения по умолчанию
+func (t *selectionTracker) sboTimeout() time.Duration {
+	if t.timeout > 0 {
+		return t.timeout
+	}
+	return DefaultSBOTimeout
+}
+
+// remember запоминает выбор объекта reference
+func (t *selectionTracker) remember(reference string, s selection) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.selected == nil {
+		t.selected = make(map[string]selection)
+	}
+	s.selectedAt = t.currentTime()
+	t.selected[reference] = s
+}
+
+// clear снимает выбор объекта reference
+func (t *selectionTracker) clear(reference string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.selected, reference)
+}
+
+// expired возвращает выбор объекта и true, если объект выбран и sboTimeout истёк
+func (t *selectionTracker) expired(reference string) (selection, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	s, ok := t.selected[reference]
+	if !ok {
+		return s, false
+	}
+	return s, t.currentTime().Sub(s.selectedAt) > t.sboTimeout()
+}
+
+// Select выбирает объект управления reference с обычной безопасностью
+// (sbo-with-normal-security): читает LN$CO$DO$SBO. Сервер возвращает ссылку
+// на объект при успешном выборе и пустую строку при отказе.
+func (c *MmsClient) Select(ctx context.Context, reference string) (err error) {
+	defer c.audit(AuditSelect, reference, nil, time.Now(), &err)
+	if err := c.checkWritable("select " + reference); err != nil {
+		return err
+	}
+	ctx = withDefaultPriority(ctx, PriorityControl)
+	request, err := c.NewReadRequest(reference, mms.FCCO)
+	if err != nil {
+		return err
+	}
+	request.ItemID += "$SBO"
+
+	result, err := c.ReadObject(ctx, request)
+	if err != nil {
+		return err
+	}
+	if !result.Success {
+		if result.Error != nil {
+			return result.Error
+		}
+		return fmt.Errorf("select %s failed", reference)
+	}
+	if result.Value.StringValue() == "" {
+		return fmt.Errorf("select %s rejected by server", reference)
+	}
+
+	c.selections.remember(reference, selection{})
+	return nil
+}
+
+// SelectWithValue выбирает объект управления reference с усиленной безопасностью
+// (sbo-with-enhanced-security): записывает структуру OperValue в LN$CO$DO$SBOw.
+// Отказ возвращается как *LastApplError, если сервер его прислал.
+func (c *MmsClient) SelectWithValue(ctx context.Context, reference string, ctlVal *variant.Variant, opts ...ControlOption) error {
+	return c.selectWithValue(ctx, reference, ctlVal, selection{withValue: true, ctlNum: c.nextCtlNum(), origin: c.origin}, opts...)
+}
+
+// selectWithValue записывает SBOw с ctlNum и origin выбора s и запоминает выбор
+func (c *MmsClient) selectWithValue(ctx context.Context, reference string, ctlVal *variant.Variant, s selection, opts ...ControlOption) error {
+	ctx = withDefaultPriority(ctx, PriorityControl)
+	if _, err := c.writeControl(ctx, reference, "SBOw", c.operValue(ctlVal, s.ctlNum, s.origin, opts...)); err != nil {
+		return err
+	}
+
+	c.selections.remember(reference, s)
+	return nil
+}
+
+// checkSelection проверяет sboTimeout выбранного объекта перед Operate
+// и при WithAutoReselect выбирает объект повторно; SBOw записывается
+// с ctlVal и opts выполняемой команды и ctlNum и origin первого выбора.
+// Возвращает действующий выбор объекта (нулевой, если объект не выбран).
+func (c *MmsClient) checkSelection(ctx context.Context, reference string, ctlVal *variant.Variant, opts ...ControlOption) (selection, error) {
+	s, expired := c.selections.expired(reference)
+	if !expired {
+		return s, nil
+	}
+	if !c.selections.autoReselect {
+		c.selections.clear(reference)
+		return selection{}, fmt.Errorf("%w: %s selected %s ago, sboTimeout %s", ErrSelectionExpired, reference,
+			c.selections.currentTime().Sub(s.selectedAt).Round(time.Millisecond), c.selections.sboTimeout())
+	}
+
+	c.logger.Debug("sboTimeout of %s expired, reselecting", reference)
+	if !s.withValue {
+		return s, c.Select(ctx, reference)
+	}
+	return s, c.selectWithValue(ctx, reference, ctlVal, s, opts...)
+}
@@ -0,0 +1,98 @@
+package go61850
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/slonegd/go61850/mmstest"
+	"github.com/slonegd/go61850/osi/mms"
+	"github.com/slonegd/go61850/osi/mms/variant"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckSelection(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := &MmsClient{logger: defaultLogger()}
+	WithSBOTimeout(10 * time.Second)(c)
+	c.selections.now = func() time.Time { return now }
+
+	// Невыбранный объект не проверяется (прямое управление)
+	_, err := c.checkSelection(context.Background(), "LD0/CSWI1.Pos", nil)
+	assert.NoError(t, err)
+
+	c.selections.remember("LD0/CSWI1.Pos", selection{})
+	now = now.Add(5 * time.Second)
+	_, err = c.checkSelection(context.Background(), "LD0/CSWI1.Pos", nil)
+	assert.NoError(t, err)
+
+	now = now.Add(6 * time.Second)
+	_, err = c.checkSelection(context.Background(), "LD0/CSWI1.Pos", nil)
+	assert.True(t, errors.Is(err, ErrSelectionExpired))
+	assert.EqualError(t, err, "selection expired: LD0/CSWI1.Pos selected 11s ago, sboTimeout 10s")
+
+	// Выбор снят: повторный Operate идёт как прямое управление
+	_, err = c.checkSelection(context.Background(), "LD0/CSWI1.Pos", nil)
+	assert.NoError(t, err)
+}
+
+func TestCheckSelectionAutoReselect(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := &MmsClient{logger: defaultLogger()}
+	WithAutoReselect()(c)
+	c.selections.now = func() time.Time { return now }
+
+	c.selections.remember("LD0/CSWI1.Pos", selection{})
+	now = now.Add(DefaultSBOTimeout + time.Second)
+
+	// Повторный выбор выполняется через Select, без соединения он завершается ошибкой
+	_, err := c.checkSelection(context.Background(), "LD0/CSWI1.Pos", nil)
+	assert.EqualError(t, err, "connection not established, call Initiate first")
+
+	// Повторный SBOw записывается со значением выполняемой команды, а не первого выбора;
+	// SBOw, повторный SBOw и Oper несут один ctlNum и origin
+	operTime := WithOperTime(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	expected := &MmsClient{}
+	oper := func(ctlVal bool, ctlNum uint8) *variant.Variant {
+		return expected.operValue(variant.NewBoolVariant(ctlVal), ctlNum, ControlOrigin{}, operTime)
+	}
+	server := mmstest.NewTranscriptServer(t, append(fileTranscript(t),
+		writeExchange(t, "CSWI1$CO$Pos$SBOw", oper(true, 0)),
+		writeExchange(t, "CSWI1$CO$Pos$SBOw", oper(false, 0)),
+		writeExchange(t, "CSWI1$CO$Pos$Oper", oper(false, 0)),
+		writeExchange(t, "CSWI1$CO$Pos$SBOw", oper(true, 1)),
+		writeExchange(t, "CSWI1$CO$Pos$Oper", oper(true, 1)),
+		// Прямое управление без выбора получает новый ctlNum
+		writeExchange(t, "CSWI1$CO$Pos$Oper", oper(false, 2)))...)
+	conn := server.Dial()
+	ctx := context.Background()
+	client, err := NewMmsClient(ctx, conn, WithAutoReselect())
+	assert.NoError(t, err)
+	client.selections.now = func() time.Time { return now }
+	_, err = client.Initiate(ctx)
+	assert.NoError(t, err)
+	assert.NoError(t, client.SelectWithValue(ctx, "LD/CSWI1.Pos", variant.NewBoolVariant(true), operTime))
+	now = now.Add(DefaultSBOTimeout + time.Second)
+	assert.NoError(t, client.Operate(ctx, "LD/CSWI1.Pos", variant.NewBoolVariant(false), operTime))
+	assert.NoError(t, client.SelectWithValue(ctx, "LD/CSWI1.Pos", variant.NewBoolVariant(true), operTime))
+	assert.NoError(t, client.Operate(ctx, "LD/CSWI1.Pos", variant.NewBoolVariant(true), operTime))
+	assert.NoError(t, client.Operate(ctx, "LD/CSWI1.Pos", variant.NewBoolVariant(false), operTime))
+	conn.Close()
+	assert.NoError(t, server.Wait())
+}
+
+func TestSelectDefaultDomain(t *testing.T) {
+	c := &MmsClient{logger: defaultLogger()}
+	err := c.Select(context.Background(), "CSWI1.Pos")
+	assert.True(t, errors.Is(err, mms.ErrMissingDomain))
+
+	WithDefaultDomain("LD0")(c)
+	request, err := c.NewReadRequest("CSWI1.Pos", mms.FCCO)
+	assert.NoError(t, err)
+	assert.Equal(t, &mms.ReadRequest{InvokeID: 1, DomainID: "LD0", ItemID: "CSWI1$CO$Pos"}, request)
+
+	// Ссылка разрешена, запрос не отправлен: нет соединения
+	err = c.Select(context.Background(), "CSWI1.Pos")
+	assert.EqualError(t, err, "connection not established, call Initiate first")
+}
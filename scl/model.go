@@ -0,0 +1,182 @@
+package scl
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/slonegd/go61850/model"
+	"github.com/slonegd/go61850/osi/mms"
+	"github.com/slonegd/go61850/osi/mms/variant"
+)
+
+// Model строит модель данных сервера для IED iedName: логические устройства
+// и узлы по шаблонам типов, наборы данных и условия запуска отчётов
+// (dchg, qchg, dupd) атрибутов верхнего уровня. Листовые атрибуты получают
+// нулевые значения своего базового типа; элементы Val файла не разбираются.
+func (s *SCL) Model(iedName string) (*model.Model, error) {
+	ied := s.IED(iedName)
+	if ied == nil {
+		return nil, fmt.Errorf("IED %s not found", iedName)
+	}
+
+	m := &model.Model{Name: iedName}
+	for _, ld := range ied.LDevices() {
+		logicalDevice := &model.LogicalDevice{Name: ld.Name(iedName)}
+		for _, ln := range ld.LogicalNodes() {
+			logicalNode, err := s.logicalNode(ln)
+			if err != nil {
+				return nil, err
+			}
+			logicalDevice.LogicalNodes = append(logicalDevice.LogicalNodes, logicalNode)
+
+			for _, ds := range ln.DataSets {
+				dataSet := &model.DataSet{Name: ln.Name() + "$" + ds.Name}
+				for _, fcda := range ds.FCDAs {
+					if fcda.LdInst != ld.Inst {
+						return nil, fmt.Errorf("FCDA %s of data set %s refers to another LDevice %s",
+							fcda.Reference(), ds.Name, fcda.LdInst)
+					}
+					dataSet.Members = append(dataSet.Members, fcda.mmsName())
+				}
+				logicalDevice.DataSets = append(logicalDevice.DataSets, dataSet)
+			}
+		}
+		m.LogicalDevices = append(m.LogicalDevices, logicalDevice)
+	}
+	return m, nil
+}
+
+// mmsName возвращает MMS имя элемента набора данных в домене: "LN$FC$DO$DA"
+func (f FCDA) mmsName() string {
+	name := f.Prefix + f.LnClass + f.LnInst + "$" + f.FC + "$" + strings.ReplaceAll(f.DoName, ".", "$")
+	if f.DaName != "" {
+		name += "$" + strings.ReplaceAll(f.DaName, ".", "$")
+	}
+	return name
+}
+
+// logicalNode строит логический узел модели по его типу
+func (s *SCL) logicalNode(ln LN) (*model.LogicalNode, error) {
+	lnType := s.Templates.LNodeType(ln.LnType)
+	if lnType == nil {
+		return nil, fmt.Errorf("LNodeType %s of %s not found", ln.LnType, ln.Name())
+	}
+	logicalNode := &model.LogicalNode{Name: ln.Name()}
+	for _, do := range lnType.DOs {
+		dataObject, err := s.dataObject(do.Name, do.Type)
+		if err != nil {
+			return nil, err
+		}
+		logicalNode.DataObjects = append(logicalNode.DataObjects, dataObject)
+	}
+	return logicalNode, nil
+}
+
+// dataObject строит объект данных типа typeID с SDO и атрибутами в порядке шаблона
+func (s *SCL) dataObject(name, typeID string) (*model.DataObject, error) {
+	doType := s.Templates.DOType(typeID)
+	if doType == nil {
+		return nil, fmt.Errorf("DOType %s of %s not found", typeID, name)
+	}
+	do := &model.DataObject{Name: name}
+	for _, e := range doType.Elements {
+		switch {
+		case e.IsSDO():
+			sdo, err := s.dataObject(e.Name, e.Type)
+			if err != nil {
+				return nil, err
+			}
+			do.Children = append(do.Children, sdo)
+		case e.IsDA():
+			da, err := s.dataAttribute(e)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+			da.FC = mms.FunctionalConstraint(e.FC)
+			// Компоненты составного атрибута наследуют условия DA (см. model.DataAttribute)
+			da.Triggers = e.Trigger()
+			do.Children = append(do.Children, da)
+		}
+	}
+	return do, nil
+}
+
+// dataAttribute строит DA или BDA; составной атрибут раскрывается по DAType
+func (s *SCL) dataAttribute(e Element) (*model.DataAttribute, error) {
+	da := &model.DataAttribute{Name: e.Name}
+	if e.BType != "Struct" {
+		value, spec, err := zeroValue(e.BType)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", e.Name, err)
+		}
+		da.Value, da.Type = value, spec
+		return da, nil
+	}
+
+	daType := s.Templates.DAType(e.Type)
+	if daType == nil {
+		return nil, fmt.Errorf("DAType %s of %s not found", e.Type, e.Name)
+	}
+	for _, bda := range daType.BDAs {
+		component, err := s.dataAttribute(bda)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%w", e.Name, err)
+		}
+		da.Attributes = append(da.Attributes, component)
+	}
+	return da, nil
+}
+
+// zeroValue возвращает нулевое значение базового типа SCL и его MMS тип
+// по IEC 61850-8-1; nil тип - совпадает с выведенным из значения (mms.TypeOf)
+func zeroValue(bType string) (*variant.Variant, *mms.TypeSpecification, error) {
+	switch bType {
+	case "BOOLEAN":
+		return variant.NewBoolVariant(false), nil, nil
+	case "INT8", "Enum":
+		return variant.NewInt32Variant(0), &mms.TypeSpecification{Type: mms.TypeSpecInteger, IntegerSize: 8}, nil
+	case "INT16":
+		return variant.NewInt32Variant(0), &mms.TypeSpecification{Type: mms.TypeSpecInteger, IntegerSize: 16}, nil
+	case "INT32":
+		return variant.NewInt32Variant(0), nil, nil
+	case "INT64":
+		return variant.NewInt64Variant(0), nil, nil
+	case "INT8U":
+		return variant.NewUint32Variant(0), &mms.TypeSpecification{Type: mms.TypeSpecUnsigned, UnsignedSize: 8}, nil
+	case "INT16U":
+		return variant.NewUint32Variant(0), &mms.TypeSpecification{Type: mms.TypeSpecUnsigned, UnsignedSize: 16}, nil
+	case "INT24U":
+		return variant.NewUint32Variant(0), &mms.TypeSpecification{Type: mms.TypeSpecUnsigned, UnsignedSize: 24}, nil
+	case "INT32U":
+		return variant.NewUint32Variant(0), nil, nil
+	case "INT64U":
+		return variant.NewUint64Variant(0), nil, nil
+	case "FLOAT32":
+		return variant.NewFloat32Variant(0), nil, nil
+	case "Timestamp":
+		return variant.NewUTCTimeVariant(time.Unix(0, 0).UTC()), nil, nil
+	case "EntryTime":
+		return variant.NewBinaryTimeVariant(make([]byte, 6)), nil, nil
+	case "Quality":
+		// bit-string переменной длины до 13 бит
+		return variant.NewBitStringVariant(make([]byte, 2), 13),
+			&mms.TypeSpecification{Type: mms.TypeSpecBitString, BitStringSize: -13}, nil
+	case "Dbpos", "Tcmd", "Check":
+		return variant.NewBitStringVariant(make([]byte, 1), 2), nil, nil
+	case "VisString32", "VisString64", "VisString65", "VisString129", "VisString255":
+		size, _ := strconv.Atoi(strings.TrimPrefix(bType, "VisString"))
+		return variant.NewVisibleStringVariant(""),
+			&mms.TypeSpecification{Type: mms.TypeSpecVisibleString, VisibleStringSize: -size}, nil
+	case "ObjRef":
+		return variant.NewVisibleStringVariant(""),
+			&mms.TypeSpecification{Type: mms.TypeSpecVisibleString, VisibleStringSize: -129}, nil
+	case "Unicode255":
+		return variant.NewMMSStringVariant(""), nil, nil
+	case "Octet64":
+		return variant.NewOctetStringVariant(nil), nil, nil
+	default:
+		return nil, nil, fmt.Errorf("unsupported bType %s", bType)
+	}
+}
@@ -0,0 +1,362 @@
+// Package scl разбирает файлы SCL (IEC 61850-6): SCD, ICD, CID.
+//
+// Поддерживается подмножество, необходимое клиенту и серверу: IED,
+// логические устройства и узлы, наборы данных, блоки управления отчётами
+// и шаблоны типов (DataTypeTemplates). Model строит по IED модель данных
+// сервера (пакет model) с условиями dchg, qchg и dupd атрибутов.
+package scl
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/slonegd/go61850/model"
+	"github.com/slonegd/go61850/osi/mms"
+)
+
+// SCL представляет корневой элемент файла SCL
+type SCL struct {
+	XMLName   xml.Name          `xml:"SCL"`
+	IEDs      []IED             `xml:"IED"`
+	Templates DataTypeTemplates `xml:"DataTypeTemplates"`
+}
+
+// IED представляет интеллектуальное электронное устройство
+type IED struct {
+	Name         string        `xml:"name,attr"`
+	Manufacturer string        `xml:"manufacturer,attr"`
+	AccessPoints []AccessPoint `xml:"AccessPoint"`
+}
+
+// AccessPoint представляет точку доступа IED
+type AccessPoint struct {
+	Name   string  `xml:"name,attr"`
+	Server *Server `xml:"Server"`
+}
+
+// Server представляет сервер точки доступа
+type Server struct {
+	LDevices []LDevice `xml:"LDevice"`
+}
+
+// LDevice представляет логическое устройство
+type LDevice struct {
+	Inst string `xml:"inst,attr"`
+	// LdName - явное имя логического устройства (Edition 2), если задано
+	LdName string `xml:"ldName,attr"`
+	LN0    LN     `xml:"LN0"`
+	LNs    []LN   `xml:"LN"`
+}
+
+// LN представляет логический узел (LN0 или LN)
+type LN struct {
+	Prefix         string          `xml:"prefix,attr"`
+	LnClass        string          `xml:"lnClass,attr"`
+	Inst           string          `xml:"inst,attr"`
+	LnType         string          `xml:"lnType,attr"`
+	DataSets       []DataSet       `xml:"DataSet"`
+	ReportControls []ReportControl `xml:"ReportControl"`
+}
+
+// DataSet представляет набор данных
+type DataSet struct {
+	Name  string `xml:"name,attr"`
+	FCDAs []FCDA `xml:"FCDA"`
+}
+
+// FCDA представляет элемент набора данных (functionally constrained data attribute)
+type FCDA struct {
+	LdInst  string `xml:"ldInst,attr"`
+	Prefix  string `xml:"prefix,attr"`
+	LnClass string `xml:"lnClass,attr"`
+	LnInst  string `xml:"lnInst,attr"`
+	DoName  string `xml:"doName,attr"`
+	DaName  string `xml:"daName,attr"`
+	FC      string `xml:"fc,attr"`
+}
+
+// ReportControl представляет блок управления отчётами
+type ReportControl struct {
+	Name     string `xml:"name,attr"`
+	DatSet   string `xml:"datSet,attr"`
+	RptID    string `xml:"rptID,attr"`
+	ConfRev  uint32 `xml:"confRev,attr"`
+	Buffered bool   `xml:"buffered,attr"`
+	IntgPd   uint32 `xml:"intgPd,attr"`
+	BufTime  uint32 `xml:"bufTime,attr"`
+	// TrgOps - условия запуска отчёта; nil - не заданы
+	TrgOps *TrgOps `xml:"TrgOps"`
+}
+
+// TrgOps представляет условия запуска отчёта блока управления
+type TrgOps struct {
+	Dchg   bool `xml:"dchg,attr"`
+	Qchg   bool `xml:"qchg,attr"`
+	Dupd   bool `xml:"dupd,attr"`
+	Period bool `xml:"period,attr"`
+	GI     bool `xml:"gi,attr"`
+}
+
+// Trigger возвращает условия в виде model.Trigger
+func (t TrgOps) Trigger() model.Trigger {
+	return triggerOf(t.Dchg, t.Qchg, t.Dupd) |
+		flag(t.Period, model.TriggerIntegrity) | flag(t.GI, model.TriggerGI)
+}
+
+// triggerOf собирает условия dchg, qchg и dupd
+func triggerOf(dchg, qchg, dupd bool) model.Trigger {
+	return flag(dchg, model.TriggerDataChange) | flag(qchg, model.TriggerQualityChange) |
+		flag(dupd, model.TriggerDataUpdate)
+}
+
+// flag возвращает t, если set истинно
+func flag(set bool, t model.Trigger) model.Trigger {
+	if set {
+		return t
+	}
+	return 0
+}
+
+// DataTypeTemplates представляет шаблоны типов
+type DataTypeTemplates struct {
+	LNodeTypes []LNodeType `xml:"LNodeType"`
+	DOTypes    []DOType    `xml:"DOType"`
+	DATypes    []DAType    `xml:"DAType"`
+}
+
+// LNodeType представляет тип логического узла
+type LNodeType struct {
+	ID      string `xml:"id,attr"`
+	LnClass string `xml:"lnClass,attr"`
+	DOs     []DO   `xml:"DO"`
+}
+
+// DO представляет объект данных в типе логического узла
+type DO struct {
+	Name string `xml:"name,attr"`
+	Type string `xml:"type,attr"`
+}
+
+// DOType представляет тип объекта данных.
+// Elements хранит SDO и DA в порядке файла: этот порядок определяет
+// порядок элементов структур MMS.
+type DOType struct {
+	ID       string    `xml:"id,attr"`
+	CDC      string    `xml:"cdc,attr"`
+	Elements []Element `xml:",any"`
+}
+
+// DAType представляет тип составного атрибута данных
+type DAType struct {
+	ID   string    `xml:"id,attr"`
+	BDAs []Element `xml:"BDA"`
+}
+
+// Element представляет SDO, DA или BDA
+type Element struct {
+	XMLName xml.Name
+	Name    string `xml:"name,attr"`
+	// Type - ссылка на DOType (для SDO) или DAType (для bType="Struct")
+	Type  string `xml:"type,attr"`
+	FC    string `xml:"fc,attr"`
+	BType string `xml:"bType,attr"`
+	// Dchg, Qchg и Dupd - условия запуска отчётов для DA
+	Dchg bool `xml:"dchg,attr"`
+	Qchg bool `xml:"qchg,attr"`
+	Dupd bool `xml:"dupd,attr"`
+}
+
+// Trigger возвращает условия запуска отчётов DA в виде model.Trigger
+func (e Element) Trigger() model.Trigger {
+	return triggerOf(e.Dchg, e.Qchg, e.Dupd)
+}
+
+// IsSDO возвращает true для вложенного объекта данных
+func (e Element) IsSDO() bool { return e.XMLName.Local == "SDO" }
+
+// IsDA возвращает true для атрибута данных
+func (e Element) IsDA() bool { return e.XMLName.Local == "DA" }
+
+// Parse разбирает SCL из r
+func Parse(r io.Reader) (*SCL, error) {
+	var s SCL
+	if err := xml.NewDecoder(r).Decode(&s); err != nil {
+		return nil, fmt.Errorf("failed to parse SCL: %w", err)
+	}
+	return &s, nil
+}
+
+// ParseFile разбирает SCL файл
+func ParseFile(path string) (*SCL, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Parse(f)
+}
+
+// IED возвращает IED по имени или nil
+func (s *SCL) IED(name string) *IED {
+	for i := range s.IEDs {
+		if s.IEDs[i].Name == name {
+			return &s.IEDs[i]
+		}
+	}
+	return nil
+}
+
+// LDevices возвращает логические устройства всех точек доступа IED
+func (ied *IED) LDevices() []LDevice {
+	var lds []LDevice
+	for _, ap := range ied.AccessPoints {
+		if ap.Server != nil {
+			lds = append(lds, ap.Server.LDevices...)
+		}
+	}
+	return lds
+}
+
+// Name возвращает имя логического устройства (домена MMS):
+// ldName, если задан, иначе имя IED и inst
+func (ld LDevice) Name(iedName string) string {
+	if ld.LdName != "" {
+		return ld.LdName
+	}
+	return iedName + ld.Inst
+}
+
+// LogicalNodes возвращает LN0 и остальные логические узлы в порядке файла
+func (ld LDevice) LogicalNodes() []LN {
+	lns := make([]LN, 0, len(ld.LNs)+1)
+	if ld.LN0.LnClass != "" {
+		lns = append(lns, ld.LN0)
+	}
+	return append(lns, ld.LNs...)
+}
+
+// Name возвращает имя логического узла: prefix + lnClass + inst
+func (ln LN) Name() string {
+	return ln.Prefix + ln.LnClass + ln.Inst
+}
+
+// LNodeType возвращает тип логического узла по id или nil
+func (t *DataTypeTemplates) LNodeType(id string) *LNodeType {
+	for i := range t.LNodeTypes {
+		if t.LNodeTypes[i].ID == id {
+			return &t.LNodeTypes[i]
+		}
+	}
+	return nil
+}
+
+// DOType возвращает тип объекта данных по id или nil
+func (t *DataTypeTemplates) DOType(id string) *DOType {
+	for i := range t.DOTypes {
+		if t.DOTypes[i].ID == id {
+			return &t.DOTypes[i]
+		}
+	}
+	return nil
+}
+
+// DAType возвращает тип атрибута данных по id или nil
+func (t *DataTypeTemplates) DAType(id string) *DAType {
+	for i := range t.DATypes {
+		if t.DATypes[i].ID == id {
+			return &t.DATypes[i]
+		}
+	}
+	return nil
+}
+
+// Attribute представляет листовой атрибут данных, найденный в SCL
+type Attribute struct {
+	// LDevice - имя логического устройства (домена MMS)
+	LDevice string
+	// LN - имя логического узла
+	LN string
+	// Path - путь от LN через точку, например "AnIn1.mag.f"
+	Path string
+	// FC - функциональное ограничение
+	FC mms.FunctionalConstraint
+	// BType - базовый тип атрибута (например, "FLOAT32", "Quality")
+	BType string
+	// Triggers - условия запуска отчётов DA верхнего уровня, к которому относится атрибут
+	Triggers model.Trigger
+}
+
+// Reference возвращает ссылку на объект в формате IEC 61850: "LD/LN.DO.DA"
+func (a Attribute) Reference() string {
+	return a.LDevice + "/" + a.LN + "." + a.Path
+}
+
+// Attributes возвращает все листовые атрибуты логического узла ln устройства ldName
+// в порядке шаблонов типов
+func (s *SCL) Attributes(ldName string, ln LN) ([]Attribute, error) {
+	lnType := s.Templates.LNodeType(ln.LnType)
+	if lnType == nil {
+		return nil, fmt.Errorf("LNodeType %s of %s not found", ln.LnType, ln.Name())
+	}
+
+	var attributes []Attribute
+	for _, do := range lnType.DOs {
+		base := Attribute{LDevice: ldName, LN: ln.Name()}
+		var err error
+		attributes, err = s.appendDOAttributes(attributes, base, do.Name, do.Type)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return attributes, nil
+}
+
+// appendDOAttributes добавляет атрибуты объекта данных типа typeID
+func (s *SCL) appendDOAttributes(attributes []Attribute, base Attribute, path, typeID string) ([]Attribute, error) {
+	doType := s.Templates.DOType(typeID)
+	if doType == nil {
+		return nil, fmt.Errorf("DOType %s of %s not found", typeID, path)
+	}
+
+	var err error
+	for _, e := range doType.Elements {
+		switch {
+		case e.IsSDO():
+			attributes, err = s.appendDOAttributes(attributes, base, path+"."+e.Name, e.Type)
+		case e.IsDA():
+			attr := base
+			attr.FC = mms.FunctionalConstraint(e.FC)
+			attr.Triggers = e.Trigger()
+			attributes, err = s.appendDAAttributes(attributes, attr, path+"."+e.Name, e)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return attributes, nil
+}
+
+// appendDAAttributes добавляет атрибут e или, для составного атрибута, его компоненты
+func (s *SCL) appendDAAttributes(attributes []Attribute, base Attribute, path string, e Element) ([]Attribute, error) {
+	if e.BType != "Struct" {
+		attr := base
+		attr.Path = path
+		attr.BType = e.BType
+		return append(attributes, attr), nil
+	}
+
+	daType := s.Templates.DAType(e.Type)
+	if daType == nil {
+		return nil, fmt.Errorf("DAType %s of %s not found", e.Type, path)
+	}
+	var err error
+	for _, bda := range daType.BDAs {
+		attributes, err = s.appendDAAttributes(attributes, base, path+"."+bda.Name, bda)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return attributes, nil
+}
@@ -0,0 +1,133 @@
+package scl
+
+import (
+	"testing"
+
+	"github.com/slonegd/go61850/model"
+	"github.com/slonegd/go61850/osi/mms"
+	"github.com/slonegd/go61850/osi/mms/variant"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFile(t *testing.T) {
+	s, err := ParseFile("testdata/simpleIO.scd")
+	assert.NoError(t, err)
+
+	ied := s.IED("simpleIO")
+	assert.NotNil(t, ied)
+	lds := ied.LDevices()
+	assert.Len(t, lds, 1)
+	assert.Equal(t, "simpleIOGenericIO", lds[0].Name(ied.Name))
+
+	lns := lds[0].LogicalNodes()
+	assert.Len(t, lns, 2)
+	assert.Equal(t, "LLN0", lns[0].Name())
+	assert.Equal(t, "GGIO1", lns[1].Name())
+
+	assert.Equal(t, "Events", lns[0].DataSets[0].Name)
+	assert.Len(t, lns[0].DataSets[0].FCDAs, 2)
+	assert.Equal(t, ReportControl{Name: "EventsBRCB", DatSet: "Events", RptID: "Events", ConfRev: 1, Buffered: true, BufTime: 50},
+		lns[0].ReportControls[1])
+	assert.Equal(t, model.TriggerDataChange|model.TriggerQualityChange|model.TriggerIntegrity|model.TriggerGI,
+		lns[0].ReportControls[0].TrgOps.Trigger())
+}
+
+func TestAttributes(t *testing.T) {
+	s, err := ParseFile("testdata/simpleIO.scd")
+	assert.NoError(t, err)
+	ln := s.IEDs[0].LDevices()[0].LNs[0]
+
+	attributes, err := s.Attributes("simpleIOGenericIO", ln)
+	assert.NoError(t, err)
+
+	var references []string
+	for _, a := range attributes {
+		references = append(references, a.Reference()+" "+string(a.FC))
+	}
+	assert.Equal(t, []string{
+		"simpleIOGenericIO/GGIO1.AnIn1.mag.f MX",
+		"simpleIOGenericIO/GGIO1.AnIn1.q MX",
+		"simpleIOGenericIO/GGIO1.AnIn1.t MX",
+		"simpleIOGenericIO/GGIO1.AnIn2.mag.f MX",
+		"simpleIOGenericIO/GGIO1.AnIn2.q MX",
+		"simpleIOGenericIO/GGIO1.AnIn2.t MX",
+	}, references)
+	assert.Equal(t, Attribute{LDevice: "simpleIOGenericIO", LN: "GGIO1", Path: "AnIn1.mag.f", FC: mms.FCMX, BType: "FLOAT32",
+		Triggers: model.TriggerDataChange}, attributes[0])
+	assert.Equal(t, model.TriggerQualityChange, attributes[1].Triggers)
+
+	_, err = s.Attributes("simpleIOGenericIO", LN{LnClass: "XCBR", Inst: "1", LnType: "XCBR1"})
+	assert.EqualError(t, err, "LNodeType XCBR1 of XCBR1 not found")
+}
+
+func TestFCDAType(t *testing.T) {
+	s, err := ParseFile("testdata/simpleIO.scd")
+	assert.NoError(t, err)
+	ied := s.IED("simpleIO")
+	ds := ied.LDevice("GenericIO").DataSet("Events")
+	assert.NotNil(t, ds)
+
+	typ, err := s.FCDAType(ied, ds.FCDAs[0])
+	assert.NoError(t, err)
+	assert.Equal(t, "AnIn1{mag{f:FLOAT32}, q:Quality, t:Timestamp}", typ.String())
+
+	typ, err = s.FCDAType(ied, FCDA{LdInst: "GenericIO", LnClass: "GGIO", LnInst: "1", DoName: "AnIn1", DaName: "mag.f", FC: "MX"})
+	assert.NoError(t, err)
+	assert.Equal(t, Type{Name: "f", BType: "FLOAT32"}, typ)
+
+	_, err = s.FCDAType(ied, FCDA{LdInst: "GenericIO", LnClass: "GGIO", LnInst: "1", DoName: "AnIn1", FC: "ST"})
+	assert.EqualError(t, err, "FCDA GGIO1.AnIn1 has no attributes with FC ST")
+	_, err = s.FCDAType(ied, FCDA{LdInst: "GenericIO", LnClass: "GGIO", LnInst: "1", DoName: "AnIn1", DaName: "mag.i", FC: "MX"})
+	assert.EqualError(t, err, "BDA i of FCDA GGIO1.AnIn1.mag.i not found")
+}
+
+func TestModel(t *testing.T) {
+	s, err := ParseFile("testdata/simpleIO.scd")
+	assert.NoError(t, err)
+
+	m, err := s.Model("simpleIO")
+	assert.NoError(t, err)
+	ld := m.LogicalDevice("simpleIOGenericIO")
+	assert.NotNil(t, ld)
+	assert.Equal(t, []*model.DataSet{{Name: "LLN0$Events", Members: []string{"GGIO1$MX$AnIn1", "GGIO1$MX$AnIn2"}}}, ld.DataSets)
+
+	ln := ld.LogicalNode("GGIO1")
+	mag, triggers, err := ln.Attribute(mms.FCMX, "AnIn1", "mag")
+	assert.NoError(t, err)
+	assert.Equal(t, model.TriggerDataChange, mag.Triggers)
+	assert.Equal(t, model.TriggerDataChange, triggers)
+	// компонент наследует условия DA верхнего уровня
+	f, triggers, err := ln.Attribute(mms.FCMX, "AnIn1", "mag", "f")
+	assert.NoError(t, err)
+	assert.Equal(t, model.Trigger(0), f.Triggers)
+	assert.Equal(t, model.TriggerDataChange, triggers)
+	q, _, err := ln.Attribute(mms.FCMX, "AnIn2", "q")
+	assert.NoError(t, err)
+	assert.Equal(t, model.TriggerQualityChange, q.Triggers)
+	_, triggers, err = ln.Attribute(mms.FCMX, "AnIn2", "t")
+	assert.NoError(t, err)
+	assert.Equal(t, model.Trigger(0), triggers)
+
+	spec, err := ln.FCType(mms.FCMX, "AnIn1", "q")
+	assert.NoError(t, err)
+	assert.Equal(t, -13, spec.BitStringSize)
+	names := ld.VariableNames()
+	assert.Contains(t, names, "LLN0$CF$Mod$ctlModel")
+	assert.Contains(t, names, "GGIO1$MX$AnIn2$mag$f")
+
+	_, err = s.Model("missing")
+	assert.EqualError(t, err, "IED missing not found")
+}
+
+func TestZeroValue64(t *testing.T) {
+	value, spec, err := zeroValue("INT64")
+	assert.NoError(t, err)
+	assert.Equal(t, variant.NewInt64Variant(0), value)
+	assert.Nil(t, spec)
+	assert.Equal(t, 64, mms.TypeOf(value).IntegerSize)
+
+	value, _, err = zeroValue("INT64U")
+	assert.NoError(t, err)
+	assert.Equal(t, variant.NewUint64Variant(0), value)
+	assert.Equal(t, 64, mms.TypeOf(value).UnsignedSize)
+}
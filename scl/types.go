@@ -0,0 +1,222 @@
+package scl
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Type описывает тип элемента данных по шаблонам SCL: базовый тип (BType)
+// или структуру (BType "Struct") с компонентами в порядке шаблона.
+// Порядок компонентов совпадает с порядком элементов структуры MMS Data.
+type Type struct {
+	// Name - имя элемента (DO, SDO, DA или BDA)
+	Name     string
+	BType    string
+	Children []Type
+}
+
+// IsStruct возвращает true для структуры
+func (t Type) IsStruct() bool { return t.BType == "Struct" }
+
+// String возвращает тип в виде "name:BType" или "name{...}" для структуры
+func (t Type) String() string {
+	if !t.IsStruct() {
+		return t.Name + ":" + t.BType
+	}
+	children := make([]string, len(t.Children))
+	for i, child := range t.Children {
+		children[i] = child.String()
+	}
+	return t.Name + "{" + strings.Join(children, ", ") + "}"
+}
+
+// LDevice возвращает логическое устройство IED по inst или nil
+func (ied *IED) LDevice(inst string) *LDevice {
+	for _, ap := range ied.AccessPoints {
+		if ap.Server == nil {
+			continue
+		}
+		for i := range ap.Server.LDevices {
+			if ap.Server.LDevices[i].Inst == inst {
+				return &ap.Server.LDevices[i]
+			}
+		}
+	}
+	return nil
+}
+
+// LN возвращает логический узел по prefix, lnClass и inst или nil
+func (ld *LDevice) LN(prefix, lnClass, inst string) *LN {
+	for _, ln := range ld.LogicalNodes() {
+		if ln.Prefix == prefix && ln.LnClass == lnClass && ln.Inst == inst {
+			return &ln
+		}
+	}
+	return nil
+}
+
+// DataSet возвращает набор данных логического устройства по имени или nil
+func (ld *LDevice) DataSet(name string) *DataSet {
+	for _, ln := range ld.LogicalNodes() {
+		for i := range ln.DataSets {
+			if ln.DataSets[i].Name == name {
+				return &ln.DataSets[i]
+			}
+		}
+	}
+	return nil
+}
+
+// Reference возвращает ссылку на элемент набора данных: "LN.DO.DA"
+// (имя логического устройства не включается)
+func (f FCDA) Reference() string {
+	reference := f.Prefix + f.LnClass + f.LnInst + "." + f.DoName
+	if f.DaName != "" {
+		reference += "." + f.DaName
+	}
+	return reference
+}
+
+// FCDAType возвращает тип элемента набора данных f устройства ied:
+// для FCDA без daName - структуру атрибутов DO с функциональным ограничением f.FC.
+func (s *SCL) FCDAType(ied *IED, f FCDA) (Type, error) {
+	ld := ied.LDevice(f.LdInst)
+	if ld == nil {
+		return Type{}, fmt.Errorf("LDevice %s of FCDA %s not found", f.LdInst, f.Reference())
+	}
+	ln := ld.LN(f.Prefix, f.LnClass, f.LnInst)
+	if ln == nil {
+		return Type{}, fmt.Errorf("LN %s%s%s of FCDA %s not found", f.Prefix, f.LnClass, f.LnInst, f.Reference())
+	}
+	lnType := s.Templates.LNodeType(ln.LnType)
+	if lnType == nil {
+		return Type{}, fmt.Errorf("LNodeType %s of %s not found", ln.LnType, ln.Name())
+	}
+
+	// doName может содержать SDO через точку, например "PhV.phsA"
+	doPath := strings.Split(f.DoName, ".")
+	var typeID string
+	for _, do := range lnType.DOs {
+		if do.Name == doPath[0] {
+			typeID = do.Type
+		}
+	}
+	if typeID == "" {
+		return Type{}, fmt.Errorf("DO %s of FCDA %s not found", doPath[0], f.Reference())
+	}
+	for _, name := range doPath[1:] {
+		doType := s.Templates.DOType(typeID)
+		if doType == nil {
+			return Type{}, fmt.Errorf("DOType %s of FCDA %s not found", typeID, f.Reference())
+		}
+		typeID = ""
+		for _, e := range doType.Elements {
+			if e.IsSDO() && e.Name == name {
+				typeID = e.Type
+			}
+		}
+		if typeID == "" {
+			return Type{}, fmt.Errorf("SDO %s of FCDA %s not found", name, f.Reference())
+		}
+	}
+
+	if f.DaName == "" {
+		t, err := s.doType(doPath[len(doPath)-1], typeID, f.FC)
+		if err != nil {
+			return Type{}, err
+		}
+		if len(t.Children) == 0 {
+			return Type{}, fmt.Errorf("FCDA %s has no attributes with FC %s", f.Reference(), f.FC)
+		}
+		return t, nil
+	}
+
+	return s.daPathType(typeID, strings.Split(f.DaName, "."), f)
+}
+
+// doType возвращает структуру атрибутов объекта данных typeID с функциональным ограничением fc.
+// SDO без атрибутов fc не включаются.
+func (s *SCL) doType(name, typeID, fc string) (Type, error) {
+	doType := s.Templates.DOType(typeID)
+	if doType == nil {
+		return Type{}, fmt.Errorf("DOType %s of %s not found", typeID, name)
+	}
+
+	t := Type{Name: name, BType: "Struct"}
+	for _, e := range doType.Elements {
+		switch {
+		case e.IsSDO():
+			sdo, err := s.doType(e.Name, e.Type, fc)
+			if err != nil {
+				return Type{}, err
+			}
+			if len(sdo.Children) > 0 {
+				t.Children = append(t.Children, sdo)
+			}
+		case e.IsDA() && e.FC == fc:
+			da, err := s.elementType(e)
+			if err != nil {
+				return Type{}, err
+			}
+			t.Children = append(t.Children, da)
+		}
+	}
+	return t, nil
+}
+
+// daPathType находит атрибут по пути daName в объекте данных typeID
+func (s *SCL) daPathType(typeID string, path []string, f FCDA) (Type, error) {
+	doType := s.Templates.DOType(typeID)
+	if doType == nil {
+		return Type{}, fmt.Errorf("DOType %s of FCDA %s not found", typeID, f.Reference())
+	}
+
+	var da *Element
+	for i, e := range doType.Elements {
+		if e.IsDA() && e.Name == path[0] && e.FC == f.FC {
+			da = &doType.Elements[i]
+		}
+	}
+	if da == nil {
+		return Type{}, fmt.Errorf("DA %s with FC %s of FCDA %s not found", path[0], f.FC, f.Reference())
+	}
+
+	t, err := s.elementType(*da)
+	if err != nil {
+		return Type{}, err
+	}
+	for _, name := range path[1:] {
+		var next *Type
+		for i := range t.Children {
+			if t.Children[i].Name == name {
+				next = &t.Children[i]
+			}
+		}
+		if next == nil {
+			return Type{}, fmt.Errorf("BDA %s of FCDA %s not found", name, f.Reference())
+		}
+		t = *next
+	}
+	return t, nil
+}
+
+// elementType возвращает тип DA или BDA, раскрывая составные атрибуты
+func (s *SCL) elementType(e Element) (Type, error) {
+	if e.BType != "Struct" {
+		return Type{Name: e.Name, BType: e.BType}, nil
+	}
+
+	daType := s.Templates.DAType(e.Type)
+	if daType == nil {
+		return Type{}, fmt.Errorf("DAType %s of %s not found", e.Type, e.Name)
+	}
+	t := Type{Name: e.Name, BType: "Struct"}
+	for _, bda := range daType.BDAs {
+		child, err := s.elementType(bda)
+		if err != nil {
+			return Type{}, err
+		}
+		t.Children = append(t.Children, child)
+	}
+	return t, nil
+}
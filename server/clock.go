@@ -0,0 +1,116 @@
+package server
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Clock - источник времени и таймеров сервера (планировщик отчётов, журналы).
+// Интервалы отсчитываются по монотонному времени.
+type Clock interface {
+	Now() time.Time
+	// AfterFunc вызывает f в отдельной горутине через d
+	AfterFunc(d time.Duration, f func()) Timer
+}
+
+// Timer - запланированный вызов Clock.AfterFunc
+type Timer interface {
+	// Stop отменяет вызов; возвращает false, если вызов уже произошёл
+	Stop() bool
+}
+
+// systemClock - Clock на основе пакета time
+type systemClock struct{}
+
+// Now реализует Clock
+func (systemClock) Now() time.Time { return time.Now() }
+
+// AfterFunc реализует Clock
+func (systemClock) AfterFunc(d time.Duration, f func()) Timer { return time.AfterFunc(d, f) }
+
+// WithClock задаёт источник времени и таймеров (например, для тестов)
+func WithClock(c Clock) Option {
+	return func(s *Server) {
+		s.clock = c
+	}
+}
+
+// SimulatedClock - Clock с управляемым временем: время стоит на месте, пока его
+// не сдвинет Advance, а таймеры вызываются синхронно в горутине Advance.
+// Позволяет детерминированно проверять integrity отчёты, буферизацию BufTm
+// и метки времени журналов без ожидания реального времени.
+type SimulatedClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*simulatedTimer
+}
+
+type simulatedTimer struct {
+	clock *SimulatedClock
+	at    time.Time
+	f     func()
+}
+
+// NewSimulatedClock создаёт SimulatedClock с текущим временем start
+func NewSimulatedClock(start time.Time) *SimulatedClock {
+	return &SimulatedClock{now: start}
+}
+
+// Now реализует Clock
+func (c *SimulatedClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// AfterFunc реализует Clock; f вызывается из Advance, когда время достигнет now+d
+func (c *SimulatedClock) AfterFunc(d time.Duration, f func()) Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := &simulatedTimer{clock: c, at: c.now.Add(d), f: f}
+	c.timers = append(c.timers, t)
+	return t
+}
+
+// Stop реализует Timer
+func (t *simulatedTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	for i, timer := range t.clock.timers {
+		if timer == t {
+			t.clock.timers = append(t.clock.timers[:i], t.clock.timers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Advance сдвигает время на d, вызывая наступившие таймеры по порядку.
+// На время вызова таймера Now возвращает момент его срабатывания; таймеры,
+// запланированные из f и наступающие до конца интервала, тоже вызываются.
+func (c *SimulatedClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	target := c.now.Add(d)
+	for {
+		sort.SliceStable(c.timers, func(i, j int) bool { return c.timers[i].at.Before(c.timers[j].at) })
+		if len(c.timers) == 0 || c.timers[0].at.After(target) {
+			break
+		}
+		t := c.timers[0]
+		c.timers = c.timers[1:]
+		c.now = t.at
+		c.mu.Unlock()
+		t.f()
+		c.mu.Lock()
+	}
+	c.now = target
+	c.mu.Unlock()
+}
+
+// Pending возвращает число запланированных и не вызванных таймеров
+func (c *SimulatedClock) Pending() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.timers)
+}
@@ -0,0 +1,111 @@
+package server_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/slonegd/go61850"
+	"github.com/slonegd/go61850/model"
+	"github.com/slonegd/go61850/osi/mms"
+	"github.com/slonegd/go61850/osi/mms/variant"
+	"github.com/slonegd/go61850/server"
+)
+
+// nopLogger отключает отладочный вывод в примерах
+type nopLogger struct{}
+
+func (nopLogger) Debug(string, ...any) {}
+
+// exampleModel создаёт модель simpleIOGenericIO из libIEC61850 с одним аналоговым входом
+// и его зоной нечувствительности db (FC CF)
+func exampleModel() *model.Model {
+	return &model.Model{
+		Name: "simpleIO",
+		LogicalDevices: []*model.LogicalDevice{{
+			Name: "simpleIOGenericIO",
+			LogicalNodes: []*model.LogicalNode{{
+				Name: "GGIO1",
+				DataObjects: []*model.DataObject{{
+					Name: "AnIn1",
+					Children: []model.DataNode{
+						&model.DataAttribute{Name: "mag", FC: mms.FCMX, Attributes: []*model.DataAttribute{
+							{Name: "f", FC: mms.FCMX, Value: variant.NewFloat32Variant(1.5)},
+						}},
+						&model.DataAttribute{Name: "db", FC: mms.FCCF, Value: variant.NewUint32Variant(0)},
+					},
+				}},
+			}},
+		}},
+	}
+}
+
+// Сервер отвечает на запросы по модели данных; соединения клиентов принимает
+// Serve (например, симулятор IED на порту 102) или mmstest/vnet в тестах
+func ExampleServer() {
+	s := server.New(exampleModel(), server.WithLogger(nopLogger{}))
+
+	if err := s.SetValue("simpleIOGenericIO", "GGIO1$MX$AnIn1$mag$f", variant.NewFloat32Variant(2.5)); err != nil {
+		fmt.Println(err)
+		return
+	}
+	result := s.Read("simpleIOGenericIO", "GGIO1$MX$AnIn1$mag$f")
+	fmt.Println(result.Success, result.Value.Float32())
+
+	result = s.Read("simpleIOGenericIO", "GGIO1$MX$AnIn2$mag$f")
+	fmt.Println(result.Success, result.Error)
+	// Output:
+	// true 2.5
+	// false data access error: object-non-existent
+}
+
+// Имена переменных домена для службы GetNameList
+func ExampleServer_GetNameList() {
+	s := server.New(exampleModel(), server.WithLogger(nopLogger{}))
+
+	names, err := s.GetNameList(mms.ObjectClassNamedVariable, mms.ScopeDomain, "simpleIOGenericIO", "")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	for _, name := range names {
+		fmt.Println(name)
+	}
+	// Output:
+	// GGIO1
+	// GGIO1$MX
+	// GGIO1$MX$AnIn1
+	// GGIO1$MX$AnIn1$mag
+	// GGIO1$MX$AnIn1$mag$f
+	// GGIO1$CF
+	// GGIO1$CF$AnIn1
+	// GGIO1$CF$AnIn1$db
+}
+
+// ServeConn обслуживает одно соединение, например принятое своим listener или
+// net.Pipe в тестах: клиент записывает атрибут, а приложение читает новое значение
+func ExampleServer_ServeConn() {
+	s := server.New(exampleModel(), server.WithLogger(nopLogger{}))
+	clientConn, serverConn := net.Pipe()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.ServeConn(ctx, serverConn)
+	defer clientConn.Close()
+
+	client, err := go61850.NewMmsClient(ctx, clientConn, go61850.WithLogger(nopLogger{}))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	if _, err := client.Initiate(ctx); err != nil {
+		fmt.Println(err)
+		return
+	}
+	if err := client.Write(ctx, "simpleIOGenericIO/GGIO1.AnIn1.db", mms.FCCF, variant.NewUint32Variant(500)); err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(s.Read("simpleIOGenericIO", "GGIO1$CF$AnIn1$db").Value)
+
+	// Output: uint32(500)
+}
@@ -0,0 +1,466 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"math"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultFileChunkSize - размер фрагмента FileRead по умолчанию
+const DefaultFileChunkSize = 4096
+
+// DefaultMaxOpenFiles - число одновременно открытых файлов (FRSM) по умолчанию
+const DefaultMaxOpenFiles = 16
+
+// FileErrorCode представляет код ошибки класса file согласно ISO/IEC 9506-2
+type FileErrorCode uint8
+
+const (
+	FileOther              FileErrorCode = 0
+	FileNameAmbiguous      FileErrorCode = 1
+	FileBusy               FileErrorCode = 2
+	FileNameSyntaxError    FileErrorCode = 3
+	FileContentTypeInvalid FileErrorCode = 4
+	FilePositionInvalid    FileErrorCode = 5
+	FileAccessDenied       FileErrorCode = 6
+	FileNonExistent        FileErrorCode = 7
+	FileDuplicateFilename  FileErrorCode = 8
+	FileInsufficientSpace  FileErrorCode = 9
+)
+
+// String возвращает строковое представление FileErrorCode
+func (c FileErrorCode) String() string {
+	switch c {
+	case FileOther:
+		return "other"
+	case FileNameAmbiguous:
+		return "filename-ambiguous"
+	case FileBusy:
+		return "file-busy"
+	case FileNameSyntaxError:
+		return "filename-syntax-error"
+	case FileContentTypeInvalid:
+		return "content-type-invalid"
+	case FilePositionInvalid:
+		return "position-invalid"
+	case FileAccessDenied:
+		return "file-access-denied"
+	case FileNonExistent:
+		return "file-non-existent"
+	case FileDuplicateFilename:
+		return "duplicate-filename"
+	case FileInsufficientSpace:
+		return "insufficient-space-in-filestore"
+	default:
+		return fmt.Sprintf("FileErrorCode(%d)", uint8(c))
+	}
+}
+
+// FileError - ошибка файловой службы, передаваемая клиенту как ServiceError класса file
+type FileError struct {
+	Code FileErrorCode
+	Name string
+	Err  error
+}
+
+// Error реализует интерфейс error
+func (e *FileError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("file %s: %s: %v", e.Name, e.Code, e.Err)
+	}
+	return fmt.Sprintf("file %s: %s", e.Name, e.Code)
+}
+
+// Unwrap возвращает исходную ошибку хранилища
+func (e *FileError) Unwrap() error { return e.Err }
+
+// fileError сопоставляет ошибку хранилища с кодом ошибки MMS
+func fileError(name string, err error) *FileError {
+	code := FileOther
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		code = FileNonExistent
+	case errors.Is(err, fs.ErrExist):
+		code = FileDuplicateFilename
+	case errors.Is(err, fs.ErrPermission):
+		code = FileAccessDenied
+	case errors.Is(err, fs.ErrInvalid):
+		code = FileNameSyntaxError
+	case errors.Is(err, ErrQuotaExceeded):
+		code = FileInsufficientSpace
+	}
+	return &FileError{Code: code, Name: name, Err: err}
+}
+
+// FileEntry описывает файл в ответе FileDirectory и FileOpen
+type FileEntry struct {
+	Name string
+	// Size - размер файла; sizeOfFile в MMS - Unsigned32, поэтому размер
+	// файлов от 4 ГиБ ограничивается math.MaxUint32
+	Size         uint32
+	LastModified time.Time
+}
+
+// openFile - открытый файл (File Read State Machine)
+type openFile struct {
+	name string
+	path string
+	file fs.File
+}
+
+// Association - ассоциация клиента, которой принадлежат открытые файлы (FRSM).
+// Как и в MMS, идентификаторы FRSM действуют только в своей ассоциации, а Release
+// закрывает оставшиеся файлы: разорванное соединение не занимает места
+// в пределе WithMaxOpenFiles.
+type Association struct {
+	server  *Server
+	release func()
+
+	mu        sync.Mutex
+	openFiles map[int32]*openFile
+	nextFRSM  int32
+	released  bool
+}
+
+// NewAssociation открывает ассоциацию для файловых служб и учитывает её в Stats
+// (как Associate). Транспорт вызывает Release при завершении соединения.
+func (s *Server) NewAssociation() *Association {
+	return &Association{server: s, release: s.Associate(), openFiles: make(map[int32]*openFile)}
+}
+
+// Release закрывает открытые в ассоциации файлы и завершает её.
+// Повторный вызов ничего не делает.
+func (a *Association) Release() {
+	a.mu.Lock()
+	if a.released {
+		a.mu.Unlock()
+		return
+	}
+	a.released = true
+	files := a.openFiles
+	a.openFiles = nil
+	a.mu.Unlock()
+
+	for frsmID, open := range files {
+		a.server.logger.Debug("file close %s on release: frsmID=%d", open.name, frsmID)
+		a.server.closeFile(open)
+	}
+	a.release()
+}
+
+// WithFileSystem задаёт хранилище файлов для файловых служб MMS.
+// Для FileDelete и FileRename хранилище должно реализовывать WritableFS.
+func WithFileSystem(fsys fs.FS) Option {
+	return func(s *Server) {
+		s.files = fsys
+	}
+}
+
+// WithFileChunkSize задаёт максимальный размер данных в одном ответе FileRead
+func WithFileChunkSize(size int) Option {
+	return func(s *Server) {
+		s.fileChunkSize = size
+	}
+}
+
+// WithMaxOpenFiles задаёт число одновременно открытых файлов во всех ассоциациях
+func WithMaxOpenFiles(n int) Option {
+	return func(s *Server) {
+		s.maxOpenFiles = n
+	}
+}
+
+// filePath преобразует имя файла MMS в путь хранилища.
+// Имена вида "/COMTRADE/rec1.cfg" считаются от корня хранилища;
+// "..", "\", ":" и пустые элементы пути запрещены.
+func filePath(name string) (string, error) {
+	p := strings.TrimPrefix(name, "/")
+	if p == "" {
+		return ".", nil
+	}
+	if strings.ContainsAny(p, "\\:\x00") || !fs.ValidPath(p) {
+		return "", &FileError{Code: FileNameSyntaxError, Name: name}
+	}
+	return p, nil
+}
+
+// fileSystem возвращает хранилище или ошибку, если файловые службы не настроены
+func (s *Server) fileSystem(name string) (fs.FS, error) {
+	if s.files == nil {
+		return nil, &FileError{Code: FileAccessDenied, Name: name, Err: errors.New("file services are not configured")}
+	}
+	return s.files, nil
+}
+
+// FileDirectory возвращает список файлов каталога name (или сведения о файле name),
+// продолжая после continueAfter, если оно задано
+func (s *Server) FileDirectory(name, continueAfter string) (_ []FileEntry, err error) {
+	defer s.countRequest("fileDirectory", &err)
+	fsys, err := s.fileSystem(name)
+	if err != nil {
+		return nil, err
+	}
+	p, err := filePath(name)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := fs.Stat(fsys, p)
+	if err != nil {
+		return nil, fileError(name, err)
+	}
+	if !info.IsDir() {
+		return []FileEntry{fileEntry(name, info)}, nil
+	}
+
+	dirEntries, err := fs.ReadDir(fsys, p)
+	if err != nil {
+		return nil, fileError(name, err)
+	}
+	entries := []FileEntry{}
+	for _, de := range dirEntries {
+		if de.IsDir() {
+			continue
+		}
+		info, err := de.Info()
+		if err != nil {
+			return nil, fileError(name, err)
+		}
+		entryName := de.Name()
+		if p != "." {
+			entryName = path.Join(p, entryName)
+		}
+		entries = append(entries, fileEntry(entryName, info))
+	}
+
+	if continueAfter != "" {
+		for i, entry := range entries {
+			if entry.Name == strings.TrimPrefix(continueAfter, "/") {
+				return entries[i+1:], nil
+			}
+		}
+		return []FileEntry{}, nil
+	}
+	return entries, nil
+}
+
+// fileEntry создаёт FileEntry по сведениям о файле
+func fileEntry(name string, info fs.FileInfo) FileEntry {
+	size := uint32(math.MaxUint32)
+	if info.Size() < math.MaxUint32 {
+		size = uint32(info.Size())
+	}
+	return FileEntry{Name: name, Size: size, LastModified: info.ModTime()}
+}
+
+// FileOpen открывает файл name для чтения с позиции position
+// и возвращает идентификатор FRSM ассоциации и сведения о файле
+func (a *Association) FileOpen(name string, position uint32) (_ int32, _ FileEntry, err error) {
+	s := a.server
+	defer s.countRequest("fileOpen", &err)
+	fsys, err := s.fileSystem(name)
+	if err != nil {
+		return 0, FileEntry{}, err
+	}
+	p, err := filePath(name)
+	if err != nil {
+		return 0, FileEntry{}, err
+	}
+
+	// Место в пределе WithMaxOpenFiles занимается до открытия, само открытие
+	// и пропуск до position выполняются без блокировки сервера
+	if !s.reserveFile(p) {
+		return 0, FileEntry{}, &FileError{Code: FileBusy, Name: name, Err: errors.New("too many open files")}
+	}
+	open, info, err := openAt(fsys, name, p, position)
+	if err != nil {
+		s.releaseFile(p)
+		return 0, FileEntry{}, err
+	}
+
+	a.mu.Lock()
+	if a.released {
+		a.mu.Unlock()
+		s.closeFile(open)
+		return 0, FileEntry{}, &FileError{Code: FileOther, Name: name, Err: errors.New("association released")}
+	}
+	a.nextFRSM++
+	frsmID := a.nextFRSM
+	a.openFiles[frsmID] = open
+	a.mu.Unlock()
+	s.logger.Debug("file open %s: frsmID=%d", name, frsmID)
+	return frsmID, fileEntry(name, info), nil
+}
+
+// openAt открывает файл p хранилища и пропускает position байт
+func openAt(fsys fs.FS, name, p string, position uint32) (*openFile, fs.FileInfo, error) {
+	file, err := fsys.Open(p)
+	if err != nil {
+		return nil, nil, fileError(name, err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, nil, fileError(name, err)
+	}
+	if info.IsDir() {
+		file.Close()
+		return nil, nil, &FileError{Code: FileContentTypeInvalid, Name: name, Err: errors.New("is a directory")}
+	}
+	if int64(position) > info.Size() {
+		file.Close()
+		return nil, nil, &FileError{Code: FilePositionInvalid, Name: name}
+	}
+	if position > 0 {
+		if _, err := io.CopyN(io.Discard, file, int64(position)); err != nil {
+			file.Close()
+			return nil, nil, fileError(name, err)
+		}
+	}
+	return &openFile{name: name, path: p, file: file}, info, nil
+}
+
+// FileRead читает очередной фрагмент открытого файла.
+// moreFollows равно false, если достигнут конец файла.
+func (a *Association) FileRead(frsmID int32) (data []byte, moreFollows bool, err error) {
+	s := a.server
+	defer s.countRequest("fileRead", &err)
+	a.mu.Lock()
+	open, ok := a.openFiles[frsmID]
+	a.mu.Unlock()
+	if !ok {
+		return nil, false, fmt.Errorf("unknown frsmID %d", frsmID)
+	}
+
+	buffer := make([]byte, s.fileChunkSize)
+	n, err := io.ReadFull(open.file, buffer)
+	switch {
+	case err == nil:
+		return buffer[:n], true, nil
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return buffer[:n], false, nil
+	default:
+		return nil, false, fileError(open.name, err)
+	}
+}
+
+// FileClose закрывает открытый файл
+func (a *Association) FileClose(frsmID int32) (err error) {
+	defer a.server.countRequest("fileClose", &err)
+	a.mu.Lock()
+	open, ok := a.openFiles[frsmID]
+	delete(a.openFiles, frsmID)
+	a.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("unknown frsmID %d", frsmID)
+	}
+	return a.server.closeFile(open)
+}
+
+// reserveFile занимает место в пределе открытых файлов сервера для пути p;
+// false - предел исчерпан
+func (s *Server) reserveFile(p string) bool {
+	s.filesMu.Lock()
+	defer s.filesMu.Unlock()
+	if s.openCount >= s.maxOpenFiles {
+		return false
+	}
+	if s.openPaths == nil {
+		s.openPaths = make(map[string]int)
+	}
+	s.openCount++
+	s.openPaths[p]++
+	return true
+}
+
+// releaseFile освобождает место, занятое reserveFile
+func (s *Server) releaseFile(p string) {
+	s.filesMu.Lock()
+	defer s.filesMu.Unlock()
+	s.openCount--
+	if s.openPaths[p]--; s.openPaths[p] == 0 {
+		delete(s.openPaths, p)
+	}
+}
+
+// closeFile закрывает файл и освобождает его место
+func (s *Server) closeFile(open *openFile) error {
+	s.releaseFile(open.path)
+	return open.file.Close()
+}
+
+// writableFS возвращает изменяемое хранилище
+func (s *Server) writableFS(name string) (WritableFS, error) {
+	fsys, err := s.fileSystem(name)
+	if err != nil {
+		return nil, err
+	}
+	w, ok := fsys.(WritableFS)
+	if !ok {
+		return nil, &FileError{Code: FileAccessDenied, Name: name, Err: errors.New("file store is read-only")}
+	}
+	return w, nil
+}
+
+// FileDelete удаляет файл name
+func (s *Server) FileDelete(name string) (err error) {
+	defer s.countRequest("fileDelete", &err)
+	fsys, err := s.writableFS(name)
+	if err != nil {
+		return err
+	}
+	p, err := filePath(name)
+	if err != nil {
+		return err
+	}
+	if p == "." {
+		return &FileError{Code: FileAccessDenied, Name: name}
+	}
+	if s.isOpen(p) {
+		return &FileError{Code: FileBusy, Name: name}
+	}
+	if err := fsys.Remove(p); err != nil {
+		return fileError(name, err)
+	}
+	return nil
+}
+
+// FileRename переименовывает файл current в newName
+func (s *Server) FileRename(current, newName string) (err error) {
+	defer s.countRequest("fileRename", &err)
+	fsys, err := s.writableFS(current)
+	if err != nil {
+		return err
+	}
+	from, err := filePath(current)
+	if err != nil {
+		return err
+	}
+	to, err := filePath(newName)
+	if err != nil {
+		return err
+	}
+	if from == "." || to == "." {
+		return &FileError{Code: FileAccessDenied, Name: current}
+	}
+	if s.isOpen(from) {
+		return &FileError{Code: FileBusy, Name: current}
+	}
+	if err := fsys.Rename(from, to); err != nil {
+		return fileError(current, err)
+	}
+	return nil
+}
+
+// isOpen проверяет, открыт ли файл по пути p в какой-либо ассоциации
+func (s *Server) isOpen(p string) bool {
+	s.filesMu.Lock()
+	defer s.filesMu.Unlock()
+	return s.openPaths[p] > 0
+}
@@ -0,0 +1,174 @@
+package server
+
+import (
+	"errors"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/slonegd/go61850/model"
+	"github.com/stretchr/testify/assert"
+)
+
+// fileCode возвращает код FileError или -1
+func fileCode(err error) int {
+	var fileErr *FileError
+	if errors.As(err, &fileErr) {
+		return int(fileErr.Code)
+	}
+	return -1
+}
+
+func TestMemFS(t *testing.T) {
+	m := NewMemFS(10)
+	assert.NoError(t, m.WriteFile("COMTRADE/rec1.cfg", []byte("12345")))
+	assert.NoError(t, m.WriteFile("COMTRADE/rec1.dat", []byte("123")))
+	assert.ErrorIs(t, m.WriteFile("COMTRADE/rec2.dat", []byte("123")), ErrQuotaExceeded)
+	// замена файла учитывает освобождаемый объём
+	assert.NoError(t, m.WriteFile("COMTRADE/rec1.dat", []byte("12345")))
+	assert.Equal(t, int64(10), m.Used())
+
+	generated := 0
+	assert.NoError(t, m.Generate("COMTRADE/live.cfg", func() ([]byte, error) {
+		generated++
+		return []byte("generated"), nil
+	}))
+	assert.NoError(t, fstest.TestFS(m, "COMTRADE/rec1.cfg", "COMTRADE/rec1.dat", "COMTRADE/live.cfg"))
+	assert.Positive(t, generated)
+
+	assert.ErrorIs(t, m.Rename("COMTRADE/rec1.cfg", "COMTRADE/rec1.dat"), os.ErrExist)
+	assert.NoError(t, m.Rename("COMTRADE/rec1.cfg", "old.cfg"))
+	assert.NoError(t, m.Remove("old.cfg"))
+	assert.Equal(t, int64(5), m.Used())
+}
+
+func TestServerFiles(t *testing.T) {
+	m := NewMemFS(0)
+	assert.NoError(t, m.WriteFile("COMTRADE/rec1.cfg", []byte("0123456789")))
+	assert.NoError(t, m.WriteFile("COMTRADE/rec1.dat", []byte("abc")))
+	assert.NoError(t, m.WriteFile("readme.txt", []byte("x")))
+	s := New(newTestModel(), WithFileSystem(m), WithFileChunkSize(4), WithMaxOpenFiles(1))
+
+	entries, err := s.FileDirectory("/", "")
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "readme.txt", entries[0].Name)
+
+	entries, err = s.FileDirectory("/COMTRADE", "COMTRADE/rec1.cfg")
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, FileEntry{Name: "COMTRADE/rec1.dat", Size: 3, LastModified: entries[0].LastModified}, entries[0])
+
+	a := s.NewAssociation()
+	defer a.Release()
+	frsmID, entry, err := a.FileOpen("/COMTRADE/rec1.cfg", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(10), entry.Size)
+
+	_, _, err = a.FileOpen("/readme.txt", 0)
+	assert.Equal(t, int(FileBusy), fileCode(err))
+	assert.Equal(t, int(FileBusy), fileCode(s.FileDelete("COMTRADE/rec1.cfg")))
+
+	var content []byte
+	for more := true; more; {
+		var data []byte
+		data, more, err = a.FileRead(frsmID)
+		assert.NoError(t, err)
+		content = append(content, data...)
+	}
+	assert.Equal(t, "23456789", string(content))
+	assert.NoError(t, a.FileClose(frsmID))
+	assert.Error(t, a.FileClose(frsmID))
+
+	assert.NoError(t, s.FileRename("/COMTRADE/rec1.cfg", "/COMTRADE/rec2.cfg"))
+	assert.NoError(t, s.FileDelete("/COMTRADE/rec2.cfg"))
+	assert.Equal(t, int(FileNonExistent), fileCode(s.FileDelete("/COMTRADE/rec2.cfg")))
+
+	for _, name := range []string{"../etc/passwd", "/COMTRADE/../../x", "C:\\x", "a//b"} {
+		_, _, err := a.FileOpen(name, 0)
+		assert.Equal(t, int(FileNameSyntaxError), fileCode(err), name)
+	}
+
+	readOnly := New(newTestModel(), WithFileSystem(fstest.MapFS{"a": {Data: []byte("a")}}))
+	assert.Equal(t, int(FileAccessDenied), fileCode(readOnly.FileDelete("a")))
+	_, err = New(&model.Model{}).FileDirectory("/", "")
+	assert.Equal(t, int(FileAccessDenied), fileCode(err))
+}
+
+func TestDirFS(t *testing.T) {
+	dir := t.TempDir()
+	outside := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "rec1.cfg"), []byte("12345"), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(outside, "secret"), []byte("secret"), 0o644))
+	assert.NoError(t, os.Symlink(filepath.Join(outside, "secret"), filepath.Join(dir, "link")))
+
+	d, err := NewDirFS(dir, 8)
+	assert.NoError(t, err)
+	defer d.Close()
+	assert.Equal(t, int64(5), d.Used())
+
+	assert.ErrorIs(t, d.WriteFile("rec2.cfg", []byte("1234")), ErrQuotaExceeded)
+	assert.NoError(t, d.WriteFile("rec2.cfg", []byte("123")))
+	assert.NoError(t, d.Rename("rec2.cfg", "rec3.cfg"))
+	assert.NoError(t, d.Remove("rec3.cfg"))
+	assert.Equal(t, int64(5), d.Used())
+
+	// символическая ссылка за пределы каталога не открывается
+	s := New(newTestModel(), WithFileSystem(d))
+	_, _, err = s.NewAssociation().FileOpen("/link", 0)
+	assert.Error(t, err)
+
+	// переименование не следует за символической ссылкой на каталог
+	assert.NoError(t, os.Symlink(outside, filepath.Join(dir, "out")))
+	assert.ErrorIs(t, d.Rename("rec1.cfg", "out/rec1.cfg"), fs.ErrPermission)
+	assert.NoError(t, os.Mkdir(filepath.Join(dir, "COMTRADE"), 0o755))
+	assert.NoError(t, d.Rename("rec1.cfg", "COMTRADE/rec1.cfg"))
+	_, err = os.Stat(filepath.Join(dir, "COMTRADE", "rec1.cfg"))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), d.Used())
+}
+
+func TestAssociationRelease(t *testing.T) {
+	m := NewMemFS(0)
+	assert.NoError(t, m.WriteFile("rec1.cfg", []byte("0123456789")))
+	s := New(newTestModel(), WithFileSystem(m), WithMaxOpenFiles(1))
+
+	dropped := s.NewAssociation()
+	frsmID, _, err := dropped.FileOpen("rec1.cfg", 0)
+	assert.NoError(t, err)
+	other := s.NewAssociation()
+	_, _, err = other.FileOpen("rec1.cfg", 0)
+	assert.Equal(t, int(FileBusy), fileCode(err))
+	assert.Equal(t, int64(2), s.Stats().ActiveAssociations)
+
+	// завершение соединения закрывает его файлы
+	dropped.Release()
+	dropped.Release()
+	assert.Equal(t, int64(1), s.Stats().ActiveAssociations)
+	_, _, err = dropped.FileRead(frsmID)
+	assert.Error(t, err)
+	_, _, err = dropped.FileOpen("rec1.cfg", 0)
+	assert.Error(t, err)
+	// FRSM принадлежат ассоциации
+	assert.Error(t, other.FileClose(frsmID))
+	otherID, _, err := other.FileOpen("rec1.cfg", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), otherID)
+	assert.Equal(t, int(FileBusy), fileCode(s.FileDelete("rec1.cfg")))
+	other.Release()
+	assert.NoError(t, s.FileDelete("rec1.cfg"))
+}
+
+// bigFileInfo - сведения о файле размером больше 4 ГиБ
+type bigFileInfo struct{ fs.FileInfo }
+
+func (bigFileInfo) Size() int64        { return 5 << 30 }
+func (bigFileInfo) ModTime() time.Time { return testTime }
+
+func TestFileEntrySize(t *testing.T) {
+	assert.Equal(t, FileEntry{Name: "big", Size: math.MaxUint32, LastModified: testTime}, fileEntry("big", bigFileInfo{}))
+}
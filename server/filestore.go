@@ -0,0 +1,414 @@
+package server
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrQuotaExceeded возвращается при записи, превышающей квоту хранилища
+var ErrQuotaExceeded = errors.New("file store quota exceeded")
+
+// WritableFS - хранилище файлов, допускающее изменение.
+// Необходимо для служб FileDelete, FileRename и ObtainFile;
+// для чтения (FileOpen, FileRead, FileDirectory) достаточно fs.FS.
+type WritableFS interface {
+	fs.FS
+	WriteFile(name string, data []byte) error
+	Remove(name string) error
+	Rename(oldName, newName string) error
+}
+
+// MemFS - хранилище файлов в памяти. Помимо записанных файлов может
+// содержать генерируемые, содержимое которых создаётся при каждом открытии
+// (например, COMTRADE осциллограммы симулятора).
+type MemFS struct {
+	mu    sync.RWMutex
+	quota int64
+	used  int64
+	files map[string]*memEntry
+	now   func() time.Time
+}
+
+// memEntry - файл MemFS
+type memEntry struct {
+	data     []byte
+	modTime  time.Time
+	generate func() ([]byte, error)
+}
+
+// NewMemFS создаёт пустое хранилище в памяти с квотой quota байт (0 - без ограничения).
+// Генерируемые файлы в квоте не учитываются.
+func NewMemFS(quota int64) *MemFS {
+	return &MemFS{quota: quota, files: make(map[string]*memEntry), now: time.Now}
+}
+
+// WriteFile создаёт или заменяет файл name
+func (m *MemFS) WriteFile(name string, data []byte) error {
+	if !fs.ValidPath(name) || name == "." {
+		return &fs.PathError{Op: "write", Path: name, Err: fs.ErrInvalid}
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.isDir(name) {
+		return &fs.PathError{Op: "write", Path: name, Err: fs.ErrExist}
+	}
+	var replaced int64
+	if old, ok := m.files[name]; ok {
+		replaced = int64(len(old.data))
+	}
+	if m.quota > 0 && m.used-replaced+int64(len(data)) > m.quota {
+		return &fs.PathError{Op: "write", Path: name, Err: ErrQuotaExceeded}
+	}
+	m.used += int64(len(data)) - replaced
+	m.files[name] = &memEntry{data: bytes.Clone(data), modTime: m.now()}
+	return nil
+}
+
+// Generate добавляет файл name, содержимое которого создаётся generate при открытии
+func (m *MemFS) Generate(name string, generate func() ([]byte, error)) error {
+	if !fs.ValidPath(name) || name == "." {
+		return &fs.PathError{Op: "generate", Path: name, Err: fs.ErrInvalid}
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if old, ok := m.files[name]; ok {
+		m.used -= int64(len(old.data))
+	}
+	m.files[name] = &memEntry{modTime: m.now(), generate: generate}
+	return nil
+}
+
+// Remove удаляет файл name
+func (m *MemFS) Remove(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entry, ok := m.files[name]
+	if !ok {
+		return &fs.PathError{Op: "remove", Path: name, Err: fs.ErrNotExist}
+	}
+	m.used -= int64(len(entry.data))
+	delete(m.files, name)
+	return nil
+}
+
+// Rename переименовывает файл oldName в newName; существующий newName не заменяется
+func (m *MemFS) Rename(oldName, newName string) error {
+	if !fs.ValidPath(newName) || newName == "." {
+		return &fs.PathError{Op: "rename", Path: newName, Err: fs.ErrInvalid}
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entry, ok := m.files[oldName]
+	if !ok {
+		return &fs.PathError{Op: "rename", Path: oldName, Err: fs.ErrNotExist}
+	}
+	if _, ok := m.files[newName]; ok || m.isDir(newName) {
+		return &fs.PathError{Op: "rename", Path: newName, Err: fs.ErrExist}
+	}
+	delete(m.files, oldName)
+	m.files[newName] = entry
+	return nil
+}
+
+// Open реализует fs.FS. Каталоги существуют неявно по путям файлов.
+func (m *MemFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	m.mu.RLock()
+	entry, ok := m.files[name]
+	if !ok {
+		defer m.mu.RUnlock()
+		if !m.isDir(name) {
+			return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+		}
+		return &memDir{info: memInfo{name: path.Base(name), dir: true}, entries: m.readDir(name)}, nil
+	}
+	m.mu.RUnlock()
+
+	data := entry.data
+	if entry.generate != nil {
+		var err error
+		if data, err = entry.generate(); err != nil {
+			return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+		}
+	}
+	info := memInfo{name: path.Base(name), size: int64(len(data)), modTime: entry.modTime}
+	return &memFile{info: info, Reader: bytes.NewReader(data)}, nil
+}
+
+// Used возвращает занятый файлами объём в байтах
+func (m *MemFS) Used() int64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.used
+}
+
+// isDir проверяет, является ли name каталогом (корнем или префиксом пути файла)
+func (m *MemFS) isDir(name string) bool {
+	if name == "." {
+		return true
+	}
+	prefix := name + "/"
+	for file := range m.files {
+		if strings.HasPrefix(file, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// readDir возвращает элементы каталога dir, упорядоченные по имени
+func (m *MemFS) readDir(dir string) []fs.DirEntry {
+	prefix := dir + "/"
+	if dir == "." {
+		prefix = ""
+	}
+	seen := make(map[string]fs.DirEntry)
+	for file, entry := range m.files {
+		rest, ok := strings.CutPrefix(file, prefix)
+		if !ok {
+			continue
+		}
+		if sub, _, isSub := strings.Cut(rest, "/"); isSub {
+			seen[sub] = fs.FileInfoToDirEntry(memInfo{name: sub, dir: true})
+			continue
+		}
+		seen[rest] = &memDirEntry{name: rest, entry: entry}
+	}
+	entries := make([]fs.DirEntry, 0, len(seen))
+	for _, e := range seen {
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+	return entries
+}
+
+// memInfo реализует fs.FileInfo для MemFS
+type memInfo struct {
+	name    string
+	size    int64
+	modTime time.Time
+	dir     bool
+}
+
+func (i memInfo) Name() string       { return i.name }
+func (i memInfo) Size() int64        { return i.size }
+func (i memInfo) ModTime() time.Time { return i.modTime }
+func (i memInfo) IsDir() bool        { return i.dir }
+func (i memInfo) Sys() any           { return nil }
+
+func (i memInfo) Mode() fs.FileMode {
+	if i.dir {
+		return fs.ModeDir | 0o555
+	}
+	return 0o444
+}
+
+// memDirEntry - элемент каталога MemFS. Сведения о генерируемом файле
+// определяются при вызове Info, так как размер известен только после генерации.
+type memDirEntry struct {
+	name  string
+	entry *memEntry
+}
+
+func (e *memDirEntry) Name() string      { return e.name }
+func (e *memDirEntry) IsDir() bool       { return false }
+func (e *memDirEntry) Type() fs.FileMode { return 0 }
+
+// Info реализует fs.DirEntry
+func (e *memDirEntry) Info() (fs.FileInfo, error) {
+	data := e.entry.data
+	if e.entry.generate != nil {
+		var err error
+		if data, err = e.entry.generate(); err != nil {
+			return nil, err
+		}
+	}
+	return memInfo{name: e.name, size: int64(len(data)), modTime: e.entry.modTime}, nil
+}
+
+// memFile - открытый файл MemFS
+type memFile struct {
+	*bytes.Reader
+	info memInfo
+}
+
+func (f *memFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+func (f *memFile) Close() error               { return nil }
+
+// memDir - открытый каталог MemFS
+type memDir struct {
+	info    memInfo
+	entries []fs.DirEntry
+	pos     int
+}
+
+func (d *memDir) Stat() (fs.FileInfo, error) { return d.info, nil }
+func (d *memDir) Close() error               { return nil }
+
+func (d *memDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.info.name, Err: fs.ErrInvalid}
+}
+
+// ReadDir реализует fs.ReadDirFile
+func (d *memDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	rest := d.entries[d.pos:]
+	if n <= 0 {
+		d.pos = len(d.entries)
+		return rest, nil
+	}
+	if len(rest) == 0 {
+		return nil, io.EOF
+	}
+	n = min(n, len(rest))
+	d.pos += n
+	return rest[:n], nil
+}
+
+// DirFS - хранилище файлов в каталоге на диске с квотой.
+// Доступ выполняется через os.Root, поэтому символические ссылки
+// и пути с ".." не позволяют выйти за пределы каталога.
+type DirFS struct {
+	fs.FS
+	root  *os.Root
+	mu    sync.Mutex
+	quota int64
+	used  int64
+}
+
+// NewDirFS открывает каталог dir как хранилище с квотой quota байт (0 - без ограничения).
+// Занятый объём определяется по уже существующим файлам.
+func NewDirFS(dir string, quota int64) (*DirFS, error) {
+	root, err := os.OpenRoot(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file store %s: %w", dir, err)
+	}
+	d := &DirFS{FS: root.FS(), root: root, quota: quota}
+	err = fs.WalkDir(d.FS, ".", func(_ string, entry fs.DirEntry, err error) error {
+		if err != nil || !entry.Type().IsRegular() {
+			return err
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		d.used += info.Size()
+		return nil
+	})
+	if err != nil {
+		root.Close()
+		return nil, fmt.Errorf("failed to scan file store %s: %w", dir, err)
+	}
+	return d, nil
+}
+
+// WriteFile создаёт или заменяет файл name
+func (d *DirFS) WriteFile(name string, data []byte) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var replaced int64
+	if info, err := d.root.Stat(name); err == nil {
+		replaced = info.Size()
+	}
+	if d.quota > 0 && d.used-replaced+int64(len(data)) > d.quota {
+		return &fs.PathError{Op: "write", Path: name, Err: ErrQuotaExceeded}
+	}
+	if err := d.writeFile(name, data); err != nil {
+		return err
+	}
+	d.used += int64(len(data)) - replaced
+	return nil
+}
+
+// writeFile записывает файл через os.Root
+func (d *DirFS) writeFile(name string, data []byte) error {
+	f, err := d.root.Create(name)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Remove удаляет файл name
+func (d *DirFS) Remove(name string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	info, err := d.root.Stat(name)
+	if err != nil {
+		return err
+	}
+	if err := d.root.Remove(name); err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		d.used -= info.Size()
+	}
+	return nil
+}
+
+// Rename переименовывает файл oldName в newName атомарно (os.Rename);
+// существующий newName не заменяется. Каталоги обоих путей не должны быть
+// символическими ссылками, чтобы переименование не вышло за пределы хранилища.
+func (d *DirFS) Rename(oldName, newName string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, err := d.root.Lstat(newName); err == nil {
+		return &fs.PathError{Op: "rename", Path: newName, Err: fs.ErrExist}
+	}
+	if _, err := d.root.Lstat(oldName); err != nil {
+		return err
+	}
+	for _, name := range []string{oldName, newName} {
+		if err := d.checkDir(path.Dir(name)); err != nil {
+			return err
+		}
+	}
+	return os.Rename(d.osPath(oldName), d.osPath(newName))
+}
+
+// checkDir проверяет, что каталог dir хранилища и его предки существуют
+// и не являются символическими ссылками
+func (d *DirFS) checkDir(dir string) error {
+	for ; dir != "."; dir = path.Dir(dir) {
+		info, err := d.root.Lstat(dir)
+		if err != nil {
+			return err
+		}
+		if info.Mode()&fs.ModeSymlink != 0 {
+			return &fs.PathError{Op: "rename", Path: dir, Err: fs.ErrPermission}
+		}
+	}
+	return nil
+}
+
+// osPath возвращает путь файла name хранилища в файловой системе
+func (d *DirFS) osPath(name string) string {
+	return filepath.Join(d.root.Name(), filepath.FromSlash(name))
+}
+
+// Used возвращает занятый файлами объём в байтах
+func (d *DirFS) Used() int64 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.used
+}
+
+// Close закрывает каталог хранилища
+func (d *DirFS) Close() error {
+	return d.root.Close()
+}
@@ -0,0 +1,209 @@
+package server
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/slonegd/go61850/model"
+	"github.com/slonegd/go61850/osi/mms/variant"
+)
+
+// ErrJournalNotFound возвращается при обращении к журналу, отсутствующему в модели
+var ErrJournalNotFound = errors.New("journal not found")
+
+// JournalData - значение, записанное в журнал (элемент entryContent)
+type JournalData struct {
+	// Reference - ссылка на атрибут данных "LD/LN.DO.DA"
+	Reference string
+	Value     *variant.Variant
+	// ReasonCode - причина записи
+	ReasonCode model.Trigger
+}
+
+// JournalEntry - запись журнала (LOG) согласно IEC 61850-7-2
+type JournalEntry struct {
+	EntryID model.EntryID
+	Time    model.TimeOfEntry
+	Data    []JournalData
+}
+
+// JournalQuery задаёт диапазон записей для ReadJournal.
+// Нулевые значения полей означают отсутствие ограничения.
+type JournalQuery struct {
+	// Start и Stop - границы времени записей включительно
+	Start time.Time
+	Stop  time.Time
+	// StartAfter - вернуть записи после записи с этим EntryID (entryToStartAfter)
+	StartAfter model.EntryID
+	// Limit - максимальное число записей (numberOfEntries)
+	Limit int
+}
+
+// JournalStore - хранилище журналов сервера.
+// journal - полное имя журнала "LD/LN$Journal", например "simpleIOGenericIO/LLN0$EventLog".
+type JournalStore interface {
+	// Append добавляет запись, присваивая ей EntryID, и возвращает присвоенный EntryID
+	Append(journal string, entry JournalEntry) (model.EntryID, error)
+	// Query возвращает записи журнала в порядке EntryID и признак наличия
+	// следующих записей при ограничении Limit
+	Query(journal string, query JournalQuery) (entries []JournalEntry, moreFollows bool, err error)
+	// Count возвращает число записей журнала (ReportJournalStatus)
+	Count(journal string) (int, error)
+	// Clear удаляет записи до entryID включительно (нулевой - все) и возвращает
+	// число удалённых записей (InitializeJournal)
+	Clear(journal string, entryID model.EntryID) (int, error)
+}
+
+// MemJournalStore - хранилище журналов в памяти. При заполнении журнала
+// до capacity записей самые старые записи вытесняются.
+type MemJournalStore struct {
+	mu       sync.Mutex
+	capacity int
+	nextID   uint64
+	journals map[string][]JournalEntry
+}
+
+// NewMemJournalStore создаёт хранилище с ограничением capacity записей на журнал
+// (0 - без ограничения)
+func NewMemJournalStore(capacity int) *MemJournalStore {
+	return &MemJournalStore{capacity: capacity, journals: make(map[string][]JournalEntry)}
+}
+
+// Append реализует JournalStore. EntryID выдаются монотонно в пределах хранилища.
+func (m *MemJournalStore) Append(journal string, entry JournalEntry) (model.EntryID, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.nextID++
+	binary.BigEndian.PutUint64(entry.EntryID[:], m.nextID)
+	entries := append(m.journals[journal], entry)
+	if m.capacity > 0 && len(entries) > m.capacity {
+		entries = slices.Delete(entries, 0, len(entries)-m.capacity)
+	}
+	m.journals[journal] = entries
+	return entry.EntryID, nil
+}
+
+// Query реализует JournalStore
+func (m *MemJournalStore) Query(journal string, query JournalQuery) ([]JournalEntry, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var start, stop model.TimeOfEntry
+	if !query.Start.IsZero() {
+		start = model.NewTimeOfEntry(query.Start)
+	}
+	if !query.Stop.IsZero() {
+		stop = model.NewTimeOfEntry(query.Stop)
+	}
+
+	result := []JournalEntry{}
+	for _, entry := range m.journals[journal] {
+		if !query.StartAfter.IsZero() && entry.EntryID.Compare(query.StartAfter) <= 0 {
+			continue
+		}
+		if !query.Start.IsZero() && entry.Time.Before(start) {
+			continue
+		}
+		if !query.Stop.IsZero() && stop.Before(entry.Time) {
+			break
+		}
+		if query.Limit > 0 && len(result) == query.Limit {
+			return result, true, nil
+		}
+		result = append(result, entry)
+	}
+	return result, false, nil
+}
+
+// Count реализует JournalStore
+func (m *MemJournalStore) Count(journal string) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.journals[journal]), nil
+}
+
+// Clear реализует JournalStore
+func (m *MemJournalStore) Clear(journal string, entryID model.EntryID) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entries := m.journals[journal]
+	n := len(entries)
+	if !entryID.IsZero() {
+		n, _ = slices.BinarySearchFunc(entries, entryID, func(e JournalEntry, id model.EntryID) int {
+			return e.EntryID.Compare(id)
+		})
+		if n < len(entries) && entries[n].EntryID == entryID {
+			n++
+		}
+	}
+	m.journals[journal] = slices.Delete(entries, 0, n)
+	return n, nil
+}
+
+// WithJournalStore задаёт хранилище журналов сервера
+func WithJournalStore(store JournalStore) Option {
+	return func(s *Server) {
+		s.journals = store
+	}
+}
+
+// journalName проверяет наличие журнала в модели и возвращает его полное имя
+func (s *Server) journalName(domainID, journal string) (string, error) {
+	if s.journals == nil {
+		return "", errors.New("journal store is not configured")
+	}
+	ld := s.model.LogicalDevice(domainID)
+	if ld == nil || !slices.Contains(ld.Journals, journal) {
+		return "", fmt.Errorf("%w: %s/%s", ErrJournalNotFound, domainID, journal)
+	}
+	return domainID + "/" + journal, nil
+}
+
+// LogEntry добавляет в журнал journal домена domainID запись с текущим временем
+func (s *Server) LogEntry(domainID, journal string, data ...JournalData) (model.EntryID, error) {
+	name, err := s.journalName(domainID, journal)
+	if err != nil {
+		return model.EntryID{}, err
+	}
+	entry := JournalEntry{Time: model.NewTimeOfEntry(s.clock.Now()), Data: data}
+	id, err := s.journals.Append(name, entry)
+	if err != nil {
+		return model.EntryID{}, fmt.Errorf("failed to append to journal %s: %w", name, err)
+	}
+	s.logger.Debug("journal %s: entry %s", name, id)
+	return id, nil
+}
+
+// ReadJournal возвращает записи журнала journal домена domainID (служба ReadJournal)
+func (s *Server) ReadJournal(domainID, journal string, query JournalQuery) (_ []JournalEntry, _ bool, err error) {
+	defer s.countRequest("readJournal", &err)
+	name, err := s.journalName(domainID, journal)
+	if err != nil {
+		return nil, false, err
+	}
+	return s.journals.Query(name, query)
+}
+
+// JournalStatus возвращает число записей журнала (служба ReportJournalStatus)
+func (s *Server) JournalStatus(domainID, journal string) (_ int, err error) {
+	defer s.countRequest("reportJournalStatus", &err)
+	name, err := s.journalName(domainID, journal)
+	if err != nil {
+		return 0, err
+	}
+	return s.journals.Count(name)
+}
+
+// InitializeJournal удаляет записи журнала до entryID включительно
+// (нулевой - все) и возвращает число удалённых записей
+func (s *Server) InitializeJournal(domainID, journal string, entryID model.EntryID) (_ int, err error) {
+	defer s.countRequest("initializeJournal", &err)
+	name, err := s.journalName(domainID, journal)
+	if err != nil {
+		return 0, err
+	}
+	return s.journals.Clear(name, entryID)
+}
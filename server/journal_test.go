@@ -0,0 +1,62 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/slonegd/go61850/model"
+	"github.com/slonegd/go61850/osi/mms/variant"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJournal(t *testing.T) {
+	clock := NewSimulatedClock(testTime)
+	s := New(newTestModel(), WithJournalStore(NewMemJournalStore(3)), WithClock(clock))
+
+	var ids []model.EntryID
+	for i := range 4 {
+		clock.now = testTime.Add(time.Duration(i) * time.Second)
+		id, err := s.LogEntry("simpleIOGenericIO", "LLN0$EventLog", JournalData{
+			Reference:  "simpleIOGenericIO/GGIO1.AnIn1.mag.f",
+			Value:      variant.NewFloat32Variant(float32(i)),
+			ReasonCode: model.TriggerDataChange,
+		})
+		assert.NoError(t, err)
+		ids = append(ids, id)
+	}
+	assert.Equal(t, "0000000000000004", ids[3].String())
+
+	// первая запись вытеснена при заполнении журнала
+	count, err := s.JournalStatus("simpleIOGenericIO", "LLN0$EventLog")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	entries, more, err := s.ReadJournal("simpleIOGenericIO", "LLN0$EventLog", JournalQuery{
+		Start: testTime.Add(2 * time.Second),
+	})
+	assert.NoError(t, err)
+	assert.False(t, more)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, ids[2], entries[0].EntryID)
+	assert.Equal(t, testTime.Add(2*time.Second), entries[0].Time.Time())
+
+	entries, more, err = s.ReadJournal("simpleIOGenericIO", "LLN0$EventLog", JournalQuery{StartAfter: ids[1], Limit: 1})
+	assert.NoError(t, err)
+	assert.True(t, more)
+	assert.Equal(t, []model.EntryID{ids[2]}, []model.EntryID{entries[0].EntryID})
+
+	entries, _, err = s.ReadJournal("simpleIOGenericIO", "LLN0$EventLog", JournalQuery{Stop: testTime.Add(2 * time.Second)})
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+
+	deleted, err := s.InitializeJournal("simpleIOGenericIO", "LLN0$EventLog", ids[2])
+	assert.NoError(t, err)
+	assert.Equal(t, 2, deleted)
+	count, _ = s.JournalStatus("simpleIOGenericIO", "LLN0$EventLog")
+	assert.Equal(t, 1, count)
+
+	_, err = s.LogEntry("simpleIOGenericIO", "LLN0$Other")
+	assert.ErrorIs(t, err, ErrJournalNotFound)
+	_, err = New(newTestModel()).LogEntry("simpleIOGenericIO", "LLN0$EventLog")
+	assert.EqualError(t, err, "journal store is not configured")
+}
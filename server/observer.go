@@ -0,0 +1,121 @@
+package server
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/slonegd/go61850/model"
+	"github.com/slonegd/go61850/osi/mms/variant"
+)
+
+// ValueChange описывает изменение атрибута модели
+type ValueChange struct {
+	DomainID string
+	// ItemID - MMS имя атрибута, например "GGIO1$MX$AnIn1$mag$f"
+	ItemID string
+	Old    *variant.Variant
+	New    *variant.Variant
+	// Reason - обнаруженные условия (dchg, qchg, dupd), разрешённые Triggers атрибута
+	// и маской наблюдателя
+	Reason model.Trigger
+}
+
+// Observer получает изменения значений модели
+type Observer func(change ValueChange)
+
+// DataSetObserver получает изменения элемента member набора данных
+type DataSetObserver func(member int, change ValueChange)
+
+// observer - зарегистрированный наблюдатель
+type observer struct {
+	id       uint64
+	domainID string
+	// members - MMS имена наблюдаемых объектов (имя атрибута или его предка)
+	members  []string
+	triggers model.Trigger
+	notify   DataSetObserver
+}
+
+// match возвращает индекс наблюдаемого объекта, которому принадлежит itemID, или -1
+func (o *observer) match(domainID, itemID string) int {
+	if o.domainID != domainID {
+		return -1
+	}
+	for i, member := range o.members {
+		if itemID == member || strings.HasPrefix(itemID, member+"$") {
+			return i
+		}
+	}
+	return -1
+}
+
+// Observe регистрирует наблюдателя изменений атрибута itemID домена domainID
+// или всех атрибутов его потомков (например, "GGIO1$MX$AnIn1" - все атрибуты DO с FC MX).
+// triggers ограничивает условия, о которых сообщается. Возвращает функцию отмены.
+func (s *Server) Observe(domainID, itemID string, triggers model.Trigger, fn Observer) (cancel func()) {
+	return s.addObserver(domainID, []string{itemID}, triggers, func(_ int, change ValueChange) {
+		fn(change)
+	})
+}
+
+// ObserveDataSet регистрирует наблюдателя изменений элементов набора данных dataSet
+// домена domainID (для оценки условий RCB и GoCB). triggers - TrgOps блока управления.
+func (s *Server) ObserveDataSet(domainID, dataSet string, triggers model.Trigger, fn DataSetObserver) (cancel func(), err error) {
+	ld := s.model.LogicalDevice(domainID)
+	if ld == nil {
+		return nil, fmt.Errorf("domain %s not found", domainID)
+	}
+	for _, ds := range ld.DataSets {
+		if ds.Name == dataSet {
+			return s.addObserver(domainID, ds.Members, triggers, fn), nil
+		}
+	}
+	return nil, fmt.Errorf("data set %s not found in %s", dataSet, domainID)
+}
+
+// addObserver добавляет наблюдателя
+func (s *Server) addObserver(domainID string, members []string, triggers model.Trigger, fn DataSetObserver) func() {
+	s.observersMu.Lock()
+	defer s.observersMu.Unlock()
+	s.nextObserver++
+	id := s.nextObserver
+	s.observers = append(s.observers, &observer{
+		id: id, domainID: domainID, members: members, triggers: triggers, notify: fn,
+	})
+	return func() {
+		s.observersMu.Lock()
+		defer s.observersMu.Unlock()
+		for i, o := range s.observers {
+			if o.id == id {
+				s.observers = append(s.observers[:i:i], s.observers[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// SetValue изменяет значение листового атрибута itemID домена domainID
+// ("LN$FC$DO$DA...") и уведомляет наблюдателей. Условия определяются
+// по Triggers атрибута: dchg и qchg - при изменении значения, dupd - при любой записи.
+func (s *Server) SetValue(domainID, itemID string, value *variant.Variant) error {
+	return s.Transaction(func(tx *Tx) error {
+		return tx.SetValue(domainID, itemID, value)
+	})
+}
+
+// notify передаёт изменение наблюдателям с подходящими условиями
+func (s *Server) notify(change ValueChange) {
+	s.observersMu.Lock()
+	observers := append([]*observer(nil), s.observers...)
+	s.observersMu.Unlock()
+
+	for _, o := range observers {
+		member := o.match(change.DomainID, change.ItemID)
+		if member < 0 || change.Reason&o.triggers == 0 {
+			continue
+		}
+		c := change
+		c.Reason &= o.triggers
+		o.notify(member, c)
+	}
+}
@@ -0,0 +1,91 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/slonegd/go61850/model"
+	"github.com/slonegd/go61850/osi/mms"
+	"github.com/slonegd/go61850/osi/mms/variant"
+	"github.com/slonegd/go61850/scl"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestObserver(t *testing.T) {
+	m := newTestModel()
+	ln := m.LogicalDevice("simpleIOGenericIO").LogicalNode("GGIO1")
+	for _, do := range []string{"AnIn1", "AnIn2"} {
+		mag, _, err := ln.Attribute(mms.FCMX, do, "mag")
+		assert.NoError(t, err)
+		mag.Triggers = model.TriggerDataChange | model.TriggerDataUpdate
+		q, _, err := ln.Attribute(mms.FCMX, do, "q")
+		assert.NoError(t, err)
+		q.Triggers = model.TriggerQualityChange
+	}
+	s := New(m)
+
+	var changes []ValueChange
+	cancel := s.Observe("simpleIOGenericIO", "GGIO1$MX$AnIn1", model.TriggerDataChange|model.TriggerQualityChange,
+		func(change ValueChange) { changes = append(changes, change) })
+
+	type memberChange struct {
+		member int
+		reason model.Trigger
+	}
+	var dsChanges []memberChange
+	cancelDS, err := s.ObserveDataSet("simpleIOGenericIO", "LLN0$Measurements", model.TriggerDataUpdate,
+		func(member int, change ValueChange) {
+			dsChanges = append(dsChanges, memberChange{member, change.Reason})
+		})
+	assert.NoError(t, err)
+	defer cancelDS()
+
+	// компонент mag.f наследует условия mag
+	assert.NoError(t, s.SetValue("simpleIOGenericIO", "GGIO1$MX$AnIn1$mag$f", variant.NewFloat32Variant(3)))
+	// запись того же значения - только dupd
+	assert.NoError(t, s.SetValue("simpleIOGenericIO", "GGIO1$MX$AnIn1$mag$f", variant.NewFloat32Variant(3)))
+	assert.NoError(t, s.SetValue("simpleIOGenericIO", "GGIO1$MX$AnIn1$q", variant.NewBitStringVariant([]byte{0x40, 0}, 13)))
+	// t не имеет условий
+	assert.NoError(t, s.SetValue("simpleIOGenericIO", "GGIO1$MX$AnIn1$t", variant.NewUTCTimeVariant(testTime.Add(1))))
+	assert.NoError(t, s.SetValue("simpleIOGenericIO", "GGIO1$MX$AnIn2$mag$f", variant.NewFloat32Variant(4)))
+
+	assert.Len(t, changes, 2)
+	assert.Equal(t, "GGIO1$MX$AnIn1$mag$f", changes[0].ItemID)
+	assert.Equal(t, model.TriggerDataChange, changes[0].Reason)
+	assert.Equal(t, float32(1.5), changes[0].Old.Float32())
+	assert.Equal(t, model.TriggerQualityChange, changes[1].Reason)
+	assert.Equal(t, []memberChange{
+		{0, model.TriggerDataUpdate}, {0, model.TriggerDataUpdate}, {1, model.TriggerDataUpdate},
+	}, dsChanges)
+
+	result := s.Read("simpleIOGenericIO", "GGIO1$MX$AnIn1$mag$f")
+	assert.Equal(t, float32(3), result.Value.Float32())
+
+	cancel()
+	assert.NoError(t, s.SetValue("simpleIOGenericIO", "GGIO1$MX$AnIn1$mag$f", variant.NewFloat32Variant(5)))
+	assert.Len(t, changes, 2)
+
+	assert.Error(t, s.SetValue("simpleIOGenericIO", "GGIO1$MX$AnIn1$mag", variant.NewFloat32Variant(5)))
+	assert.Error(t, s.SetValue("simpleIOGenericIO", "GGIO1$MX$AnIn9$q", variant.NewFloat32Variant(5)))
+	_, err = s.ObserveDataSet("simpleIOGenericIO", "LLN0$Missing", 0, nil)
+	assert.EqualError(t, err, "data set LLN0$Missing not found in simpleIOGenericIO")
+}
+
+func TestObserverSCLTriggers(t *testing.T) {
+	s, err := scl.ParseFile("../scl/testdata/simpleIO.scd")
+	assert.NoError(t, err)
+	m, err := s.Model("simpleIO")
+	assert.NoError(t, err)
+	server := New(m)
+
+	var reasons []model.Trigger
+	cancel, err := server.ObserveDataSet("simpleIOGenericIO", "LLN0$Events", model.TriggerDataChange|model.TriggerQualityChange,
+		func(_ int, change ValueChange) { reasons = append(reasons, change.Reason) })
+	assert.NoError(t, err)
+	defer cancel()
+
+	// mag - dchg, q - qchg, t - без условий (simpleIO.scd)
+	assert.NoError(t, server.SetValue("simpleIOGenericIO", "GGIO1$MX$AnIn1$mag$f", variant.NewFloat32Variant(1)))
+	assert.NoError(t, server.SetValue("simpleIOGenericIO", "GGIO1$MX$AnIn2$q", variant.NewBitStringVariant([]byte{0x40, 0}, 13)))
+	assert.NoError(t, server.SetValue("simpleIOGenericIO", "GGIO1$MX$AnIn2$t", variant.NewUTCTimeVariant(testTime)))
+	assert.Equal(t, []model.Trigger{model.TriggerDataChange, model.TriggerQualityChange}, reasons)
+}
@@ -0,0 +1,271 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/slonegd/go61850/model"
+	"github.com/slonegd/go61850/osi/mms/variant"
+)
+
+// ReportControl описывает блок управления отчётами (URCB или BRCB)
+type ReportControl struct {
+	// Name - MMS имя блока, например "LLN0$RP$EventsRCB"
+	Name  string
+	RptID string
+	// DataSet - MMS имя набора данных домена, например "LLN0$Events"
+	DataSet string
+	// TrgOps - условия запуска: dchg, qchg, dupd и period (integrity)
+	TrgOps model.Trigger
+	// IntgPd - период integrity отчётов; 0 - не передаются
+	IntgPd time.Duration
+	// BufTm - время накопления событий в одном отчёте; 0 - отчёт на каждое событие
+	BufTm    time.Duration
+	Buffered bool
+}
+
+// Report - отчёт, сформированный блоком управления
+type Report struct {
+	RptID string
+	// SqNum - порядковый номер отчёта (INT8U для URCB, INT16U для BRCB)
+	SqNum   uint16
+	DataSet string
+	// Reasons - причины включения по элементам набора; 0 - элемент не включён
+	Reasons []model.Trigger
+	// Values - значения включённых элементов на момент отправки; nil для невключённых
+	Values []*variant.Variant
+	Time   time.Time
+}
+
+// ReportSink получает сформированные отчёты. Вызывается последовательно
+// для одного блока управления, в порядке SqNum и без блокировок блока:
+// sink может изменять значения модели (SetValue) и выключать блок.
+type ReportSink func(Report)
+
+// reportControl - состояние включённого блока управления
+type reportControl struct {
+	server   *Server
+	domainID string
+	config   ReportControl
+	members  int
+	sink     ReportSink
+
+	mu sync.Mutex
+	// pending - причины событий, накопленных за BufTm; nil - буфер пуст
+	pending  []model.Trigger
+	bufTimer Timer
+	// bufGen - поколение буфера BufTm: таймер, сработавший после отправки
+	// своего буфера или выключения блока, видит другое поколение
+	bufGen    uint64
+	intgTimer Timer
+	// intgStart и intgCount задают моменты integrity отчётов без накопления погрешности;
+	// intgCount также служит поколением таймера integrity
+	intgStart time.Time
+	intgCount int64
+	sqNum     uint16
+	disabled  bool
+	cancel    func()
+	// outbox - сформированные, но ещё не переданные в sink отчёты;
+	// delivering - outbox передаётся (см. deliver)
+	outbox     []Report
+	delivering bool
+}
+
+// EnableReport включает блок управления rc домена domainID: события элементов набора
+// по TrgOps объединяются за BufTm, integrity отчёты отправляются каждые IntgPd.
+// Возвращает функцию выключения.
+func (s *Server) EnableReport(domainID string, rc ReportControl, sink ReportSink) (disable func(), err error) {
+	ld := s.model.LogicalDevice(domainID)
+	if ld == nil {
+		return nil, fmt.Errorf("domain %s not found", domainID)
+	}
+	var members int
+	for _, ds := range ld.DataSets {
+		if ds.Name == rc.DataSet {
+			members = len(ds.Members)
+		}
+	}
+	if members == 0 {
+		return nil, fmt.Errorf("data set %s not found or empty in %s", rc.DataSet, domainID)
+	}
+	if rc.TrgOps.Has(model.TriggerIntegrity) && rc.IntgPd <= 0 {
+		return nil, errors.New("integrity trigger requires positive IntgPd")
+	}
+
+	r := &reportControl{server: s, domainID: domainID, config: rc, members: members, sink: sink}
+	events := rc.TrgOps & (model.TriggerDataChange | model.TriggerQualityChange | model.TriggerDataUpdate)
+	if events != 0 {
+		r.cancel, err = s.ObserveDataSet(domainID, rc.DataSet, events, r.event)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if rc.TrgOps.Has(model.TriggerIntegrity) {
+		r.mu.Lock()
+		r.intgStart = s.clock.Now()
+		r.scheduleIntegrity()
+		r.mu.Unlock()
+	}
+	s.logger.Debug("report %s/%s enabled: trgOps=%s intgPd=%s bufTm=%s", domainID, rc.Name, rc.TrgOps, rc.IntgPd, rc.BufTm)
+	return r.disable, nil
+}
+
+// event обрабатывает изменение элемента набора данных
+func (r *reportControl) event(member int, change ValueChange) {
+	// deliver выполняется после снятия r.mu
+	defer r.deliver()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.disabled {
+		return
+	}
+
+	if r.config.BufTm <= 0 {
+		reasons := make([]model.Trigger, r.members)
+		reasons[member] = change.Reason
+		r.send(reasons)
+		return
+	}
+
+	// Повторное событие элемента в буфере отправляет накопленный отчёт
+	// и начинает новый буфер (IEC 61850-7-2, BufTm)
+	if r.pending != nil && r.pending[member] != 0 {
+		r.flush()
+	}
+	if r.pending == nil {
+		r.pending = make([]model.Trigger, r.members)
+		r.bufGen++
+		gen := r.bufGen
+		r.bufTimer = r.server.clock.AfterFunc(r.config.BufTm, func() { r.bufTimeout(gen) })
+	}
+	r.pending[member] |= change.Reason
+}
+
+// bufTimeout отправляет отчёт буфера поколения gen по истечении BufTm
+func (r *reportControl) bufTimeout(gen uint64) {
+	defer r.deliver()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.disabled && gen == r.bufGen {
+		r.flush()
+	}
+}
+
+// flush отправляет накопленные события
+func (r *reportControl) flush() {
+	if r.pending == nil {
+		return
+	}
+	r.bufTimer.Stop()
+	r.bufGen++
+	reasons := r.pending
+	r.pending = nil
+	r.send(reasons)
+}
+
+// scheduleIntegrity планирует следующий integrity отчёт
+func (r *reportControl) scheduleIntegrity() {
+	r.intgCount++
+	count := r.intgCount
+	deadline := r.intgStart.Add(time.Duration(count) * r.config.IntgPd)
+	r.intgTimer = r.server.clock.AfterFunc(deadline.Sub(r.server.clock.Now()), func() { r.integrity(count) })
+}
+
+// integrity отправляет integrity отчёт со всеми элементами набора.
+// Накопленные события отправляются отдельным отчётом до него.
+// count - номер отчёта, для которого запланирован таймер.
+func (r *reportControl) integrity(count int64) {
+	defer r.deliver()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.disabled || count != r.intgCount {
+		return
+	}
+	r.flush()
+	reasons := make([]model.Trigger, r.members)
+	for i := range reasons {
+		reasons[i] = model.TriggerIntegrity
+	}
+	r.send(reasons)
+	r.scheduleIntegrity()
+}
+
+// send формирует отчёт с текущими значениями включённых элементов и ставит
+// его в outbox; вызывается под r.mu
+func (r *reportControl) send(reasons []model.Trigger) {
+	ld := r.server.model.LogicalDevice(r.domainID)
+	var members []string
+	for _, ds := range ld.DataSets {
+		if ds.Name == r.config.DataSet {
+			members = ds.Members
+		}
+	}
+
+	// Включённые элементы читаются одним снимком (см. Server.Transaction)
+	values := make([]*variant.Variant, len(reasons))
+	for i, result := range r.server.readMembers(r.domainID, members, reasons) {
+		if i < len(values) {
+			values[i] = result.Value
+		}
+	}
+	report := Report{
+		RptID:   r.config.RptID,
+		SqNum:   r.sqNum,
+		DataSet: r.config.DataSet,
+		Reasons: reasons,
+		Values:  values,
+		Time:    r.server.clock.Now(),
+	}
+	r.sqNum++
+	if !r.config.Buffered && r.sqNum > 0xff {
+		r.sqNum = 0
+	}
+	r.server.stats.report()
+	r.outbox = append(r.outbox, report)
+}
+
+// deliver передаёт отчёты outbox в sink по одному вне r.mu. Если передачу уже
+// ведёт другой вызов (в том числе вложенный - из sink, изменившего значение
+// модели), новые отчёты передаст он, поэтому sink вызывается последовательно.
+// Отчёты выключенного блока отбрасываются.
+func (r *reportControl) deliver() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.delivering {
+		return
+	}
+	r.delivering = true
+	for len(r.outbox) > 0 && !r.disabled {
+		report := r.outbox[0]
+		r.outbox = r.outbox[1:]
+		r.mu.Unlock()
+		r.sink(report)
+		r.mu.Lock()
+	}
+	r.outbox = nil
+	r.delivering = false
+}
+
+// disable выключает блок управления; накопленные события отбрасываются
+func (r *reportControl) disable() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.disabled {
+		return
+	}
+	r.disabled = true
+	r.bufGen++
+	r.outbox = nil
+	if r.cancel != nil {
+		r.cancel()
+	}
+	if r.bufTimer != nil {
+		r.bufTimer.Stop()
+	}
+	if r.intgTimer != nil {
+		r.intgTimer.Stop()
+	}
+	r.pending = nil
+}
@@ -0,0 +1,176 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/slonegd/go61850/model"
+	"github.com/slonegd/go61850/osi/mms"
+	"github.com/slonegd/go61850/osi/mms/variant"
+	"github.com/stretchr/testify/assert"
+)
+
+// newReportTestServer создаёт сервер с условиями dchg для mag и qchg для q
+func newReportTestServer(t *testing.T, clock Clock) *Server {
+	m := newTestModel()
+	ln := m.LogicalDevice("simpleIOGenericIO").LogicalNode("GGIO1")
+	for _, do := range []string{"AnIn1", "AnIn2"} {
+		mag, _, err := ln.Attribute(mms.FCMX, do, "mag")
+		assert.NoError(t, err)
+		mag.Triggers = model.TriggerDataChange
+		q, _, err := ln.Attribute(mms.FCMX, do, "q")
+		assert.NoError(t, err)
+		q.Triggers = model.TriggerQualityChange
+	}
+	return New(m, WithClock(clock))
+}
+
+func TestReportBufTm(t *testing.T) {
+	clock := NewSimulatedClock(testTime)
+	s := newReportTestServer(t, clock)
+	var reports []Report
+	disable, err := s.EnableReport("simpleIOGenericIO", ReportControl{
+		Name: "LLN0$RP$MeasRCB", RptID: "Meas", DataSet: "LLN0$Measurements",
+		TrgOps: model.TriggerDataChange | model.TriggerQualityChange,
+		BufTm:  50 * time.Millisecond,
+	}, func(r Report) { reports = append(reports, r) })
+	assert.NoError(t, err)
+
+	set := func(item string, value *variant.Variant) {
+		assert.NoError(t, s.SetValue("simpleIOGenericIO", item, value))
+	}
+
+	// события разных элементов за BufTm объединяются
+	set("GGIO1$MX$AnIn1$mag$f", variant.NewFloat32Variant(10))
+	clock.Advance(20 * time.Millisecond)
+	set("GGIO1$MX$AnIn2$q", variant.NewBitStringVariant([]byte{0x40, 0}, 13))
+	assert.Empty(t, reports)
+	clock.Advance(30 * time.Millisecond)
+	assert.Len(t, reports, 1)
+	assert.Equal(t, []model.Trigger{model.TriggerDataChange, model.TriggerQualityChange}, reports[0].Reasons)
+	assert.Equal(t, testTime.Add(50*time.Millisecond), reports[0].Time)
+	assert.Equal(t, float32(10), reports[0].Values[0].Structure()[0].Structure()[0].Float32())
+
+	// повторное событие элемента отправляет накопленный отчёт сразу
+	set("GGIO1$MX$AnIn1$mag$f", variant.NewFloat32Variant(11))
+	clock.Advance(10 * time.Millisecond)
+	set("GGIO1$MX$AnIn1$mag$f", variant.NewFloat32Variant(12))
+	assert.Len(t, reports, 2)
+	assert.Equal(t, []model.Trigger{model.TriggerDataChange, 0}, reports[1].Reasons)
+	assert.Nil(t, reports[1].Values[1])
+	clock.Advance(50 * time.Millisecond)
+	assert.Len(t, reports, 3)
+	assert.Equal(t, uint16(2), reports[2].SqNum)
+
+	disable()
+	set("GGIO1$MX$AnIn1$mag$f", variant.NewFloat32Variant(13))
+	clock.Advance(time.Second)
+	assert.Len(t, reports, 3)
+}
+
+func TestReportIntegrity(t *testing.T) {
+	clock := NewSimulatedClock(testTime)
+	s := newReportTestServer(t, clock)
+	var reports []Report
+	disable, err := s.EnableReport("simpleIOGenericIO", ReportControl{
+		Name: "LLN0$RP$MeasRCB", RptID: "Meas", DataSet: "LLN0$Measurements",
+		TrgOps: model.TriggerDataChange | model.TriggerIntegrity,
+		IntgPd: time.Second,
+		BufTm:  100 * time.Millisecond,
+	}, func(r Report) { reports = append(reports, r) })
+	assert.NoError(t, err)
+
+	clock.Advance(950 * time.Millisecond)
+	assert.NoError(t, s.SetValue("simpleIOGenericIO", "GGIO1$MX$AnIn1$mag$f", variant.NewFloat32Variant(10)))
+	clock.Advance(2050 * time.Millisecond)
+
+	// накопленное событие отправляется перед integrity отчётом
+	assert.Len(t, reports, 4)
+	assert.Equal(t, []model.Trigger{model.TriggerDataChange, 0}, reports[0].Reasons)
+	for i, r := range reports[1:] {
+		assert.Equal(t, []model.Trigger{model.TriggerIntegrity, model.TriggerIntegrity}, r.Reasons)
+		assert.Equal(t, testTime.Add(time.Duration(i+1)*time.Second), r.Time)
+		assert.NotNil(t, r.Values[1])
+	}
+
+	// После отключения следующий integrity отчёт не запланирован
+	assert.Equal(t, 1, clock.Pending())
+	disable()
+	assert.Equal(t, 0, clock.Pending())
+
+	_, err = s.EnableReport("simpleIOGenericIO", ReportControl{DataSet: "LLN0$Measurements", TrgOps: model.TriggerIntegrity}, nil)
+	assert.EqualError(t, err, "integrity trigger requires positive IntgPd")
+	_, err = s.EnableReport("simpleIOGenericIO", ReportControl{DataSet: "LLN0$Missing"}, nil)
+	assert.EqualError(t, err, "data set LLN0$Missing not found or empty in simpleIOGenericIO")
+}
+
+func TestReportSinkReentrant(t *testing.T) {
+	s := newReportTestServer(t, NewSimulatedClock(testTime))
+	var reports []Report
+	var disable func()
+	disable, err := s.EnableReport("simpleIOGenericIO", ReportControl{
+		Name: "LLN0$RP$MeasRCB", RptID: "Meas", DataSet: "LLN0$Measurements",
+		TrgOps: model.TriggerDataChange | model.TriggerQualityChange,
+	}, func(r Report) {
+		reports = append(reports, r)
+		// sink изменяет модель и выключает блок без взаимной блокировки
+		if len(reports) == 1 {
+			assert.NoError(t, s.SetValue("simpleIOGenericIO", "GGIO1$MX$AnIn2$q", variant.NewBitStringVariant([]byte{0x40, 0}, 13)))
+			assert.Len(t, reports, 1)
+		} else {
+			disable()
+		}
+	})
+	assert.NoError(t, err)
+
+	assert.NoError(t, s.SetValue("simpleIOGenericIO", "GGIO1$MX$AnIn1$mag$f", variant.NewFloat32Variant(10)))
+	assert.Len(t, reports, 2)
+	assert.Equal(t, []model.Trigger{0, model.TriggerQualityChange}, reports[1].Reasons)
+	assert.Equal(t, uint16(1), reports[1].SqNum)
+
+	assert.NoError(t, s.SetValue("simpleIOGenericIO", "GGIO1$MX$AnIn1$mag$f", variant.NewFloat32Variant(11)))
+	assert.Len(t, reports, 2)
+}
+
+// staleClock - Clock, у которого Stop не отменяет вызов, как у time.AfterFunc,
+// чья функция уже запущена и ожидает блокировку блока управления
+type staleClock struct{ funcs []func() }
+
+func (c *staleClock) Now() time.Time { return testTime }
+
+func (c *staleClock) AfterFunc(_ time.Duration, f func()) Timer {
+	c.funcs = append(c.funcs, f)
+	return staleTimer{}
+}
+
+type staleTimer struct{}
+
+func (staleTimer) Stop() bool { return false }
+
+func TestReportStaleTimer(t *testing.T) {
+	clock := &staleClock{}
+	s := newReportTestServer(t, clock)
+	var reports []Report
+	disable, err := s.EnableReport("simpleIOGenericIO", ReportControl{
+		Name: "LLN0$RP$MeasRCB", RptID: "Meas", DataSet: "LLN0$Measurements",
+		TrgOps: model.TriggerDataChange,
+		BufTm:  50 * time.Millisecond,
+	}, func(r Report) { reports = append(reports, r) })
+	assert.NoError(t, err)
+
+	assert.NoError(t, s.SetValue("simpleIOGenericIO", "GGIO1$MX$AnIn1$mag$f", variant.NewFloat32Variant(10)))
+	assert.NoError(t, s.SetValue("simpleIOGenericIO", "GGIO1$MX$AnIn1$mag$f", variant.NewFloat32Variant(11)))
+	assert.Len(t, reports, 1)
+	assert.Len(t, clock.funcs, 2)
+
+	// таймер первого буфера не отправляет второй буфер раньше BufTm
+	clock.funcs[0]()
+	assert.Len(t, reports, 1)
+	clock.funcs[1]()
+	assert.Len(t, reports, 2)
+
+	assert.NoError(t, s.SetValue("simpleIOGenericIO", "GGIO1$MX$AnIn1$mag$f", variant.NewFloat32Variant(12)))
+	disable()
+	clock.funcs[2]()
+	assert.Len(t, reports, 2)
+}
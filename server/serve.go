@@ -0,0 +1,116 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+
+	"github.com/slonegd/go61850/osi/mms"
+	"github.com/slonegd/go61850/osi/mms/variant"
+)
+
+// mmsServer создаёт MMS сервер с обработчиками служб Read, Write, GetNameList
+// и GetVariableAccessAttributes по модели данных
+func (s *Server) mmsServer() *mms.Server {
+	return mms.NewServer(s.logger,
+		mms.WithHandler(mms.ServiceRead, s.HandleReadRequest),
+		mms.WithHandler(mms.ServiceWrite, s.HandleWriteRequest),
+		mms.WithHandler(mms.ServiceGetNameList, s.HandleGetNameListRequest),
+		mms.WithHandler(mms.ServiceGetVariableAccessAttributes, s.HandleGetVariableAccessAttributesRequest),
+		mms.WithAssociateHook(s.Associate),
+		mms.WithUnconfirmedHook(s.attachSender),
+	)
+}
+
+// attachSender регистрирует отправку PDU ассоциации до её завершения
+func (s *Server) attachSender(send func(pdu []byte) error) (release func()) {
+	s.sendersMu.Lock()
+	defer s.sendersMu.Unlock()
+	if s.senders == nil {
+		s.senders = make(map[uint64]func(pdu []byte) error)
+	}
+	id := s.nextSender
+	s.nextSender++
+	s.senders[id] = send
+	return func() {
+		s.sendersMu.Lock()
+		defer s.sendersMu.Unlock()
+		delete(s.senders, id)
+	}
+}
+
+// SendReport отправляет отчёт r блока домена domainID всем ассоциациям Serve
+// и ServeConn как InformationReport "RPT" с полями SeqNum, DatSet и ReasonCode.
+// Предназначен для ReportSink: EnableReport(domainID, rc, func(r Report) { s.SendReport(domainID, r) }).
+// Блокируется до записи отчёта в соединения; возвращает ошибки отправки.
+func (s *Server) SendReport(domainID string, r Report) error {
+	pdu, err := reportPDU(domainID, r).Bytes()
+	if err != nil {
+		return err
+	}
+	s.sendersMu.Lock()
+	senders := make([]func(pdu []byte) error, 0, len(s.senders))
+	for _, send := range s.senders {
+		senders = append(senders, send)
+	}
+	s.sendersMu.Unlock()
+
+	var errs []error
+	for _, send := range senders {
+		if err := send(pdu); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// reportPDU кодирует отчёт с полями SeqNum, DatSet и ReasonCode
+func reportPDU(domainID string, r Report) *mms.InformationReportPDU {
+	// OptFlds: sequence-number (бит 1), reason-for-inclusion (бит 3), data-set-name (бит 4)
+	optFlds := variant.NewBitStringVariant([]byte{0x58, 0x00}, 10)
+	inclusion := make([]byte, (len(r.Reasons)+7)/8)
+	var values, reasons []*variant.Variant
+	for i, reason := range r.Reasons {
+		if reason == 0 {
+			continue
+		}
+		inclusion[i/8] |= 0x80 >> (i % 8)
+		values = append(values, r.Values[i])
+		reasons = append(reasons, reason.BitString(7))
+	}
+
+	elements := []*variant.Variant{
+		variant.NewVisibleStringVariant(r.RptID),
+		optFlds,
+		variant.NewUint32Variant(uint32(r.SqNum)),
+		variant.NewVisibleStringVariant(domainID + "/" + r.DataSet),
+		variant.NewBitStringVariant(inclusion, len(r.Reasons)),
+	}
+	elements = append(elements, values...)
+	elements = append(elements, reasons...)
+
+	results := make([]mms.AccessResult, len(elements))
+	for i, element := range elements {
+		results[i] = mms.AccessResult{Success: true, Value: element}
+	}
+	return &mms.InformationReportPDU{VariableListName: &mms.ObjectName{ItemID: "RPT"}, Results: results}
+}
+
+// Serve принимает соединения клиентов MMS на l (например, net.Listen("tcp", ":102"))
+// и обслуживает каждое в отдельной горутине. Ассоциации учитываются в Stats.
+// Возвращает ошибку Accept, например после закрытия l.
+func (s *Server) Serve(l net.Listener) error {
+	return s.mmsServer().Serve(l)
+}
+
+// ServeConn обслуживает одно соединение клиента MMS до его закрытия или отмены ctx
+func (s *Server) ServeConn(ctx context.Context, conn io.ReadWriteCloser) error {
+	return s.mmsServer().ServeConn(ctx, conn)
+}
+
+// Conformance возвращает матрицу соответствия сервера (PICS): сервисы, на которые
+// отвечает ServeConn, и параметры CBB ассоциации (см. mms.Server.Conformance)
+func (s *Server) Conformance() *mms.Conformance {
+	return s.mmsServer().Conformance()
+}
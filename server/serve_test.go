@@ -0,0 +1,262 @@
+package server
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/slonegd/go61850"
+	"github.com/slonegd/go61850/model"
+	"github.com/slonegd/go61850/osi/mms"
+	"github.com/slonegd/go61850/osi/mms/variant"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServe(t *testing.T) {
+	s := New(newTestModel())
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer l.Close()
+	go s.Serve(l)
+
+	conn, err := net.Dial("tcp", l.Addr().String())
+	assert.NoError(t, err)
+	ctx := context.Background()
+	client, err := go61850.NewMmsClient(ctx, conn)
+	assert.NoError(t, err)
+	response, err := client.Initiate(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(65000), *response.LocalDetailCalled)
+	assert.Equal(t, []mms.ServiceSupportedBit{mms.GetNameList, mms.Read, mms.Write,
+		mms.GetVariableAccessAttributes, mms.Conclude}, response.ServicesSupportedCalled)
+
+	value, err := client.Read(ctx, "simpleIOGenericIO/GGIO1.AnIn1.mag.f", mms.FCMX)
+	assert.NoError(t, err)
+	assert.Equal(t, variant.NewFloat32Variant(1.5), value)
+
+	assert.NoError(t, client.Write(ctx, "simpleIOGenericIO/GGIO1.AnIn2.d", mms.FCDC, variant.NewInt32Variant(5)))
+	value, err = client.Read(ctx, "simpleIOGenericIO/GGIO1.AnIn2.d", mms.FCDC)
+	assert.NoError(t, err)
+	assert.Equal(t, variant.NewInt32Variant(5), value)
+	err = client.Write(ctx, "simpleIOGenericIO/GGIO1.AnIn2.mag.f", mms.FCMX, variant.NewFloat32Variant(5))
+	assert.ErrorIs(t, err, &mms.DataAccessError{ErrorCode: mms.ObjectAccessDenied})
+
+	names, err := client.GetNameList(ctx, mms.NewGetNameListRequest(mms.ObjectClassDomain, ""))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"simpleIOGenericIO", "simpleIOProtection"}, names.Identifiers)
+
+	spec, err := client.GetTypeSpecification(ctx, &mms.ReadRequest{DomainID: "simpleIOGenericIO", ItemID: "GGIO1$MX$AnIn1"})
+	assert.NoError(t, err)
+	assert.Equal(t, "q", spec.Structure.Components[1].Name)
+	_, err = client.GetTypeSpecification(ctx, &mms.ReadRequest{DomainID: "simpleIOGenericIO", ItemID: "GGIO1$MX$AnIn3"})
+	assert.ErrorIs(t, err, &mms.ServiceError{Class: mms.ErrorClassDefinition, Code: mms.DefinitionErrorObjectUndefined})
+
+	assert.Equal(t, int64(1), s.Stats().ActiveAssociations)
+	conn.Close()
+	assert.Eventually(t, func() bool { return s.Stats().ActiveAssociations == 0 }, time.Second, time.Millisecond)
+	assert.Equal(t, uint64(1), s.Stats().Associations)
+}
+
+func TestWriteMany(t *testing.T) {
+	s := New(newTestModel())
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer l.Close()
+	go s.Serve(l)
+
+	conn, err := net.Dial("tcp", l.Addr().String())
+	assert.NoError(t, err)
+	defer conn.Close()
+	ctx := context.Background()
+	client, err := go61850.NewMmsClient(ctx, conn)
+	assert.NoError(t, err)
+	_, err = client.Initiate(ctx)
+	assert.NoError(t, err)
+
+	// Значение процесса (MX) не записывается, остальные переменные записываются
+	results, err := client.WriteMany(ctx, []mms.WriteItem{
+		{Variable: mms.ObjectName{DomainID: "simpleIOGenericIO", ItemID: "GGIO1$DC$AnIn1$d"}, Value: variant.NewInt32Variant(1)},
+		{Variable: mms.ObjectName{DomainID: "simpleIOGenericIO", ItemID: "GGIO1$MX$AnIn1$mag$f"}, Value: variant.NewFloat32Variant(9)},
+		{Variable: mms.ObjectName{DomainID: "simpleIOGenericIO", ItemID: "GGIO1$DC$AnIn2$d"}, Value: variant.NewInt32Variant(2)},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []mms.WriteResult{
+		{Variable: mms.ObjectName{DomainID: "simpleIOGenericIO", ItemID: "GGIO1$DC$AnIn1$d"}},
+		{Variable: mms.ObjectName{DomainID: "simpleIOGenericIO", ItemID: "GGIO1$MX$AnIn1$mag$f"},
+			Error: &mms.DataAccessError{ErrorCode: mms.ObjectAccessDenied}},
+		{Variable: mms.ObjectName{DomainID: "simpleIOGenericIO", ItemID: "GGIO1$DC$AnIn2$d"}},
+	}, results)
+
+	value, err := client.Read(ctx, "simpleIOGenericIO/GGIO1.AnIn2.d", mms.FCDC)
+	assert.NoError(t, err)
+	assert.Equal(t, variant.NewInt32Variant(2), value)
+	value, err = client.Read(ctx, "simpleIOGenericIO/GGIO1.AnIn1.mag.f", mms.FCMX)
+	assert.NoError(t, err)
+	assert.Equal(t, variant.NewFloat32Variant(1.5), value)
+}
+
+func TestWriteManySplit(t *testing.T) {
+	s := New(newTestModel())
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer l.Close()
+	go s.Serve(l)
+
+	conn, err := net.Dial("tcp", l.Addr().String())
+	assert.NoError(t, err)
+	defer conn.Close()
+	ctx := context.Background()
+	client, err := go61850.NewMmsClient(ctx, conn)
+	assert.NoError(t, err)
+	_, err = client.Initiate(ctx, mms.WithLocalDetailCalling(96))
+	assert.NoError(t, err)
+	info := client.AssociationInfo()
+	assert.Equal(t, uint32(96), info.Capabilities.MaxPduSize)
+
+	// Две переменные не помещаются в PDU: каждая записывается отдельным запросом
+	results, err := client.WriteMany(ctx, []mms.WriteItem{
+		{Variable: mms.ObjectName{DomainID: "simpleIOGenericIO", ItemID: "GGIO1$DC$AnIn1$d"}, Value: variant.NewInt32Variant(1)},
+		{Variable: mms.ObjectName{DomainID: "simpleIOGenericIO", ItemID: "GGIO1$MX$AnIn1$mag$f"}, Value: variant.NewFloat32Variant(9)},
+		{Variable: mms.ObjectName{DomainID: "simpleIOGenericIO", ItemID: "GGIO1$DC$AnIn2$d"}, Value: variant.NewInt32Variant(2)},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []mms.WriteResult{
+		{Variable: mms.ObjectName{DomainID: "simpleIOGenericIO", ItemID: "GGIO1$DC$AnIn1$d"}},
+		{Variable: mms.ObjectName{DomainID: "simpleIOGenericIO", ItemID: "GGIO1$MX$AnIn1$mag$f"},
+			Error: &mms.DataAccessError{ErrorCode: mms.ObjectAccessDenied}},
+		{Variable: mms.ObjectName{DomainID: "simpleIOGenericIO", ItemID: "GGIO1$DC$AnIn2$d"}},
+	}, results)
+	assert.Equal(t, uint64(3), s.Stats().Requests["write"])
+}
+
+func TestWriteManyPartial(t *testing.T) {
+	s := New(newTestModel())
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer l.Close()
+	go s.Serve(l)
+
+	conn, err := net.Dial("tcp", l.Addr().String())
+	assert.NoError(t, err)
+	defer conn.Close()
+	ctx := context.Background()
+	var records []go61850.AuditRecord
+	client, err := go61850.NewMmsClient(ctx, conn,
+		go61850.WithServerProfile(go61850.ProfileSiemens),
+		go61850.WithAuditLog(go61850.AuditSinkFunc(func(r go61850.AuditRecord) { records = append(records, r) })),
+	)
+	assert.NoError(t, err)
+	_, err = client.Initiate(ctx, mms.WithLocalDetailCalling(96))
+	assert.NoError(t, err)
+
+	// Третий запрос не отправляется: имя длиннее 32 символов профиля Siemens.
+	// Результаты первых двух запросов возвращаются вместе с ошибкой.
+	long := mms.ObjectName{DomainID: "simpleIOGenericIO", ItemID: "GGIO1$DC$" + strings.Repeat("d", 30)}
+	results, err := client.WriteMany(ctx, []mms.WriteItem{
+		{Variable: mms.ObjectName{DomainID: "simpleIOGenericIO", ItemID: "GGIO1$DC$AnIn1$d"}, Value: variant.NewInt32Variant(1)},
+		{Variable: mms.ObjectName{DomainID: "simpleIOGenericIO", ItemID: "GGIO1$MX$AnIn1$mag$f"}, Value: variant.NewFloat32Variant(9)},
+		{Variable: long, Value: variant.NewInt32Variant(2)},
+	})
+	assert.Error(t, err)
+	assert.Equal(t, []mms.WriteResult{
+		{Variable: mms.ObjectName{DomainID: "simpleIOGenericIO", ItemID: "GGIO1$DC$AnIn1$d"}},
+		{Variable: mms.ObjectName{DomainID: "simpleIOGenericIO", ItemID: "GGIO1$MX$AnIn1$mag$f"},
+			Error: &mms.DataAccessError{ErrorCode: mms.ObjectAccessDenied}},
+	}, results)
+	assert.Equal(t, uint64(2), s.Stats().Requests["write"])
+
+	// Журнал аудита: результат сервера для отправленных переменных, ошибка - для остальных
+	assert.Len(t, records, 3)
+	assert.NoError(t, records[0].Err)
+	assert.ErrorIs(t, records[1].Err, &mms.DataAccessError{ErrorCode: mms.ObjectAccessDenied})
+	assert.Equal(t, err, records[2].Err)
+}
+
+func TestServeReadMany(t *testing.T) {
+	s := New(newTestModel())
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer l.Close()
+	go s.Serve(l)
+
+	conn, err := net.Dial("tcp", l.Addr().String())
+	assert.NoError(t, err)
+	defer conn.Close()
+	ctx := context.Background()
+	client, err := go61850.NewMmsClient(ctx, conn)
+	assert.NoError(t, err)
+	_, err = client.Initiate(ctx)
+	assert.NoError(t, err)
+
+	// Каждая переменная listOfVariable получает свой AccessResult
+	results, err := client.ReadMany(ctx, []mms.ObjectName{
+		{DomainID: "simpleIOGenericIO", ItemID: "GGIO1$MX$AnIn1$mag$f"},
+		{DomainID: "simpleIOGenericIO", ItemID: "GGIO1$MX$AnIn9"},
+		{ItemID: "Vendor"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []mms.AccessResult{
+		{Success: true, Value: variant.NewFloat32Variant(1.5)},
+		{Error: &mms.DataAccessError{ErrorCode: mms.ObjectNonExistent}},
+		{Success: true, Value: variant.NewInt32Variant(42)},
+	}, results)
+
+	// Чтение набора данных по variableListName
+	members := []mms.DataSetMember{
+		{Name: mms.ObjectName{DomainID: "simpleIOGenericIO", ItemID: "GGIO1$MX$AnIn1"}},
+		{Name: mms.ObjectName{DomainID: "simpleIOGenericIO", ItemID: "GGIO1$MX$AnIn2"}},
+	}
+	values, err := client.ReadDataSet(ctx, mms.ObjectName{DomainID: "simpleIOGenericIO", ItemID: "LLN0$Measurements"}, members)
+	assert.NoError(t, err)
+	assert.Len(t, values, 2)
+	assert.Equal(t, "GGIO1$MX$AnIn2", values[1].Member.Name.ItemID)
+	assert.Equal(t, s.Read("simpleIOGenericIO", "GGIO1$MX$AnIn2"), values[1].Result)
+
+	_, err = client.ReadDataSet(ctx, mms.ObjectName{DomainID: "simpleIOGenericIO", ItemID: "LLN0$Missing"}, members)
+	var serviceError *mms.ServiceError
+	assert.ErrorAs(t, err, &serviceError)
+	assert.Equal(t, mms.DefinitionErrorObjectUndefined, serviceError.Code)
+}
+
+func TestSendReport(t *testing.T) {
+	s := newReportTestServer(t, NewSimulatedClock(testTime))
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer l.Close()
+	go s.Serve(l)
+
+	conn, err := net.Dial("tcp", l.Addr().String())
+	assert.NoError(t, err)
+	defer conn.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	queue := go61850.NewReportQueue(4)
+	client, err := go61850.NewMmsClient(ctx, conn, go61850.WithReportQueue(queue))
+	assert.NoError(t, err)
+	_, err = client.Initiate(ctx)
+	assert.NoError(t, err)
+	go client.Listen(ctx)
+
+	_, err = s.EnableReport("simpleIOGenericIO", ReportControl{
+		Name: "LLN0$RP$MeasRCB", RptID: "Meas", DataSet: "LLN0$Measurements", TrgOps: model.TriggerDataChange,
+	}, func(r Report) { assert.NoError(t, s.SendReport("simpleIOGenericIO", r)) })
+	assert.NoError(t, err)
+	assert.NoError(t, s.SetValue("simpleIOGenericIO", "GGIO1$MX$AnIn2$mag$f", variant.NewFloat32Variant(7)))
+
+	pdu, err := queue.Receive(ctx)
+	assert.NoError(t, err)
+	report, err := go61850.ParseReport(pdu, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, "Meas", report.RptID)
+	assert.Equal(t, "simpleIOGenericIO/LLN0$Measurements", report.DataSet)
+	assert.Equal(t, []bool{false, true}, report.Inclusion)
+	assert.Equal(t, []model.Trigger{0, model.TriggerDataChange}, report.Reasons)
+}
+
+func TestServerConformance(t *testing.T) {
+	c := New(newTestModel()).Conformance()
+	assert.Equal(t, []mms.ServiceSupportedBit{mms.GetNameList, mms.Read, mms.Write, mms.GetVariableAccessAttributes, mms.Conclude},
+		c.ServicesIn(mms.RoleResponder))
+}
@@ -0,0 +1,332 @@
+package server
+
+import (
+	"fmt"
+	"io/fs"
+	"strings"
+	"sync"
+
+	"github.com/slonegd/go61850/logger"
+	"github.com/slonegd/go61850/model"
+	"github.com/slonegd/go61850/osi/mms"
+)
+
+// Server представляет MMS сервер IEC 61850, отвечающий на запросы по модели данных.
+// Сервер не привязан к транспорту: обработчики принимают и возвращают MMS PDU.
+type Server struct {
+	model  *model.Model
+	logger logger.Logger
+
+	// files - хранилище файловых служб; nil - службы не настроены
+	files         fs.FS
+	fileChunkSize int
+	maxOpenFiles  int
+	// filesMu защищает счётчики FRSM всех ассоциаций (см. Association):
+	// openCount - для предела WithMaxOpenFiles, openPaths - для FileDelete и FileRename
+	filesMu   sync.Mutex
+	openCount int
+	openPaths map[string]int
+
+	// valuesMu защищает значения модели, изменяемые через SetValue и Transaction
+	valuesMu     sync.RWMutex
+	observersMu  sync.Mutex
+	observers    []*observer
+	nextObserver uint64
+
+	// journals - хранилище журналов; nil - журналы не ведутся
+	journals JournalStore
+	clock    Clock
+
+	// stats - счётчики ассоциаций, запросов и отчётов (см. stats.go)
+	stats serverStats
+	// maxNames - наибольшее число имён в ответе GetNameList; 0 - без ограничения
+	maxNames int
+
+	// senders - отправка PDU ассоциациям ServeConn по их номерам (см. SendReport)
+	sendersMu  sync.Mutex
+	senders    map[uint64]func(pdu []byte) error
+	nextSender uint64
+}
+
+// Option представляет опцию для настройки Server
+type Option func(*Server)
+
+// WithLogger устанавливает логгер для Server
+func WithLogger(l logger.Logger) Option {
+	return func(s *Server) {
+		s.logger = l
+	}
+}
+
+// WithMaxNames ограничивает число имён в ответе GetNameList: остальные имена
+// сервер отдаёт по следующим запросам с continueAfter, отвечая moreFollows
+func WithMaxNames(n int) Option {
+	return func(s *Server) {
+		s.maxNames = n
+	}
+}
+
+// New создаёт новый сервер для модели данных m
+func New(m *model.Model, opts ...Option) *Server {
+	s := &Server{
+		model:         m,
+		logger:        logger.NewLogger("server"),
+		fileChunkSize: DefaultFileChunkSize,
+		maxOpenFiles:  DefaultMaxOpenFiles,
+		clock:         systemClock{},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// HandleReadRequest обрабатывает BER-кодированный MMS Read Request PDU
+// и возвращает BER-кодированный Read Response PDU: по одному AccessResult
+// на каждую переменную listOfVariable или на каждый элемент набора данных
+// (variableListName, см. ReadDataSet). Для неизвестного набора данных
+// возвращается *mms.ServiceError definition/object-undefined.
+func (s *Server) HandleReadRequest(pdu []byte) ([]byte, error) {
+	request, err := mms.ParseReadRequest(pdu)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Read Request: %w", err)
+	}
+
+	response := mms.ReadResponse{InvokeID: request.InvokeID}
+	if request.DataSet != nil {
+		s.logger.Debug("MMS Read Request: data set %s", request.DataSet)
+		results, err := s.ReadDataSet(request.DataSet.DomainID, request.DataSet.ItemID)
+		s.stats.request(mms.ServiceRead.String(), err != nil)
+		if err != nil {
+			s.logger.Debug("read %s: %v", request.DataSet, err)
+			return nil, &mms.ServiceError{Class: mms.ErrorClassDefinition, Code: mms.DefinitionErrorObjectUndefined}
+		}
+		response.ListOfAccessResult = results
+		return response.Bytes()
+	}
+
+	for _, variable := range request.Variables() {
+		s.logger.Debug("MMS Read Request: domain=%s item=%s", variable.DomainID, variable.ItemID)
+		response.ListOfAccessResult = append(response.ListOfAccessResult, s.Read(variable.DomainID, variable.ItemID))
+	}
+	return response.Bytes()
+}
+
+// Read читает переменную модели по MMS имени.
+// itemID имеет вид "LN$FC$DO$DA..." (например, "GGIO1$MX$AnIn1$mag$f").
+// Если имя указывает на DO или LN с FC (FCD), возвращается структура
+// всех атрибутов с этим FC. Для несуществующих объектов возвращается
+// ошибка доступа object-non-existent.
+// Пустой domainID означает переменную уровня VMD ("name$component...").
+func (s *Server) Read(domainID, itemID string) mms.AccessResult {
+	result := s.read(domainID, itemID)
+	s.stats.request(mms.ServiceRead.String(), !result.Success)
+	return result
+}
+
+// read читает переменную модели без учёта в статистике запросов
+func (s *Server) read(domainID, itemID string) mms.AccessResult {
+	s.valuesMu.RLock()
+	defer s.valuesMu.RUnlock()
+	return s.readLocked(domainID, itemID)
+}
+
+// readLocked читает переменную модели; вызывающий удерживает valuesMu
+func (s *Server) readLocked(domainID, itemID string) mms.AccessResult {
+	if domainID == "" {
+		return s.readVMD(itemID)
+	}
+
+	ld := s.model.LogicalDevice(domainID)
+	if ld == nil {
+		return failure(mms.ObjectNonExistent)
+	}
+
+	parts := strings.Split(itemID, "$")
+	ln := ld.LogicalNode(parts[0])
+	if ln == nil {
+		return failure(mms.ObjectNonExistent)
+	}
+	if len(parts) < 2 {
+		// Чтение LN целиком (все FC) не поддерживается
+		return failure(mms.ObjectAccessUnsupported)
+	}
+
+	value, err := ln.FCValue(mms.FunctionalConstraint(parts[1]), parts[2:]...)
+	if err != nil {
+		s.logger.Debug("read %s/%s: %v", domainID, itemID, err)
+		return failure(mms.ObjectNonExistent)
+	}
+
+	return mms.AccessResult{Success: true, Value: value}
+}
+
+// readVMD читает переменную уровня VMD
+func (s *Server) readVMD(itemID string) mms.AccessResult {
+	parts := strings.Split(itemID, "$")
+	da := s.model.VMDVariable(parts[0])
+	if da == nil {
+		return failure(mms.ObjectNonExistent)
+	}
+	value, err := da.Lookup(parts[1:]...)
+	if err != nil {
+		s.logger.Debug("read %s: %v", itemID, err)
+		return failure(mms.ObjectNonExistent)
+	}
+	return mms.AccessResult{Success: true, Value: value}
+}
+
+// HandleGetVariableAccessAttributesRequest обрабатывает BER-кодированный MMS
+// GetVariableAccessAttributes Request PDU и возвращает BER-кодированный ответ.
+// Для неизвестной переменной возвращается *mms.ServiceError.
+func (s *Server) HandleGetVariableAccessAttributesRequest(pdu []byte) ([]byte, error) {
+	request, err := mms.ParseGetVariableAccessAttributesRequest(pdu)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse GetVariableAccessAttributes Request: %w", err)
+	}
+	s.logger.Debug("MMS GetVariableAccessAttributes Request: domain=%s item=%s", request.DomainID, request.ItemID)
+
+	spec, err := s.TypeSpecification(request.DomainID, request.ItemID)
+	if err != nil {
+		return nil, err
+	}
+	response := mms.VariableAccessAttributesResponse{
+		InvokeID:          request.InvokeID,
+		TypeSpecification: spec,
+	}
+	return response.Bytes(), nil
+}
+
+// TypeSpecification возвращает MMS тип переменной модели с именами компонентов.
+// itemID имеет вид как в Read; имя LN без FC ("GGIO1") возвращает структуру,
+// компоненты которой названы по FC. Пустой domainID означает переменную уровня VMD.
+// Для несуществующих объектов возвращается *mms.ServiceError definition/object-undefined.
+func (s *Server) TypeSpecification(domainID, itemID string) (_ *mms.TypeSpecification, err error) {
+	defer s.countRequest(mms.ServiceGetVariableAccessAttributes.String(), &err)
+	s.valuesMu.RLock()
+	defer s.valuesMu.RUnlock()
+	spec, err := s.typeSpecification(domainID, itemID)
+	if err != nil {
+		s.logger.Debug("type of %s/%s: %v", domainID, itemID, err)
+		return nil, &mms.ServiceError{Class: mms.ErrorClassDefinition, Code: mms.DefinitionErrorObjectUndefined}
+	}
+	return spec, nil
+}
+
+// typeSpecification находит тип переменной модели; вызывающий удерживает valuesMu
+func (s *Server) typeSpecification(domainID, itemID string) (*mms.TypeSpecification, error) {
+	parts := strings.Split(itemID, "$")
+	if domainID == "" {
+		da := s.model.VMDVariable(parts[0])
+		if da == nil {
+			return nil, fmt.Errorf("variable %s not found", parts[0])
+		}
+		return da.LookupType(parts[1:]...)
+	}
+
+	ld := s.model.LogicalDevice(domainID)
+	if ld == nil {
+		return nil, fmt.Errorf("domain %s not found", domainID)
+	}
+	ln := ld.LogicalNode(parts[0])
+	if ln == nil {
+		return nil, fmt.Errorf("logical node %s not found", parts[0])
+	}
+	if len(parts) < 2 {
+		return ln.Type()
+	}
+	return ln.FCType(mms.FunctionalConstraint(parts[1]), parts[2:]...)
+}
+
+// HandleGetNameListRequest обрабатывает BER-кодированный MMS GetNameList Request PDU
+// и возвращает BER-кодированный GetNameList Response PDU.
+func (s *Server) HandleGetNameListRequest(pdu []byte) ([]byte, error) {
+	request, err := mms.ParseGetNameListRequest(pdu)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse GetNameList Request: %w", err)
+	}
+	s.logger.Debug("MMS GetNameList Request: class=%s scope=%s domain=%s continueAfter=%s",
+		request.ObjectClass, request.ObjectScope, request.DomainID, request.ContinueAfter)
+
+	names, err := s.GetNameList(request.ObjectClass, request.ObjectScope, request.DomainID, request.ContinueAfter)
+	if err != nil {
+		return nil, err
+	}
+
+	response := mms.GetNameListResponse{
+		InvokeID:    request.InvokeID,
+		Identifiers: names,
+	}
+	if s.maxNames > 0 && len(names) > s.maxNames {
+		response.Identifiers = names[:s.maxNames]
+		response.MoreFollows = true
+	}
+	return response.Bytes(), nil
+}
+
+// GetNameList возвращает имена объектов класса class в области scope
+// согласно ISO/IEC 9506-1:
+//   - domain (только VMD) - имена логических устройств;
+//   - namedVariable - переменные VMD либо все имена переменных домена;
+//   - namedVariableList - наборы данных домена;
+//   - journal - журналы домена.
+//
+// Наборы данных и журналы VMD и все объекты области aaSpecific не поддерживаются:
+// для них возвращается пустой список. Неизвестный домен и класс объектов, которого
+// нет в области, возвращают ошибку, оборачивающую *mms.ServiceError
+// access/object-non-existent: клиент получает confirmed-ErrorPDU.
+// continueAfter задаёт имя, после которого продолжается список.
+func (s *Server) GetNameList(class mms.ObjectClass, scope mms.ObjectScope, domainID, continueAfter string) (_ []string, err error) {
+	defer s.countRequest(mms.ServiceGetNameList.String(), &err)
+	nonExistent := &mms.ServiceError{Class: mms.ErrorClassAccess, Code: mms.AccessErrorObjectNonExistent}
+	var names []string
+	switch scope {
+	case mms.ScopeVMD:
+		switch class {
+		case mms.ObjectClassDomain:
+			for _, ld := range s.model.LogicalDevices {
+				names = append(names, ld.Name)
+			}
+		case mms.ObjectClassNamedVariable:
+			for _, da := range s.model.VMDVariables {
+				names = append(names, da.Name)
+			}
+		case mms.ObjectClassNamedVariableList, mms.ObjectClassJournal:
+		default:
+			return nil, fmt.Errorf("object class %s not supported in VMD scope: %w", class, nonExistent)
+		}
+	case mms.ScopeDomain:
+		ld := s.model.LogicalDevice(domainID)
+		if ld == nil {
+			return nil, fmt.Errorf("domain %s not found: %w", domainID, nonExistent)
+		}
+		switch class {
+		case mms.ObjectClassNamedVariable:
+			names = ld.VariableNames()
+		case mms.ObjectClassNamedVariableList:
+			names = ld.DataSetNames()
+		case mms.ObjectClassJournal:
+			names = append(names, ld.Journals...)
+		default:
+			return nil, fmt.Errorf("object class %s not supported in domain scope: %w", class, nonExistent)
+		}
+	}
+
+	if continueAfter != "" {
+		for i, name := range names {
+			if name == continueAfter {
+				return names[i+1:], nil
+			}
+		}
+		return []string{}, nil
+	}
+	if names == nil {
+		names = []string{}
+	}
+	return names, nil
+}
+
+// failure создаёт неуспешный результат доступа
+func failure(code mms.DataAccessErrorCode) mms.AccessResult {
+	return mms.AccessResult{Error: &mms.DataAccessError{ErrorCode: code}}
+}
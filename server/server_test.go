@@ -0,0 +1,284 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/slonegd/go61850/model"
+	"github.com/slonegd/go61850/osi/mms"
+	"github.com/slonegd/go61850/osi/mms/variant"
+	"github.com/stretchr/testify/assert"
+)
+
+// testTime - время для атрибутов t в тестовой модели
+var testTime = time.Date(2026, 1, 5, 11, 21, 52, 0, time.UTC)
+
+// newTestModel создаёт модель, повторяющую simpleIOGenericIO из libIEC61850
+func newTestModel() *model.Model {
+	anIn := func(name string, value float32) *model.DataObject {
+		return &model.DataObject{
+			Name: name,
+			Children: []model.DataNode{
+				&model.DataAttribute{Name: "mag", FC: mms.FCMX, Attributes: []*model.DataAttribute{
+					{Name: "f", FC: mms.FCMX, Value: variant.NewFloat32Variant(value)},
+				}},
+				&model.DataAttribute{Name: "q", FC: mms.FCMX, Value: variant.NewBitStringVariant([]byte{0, 0}, 13)},
+				&model.DataAttribute{Name: "t", FC: mms.FCMX, Value: variant.NewUTCTimeVariant(testTime)},
+				&model.DataAttribute{Name: "d", FC: mms.FCDC, Value: variant.NewInt32Variant(1)},
+			},
+		}
+	}
+
+	return &model.Model{
+		Name: "simpleIO",
+		LogicalDevices: []*model.LogicalDevice{
+			{
+				Name: "simpleIOGenericIO",
+				LogicalNodes: []*model.LogicalNode{{
+					Name:        "GGIO1",
+					DataObjects: []*model.DataObject{anIn("AnIn1", 1.5), anIn("AnIn2", 2.5)},
+				}},
+				DataSets: []*model.DataSet{
+					{Name: "LLN0$Measurements", Members: []string{"GGIO1$MX$AnIn1", "GGIO1$MX$AnIn2"}},
+				},
+				Journals: []string{"LLN0$EventLog"},
+			},
+			{Name: "simpleIOProtection"},
+		},
+		VMDVariables: []*model.DataAttribute{
+			{Name: "Vendor", Value: variant.NewInt32Variant(42)},
+		},
+	}
+}
+
+func TestServerRead(t *testing.T) {
+	anIn1 := variant.NewStructureVariant([]*variant.Variant{
+		variant.NewStructureVariant([]*variant.Variant{variant.NewFloat32Variant(1.5)}),
+		variant.NewBitStringVariant([]byte{0, 0}, 13),
+		variant.NewUTCTimeVariant(testTime),
+	})
+	anIn2 := variant.NewStructureVariant([]*variant.Variant{
+		variant.NewStructureVariant([]*variant.Variant{variant.NewFloat32Variant(2.5)}),
+		variant.NewBitStringVariant([]byte{0, 0}, 13),
+		variant.NewUTCTimeVariant(testTime),
+	})
+
+	tests := []struct {
+		name   string
+		domain string
+		item   string
+		want   mms.AccessResult
+	}{
+		{
+			name:   "атрибут",
+			domain: "simpleIOGenericIO",
+			item:   "GGIO1$MX$AnIn1$mag$f",
+			want:   mms.AccessResult{Success: true, Value: variant.NewFloat32Variant(1.5)},
+		},
+		{
+			name:   "FCD - DO со всеми атрибутами MX",
+			domain: "simpleIOGenericIO",
+			item:   "GGIO1$MX$AnIn1",
+			want:   mms.AccessResult{Success: true, Value: anIn1},
+		},
+		{
+			name:   "LN с FC",
+			domain: "simpleIOGenericIO",
+			item:   "GGIO1$MX",
+			want:   mms.AccessResult{Success: true, Value: variant.NewStructureVariant([]*variant.Variant{anIn1, anIn2})},
+		},
+		{
+			name:   "FCD с другим FC",
+			domain: "simpleIOGenericIO",
+			item:   "GGIO1$DC$AnIn2",
+			want: mms.AccessResult{Success: true, Value: variant.NewStructureVariant([]*variant.Variant{
+				variant.NewInt32Variant(1),
+			})},
+		},
+		{
+			name:   "атрибут с чужим FC",
+			domain: "simpleIOGenericIO",
+			item:   "GGIO1$ST$AnIn1$mag",
+			want:   failure(mms.ObjectNonExistent),
+		},
+		{
+			name:   "переменная VMD",
+			domain: "",
+			item:   "Vendor",
+			want:   mms.AccessResult{Success: true, Value: variant.NewInt32Variant(42)},
+		},
+		{
+			name:   "несуществующая переменная VMD",
+			domain: "",
+			item:   "Model",
+			want:   failure(mms.ObjectNonExistent),
+		},
+		{
+			name:   "несуществующий домен",
+			domain: "unknown",
+			item:   "GGIO1$MX",
+			want:   failure(mms.ObjectNonExistent),
+		},
+	}
+
+	s := New(newTestModel())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, s.Read(tt.domain, tt.item))
+		})
+	}
+}
+
+func TestServerHandleReadRequest(t *testing.T) {
+	s := New(newTestModel())
+
+	request, err := mms.NewReadRequest("simpleIOGenericIO/GGIO1.AnIn1", mms.FCMX)
+	assert.NoError(t, err)
+	request.InvokeID = 5
+
+	pdu, err := s.HandleReadRequest(request.Bytes())
+	assert.NoError(t, err)
+
+	response, err := mms.ParseReadResponse(pdu)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(5), response.InvokeID)
+	assert.Len(t, response.ListOfAccessResult, 1)
+	assert.Equal(t,
+		"struct{struct{float32(1.5)}, bit-string(0b0_0000_0000_0000), utc-time(2026-01-05T11:21:52Z)}",
+		response.ListOfAccessResult[0].Value.String(),
+	)
+}
+
+func TestServerGetNameList(t *testing.T) {
+	anIn := func(name string) []string {
+		prefix := "GGIO1$MX$" + name
+		return []string{prefix, prefix + "$mag", prefix + "$mag$f", prefix + "$q", prefix + "$t"}
+	}
+	variables := []string{"GGIO1", "GGIO1$MX"}
+	variables = append(variables, anIn("AnIn1")...)
+	variables = append(variables, anIn("AnIn2")...)
+	variables = append(variables, "GGIO1$DC", "GGIO1$DC$AnIn1", "GGIO1$DC$AnIn1$d", "GGIO1$DC$AnIn2", "GGIO1$DC$AnIn2$d")
+
+	tests := []struct {
+		name          string
+		class         mms.ObjectClass
+		scope         mms.ObjectScope
+		domain        string
+		continueAfter string
+		want          []string
+	}{
+		{
+			name:  "домены",
+			class: mms.ObjectClassDomain,
+			scope: mms.ScopeVMD,
+			want:  []string{"simpleIOGenericIO", "simpleIOProtection"},
+		},
+		{
+			name:  "переменные VMD",
+			class: mms.ObjectClassNamedVariable,
+			scope: mms.ScopeVMD,
+			want:  []string{"Vendor"},
+		},
+		{
+			name:   "переменные домена",
+			class:  mms.ObjectClassNamedVariable,
+			scope:  mms.ScopeDomain,
+			domain: "simpleIOGenericIO",
+			want:   variables,
+		},
+		{
+			name:          "переменные домена после имени",
+			class:         mms.ObjectClassNamedVariable,
+			scope:         mms.ScopeDomain,
+			domain:        "simpleIOGenericIO",
+			continueAfter: "GGIO1$DC$AnIn2",
+			want:          []string{"GGIO1$DC$AnIn2$d"},
+		},
+		{
+			name:   "наборы данных",
+			class:  mms.ObjectClassNamedVariableList,
+			scope:  mms.ScopeDomain,
+			domain: "simpleIOGenericIO",
+			want:   []string{"LLN0$Measurements"},
+		},
+		{
+			name:   "журналы",
+			class:  mms.ObjectClassJournal,
+			scope:  mms.ScopeDomain,
+			domain: "simpleIOGenericIO",
+			want:   []string{"LLN0$EventLog"},
+		},
+		{
+			name:   "пустой домен",
+			class:  mms.ObjectClassNamedVariable,
+			scope:  mms.ScopeDomain,
+			domain: "simpleIOProtection",
+			want:   []string{},
+		},
+		{
+			name:  "домены в области ассоциации",
+			class: mms.ObjectClassDomain,
+			scope: mms.ScopeAA,
+			want:  []string{},
+		},
+	}
+
+	s := New(newTestModel())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.GetNameList(tt.class, tt.scope, tt.domain, tt.continueAfter)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	nonExistent := &mms.ServiceError{Class: mms.ErrorClassAccess, Code: mms.AccessErrorObjectNonExistent}
+	_, err := s.GetNameList(mms.ObjectClassNamedVariable, mms.ScopeDomain, "unknown", "")
+	assert.ErrorIs(t, err, nonExistent)
+	assert.ErrorContains(t, err, "domain unknown not found")
+	_, err = s.GetNameList(mms.ObjectClassSemaphore, mms.ScopeVMD, "", "")
+	assert.ErrorIs(t, err, nonExistent)
+	_, err = s.GetNameList(mms.ObjectClassDomain, mms.ScopeDomain, "simpleIOGenericIO", "")
+	assert.ErrorIs(t, err, nonExistent)
+}
+
+func TestServerHandleGetNameListRequest(t *testing.T) {
+	s := New(newTestModel())
+
+	request := mms.GetNameListRequest{InvokeID: 7, ObjectClass: mms.ObjectClassDomain, ObjectScope: mms.ScopeVMD}
+	pdu, err := s.HandleGetNameListRequest(request.Bytes())
+	assert.NoError(t, err)
+
+	response, err := mms.ParseGetNameListResponse(pdu)
+	assert.NoError(t, err)
+	assert.Equal(t, &mms.GetNameListResponse{
+		InvokeID:    7,
+		Identifiers: []string{"simpleIOGenericIO", "simpleIOProtection"},
+	}, response)
+
+	// Неизвестный домен: ServiceError, из которой mms.Server строит confirmed-ErrorPDU
+	request = mms.GetNameListRequest{InvokeID: 8, ObjectClass: mms.ObjectClassNamedVariable,
+		ObjectScope: mms.ScopeDomain, DomainID: "unknown"}
+	_, err = s.HandleGetNameListRequest(request.Bytes())
+	var serviceError *mms.ServiceError
+	assert.ErrorAs(t, err, &serviceError)
+	assert.Equal(t, mms.ServiceError{Class: mms.ErrorClassAccess, Code: mms.AccessErrorObjectNonExistent}, *serviceError)
+}
+
+func TestServerMaxNames(t *testing.T) {
+	s := New(newTestModel(), WithMaxNames(1))
+
+	request := mms.GetNameListRequest{InvokeID: 7, ObjectClass: mms.ObjectClassDomain, ObjectScope: mms.ScopeVMD}
+	pdu, err := s.HandleGetNameListRequest(request.Bytes())
+	assert.NoError(t, err)
+	response, err := mms.ParseGetNameListResponse(pdu)
+	assert.NoError(t, err)
+	assert.Equal(t, &mms.GetNameListResponse{InvokeID: 7, Identifiers: []string{"simpleIOGenericIO"}, MoreFollows: true}, response)
+
+	request.ContinueAfter = "simpleIOGenericIO"
+	pdu, err = s.HandleGetNameListRequest(request.Bytes())
+	assert.NoError(t, err)
+	response, err = mms.ParseGetNameListResponse(pdu)
+	assert.NoError(t, err)
+	assert.Equal(t, &mms.GetNameListResponse{InvokeID: 7, Identifiers: []string{"simpleIOProtection"}}, response)
+}
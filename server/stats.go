@@ -0,0 +1,99 @@
+package server
+
+import (
+	"expvar"
+	"maps"
+	"sync"
+)
+
+// Stats - снимок счётчиков сервера
+type Stats struct {
+	// Associations - число ассоциаций с запуска сервера
+	Associations uint64
+	// ActiveAssociations - число открытых ассоциаций
+	ActiveAssociations int64
+	// Requests - число запросов по имени службы MMS ("read", "getNameList", "fileOpen"...)
+	Requests map[string]uint64
+	// Errors - число запросов, завершившихся ошибкой или отказом в доступе
+	Errors uint64
+	// ReportsEmitted - число отчётов, переданных ReportSink
+	ReportsEmitted uint64
+}
+
+// serverStats - счётчики сервера, безопасные для конкурентного использования
+type serverStats struct {
+	mu                 sync.Mutex
+	associations       uint64
+	activeAssociations int64
+	requests           map[string]uint64
+	errors             uint64
+	reportsEmitted     uint64
+}
+
+// request учитывает запрос службы service
+func (st *serverStats) request(service string, failed bool) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if st.requests == nil {
+		st.requests = make(map[string]uint64)
+	}
+	st.requests[service]++
+	if failed {
+		st.errors++
+	}
+}
+
+// report учитывает отправленный отчёт
+func (st *serverStats) report() {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.reportsEmitted++
+}
+
+// countRequest учитывает запрос службы service; вызывается через defer
+// с указателем на возвращаемую ошибку
+func (s *Server) countRequest(service string, err *error) {
+	s.stats.request(service, *err != nil)
+}
+
+// Associate учитывает открытие ассоциации и возвращает функцию её закрытия.
+// Вызывается транспортом, принимающим соединения клиентов:
+//
+//	release := s.Associate()
+//	defer release()
+func (s *Server) Associate() (release func()) {
+	s.stats.mu.Lock()
+	s.stats.associations++
+	s.stats.activeAssociations++
+	s.stats.mu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			s.stats.mu.Lock()
+			s.stats.activeAssociations--
+			s.stats.mu.Unlock()
+		})
+	}
+}
+
+// Stats возвращает снимок счётчиков сервера
+func (s *Server) Stats() Stats {
+	s.stats.mu.Lock()
+	defer s.stats.mu.Unlock()
+	requests := make(map[string]uint64, len(s.stats.requests))
+	maps.Copy(requests, s.stats.requests)
+	return Stats{
+		Associations:       s.stats.associations,
+		ActiveAssociations: s.stats.activeAssociations,
+		Requests:           requests,
+		Errors:             s.stats.errors,
+		ReportsEmitted:     s.stats.reportsEmitted,
+	}
+}
+
+// PublishExpvar публикует Stats в expvar под именем name (например, для
+// /debug/vars симулятора). Как и expvar.Publish, паникует при повторном имени.
+func (s *Server) PublishExpvar(name string) {
+	expvar.Publish(name, expvar.Func(func() any { return s.Stats() }))
+}
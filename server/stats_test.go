@@ -0,0 +1,59 @@
+package server
+
+import (
+	"encoding/json"
+	"expvar"
+	"testing"
+	"time"
+
+	"github.com/slonegd/go61850/model"
+	"github.com/slonegd/go61850/osi/mms"
+	"github.com/slonegd/go61850/osi/mms/variant"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStats(t *testing.T) {
+	clock := NewSimulatedClock(testTime)
+	s := newReportTestServer(t, clock)
+
+	release := s.Associate()
+	s.Associate()
+	assert.Equal(t, int64(2), s.Stats().ActiveAssociations)
+	release()
+	release()
+
+	s.Read("simpleIOGenericIO", "GGIO1$MX$AnIn1$mag$f")
+	s.Read("simpleIOGenericIO", "GGIO1$MX$Unknown")
+	_, err := s.GetNameList(mms.ObjectClassDomain, mms.ScopeVMD, "", "")
+	assert.NoError(t, err)
+	_, err = s.FileDirectory("", "")
+	assert.Error(t, err)
+
+	// Чтение значений для отчёта не считается запросом
+	disable, err := s.EnableReport("simpleIOGenericIO", ReportControl{
+		Name: "LLN0$RP$MeasRCB", RptID: "Meas", DataSet: "LLN0$Measurements",
+		TrgOps: model.TriggerDataChange,
+	}, func(Report) {})
+	assert.NoError(t, err)
+	defer disable()
+	assert.NoError(t, s.SetValue("simpleIOGenericIO", "GGIO1$MX$AnIn1$mag$f", variant.NewFloat32Variant(10)))
+	clock.Advance(time.Millisecond)
+
+	stats := s.Stats()
+	assert.Equal(t, Stats{
+		Associations:       2,
+		ActiveAssociations: 1,
+		Requests:           map[string]uint64{"read": 2, "getNameList": 1, "fileDirectory": 1},
+		Errors:             2,
+		ReportsEmitted:     1,
+	}, stats)
+
+	// Снимок не меняется последующими запросами
+	s.Read("simpleIOGenericIO", "GGIO1$MX$AnIn1$mag$f")
+	assert.Equal(t, uint64(2), stats.Requests["read"])
+
+	s.PublishExpvar("go61850-server-stats-test")
+	var published Stats
+	assert.NoError(t, json.Unmarshal([]byte(expvar.Get("go61850-server-stats-test").String()), &published))
+	assert.Equal(t, uint64(3), published.Requests["read"])
+}
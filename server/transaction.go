@@ -0,0 +1,125 @@
+package server
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/slonegd/go61850/model"
+	"github.com/slonegd/go61850/osi/mms"
+	"github.com/slonegd/go61850/osi/mms/variant"
+)
+
+// Tx - транзакция изменения значений модели (см. Server.Transaction)
+type Tx struct {
+	server  *Server
+	changes []txChange
+}
+
+// txChange - изменение листового атрибута, применяемое при завершении транзакции
+type txChange struct {
+	domainID, itemID string
+	attribute        *model.DataAttribute
+	triggers         model.Trigger
+	value            *variant.Variant
+}
+
+// SetValue добавляет в транзакцию изменение листового атрибута itemID домена domainID
+// ("LN$FC$DO$DA..."). Атрибут проверяется сразу, значение применяется при завершении
+// транзакции; повторное изменение атрибута заменяет предыдущее, и наблюдатели
+// получают одно уведомление со значением до транзакции в Old.
+func (tx *Tx) SetValue(domainID, itemID string, value *variant.Variant) error {
+	attribute, triggers, err := tx.server.leafAttribute(domainID, itemID)
+	if err != nil {
+		return err
+	}
+	for i := range tx.changes {
+		if tx.changes[i].attribute == attribute {
+			tx.changes[i].value = value
+			return nil
+		}
+	}
+	tx.changes = append(tx.changes, txChange{domainID, itemID, attribute, triggers, value})
+	return nil
+}
+
+// Transaction выполняет fn и атомарно применяет изменения, добавленные tx.SetValue:
+// чтение (Read, ReadDataSet, отчёты) видит либо все новые значения, либо ни одного,
+// поэтому элементы набора данных, изменяемые вместе, не читаются вразнобой.
+// Если fn возвращает ошибку, изменения отбрасываются и ошибка возвращается.
+// Наблюдатели уведомляются после применения всех изменений в порядке SetValue.
+func (s *Server) Transaction(fn func(tx *Tx) error) error {
+	tx := &Tx{server: s}
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	changes := make([]ValueChange, 0, len(tx.changes))
+	s.valuesMu.Lock()
+	for _, c := range tx.changes {
+		old := c.attribute.Value
+		c.attribute.Value = c.value
+		reason := c.triggers & model.TriggerDataUpdate
+		if !old.Equal(c.value) {
+			reason |= c.triggers & (model.TriggerDataChange | model.TriggerQualityChange)
+		}
+		if reason != 0 {
+			changes = append(changes, ValueChange{DomainID: c.domainID, ItemID: c.itemID, Old: old, New: c.value, Reason: reason})
+		}
+	}
+	s.valuesMu.Unlock()
+
+	for _, change := range changes {
+		s.notify(change)
+	}
+	return nil
+}
+
+// leafAttribute находит листовой атрибут itemID домена domainID и его условия
+func (s *Server) leafAttribute(domainID, itemID string) (*model.DataAttribute, model.Trigger, error) {
+	ld := s.model.LogicalDevice(domainID)
+	if ld == nil {
+		return nil, 0, fmt.Errorf("domain %s not found", domainID)
+	}
+	parts := strings.Split(itemID, "$")
+	ln := ld.LogicalNode(parts[0])
+	if ln == nil || len(parts) < 2 {
+		return nil, 0, fmt.Errorf("logical node of %s not found in %s", itemID, domainID)
+	}
+	da, triggers, err := ln.Attribute(mms.FunctionalConstraint(parts[1]), parts[2:]...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("set %s/%s: %w", domainID, itemID, err)
+	}
+	if len(da.Attributes) != 0 {
+		return nil, 0, fmt.Errorf("set %s/%s: attribute is not a leaf", domainID, itemID)
+	}
+	return da, triggers, nil
+}
+
+// ReadDataSet читает значения элементов набора данных dataSet домена domainID
+// согласованным снимком: все элементы читаются без промежуточных изменений
+func (s *Server) ReadDataSet(domainID, dataSet string) ([]mms.AccessResult, error) {
+	ld := s.model.LogicalDevice(domainID)
+	if ld == nil {
+		return nil, fmt.Errorf("domain %s not found", domainID)
+	}
+	for _, ds := range ld.DataSets {
+		if ds.Name == dataSet {
+			return s.readMembers(domainID, ds.Members, nil), nil
+		}
+	}
+	return nil, fmt.Errorf("data set %s not found in %s", dataSet, domainID)
+}
+
+// readMembers читает элементы members домена domainID одним снимком;
+// если include задан, читаются только элементы с include[i] != 0
+func (s *Server) readMembers(domainID string, members []string, include []model.Trigger) []mms.AccessResult {
+	s.valuesMu.RLock()
+	defer s.valuesMu.RUnlock()
+	results := make([]mms.AccessResult, len(members))
+	for i, member := range members {
+		if include == nil || (i < len(include) && include[i] != 0) {
+			results[i] = s.readLocked(domainID, member)
+		}
+	}
+	return results
+}
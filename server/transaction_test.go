@@ -0,0 +1,110 @@
+package server
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/slonegd/go61850/model"
+	"github.com/slonegd/go61850/osi/mms"
+	"github.com/slonegd/go61850/osi/mms/variant"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransaction(t *testing.T) {
+	m := newTestModel()
+	ln := m.LogicalDevice("simpleIOGenericIO").LogicalNode("GGIO1")
+	for _, do := range []string{"AnIn1", "AnIn2"} {
+		mag, _, err := ln.Attribute(mms.FCMX, do, "mag")
+		assert.NoError(t, err)
+		mag.Triggers = model.TriggerDataChange
+	}
+	s := New(m)
+
+	var members []int
+	cancel, err := s.ObserveDataSet("simpleIOGenericIO", "LLN0$Measurements", model.TriggerDataChange,
+		func(member int, change ValueChange) {
+			// Наблюдатель видит уже все изменения транзакции
+			results, err := s.ReadDataSet("simpleIOGenericIO", "LLN0$Measurements")
+			assert.NoError(t, err)
+			assert.Equal(t, float32(7), results[1].Value.Structure()[0].Structure()[0].Float32())
+			members = append(members, member)
+		})
+	assert.NoError(t, err)
+	defer cancel()
+
+	err = s.Transaction(func(tx *Tx) error {
+		assert.NoError(t, tx.SetValue("simpleIOGenericIO", "GGIO1$MX$AnIn1$mag$f", variant.NewFloat32Variant(5)))
+		return tx.SetValue("simpleIOGenericIO", "GGIO1$MX$AnIn2$mag$f", variant.NewFloat32Variant(7))
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{0, 1}, members)
+
+	// Ошибка отменяет все изменения транзакции
+	failed := errors.New("failed")
+	err = s.Transaction(func(tx *Tx) error {
+		assert.NoError(t, tx.SetValue("simpleIOGenericIO", "GGIO1$MX$AnIn1$mag$f", variant.NewFloat32Variant(9)))
+		assert.Error(t, tx.SetValue("simpleIOGenericIO", "GGIO1$MX$AnIn1$mag", variant.NewFloat32Variant(9)))
+		return failed
+	})
+	assert.ErrorIs(t, err, failed)
+	assert.Equal(t, float32(5), s.Read("simpleIOGenericIO", "GGIO1$MX$AnIn1$mag$f").Value.Float32())
+	assert.Equal(t, []int{0, 1}, members)
+
+	_, err = s.ReadDataSet("simpleIOGenericIO", "LLN0$Events")
+	assert.EqualError(t, err, "data set LLN0$Events not found in simpleIOGenericIO")
+}
+
+func TestTransactionRepeatedSetValue(t *testing.T) {
+	m := newTestModel()
+	mag, _, err := m.LogicalDevice("simpleIOGenericIO").LogicalNode("GGIO1").Attribute(mms.FCMX, "AnIn1", "mag")
+	assert.NoError(t, err)
+	mag.Triggers = model.TriggerDataChange
+	s := New(m)
+
+	var changes []ValueChange
+	cancel := s.Observe("simpleIOGenericIO", "GGIO1$MX$AnIn1$mag$f", model.TriggerDataChange, func(change ValueChange) {
+		changes = append(changes, change)
+	})
+	defer cancel()
+
+	// Повторное изменение заменяет предыдущее: одно уведомление, Old - значение до транзакции
+	err = s.Transaction(func(tx *Tx) error {
+		assert.NoError(t, tx.SetValue("simpleIOGenericIO", "GGIO1$MX$AnIn1$mag$f", variant.NewFloat32Variant(5)))
+		return tx.SetValue("simpleIOGenericIO", "GGIO1$MX$AnIn1$mag$f", variant.NewFloat32Variant(7))
+	})
+	assert.NoError(t, err)
+	assert.Len(t, changes, 1)
+	assert.Equal(t, variant.NewFloat32Variant(1.5), changes[0].Old)
+	assert.Equal(t, variant.NewFloat32Variant(7), changes[0].New)
+	assert.Equal(t, float32(7), s.Read("simpleIOGenericIO", "GGIO1$MX$AnIn1$mag$f").Value.Float32())
+}
+
+func TestTransactionConsistentSnapshot(t *testing.T) {
+	s := New(newTestModel())
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := range 200 {
+			value := variant.NewFloat32Variant(float32(i))
+			s.Transaction(func(tx *Tx) error {
+				tx.SetValue("simpleIOGenericIO", "GGIO1$MX$AnIn1$mag$f", value)
+				return tx.SetValue("simpleIOGenericIO", "GGIO1$MX$AnIn2$mag$f", value)
+			})
+		}
+	}()
+
+	// Элементы набора, изменяемые одной транзакцией, всегда равны
+	for range 200 {
+		results, err := s.ReadDataSet("simpleIOGenericIO", "LLN0$Measurements")
+		assert.NoError(t, err)
+		anIn1 := results[0].Value.Structure()[0].Structure()[0].Float32()
+		anIn2 := results[1].Value.Structure()[0].Structure()[0].Float32()
+		if anIn1 != 1.5 || anIn2 != 2.5 {
+			assert.Equal(t, anIn1, anIn2)
+		}
+	}
+	wg.Wait()
+}
@@ -0,0 +1,144 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/slonegd/go61850/model"
+	"github.com/slonegd/go61850/osi/mms"
+	"github.com/slonegd/go61850/osi/mms/variant"
+)
+
+// HandleWriteRequest обрабатывает BER-кодированный MMS Write Request PDU
+// и возвращает BER-кодированный Write Response PDU.
+func (s *Server) HandleWriteRequest(pdu []byte) ([]byte, error) {
+	request, err := mms.ParseWriteRequest(pdu)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Write Request: %w", err)
+	}
+	response := mms.WriteResponse{InvokeID: request.InvokeID}
+	// Переменные записываются по отдельности: отказ одной не отменяет запись остальных
+	for _, item := range request.Variables() {
+		s.logger.Debug("MMS Write Request: variable=%s value=%s", item.Variable, item.Value)
+		var result mms.WriteResult
+		if err := s.Write(item.Variable.DomainID, item.Variable.ItemID, item.Value); err != nil {
+			errors.As(err, &result.Error)
+		}
+		response.Results = append(response.Results, result)
+	}
+	return response.Bytes(), nil
+}
+
+// Write записывает значение переменной модели по MMS имени "LN$FC$DO$DA..."
+// (например, "GGIO1$CF$AnIn1$db"). Составной атрибут записывается структурой
+// значений своих компонентов атомарно, как в Transaction; наблюдатели
+// получают изменение каждого компонента.
+//
+// Ошибка всегда имеет тип *mms.DataAccessError:
+//   - object-access-denied - переменная VMD или FC ST и MX (значения процесса);
+//   - object-non-existent - объекта нет в модели;
+//   - object-access-unsupported - имя указывает на LN или DO, а не на атрибут;
+//   - type-inconsistent - тип значения не совпадает с типом атрибута.
+func (s *Server) Write(domainID, itemID string, value *variant.Variant) (err error) {
+	defer s.countRequest(mms.ServiceWrite.String(), &err)
+	if accessErr := s.write(domainID, itemID, value); accessErr != nil {
+		s.logger.Debug("write %s/%s: %v", domainID, itemID, accessErr)
+		return accessErr
+	}
+	return nil
+}
+
+// write записывает переменную модели и возвращает причину отказа
+func (s *Server) write(domainID, itemID string, value *variant.Variant) *mms.DataAccessError {
+	if domainID == "" {
+		return &mms.DataAccessError{ErrorCode: mms.ObjectAccessDenied}
+	}
+	ld := s.model.LogicalDevice(domainID)
+	if ld == nil {
+		return &mms.DataAccessError{ErrorCode: mms.ObjectNonExistent}
+	}
+	parts := strings.Split(itemID, "$")
+	ln := ld.LogicalNode(parts[0])
+	if ln == nil {
+		return &mms.DataAccessError{ErrorCode: mms.ObjectNonExistent}
+	}
+	if len(parts) < 3 {
+		return &mms.DataAccessError{ErrorCode: mms.ObjectAccessUnsupported}
+	}
+
+	fc := mms.FunctionalConstraint(parts[1])
+	da, _, err := ln.Attribute(fc, parts[2:]...)
+	if err != nil {
+		if _, err := ln.FCType(fc, parts[2:]...); err == nil {
+			// Имя DO (FCD)
+			return &mms.DataAccessError{ErrorCode: mms.ObjectAccessUnsupported}
+		}
+		return &mms.DataAccessError{ErrorCode: mms.ObjectNonExistent}
+	}
+	if fc == mms.FCST || fc == mms.FCMX {
+		return &mms.DataAccessError{ErrorCode: mms.ObjectAccessDenied}
+	}
+
+	err = s.Transaction(func(tx *Tx) error {
+		return s.setValues(tx, domainID, itemID, da, value)
+	})
+	var accessErr *mms.DataAccessError
+	if errors.As(err, &accessErr) {
+		return accessErr
+	}
+	if err != nil {
+		return &mms.DataAccessError{ErrorCode: mms.ObjectNonExistent}
+	}
+	return nil
+}
+
+// setValues добавляет в транзакцию запись value в атрибут da с MMS именем itemID;
+// составной атрибут записывается покомпонентно
+func (s *Server) setValues(tx *Tx, domainID, itemID string, da *model.DataAttribute, value *variant.Variant) error {
+	if len(da.Attributes) == 0 {
+		s.valuesMu.RLock()
+		current := da.Value
+		s.valuesMu.RUnlock()
+		if current != nil {
+			value = widen(current, value)
+		}
+		if current != nil && !sameType(current, value) {
+			return &mms.DataAccessError{ErrorCode: mms.TypeInconsistent}
+		}
+		return tx.SetValue(domainID, itemID, value)
+	}
+
+	if value.Type() != variant.Structure || len(value.Structure()) != len(da.Attributes) {
+		return &mms.DataAccessError{ErrorCode: mms.TypeInconsistent}
+	}
+	for i, component := range da.Attributes {
+		if err := s.setValues(tx, domainID, itemID+"$"+component.Name, component, value.Structure()[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// widen приводит integer и unsigned, разобранные в 32 бита, к 64-битному типу
+// атрибута: размер в MMS Data не передаётся, и малое значение INT64 приходит как Int32
+func widen(current, value *variant.Variant) *variant.Variant {
+	switch {
+	case current.Type() == variant.Int64 && value.Type() == variant.Int32:
+		return variant.NewInt64Variant(value.Int64())
+	case current.Type() == variant.Uint64 && value.Type() == variant.Uint32:
+		return variant.NewUint64Variant(value.Uint64())
+	}
+	return value
+}
+
+// sameType сообщает, совпадают ли типы значений; у bit-string учитывается размер
+func sameType(a, b *variant.Variant) bool {
+	if a.Type() != b.Type() {
+		return false
+	}
+	if a.Type() == variant.BitString {
+		return a.BitString().BitSize == b.BitString().BitSize
+	}
+	return true
+}
@@ -0,0 +1,149 @@
+package server
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/slonegd/go61850/model"
+	"github.com/slonegd/go61850/osi/mms"
+	"github.com/slonegd/go61850/osi/mms/variant"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServerWrite(t *testing.T) {
+	m := newTestModel()
+	anIn1 := m.LogicalDevice("simpleIOGenericIO").LogicalNode("GGIO1").DataObject("AnIn1")
+	anIn1.Children = append(anIn1.Children, &model.DataAttribute{
+		Name: "units", FC: mms.FCCF, Triggers: model.TriggerDataChange,
+		Attributes: []*model.DataAttribute{
+			{Name: "SIUnit", FC: mms.FCCF, Value: variant.NewInt32Variant(0)},
+			{Name: "multiplier", FC: mms.FCCF, Value: variant.NewInt32Variant(0)},
+		},
+	})
+	s := New(m)
+
+	var changes []string
+	cancel := s.Observe("simpleIOGenericIO", "GGIO1$CF$AnIn1", model.TriggerDataChange, func(change ValueChange) {
+		changes = append(changes, change.ItemID)
+	})
+	defer cancel()
+
+	assert.NoError(t, s.Write("simpleIOGenericIO", "GGIO1$DC$AnIn1$d", variant.NewInt32Variant(7)))
+	assert.Equal(t, variant.NewInt32Variant(7), s.Read("simpleIOGenericIO", "GGIO1$DC$AnIn1$d").Value)
+
+	// Составной атрибут записывается покомпонентно с условиями атрибута верхнего уровня
+	units := variant.NewStructureVariant([]*variant.Variant{variant.NewInt32Variant(29), variant.NewInt32Variant(3)})
+	assert.NoError(t, s.Write("simpleIOGenericIO", "GGIO1$CF$AnIn1$units", units))
+	assert.Equal(t, units, s.Read("simpleIOGenericIO", "GGIO1$CF$AnIn1$units").Value)
+	assert.Equal(t, []string{"GGIO1$CF$AnIn1$units$SIUnit", "GGIO1$CF$AnIn1$units$multiplier"}, changes)
+
+	for _, tt := range []struct {
+		domain, item string
+		value        *variant.Variant
+		want         mms.DataAccessErrorCode
+	}{
+		{"", "Vendor", variant.NewInt32Variant(1), mms.ObjectAccessDenied},
+		{"simpleIOGenericIO", "GGIO1$MX$AnIn1$mag$f", variant.NewFloat32Variant(1), mms.ObjectAccessDenied},
+		{"unknown", "GGIO1$DC$AnIn1$d", variant.NewInt32Variant(1), mms.ObjectNonExistent},
+		{"simpleIOGenericIO", "GGIO2$DC$AnIn1$d", variant.NewInt32Variant(1), mms.ObjectNonExistent},
+		{"simpleIOGenericIO", "GGIO1$DC$AnIn1$x", variant.NewInt32Variant(1), mms.ObjectNonExistent},
+		{"simpleIOGenericIO", "GGIO1$DC", variant.NewInt32Variant(1), mms.ObjectAccessUnsupported},
+		{"simpleIOGenericIO", "GGIO1$DC$AnIn1", variant.NewInt32Variant(1), mms.ObjectAccessUnsupported},
+		{"simpleIOGenericIO", "GGIO1$DC$AnIn1$d", variant.NewBoolVariant(true), mms.TypeInconsistent},
+		{"simpleIOGenericIO", "GGIO1$CF$AnIn1$units", variant.NewInt32Variant(1), mms.TypeInconsistent},
+	} {
+		t.Run(tt.domain+"/"+tt.item, func(t *testing.T) {
+			err := s.Write(tt.domain, tt.item, tt.value)
+			assert.Equal(t, &mms.DataAccessError{ErrorCode: tt.want}, err)
+		})
+	}
+	// Ошибка в компоненте отменяет запись всей структуры
+	partial := variant.NewStructureVariant([]*variant.Variant{variant.NewInt32Variant(1), variant.NewBoolVariant(true)})
+	assert.Error(t, s.Write("simpleIOGenericIO", "GGIO1$CF$AnIn1$units", partial))
+	assert.Equal(t, units, s.Read("simpleIOGenericIO", "GGIO1$CF$AnIn1$units").Value)
+
+	stats := s.Stats()
+	assert.Equal(t, uint64(12), stats.Requests["write"])
+	assert.Equal(t, uint64(10), stats.Errors)
+}
+
+func TestServerHandleWriteRequest(t *testing.T) {
+	s := New(newTestModel())
+
+	request := &mms.WriteRequest{InvokeID: 3, DomainID: "simpleIOGenericIO", ItemID: "GGIO1$MX$AnIn1$mag$f",
+		Value: variant.NewFloat32Variant(1)}
+	pdu, err := request.Bytes()
+	assert.NoError(t, err)
+	pdu, err = s.HandleWriteRequest(pdu)
+	assert.NoError(t, err)
+
+	response, err := mms.ParseWriteResponse(pdu)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(3), response.InvokeID)
+	assert.ErrorIs(t, response.Err(), &mms.DataAccessError{ErrorCode: mms.ObjectAccessDenied})
+}
+
+func TestServerTypeSpecification(t *testing.T) {
+	m := newTestModel()
+	ln := m.LogicalDevice("simpleIOGenericIO").LogicalNode("GGIO1")
+	for _, do := range []string{"AnIn1", "AnIn2"} {
+		q, _, err := ln.Attribute(mms.FCMX, do, "q")
+		assert.NoError(t, err)
+		q.Type = &mms.TypeSpecification{Type: mms.TypeSpecBitString, BitStringSize: -13}
+	}
+	s := New(m)
+
+	request := mms.NewGetVariableAccessAttributesRequest("simpleIOGenericIO", "GGIO1$MX")
+	pdu, err := s.HandleGetVariableAccessAttributesRequest(request.Bytes())
+	assert.NoError(t, err)
+	// Тип AnIn1 совпадает с ответом libIEC61850 (см. osi/mms/type_specification_test.go)
+	assert.Contains(t, fmt.Sprintf("%x", pdu), "303c8005416e496e31a133a231a12f301a80036d6167a113a211a10f300d800166"+
+		"a108a7060201200201083008800171a1038401f33007800174a1029100")
+	response, err := mms.ParseGetVariableAccessAttributesResponse(pdu)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(2), response.InvokeID)
+	assert.Equal(t, "AnIn2", response.TypeSpecification.Structure.Components[1].Name)
+
+	spec, err := s.TypeSpecification("simpleIOGenericIO", "GGIO1")
+	assert.NoError(t, err)
+	assert.Equal(t, []mms.ComponentSpec{
+		{Name: "MX", Type: mustType(t, s, "GGIO1$MX")},
+		{Name: "DC", Type: mustType(t, s, "GGIO1$DC")},
+	}, spec.Structure.Components)
+
+	spec, err = s.TypeSpecification("", "Vendor")
+	assert.NoError(t, err)
+	assert.Equal(t, &mms.TypeSpecification{Type: mms.TypeSpecInteger, IntegerSize: 32}, spec)
+
+	_, err = s.TypeSpecification("simpleIOGenericIO", "GGIO1$ST")
+	assert.ErrorIs(t, err, &mms.ServiceError{Class: mms.ErrorClassDefinition, Code: mms.DefinitionErrorObjectUndefined})
+	request = mms.NewGetVariableAccessAttributesRequest("simpleIOGenericIO", "GGIO1$MX$AnIn3")
+	_, err = s.HandleGetVariableAccessAttributesRequest(request.Bytes())
+	assert.Equal(t, &mms.ServiceError{Class: mms.ErrorClassDefinition, Code: mms.DefinitionErrorObjectUndefined}, err)
+}
+
+// mustType возвращает тип переменной itemID домена simpleIOGenericIO
+func mustType(t *testing.T, s *Server, itemID string) *mms.TypeSpecification {
+	spec, err := s.TypeSpecification("simpleIOGenericIO", itemID)
+	assert.NoError(t, err)
+	return spec
+}
+
+func TestServerWriteInt64(t *testing.T) {
+	m := newTestModel()
+	anIn1 := m.LogicalDevice("simpleIOGenericIO").LogicalNode("GGIO1").DataObject("AnIn1")
+	anIn1.Children = append(anIn1.Children,
+		&model.DataAttribute{Name: "actVal", FC: mms.FCCF, Value: variant.NewInt64Variant(0)},
+		&model.DataAttribute{Name: "cnt", FC: mms.FCCF, Value: variant.NewUint64Variant(0)},
+	)
+	s := New(m)
+
+	// Малые значения приходят в MMS Data как Int32 и Uint32 и приводятся к типу атрибута
+	assert.NoError(t, s.Write("simpleIOGenericIO", "GGIO1$CF$AnIn1$actVal", variant.NewInt32Variant(-5)))
+	assert.Equal(t, variant.NewInt64Variant(-5), s.Read("simpleIOGenericIO", "GGIO1$CF$AnIn1$actVal").Value)
+	assert.NoError(t, s.Write("simpleIOGenericIO", "GGIO1$CF$AnIn1$cnt", variant.NewUint32Variant(7)))
+	assert.Equal(t, variant.NewUint64Variant(7), s.Read("simpleIOGenericIO", "GGIO1$CF$AnIn1$cnt").Value)
+
+	err := s.Write("simpleIOGenericIO", "GGIO1$CF$AnIn1$actVal", variant.NewUint32Variant(1))
+	assert.Equal(t, &mms.DataAccessError{ErrorCode: mms.TypeInconsistent}, err)
+}
@@ -0,0 +1,102 @@
+package go61850
+
+import (
+	"context"
+	"errors"
+
+	"github.com/slonegd/go61850/osi/mms"
+)
+
+// RequestHandler отвечает на confirmed-RequestPDU сервера и возвращает закодированный
+// ConfirmedServiceResponse (например, (&mms.IdentifyResponse{...}).Bytes()).
+// Ошибка *mms.ServiceError отправляется серверу как confirmed-ErrorPDU,
+// остальные ошибки - как RejectPDU с причиной invalid-argument.
+type RequestHandler func(ctx context.Context, request *mms.ConfirmedRequest) ([]byte, error)
+
+// WithRequestHandler задаёт обработчик запросов сервера для сервиса service.
+// По умолчанию клиент отвечает на Identify и Status, запросы остальных сервисов
+// отклоняются RejectPDU (unrecognized-service). nil убирает обработчик сервиса.
+func WithRequestHandler(service mms.ConfirmedService, handler RequestHandler) MmsClientOption {
+	return func(c *MmsClient) {
+		if c.requestHandlers == nil {
+			c.requestHandlers = defaultRequestHandlers()
+		}
+		if handler == nil {
+			delete(c.requestHandlers, service)
+			return
+		}
+		c.requestHandlers[service] = handler
+	}
+}
+
+// WithIdentity задаёт ответ клиента на запрос Identify сервера
+func WithIdentity(vendorName, modelName, revision string) MmsClientOption {
+	identity := &mms.IdentifyResponse{VendorName: vendorName, ModelName: modelName, Revision: revision}
+	return WithRequestHandler(mms.ServiceIdentify, func(context.Context, *mms.ConfirmedRequest) ([]byte, error) {
+		return identity.Bytes(), nil
+	})
+}
+
+// defaultRequestHandlers возвращает обработчики минимального набора сервисов VMD клиента
+func defaultRequestHandlers() map[mms.ConfirmedService]RequestHandler {
+	identity := &mms.IdentifyResponse{VendorName: "go61850", ModelName: "MmsClient", Revision: "1"}
+	status := &mms.StatusResponse{
+		LogicalStatus:  mms.LogicalStatusStateChangesAllowed,
+		PhysicalStatus: mms.PhysicalStatusOperational,
+	}
+	return map[mms.ConfirmedService]RequestHandler{
+		mms.ServiceIdentify: func(context.Context, *mms.ConfirmedRequest) ([]byte, error) {
+			return identity.Bytes(), nil
+		},
+		mms.ServiceStatus: func(context.Context, *mms.ConfirmedRequest) ([]byte, error) {
+			return status.Bytes(), nil
+		},
+	}
+}
+
+// handleServerRequest отвечает на confirmed-RequestPDU сервера обработчиком из
+// WithRequestHandler или RejectPDU. Возвращает false, если mmsData - не запрос сервера.
+func (c *MmsClient) handleServerRequest(ctx context.Context, mmsData []byte) bool {
+	if !mms.IsConfirmedRequest(mmsData) {
+		return false
+	}
+
+	var answer []byte
+	request, err := mms.ParseConfirmedRequest(mmsData)
+	switch {
+	case err != nil:
+		c.logger.Debug("failed to parse server Confirmed-RequestPDU: %v", err)
+		if id, ok := mms.RequestInvokeID(mmsData); ok {
+			answer = (&mms.Reject{InvokeID: id, Reason: mms.RejectOther}).Bytes()
+		}
+	case c.requestHandlers[request.Service] == nil:
+		c.logger.Debug("server request %s (invokeID %d) rejected: unrecognized service", request.Service, request.InvokeID)
+		answer = (&mms.Reject{InvokeID: request.InvokeID, Reason: mms.RejectUnrecognizedService}).Bytes()
+	default:
+		answer = c.answerServerRequest(ctx, request)
+	}
+	if answer == nil {
+		return true
+	}
+
+	c.logger.Debug("MMS answer to server request PDU: %x", answer)
+	if err := c.mmsClient.SendMmsPduContext(ctx, answer); err != nil {
+		c.logger.Debug("failed to answer server request: %v", err)
+	}
+	return true
+}
+
+// answerServerRequest вызывает обработчик запроса и кодирует ответ
+func (c *MmsClient) answerServerRequest(ctx context.Context, request *mms.ConfirmedRequest) []byte {
+	service, err := c.requestHandlers[request.Service](ctx, request)
+	if err == nil {
+		return (&mms.ConfirmedResponse{InvokeID: request.InvokeID, Service: service}).Bytes()
+	}
+
+	c.logger.Debug("server request %s (invokeID %d) failed: %v", request.Service, request.InvokeID, err)
+	var serviceError *mms.ServiceError
+	if errors.As(err, &serviceError) {
+		return (&mms.ConfirmedError{InvokeID: request.InvokeID, ServiceError: *serviceError}).Bytes()
+	}
+	return (&mms.Reject{InvokeID: request.InvokeID, Reason: mms.RejectInvalidArgument}).Bytes()
+}
@@ -0,0 +1,82 @@
+package go61850
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/slonegd/go61850/mmstest"
+	"github.com/slonegd/go61850/osi/mms"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServerRequest(t *testing.T) {
+	f, err := os.Open("mmstest/testdata/read.txt")
+	assert.NoError(t, err)
+	defer f.Close()
+	exchanges, err := mmstest.ParseTranscript(f)
+	assert.NoError(t, err)
+	read := exchanges[2]
+
+	server := mmstest.NewTranscriptServer(t,
+		exchanges[0], exchanges[1],
+		// До ответа на чтение сервер запрашивает Identify (invokeID 7)
+		mmstest.Exchange{
+			Request:   read.Request,
+			Responses: []string{"03 00 00 1b 02 f0 80 01 00 01 00 61 0e 30 0c 02 01 03 a0 07 a0 05 02 01 07 82 00"},
+		},
+		// Ответ Identify, затем запрос Rename (invokeID 8), обработчика которого нет
+		mmstest.Exchange{
+			Request: "03 00 00 24 02 f0 80 01 00 01 00 61 17 30 15 02 01 03 a0 10" +
+				" a1 0e 02 01 07 a2 09 80 01 56 81 01 4d 82 01 31",
+			Responses: []string{"03 00 00 1b 02 f0 80 01 00 01 00 61 0e 30 0c 02 01 03 a0 07 a0 05 02 01 08 a3 00"},
+		},
+		// RejectPDU unrecognized-service, затем ответ на чтение
+		mmstest.Exchange{
+			Request:   "03 00 00 1c 02 f0 80 01 00 01 00 61 0f 30 0d 02 01 03 a0 08 a4 06 80 01 08 81 01 01",
+			Responses: read.Responses,
+		},
+	)
+	conn := server.Dial()
+	ctx := context.Background()
+	client, err := NewMmsClient(ctx, conn, WithIdentity("V", "M", "1"))
+	assert.NoError(t, err)
+	_, err = client.Initiate(ctx)
+	assert.NoError(t, err)
+
+	result, err := client.ReadObject(ctx, &mms.ReadRequest{DomainID: "simpleIOGenericIO", ItemID: "GGIO1$MX"})
+	assert.NoError(t, err)
+	assert.True(t, result.Success)
+
+	conn.Close()
+	assert.NoError(t, server.Wait())
+}
+
+func TestAnswerServerRequest(t *testing.T) {
+	client := &MmsClient{logger: defaultLogger(), requestHandlers: defaultRequestHandlers()}
+	ctx := context.Background()
+
+	answer := client.answerServerRequest(ctx, &mms.ConfirmedRequest{InvokeID: 1, Service: mms.ServiceStatus})
+	assert.Equal(t, "a10b020101a006800100810100", fmt.Sprintf("%x", answer))
+
+	// *mms.ServiceError отправляется как confirmed-ErrorPDU
+	WithRequestHandler(mms.ServiceRead, func(context.Context, *mms.ConfirmedRequest) ([]byte, error) {
+		return nil, &mms.ServiceError{Class: mms.ErrorClassAccess, Code: 2}
+	})(client)
+	answer = client.answerServerRequest(ctx, &mms.ConfirmedRequest{InvokeID: 2, Service: mms.ServiceRead})
+	assert.Equal(t, "a20a800102a205a003870102", fmt.Sprintf("%x", answer))
+
+	// Прочие ошибки - RejectPDU invalid-argument
+	WithRequestHandler(mms.ServiceWrite, func(context.Context, *mms.ConfirmedRequest) ([]byte, error) {
+		return nil, errors.New("bad argument")
+	})(client)
+	answer = client.answerServerRequest(ctx, &mms.ConfirmedRequest{InvokeID: 3, Service: mms.ServiceWrite})
+	assert.Equal(t, "a406800103810104", fmt.Sprintf("%x", answer))
+
+	// nil убирает обработчик сервиса
+	WithRequestHandler(mms.ServiceStatus, nil)(client)
+	assert.NotContains(t, client.requestHandlers, mms.ServiceStatus)
+	assert.Contains(t, client.requestHandlers, mms.ServiceIdentify)
+}
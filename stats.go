@@ -0,0 +1,119 @@
+package go61850
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// ReferenceStats - статистика запросов к одной ссылке на объект
+type ReferenceStats struct {
+	// Requests - количество запросов
+	Requests uint64
+	// Errors - количество запросов, завершившихся ошибкой транспорта
+	// или ошибкой доступа к данным
+	Errors uint64
+	// Total - суммарное время ожидания ответов
+	Total time.Duration
+	// Min и Max - минимальное и максимальное время ожидания ответа
+	Min time.Duration
+	Max time.Duration
+}
+
+// Mean возвращает среднее время ожидания ответа
+func (s ReferenceStats) Mean() time.Duration {
+	if s.Requests == 0 {
+		return 0
+	}
+	return s.Total / time.Duration(s.Requests)
+}
+
+// Stats собирает время ожидания ответов и количество ошибок по ссылкам на объекты.
+// Помогает найти медленные точки IED при наладке. Безопасен для конкурентного использования.
+//
+// Ключ статистики - MMS имя "domainId/itemId" для чтения и запроса типа,
+// имя домена для GetNameList.
+type Stats struct {
+	mu   sync.Mutex
+	refs map[string]*ReferenceStats
+}
+
+// NewStats создаёт пустой реестр статистики
+func NewStats() *Stats {
+	return &Stats{refs: make(map[string]*ReferenceStats)}
+}
+
+// Record учитывает запрос к reference длительностью d
+func (s *Stats) Record(reference string, d time.Duration, failed bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rs, ok := s.refs[reference]
+	if !ok {
+		rs = &ReferenceStats{Min: d}
+		s.refs[reference] = rs
+	}
+	rs.Requests++
+	if failed {
+		rs.Errors++
+	}
+	rs.Total += d
+	rs.Min = min(rs.Min, d)
+	rs.Max = max(rs.Max, d)
+}
+
+// Reference возвращает статистику по ссылке
+func (s *Stats) Reference(reference string) (ReferenceStats, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rs, ok := s.refs[reference]
+	if !ok {
+		return ReferenceStats{}, false
+	}
+	return *rs, true
+}
+
+// References возвращает ссылки, по которым есть статистика, в порядке убывания
+// среднего времени ответа (самые медленные первыми)
+func (s *Stats) References() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	refs := make([]string, 0, len(s.refs))
+	for ref := range s.refs {
+		refs = append(refs, ref)
+	}
+	sort.Slice(refs, func(i, j int) bool {
+		mi, mj := s.refs[refs[i]].Mean(), s.refs[refs[j]].Mean()
+		if mi != mj {
+			return mi > mj
+		}
+		return refs[i] < refs[j]
+	})
+	return refs
+}
+
+// Reset очищает статистику
+func (s *Stats) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.refs = make(map[string]*ReferenceStats)
+}
+
+// WithStats включает сбор статистики запросов, доступной через MmsClient.Stats
+func WithStats() MmsClientOption {
+	return func(c *MmsClient) {
+		c.stats = NewStats()
+	}
+}
+
+// Stats возвращает статистику запросов или nil, если сбор не включён (см. WithStats)
+func (c *MmsClient) Stats() *Stats {
+	return c.stats
+}
+
+// recordStats учитывает запрос в статистике, если она включена
+func (c *MmsClient) recordStats(reference string, start time.Time, failed bool) {
+	if c.stats != nil {
+		c.stats.Record(reference, time.Since(start), failed)
+	}
+}
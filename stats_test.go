@@ -0,0 +1,44 @@
+package go61850
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStats(t *testing.T) {
+	s := NewStats()
+	s.Record("LD0/GGIO1$MX$AnIn1", 10*time.Millisecond, false)
+	s.Record("LD0/GGIO1$MX$AnIn1", 30*time.Millisecond, true)
+	s.Record("LD0/GGIO1$MX$AnIn2", 5*time.Millisecond, false)
+
+	rs, ok := s.Reference("LD0/GGIO1$MX$AnIn1")
+	assert.True(t, ok)
+	assert.Equal(t, ReferenceStats{
+		Requests: 2,
+		Errors:   1,
+		Total:    40 * time.Millisecond,
+		Min:      10 * time.Millisecond,
+		Max:      30 * time.Millisecond,
+	}, rs)
+	assert.Equal(t, 20*time.Millisecond, rs.Mean())
+
+	assert.Equal(t, []string{"LD0/GGIO1$MX$AnIn1", "LD0/GGIO1$MX$AnIn2"}, s.References())
+
+	s.Reset()
+	_, ok = s.Reference("LD0/GGIO1$MX$AnIn1")
+	assert.False(t, ok)
+}
+
+func TestMmsClientStatsDisabled(t *testing.T) {
+	c := &MmsClient{}
+	assert.Nil(t, c.Stats())
+	c.recordStats("LD0", time.Now(), false)
+
+	WithStats()(c)
+	c.recordStats("LD0", time.Now(), true)
+	rs, ok := c.Stats().Reference("LD0")
+	assert.True(t, ok)
+	assert.Equal(t, uint64(1), rs.Errors)
+}
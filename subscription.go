@@ -0,0 +1,275 @@
+package go61850
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/slonegd/go61850/model"
+	"github.com/slonegd/go61850/osi/mms"
+	"github.com/slonegd/go61850/osi/mms/variant"
+)
+
+// SubscriptionStoreVersion - версия формата файла подписок; файл другой версии не загружается
+const SubscriptionStoreVersion = 1
+
+// ErrDataSetChanged возвращается Resume, если набор данных RCB на сервере
+// отличается от сохранённого в подписке (Subscription.Members)
+var ErrDataSetChanged = errors.New("data set changed")
+
+// Subscription - подписка на отчёты: включённый блок управления отчётами,
+// его настройки и позиция в буфере отчётов
+type Subscription struct {
+	// RCB - ссылка на блок управления отчётами в формате MMS, например "LD0/LLN0$BR$EventsRCB"
+	RCB      string      `json:"rcb"`
+	Settings RCBSettings `json:"settings"`
+	// EntryID - последняя принятая запись буферизированного RCB; нулевой - с начала буфера
+	EntryID model.EntryID `json:"entryID"`
+	// Members - определение набора данных RCB в порядке элементов на сервере
+	// (см. GetDataSetDirectory); сопоставляет значения отчётов без запроса к серверу.
+	// Resume сверяет его с сервером перед включением RCB.
+	Members []mms.DataSetMember `json:"members,omitempty"`
+}
+
+// Buffered возвращает true для буферизированного блока управления отчётами (BR)
+func (s Subscription) Buffered() bool {
+	return strings.Contains(s.RCB, "$BR$")
+}
+
+// rptID возвращает идентификатор отчётов RCB: без RptID сервер подставляет ссылку на RCB
+func (s Subscription) rptID() string {
+	if s.Settings.RptID != nil && *s.Settings.RptID != "" {
+		return *s.Settings.RptID
+	}
+	return s.RCB
+}
+
+// settings возвращает настройки для ConfigureRCB с позицией в буфере
+func (s Subscription) settings() RCBSettings {
+	settings := s.Settings
+	if s.Buffered() && !s.EntryID.IsZero() {
+		entryID := s.EntryID
+		settings.EntryID = &entryID
+	}
+	return settings
+}
+
+// SubscriptionStore сохраняет подписки между запусками приложения.
+// Save получает полный список подписок после каждого изменения.
+type SubscriptionStore interface {
+	Load() ([]Subscription, error)
+	Save(subscriptions []Subscription) error
+}
+
+// FileSubscriptionStore хранит подписки в JSON файле
+type FileSubscriptionStore struct {
+	path string
+}
+
+// NewFileSubscriptionStore создаёт хранилище подписок в файле path
+func NewFileSubscriptionStore(path string) *FileSubscriptionStore {
+	return &FileSubscriptionStore{path: path}
+}
+
+// subscriptionFile - формат файла FileSubscriptionStore
+type subscriptionFile struct {
+	Version       int            `json:"version"`
+	Subscriptions []Subscription `json:"subscriptions"`
+}
+
+// Load читает подписки из файла; отсутствующий файл даёт пустой список без ошибки
+func (s *FileSubscriptionStore) Load() ([]Subscription, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var file subscriptionFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse subscriptions %s: %w", s.path, err)
+	}
+	if file.Version != SubscriptionStoreVersion {
+		return nil, fmt.Errorf("subscriptions %s: unsupported version %d", s.path, file.Version)
+	}
+	return file.Subscriptions, nil
+}
+
+// Save атомарно записывает подписки в файл
+func (s *FileSubscriptionStore) Save(subscriptions []Subscription) error {
+	data, err := json.MarshalIndent(subscriptionFile{Version: SubscriptionStoreVersion, Subscriptions: subscriptions}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(s.path, data)
+}
+
+// Subscriptions управляет подписками на отчёты и сохраняет их в SubscriptionStore,
+// чтобы перезапущенный шлюз продолжил приём с того же места: Resume заново
+// включает блоки управления отчётами, а буферизированным передаёт EntryID
+// последнего принятого отчёта (см. Track). Для восстановления после обрыва
+// связи Resume передаётся в Reconnector опцией WithOnConnect.
+// Безопасен для конкурентного использования.
+type Subscriptions struct {
+	store SubscriptionStore
+
+	mu    sync.Mutex
+	items []Subscription
+}
+
+// NewSubscriptions загружает подписки из store
+func NewSubscriptions(store SubscriptionStore) (*Subscriptions, error) {
+	items, err := store.Load()
+	if err != nil {
+		return nil, err
+	}
+	return &Subscriptions{store: store, items: items}, nil
+}
+
+// List возвращает копию текущих подписок
+func (s *Subscriptions) List() []Subscription {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Subscription(nil), s.items...)
+}
+
+// Subscribe настраивает и включает RCB sub.RCB (см. ConfigureRCB) и сохраняет подписку,
+// заменяя прежнюю подписку на тот же RCB. Ссылка приводится к формату MMS.
+func (s *Subscriptions) Subscribe(ctx context.Context, client *MmsClient, sub Subscription) error {
+	request, err := client.NewReadRequest(sub.RCB, mms.FCNone)
+	if err != nil {
+		return err
+	}
+	sub.RCB = request.DomainID + "/" + request.ItemID
+	if err := client.ConfigureRCB(ctx, sub.RCB, sub.settings()); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if i := s.index(sub.RCB); i >= 0 {
+		s.items[i] = sub
+	} else {
+		s.items = append(s.items, sub)
+	}
+	return s.store.Save(s.items)
+}
+
+// Unsubscribe выключает RCB rcb (RptEna=false) и удаляет подписку из хранилища.
+// Подписка удаляется и при ошибке записи, например после обрыва связи.
+func (s *Subscriptions) Unsubscribe(ctx context.Context, client *MmsClient, rcb string) error {
+	request, err := client.NewReadRequest(rcb, mms.FCNone)
+	if err != nil {
+		return err
+	}
+	rcb = request.DomainID + "/" + request.ItemID
+
+	s.mu.Lock()
+	if i := s.index(rcb); i >= 0 {
+		s.items = append(s.items[:i], s.items[i+1:]...)
+	}
+	saveErr := s.store.Save(s.items)
+	s.mu.Unlock()
+
+	ctx = withDefaultPriority(ctx, PriorityReport)
+	err = client.writeVariable(ctx, request.DomainID, request.ItemID+"$RptEna", variant.NewBoolVariant(false), nil)
+	if err != nil {
+		return fmt.Errorf("unsubscribe %s: %w", rcb, err)
+	}
+	return saveErr
+}
+
+// Resume включает RCB всех сохранённых подписок на client. Буферизированные RCB
+// продолжают с записи после сохранённого EntryID. Подходит для WithOnConnect.
+// Подписка с Members включается, только если набор данных RCB на сервере
+// не изменился (GetDataSetDirectory), иначе возвращается ErrDataSetChanged:
+// значения отчётов нельзя сопоставить с сохранённым определением, и подписку
+// нужно оформить заново (Subscribe). Ошибка одной подписки не прерывает
+// восстановление остальных; возвращаются все ошибки.
+func (s *Subscriptions) Resume(ctx context.Context, client *MmsClient) error {
+	var errs []error
+	for _, sub := range s.List() {
+		err := checkDataSet(ctx, client, sub)
+		if err == nil {
+			err = client.ConfigureRCB(ctx, sub.RCB, sub.settings())
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("resume %s: %w", sub.RCB, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// checkDataSet сравнивает сохранённое определение набора данных подписки
+// с определением на сервере. Набор данных берётся из Settings.DatSet,
+// без него - из атрибута DatSet блока управления отчётами.
+func checkDataSet(ctx context.Context, client *MmsClient, sub Subscription) error {
+	if sub.Members == nil {
+		return nil
+	}
+	ctx = withDefaultPriority(ctx, PriorityReport)
+	var dataSetRef string
+	if sub.Settings.DatSet != nil {
+		dataSetRef = *sub.Settings.DatSet
+	} else {
+		value, err := client.Read(ctx, sub.RCB+"$DatSet", mms.FCNone)
+		if err != nil {
+			return fmt.Errorf("read DatSet: %w", err)
+		}
+		dataSetRef = value.StringValue()
+	}
+	request, err := client.NewReadRequest(dataSetRef, mms.FCNone)
+	if err != nil {
+		return err
+	}
+	dataSet := mms.ObjectName{DomainID: request.DomainID, ItemID: request.ItemID}
+	directory, err := client.GetDataSetDirectory(ctx, dataSet)
+	if err != nil {
+		return err
+	}
+	if !slices.EqualFunc(directory.Members, sub.Members, func(a, b mms.DataSetMember) bool {
+		return a.String() == b.String()
+	}) {
+		return fmt.Errorf("%w: %s has %d members on server, %d in subscription",
+			ErrDataSetChanged, dataSet, len(directory.Members), len(sub.Members))
+	}
+	return nil
+}
+
+// Track запоминает EntryID отчёта r буферизированного RCB и сохраняет подписки.
+// Вызывается для каждого принятого отчёта после его обработки, чтобы после
+// перезапуска не получить обработанные отчёты повторно.
+func (s *Subscriptions) Track(r *Report) error {
+	if r.EntryID.IsZero() {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.items {
+		if s.items[i].Buffered() && s.items[i].rptID() == r.RptID {
+			if s.items[i].EntryID == r.EntryID {
+				return nil
+			}
+			s.items[i].EntryID = r.EntryID
+			return s.store.Save(s.items)
+		}
+	}
+	return nil
+}
+
+// index возвращает позицию подписки на rcb или -1
+func (s *Subscriptions) index(rcb string) int {
+	for i, sub := range s.items {
+		if sub.RCB == rcb {
+			return i
+		}
+	}
+	return -1
+}
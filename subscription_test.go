@@ -0,0 +1,154 @@
+package go61850
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/slonegd/go61850/mmstest"
+	"github.com/slonegd/go61850/model"
+	"github.com/slonegd/go61850/osi/mms"
+	"github.com/slonegd/go61850/osi/mms/variant"
+	"github.com/stretchr/testify/assert"
+)
+
+// writeExchange возвращает обмен успешной записи value в переменную LD/itemID
+func writeExchange(t *testing.T, itemID string, value *variant.Variant) mmstest.Exchange {
+	pdu, err := (&mms.WriteRequest{InvokeID: 1, DomainID: "LD", ItemID: itemID, Value: value}).Bytes()
+	assert.NoError(t, err)
+	return mmstest.Exchange{Request: mmsRequest(pdu), Responses: []string{rcbWriteOK}}
+}
+
+func TestSubscriptions(t *testing.T) {
+	entryID := model.EntryID{0, 0, 0, 0, 0, 0, 0, 7}
+	rptID := variant.NewVisibleStringVariant("rpt")
+	exchanges := append(fileTranscript(t),
+		// Subscribe
+		writeExchange(t, "LLN0$BR$brcb01$RptEna", variant.NewBoolVariant(false)),
+		writeExchange(t, "LLN0$BR$brcb01$RptID", rptID),
+		writeExchange(t, "LLN0$BR$brcb01$RptEna", variant.NewBoolVariant(true)),
+		// Resume после перезапуска: продолжение с сохранённого EntryID
+		writeExchange(t, "LLN0$BR$brcb01$RptEna", variant.NewBoolVariant(false)),
+		writeExchange(t, "LLN0$BR$brcb01$RptID", rptID),
+		writeExchange(t, "LLN0$BR$brcb01$EntryID", variant.NewOctetStringVariant(entryID.Bytes())),
+		writeExchange(t, "LLN0$BR$brcb01$RptEna", variant.NewBoolVariant(true)),
+		// Unsubscribe
+		writeExchange(t, "LLN0$BR$brcb01$RptEna", variant.NewBoolVariant(false)),
+	)
+	server := mmstest.NewTranscriptServer(t, exchanges...)
+	conn := server.Dial()
+	ctx := context.Background()
+	client, err := NewMmsClient(ctx, conn, WithDefaultDomain("LD"))
+	assert.NoError(t, err)
+	_, err = client.Initiate(ctx)
+	assert.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "subscriptions.json")
+	subscriptions, err := NewSubscriptions(NewFileSubscriptionStore(path))
+	assert.NoError(t, err)
+	assert.Empty(t, subscriptions.List())
+
+	id := "rpt"
+	err = subscriptions.Subscribe(ctx, client, Subscription{RCB: "LLN0.BR.brcb01", Settings: RCBSettings{RptID: &id}})
+	assert.NoError(t, err)
+
+	// Отчёты других RCB и без EntryID не меняют позицию
+	assert.NoError(t, subscriptions.Track(&Report{RptID: "other", EntryID: model.EntryID{1}}))
+	assert.NoError(t, subscriptions.Track(&Report{RptID: "rpt"}))
+	assert.True(t, subscriptions.List()[0].EntryID.IsZero())
+	assert.NoError(t, subscriptions.Track(&Report{RptID: "rpt", EntryID: entryID}))
+
+	// Перезапуск: подписки загружаются из файла
+	restarted, err := NewSubscriptions(NewFileSubscriptionStore(path))
+	assert.NoError(t, err)
+	list := restarted.List()
+	assert.Len(t, list, 1)
+	assert.Equal(t, "LD/LLN0$BR$brcb01", list[0].RCB)
+	assert.Equal(t, entryID, list[0].EntryID)
+	assert.True(t, list[0].Buffered())
+	assert.NoError(t, restarted.Resume(ctx, client))
+
+	assert.NoError(t, restarted.Unsubscribe(ctx, client, "LD/LLN0$BR$brcb01"))
+	assert.Empty(t, restarted.List())
+
+	conn.Close()
+	assert.NoError(t, server.Wait())
+
+	reloaded, err := NewSubscriptions(NewFileSubscriptionStore(path))
+	assert.NoError(t, err)
+	assert.Empty(t, reloaded.List())
+}
+
+func TestFileSubscriptionStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "subscriptions.json")
+	store := NewFileSubscriptionStore(path)
+
+	optFlds := OptSeqNum | OptEntryID
+	saved := []Subscription{{
+		RCB:      "LD/LLN0$BR$brcb01",
+		Settings: RCBSettings{OptFlds: &optFlds, GI: true},
+		EntryID:  model.EntryID{0, 0, 0, 0, 0, 0, 1, 0},
+		Members:  []mms.DataSetMember{{Name: mms.ObjectName{DomainID: "LD", ItemID: "GGIO1$ST$Ind1"}}},
+	}}
+	assert.NoError(t, store.Save(saved))
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"entryID": "0000000000000100"`)
+
+	loaded, err := store.Load()
+	assert.NoError(t, err)
+	assert.Equal(t, saved, loaded)
+
+	assert.NoError(t, os.WriteFile(path, []byte(`{"version": 99}`), 0o644))
+	_, err = store.Load()
+	assert.ErrorContains(t, err, "unsupported version 99")
+}
+
+func TestSubscriptionsResumeDataSet(t *testing.T) {
+	dataSet := mms.ObjectName{DomainID: "LD", ItemID: "LLN0$Events"}
+	ind1 := mms.DataSetMember{Name: mms.ObjectName{DomainID: "LD", ItemID: "GGIO1$ST$Ind1"}}
+	ind2 := mms.DataSetMember{Name: mms.ObjectName{DomainID: "LD", ItemID: "GGIO1$ST$Ind2"}}
+	directoryRequest, err := (&mms.GetNamedVariableListAttributesRequest{InvokeID: 1, DataSet: dataSet}).Bytes()
+	assert.NoError(t, err)
+	directory := func(members ...mms.DataSetMember) mmstest.Exchange {
+		response := &mms.GetNamedVariableListAttributesResponse{InvokeID: 1, Members: members}
+		return mmstest.Exchange{Request: mmsRequest(directoryRequest), Responses: []string{mmsFrame(response.Bytes())}}
+	}
+	datSet := variant.NewVisibleStringVariant("LD/LLN0$Events")
+	exchanges := append(fileTranscript(t),
+		// Набор данных не изменился: RCB включается
+		directory(ind1),
+		writeExchange(t, "LLN0$RP$urcb01$RptEna", variant.NewBoolVariant(false)),
+		writeExchange(t, "LLN0$RP$urcb01$DatSet", datSet),
+		writeExchange(t, "LLN0$RP$urcb01$RptEna", variant.NewBoolVariant(true)),
+		// В набор данных добавлен элемент: RCB не включается
+		directory(ind1, ind2),
+	)
+	server := mmstest.NewTranscriptServer(t, exchanges...)
+	conn := server.Dial()
+	ctx := context.Background()
+	client, err := NewMmsClient(ctx, conn)
+	assert.NoError(t, err)
+	_, err = client.Initiate(ctx)
+	assert.NoError(t, err)
+
+	ref := "LD/LLN0$Events"
+	store := NewFileSubscriptionStore(filepath.Join(t.TempDir(), "subscriptions.json"))
+	assert.NoError(t, store.Save([]Subscription{{
+		RCB:      "LD/LLN0$RP$urcb01",
+		Settings: RCBSettings{DatSet: &ref},
+		Members:  []mms.DataSetMember{ind1},
+	}}))
+	subscriptions, err := NewSubscriptions(store)
+	assert.NoError(t, err)
+
+	assert.NoError(t, subscriptions.Resume(ctx, client))
+	err = subscriptions.Resume(ctx, client)
+	assert.ErrorIs(t, err, ErrDataSetChanged)
+	assert.ErrorContains(t, err, "resume LD/LLN0$RP$urcb01: data set changed: LD/LLN0$Events has 2 members on server, 1 in subscription")
+
+	conn.Close()
+	assert.NoError(t, server.Wait())
+}
@@ -0,0 +1,161 @@
+// Package sv содержит кодирование, публикацию и приём Sampled Values (IEC 61850-9-2)
+// в профиле 9-2LE: 8 каналов (4 тока, 4 напряжения), 80 или 256 выборок на период.
+//
+// Пакет формирует Ethernet кадры; отправка в сеть выполняется FrameWriter
+// (например, raw socket), что позволяет использовать издателя в тестовых
+// стендах без привилегий.
+//
+// Пакет не использует cgo. Платформенный код (RawSocket на AF_PACKET) собирается
+// только для Linux; на прочих платформах OpenRawSocket возвращает
+// ErrRawSocketUnsupported, а кадры пишутся и читаются в формате pcap
+// (PcapWriter, PcapReader).
+package sv
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+
+	"github.com/slonegd/go61850/internal/ber"
+)
+
+// EtherType Sampled Values и VLAN
+const (
+	EtherTypeSV   = 0x88BA
+	EtherTypeVLAN = 0x8100
+)
+
+// Теги savPdu согласно IEC 61850-9-2
+const (
+	tagSavPdu   ber.Tag = 0x60
+	tagNoASDU   ber.Tag = 0x80
+	tagSeqASDU  ber.Tag = 0xA2
+	tagASDU     ber.Tag = 0x30
+	tagSvID     ber.Tag = 0x80
+	tagDatSet   ber.Tag = 0x81
+	tagSmpCnt   ber.Tag = 0x82
+	tagConfRev  ber.Tag = 0x83
+	tagSmpSynch ber.Tag = 0x85
+	tagSmpRate  ber.Tag = 0x86
+	tagSample   ber.Tag = 0x87
+)
+
+// SmpSynch представляет состояние синхронизации источника выборок
+type SmpSynch uint8
+
+const (
+	// SmpSynchNone - нет синхронизации
+	SmpSynchNone SmpSynch = 0
+	// SmpSynchLocal - синхронизация от локального источника
+	SmpSynchLocal SmpSynch = 1
+	// SmpSynchGlobal - синхронизация от глобального источника (например, PTP)
+	SmpSynchGlobal SmpSynch = 2
+)
+
+// String возвращает строковое представление SmpSynch
+func (s SmpSynch) String() string {
+	switch s {
+	case SmpSynchNone:
+		return "none"
+	case SmpSynchLocal:
+		return "local"
+	case SmpSynchGlobal:
+		return "global"
+	default:
+		return fmt.Sprintf("SmpSynch(%d)", uint8(s))
+	}
+}
+
+// Value представляет значение канала 9-2LE: INT32 и качество (32 бита)
+type Value struct {
+	Value   int32
+	Quality uint32
+}
+
+// ASDU представляет один набор выборок (Application Service Data Unit)
+type ASDU struct {
+	SvID string
+	// DatSet - ссылка на набор данных; пустая строка - не передаётся
+	DatSet  string
+	SmpCnt  uint16
+	ConfRev uint32
+	// SmpSynch - состояние синхронизации
+	SmpSynch SmpSynch
+	// SmpRate - частота выборок; 0 - не передаётся (как в 9-2LE)
+	SmpRate uint16
+	Sample  []Value
+}
+
+// VLAN представляет тег IEEE 802.1Q
+type VLAN struct {
+	ID       uint16
+	Priority uint8
+}
+
+// Frame представляет Ethernet кадр Sampled Values
+type Frame struct {
+	Dst net.HardwareAddr
+	Src net.HardwareAddr
+	// VLAN - тег 802.1Q; nil - кадр без тега
+	VLAN  *VLAN
+	AppID uint16
+	ASDUs []ASDU
+}
+
+// Bytes кодирует ASDU:
+// 30 { 80 svID [81 datSet] 82 smpCnt 83 confRev 85 smpSynch [86 smpRate] 87 sample }
+func (a *ASDU) Bytes() []byte {
+	content := wrapTL(tagSvID, []byte(a.SvID))
+	if a.DatSet != "" {
+		content = append(content, wrapTL(tagDatSet, []byte(a.DatSet))...)
+	}
+	content = append(content, wrapTL(tagSmpCnt, binary.BigEndian.AppendUint16(nil, a.SmpCnt))...)
+	content = append(content, wrapTL(tagConfRev, binary.BigEndian.AppendUint32(nil, a.ConfRev))...)
+	content = append(content, wrapTL(tagSmpSynch, []byte{byte(a.SmpSynch)})...)
+	if a.SmpRate != 0 {
+		content = append(content, wrapTL(tagSmpRate, binary.BigEndian.AppendUint16(nil, a.SmpRate))...)
+	}
+
+	sample := make([]byte, 0, 8*len(a.Sample))
+	for _, v := range a.Sample {
+		sample = binary.BigEndian.AppendUint32(sample, uint32(v.Value))
+		sample = binary.BigEndian.AppendUint32(sample, v.Quality)
+	}
+	content = append(content, wrapTL(tagSample, sample)...)
+
+	return wrapTL(tagASDU, content)
+}
+
+// Bytes кодирует кадр: заголовок Ethernet, [802.1Q], 88ba, APPID, Length,
+// Reserved1, Reserved2 и savPdu
+func (f *Frame) Bytes() []byte {
+	var asdus []byte
+	for i := range f.ASDUs {
+		asdus = append(asdus, f.ASDUs[i].Bytes()...)
+	}
+	savPdu := wrapTL(tagNoASDU, []byte{byte(len(f.ASDUs))})
+	savPdu = append(savPdu, wrapTL(tagSeqASDU, asdus)...)
+	savPdu = wrapTL(tagSavPdu, savPdu)
+
+	frame := make([]byte, 0, 26+len(savPdu))
+	frame = append(frame, f.Dst...)
+	frame = append(frame, f.Src...)
+	if f.VLAN != nil {
+		frame = binary.BigEndian.AppendUint16(frame, EtherTypeVLAN)
+		frame = binary.BigEndian.AppendUint16(frame, uint16(f.VLAN.Priority)<<13|f.VLAN.ID&0x0fff)
+	}
+	frame = binary.BigEndian.AppendUint16(frame, EtherTypeSV)
+	frame = binary.BigEndian.AppendUint16(frame, f.AppID)
+	// Length включает APPID, Length, Reserved1, Reserved2 (8 байт) и savPdu
+	frame = binary.BigEndian.AppendUint16(frame, uint16(8+len(savPdu)))
+	frame = append(frame, 0, 0, 0, 0)
+	return append(frame, savPdu...)
+}
+
+// wrapTL оборачивает content в BER тег и длину
+func wrapTL(tag ber.Tag, content []byte) []byte {
+	buffer := make([]byte, 1+ber.DetermineLengthSize(uint32(len(content)))+len(content))
+	bufPos := ber.EncodeTL(tag, uint32(len(content)), buffer, 0)
+	copy(buffer[bufPos:], content)
+	return buffer
+}
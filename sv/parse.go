@@ -0,0 +1,157 @@
+package sv
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+
+	"github.com/slonegd/go61850/internal/ber"
+)
+
+// ParseFrame разбирает Ethernet кадр Sampled Values (обратная операция к Frame.Bytes)
+func ParseFrame(buffer []byte) (*Frame, error) {
+	if len(buffer) < 14 {
+		return nil, fmt.Errorf("frame too short: %d bytes", len(buffer))
+	}
+	frame := &Frame{
+		Dst: net.HardwareAddr(append([]byte{}, buffer[0:6]...)),
+		Src: net.HardwareAddr(append([]byte{}, buffer[6:12]...)),
+	}
+
+	bufPos := 12
+	etherType := binary.BigEndian.Uint16(buffer[bufPos:])
+	if etherType == EtherTypeVLAN {
+		if len(buffer) < 18 {
+			return nil, fmt.Errorf("frame too short for VLAN tag: %d bytes", len(buffer))
+		}
+		tci := binary.BigEndian.Uint16(buffer[bufPos+2:])
+		frame.VLAN = &VLAN{ID: tci & 0x0fff, Priority: uint8(tci >> 13)}
+		bufPos += 4
+		etherType = binary.BigEndian.Uint16(buffer[bufPos:])
+	}
+	if etherType != EtherTypeSV {
+		return nil, fmt.Errorf("unexpected EtherType 0x%04x", etherType)
+	}
+	bufPos += 2
+
+	if len(buffer) < bufPos+8 {
+		return nil, fmt.Errorf("frame too short for SV header: %d bytes", len(buffer))
+	}
+	frame.AppID = binary.BigEndian.Uint16(buffer[bufPos:])
+	length := int(binary.BigEndian.Uint16(buffer[bufPos+2:]))
+	if length < 8 || bufPos+length > len(buffer) {
+		return nil, fmt.Errorf("invalid SV length %d", length)
+	}
+	apdu := buffer[bufPos+8 : bufPos+length]
+
+	savPdu, err := expectTLV(apdu, byte(tagSavPdu), "savPdu")
+	if err != nil {
+		return nil, err
+	}
+	for pos := 0; pos < len(savPdu); {
+		tag, value, next, err := decodeTLV(savPdu, pos)
+		if err != nil {
+			return nil, err
+		}
+		if tag == byte(tagSeqASDU) {
+			for asduPos := 0; asduPos < len(value); {
+				asduTag, asdu, asduNext, err := decodeTLV(value, asduPos)
+				if err != nil {
+					return nil, err
+				}
+				if asduTag != byte(tagASDU) {
+					return nil, fmt.Errorf("unexpected tag in seqASDU: 0x%02x", asduTag)
+				}
+				parsed, err := parseASDU(asdu)
+				if err != nil {
+					return nil, err
+				}
+				frame.ASDUs = append(frame.ASDUs, *parsed)
+				asduPos = asduNext
+			}
+		}
+		// noASDU и security не нужны: число ASDU определяется по seqASDU
+		pos = next
+	}
+
+	return frame, nil
+}
+
+// parseASDU разбирает содержимое ASDU
+func parseASDU(buffer []byte) (*ASDU, error) {
+	asdu := &ASDU{}
+	for pos := 0; pos < len(buffer); {
+		tag, value, next, err := decodeTLV(buffer, pos)
+		if err != nil {
+			return nil, err
+		}
+		switch ber.Tag(tag) {
+		case tagSvID:
+			asdu.SvID = string(value)
+		case tagDatSet:
+			asdu.DatSet = string(value)
+		case tagSmpCnt:
+			if len(value) != 2 {
+				return nil, fmt.Errorf("invalid smpCnt length: %d", len(value))
+			}
+			asdu.SmpCnt = binary.BigEndian.Uint16(value)
+		case tagConfRev:
+			if len(value) != 4 {
+				return nil, fmt.Errorf("invalid confRev length: %d", len(value))
+			}
+			asdu.ConfRev = binary.BigEndian.Uint32(value)
+		case tagSmpSynch:
+			if len(value) != 1 {
+				return nil, fmt.Errorf("invalid smpSynch length: %d", len(value))
+			}
+			asdu.SmpSynch = SmpSynch(value[0])
+		case tagSmpRate:
+			if len(value) != 2 {
+				return nil, fmt.Errorf("invalid smpRate length: %d", len(value))
+			}
+			asdu.SmpRate = binary.BigEndian.Uint16(value)
+		case tagSample:
+			if len(value)%8 != 0 {
+				return nil, fmt.Errorf("invalid sample length: %d", len(value))
+			}
+			asdu.Sample = make([]Value, len(value)/8)
+			for i := range asdu.Sample {
+				asdu.Sample[i] = Value{
+					Value:   int32(binary.BigEndian.Uint32(value[i*8:])),
+					Quality: binary.BigEndian.Uint32(value[i*8+4:]),
+				}
+			}
+		}
+		// refrTm, smpMod и другие необязательные поля пропускаются
+		pos = next
+	}
+	return asdu, nil
+}
+
+// decodeTLV читает один BER элемент, начиная с bufPos
+func decodeTLV(buffer []byte, bufPos int) (tag byte, content []byte, next int, err error) {
+	if bufPos >= len(buffer) {
+		return 0, nil, 0, fmt.Errorf("unexpected end of buffer at %d", bufPos)
+	}
+	tag = buffer[bufPos]
+	newPos, length, err := ber.DecodeLength(buffer, bufPos+1, len(buffer))
+	if err != nil {
+		return 0, nil, 0, fmt.Errorf("failed to decode length for tag 0x%02x: %w", tag, err)
+	}
+	if newPos+length > len(buffer) {
+		return 0, nil, 0, fmt.Errorf("invalid length for tag 0x%02x: exceeds buffer size", tag)
+	}
+	return tag, buffer[newPos : newPos+length], newPos + length, nil
+}
+
+// expectTLV читает один BER элемент и проверяет его тег
+func expectTLV(buffer []byte, expected byte, what string) ([]byte, error) {
+	tag, content, _, err := decodeTLV(buffer, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", what, err)
+	}
+	if tag != expected {
+		return nil, fmt.Errorf("invalid tag for %s: expected 0x%02x, got 0x%02x", what, expected, tag)
+	}
+	return content, nil
+}
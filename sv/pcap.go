@@ -0,0 +1,131 @@
+package sv
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// FrameReader принимает Ethernet кадры вместе с моментом приёма
+// (raw socket или запись в формате pcap)
+type FrameReader interface {
+	// ReadFrame возвращает следующий кадр; io.EOF - кадров больше нет
+	ReadFrame() (frame []byte, arrival time.Time, err error)
+}
+
+// Формат pcap (libpcap): заголовок файла и записи кадров
+const (
+	pcapMagic         = 0xa1b2c3d4 // метки времени в микросекундах
+	pcapMagicNano     = 0xa1b23c4d // метки времени в наносекундах
+	pcapLinkEthernet  = 1
+	pcapSnapLen       = 65535
+	pcapHeaderLen     = 24
+	pcapRecordHdrLen  = 16
+	pcapVersionMajor  = 2
+	pcapVersionMinor  = 4
+	pcapMaxRecordSize = 1 << 18
+)
+
+// ErrRawSocketUnsupported возвращается OpenRawSocket на платформах без AF_PACKET
+var ErrRawSocketUnsupported = errors.New("raw socket is not supported on this platform")
+
+// ErrPcapFormat возвращается для файлов, не являющихся записью Ethernet кадров в формате pcap
+var ErrPcapFormat = errors.New("invalid pcap file")
+
+// PcapWriter записывает кадры в формате pcap (Ethernet). Реализует FrameWriter,
+// что позволяет сохранять поток издателя в файл для Wireshark или
+// воспроизведения через PcapReader без raw socket.
+type PcapWriter struct {
+	w   io.Writer
+	now func() time.Time
+}
+
+// NewPcapWriter записывает заголовок pcap в w и возвращает PcapWriter
+func NewPcapWriter(w io.Writer) (*PcapWriter, error) {
+	header := make([]byte, pcapHeaderLen)
+	binary.LittleEndian.PutUint32(header[0:], pcapMagic)
+	binary.LittleEndian.PutUint16(header[4:], pcapVersionMajor)
+	binary.LittleEndian.PutUint16(header[6:], pcapVersionMinor)
+	binary.LittleEndian.PutUint32(header[16:], pcapSnapLen)
+	binary.LittleEndian.PutUint32(header[20:], pcapLinkEthernet)
+	if _, err := w.Write(header); err != nil {
+		return nil, err
+	}
+	return &PcapWriter{w: w, now: time.Now}, nil
+}
+
+// WriteFrame реализует FrameWriter: записывает кадр с текущим временем
+func (p *PcapWriter) WriteFrame(frame []byte) error {
+	return p.WriteFrameAt(frame, p.now())
+}
+
+// WriteFrameAt записывает кадр с меткой времени t
+func (p *PcapWriter) WriteFrameAt(frame []byte, t time.Time) error {
+	record := make([]byte, pcapRecordHdrLen, pcapRecordHdrLen+len(frame))
+	binary.LittleEndian.PutUint32(record[0:], uint32(t.Unix()))
+	binary.LittleEndian.PutUint32(record[4:], uint32(t.Nanosecond()/1000))
+	binary.LittleEndian.PutUint32(record[8:], uint32(len(frame)))
+	binary.LittleEndian.PutUint32(record[12:], uint32(len(frame)))
+	_, err := p.w.Write(append(record, frame...))
+	return err
+}
+
+// PcapReader читает кадры из записи в формате pcap (Ethernet) - замена raw socket
+// на платформах без него и в тестах. Поддерживаются оба порядка байт
+// и метки времени в микро- и наносекундах.
+type PcapReader struct {
+	r     io.Reader
+	order binary.ByteOrder
+	nano  bool
+}
+
+// NewPcapReader читает заголовок pcap из r
+func NewPcapReader(r io.Reader) (*PcapReader, error) {
+	header := make([]byte, pcapHeaderLen)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPcapFormat, err)
+	}
+	p := &PcapReader{r: r}
+	for _, order := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+		switch order.Uint32(header) {
+		case pcapMagic:
+			p.order = order
+		case pcapMagicNano:
+			p.order, p.nano = order, true
+		}
+	}
+	if p.order == nil {
+		return nil, fmt.Errorf("%w: magic %x", ErrPcapFormat, header[:4])
+	}
+	if link := p.order.Uint32(header[20:]); link != pcapLinkEthernet {
+		return nil, fmt.Errorf("%w: link type %d, want Ethernet", ErrPcapFormat, link)
+	}
+	return p, nil
+}
+
+// ReadFrame реализует FrameReader
+func (p *PcapReader) ReadFrame() ([]byte, time.Time, error) {
+	record := make([]byte, pcapRecordHdrLen)
+	if _, err := io.ReadFull(p.r, record); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			err = fmt.Errorf("%w: truncated record header", ErrPcapFormat)
+		}
+		return nil, time.Time{}, err
+	}
+	sec := int64(p.order.Uint32(record[0:]))
+	frac := int64(p.order.Uint32(record[4:]))
+	if !p.nano {
+		frac *= 1000
+	}
+	length := p.order.Uint32(record[8:])
+	if length > pcapMaxRecordSize {
+		return nil, time.Time{}, fmt.Errorf("%w: record length %d", ErrPcapFormat, length)
+	}
+	frame := make([]byte, length)
+	if _, err := io.ReadFull(p.r, frame); err != nil {
+		return nil, time.Time{}, fmt.Errorf("%w: truncated record", ErrPcapFormat)
+	}
+	return frame, time.Unix(sec, frac), nil
+}
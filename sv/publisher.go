@@ -0,0 +1,218 @@
+package sv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"sync/atomic"
+	"time"
+)
+
+// Source - источник значений канала (форма сигнала).
+// t - время выборки от начала публикации.
+type Source interface {
+	Sample(t time.Duration) Value
+}
+
+// SourceFunc позволяет использовать функцию как Source
+type SourceFunc func(t time.Duration) Value
+
+// Sample реализует Source
+func (f SourceFunc) Sample(t time.Duration) Value { return f(t) }
+
+// Sine - синусоидальный сигнал. Amplitude задаётся в единицах 9-2LE:
+// 1 мА для токов и 10 мВ для напряжений.
+type Sine struct {
+	Amplitude float64
+	Frequency float64
+	// Phase - начальная фаза в радианах
+	Phase float64
+}
+
+// Sample реализует Source
+func (s Sine) Sample(t time.Duration) Value {
+	return Value{Value: int32(math.Round(s.Amplitude * math.Sin(2*math.Pi*s.Frequency*t.Seconds()+s.Phase)))}
+}
+
+// Constant - постоянное значение канала
+type Constant Value
+
+// Sample реализует Source
+func (c Constant) Sample(time.Duration) Value { return Value(c) }
+
+// FrameWriter отправляет закодированный кадр (например, через raw socket)
+type FrameWriter interface {
+	WriteFrame(frame []byte) error
+}
+
+// Clock - источник монотонного времени планировщика
+type Clock interface {
+	Now() time.Time
+	// SleepUntil ждёт наступления момента t или отмены ctx
+	SleepUntil(ctx context.Context, t time.Time) error
+}
+
+// systemClock - Clock на основе монотонного времени time.Now
+type systemClock struct{}
+
+// Now реализует Clock
+func (systemClock) Now() time.Time { return time.Now() }
+
+// SleepUntil реализует Clock
+func (systemClock) SleepUntil(ctx context.Context, t time.Time) error {
+	d := time.Until(t)
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Config описывает поток Sampled Values
+type Config struct {
+	Dst   net.HardwareAddr
+	Src   net.HardwareAddr
+	VLAN  *VLAN
+	AppID uint16
+
+	SvID    string
+	DatSet  string
+	ConfRev uint32
+	// SmpSynch - начальное состояние синхронизации (см. Publisher.SetSmpSynch)
+	SmpSynch SmpSynch
+
+	// Frequency - номинальная частота сети, Гц (50 или 60)
+	Frequency float64
+	// SamplesPerCycle - число выборок на период: 80 или 256
+	SamplesPerCycle int
+	// ASDUsPerFrame - число ASDU в кадре; 0 - по 9-2LE: 1 для 80 и 8 для 256
+	ASDUsPerFrame int
+
+	// Channels - источники значений каналов (8 для 9-2LE)
+	Channels []Source
+}
+
+// Option представляет опцию Publisher
+type Option func(*Publisher)
+
+// WithClock задаёт источник времени планировщика (например, для тестов)
+func WithClock(c Clock) Option {
+	return func(p *Publisher) {
+		p.clock = c
+	}
+}
+
+// Publisher публикует поток Sampled Values с заданной частотой выборок.
+// Момент каждой выборки вычисляется от начала публикации, поэтому
+// погрешность таймера не накапливается.
+type Publisher struct {
+	config   Config
+	writer   FrameWriter
+	clock    Clock
+	smpSynch atomic.Uint32
+	// sampleRate - выборок в секунду; smpCnt считает по модулю sampleRate
+	sampleRate int
+}
+
+// NewPublisher проверяет конфигурацию и создаёт Publisher
+func NewPublisher(config Config, writer FrameWriter, opts ...Option) (*Publisher, error) {
+	if config.SamplesPerCycle != 80 && config.SamplesPerCycle != 256 {
+		return nil, fmt.Errorf("unsupported samples per cycle: %d, expected 80 or 256", config.SamplesPerCycle)
+	}
+	if config.Frequency <= 0 {
+		return nil, fmt.Errorf("invalid frequency: %g", config.Frequency)
+	}
+	if len(config.Channels) == 0 {
+		return nil, errors.New("no channels configured")
+	}
+	if config.ASDUsPerFrame == 0 {
+		config.ASDUsPerFrame = 1
+		if config.SamplesPerCycle == 256 {
+			config.ASDUsPerFrame = 8
+		}
+	}
+
+	sampleRate := config.Frequency * float64(config.SamplesPerCycle)
+	if sampleRate != math.Trunc(sampleRate) || sampleRate > math.MaxUint16 {
+		return nil, fmt.Errorf("invalid sample rate: %g", sampleRate)
+	}
+
+	p := &Publisher{
+		config:     config,
+		writer:     writer,
+		clock:      systemClock{},
+		sampleRate: int(sampleRate),
+	}
+	p.smpSynch.Store(uint32(config.SmpSynch))
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p, nil
+}
+
+// SetSmpSynch изменяет состояние синхронизации в следующих кадрах
+// (например, при потере и восстановлении PTP)
+func (p *Publisher) SetSmpSynch(s SmpSynch) {
+	p.smpSynch.Store(uint32(s))
+}
+
+// sampleTime возвращает время выборки n от начала публикации. Целые секунды
+// и остаток считаются отдельно: n*time.Second переполняет int64 после 9.2e9
+// выборок - через 7 суток публикации при 15360 выборках в секунду.
+func (p *Publisher) sampleTime(n int64) time.Duration {
+	rate := int64(p.sampleRate)
+	return time.Duration(n/rate)*time.Second + time.Duration(n%rate)*time.Second/time.Duration(rate)
+}
+
+// frame собирает кадр из ASDUsPerFrame выборок, начиная с выборки n
+func (p *Publisher) frame(n int64) *Frame {
+	frame := &Frame{
+		Dst:   p.config.Dst,
+		Src:   p.config.Src,
+		VLAN:  p.config.VLAN,
+		AppID: p.config.AppID,
+		ASDUs: make([]ASDU, p.config.ASDUsPerFrame),
+	}
+	smpSynch := SmpSynch(p.smpSynch.Load())
+	for i := range frame.ASDUs {
+		t := p.sampleTime(n + int64(i))
+		sample := make([]Value, len(p.config.Channels))
+		for c, source := range p.config.Channels {
+			sample[c] = source.Sample(t)
+		}
+		frame.ASDUs[i] = ASDU{
+			SvID:     p.config.SvID,
+			DatSet:   p.config.DatSet,
+			SmpCnt:   uint16((n + int64(i)) % int64(p.sampleRate)),
+			ConfRev:  p.config.ConfRev,
+			SmpSynch: smpSynch,
+			Sample:   sample,
+		}
+	}
+	return frame
+}
+
+// Run публикует поток до отмены ctx или ошибки отправки.
+// Кадр отправляется в момент последней выборки в нём; при отставании
+// кадры отправляются без ожидания, выборки не пропускаются.
+func (p *Publisher) Run(ctx context.Context) error {
+	start := p.clock.Now()
+	asdus := int64(p.config.ASDUsPerFrame)
+	for n := int64(0); ; n += asdus {
+		deadline := start.Add(p.sampleTime(n + asdus - 1))
+		if err := p.clock.SleepUntil(ctx, deadline); err != nil {
+			return err
+		}
+		if err := p.writer.WriteFrame(p.frame(n).Bytes()); err != nil {
+			return fmt.Errorf("failed to write SV frame: %w", err)
+		}
+	}
+}
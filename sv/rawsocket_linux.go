@@ -0,0 +1,75 @@
+//go:build linux
+
+package sv
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"syscall"
+	"time"
+)
+
+// RawSocket передаёт и принимает кадры Sampled Values через сокет AF_PACKET.
+// Требует CAP_NET_RAW. Доступен только в Linux; на прочих платформах
+// OpenRawSocket возвращает ErrRawSocketUnsupported, а для приёма
+// используется PcapReader.
+//
+// Сокет обслуживается планировщиком ввода-вывода Go: ожидание кадра
+// не занимает поток ОС, поддерживается SetReadDeadline, а Close прерывает
+// ReadFrame, ожидающий в другой горутине.
+type RawSocket struct {
+	file *os.File
+	buf  []byte
+}
+
+// htons переводит EtherType в сетевой порядок байт для AF_PACKET
+func htons(v uint16) uint16 { return v<<8 | v>>8 }
+
+// OpenRawSocket открывает raw socket на интерфейсе name,
+// принимающий кадры с EtherType Sampled Values
+func OpenRawSocket(name string) (*RawSocket, error) {
+	ifi, err := net.InterfaceByName(name)
+	if err != nil {
+		return nil, err
+	}
+	protocol := htons(EtherTypeSV)
+	// Неблокирующий дескриптор os.NewFile регистрирует в планировщике
+	fd, err := syscall.Socket(syscall.AF_PACKET, syscall.SOCK_RAW|syscall.SOCK_NONBLOCK|syscall.SOCK_CLOEXEC, int(protocol))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open raw socket: %w", err)
+	}
+	if err := syscall.Bind(fd, &syscall.SockaddrLinklayer{Protocol: protocol, Ifindex: ifi.Index}); err != nil {
+		syscall.Close(fd)
+		return nil, fmt.Errorf("failed to bind raw socket to %s: %w", name, err)
+	}
+	return &RawSocket{file: os.NewFile(uintptr(fd), "sv:"+name), buf: make([]byte, pcapSnapLen)}, nil
+}
+
+// WriteFrame реализует FrameWriter
+func (s *RawSocket) WriteFrame(frame []byte) error {
+	_, err := s.file.Write(frame)
+	return err
+}
+
+// ReadFrame реализует FrameReader; блокируется до приёма кадра, истечения
+// срока SetReadDeadline (os.ErrDeadlineExceeded) или Close (os.ErrClosed)
+func (s *RawSocket) ReadFrame() ([]byte, time.Time, error) {
+	n, err := s.file.Read(s.buf)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	frame := make([]byte, n)
+	copy(frame, s.buf[:n])
+	return frame, time.Now(), nil
+}
+
+// SetReadDeadline задаёт срок ожидания ReadFrame; нулевое время снимает ограничение
+func (s *RawSocket) SetReadDeadline(t time.Time) error {
+	return s.file.SetReadDeadline(t)
+}
+
+// Close закрывает сокет и прерывает ожидающий ReadFrame
+func (s *RawSocket) Close() error {
+	return s.file.Close()
+}
@@ -0,0 +1,37 @@
+package sv
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRawSocketDeadlineAndClose(t *testing.T) {
+	s, err := OpenRawSocket("lo")
+	if err != nil {
+		t.Skipf("raw socket unavailable: %v", err)
+	}
+
+	assert.NoError(t, s.SetReadDeadline(time.Now().Add(20*time.Millisecond)))
+	_, _, err = s.ReadFrame()
+	assert.ErrorIs(t, err, os.ErrDeadlineExceeded)
+
+	// Close прерывает ожидающий ReadFrame
+	assert.NoError(t, s.SetReadDeadline(time.Time{}))
+	done := make(chan error, 1)
+	go func() {
+		_, _, err := s.ReadFrame()
+		done <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+	assert.NoError(t, s.Close())
+	select {
+	case err := <-done:
+		assert.True(t, errors.Is(err, os.ErrClosed), "ReadFrame error = %v", err)
+	case <-time.After(time.Second):
+		t.Fatal("ReadFrame not unblocked by Close")
+	}
+}
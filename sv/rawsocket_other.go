@@ -0,0 +1,28 @@
+//go:build !linux
+
+package sv
+
+import "time"
+
+// RawSocket на этой платформе не поддерживается: OpenRawSocket всегда
+// возвращает ErrRawSocketUnsupported. Для приёма используется PcapReader.
+type RawSocket struct{}
+
+// OpenRawSocket возвращает ErrRawSocketUnsupported
+func OpenRawSocket(name string) (*RawSocket, error) {
+	return nil, ErrRawSocketUnsupported
+}
+
+// WriteFrame реализует FrameWriter
+func (s *RawSocket) WriteFrame(frame []byte) error { return ErrRawSocketUnsupported }
+
+// ReadFrame реализует FrameReader
+func (s *RawSocket) ReadFrame() ([]byte, time.Time, error) {
+	return nil, time.Time{}, ErrRawSocketUnsupported
+}
+
+// SetReadDeadline возвращает ErrRawSocketUnsupported
+func (s *RawSocket) SetReadDeadline(t time.Time) error { return ErrRawSocketUnsupported }
+
+// Close реализует io.Closer
+func (s *RawSocket) Close() error { return nil }
@@ -0,0 +1,184 @@
+package sv
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// StreamStats - статистика потока Sampled Values для мониторинга merging unit
+type StreamStats struct {
+	SvID  string
+	AppID uint16
+	// Frames - принятые кадры, Samples - принятые ASDU
+	Frames  uint64
+	Samples uint64
+	// Lost - пропущенные выборки по разрывам smpCnt
+	Lost uint64
+	// OutOfOrder - выборки со smpCnt из прошлого (повтор или перестановка кадров)
+	OutOfOrder uint64
+	// MinInterval и MaxInterval - границы интервала между кадрами
+	MinInterval time.Duration
+	MaxInterval time.Duration
+	// Jitter - сглаженное изменение интервала между кадрами (как в RFC 3550)
+	Jitter time.Duration
+	// First и Last - время приёма первого и последнего кадра
+	First time.Time
+	Last  time.Time
+
+	lastSmpCnt   uint16
+	lastInterval time.Duration
+	// firstSamples - число ASDU первого кадра, не входящих в измеренный интервал
+	firstSamples uint64
+}
+
+// Rate возвращает измеренную частоту выборок (выборок в секунду) по времени приёма.
+// Для 80 выборок на период 50 Гц ожидается 4000; отклонение показывает
+// рассинхронизацию merging unit. 0, если принят только один кадр.
+func (s StreamStats) Rate() float64 {
+	elapsed := s.Last.Sub(s.First).Seconds()
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(s.Samples+s.Lost-s.firstSamples) / elapsed
+}
+
+// SubscriberOption представляет опцию Subscriber
+type SubscriberOption func(*Subscriber)
+
+// WithSampleRate задаёт частоту выборок потоков (выборок в секунду), по которой
+// smpCnt переходит через 0. Если не задана и ASDU не содержит smpRate,
+// пропуски перед переходом через 0 не учитываются.
+func WithSampleRate(rate int) SubscriberOption {
+	return func(s *Subscriber) {
+		s.sampleRate = rate
+	}
+}
+
+// Subscriber разбирает кадры Sampled Values и ведёт статистику по потокам (svID)
+type Subscriber struct {
+	mu         sync.Mutex
+	sampleRate int
+	streams    map[string]*StreamStats
+}
+
+// NewSubscriber создаёт Subscriber
+func NewSubscriber(opts ...SubscriberOption) *Subscriber {
+	s := &Subscriber{streams: make(map[string]*StreamStats)}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Handle разбирает кадр, принятый в момент arrival, обновляет статистику потока
+// и возвращает разобранный кадр
+func (s *Subscriber) Handle(buffer []byte, arrival time.Time) (*Frame, error) {
+	frame, err := ParseFrame(buffer)
+	if err != nil {
+		return nil, err
+	}
+	if len(frame.ASDUs) == 0 {
+		return frame, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	svID := frame.ASDUs[0].SvID
+	stream, ok := s.streams[svID]
+	if !ok {
+		stream = &StreamStats{SvID: svID, AppID: frame.AppID, First: arrival, firstSamples: uint64(len(frame.ASDUs))}
+		s.streams[svID] = stream
+	} else {
+		s.updateInterval(stream, arrival.Sub(stream.Last))
+	}
+	stream.Frames++
+	stream.Last = arrival
+
+	for i, asdu := range frame.ASDUs {
+		stream.Samples++
+		if (ok || i > 0) && !s.updateSmpCnt(stream, asdu) {
+			continue
+		}
+		stream.lastSmpCnt = asdu.SmpCnt
+	}
+	return frame, nil
+}
+
+// updateInterval учитывает интервал между кадрами
+func (s *Subscriber) updateInterval(stream *StreamStats, interval time.Duration) {
+	if stream.Frames == 1 || interval < stream.MinInterval {
+		stream.MinInterval = interval
+	}
+	if interval > stream.MaxInterval {
+		stream.MaxInterval = interval
+	}
+	if stream.Frames > 1 {
+		d := interval - stream.lastInterval
+		if d < 0 {
+			d = -d
+		}
+		stream.Jitter += (d - stream.Jitter) / 16
+	}
+	stream.lastInterval = interval
+}
+
+// updateSmpCnt учитывает разрыв smpCnt относительно предыдущей выборки.
+// Возвращает false для выборки из прошлого.
+func (s *Subscriber) updateSmpCnt(stream *StreamStats, asdu ASDU) bool {
+	rate := s.sampleRate
+	if asdu.SmpRate != 0 {
+		rate = int(asdu.SmpRate)
+	}
+
+	expected := int(stream.lastSmpCnt) + 1
+	smpCnt := int(asdu.SmpCnt)
+	if rate <= 0 {
+		if smpCnt >= expected {
+			stream.Lost += uint64(smpCnt - expected)
+		} else {
+			// Переход через 0: пропуски перед ним неизвестны
+			stream.Lost += uint64(smpCnt)
+		}
+		return true
+	}
+
+	gap := ((smpCnt-expected)%rate + rate) % rate
+	if gap > rate/2 {
+		stream.OutOfOrder++
+		return false
+	}
+	stream.Lost += uint64(gap)
+	return true
+}
+
+// Stream возвращает статистику потока svID
+func (s *Subscriber) Stream(svID string) (StreamStats, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stream, ok := s.streams[svID]
+	if !ok {
+		return StreamStats{}, false
+	}
+	return *stream, true
+}
+
+// Streams возвращает статистику всех потоков, упорядоченную по svID
+func (s *Subscriber) Streams() []StreamStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	streams := make([]StreamStats, 0, len(s.streams))
+	for _, stream := range s.streams {
+		streams = append(streams, *stream)
+	}
+	sort.Slice(streams, func(i, j int) bool { return streams[i].SvID < streams[j].SvID })
+	return streams
+}
+
+// Reset сбрасывает статистику всех потоков
+func (s *Subscriber) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.streams = make(map[string]*StreamStats)
+}
@@ -0,0 +1,237 @@
+package sv
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func parseHex(s string) []byte {
+	b, err := hex.DecodeString(strings.ReplaceAll(s, " ", ""))
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func TestFrameBytes(t *testing.T) {
+	frame := &Frame{
+		Dst:   net.HardwareAddr{0x01, 0x0c, 0xcd, 0x04, 0x00, 0x01},
+		Src:   net.HardwareAddr{0x00, 0x00, 0x00, 0x00, 0x00, 0x01},
+		AppID: 0x4000,
+		ASDUs: []ASDU{{SvID: "MU01", SmpCnt: 1, ConfRev: 1, SmpSynch: SmpSynchGlobal, Sample: []Value{{Value: 1}}}},
+	}
+	assert.Equal(t, parseHex("010ccd040001 000000000001 88ba 4000 002e 00000000"+
+		"6024 800101 a21f 301d 80044d553031 82020001 830400000001 850102 87080000000100000000"), frame.Bytes())
+
+	frame.VLAN = &VLAN{ID: 5, Priority: 4}
+	assert.Equal(t, parseHex("8100 8005 88ba"), frame.Bytes()[12:18])
+}
+
+// fakeClock сдвигает время до запрошенного момента без ожидания
+type fakeClock struct {
+	now       time.Time
+	deadlines []time.Duration
+	start     time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func (c *fakeClock) SleepUntil(ctx context.Context, t time.Time) error {
+	c.deadlines = append(c.deadlines, t.Sub(c.start))
+	c.now = t
+	return ctx.Err()
+}
+
+type frameRecorder struct {
+	frames [][]byte
+	limit  int
+}
+
+var errEnough = errors.New("enough")
+
+func (r *frameRecorder) WriteFrame(frame []byte) error {
+	r.frames = append(r.frames, frame)
+	if len(r.frames) == r.limit {
+		return errEnough
+	}
+	return nil
+}
+
+func TestPublisher(t *testing.T) {
+	start := time.Unix(1000, 0)
+	clock := &fakeClock{now: start, start: start}
+	recorder := &frameRecorder{limit: 3}
+	channels := make([]Source, 8)
+	for i := range channels {
+		channels[i] = Constant{Value: int32(i)}
+	}
+	channels[0] = Sine{Amplitude: 1000, Frequency: 50}
+
+	p, err := NewPublisher(Config{
+		AppID:           0x4000,
+		SvID:            "MU01",
+		ConfRev:         1,
+		Frequency:       60,
+		SamplesPerCycle: 256,
+		Channels:        channels,
+	}, recorder, WithClock(clock))
+	assert.NoError(t, err)
+	p.SetSmpSynch(SmpSynchLocal)
+
+	err = p.Run(context.Background())
+	assert.ErrorIs(t, err, errEnough)
+	assert.Len(t, recorder.frames, 3)
+
+	// 60 Гц * 256 = 15360 выборок/с, 8 ASDU в кадре: кадр отправляется в момент последней выборки
+	assert.Equal(t, []time.Duration{
+		time.Duration(7 * int64(time.Second) / 15360),
+		time.Duration(15 * int64(time.Second) / 15360),
+		time.Duration(23 * int64(time.Second) / 15360),
+	}, clock.deadlines)
+
+	// Время выборки не переполняется при длительной публикации: 30 суток и полсекунды
+	n := int64(30*24*60*60)*15360 + 7680
+	assert.Equal(t, 30*24*time.Hour+500*time.Millisecond, p.sampleTime(n))
+
+	frame := p.frame(15359)
+	assert.Len(t, frame.ASDUs, 8)
+	assert.Equal(t, uint16(15359), frame.ASDUs[0].SmpCnt)
+	assert.Equal(t, uint16(0), frame.ASDUs[1].SmpCnt)
+	assert.Equal(t, SmpSynchLocal, frame.ASDUs[0].SmpSynch)
+	assert.Equal(t, Value{Value: 3}, frame.ASDUs[0].Sample[3])
+
+	_, err = NewPublisher(Config{Frequency: 50, SamplesPerCycle: 100, Channels: channels}, recorder)
+	assert.EqualError(t, err, "unsupported samples per cycle: 100, expected 80 or 256")
+}
+
+func TestSine(t *testing.T) {
+	s := Sine{Amplitude: 1000, Frequency: 50}
+	assert.Equal(t, Value{Value: 0}, s.Sample(0))
+	assert.Equal(t, Value{Value: 1000}, s.Sample(5*time.Millisecond))
+	assert.Equal(t, Value{Value: -1000}, s.Sample(15*time.Millisecond))
+}
+
+func TestParseFrame(t *testing.T) {
+	frame := &Frame{
+		Dst:   net.HardwareAddr{0x01, 0x0c, 0xcd, 0x04, 0x00, 0x01},
+		Src:   net.HardwareAddr{0x00, 0x00, 0x00, 0x00, 0x00, 0x01},
+		VLAN:  &VLAN{ID: 5, Priority: 4},
+		AppID: 0x4000,
+		ASDUs: []ASDU{
+			{SvID: "MU01", DatSet: "MU01/LLN0$PhsMeas", SmpCnt: 1, ConfRev: 1, SmpSynch: SmpSynchGlobal, SmpRate: 4000, Sample: []Value{{Value: -1, Quality: 0x2000}}},
+			{SvID: "MU01", SmpCnt: 2, ConfRev: 1, Sample: []Value{{Value: 7}}},
+		},
+	}
+	parsed, err := ParseFrame(frame.Bytes())
+	assert.NoError(t, err)
+	assert.Equal(t, frame, parsed)
+
+	frame.VLAN = nil
+	parsed, err = ParseFrame(frame.Bytes())
+	assert.NoError(t, err)
+	assert.Equal(t, frame, parsed)
+
+	_, err = ParseFrame(parseHex("010ccd040001 000000000001 88b8 4000 0008 00000000"))
+	assert.EqualError(t, err, "unexpected EtherType 0x88b8")
+}
+
+func TestSubscriber(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	frame := func(smpCnt uint16) []byte {
+		f := &Frame{Dst: make(net.HardwareAddr, 6), Src: make(net.HardwareAddr, 6), AppID: 0x4000, ASDUs: []ASDU{{SvID: "MU01", SmpCnt: smpCnt, Sample: []Value{{}}}}}
+		return f.Bytes()
+	}
+
+	s := NewSubscriber(WithSampleRate(4000))
+	for _, rx := range []struct {
+		smpCnt uint16
+		at     time.Duration
+	}{
+		{0, 0},
+		{1, 250 * time.Microsecond},
+		{2, 500 * time.Microsecond},
+		// выборки 3 и 4 потеряны
+		{5, 1250 * time.Microsecond},
+	} {
+		_, err := s.Handle(frame(rx.smpCnt), start.Add(rx.at))
+		assert.NoError(t, err)
+	}
+
+	stats, ok := s.Stream("MU01")
+	assert.True(t, ok)
+	assert.Equal(t, uint16(0x4000), stats.AppID)
+	assert.Equal(t, uint64(4), stats.Frames)
+	assert.Equal(t, uint64(4), stats.Samples)
+	assert.Equal(t, uint64(2), stats.Lost)
+	assert.Equal(t, 250*time.Microsecond, stats.MinInterval)
+	assert.Equal(t, 750*time.Microsecond, stats.MaxInterval)
+	assert.Equal(t, 500*time.Microsecond/16, stats.Jitter)
+	assert.InDelta(t, 4000, stats.Rate(), 1e-6)
+
+	// повтор кадра не считается потерей и не сбивает счёт
+	for _, smpCnt := range []uint16{5, 6} {
+		_, err := s.Handle(frame(smpCnt), start.Add(1500*time.Microsecond))
+		assert.NoError(t, err)
+	}
+	stats, _ = s.Stream("MU01")
+	assert.Equal(t, uint64(1), stats.OutOfOrder)
+	assert.Equal(t, uint64(2), stats.Lost)
+
+	// переход smpCnt через 0 учитывается по частоте выборок
+	s.Reset()
+	for _, smpCnt := range []uint16{3998, 3999, 1} {
+		_, err := s.Handle(frame(smpCnt), start)
+		assert.NoError(t, err)
+	}
+	stats, _ = s.Stream("MU01")
+	assert.Equal(t, uint64(1), stats.Lost)
+	assert.Len(t, s.Streams(), 1)
+
+	_, ok = s.Stream("MU02")
+	assert.False(t, ok)
+}
+
+func TestPcap(t *testing.T) {
+	frame := &Frame{
+		Dst:   net.HardwareAddr{0x01, 0x0c, 0xcd, 0x04, 0x00, 0x01},
+		Src:   net.HardwareAddr{0x00, 0x00, 0x00, 0x00, 0x00, 0x01},
+		AppID: 0x4000,
+		ASDUs: []ASDU{{SvID: "MU01", SmpCnt: 1, ConfRev: 1, Sample: []Value{{Value: 1}}}},
+	}
+	var file bytes.Buffer
+	writer, err := NewPcapWriter(&file)
+	assert.NoError(t, err)
+	arrival := time.Date(2024, 1, 2, 3, 4, 5, 250000000, time.UTC)
+	assert.NoError(t, writer.WriteFrameAt(frame.Bytes(), arrival))
+	assert.NoError(t, writer.WriteFrameAt(frame.Bytes(), arrival.Add(250*time.Microsecond)))
+
+	// Запись воспроизводится в Subscriber так же, как кадры из raw socket
+	var reader FrameReader
+	reader, err = NewPcapReader(&file)
+	assert.NoError(t, err)
+	subscriber := NewSubscriber()
+	for i := 0; i < 2; i++ {
+		buffer, at, err := reader.ReadFrame()
+		assert.NoError(t, err)
+		assert.True(t, arrival.Add(time.Duration(i)*250*time.Microsecond).Equal(at))
+		_, err = subscriber.Handle(buffer, at)
+		assert.NoError(t, err)
+	}
+	_, _, err = reader.ReadFrame()
+	assert.ErrorIs(t, err, io.EOF)
+	stream, ok := subscriber.Stream("MU01")
+	assert.True(t, ok)
+	assert.Equal(t, uint64(2), stream.Frames)
+
+	_, err = NewPcapReader(bytes.NewReader(make([]byte, 24)))
+	assert.ErrorIs(t, err, ErrPcapFormat)
+}
@@ -0,0 +1,27 @@
+package go61850
+
+import (
+	"github.com/slonegd/go61850/osi/cotp"
+	"github.com/slonegd/go61850/osi/mms"
+)
+
+// WithRawPDUTap устанавливает получателя каждого отправленного и принятого PDU
+// на каждом уровне стека (COTP, Session, Presentation, ACSE, MMS) - для внешних
+// анализаторов протокола и собственного журналирования. data действителен
+// только на время вызова, tap вызывается синхронно и не должен блокироваться.
+func WithRawPDUTap(tap func(direction mms.Direction, layer mms.Layer, data []byte)) MmsClientOption {
+	return func(c *MmsClient) {
+		c.tap = tap
+	}
+}
+
+// cotpPacketTap передаёт TPKT соединения в tap как PDU уровня mms.LayerCOTP
+func cotpPacketTap(tap mms.RawPDUTap) cotp.PacketTap {
+	return func(outgoing bool, packet []byte) {
+		direction := mms.DirectionReceive
+		if outgoing {
+			direction = mms.DirectionSend
+		}
+		tap(direction, mms.LayerCOTP, packet)
+	}
+}
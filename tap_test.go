@@ -0,0 +1,58 @@
+package go61850
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/slonegd/go61850/mmstest"
+	"github.com/slonegd/go61850/osi/mms"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRawPDUTap(t *testing.T) {
+	f, err := os.Open("mmstest/testdata/read.txt")
+	assert.NoError(t, err)
+	defer f.Close()
+	exchanges, err := mmstest.ParseTranscript(f)
+	assert.NoError(t, err)
+
+	var events []string
+	var mmsPdus [][]byte
+	tap := func(direction mms.Direction, layer mms.Layer, data []byte) {
+		events = append(events, fmt.Sprintf("%s %s", direction, layer))
+		if layer == mms.LayerMMS {
+			mmsPdus = append(mmsPdus, append([]byte(nil), data...))
+		}
+	}
+
+	server := mmstest.NewTranscriptServer(t, exchanges...)
+	conn := server.Dial()
+	ctx := context.Background()
+	client, err := NewMmsClient(ctx, conn, WithRawPDUTap(tap))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"send COTP", "receive COTP"}, events)
+
+	events = nil
+	_, err = client.Initiate(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"send MMS", "send ACSE", "send Presentation", "send Session", "send COTP",
+		"receive COTP", "receive Session", "receive Presentation", "receive ACSE", "receive MMS",
+	}, events)
+
+	events = nil
+	_, err = client.ReadObject(ctx, &mms.ReadRequest{DomainID: "simpleIOGenericIO", ItemID: "GGIO1$MX"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"send MMS", "send Presentation", "send Session", "send COTP",
+		"receive COTP", "receive Session", "receive Presentation", "receive MMS",
+	}, events)
+	assert.Len(t, mmsPdus, 4)
+	assert.Equal(t, byte(0xa0), mmsPdus[2][0]) // confirmed-RequestPDU
+	assert.Equal(t, byte(0xa1), mmsPdus[3][0]) // confirmed-ResponsePDU
+
+	conn.Close()
+	assert.NoError(t, server.Wait())
+}
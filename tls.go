@@ -0,0 +1,194 @@
+package go61850
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+)
+
+// DefaultTLSPort - TCP порт MMS поверх TLS (IEC 62351-4)
+const DefaultTLSPort = "3782"
+
+var (
+	// ErrCertificateNotPinned возвращается при установке TLS соединения, если
+	// сертификат сервера не совпадает ни с одним закреплённым (PinCertificate, PinPublicKey)
+	ErrCertificateNotPinned = errors.New("server certificate is not pinned")
+	// ErrCertificateRevoked возвращается RevocationCheck для отозванного сертификата
+	ErrCertificateRevoked = errors.New("certificate revoked")
+)
+
+// RevocationCheck проверяет, не отозван ли сертификат cert, выпущенный issuer.
+// issuer равен nil, если издатель не передан сервером и цепочка не проверялась
+// (InsecureSkipVerify). Подходит для проверки по CRL (CRLRevocationCheck) и OCSP.
+type RevocationCheck func(cert, issuer *x509.Certificate) error
+
+// TLSOption представляет опцию проверки сертификата сервера в WithTLS
+type TLSOption func(*tlsSettings)
+
+// tlsSettings - параметры TLS соединения Dial
+type tlsSettings struct {
+	config *tls.Config
+	// certificates - закреплённые сертификаты (DER)
+	certificates [][]byte
+	// publicKeys - SHA-256 закреплённых SubjectPublicKeyInfo
+	publicKeys [][]byte
+	revocation []RevocationCheck
+}
+
+// PinCertificate закрепляет сертификаты сервера: соединение устанавливается, только
+// если сертификат сервера побайтно совпадает с одним из certs
+func PinCertificate(certs ...*x509.Certificate) TLSOption {
+	return func(s *tlsSettings) {
+		for _, cert := range certs {
+			s.certificates = append(s.certificates, cert.Raw)
+		}
+	}
+}
+
+// PinPublicKey закрепляет открытые ключи сервера по SHA-256 от SubjectPublicKeyInfo
+// (см. PublicKeyPin); в отличие от PinCertificate, закрепление переживает
+// перевыпуск сертификата с тем же ключом
+func PinPublicKey(hashes ...[]byte) TLSOption {
+	return func(s *tlsSettings) {
+		s.publicKeys = append(s.publicKeys, hashes...)
+	}
+}
+
+// PublicKeyPin возвращает SHA-256 от SubjectPublicKeyInfo сертификата cert для PinPublicKey
+func PublicKeyPin(cert *x509.Certificate) []byte {
+	sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return sum[:]
+}
+
+// WithRevocationCheck добавляет проверку отзыва сертификатов сервера:
+// check вызывается для каждого сертификата цепочки, кроме корневого
+func WithRevocationCheck(check RevocationCheck) TLSOption {
+	return func(s *tlsSettings) {
+		s.revocation = append(s.revocation, check)
+	}
+}
+
+// WithTLS устанавливает в Dial соединение TLS (IEC 62351-4) с параметрами config;
+// адрес без порта дополняется портом DefaultTLSPort. Пустой ServerName заменяется
+// узлом из адреса. Опции opts задают закрепление сертификата и проверку отзыва
+// для этого IED; они выполняются и при InsecureSkipVerify, поэтому для
+// самоподписанных сертификатов IED без общей PKI достаточно закрепления.
+func WithTLS(config *tls.Config, opts ...TLSOption) MmsClientOption {
+	return func(c *MmsClient) {
+		s := &tlsSettings{config: config}
+		for _, opt := range opts {
+			opt(s)
+		}
+		c.tls = s
+	}
+}
+
+// handshake устанавливает TLS соединение поверх conn с сервером host
+func (s *tlsSettings) handshake(ctx context.Context, conn net.Conn, host string) (net.Conn, error) {
+	config := &tls.Config{}
+	if s.config != nil {
+		config = s.config.Clone()
+	}
+	if config.ServerName == "" {
+		config.ServerName = host
+	}
+	verify := config.VerifyConnection
+	config.VerifyConnection = func(state tls.ConnectionState) error {
+		if verify != nil {
+			if err := verify(state); err != nil {
+				return err
+			}
+		}
+		return s.verify(state)
+	}
+
+	tlsConn := tls.Client(conn, config)
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		return nil, fmt.Errorf("TLS handshake: %w", err)
+	}
+	return tlsConn, nil
+}
+
+// verify проверяет закрепление и отзыв сертификатов сервера
+func (s *tlsSettings) verify(state tls.ConnectionState) error {
+	if len(state.PeerCertificates) == 0 {
+		return errors.New("server sent no certificate")
+	}
+	if err := s.verifyPin(state.PeerCertificates[0]); err != nil {
+		return err
+	}
+
+	// Проверенная цепочка доступна без InsecureSkipVerify, иначе - цепочка сервера
+	chain := state.PeerCertificates
+	if len(state.VerifiedChains) > 0 {
+		chain = state.VerifiedChains[0]
+	}
+	for i, cert := range chain {
+		var issuer *x509.Certificate
+		if i+1 < len(chain) {
+			issuer = chain[i+1]
+		} else if len(state.VerifiedChains) > 0 || isSelfSigned(cert) {
+			// Корневой сертификат не проверяется
+			break
+		}
+		for _, check := range s.revocation {
+			if err := check(cert, issuer); err != nil {
+				return fmt.Errorf("certificate %q: %w", cert.Subject, err)
+			}
+		}
+	}
+	return nil
+}
+
+// verifyPin проверяет, что сертификат сервера cert закреплён (если закрепление задано)
+func (s *tlsSettings) verifyPin(cert *x509.Certificate) error {
+	if len(s.certificates) == 0 && len(s.publicKeys) == 0 {
+		return nil
+	}
+	for _, pinned := range s.certificates {
+		if bytes.Equal(pinned, cert.Raw) {
+			return nil
+		}
+	}
+	pin := PublicKeyPin(cert)
+	for _, pinned := range s.publicKeys {
+		if bytes.Equal(pinned, pin) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %q", ErrCertificateNotPinned, cert.Subject)
+}
+
+// isSelfSigned сообщает, подписан ли cert собственным ключом
+func isSelfSigned(cert *x509.Certificate) bool {
+	return bytes.Equal(cert.RawIssuer, cert.RawSubject) && cert.CheckSignatureFrom(cert) == nil
+}
+
+// CRLRevocationCheck возвращает RevocationCheck по спискам отзыва lists.
+// Список выбирается по издателю сертификата; подпись списка проверяется, если
+// издатель известен. Сертификаты издателей без списка считаются не отозванными.
+func CRLRevocationCheck(lists ...*x509.RevocationList) RevocationCheck {
+	return func(cert, issuer *x509.Certificate) error {
+		for _, list := range lists {
+			if !bytes.Equal(list.RawIssuer, cert.RawIssuer) {
+				continue
+			}
+			if issuer != nil {
+				if err := list.CheckSignatureFrom(issuer); err != nil {
+					return fmt.Errorf("CRL of %q: %w", list.Issuer, err)
+				}
+			}
+			for _, entry := range list.RevokedCertificateEntries {
+				if entry.SerialNumber.Cmp(cert.SerialNumber) == 0 {
+					return fmt.Errorf("%w: serial %s", ErrCertificateRevoked, cert.SerialNumber)
+				}
+			}
+		}
+		return nil
+	}
+}
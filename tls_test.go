@@ -0,0 +1,145 @@
+package go61850
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testCertificate выпускает сертификат с ключом для name, подписанный parent
+// (самоподписанный, если parent равен nil)
+func testCertificate(t *testing.T, name string, serial int64, isCA bool, parent *tls.Certificate) tls.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	if isCA {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage |= x509.KeyUsageCertSign | x509.KeyUsageCRLSign
+	}
+	issuer, signer := template, any(key)
+	if parent != nil {
+		issuer, signer = parent.Leaf, parent.PrivateKey
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, issuer, &key.PublicKey, signer)
+	assert.NoError(t, err)
+	leaf, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+	chain := [][]byte{der}
+	if parent != nil {
+		chain = append(chain, parent.Certificate...)
+	}
+	return tls.Certificate{Certificate: chain, PrivateKey: key, Leaf: leaf}
+}
+
+// tlsHandshake устанавливает TLS соединение settings с сервером, предъявляющим cert
+func tlsHandshake(t *testing.T, settings *tlsSettings, cert tls.Certificate) error {
+	t.Helper()
+	client, server := net.Pipe()
+	defer client.Close()
+	go func() {
+		defer server.Close()
+		tls.Server(server, &tls.Config{Certificates: []tls.Certificate{cert}}).Handshake()
+	}()
+	conn, err := settings.handshake(context.Background(), client, "ied1")
+	if conn != nil {
+		conn.Close()
+	}
+	return err
+}
+
+func TestTLSPinning(t *testing.T) {
+	ied := testCertificate(t, "ied1", 1, false, nil)
+	other := testCertificate(t, "ied1", 2, false, nil)
+	insecure := &tls.Config{InsecureSkipVerify: true}
+
+	// Самоподписанный сертификат IED без общей PKI: достаточно закрепления
+	c := &MmsClient{}
+	WithTLS(insecure, PinCertificate(ied.Leaf))(c)
+	assert.NoError(t, tlsHandshake(t, c.tls, ied))
+	err := tlsHandshake(t, c.tls, other)
+	assert.ErrorIs(t, err, ErrCertificateNotPinned)
+
+	WithTLS(insecure, PinPublicKey(PublicKeyPin(other.Leaf)))(c)
+	assert.NoError(t, tlsHandshake(t, c.tls, other))
+	assert.ErrorIs(t, tlsHandshake(t, c.tls, ied), ErrCertificateNotPinned)
+
+	// Без InsecureSkipVerify цепочка проверяется до закрепления
+	WithTLS(nil, PinCertificate(ied.Leaf))(c)
+	assert.Error(t, tlsHandshake(t, c.tls, ied))
+}
+
+func TestTLSRevocation(t *testing.T) {
+	ca := testCertificate(t, "Substation CA", 1, true, nil)
+	ied1 := testCertificate(t, "ied1", 10, false, &ca)
+	ied2 := testCertificate(t, "ied1", 11, false, &ca)
+	crlDER, err := x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+		Number:                    big.NewInt(1),
+		ThisUpdate:                time.Now(),
+		NextUpdate:                time.Now().Add(time.Hour),
+		RevokedCertificateEntries: []x509.RevocationListEntry{{SerialNumber: big.NewInt(11), RevocationTime: time.Now()}},
+	}, ca.Leaf, ca.PrivateKey.(*ecdsa.PrivateKey))
+	assert.NoError(t, err)
+	crl, err := x509.ParseRevocationList(crlDER)
+	assert.NoError(t, err)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.Leaf)
+	var checked []string
+	c := &MmsClient{}
+	WithTLS(&tls.Config{RootCAs: roots},
+		WithRevocationCheck(func(cert, issuer *x509.Certificate) error {
+			checked = append(checked, cert.Subject.CommonName+" by "+issuer.Subject.CommonName)
+			return nil
+		}),
+		WithRevocationCheck(CRLRevocationCheck(crl)),
+	)(c)
+
+	assert.NoError(t, tlsHandshake(t, c.tls, ied1))
+	assert.Equal(t, []string{"ied1 by Substation CA"}, checked)
+
+	err = tlsHandshake(t, c.tls, ied2)
+	assert.ErrorIs(t, err, ErrCertificateRevoked)
+	assert.ErrorContains(t, err, "serial 11")
+}
+
+func TestDialTLS(t *testing.T) {
+	ied := testCertificate(t, "ied1", 1, false, nil)
+	other := testCertificate(t, "ied1", 2, false, nil)
+
+	var dialed string
+	dial := func(ctx context.Context, network, address string) (net.Conn, error) {
+		dialed = address
+		client, server := net.Pipe()
+		go func() {
+			defer server.Close()
+			tls.Server(server, &tls.Config{Certificates: []tls.Certificate{other}}).Handshake()
+		}()
+		return client, nil
+	}
+
+	_, err := Dial(context.Background(), "ied1", WithDialContext(dial),
+		WithTLS(&tls.Config{InsecureSkipVerify: true}, PinCertificate(ied.Leaf)))
+	assert.Equal(t, "ied1:3782", dialed)
+	assert.ErrorIs(t, err, ErrCertificateNotPinned)
+	assert.ErrorContains(t, err, "failed to dial ied1:3782: TLS handshake:")
+}
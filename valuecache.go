@@ -0,0 +1,199 @@
+package go61850
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/slonegd/go61850/osi/mms"
+	"github.com/slonegd/go61850/osi/mms/variant"
+)
+
+// StaleReason - причина, по которой значение кэша считается устаревшим
+type StaleReason uint8
+
+const (
+	// StaleNone - значение актуально
+	StaleNone StaleReason = iota
+	// StaleConnectionLost - ассоциация, от которой получено значение, разорвана
+	StaleConnectionLost
+	// StaleIntegrityElapsed - значение не обновлялось дольше периода целостности
+	StaleIntegrityElapsed
+)
+
+// String возвращает строковое представление StaleReason
+func (r StaleReason) String() string {
+	switch r {
+	case StaleNone:
+		return "fresh"
+	case StaleConnectionLost:
+		return "connection-lost"
+	case StaleIntegrityElapsed:
+		return "integrity-elapsed"
+	default:
+		return "unknown"
+	}
+}
+
+// CachedValue - значение из кэша вместе с признаками актуальности
+type CachedValue struct {
+	Value *variant.Variant
+	// Updated - время последнего обновления значения
+	Updated time.Time
+	// Age - время, прошедшее с Updated на момент чтения из кэша
+	Age time.Duration
+	// Stale - значение могло измениться на сервере и не должно
+	// использоваться как текущее
+	Stale bool
+	// Reason - причина устаревания; StaleNone для актуального значения
+	Reason StaleReason
+}
+
+// valueEntry - запись кэша
+type valueEntry struct {
+	value   *variant.Variant
+	updated time.Time
+	// lost - ассоциация разорвана после последнего обновления
+	lost bool
+}
+
+// ValueCache хранит последние значения объектов, полученные чтением или
+// из отчётов, и отмечает их устаревшими, когда источник перестал их подтверждать:
+// после разрыва ассоциации (ConnectionLost) и, с WithIntegrityPeriod,
+// если значение не обновлялось дольше периода целостности.
+// Ключ значения - MMS имя переменной "LD/LN$FC$DO$DA" (см. mms.ObjectName.String).
+//
+// Клиент с WithValueCache обновляет кэш сам: после успешного Read и ReadObject,
+// по значениям отчётов с data-reference и InformationReport именованных
+// переменных; разрыв соединения и новая ассоциация Reconnector отмечают
+// значения устаревшими. Безопасен для конкурентного использования.
+type ValueCache struct {
+	mu        sync.Mutex
+	entries   map[string]*valueEntry
+	integrity time.Duration
+	now       func() time.Time
+}
+
+// ValueCacheOption представляет опцию для настройки ValueCache
+type ValueCacheOption func(*ValueCache)
+
+// WithIntegrityPeriod задаёт период, после которого не обновлявшееся значение
+// считается устаревшим; обычно IntgPd отчёта, доставляющего значение, с запасом.
+// 0 - без ограничения (по умолчанию).
+func WithIntegrityPeriod(d time.Duration) ValueCacheOption {
+	return func(vc *ValueCache) {
+		vc.integrity = d
+	}
+}
+
+// WithValueCacheClock задаёт источник текущего времени (по умолчанию time.Now)
+func WithValueCacheClock(now func() time.Time) ValueCacheOption {
+	return func(vc *ValueCache) {
+		vc.now = now
+	}
+}
+
+// NewValueCache создаёт пустой кэш значений
+func NewValueCache(opts ...ValueCacheOption) *ValueCache {
+	vc := &ValueCache{
+		entries: make(map[string]*valueEntry),
+		now:     time.Now,
+	}
+	for _, opt := range opts {
+		opt(vc)
+	}
+	return vc
+}
+
+// Update сохраняет значение объекта reference с текущим временем;
+// значение снова считается актуальным
+func (vc *ValueCache) Update(reference string, value *variant.Variant) {
+	vc.mu.Lock()
+	defer vc.mu.Unlock()
+	vc.entries[reference] = &valueEntry{value: value, updated: vc.now()}
+}
+
+// Get возвращает значение объекта reference и признаки его актуальности.
+// false - значение в кэш не поступало.
+func (vc *ValueCache) Get(reference string) (CachedValue, bool) {
+	vc.mu.Lock()
+	defer vc.mu.Unlock()
+	entry, ok := vc.entries[reference]
+	if !ok {
+		return CachedValue{}, false
+	}
+	return vc.cachedValue(entry), true
+}
+
+// Snapshot возвращает все значения кэша с признаками актуальности
+func (vc *ValueCache) Snapshot() map[string]CachedValue {
+	vc.mu.Lock()
+	defer vc.mu.Unlock()
+	snapshot := make(map[string]CachedValue, len(vc.entries))
+	for reference, entry := range vc.entries {
+		snapshot[reference] = vc.cachedValue(entry)
+	}
+	return snapshot
+}
+
+// ConnectionLost отмечает все значения устаревшими: после разрыва ассоциации
+// сервер не сообщает об изменениях. Значение снова актуально после Update.
+// Клиент с WithValueCache вызывает его сам (см. ValueCache).
+func (vc *ValueCache) ConnectionLost() {
+	vc.mu.Lock()
+	defer vc.mu.Unlock()
+	for _, entry := range vc.entries {
+		entry.lost = true
+	}
+}
+
+// cachedValue вычисляет признаки актуальности записи
+func (vc *ValueCache) cachedValue(entry *valueEntry) CachedValue {
+	cached := CachedValue{Value: entry.value, Updated: entry.updated, Age: vc.now().Sub(entry.updated)}
+	switch {
+	case entry.lost:
+		cached.Reason = StaleConnectionLost
+	case vc.integrity > 0 && cached.Age > vc.integrity:
+		cached.Reason = StaleIntegrityElapsed
+	}
+	cached.Stale = cached.Reason != StaleNone
+	return cached
+}
+
+// WithValueCache обновляет кэш vc значениями, полученными клиентом, и отмечает
+// их устаревшими при разрыве соединения (см. ValueCache). Один кэш можно
+// передать клиентам, последовательно создаваемым Reconnector.
+func WithValueCache(vc *ValueCache) MmsClientOption {
+	return func(c *MmsClient) {
+		c.valueCache = vc
+	}
+}
+
+// cacheReport сохраняет в кэше значения InformationReport: элементы отчёта
+// с data-reference (report задан) или именованные переменные listOfVariable
+func (c *MmsClient) cacheReport(pdu *mms.InformationReportPDU, report *Report) {
+	if c.valueCache == nil {
+		return
+	}
+	if report != nil {
+		for i, reference := range report.DataRefs {
+			if i < len(report.Values) {
+				c.valueCache.Update(reference, report.Values[i])
+			}
+		}
+		return
+	}
+	for i, variable := range pdu.Variables {
+		if i < len(pdu.Results) && pdu.Results[i].Success {
+			c.valueCache.Update(variable.String(), pdu.Results[i].Value)
+		}
+	}
+}
+
+// connectionLost отмечает значения кэша устаревшими после ошибки приёма,
+// не вызванной отменой ctx запроса
+func (c *MmsClient) connectionLost(ctx context.Context, err error) {
+	if c.valueCache != nil && err != nil && ctx.Err() == nil {
+		c.valueCache.ConnectionLost()
+	}
+}
@@ -0,0 +1,90 @@
+package go61850
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/slonegd/go61850/mmstest"
+	"github.com/slonegd/go61850/osi/mms"
+	"github.com/slonegd/go61850/osi/mms/variant"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValueCacheFreshness(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache := NewValueCache(WithIntegrityPeriod(2*time.Second), WithValueCacheClock(func() time.Time { return now }))
+
+	_, ok := cache.Get("LD/GGIO1$MX$AnIn1$mag$f")
+	assert.False(t, ok)
+
+	cache.Update("LD/GGIO1$MX$AnIn1$mag$f", variant.NewFloat32Variant(1))
+	updated := now
+	now = now.Add(time.Second)
+	value, ok := cache.Get("LD/GGIO1$MX$AnIn1$mag$f")
+	assert.True(t, ok)
+	assert.Equal(t, CachedValue{Value: variant.NewFloat32Variant(1), Updated: updated, Age: time.Second}, value)
+
+	// значение не подтверждено за период целостности
+	now = now.Add(1500 * time.Millisecond)
+	value, _ = cache.Get("LD/GGIO1$MX$AnIn1$mag$f")
+	assert.True(t, value.Stale)
+	assert.Equal(t, StaleIntegrityElapsed, value.Reason)
+
+	cache.Update("LD/GGIO1$MX$AnIn1$mag$f", variant.NewFloat32Variant(2))
+	cache.Update("LD/GGIO1$ST$Ind1$stVal", variant.NewBoolVariant(true))
+	cache.ConnectionLost()
+	for reference, value := range cache.Snapshot() {
+		assert.True(t, value.Stale, reference)
+		assert.Equal(t, "connection-lost", value.Reason.String(), reference)
+	}
+
+	// после нового значения запись снова актуальна
+	cache.Update("LD/GGIO1$ST$Ind1$stVal", variant.NewBoolVariant(false))
+	value, _ = cache.Get("LD/GGIO1$ST$Ind1$stVal")
+	assert.False(t, value.Stale)
+	assert.Equal(t, StaleNone, value.Reason)
+}
+
+func TestValueCacheClient(t *testing.T) {
+	f, err := os.Open("mmstest/testdata/read.txt")
+	assert.NoError(t, err)
+	defer f.Close()
+	exchanges, err := mmstest.ParseTranscript(f)
+	assert.NoError(t, err)
+	read := exchanges[2]
+
+	server := mmstest.NewTranscriptServer(t,
+		exchanges[0], exchanges[1],
+		// Отчёт LastApplError приходит до ответа на чтение
+		mmstest.Exchange{Request: read.Request, Responses: append([]string{reportTPKT}, read.Responses...)},
+		// Соединение разрывается до ответа на повторное чтение
+		mmstest.Exchange{Request: read.Request},
+	)
+	// CC (0x16), ответ Initiate (0x8f), отчёт (0x43) и ответ на чтение (0x87)
+	conn := mmstest.NewFaultConn(server.Dial(), mmstest.DisconnectAfterRead(0x16+0x8f+0x43+0x87))
+	ctx := context.Background()
+	cache := NewValueCache()
+	client, err := NewMmsClient(ctx, conn, WithValueCache(cache), WithReportHandler(func(*mms.InformationReportPDU) {}))
+	assert.NoError(t, err)
+	_, err = client.Initiate(ctx)
+	assert.NoError(t, err)
+
+	request := &mms.ReadRequest{DomainID: "simpleIOGenericIO", ItemID: "GGIO1$MX"}
+	result, err := client.ReadObject(ctx, request)
+	assert.NoError(t, err)
+	value, ok := cache.Get("simpleIOGenericIO/GGIO1$MX")
+	assert.True(t, ok)
+	assert.False(t, value.Stale)
+	assert.Equal(t, result.Value, value.Value)
+	value, ok = cache.Get("LastApplError")
+	assert.True(t, ok)
+	assert.False(t, value.Stale)
+
+	_, err = client.ReadObject(ctx, request)
+	assert.ErrorIs(t, err, mmstest.ErrInjectedDisconnect)
+	for reference, value := range cache.Snapshot() {
+		assert.Equal(t, StaleConnectionLost, value.Reason, reference)
+	}
+}